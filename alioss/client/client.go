@@ -1,13 +1,17 @@
 package client
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"strings"
 	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
 )
 
 type AliBlobstore struct {
@@ -18,32 +22,47 @@ func New(storageClient StorageClient) (AliBlobstore, error) {
 	return AliBlobstore{storageClient: storageClient}, nil
 }
 
-func (client *AliBlobstore) Put(sourceFilePath string, destinationObject string) error {
+// errBandwidthLimitNotSupported is returned for transfers the OSS SDK does from and to
+// file paths, which leaves no reader or writer to throttle
+var errBandwidthLimitNotSupported = errors.New("--max-bandwidth is not supported by alioss")
+
+func (client *AliBlobstore) Put(ctx context.Context, sourceFilePath string, destinationObject string, options common.PutOptions) error {
+	if options.Bandwidth != nil {
+		return errBandwidthLimitNotSupported
+	}
+
 	sourceFileMD5, err := client.getMD5(sourceFilePath)
 	if err != nil {
 		return err
 	}
 
-	err = client.storageClient.Upload(sourceFilePath, sourceFileMD5, destinationObject)
+	err = client.storageClient.Upload(sourceFilePath, sourceFileMD5, destinationObject, options)
 	if err != nil {
 		return fmt.Errorf("upload failure: %w", err)
 	}
 	return nil
 }
 
-func (client *AliBlobstore) Get(sourceObject string, dest string) error {
+func (client *AliBlobstore) Get(ctx context.Context, sourceObject string, dest string, options common.GetOptions) error {
+	if options.Bandwidth != nil {
+		return errBandwidthLimitNotSupported
+	}
 	return client.storageClient.Download(sourceObject, dest)
 }
 
-func (client *AliBlobstore) Delete(object string) error {
+func (client *AliBlobstore) GetRange(ctx context.Context, sourceObject string, dest string, offset int64, options common.GetOptions) error {
+	return errors.New("not implemented")
+}
+
+func (client *AliBlobstore) Delete(ctx context.Context, object string) error {
 	return client.storageClient.Delete(object)
 }
 
-func (client *AliBlobstore) Exists(object string) (bool, error) {
+func (client *AliBlobstore) Exists(ctx context.Context, object string) (bool, error) {
 	return client.storageClient.Exists(object)
 }
 
-func (client *AliBlobstore) Sign(object string, action string, expiration time.Duration) (string, error) {
+func (client *AliBlobstore) Sign(ctx context.Context, object string, action string, expiration time.Duration) (string, error) {
 	action = strings.ToUpper(action)
 	expiredInSec := int64(expiration.Seconds())
 	switch action {
@@ -56,6 +75,24 @@ func (client *AliBlobstore) Sign(object string, action string, expiration time.D
 	}
 }
 
+func (client *AliBlobstore) SignWithOptions(ctx context.Context, object string, action string, expiration time.Duration, options common.SignOptions) (string, error) {
+	return "", errors.New("signing with content type, MD5 or start time is not supported for alioss")
+}
+
+// ValidateSign checks that a URL for action can be signed, which alioss does locally with the
+// configured access key
+func (client *AliBlobstore) ValidateSign(ctx context.Context, object string, action string, expiration time.Duration, options common.SignOptions) error {
+	if options != (common.SignOptions{}) {
+		return errors.New("signing with content type, MD5 or start time is not supported for alioss")
+	}
+
+	action = strings.ToUpper(action)
+	if action != "GET" && action != "PUT" {
+		return fmt.Errorf("action not implemented: %s", action)
+	}
+	return nil
+}
+
 func (client *AliBlobstore) getMD5(filePath string) (string, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -75,22 +112,95 @@ func (client *AliBlobstore) getMD5(filePath string) (string, error) {
 	return md5, nil
 }
 
-func (client *AliBlobstore) List(prefix string) ([]string, error) {
-	return client.storageClient.List(prefix)
+func (client *AliBlobstore) List(ctx context.Context, prefix string) ([]string, error) {
+	return client.storageClient.List(prefix, 0)
+}
+
+// ListWithLimit lists like List, but stops paging once limit objects have been found
+func (client *AliBlobstore) ListWithLimit(ctx context.Context, prefix string, limit int) ([]string, error) {
+	return client.storageClient.List(prefix, limit)
 }
 
-func (client *AliBlobstore) Copy(srcBlob string, dstBlob string) error {
-	return client.storageClient.Copy(srcBlob, dstBlob)
+func (client *AliBlobstore) ListDetailed(ctx context.Context, prefix string) ([]common.ObjectInfo, error) {
+	return client.storageClient.ListDetailed(prefix, 0)
 }
 
-func (client *AliBlobstore) Properties(dest string) error {
-	return client.storageClient.Properties(dest)
+// ListDetailedWithLimit lists like ListDetailed, but stops paging once limit objects have been found
+func (client *AliBlobstore) ListDetailedWithLimit(ctx context.Context, prefix string, limit int) ([]common.ObjectInfo, error) {
+	return client.storageClient.ListDetailed(prefix, limit)
 }
 
-func (client *AliBlobstore) EnsureStorageExists() error {
+func (client *AliBlobstore) Copy(ctx context.Context, srcBlob string, dstBlob string, options common.CopyOptions) error {
+	return client.storageClient.Copy(srcBlob, dstBlob, options)
+}
+
+func (client *AliBlobstore) CopyFromBucket(ctx context.Context, srcBucket string, srcRegion string, srcBlob string, dstBlob string, options common.CopyOptions) error {
+	return errors.New("not implemented")
+}
+
+func (client *AliBlobstore) CopyToBucket(ctx context.Context, srcBlob string, dstBucket string, dstBlob string, options common.CopyOptions) error {
+	return errors.New("not implemented")
+}
+
+func (client *AliBlobstore) PutWithETag(ctx context.Context, sourceFilePath string, destinationObject string, options common.PutOptions) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+func (client *AliBlobstore) PutWithManifest(ctx context.Context, sourceFilePath string, dest string, manifest common.UploadManifest, options common.PutOptions) error {
+	return errors.New("not implemented")
+}
+
+func (client *AliBlobstore) Rename(ctx context.Context, srcBlob string, dstBlob string) error {
+	if err := client.storageClient.Copy(srcBlob, dstBlob, common.CopyOptions{}); err != nil {
+		return err
+	}
+	return client.storageClient.Delete(srcBlob)
+}
+
+func (client *AliBlobstore) Size(ctx context.Context, dest string) (int64, error) {
+	return client.storageClient.Size(dest)
+}
+
+func (client *AliBlobstore) Properties(ctx context.Context, dest string) (common.ObjectProperties, error) {
+	return client.storageClient.Properties(dest, common.PropertiesOptions{})
+}
+
+func (client *AliBlobstore) PropertiesWithOptions(ctx context.Context, dest string, options common.PropertiesOptions) (common.ObjectProperties, error) {
+	return client.storageClient.Properties(dest, options)
+}
+
+func (client *AliBlobstore) Head(ctx context.Context, dest string) (common.ObjectHead, error) {
+	return client.storageClient.Head(dest)
+}
+
+func (client *AliBlobstore) EnsureStorageExists(ctx context.Context) error {
 	return client.storageClient.EnsureBucketExists()
 }
 
-func (client *AliBlobstore) DeleteRecursive(prefix string) error {
-	return client.storageClient.DeleteRecursive(prefix)
+func (client *AliBlobstore) DeleteRecursive(ctx context.Context, prefix string, options common.DeleteRecursiveOptions) error {
+	return client.storageClient.DeleteRecursive(prefix, options.ContinueOnError)
+}
+
+// Capabilities lists the operations the client supports
+func (client *AliBlobstore) Capabilities() []string {
+	return []string{
+		common.CapabilityPut,
+		common.CapabilityGet,
+		common.CapabilityDelete,
+		common.CapabilityDeleteRecursive,
+		common.CapabilityExists,
+		common.CapabilitySize,
+		common.CapabilitySign,
+		common.CapabilityList,
+		common.CapabilityCopy,
+		common.CapabilityRename,
+		common.CapabilityProperties,
+		common.CapabilityHead,
+		common.CapabilityEnsureStorageExists,
+		common.CapabilityIdentity,
+	}
+}
+
+func (client *AliBlobstore) Identity(ctx context.Context) (common.Identity, error) {
+	return client.storageClient.Identity(), nil
 }
@@ -1,12 +1,14 @@
 package client_test
 
 import (
+	"context"
 	"errors"
 	"os"
 	"time"
 
 	"github.com/cloudfoundry/storage-cli/alioss/client"
 	"github.com/cloudfoundry/storage-cli/alioss/client/clientfakes"
+	"github.com/cloudfoundry/storage-cli/common"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -22,14 +24,15 @@ var _ = Describe("Client", func() {
 
 			tmpFile, _ := os.CreateTemp("", "azure-storage-cli-test") //nolint:errcheck
 
-			aliBlobstore.Put(tmpFile.Name(), "destination_object") //nolint:errcheck
+			aliBlobstore.Put(context.Background(), tmpFile.Name(), "destination_object", common.PutOptions{ContentType: "text/plain"}) //nolint:errcheck
 
 			Expect(storageClient.UploadCallCount()).To(Equal(1))
-			sourceFilePath, sourceFileMD5, destination := storageClient.UploadArgsForCall(0)
+			sourceFilePath, sourceFileMD5, destination, options := storageClient.UploadArgsForCall(0)
 
 			Expect(sourceFilePath).To(BeAssignableToTypeOf("source/file/path"))
 			Expect(sourceFileMD5).To(Equal("1B2M2Y8AsgTpgAmY7PhCfg=="))
 			Expect(destination).To(Equal("destination_object"))
+			Expect(options).To(Equal(common.PutOptions{ContentType: "text/plain"}))
 		})
 	})
 
@@ -40,7 +43,7 @@ var _ = Describe("Client", func() {
 			aliBlobstore, err := client.New(&storageClient)
 			Expect(err).ToNot(HaveOccurred())
 
-			aliBlobstore.Get("source_object", "destination/file/path") //nolint:errcheck
+			aliBlobstore.Get(context.Background(), "source_object", "destination/file/path", common.GetOptions{}) //nolint:errcheck
 
 			Expect(storageClient.DownloadCallCount()).To(Equal(1))
 			sourceObject, destinationFilePath := storageClient.DownloadArgsForCall(0)
@@ -57,7 +60,7 @@ var _ = Describe("Client", func() {
 			aliBlobstore, err := client.New(&storageClient)
 			Expect(err).ToNot(HaveOccurred())
 
-			aliBlobstore.Delete("blob") //nolint:errcheck
+			aliBlobstore.Delete(context.Background(), "blob") //nolint:errcheck
 
 			Expect(storageClient.DeleteCallCount()).To(Equal(1))
 			object := storageClient.DeleteArgsForCall(0)
@@ -66,6 +69,154 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Context("List", func() {
+		It("lists the objects with the given prefix", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+			storageClient.ListReturns([]string{"prefix/a", "prefix/b"}, nil)
+
+			aliBlobstore, err := client.New(&storageClient)
+			Expect(err).ToNot(HaveOccurred())
+
+			objects, err := aliBlobstore.List(context.Background(), "prefix/")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(objects).To(Equal([]string{"prefix/a", "prefix/b"}))
+
+			Expect(storageClient.ListCallCount()).To(Equal(1))
+			prefix, limit := storageClient.ListArgsForCall(0)
+			Expect(prefix).To(Equal("prefix/"))
+			Expect(limit).To(BeZero())
+		})
+
+		It("passes the limit on", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+
+			aliBlobstore, err := client.New(&storageClient)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = aliBlobstore.ListWithLimit(context.Background(), "prefix/", 10)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, limit := storageClient.ListArgsForCall(0)
+			Expect(limit).To(Equal(10))
+		})
+
+		It("lists all objects for an empty prefix", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+
+			aliBlobstore, err := client.New(&storageClient)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = aliBlobstore.List(context.Background(), "")
+			Expect(err).ToNot(HaveOccurred())
+
+			prefix, _ := storageClient.ListArgsForCall(0)
+			Expect(prefix).To(BeEmpty())
+		})
+
+		It("returns the error of the storage client", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+			storageClient.ListReturns(nil, errors.New("boom"))
+
+			aliBlobstore, err := client.New(&storageClient)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = aliBlobstore.List(context.Background(), "prefix/")
+			Expect(err).To(MatchError("boom"))
+		})
+
+		It("lists the objects with their details", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+			details := []common.ObjectInfo{{Key: "prefix/a", Size: 10, LastModified: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)}}
+			storageClient.ListDetailedReturns(details, nil)
+
+			aliBlobstore, err := client.New(&storageClient)
+			Expect(err).ToNot(HaveOccurred())
+
+			objects, err := aliBlobstore.ListDetailed(context.Background(), "prefix/")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(objects).To(Equal(details))
+			prefix, limit := storageClient.ListDetailedArgsForCall(0)
+			Expect(prefix).To(Equal("prefix/"))
+			Expect(limit).To(BeZero())
+		})
+
+		It("passes the limit on for a detailed listing", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+
+			aliBlobstore, err := client.New(&storageClient)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = aliBlobstore.ListDetailedWithLimit(context.Background(), "prefix/", 10)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, limit := storageClient.ListDetailedArgsForCall(0)
+			Expect(limit).To(Equal(10))
+		})
+	})
+
+	Context("Copy", func() {
+		It("copies the blob within the bucket", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+
+			aliBlobstore, err := client.New(&storageClient)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = aliBlobstore.Copy(context.Background(), "source_object", "destination_object", common.CopyOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(storageClient.CopyCallCount()).To(Equal(1))
+			src, dst, options := storageClient.CopyArgsForCall(0)
+			Expect(src).To(Equal("source_object"))
+			Expect(dst).To(Equal("destination_object"))
+			Expect(options.ResetMetadata).To(BeFalse())
+		})
+
+		It("returns the error of the storage client", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+			storageClient.CopyReturns(errors.New("boom"))
+
+			aliBlobstore, err := client.New(&storageClient)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = aliBlobstore.Copy(context.Background(), "source_object", "destination_object", common.CopyOptions{})
+			Expect(err).To(MatchError("boom"))
+		})
+	})
+
+	Context("Properties", func() {
+		It("gets the properties of the blob", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+			storageClient.PropertiesReturns(common.ObjectProperties{ETag: "some-etag", ContentLength: 7}, nil)
+
+			aliBlobstore, err := client.New(&storageClient)
+			Expect(err).ToNot(HaveOccurred())
+
+			properties, err := aliBlobstore.Properties(context.Background(), "blob")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(properties).To(Equal(common.ObjectProperties{ETag: "some-etag", ContentLength: 7}))
+
+			Expect(storageClient.PropertiesCallCount()).To(Equal(1))
+			Expect(storageClient.PropertiesArgsForCall(0)).To(Equal("blob"))
+		})
+	})
+
+	Context("DeleteRecursive", func() {
+		It("deletes the blobs with the given prefix", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+
+			aliBlobstore, err := client.New(&storageClient)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = aliBlobstore.DeleteRecursive(context.Background(), "prefix/", common.DeleteRecursiveOptions{ContinueOnError: true})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(storageClient.DeleteRecursiveCallCount()).To(Equal(1))
+			prefix, continueOnError := storageClient.DeleteRecursiveArgsForCall(0)
+			Expect(prefix).To(Equal("prefix/"))
+			Expect(continueOnError).To(BeTrue())
+		})
+	})
+
 	Context("Exists", func() {
 		It("returns blob.Existing on success", func() {
 			storageClient := clientfakes.FakeStorageClient{}
@@ -73,7 +224,7 @@ var _ = Describe("Client", func() {
 
 			aliBlobstore, err := client.New(&storageClient)
 			Expect(err).NotTo(HaveOccurred())
-			existsState, err := aliBlobstore.Exists("blob")
+			existsState, err := aliBlobstore.Exists(context.Background(), "blob")
 			Expect(existsState == true).To(BeTrue())
 			Expect(err).ToNot(HaveOccurred())
 
@@ -87,7 +238,7 @@ var _ = Describe("Client", func() {
 
 			aliBlobstore, err := client.New(&storageClient)
 			Expect(err).NotTo(HaveOccurred())
-			existsState, err := aliBlobstore.Exists("blob")
+			existsState, err := aliBlobstore.Exists(context.Background(), "blob")
 			Expect(existsState == false).To(BeTrue())
 			Expect(err).ToNot(HaveOccurred())
 
@@ -101,7 +252,7 @@ var _ = Describe("Client", func() {
 
 			aliBlobstore, err := client.New(&storageClient)
 			Expect(err).NotTo(HaveOccurred())
-			existsState, err := aliBlobstore.Exists("blob")
+			existsState, err := aliBlobstore.Exists(context.Background(), "blob")
 			Expect(existsState == false).To(BeTrue())
 			Expect(err).To(HaveOccurred())
 
@@ -123,7 +274,7 @@ var _ = Describe("Client", func() {
 
 			aliBlobstore, err := client.New(&storageClient)
 			Expect(err).NotTo(HaveOccurred())
-			url, err := aliBlobstore.Sign("blob", "get", expiry)
+			url, err := aliBlobstore.Sign(context.Background(), "blob", "get", expiry)
 			Expect(url == "https://the-signed-url").To(BeTrue())
 			Expect(err).ToNot(HaveOccurred())
 
@@ -138,7 +289,7 @@ var _ = Describe("Client", func() {
 
 			aliBlobstore, err := client.New(&storageClient)
 			Expect(err).NotTo(HaveOccurred())
-			url, err := aliBlobstore.Sign("blob", "put", expiry)
+			url, err := aliBlobstore.Sign(context.Background(), "blob", "put", expiry)
 			Expect(url == "https://the-signed-url").To(BeTrue())
 			Expect(err).ToNot(HaveOccurred())
 
@@ -153,7 +304,7 @@ var _ = Describe("Client", func() {
 
 			aliBlobstore, err := client.New(&storageClient)
 			Expect(err).NotTo(HaveOccurred())
-			url, err := aliBlobstore.Sign("blob", "unknown", expiry)
+			url, err := aliBlobstore.Sign(context.Background(), "blob", "unknown", expiry)
 			Expect(url).To(Equal(""))
 			Expect(err).To(HaveOccurred())
 
@@ -5,14 +5,16 @@ import (
 	"sync"
 
 	"github.com/cloudfoundry/storage-cli/alioss/client"
+	"github.com/cloudfoundry/storage-cli/common"
 )
 
 type FakeStorageClient struct {
-	CopyStub        func(string, string) error
+	CopyStub        func(string, string, common.CopyOptions) error
 	copyMutex       sync.RWMutex
 	copyArgsForCall []struct {
 		arg1 string
 		arg2 string
+		arg3 common.CopyOptions
 	}
 	copyReturns struct {
 		result1 error
@@ -31,10 +33,11 @@ type FakeStorageClient struct {
 	deleteReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DeleteRecursiveStub        func(string) error
+	DeleteRecursiveStub        func(string, bool) error
 	deleteRecursiveMutex       sync.RWMutex
 	deleteRecursiveArgsForCall []struct {
 		arg1 string
+		arg2 bool
 	}
 	deleteRecursiveReturns struct {
 		result1 error
@@ -77,10 +80,34 @@ type FakeStorageClient struct {
 		result1 bool
 		result2 error
 	}
-	ListStub        func(string) ([]string, error)
+	HeadStub        func(string) (common.ObjectHead, error)
+	headMutex       sync.RWMutex
+	headArgsForCall []struct {
+		arg1 string
+	}
+	headReturns struct {
+		result1 common.ObjectHead
+		result2 error
+	}
+	headReturnsOnCall map[int]struct {
+		result1 common.ObjectHead
+		result2 error
+	}
+	IdentityStub        func() common.Identity
+	identityMutex       sync.RWMutex
+	identityArgsForCall []struct {
+	}
+	identityReturns struct {
+		result1 common.Identity
+	}
+	identityReturnsOnCall map[int]struct {
+		result1 common.Identity
+	}
+	ListStub        func(string, int) ([]string, error)
 	listMutex       sync.RWMutex
 	listArgsForCall []struct {
 		arg1 string
+		arg2 int
 	}
 	listReturns struct {
 		result1 []string
@@ -90,16 +117,33 @@ type FakeStorageClient struct {
 		result1 []string
 		result2 error
 	}
-	PropertiesStub        func(string) error
+	ListDetailedStub        func(string, int) ([]common.ObjectInfo, error)
+	listDetailedMutex       sync.RWMutex
+	listDetailedArgsForCall []struct {
+		arg1 string
+		arg2 int
+	}
+	listDetailedReturns struct {
+		result1 []common.ObjectInfo
+		result2 error
+	}
+	listDetailedReturnsOnCall map[int]struct {
+		result1 []common.ObjectInfo
+		result2 error
+	}
+	PropertiesStub        func(string, common.PropertiesOptions) (common.ObjectProperties, error)
 	propertiesMutex       sync.RWMutex
 	propertiesArgsForCall []struct {
 		arg1 string
+		arg2 common.PropertiesOptions
 	}
 	propertiesReturns struct {
-		result1 error
+		result1 common.ObjectProperties
+		result2 error
 	}
 	propertiesReturnsOnCall map[int]struct {
-		result1 error
+		result1 common.ObjectProperties
+		result2 error
 	}
 	SignedUrlGetStub        func(string, int64) (string, error)
 	signedUrlGetMutex       sync.RWMutex
@@ -129,12 +173,26 @@ type FakeStorageClient struct {
 		result1 string
 		result2 error
 	}
-	UploadStub        func(string, string, string) error
+	SizeStub        func(string) (int64, error)
+	sizeMutex       sync.RWMutex
+	sizeArgsForCall []struct {
+		arg1 string
+	}
+	sizeReturns struct {
+		result1 int64
+		result2 error
+	}
+	sizeReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
+	UploadStub        func(string, string, string, common.PutOptions) error
 	uploadMutex       sync.RWMutex
 	uploadArgsForCall []struct {
 		arg1 string
 		arg2 string
 		arg3 string
+		arg4 common.PutOptions
 	}
 	uploadReturns struct {
 		result1 error
@@ -146,19 +204,20 @@ type FakeStorageClient struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeStorageClient) Copy(arg1 string, arg2 string) error {
+func (fake *FakeStorageClient) Copy(arg1 string, arg2 string, arg3 common.CopyOptions) error {
 	fake.copyMutex.Lock()
 	ret, specificReturn := fake.copyReturnsOnCall[len(fake.copyArgsForCall)]
 	fake.copyArgsForCall = append(fake.copyArgsForCall, struct {
 		arg1 string
 		arg2 string
-	}{arg1, arg2})
+		arg3 common.CopyOptions
+	}{arg1, arg2, arg3})
 	stub := fake.CopyStub
 	fakeReturns := fake.copyReturns
-	fake.recordInvocation("Copy", []interface{}{arg1, arg2})
+	fake.recordInvocation("Copy", []interface{}{arg1, arg2, arg3})
 	fake.copyMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -172,17 +231,17 @@ func (fake *FakeStorageClient) CopyCallCount() int {
 	return len(fake.copyArgsForCall)
 }
 
-func (fake *FakeStorageClient) CopyCalls(stub func(string, string) error) {
+func (fake *FakeStorageClient) CopyCalls(stub func(string, string, common.CopyOptions) error) {
 	fake.copyMutex.Lock()
 	defer fake.copyMutex.Unlock()
 	fake.CopyStub = stub
 }
 
-func (fake *FakeStorageClient) CopyArgsForCall(i int) (string, string) {
+func (fake *FakeStorageClient) CopyArgsForCall(i int) (string, string, common.CopyOptions) {
 	fake.copyMutex.RLock()
 	defer fake.copyMutex.RUnlock()
 	argsForCall := fake.copyArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeStorageClient) CopyReturns(result1 error) {
@@ -269,18 +328,19 @@ func (fake *FakeStorageClient) DeleteReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeStorageClient) DeleteRecursive(arg1 string) error {
+func (fake *FakeStorageClient) DeleteRecursive(arg1 string, arg2 bool) error {
 	fake.deleteRecursiveMutex.Lock()
 	ret, specificReturn := fake.deleteRecursiveReturnsOnCall[len(fake.deleteRecursiveArgsForCall)]
 	fake.deleteRecursiveArgsForCall = append(fake.deleteRecursiveArgsForCall, struct {
 		arg1 string
-	}{arg1})
+		arg2 bool
+	}{arg1, arg2})
 	stub := fake.DeleteRecursiveStub
 	fakeReturns := fake.deleteRecursiveReturns
-	fake.recordInvocation("DeleteRecursive", []interface{}{arg1})
+	fake.recordInvocation("DeleteRecursive", []interface{}{arg1, arg2})
 	fake.deleteRecursiveMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -294,17 +354,17 @@ func (fake *FakeStorageClient) DeleteRecursiveCallCount() int {
 	return len(fake.deleteRecursiveArgsForCall)
 }
 
-func (fake *FakeStorageClient) DeleteRecursiveCalls(stub func(string) error) {
+func (fake *FakeStorageClient) DeleteRecursiveCalls(stub func(string, bool) error) {
 	fake.deleteRecursiveMutex.Lock()
 	defer fake.deleteRecursiveMutex.Unlock()
 	fake.DeleteRecursiveStub = stub
 }
 
-func (fake *FakeStorageClient) DeleteRecursiveArgsForCall(i int) string {
+func (fake *FakeStorageClient) DeleteRecursiveArgsForCall(i int) (string, bool) {
 	fake.deleteRecursiveMutex.RLock()
 	defer fake.deleteRecursiveMutex.RUnlock()
 	argsForCall := fake.deleteRecursiveArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeStorageClient) DeleteRecursiveReturns(result1 error) {
@@ -509,18 +569,136 @@ func (fake *FakeStorageClient) ExistsReturnsOnCall(i int, result1 bool, result2
 	}{result1, result2}
 }
 
-func (fake *FakeStorageClient) List(arg1 string) ([]string, error) {
+func (fake *FakeStorageClient) Head(arg1 string) (common.ObjectHead, error) {
+	fake.headMutex.Lock()
+	ret, specificReturn := fake.headReturnsOnCall[len(fake.headArgsForCall)]
+	fake.headArgsForCall = append(fake.headArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.HeadStub
+	fakeReturns := fake.headReturns
+	fake.recordInvocation("Head", []interface{}{arg1})
+	fake.headMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStorageClient) HeadCallCount() int {
+	fake.headMutex.RLock()
+	defer fake.headMutex.RUnlock()
+	return len(fake.headArgsForCall)
+}
+
+func (fake *FakeStorageClient) HeadCalls(stub func(string) (common.ObjectHead, error)) {
+	fake.headMutex.Lock()
+	defer fake.headMutex.Unlock()
+	fake.HeadStub = stub
+}
+
+func (fake *FakeStorageClient) HeadArgsForCall(i int) string {
+	fake.headMutex.RLock()
+	defer fake.headMutex.RUnlock()
+	argsForCall := fake.headArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStorageClient) HeadReturns(result1 common.ObjectHead, result2 error) {
+	fake.headMutex.Lock()
+	defer fake.headMutex.Unlock()
+	fake.HeadStub = nil
+	fake.headReturns = struct {
+		result1 common.ObjectHead
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorageClient) HeadReturnsOnCall(i int, result1 common.ObjectHead, result2 error) {
+	fake.headMutex.Lock()
+	defer fake.headMutex.Unlock()
+	fake.HeadStub = nil
+	if fake.headReturnsOnCall == nil {
+		fake.headReturnsOnCall = make(map[int]struct {
+			result1 common.ObjectHead
+			result2 error
+		})
+	}
+	fake.headReturnsOnCall[i] = struct {
+		result1 common.ObjectHead
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorageClient) Identity() common.Identity {
+	fake.identityMutex.Lock()
+	ret, specificReturn := fake.identityReturnsOnCall[len(fake.identityArgsForCall)]
+	fake.identityArgsForCall = append(fake.identityArgsForCall, struct {
+	}{})
+	stub := fake.IdentityStub
+	fakeReturns := fake.identityReturns
+	fake.recordInvocation("Identity", []interface{}{})
+	fake.identityMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorageClient) IdentityCallCount() int {
+	fake.identityMutex.RLock()
+	defer fake.identityMutex.RUnlock()
+	return len(fake.identityArgsForCall)
+}
+
+func (fake *FakeStorageClient) IdentityCalls(stub func() common.Identity) {
+	fake.identityMutex.Lock()
+	defer fake.identityMutex.Unlock()
+	fake.IdentityStub = stub
+}
+
+func (fake *FakeStorageClient) IdentityReturns(result1 common.Identity) {
+	fake.identityMutex.Lock()
+	defer fake.identityMutex.Unlock()
+	fake.IdentityStub = nil
+	fake.identityReturns = struct {
+		result1 common.Identity
+	}{result1}
+}
+
+func (fake *FakeStorageClient) IdentityReturnsOnCall(i int, result1 common.Identity) {
+	fake.identityMutex.Lock()
+	defer fake.identityMutex.Unlock()
+	fake.IdentityStub = nil
+	if fake.identityReturnsOnCall == nil {
+		fake.identityReturnsOnCall = make(map[int]struct {
+			result1 common.Identity
+		})
+	}
+	fake.identityReturnsOnCall[i] = struct {
+		result1 common.Identity
+	}{result1}
+}
+
+func (fake *FakeStorageClient) List(arg1 string, arg2 int) ([]string, error) {
 	fake.listMutex.Lock()
 	ret, specificReturn := fake.listReturnsOnCall[len(fake.listArgsForCall)]
 	fake.listArgsForCall = append(fake.listArgsForCall, struct {
 		arg1 string
-	}{arg1})
+		arg2 int
+	}{arg1, arg2})
 	stub := fake.ListStub
 	fakeReturns := fake.listReturns
-	fake.recordInvocation("List", []interface{}{arg1})
+	fake.recordInvocation("List", []interface{}{arg1, arg2})
 	fake.listMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -534,17 +712,17 @@ func (fake *FakeStorageClient) ListCallCount() int {
 	return len(fake.listArgsForCall)
 }
 
-func (fake *FakeStorageClient) ListCalls(stub func(string) ([]string, error)) {
+func (fake *FakeStorageClient) ListCalls(stub func(string, int) ([]string, error)) {
 	fake.listMutex.Lock()
 	defer fake.listMutex.Unlock()
 	fake.ListStub = stub
 }
 
-func (fake *FakeStorageClient) ListArgsForCall(i int) string {
+func (fake *FakeStorageClient) ListArgsForCall(i int) (string, int) {
 	fake.listMutex.RLock()
 	defer fake.listMutex.RUnlock()
 	argsForCall := fake.listArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeStorageClient) ListReturns(result1 []string, result2 error) {
@@ -573,23 +751,89 @@ func (fake *FakeStorageClient) ListReturnsOnCall(i int, result1 []string, result
 	}{result1, result2}
 }
 
-func (fake *FakeStorageClient) Properties(arg1 string) error {
+func (fake *FakeStorageClient) ListDetailed(arg1 string, arg2 int) ([]common.ObjectInfo, error) {
+	fake.listDetailedMutex.Lock()
+	ret, specificReturn := fake.listDetailedReturnsOnCall[len(fake.listDetailedArgsForCall)]
+	fake.listDetailedArgsForCall = append(fake.listDetailedArgsForCall, struct {
+		arg1 string
+		arg2 int
+	}{arg1, arg2})
+	stub := fake.ListDetailedStub
+	fakeReturns := fake.listDetailedReturns
+	fake.recordInvocation("ListDetailed", []interface{}{arg1, arg2})
+	fake.listDetailedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStorageClient) ListDetailedCallCount() int {
+	fake.listDetailedMutex.RLock()
+	defer fake.listDetailedMutex.RUnlock()
+	return len(fake.listDetailedArgsForCall)
+}
+
+func (fake *FakeStorageClient) ListDetailedCalls(stub func(string, int) ([]common.ObjectInfo, error)) {
+	fake.listDetailedMutex.Lock()
+	defer fake.listDetailedMutex.Unlock()
+	fake.ListDetailedStub = stub
+}
+
+func (fake *FakeStorageClient) ListDetailedArgsForCall(i int) (string, int) {
+	fake.listDetailedMutex.RLock()
+	defer fake.listDetailedMutex.RUnlock()
+	argsForCall := fake.listDetailedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStorageClient) ListDetailedReturns(result1 []common.ObjectInfo, result2 error) {
+	fake.listDetailedMutex.Lock()
+	defer fake.listDetailedMutex.Unlock()
+	fake.ListDetailedStub = nil
+	fake.listDetailedReturns = struct {
+		result1 []common.ObjectInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorageClient) ListDetailedReturnsOnCall(i int, result1 []common.ObjectInfo, result2 error) {
+	fake.listDetailedMutex.Lock()
+	defer fake.listDetailedMutex.Unlock()
+	fake.ListDetailedStub = nil
+	if fake.listDetailedReturnsOnCall == nil {
+		fake.listDetailedReturnsOnCall = make(map[int]struct {
+			result1 []common.ObjectInfo
+			result2 error
+		})
+	}
+	fake.listDetailedReturnsOnCall[i] = struct {
+		result1 []common.ObjectInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorageClient) Properties(arg1 string, arg2 common.PropertiesOptions) (common.ObjectProperties, error) {
 	fake.propertiesMutex.Lock()
 	ret, specificReturn := fake.propertiesReturnsOnCall[len(fake.propertiesArgsForCall)]
 	fake.propertiesArgsForCall = append(fake.propertiesArgsForCall, struct {
 		arg1 string
-	}{arg1})
+		arg2 common.PropertiesOptions
+	}{arg1, arg2})
 	stub := fake.PropertiesStub
 	fakeReturns := fake.propertiesReturns
-	fake.recordInvocation("Properties", []interface{}{arg1})
+	fake.recordInvocation("Properties", []interface{}{arg1, arg2})
 	fake.propertiesMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeStorageClient) PropertiesCallCount() int {
@@ -598,40 +842,43 @@ func (fake *FakeStorageClient) PropertiesCallCount() int {
 	return len(fake.propertiesArgsForCall)
 }
 
-func (fake *FakeStorageClient) PropertiesCalls(stub func(string) error) {
+func (fake *FakeStorageClient) PropertiesCalls(stub func(string, common.PropertiesOptions) (common.ObjectProperties, error)) {
 	fake.propertiesMutex.Lock()
 	defer fake.propertiesMutex.Unlock()
 	fake.PropertiesStub = stub
 }
 
-func (fake *FakeStorageClient) PropertiesArgsForCall(i int) string {
+func (fake *FakeStorageClient) PropertiesArgsForCall(i int) (string, common.PropertiesOptions) {
 	fake.propertiesMutex.RLock()
 	defer fake.propertiesMutex.RUnlock()
 	argsForCall := fake.propertiesArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeStorageClient) PropertiesReturns(result1 error) {
+func (fake *FakeStorageClient) PropertiesReturns(result1 common.ObjectProperties, result2 error) {
 	fake.propertiesMutex.Lock()
 	defer fake.propertiesMutex.Unlock()
 	fake.PropertiesStub = nil
 	fake.propertiesReturns = struct {
-		result1 error
-	}{result1}
+		result1 common.ObjectProperties
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeStorageClient) PropertiesReturnsOnCall(i int, result1 error) {
+func (fake *FakeStorageClient) PropertiesReturnsOnCall(i int, result1 common.ObjectProperties, result2 error) {
 	fake.propertiesMutex.Lock()
 	defer fake.propertiesMutex.Unlock()
 	fake.PropertiesStub = nil
 	if fake.propertiesReturnsOnCall == nil {
 		fake.propertiesReturnsOnCall = make(map[int]struct {
-			result1 error
+			result1 common.ObjectProperties
+			result2 error
 		})
 	}
 	fake.propertiesReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
+		result1 common.ObjectProperties
+		result2 error
+	}{result1, result2}
 }
 
 func (fake *FakeStorageClient) SignedUrlGet(arg1 string, arg2 int64) (string, error) {
@@ -764,20 +1011,85 @@ func (fake *FakeStorageClient) SignedUrlPutReturnsOnCall(i int, result1 string,
 	}{result1, result2}
 }
 
-func (fake *FakeStorageClient) Upload(arg1 string, arg2 string, arg3 string) error {
+func (fake *FakeStorageClient) Size(arg1 string) (int64, error) {
+	fake.sizeMutex.Lock()
+	ret, specificReturn := fake.sizeReturnsOnCall[len(fake.sizeArgsForCall)]
+	fake.sizeArgsForCall = append(fake.sizeArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.SizeStub
+	fakeReturns := fake.sizeReturns
+	fake.recordInvocation("Size", []interface{}{arg1})
+	fake.sizeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStorageClient) SizeCallCount() int {
+	fake.sizeMutex.RLock()
+	defer fake.sizeMutex.RUnlock()
+	return len(fake.sizeArgsForCall)
+}
+
+func (fake *FakeStorageClient) SizeCalls(stub func(string) (int64, error)) {
+	fake.sizeMutex.Lock()
+	defer fake.sizeMutex.Unlock()
+	fake.SizeStub = stub
+}
+
+func (fake *FakeStorageClient) SizeArgsForCall(i int) string {
+	fake.sizeMutex.RLock()
+	defer fake.sizeMutex.RUnlock()
+	argsForCall := fake.sizeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStorageClient) SizeReturns(result1 int64, result2 error) {
+	fake.sizeMutex.Lock()
+	defer fake.sizeMutex.Unlock()
+	fake.SizeStub = nil
+	fake.sizeReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorageClient) SizeReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.sizeMutex.Lock()
+	defer fake.sizeMutex.Unlock()
+	fake.SizeStub = nil
+	if fake.sizeReturnsOnCall == nil {
+		fake.sizeReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
+		})
+	}
+	fake.sizeReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorageClient) Upload(arg1 string, arg2 string, arg3 string, arg4 common.PutOptions) error {
 	fake.uploadMutex.Lock()
 	ret, specificReturn := fake.uploadReturnsOnCall[len(fake.uploadArgsForCall)]
 	fake.uploadArgsForCall = append(fake.uploadArgsForCall, struct {
 		arg1 string
 		arg2 string
 		arg3 string
-	}{arg1, arg2, arg3})
+		arg4 common.PutOptions
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.UploadStub
 	fakeReturns := fake.uploadReturns
-	fake.recordInvocation("Upload", []interface{}{arg1, arg2, arg3})
+	fake.recordInvocation("Upload", []interface{}{arg1, arg2, arg3, arg4})
 	fake.uploadMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
@@ -791,17 +1103,17 @@ func (fake *FakeStorageClient) UploadCallCount() int {
 	return len(fake.uploadArgsForCall)
 }
 
-func (fake *FakeStorageClient) UploadCalls(stub func(string, string, string) error) {
+func (fake *FakeStorageClient) UploadCalls(stub func(string, string, string, common.PutOptions) error) {
 	fake.uploadMutex.Lock()
 	defer fake.uploadMutex.Unlock()
 	fake.UploadStub = stub
 }
 
-func (fake *FakeStorageClient) UploadArgsForCall(i int) (string, string, string) {
+func (fake *FakeStorageClient) UploadArgsForCall(i int) (string, string, string, common.PutOptions) {
 	fake.uploadMutex.RLock()
 	defer fake.uploadMutex.RUnlock()
 	argsForCall := fake.uploadArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeStorageClient) UploadReturns(result1 error) {
@@ -1,10 +1,12 @@
 package client
 
 import (
-	"encoding/json"
+	"encoding/base64"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"strconv"
 	"strings"
@@ -21,6 +23,7 @@ type StorageClient interface {
 		sourceFilePath string,
 		sourceFileMD5 string,
 		destinationObject string,
+		options common.PutOptions,
 	) error
 
 	Download(
@@ -31,6 +34,7 @@ type StorageClient interface {
 	Copy(
 		srcBlob string,
 		destBlob string,
+		copyOptions common.CopyOptions,
 	) error
 
 	Delete(
@@ -38,13 +42,18 @@ type StorageClient interface {
 	) error
 
 	DeleteRecursive(
-		objects string,
+		prefix string,
+		continueOnError bool,
 	) error
 
 	Exists(
 		object string,
 	) (bool, error)
 
+	Size(
+		object string,
+	) (int64, error)
+
 	SignedUrlPut(
 		object string,
 		expiredInSec int64,
@@ -57,13 +66,26 @@ type StorageClient interface {
 
 	List(
 		prefix string,
+		limit int,
 	) ([]string, error)
 
+	ListDetailed(
+		prefix string,
+		limit int,
+	) ([]common.ObjectInfo, error)
+
 	Properties(
 		object string,
-	) error
+		options common.PropertiesOptions,
+	) (common.ObjectProperties, error)
+
+	Head(
+		object string,
+	) (common.ObjectHead, error)
 
 	EnsureBucketExists() error
+
+	Identity() common.Identity
 }
 
 // 4 MB of part size
@@ -75,6 +97,10 @@ const maxConcurrency = 5
 // Single blob put threshold is 32MB
 const singleBlobPutThreshold = int64(32 * 1024 * 1024)
 
+// CopyObject is limited to objects of up to 1GB, larger ones are copied in 100MB parts
+const multipartCopyThreshold = int64(1024 * 1024 * 1024)
+const multipartCopyPartSize = int64(100 * 1024 * 1024)
+
 func getFileSize(fileName string) (int64, error) {
 	fileInfo, err := os.Stat(fileName)
 	if err != nil {
@@ -85,12 +111,26 @@ func getFileSize(fileName string) (int64, error) {
 
 type DefaultStorageClient struct {
 	storageConfig config.AliStorageConfig
+	client        *oss.Client
+	bucket        *oss.Bucket
 }
 
+// NewStorageClient creates the OSS client once, all operations share it and its connections
 func NewStorageClient(storageConfig config.AliStorageConfig) (StorageClient, error) {
+	client, err := newOSSClient(storageConfig.Endpoint, storageConfig.AccessKeyID, storageConfig.AccessKeySecret)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket, err := client.Bucket(storageConfig.BucketName)
+	if err != nil {
+		return nil, err
+	}
 
 	return DefaultStorageClient{
 		storageConfig: storageConfig,
+		client:        client,
+		bucket:        bucket,
 	}, nil
 }
 
@@ -104,103 +144,170 @@ func newOSSClient(endpoint, accesKeyID, accessKeySecret string) (*oss.Client, er
 	}
 }
 
-func (dsc DefaultStorageClient) Upload(sourceFilePath string, sourceFileMD5 string, destinationObject string) error {
+func (dsc DefaultStorageClient) Upload(sourceFilePath string, sourceFileMD5 string, destinationObject string, putOptions common.PutOptions) error {
 	slog.Info("Uploading object to OSS bucket", "bucket", dsc.storageConfig.BucketName, "object_key", destinationObject, "file_path", sourceFilePath)
 
-	client, err := newOSSClient(dsc.storageConfig.Endpoint, dsc.storageConfig.AccessKeyID, dsc.storageConfig.AccessKeySecret)
+	fileSize, err := getFileSize(sourceFilePath)
 	if err != nil {
 		return err
 	}
-
-	bucket, err := client.Bucket(dsc.storageConfig.BucketName)
-	if err != nil {
-		return err
+	var options []oss.Option
+	if putOptions.ContentType != "" {
+		options = append(options, oss.ContentType(putOptions.ContentType))
+	}
+	for key, value := range putOptions.Metadata {
+		options = append(options, oss.Meta(key, value))
 	}
 
-	fileSize, err := getFileSize(sourceFilePath)
-	if err != nil {
-		return err
+	// The SDK sends the parts itself, with a shared concurrency budget the upload takes a single
+	// slot and sends its parts one at a time
+	routines := maxConcurrency
+	if putOptions.Concurrency != nil {
+		routines = 1
 	}
-	if fileSize <= singleBlobPutThreshold {
-		return bucket.PutObjectFromFile(destinationObject, sourceFilePath, oss.ContentMD5(sourceFileMD5))
+	putOptions.Concurrency.Acquire()
+	defer putOptions.Concurrency.Release()
+
+	if fileSize == 0 {
+		// Go sends the empty body of a file chunked as it can't tell its length, without a body
+		// the object is created with a Content-Length of 0
+		return dsc.bucket.PutObject(destinationObject, nil, append(options, oss.ContentMD5(sourceFileMD5))...)
+	} else if fileSize <= singleBlobPutThreshold {
+		return dsc.bucket.PutObjectFromFile(destinationObject, sourceFilePath, append(options, oss.ContentMD5(sourceFileMD5))...)
 
 	} else {
-		return bucket.UploadFile(destinationObject, sourceFilePath, partSize, oss.Routines(maxConcurrency))
+		return dsc.bucket.UploadFile(destinationObject, sourceFilePath, partSize, append(options, oss.Routines(routines))...)
 	}
 }
 
 func (dsc DefaultStorageClient) Download(sourceObject string, destinationFilePath string) error {
 	slog.Info("Downloading object from OSS bucket", "bucket", dsc.storageConfig.BucketName, "object_key", sourceObject, "file_path", destinationFilePath)
 
-	client, err := newOSSClient(dsc.storageConfig.Endpoint, dsc.storageConfig.AccessKeyID, dsc.storageConfig.AccessKeySecret)
-	if err != nil {
-		return err
-	}
-
-	bucket, err := client.Bucket(dsc.storageConfig.BucketName)
-	if err != nil {
-		return err
-	}
-
-	return bucket.DownloadFile(sourceObject, destinationFilePath, partSize, oss.Routines(maxConcurrency))
+	return dsc.bucket.DownloadFile(sourceObject, destinationFilePath, partSize, oss.Routines(maxConcurrency))
 }
 
-func (dsc DefaultStorageClient) Copy(sourceObject string, destinationObject string) error {
+func (dsc DefaultStorageClient) Copy(sourceObject string, destinationObject string, copyOptions common.CopyOptions) error {
 	slog.Info("copying object within OSS bucket", "bucket", dsc.storageConfig.BucketName, "source_object", sourceObject, "destination_object", destinationObject)
 	srcOut := fmt.Sprintf("%s/%s", dsc.storageConfig.BucketName, sourceObject)
 	destOut := fmt.Sprintf("%s/%s", dsc.storageConfig.BucketName, destinationObject)
 
-	client, err := newOSSClient(dsc.storageConfig.Endpoint, dsc.storageConfig.AccessKeyID, dsc.storageConfig.AccessKeySecret)
+	header, err := dsc.bucket.GetObjectDetailedMeta(sourceObject)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to get metadata of object %s: %w", srcOut, err)
 	}
-
-	bucket, err := client.Bucket(dsc.storageConfig.BucketName)
+	objectSize, err := strconv.ParseInt(header.Get(oss.HTTPHeaderContentLength), 10, 64)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to get size of object %s: %w", srcOut, err)
 	}
 
-	if _, err := bucket.CopyObject(sourceObject, destinationObject); err != nil {
+	if objectSize > multipartCopyThreshold {
+		slog.Info("Copying large object using multipart copy", "source_object", srcOut, "destination_object", destOut, "size", objectSize)
+
+		// Unlike CopyObject, a multipart upload doesn't take over the source's metadata on its own
+		var options []oss.Option
+		if !copyOptions.ResetMetadata {
+			options = metadataOptions(header)
+		}
+		if err := dsc.multipartCopy(sourceObject, destinationObject, objectSize, options); err != nil {
+			return fmt.Errorf("failed to copy object from %s to %s: %w", srcOut, destOut, err)
+		}
+		return nil
+	}
+
+	// OSS copies the source's metadata unless it is replaced, replacing it with none resets it
+	var options []oss.Option
+	if copyOptions.ResetMetadata {
+		options = append(options, oss.MetadataDirective(oss.MetaReplace))
+	}
+
+	if _, err := dsc.bucket.CopyObject(sourceObject, destinationObject, options...); err != nil {
 		return fmt.Errorf("failed to copy object from %s to %s: %w", srcOut, destOut, err)
 	}
 
 	return nil
 }
 
-func (dsc DefaultStorageClient) Delete(object string) error {
-	slog.Info("Deleting object from OSS bucket", "bucket", dsc.storageConfig.BucketName, "object_key", object)
-
-	client, err := newOSSClient(dsc.storageConfig.Endpoint, dsc.storageConfig.AccessKeyID, dsc.storageConfig.AccessKeySecret)
+// multipartCopy copies an object within the bucket using InitiateMultipartUpload, UploadPartCopy and
+// CompleteMultipartUpload. The upload is aborted if any part fails.
+func (dsc DefaultStorageClient) multipartCopy(sourceObject string, destinationObject string, objectSize int64, options []oss.Option) error {
+	imur, err := dsc.bucket.InitiateMultipartUpload(destinationObject, options...)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to initiate multipart upload: %w", err)
 	}
 
-	bucket, err := client.Bucket(dsc.storageConfig.BucketName)
-	if err != nil {
-		return err
+	var parts []oss.UploadPart
+	for offset, partNumber := int64(0), 1; offset < objectSize; offset, partNumber = offset+multipartCopyPartSize, partNumber+1 {
+		size := min(multipartCopyPartSize, objectSize-offset)
+
+		part, err := dsc.bucket.UploadPartCopy(imur, dsc.storageConfig.BucketName, sourceObject, offset, size, partNumber)
+		if err != nil {
+			if abortErr := dsc.bucket.AbortMultipartUpload(imur); abortErr != nil {
+				slog.Warn("Failed to abort multipart upload", "upload_id", imur.UploadID, "error", abortErr)
+			}
+			return fmt.Errorf("failed to copy part %d: %w", partNumber, err)
+		}
+		parts = append(parts, part)
 	}
 
-	return bucket.DeleteObject(object)
+	if _, err := dsc.bucket.CompleteMultipartUpload(imur, parts); err != nil {
+		return fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+	return nil
 }
 
-func (dsc DefaultStorageClient) DeleteRecursive(prefix string) error {
-	if prefix != "" {
-		slog.Info("Deleting all objects with prefix from OSS bucket", "bucket", dsc.storageConfig.BucketName, "prefix", prefix)
-	} else {
-		slog.Info("Deleting all objects from OSS bucket", "bucket", dsc.storageConfig.BucketName)
+// userMetadata returns the user metadata among header, with the lowercase keys OSS stores them with
+func userMetadata(header http.Header) map[string]string {
+	var metadata map[string]string
+	for key, values := range header {
+		if name, ok := strings.CutPrefix(key, oss.HTTPHeaderOssMetaPrefix); ok && len(values) > 0 {
+			if metadata == nil {
+				metadata = map[string]string{}
+			}
+			metadata[strings.ToLower(name)] = values[0]
+		}
 	}
+	return metadata
+}
 
-	client, err := newOSSClient(dsc.storageConfig.Endpoint, dsc.storageConfig.AccessKeyID, dsc.storageConfig.AccessKeySecret)
-	if err != nil {
-		return err
+// metadataOptions returns the options that set the user metadata and content headers of header on a new object
+func metadataOptions(header http.Header) []oss.Option {
+	var options []oss.Option
+	for key, values := range header {
+		if name, ok := strings.CutPrefix(key, oss.HTTPHeaderOssMetaPrefix); ok && len(values) > 0 {
+			options = append(options, oss.Meta(name, values[0]))
+		}
 	}
 
-	bucket, err := client.Bucket(dsc.storageConfig.BucketName)
-	if err != nil {
-		return err
+	contentHeaders := map[string]func(string) oss.Option{
+		oss.HTTPHeaderContentType:        oss.ContentType,
+		oss.HTTPHeaderContentEncoding:    oss.ContentEncoding,
+		oss.HTTPHeaderContentDisposition: oss.ContentDisposition,
+		oss.HTTPHeaderContentLanguage:    oss.ContentLanguage,
+		oss.HTTPHeaderCacheControl:       oss.CacheControl,
+	}
+	for key, option := range contentHeaders {
+		if value := header.Get(key); value != "" {
+			options = append(options, option(value))
+		}
+	}
+	return options
+}
+
+func (dsc DefaultStorageClient) Delete(object string) error {
+	slog.Info("Deleting object from OSS bucket", "bucket", dsc.storageConfig.BucketName, "object_key", object)
+
+	return dsc.bucket.DeleteObject(object)
+}
+
+func (dsc DefaultStorageClient) DeleteRecursive(prefix string, continueOnError bool) error {
+	if prefix != "" {
+		slog.Info("Deleting all objects with prefix from OSS bucket", "bucket", dsc.storageConfig.BucketName, "prefix", prefix)
+	} else {
+		slog.Info("Deleting all objects from OSS bucket", "bucket", dsc.storageConfig.BucketName)
 	}
 
 	var marker string
+	var errs []error
 
 	for {
 		opts := []oss.Option{
@@ -213,13 +320,14 @@ func (dsc DefaultStorageClient) DeleteRecursive(prefix string) error {
 			opts = append(opts, oss.Marker(marker))
 		}
 
-		resp, err := bucket.ListObjects(opts...)
+		resp, err := dsc.bucket.ListObjects(opts...)
 		if err != nil {
-			return fmt.Errorf("error listing objects for delete: %w", err)
+			errs = append(errs, fmt.Errorf("error listing objects for delete: %w", err))
+			return errors.Join(errs...)
 		}
 
 		if len(resp.Objects) == 0 && !resp.IsTruncated {
-			return nil
+			return errors.Join(errs...)
 		}
 
 		keys := make([]string, 0, len(resp.Objects))
@@ -229,9 +337,13 @@ func (dsc DefaultStorageClient) DeleteRecursive(prefix string) error {
 
 		if len(keys) > 0 {
 			quiet := true
-			_, err := bucket.DeleteObjects(keys, oss.DeleteObjectsQuiet(quiet))
+			_, err := dsc.bucket.DeleteObjects(keys, oss.DeleteObjectsQuiet(quiet))
 			if err != nil {
-				return fmt.Errorf("failed to batch delete %d objects (prefix=%q): %w", len(keys), prefix, err)
+				err = fmt.Errorf("failed to batch delete %d objects (prefix=%q): %w", len(keys), prefix, err)
+				if !continueOnError {
+					return err
+				}
+				errs = append(errs, err)
 			}
 		}
 
@@ -241,24 +353,18 @@ func (dsc DefaultStorageClient) DeleteRecursive(prefix string) error {
 		marker = resp.NextMarker
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
 func (dsc DefaultStorageClient) Exists(object string) (bool, error) {
 	slog.Info("Checking if object exists in OSS bucket", "bucket", dsc.storageConfig.BucketName, "object_key", object)
 
-	client, err := newOSSClient(dsc.storageConfig.Endpoint, dsc.storageConfig.AccessKeyID, dsc.storageConfig.AccessKeySecret)
-	if err != nil {
-		return false, err
-	}
-
-	bucket, err := client.Bucket(dsc.storageConfig.BucketName)
-	if err != nil {
-		return false, err
-	}
-
-	objectExists, err := bucket.IsObjectExist(object)
+	objectExists, err := dsc.bucket.IsObjectExist(object)
 	if err != nil {
+		var ossErr oss.ServiceError
+		if errors.As(err, &ossErr) && ossErr.StatusCode == 403 {
+			return false, fmt.Errorf("%w: %w", common.ErrAccessDenied, err)
+		}
 		return false, err
 	}
 
@@ -271,39 +377,31 @@ func (dsc DefaultStorageClient) Exists(object string) (bool, error) {
 	}
 }
 
-func (dsc DefaultStorageClient) SignedUrlPut(object string, expiredInSec int64) (string, error) {
-	slog.Info("Generating signed PUT URL for OSS object", "bucket", dsc.storageConfig.BucketName, "object_key", object, "expiration_seconds", expiredInSec)
+func (dsc DefaultStorageClient) Size(object string) (int64, error) {
+	slog.Info("Getting object size from OSS bucket", "bucket", dsc.storageConfig.BucketName, "object_key", object)
 
-	client, err := newOSSClient(dsc.storageConfig.Endpoint, dsc.storageConfig.AccessKeyID, dsc.storageConfig.AccessKeySecret)
+	meta, err := dsc.bucket.GetObjectMeta(object)
 	if err != nil {
-		return "", err
+		return 0, fmt.Errorf("failed to get size of object %s: %w", object, err)
 	}
 
-	bucket, err := client.Bucket(dsc.storageConfig.BucketName)
-	if err != nil {
-		return "", err
-	}
+	return strconv.ParseInt(meta.Get("Content-Length"), 10, 64)
+}
+
+func (dsc DefaultStorageClient) SignedUrlPut(object string, expiredInSec int64) (string, error) {
+	slog.Info("Generating signed PUT URL for OSS object", "bucket", dsc.storageConfig.BucketName, "object_key", object, "expiration_seconds", expiredInSec)
 
-	return bucket.SignURL(object, oss.HTTPPut, expiredInSec)
+	return dsc.bucket.SignURL(object, oss.HTTPPut, expiredInSec)
 }
 
 func (dsc DefaultStorageClient) SignedUrlGet(object string, expiredInSec int64) (string, error) {
 	slog.Info("Generating signed GET URL for OSS object", "bucket", dsc.storageConfig.BucketName, "object_key", object, "expiration_seconds", expiredInSec)
 
-	client, err := newOSSClient(dsc.storageConfig.Endpoint, dsc.storageConfig.AccessKeyID, dsc.storageConfig.AccessKeySecret)
-	if err != nil {
-		return "", err
-	}
-
-	bucket, err := client.Bucket(dsc.storageConfig.BucketName)
-	if err != nil {
-		return "", err
-	}
-
-	return bucket.SignURL(object, oss.HTTPGet, expiredInSec)
+	return dsc.bucket.SignURL(object, oss.HTTPGet, expiredInSec)
 }
 
-func (dsc DefaultStorageClient) List(prefix string) ([]string, error) {
+// List lists the objects starting with prefix, at most limit of them unless limit is zero or negative
+func (dsc DefaultStorageClient) List(prefix string, limit int) ([]string, error) {
 	if prefix != "" {
 		slog.Info("Listing all objects in OSS bucket with prefix", "bucket", dsc.storageConfig.BucketName, "prefix", prefix)
 	} else {
@@ -323,24 +421,21 @@ func (dsc DefaultStorageClient) List(prefix string) ([]string, error) {
 		if marker != "" {
 			opts = append(opts, oss.Marker(marker))
 		}
-
-		client, err := newOSSClient(dsc.storageConfig.Endpoint, dsc.storageConfig.AccessKeyID, dsc.storageConfig.AccessKeySecret)
-		if err != nil {
-			return nil, err
-		}
-
-		bucket, err := client.Bucket(dsc.storageConfig.BucketName)
-		if err != nil {
-			return nil, err
+		if limit > 0 {
+			// Don't fetch more than needed, a page holds up to 1000 objects
+			opts = append(opts, oss.MaxKeys(min(limit-len(objects), 1000)))
 		}
 
-		resp, err := bucket.ListObjects(opts...)
+		resp, err := dsc.bucket.ListObjects(opts...)
 		if err != nil {
 			return nil, fmt.Errorf("error retrieving page of objects: %w", err)
 		}
 
 		for _, obj := range resp.Objects {
 			objects = append(objects, obj.Key)
+			if len(objects) == limit {
+				return objects, nil
+			}
 		}
 
 		if !resp.IsTruncated {
@@ -352,34 +447,61 @@ func (dsc DefaultStorageClient) List(prefix string) ([]string, error) {
 	return objects, nil
 }
 
-type BlobProperties struct {
-	ETag          string    `json:"etag,omitempty"`
-	LastModified  time.Time `json:"last_modified,omitempty"`
-	ContentLength int64     `json:"content_length,omitempty"`
-}
+// ListDetailed lists like List, along with the size and last modification time of each object
+func (dsc DefaultStorageClient) ListDetailed(prefix string, limit int) ([]common.ObjectInfo, error) {
+	slog.Info("Listing objects with details in OSS bucket", "bucket", dsc.storageConfig.BucketName, "prefix", prefix)
 
-func (dsc DefaultStorageClient) Properties(object string) error {
-	slog.Info("Getting object properties from OSS bucket", "bucket", dsc.storageConfig.BucketName, "object_key", object)
+	var (
+		objects []common.ObjectInfo
+		marker  string
+	)
 
-	client, err := newOSSClient(dsc.storageConfig.Endpoint, dsc.storageConfig.AccessKeyID, dsc.storageConfig.AccessKeySecret)
-	if err != nil {
-		return err
-	}
+	for {
+		var opts []oss.Option
+		if prefix != "" {
+			opts = append(opts, oss.Prefix(prefix))
+		}
+		if marker != "" {
+			opts = append(opts, oss.Marker(marker))
+		}
+		if limit > 0 {
+			// Don't fetch more than needed, a page holds up to 1000 objects
+			opts = append(opts, oss.MaxKeys(min(limit-len(objects), 1000)))
+		}
 
-	bucket, err := client.Bucket(dsc.storageConfig.BucketName)
-	if err != nil {
-		return err
+		resp, err := dsc.bucket.ListObjects(opts...)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving page of objects: %w", err)
+		}
+
+		for _, obj := range resp.Objects {
+			objects = append(objects, common.ObjectInfo{Key: obj.Key, Size: obj.Size, LastModified: obj.LastModified})
+			if len(objects) == limit {
+				return objects, nil
+			}
+		}
+
+		if !resp.IsTruncated {
+			break
+		}
+		marker = resp.NextMarker
 	}
 
-	meta, err := bucket.GetObjectDetailedMeta(object)
+	return objects, nil
+}
+
+// Properties returns the properties of object, or common.ErrObjectNotFound if it doesn't exist
+func (dsc DefaultStorageClient) Properties(object string, options common.PropertiesOptions) (common.ObjectProperties, error) {
+	slog.Info("Getting object properties from OSS bucket", "bucket", dsc.storageConfig.BucketName, "object_key", object)
+
+	meta, err := dsc.bucket.GetObjectDetailedMeta(object)
 	if err != nil {
 		var ossErr oss.ServiceError
 		if errors.As(err, &ossErr) && ossErr.StatusCode == 404 {
-			fmt.Println(`{}`)
-			return nil
+			return common.ObjectProperties{}, common.ErrObjectNotFound
 		}
 
-		return fmt.Errorf("failed to get properties for object %s: %w", object, err)
+		return common.ObjectProperties{}, fmt.Errorf("failed to get properties for object %s: %w", object, err)
 	}
 
 	eTag := meta.Get("ETag")
@@ -405,30 +527,66 @@ func (dsc DefaultStorageClient) Properties(object string) error {
 		}
 	}
 
-	props := BlobProperties{
-		ETag:          strings.Trim(eTag, `"`),
+	return common.ObjectProperties{
+		ETag:          options.ETag(eTag),
 		LastModified:  lastModified,
 		ContentLength: contentLength,
-	}
+		ContentMD5:    meta.Get("Content-Md5"),
+		Metadata:      userMetadata(meta),
+	}, nil
+}
+
+// Head returns all headers OSS returns for object, or common.ErrObjectNotFound if it doesn't exist
+func (dsc DefaultStorageClient) Head(object string) (common.ObjectHead, error) {
+	slog.Info("Getting object head from OSS bucket", "bucket", dsc.storageConfig.BucketName, "object_key", object)
 
-	output, err := json.MarshalIndent(props, "", "  ")
+	meta, err := dsc.bucket.GetObjectDetailedMeta(object)
 	if err != nil {
-		return fmt.Errorf("failed to marshal object properties: %w", err)
+		var ossErr oss.ServiceError
+		if errors.As(err, &ossErr) && ossErr.StatusCode == 404 {
+			return common.ObjectHead{}, common.ErrObjectNotFound
+		}
+
+		return common.ObjectHead{}, fmt.Errorf("failed to get head of object %s: %w", object, err)
 	}
 
-	fmt.Println(string(output))
-	return nil
+	head := common.ObjectHead{
+		ETag:               common.PropertiesOptions{}.ETag(meta.Get(oss.HTTPHeaderEtag)),
+		ContentType:        meta.Get(oss.HTTPHeaderContentType),
+		ContentEncoding:    meta.Get(oss.HTTPHeaderContentEncoding),
+		ContentDisposition: meta.Get(oss.HTTPHeaderContentDisposition),
+		ContentLanguage:    meta.Get(oss.HTTPHeaderContentLanguage),
+		CacheControl:       meta.Get(oss.HTTPHeaderCacheControl),
+		ContentMD5:         meta.Get(oss.HTTPHeaderContentMD5),
+		StorageClass:       meta.Get(oss.HTTPHeaderOssStorageClass),
+		VersionID:          oss.GetVersionId(meta),
+		Metadata:           userMetadata(meta),
+	}
+	if lastModified, err := time.Parse(time.RFC1123, meta.Get(oss.HTTPHeaderLastModified)); err == nil {
+		head.LastModified = lastModified
+	}
+	if contentLength, err := strconv.ParseInt(meta.Get(oss.HTTPHeaderContentLength), 10, 64); err == nil {
+		head.ContentLength = contentLength
+	}
+	// OSS reports the CRC-64/ECMA of every object as a decimal number
+	if crc, err := strconv.ParseUint(meta.Get(oss.HTTPHeaderOssCRC64), 10, 64); err == nil {
+		head.Checksums = map[string]string{
+			common.ChecksumCRC64ECMA: base64.StdEncoding.EncodeToString(binary.BigEndian.AppendUint64(nil, crc)),
+		}
+	}
+	if algorithm := meta.Get(oss.HTTPHeaderOssServerSideEncryption); algorithm != "" {
+		head.Encryption = &common.ObjectEncryption{
+			Algorithm: algorithm,
+			KeyID:     meta.Get(oss.HTTPHeaderOssServerSideEncryptionKeyID),
+		}
+	}
+	return head, nil
 }
 
 func (dsc DefaultStorageClient) EnsureBucketExists() error {
 	slog.Info("Ensuring OSS bucket exists", "bucket", dsc.storageConfig.BucketName)
 
-	client, err := newOSSClient(dsc.storageConfig.Endpoint, dsc.storageConfig.AccessKeyID, dsc.storageConfig.AccessKeySecret)
-	if err != nil {
-		return err
-	}
-
-	exists, err := client.IsBucketExist(dsc.storageConfig.BucketName)
+	exists, err := dsc.client.IsBucketExist(dsc.storageConfig.BucketName)
 	if err != nil {
 		return fmt.Errorf("failed to check if bucket exists: %w", err)
 	}
@@ -438,10 +596,28 @@ func (dsc DefaultStorageClient) EnsureBucketExists() error {
 		return nil
 	}
 
-	if err := client.CreateBucket(dsc.storageConfig.BucketName); err != nil {
+	var options []oss.Option
+	if dsc.storageConfig.StorageClass != "" {
+		options = append(options, oss.StorageClass(oss.StorageClassType(dsc.storageConfig.StorageClass)))
+	}
+	if dsc.storageConfig.ACL != "" {
+		options = append(options, oss.ACL(oss.ACLType(dsc.storageConfig.ACL)))
+	}
+
+	if err := dsc.client.CreateBucket(dsc.storageConfig.BucketName, options...); err != nil {
+		var ossErr oss.ServiceError
+		if errors.As(err, &ossErr) && ossErr.Code == "BucketAlreadyExists" {
+			slog.Warn("OSS bucket got created by another process", "bucket", dsc.storageConfig.BucketName)
+			return nil
+		}
 		return fmt.Errorf("failed to create bucket '%s': %w", dsc.storageConfig.BucketName, err)
 	}
 
 	slog.Info("OSS bucket created successfully", "bucket", dsc.storageConfig.BucketName)
 	return nil
 }
+
+// Identity reports the access key ID in use, the secret is never included
+func (dsc DefaultStorageClient) Identity() common.Identity {
+	return common.Identity{CredentialsSource: "static", Principal: dsc.storageConfig.AccessKeyID}
+}
@@ -0,0 +1,718 @@
+package client_test
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cloudfoundry/storage-cli/alioss/client"
+	"github.com/cloudfoundry/storage-cli/alioss/config"
+	"github.com/cloudfoundry/storage-cli/common"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeOSS answers bucket listings with an empty result and records every bucket creation request
+type fakeOSS struct {
+	createError string
+
+	mu           sync.Mutex
+	createHeader http.Header
+	createBody   string
+}
+
+func (f *fakeOSS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(`<ListAllMyBucketsResult><Buckets></Buckets></ListAllMyBucketsResult>`)) //nolint:errcheck
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body) //nolint:errcheck
+		f.mu.Lock()
+		f.createHeader = r.Header.Clone()
+		f.createBody = string(body)
+		f.mu.Unlock()
+
+		if f.createError != "" {
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprintf(w, "<Error><Code>%s</Code><Message>some message</Message></Error>", f.createError)
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// fakeOSSObject answers for a single object of the given size and records every request it receives
+type fakeOSSObject struct {
+	size      int64
+	header    http.Header
+	missing   bool
+	forbidden bool
+
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func (f *fakeOSSObject) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.requests = append(f.requests, r.Clone(r.Context()))
+	f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/xml")
+	switch {
+	case f.missing:
+		w.WriteHeader(http.StatusNotFound)
+	case f.forbidden:
+		w.WriteHeader(http.StatusForbidden)
+	case r.Method == http.MethodHead:
+		for key, values := range f.header {
+			w.Header()[key] = values
+		}
+		w.Header().Set("Content-Length", strconv.FormatInt(f.size, 10))
+	case r.Method == http.MethodPost && r.URL.Query().Has("uploads"):
+		w.Write([]byte(`<InitiateMultipartUploadResult><Bucket>some-bucket</Bucket><Key>new-object</Key><UploadId>some-upload-id</UploadId></InitiateMultipartUploadResult>`)) //nolint:errcheck
+	case r.Method == http.MethodPost:
+		w.Write([]byte(`<CompleteMultipartUploadResult></CompleteMultipartUploadResult>`)) //nolint:errcheck
+	case r.Method == http.MethodPut && r.URL.Query().Has("partNumber"):
+		w.Write([]byte(`<CopyPartResult><ETag>"some-etag"</ETag></CopyPartResult>`)) //nolint:errcheck
+	case r.Method == http.MethodPut && r.Header.Get("X-Oss-Copy-Source") != "":
+		w.Write([]byte(`<CopyObjectResult></CopyObjectResult>`)) //nolint:errcheck
+	case r.Method == http.MethodPut:
+		size, _ := io.Copy(io.Discard, r.Body) //nolint:errcheck
+		f.mu.Lock()
+		f.size = size
+		f.mu.Unlock()
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeOSSObject) Requests(method string) []*http.Request {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var requests []*http.Request
+	for _, r := range f.requests {
+		if r.Method == method {
+			requests = append(requests, r)
+		}
+	}
+	return requests
+}
+
+// fakeOSSBucket lists the given keys in pages of at most max-keys and records every batch delete it receives
+type fakeOSSBucket struct {
+	keys       []string
+	failDelete bool
+
+	mu      sync.Mutex
+	prefix  string
+	deletes [][]string
+}
+
+func (f *fakeOSSBucket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/xml")
+	query := r.URL.Query()
+
+	switch {
+	case r.Method == http.MethodGet:
+		f.mu.Lock()
+		f.prefix = query.Get("prefix")
+		f.mu.Unlock()
+
+		maxKeys, _ := strconv.Atoi(query.Get("max-keys")) //nolint:errcheck
+		var page []string
+		for _, key := range f.keys {
+			if key > query.Get("marker") && strings.HasPrefix(key, query.Get("prefix")) {
+				page = append(page, key)
+			}
+		}
+		truncated := maxKeys > 0 && len(page) > maxKeys
+		if truncated {
+			page = page[:maxKeys]
+		}
+
+		var contents strings.Builder
+		for _, key := range page {
+			fmt.Fprintf(&contents, "<Contents><Key>%s</Key></Contents>", key)
+		}
+		nextMarker := ""
+		if truncated {
+			nextMarker = page[len(page)-1]
+		}
+		fmt.Fprintf(w, "<ListBucketResult><IsTruncated>%t</IsTruncated><NextMarker>%s</NextMarker>%s</ListBucketResult>", truncated, nextMarker, contents.String())
+	case r.Method == http.MethodPost && query.Has("delete"):
+		if f.failDelete {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		var request struct {
+			Objects []struct {
+				Key string `xml:"Key"`
+			} `xml:"Object"`
+		}
+		Expect(xml.NewDecoder(r.Body).Decode(&request)).To(Succeed())
+
+		var keys []string
+		for _, object := range request.Objects {
+			keys = append(keys, object.Key)
+		}
+		f.mu.Lock()
+		f.deletes = append(f.deletes, keys)
+		f.mu.Unlock()
+
+		w.Write([]byte(`<DeleteResult></DeleteResult>`)) //nolint:errcheck
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+var _ = Describe("DefaultStorageClient", func() {
+	Context("Identity", func() {
+		It("reports the access key ID but not the secret", func() {
+			storageClient, err := client.NewStorageClient(config.AliStorageConfig{
+				AccessKeyID:     "some-access-key-id",
+				AccessKeySecret: "some-access-key-secret",
+				Endpoint:        "oss-cn-hangzhou.aliyuncs.com",
+				BucketName:      "some-bucket",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			identity := storageClient.Identity()
+			Expect(identity.Principal).To(Equal("some-access-key-id"))
+			Expect(fmt.Sprintf("%+v", identity)).ToNot(ContainSubstring("some-access-key-secret"))
+		})
+	})
+
+	Context("NewStorageClient", func() {
+		It("fails for an invalid endpoint", func() {
+			_, err := client.NewStorageClient(config.AliStorageConfig{
+				AccessKeyID:     "id",
+				AccessKeySecret: "secret",
+				Endpoint:        "http://%zz",
+				BucketName:      "some-bucket",
+			})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("shares one client and its connections between operations", func() {
+			var connections atomic.Int32
+			server := httptest.NewUnstartedServer(&fakeOSSObject{size: 10})
+			server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					connections.Add(1)
+				}
+			}
+			server.Start()
+			DeferCleanup(server.Close)
+
+			storageClient, err := client.NewStorageClient(config.AliStorageConfig{
+				AccessKeyID:     "id",
+				AccessKeySecret: "secret",
+				Endpoint:        server.URL,
+				BucketName:      "some-bucket",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			for range 3 {
+				exists, err := storageClient.Exists("some-object")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(exists).To(BeTrue())
+
+				size, err := storageClient.Size("some-object")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(size).To(Equal(int64(10)))
+			}
+
+			Expect(connections.Load()).To(Equal(int32(1)))
+		})
+	})
+
+	Context("Upload", func() {
+		It("stores the object with the upload content type and metadata", func() {
+			object := &fakeOSSObject{}
+			server := httptest.NewServer(object)
+			DeferCleanup(server.Close)
+
+			storageClient, err := client.NewStorageClient(config.AliStorageConfig{
+				AccessKeyID:     "id",
+				AccessKeySecret: "secret",
+				Endpoint:        server.URL,
+				BucketName:      "some-bucket",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			sourceFile := filepath.Join(GinkgoT().TempDir(), "source")
+			Expect(os.WriteFile(sourceFile, []byte("0123456789"), 0644)).To(Succeed())
+
+			err = storageClient.Upload(sourceFile, "eB5eJF1ptWaXm4bijSPyxw==", "new-object", common.PutOptions{
+				ContentType: "text/csv",
+				Metadata:    map[string]string{common.MD5MetadataKey: "781e5e245d69b566979b86e28d23f2c7"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			puts := object.Requests(http.MethodPut)
+			Expect(puts).To(HaveLen(1))
+			Expect(puts[0].Header.Get("Content-Type")).To(Equal("text/csv"))
+			Expect(puts[0].Header.Get("X-Oss-Meta-Md5")).To(Equal("781e5e245d69b566979b86e28d23f2c7"))
+		})
+
+		It("uploads an empty file as a zero-byte object", func() {
+			object := &fakeOSSObject{size: 10}
+			server := httptest.NewServer(object)
+			DeferCleanup(server.Close)
+
+			storageClient, err := client.NewStorageClient(config.AliStorageConfig{
+				AccessKeyID:     "id",
+				AccessKeySecret: "secret",
+				Endpoint:        server.URL,
+				BucketName:      "some-bucket",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			sourceFile := filepath.Join(GinkgoT().TempDir(), "empty")
+			Expect(os.WriteFile(sourceFile, nil, 0644)).To(Succeed())
+
+			err = storageClient.Upload(sourceFile, "1B2M2Y8AsgTpgAmY7PhCfg==", "empty-object", common.PutOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			puts := object.Requests(http.MethodPut)
+			Expect(puts).To(HaveLen(1))
+			Expect(puts[0].ContentLength).To(BeZero())
+			Expect(object.Requests(http.MethodPost)).To(BeEmpty())
+
+			properties, err := storageClient.Properties("empty-object", common.PropertiesOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(properties.ContentLength).To(BeZero())
+		})
+	})
+
+	Context("Copy", func() {
+		var (
+			object        *fakeOSSObject
+			storageConfig config.AliStorageConfig
+		)
+
+		BeforeEach(func() {
+			object = &fakeOSSObject{header: http.Header{
+				"X-Oss-Meta-Owner": []string{"someone"},
+				"Cache-Control":    []string{"no-cache"},
+			}}
+			server := httptest.NewServer(object)
+			DeferCleanup(server.Close)
+
+			storageConfig = config.AliStorageConfig{
+				AccessKeyID:     "id",
+				AccessKeySecret: "secret",
+				Endpoint:        server.URL,
+				BucketName:      "some-bucket",
+			}
+		})
+
+		It("copies objects up to 1GB with a single CopyObject", func() {
+			object.size = 1024 * 1024 * 1024
+
+			storageClient, err := client.NewStorageClient(storageConfig)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = storageClient.Copy("old-object", "new-object", common.CopyOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			puts := object.Requests(http.MethodPut)
+			Expect(puts).To(HaveLen(1))
+			Expect(puts[0].URL.Path).To(Equal("/some-bucket/new-object"))
+			Expect(puts[0].Header.Get("X-Oss-Copy-Source")).To(Equal("/some-bucket/old-object"))
+			Expect(object.Requests(http.MethodPost)).To(BeEmpty())
+		})
+
+		It("copies larger objects in 100MB parts", func() {
+			object.size = 1024*1024*1024 + 1
+
+			storageClient, err := client.NewStorageClient(storageConfig)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = storageClient.Copy("old-object", "new-object", common.CopyOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			posts := object.Requests(http.MethodPost)
+			Expect(posts).To(HaveLen(2))
+			Expect(posts[0].URL.Query().Has("uploads")).To(BeTrue())
+			Expect(posts[0].Header.Get("X-Oss-Meta-Owner")).To(Equal("someone"))
+			Expect(posts[0].Header.Get("Cache-Control")).To(Equal("no-cache"))
+			Expect(posts[1].URL.Query().Get("uploadId")).To(Equal("some-upload-id"))
+
+			parts := object.Requests(http.MethodPut)
+			Expect(parts).To(HaveLen(11))
+			Expect(parts[0].Header.Get("X-Oss-Copy-Source")).To(Equal("/some-bucket/old-object"))
+			Expect(parts[0].Header.Get("X-Oss-Copy-Source-Range")).To(Equal("bytes=0-104857599"))
+			Expect(parts[10].URL.Query().Get("partNumber")).To(Equal("11"))
+			Expect(parts[10].Header.Get("X-Oss-Copy-Source-Range")).To(Equal("bytes=1048576000-1073741824"))
+		})
+
+		It("does not take over the metadata of larger objects when resetting it", func() {
+			object.size = 1024*1024*1024 + 1
+
+			storageClient, err := client.NewStorageClient(storageConfig)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = storageClient.Copy("old-object", "new-object", common.CopyOptions{ResetMetadata: true})
+			Expect(err).ToNot(HaveOccurred())
+
+			posts := object.Requests(http.MethodPost)
+			Expect(posts).ToNot(BeEmpty())
+			Expect(posts[0].Header.Get("X-Oss-Meta-Owner")).To(BeEmpty())
+			Expect(posts[0].Header.Get("Cache-Control")).To(BeEmpty())
+		})
+	})
+
+	Context("DeleteRecursive", func() {
+		var (
+			bucket        *fakeOSSBucket
+			storageClient client.StorageClient
+		)
+
+		BeforeEach(func() {
+			bucket = &fakeOSSBucket{}
+			for i := 0; i < 1500; i++ {
+				bucket.keys = append(bucket.keys, fmt.Sprintf("prefix/%04d", i))
+			}
+			bucket.keys = append(bucket.keys, "zzz-other")
+
+			server := httptest.NewServer(bucket)
+			DeferCleanup(server.Close)
+
+			var err error
+			storageClient, err = client.NewStorageClient(config.AliStorageConfig{
+				AccessKeyID:     "id",
+				AccessKeySecret: "secret",
+				Endpoint:        server.URL,
+				BucketName:      "some-bucket",
+			})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("deletes the objects under the prefix in batches of 1000", func() {
+			err := storageClient.DeleteRecursive("prefix/", false)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(bucket.prefix).To(Equal("prefix/"))
+			Expect(bucket.deletes).To(HaveLen(2))
+			Expect(bucket.deletes[0]).To(HaveLen(1000))
+			Expect(bucket.deletes[0][0]).To(Equal("prefix/0000"))
+			Expect(bucket.deletes[1]).To(HaveLen(500))
+			Expect(bucket.deletes[1][499]).To(Equal("prefix/1499"))
+		})
+
+		It("deletes everything for an empty prefix", func() {
+			err := storageClient.DeleteRecursive("", false)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(bucket.deletes).To(HaveLen(2))
+			Expect(bucket.deletes[1]).To(HaveLen(501))
+			Expect(bucket.deletes[1][500]).To(Equal("zzz-other"))
+		})
+
+		It("stops at the first failed batch", func() {
+			bucket.failDelete = true
+
+			err := storageClient.DeleteRecursive("prefix/", false)
+			Expect(err).To(MatchError(ContainSubstring("failed to batch delete 1000 objects")))
+		})
+
+		It("tries all batches with continueOnError", func() {
+			bucket.failDelete = true
+
+			err := storageClient.DeleteRecursive("prefix/", true)
+			Expect(err).To(MatchError(ContainSubstring("failed to batch delete 1000 objects")))
+			Expect(err).To(MatchError(ContainSubstring("failed to batch delete 500 objects")))
+		})
+	})
+
+	Context("Properties", func() {
+		var (
+			object        *fakeOSSObject
+			storageClient client.StorageClient
+			lastModified  time.Time
+		)
+
+		BeforeEach(func() {
+			var err error
+			// Parsed like the client does, so that the zones compare equal
+			lastModified, err = time.Parse(time.RFC1123, "Fri, 01 Mar 2024 12:30:45 GMT")
+			Expect(err).ToNot(HaveOccurred())
+
+			object = &fakeOSSObject{size: 7, header: http.Header{
+				"Etag":          []string{`"9A0364B9E99BB480DD25E1F0284C8555"`},
+				"Last-Modified": []string{"Fri, 01 Mar 2024 12:30:45 GMT"},
+			}}
+			server := httptest.NewServer(object)
+			DeferCleanup(server.Close)
+
+			storageClient, err = client.NewStorageClient(config.AliStorageConfig{
+				AccessKeyID:     "id",
+				AccessKeySecret: "secret",
+				Endpoint:        server.URL,
+				BucketName:      "some-bucket",
+			})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns the etag, last modification and size of the object", func() {
+			properties, err := storageClient.Properties("some-object", common.PropertiesOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(properties).To(Equal(common.ObjectProperties{
+				ETag:          "9A0364B9E99BB480DD25E1F0284C8555",
+				LastModified:  lastModified,
+				ContentLength: 7,
+			}))
+		})
+
+		It("keeps the quotes of the etag when asked to", func() {
+			properties, err := storageClient.Properties("some-object", common.PropertiesOptions{RawETag: true})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(properties.ETag).To(Equal(`"9A0364B9E99BB480DD25E1F0284C8555"`))
+		})
+
+		It("reports a missing object as not found", func() {
+			object.missing = true
+
+			_, err := storageClient.Properties("some-object", common.PropertiesOptions{})
+			Expect(err).To(MatchError(common.ErrObjectNotFound))
+		})
+
+		It("includes the user metadata of the object", func() {
+			object.header.Set("X-Oss-Meta-Owner", "team-a")
+			object.header.Set("X-Oss-Meta-Build_id", "42")
+
+			properties, err := storageClient.Properties("some-object", common.PropertiesOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(properties.Metadata).To(Equal(map[string]string{"owner": "team-a", "build_id": "42"}))
+		})
+
+		It("returns the same properties as the other storage types for an equivalent object", func() {
+			object.header.Set("Etag", `"9a0364b9e99bb480dd25e1f0284c8555"`)
+			object.header.Set("X-Oss-Meta-Owner", "team-a")
+
+			properties, err := storageClient.Properties("some-object", common.PropertiesOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			// The s3 and gcs tests expect the very same document
+			Expect(common.MarshalObjectProperties(properties)).To(BeEquivalentTo(`{
+  "content_length": 7,
+  "etag": "9a0364b9e99bb480dd25e1f0284c8555",
+  "last_modified": "2024-03-01T12:30:45Z",
+  "metadata": {
+    "owner": "team-a"
+  }
+}`))
+		})
+	})
+
+	Context("Head", func() {
+		var (
+			object        *fakeOSSObject
+			storageClient client.StorageClient
+			lastModified  time.Time
+		)
+
+		BeforeEach(func() {
+			var err error
+			// Parsed like the client does, so that the zones compare equal
+			lastModified, err = time.Parse(time.RFC1123, "Fri, 01 Mar 2024 12:30:45 GMT")
+			Expect(err).ToNot(HaveOccurred())
+
+			object = &fakeOSSObject{size: 7, header: http.Header{
+				"Etag":                                []string{`"9A0364B9E99BB480DD25E1F0284C8555"`},
+				"Last-Modified":                       []string{"Fri, 01 Mar 2024 12:30:45 GMT"},
+				"Content-Type":                        []string{"text/plain"},
+				"Content-Md5":                         []string{"mgNkuembtIDdJeHwKEyFVQ=="},
+				"X-Oss-Storage-Class":                 []string{"IA"},
+				"X-Oss-Server-Side-Encryption":        []string{"KMS"},
+				"X-Oss-Server-Side-Encryption-Key-Id": []string{"some-key"},
+				"X-Oss-Meta-Owner":                    []string{"team-a"},
+				"X-Oss-Hash-Crc64ecma":                []string{"11051210869376104954"},
+			}}
+			server := httptest.NewServer(object)
+			DeferCleanup(server.Close)
+
+			storageClient, err = client.NewStorageClient(config.AliStorageConfig{
+				AccessKeyID:     "id",
+				AccessKeySecret: "secret",
+				Endpoint:        server.URL,
+				BucketName:      "some-bucket",
+			})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns all headers of the object", func() {
+			head, err := storageClient.Head("some-object")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(head).To(Equal(common.ObjectHead{
+				ETag:          "9A0364B9E99BB480DD25E1F0284C8555",
+				LastModified:  lastModified,
+				ContentLength: 7,
+				ContentType:   "text/plain",
+				ContentMD5:    "mgNkuembtIDdJeHwKEyFVQ==",
+				StorageClass:  "IA",
+				Metadata:      map[string]string{"owner": "team-a"},
+				Encryption:    &common.ObjectEncryption{Algorithm: "KMS", KeyID: "some-key"},
+				Checksums:     map[string]string{common.ChecksumCRC64ECMA: "mV3Ju98ZOfo="},
+			}))
+		})
+
+		It("reports a missing object as not found", func() {
+			object.missing = true
+
+			_, err := storageClient.Head("some-object")
+			Expect(err).To(MatchError(common.ErrObjectNotFound))
+		})
+	})
+
+	Context("SignedUrlGet", func() {
+		It("escapes keys with spaces, unicode and plus signs so the url reaches the object", func() {
+			object := &fakeOSSObject{size: 7}
+			server := httptest.NewServer(object)
+			DeferCleanup(server.Close)
+
+			storageClient, err := client.NewStorageClient(config.AliStorageConfig{
+				AccessKeyID:     "id",
+				AccessKeySecret: "secret",
+				Endpoint:        server.URL,
+				BucketName:      "some-bucket",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			signedURL, err := storageClient.SignedUrlGet("dir a/üñîçød ë file+1.txt", 60)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(signedURL).ToNot(ContainSubstring(" "))
+
+			req, err := http.NewRequest(http.MethodHead, signedURL, nil)
+			Expect(err).ToNot(HaveOccurred())
+			resp, err := http.DefaultClient.Do(req)
+			Expect(err).ToNot(HaveOccurred())
+			resp.Body.Close() //nolint:errcheck
+
+			heads := object.Requests(http.MethodHead)
+			Expect(heads).To(HaveLen(1))
+			Expect(heads[0].URL.Path).To(Equal("/some-bucket/dir a/üñîçød ë file+1.txt"))
+		})
+	})
+
+	Context("Exists", func() {
+		var (
+			object        *fakeOSSObject
+			storageClient client.StorageClient
+		)
+
+		BeforeEach(func() {
+			object = &fakeOSSObject{size: 7}
+			server := httptest.NewServer(object)
+			DeferCleanup(server.Close)
+
+			var err error
+			storageClient, err = client.NewStorageClient(config.AliStorageConfig{
+				AccessKeyID:     "id",
+				AccessKeySecret: "secret",
+				Endpoint:        server.URL,
+				BucketName:      "some-bucket",
+			})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("reports an existing object", func() {
+			exists, err := storageClient.Exists("some-object")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(exists).To(BeTrue())
+		})
+
+		It("reports a missing object", func() {
+			object.missing = true
+
+			exists, err := storageClient.Exists("some-object")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(exists).To(BeFalse())
+		})
+
+		It("marks a denied access", func() {
+			object.forbidden = true
+
+			_, err := storageClient.Exists("some-object")
+			Expect(err).To(MatchError(common.ErrAccessDenied))
+		})
+	})
+
+	Context("EnsureBucketExists", func() {
+		var (
+			oss           *fakeOSS
+			storageConfig config.AliStorageConfig
+		)
+
+		BeforeEach(func() {
+			oss = &fakeOSS{}
+			server := httptest.NewServer(oss)
+			DeferCleanup(server.Close)
+
+			storageConfig = config.AliStorageConfig{
+				AccessKeyID:     "id",
+				AccessKeySecret: "secret",
+				Endpoint:        server.URL,
+				BucketName:      "some-bucket",
+			}
+		})
+
+		It("forwards the storage class and acl when the bucket is created", func() {
+			storageConfig.StorageClass = "IA"
+			storageConfig.ACL = "public-read"
+
+			storageClient, err := client.NewStorageClient(storageConfig)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = storageClient.EnsureBucketExists()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(oss.createHeader.Get("X-Oss-Acl")).To(Equal("public-read"))
+			Expect(oss.createBody).To(ContainSubstring("<StorageClass>IA</StorageClass>"))
+		})
+
+		It("creates a private standard bucket by default", func() {
+			storageClient, err := client.NewStorageClient(storageConfig)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = storageClient.EnsureBucketExists()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(oss.createHeader.Get("X-Oss-Acl")).To(BeEmpty())
+			Expect(oss.createBody).To(ContainSubstring("<StorageClass>Standard</StorageClass>"))
+		})
+
+		It("succeeds when the bucket got created in the meantime", func() {
+			oss.createError = "BucketAlreadyExists"
+
+			storageClient, err := client.NewStorageClient(storageConfig)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = storageClient.EnsureBucketExists()
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("fails when the bucket can't be created", func() {
+			oss.createError = "TooManyBuckets"
+
+			storageClient, err := client.NewStorageClient(storageConfig)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = storageClient.EnsureBucketExists()
+			Expect(err).To(MatchError(ContainSubstring("failed to create bucket 'some-bucket'")))
+		})
+	})
+})
@@ -6,10 +6,13 @@ import (
 )
 
 type AliStorageConfig struct {
-	AccessKeyID     string `json:"access_key_id"`
-	AccessKeySecret string `json:"access_key_secret"`
-	Endpoint        string `json:"endpoint"`
-	BucketName      string `json:"bucket_name"`
+	AccessKeyID     string `json:"access_key_id" required:"true"`
+	AccessKeySecret string `json:"access_key_secret" required:"true"`
+	Endpoint        string `json:"endpoint" required:"true"`
+	BucketName      string `json:"bucket_name" required:"true"`
+	// StorageClass and ACL are only applied when ensure-storage-exists creates the bucket
+	StorageClass string `json:"storage_class"`
+	ACL          string `json:"acl"`
 }
 
 // NewFromReader returns a new ali-storage-cli configuration struct from the contents of reader.
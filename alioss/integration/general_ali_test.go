@@ -17,7 +17,6 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/onsi/gomega/gbytes"
 )
 
 var _ = Describe("General testing for all Ali regions", func() {
@@ -43,24 +42,24 @@ var _ = Describe("General testing for all Ali regions", func() {
 			defer func() {
 				cliSession, err := integration.RunCli(cliPath, configPath, storageType, "delete", blobName)
 				Expect(err).ToNot(HaveOccurred())
-				Expect(cliSession.ExitCode()).To(BeZero())
+				Expect(cliSession.ExitCode).To(BeZero())
 			}()
 
 			cliSession, err := integration.RunCli(cliPath, configPath, storageType, "put", contentFile, blobName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			cliSession, err = integration.RunCli(cliPath, configPath, storageType, "exists", blobName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
-			Expect(cliSession.Err).Should(gbytes.Say(`"msg":"Object exists in OSS bucket"`))
+			Expect(cliSession.ExitCode).To(BeZero())
+			Expect(string(cliSession.Stderr)).To(MatchRegexp(`"msg":"Object exists in OSS bucket"`))
 		})
 
 		It("`put` will use UploadFile option for file bigger than singleBlobPutThreshold(32MB)", func() {
 			defer func() {
 				cliSession, err := integration.RunCli(cliPath, configPath, storageType, "delete", blobName)
 				Expect(err).ToNot(HaveOccurred())
-				Expect(cliSession.ExitCode()).To(BeZero())
+				Expect(cliSession.ExitCode).To(BeZero())
 			}()
 
 			contentSize := 1024 * 1024 * 64 //64MB
@@ -82,12 +81,12 @@ var _ = Describe("General testing for all Ali regions", func() {
 
 			cliSession, err := integration.RunCli(cliPath, configPath, storageType, "put", contentFile, blobName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			cliSession, err = integration.RunCli(cliPath, configPath, storageType, "exists", blobName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
-			Expect(cliSession.Err).Should(gbytes.Say(`"msg":"Object exists in OSS bucket"`))
+			Expect(cliSession.ExitCode).To(BeZero())
+			Expect(string(cliSession.Stderr)).To(MatchRegexp(`"msg":"Object exists in OSS bucket"`))
 
 			// Compare the content of the original and downloaded blobs
 			tmpLocalFile, err := os.CreateTemp("", "download-big-file-alioss")
@@ -97,7 +96,7 @@ var _ = Describe("General testing for all Ali regions", func() {
 			defer os.Remove(tmpLocalFile.Name()) //nolint:errcheck
 			cliSession, err = integration.RunCli(cliPath, configPath, storageType, "get", blobName, tmpLocalFile.Name())
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			// Verify file size matches
 			downloadedInfo, err := os.Stat(tmpLocalFile.Name())
@@ -120,7 +119,7 @@ var _ = Describe("General testing for all Ali regions", func() {
 			defer func() {
 				cliSession, err := integration.RunCli(cliPath, configPath, storageType, "delete", blobName)
 				Expect(err).ToNot(HaveOccurred())
-				Expect(cliSession.ExitCode()).To(BeZero())
+				Expect(cliSession.ExitCode).To(BeZero())
 			}()
 
 			tmpLocalFile, _ := os.CreateTemp("", "ali-storage-cli-download") //nolint:errcheck
@@ -130,11 +129,11 @@ var _ = Describe("General testing for all Ali regions", func() {
 			contentFile = integration.MakeContentFile("initial content")
 			cliSession, err := integration.RunCli(cliPath, configPath, storageType, "put", contentFile, blobName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			cliSession, err = integration.RunCli(cliPath, configPath, storageType, "get", blobName, tmpLocalFile.Name())
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			gottenBytes, _ := os.ReadFile(tmpLocalFile.Name()) //nolint:errcheck
 			Expect(string(gottenBytes)).To(Equal("initial content"))
@@ -142,11 +141,11 @@ var _ = Describe("General testing for all Ali regions", func() {
 			contentFile = integration.MakeContentFile("updated content")
 			cliSession, err = integration.RunCli(cliPath, configPath, storageType, "put", contentFile, blobName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			cliSession, err = integration.RunCli(cliPath, configPath, storageType, "get", blobName, tmpLocalFile.Name())
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			gottenBytes, _ = os.ReadFile(tmpLocalFile.Name()) //nolint:errcheck
 			Expect(string(gottenBytes)).To(Equal("updated content"))
@@ -164,9 +163,9 @@ var _ = Describe("General testing for all Ali regions", func() {
 
 			cliSession, err := integration.RunCli(cliPath, configPath, storageType, "put", contentFile, blobName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(Equal(1))
+			Expect(cliSession.ExitCode).To(Equal(1))
 
-			consoleOutput := bytes.NewBuffer(cliSession.Err.Contents()).String()
+			consoleOutput := bytes.NewBuffer(cliSession.Stderr).String()
 			Expect(consoleOutput).To(ContainSubstring("upload failure"))
 		})
 	})
@@ -178,18 +177,18 @@ var _ = Describe("General testing for all Ali regions", func() {
 			defer func() {
 				cliSession, err := integration.RunCli(cliPath, configPath, storageType, "delete", blobName)
 				Expect(err).ToNot(HaveOccurred())
-				Expect(cliSession.ExitCode()).To(BeZero())
+				Expect(cliSession.ExitCode).To(BeZero())
 
 				_ = os.Remove(outputFilePath) //nolint:errcheck
 			}()
 
 			cliSession, err := integration.RunCli(cliPath, configPath, storageType, "put", contentFile, blobName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			cliSession, err = integration.RunCli(cliPath, configPath, storageType, "get", blobName, outputFilePath)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			fileContent, _ := os.ReadFile(outputFilePath) //nolint:errcheck
 			Expect(string(fileContent)).To(Equal("foo"))
@@ -201,20 +200,20 @@ var _ = Describe("General testing for all Ali regions", func() {
 			defer func() {
 				cliSession, err := integration.RunCli(cliPath, configPath, storageType, "delete", blobName)
 				Expect(err).ToNot(HaveOccurred())
-				Expect(cliSession.ExitCode()).To(BeZero())
+				Expect(cliSession.ExitCode).To(BeZero())
 			}()
 
 			cliSession, err := integration.RunCli(cliPath, configPath, storageType, "put", contentFile, blobName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			cliSession, err = integration.RunCli(cliPath, configPath, storageType, "delete", blobName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			cliSession, err = integration.RunCli(cliPath, configPath, storageType, "exists", blobName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(Equal(3))
+			Expect(cliSession.ExitCode).To(Equal(3))
 		})
 	})
 
@@ -235,7 +234,7 @@ var _ = Describe("General testing for all Ali regions", func() {
 
 				for _, b := range []string{blob1, blob2, otherBlob} {
 					cliSession, err := integration.RunCli(cliPath, configPath, storageType, "delete", b)
-					if err == nil && (cliSession.ExitCode() == 0 || cliSession.ExitCode() == 3) {
+					if err == nil && (cliSession.ExitCode == 0 || cliSession.ExitCode == 3) {
 						continue
 					}
 				}
@@ -243,31 +242,31 @@ var _ = Describe("General testing for all Ali regions", func() {
 
 			cliSession, err := integration.RunCli(cliPath, configPath, storageType, "put", contentFile1, blob1)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			cliSession, err = integration.RunCli(cliPath, configPath, storageType, "put", contentFile2, blob2)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			cliSession, err = integration.RunCli(cliPath, configPath, storageType, "put", contentFileOther, otherBlob)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			cliSession, err = integration.RunCli(cliPath, configPath, storageType, "delete-recursive", prefix)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			cliSession, err = integration.RunCli(cliPath, configPath, storageType, "exists", blob1)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(Equal(3))
+			Expect(cliSession.ExitCode).To(Equal(3))
 
 			cliSession, err = integration.RunCli(cliPath, configPath, storageType, "exists", blob2)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(Equal(3))
+			Expect(cliSession.ExitCode).To(Equal(3))
 
 			cliSession, err = integration.RunCli(cliPath, configPath, storageType, "exists", otherBlob)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(Equal(0))
+			Expect(cliSession.ExitCode).To(Equal(0))
 		})
 	})
 
@@ -283,8 +282,8 @@ var _ = Describe("General testing for all Ali regions", func() {
 						GinkgoWriter.Printf("cleanup: error deleting %s: %v\n", b, err)
 						continue
 					}
-					if cliSession.ExitCode() != 0 && cliSession.ExitCode() != 3 {
-						GinkgoWriter.Printf("cleanup: delete %s exited with code %d\n", b, cliSession.ExitCode())
+					if cliSession.ExitCode != 0 && cliSession.ExitCode != 3 {
+						GinkgoWriter.Printf("cleanup: delete %s exited with code %d\n", b, cliSession.ExitCode)
 					}
 				}
 			}()
@@ -292,11 +291,11 @@ var _ = Describe("General testing for all Ali regions", func() {
 			contentFile = integration.MakeContentFile("copied content")
 			cliSession, err := integration.RunCli(cliPath, configPath, storageType, "put", contentFile, srcBlob)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			cliSession, err = integration.RunCli(cliPath, configPath, storageType, "copy", srcBlob, destBlob)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			tmpLocalFile, _ := os.CreateTemp("", "ali-storage-cli-copy") //nolint:errcheck
 			tmpLocalFile.Close()                                         //nolint:errcheck
@@ -304,7 +303,7 @@ var _ = Describe("General testing for all Ali regions", func() {
 
 			cliSession, err = integration.RunCli(cliPath, configPath, storageType, "get", destBlob, tmpLocalFile.Name())
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			gottenBytes, _ := os.ReadFile(tmpLocalFile.Name()) //nolint:errcheck
 			Expect(string(gottenBytes)).To(Equal("copied content"))
@@ -316,22 +315,22 @@ var _ = Describe("General testing for all Ali regions", func() {
 			defer func() {
 				cliSession, err := integration.RunCli(cliPath, configPath, storageType, "delete", blobName)
 				Expect(err).ToNot(HaveOccurred())
-				Expect(cliSession.ExitCode()).To(BeZero())
+				Expect(cliSession.ExitCode).To(BeZero())
 			}()
 
 			cliSession, err := integration.RunCli(cliPath, configPath, storageType, "put", contentFile, blobName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			cliSession, err = integration.RunCli(cliPath, configPath, storageType, "exists", blobName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(Equal(0))
+			Expect(cliSession.ExitCode).To(Equal(0))
 		})
 
 		It("returns 3 for a not existing blob", func() {
 			cliSession, err := integration.RunCli(cliPath, configPath, storageType, "exists", blobName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(Equal(3))
+			Expect(cliSession.ExitCode).To(Equal(3))
 		})
 	})
 
@@ -339,22 +338,22 @@ var _ = Describe("General testing for all Ali regions", func() {
 		It("returns 0 for an existing blob", func() {
 			cliSession, err := integration.RunCli(cliPath, configPath, storageType, "sign", "some-blob", "get", "60s")
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
-			getUrl := bytes.NewBuffer(cliSession.Out.Contents()).String()
+			getUrl := bytes.NewBuffer(cliSession.Stdout).String()
 			Expect(getUrl).To(MatchRegexp("http://" + bucketName + "." + endpoint + "/some-blob"))
 
 			cliSession, err = integration.RunCli(cliPath, configPath, storageType, "sign", "some-blob", "put", "60s")
 			Expect(err).ToNot(HaveOccurred())
 
-			putUrl := bytes.NewBuffer(cliSession.Out.Contents()).String()
+			putUrl := bytes.NewBuffer(cliSession.Stdout).String()
 			Expect(putUrl).To(MatchRegexp("http://" + bucketName + "." + endpoint + "/some-blob"))
 		})
 
 		It("returns 3 for a not existing blob", func() {
 			cliSession, err := integration.RunCli(cliPath, configPath, storageType, "exists", blobName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(Equal(3))
+			Expect(cliSession.ExitCode).To(Equal(3))
 		})
 	})
 
@@ -365,9 +364,9 @@ var _ = Describe("General testing for all Ali regions", func() {
 
 			cliSession, err := integration.RunCli(cliPath, configPath, storageType, "-v")
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(Equal(0))
+			Expect(cliSession.ExitCode).To(Equal(0))
 
-			consoleOutput := bytes.NewBuffer(cliSession.Out.Contents()).String()
+			consoleOutput := bytes.NewBuffer(cliSession.Stdout).String()
 			Expect(consoleOutput).To(ContainSubstring("version"))
 		})
 	})
@@ -397,21 +396,21 @@ var _ = Describe("General testing for all Ali regions", func() {
 
 			cliSession, err := integration.RunCli(cliPath, configPath, storageType, "put", contentFile1, blob1)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			cliSession, err = integration.RunCli(cliPath, configPath, storageType, "put", contentFile2, blob2)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			cliSession, err = integration.RunCli(cliPath, configPath, storageType, "put", contentFileOther, otherBlob)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			cliSession, err = integration.RunCli(cliPath, configPath, storageType, "list", prefix)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
-			output := bytes.NewBuffer(cliSession.Out.Contents()).String()
+			output := bytes.NewBuffer(cliSession.Stdout).String()
 
 			Expect(output).To(ContainSubstring(blob1))
 			Expect(output).To(ContainSubstring(blob2))
@@ -434,7 +433,7 @@ var _ = Describe("General testing for all Ali regions", func() {
 
 				cliSession, err := integration.RunCli(cliPath, configPath, storageType, "put", contentFile, blobName)
 				Expect(err).ToNot(HaveOccurred())
-				Expect(cliSession.ExitCode()).To(BeZero())
+				Expect(cliSession.ExitCode).To(BeZero())
 			}
 
 			defer func() {
@@ -444,7 +443,7 @@ var _ = Describe("General testing for all Ali regions", func() {
 
 				for _, b := range blobNames {
 					cliSession, err := integration.RunCli(cliPath, configPath, storageType, "delete", b)
-					if err == nil && (cliSession.ExitCode() == 0 || cliSession.ExitCode() == 3) {
+					if err == nil && (cliSession.ExitCode == 0 || cliSession.ExitCode == 3) {
 						continue
 					}
 				}
@@ -452,9 +451,9 @@ var _ = Describe("General testing for all Ali regions", func() {
 
 			cliSession, err := integration.RunCli(cliPath, configPath, storageType, "list", prefix)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
-			output := bytes.NewBuffer(cliSession.Out.Contents()).String()
+			output := bytes.NewBuffer(cliSession.Stdout).String()
 
 			for _, b := range blobNames {
 				Expect(output).To(ContainSubstring(b))
@@ -510,7 +509,7 @@ var _ = Describe("General testing for all Ali regions", func() {
 
 			s1, err := integration.RunCli(cliPath, configPath, storageType, "ensure-storage-exists")
 			Expect(err).ToNot(HaveOccurred())
-			Expect(s1.ExitCode()).To(BeZero())
+			Expect(s1.ExitCode).To(BeZero())
 
 			Eventually(func(g Gomega) bool {
 				exists, existsErr := ossClient.IsBucketExist(newBucketName)
@@ -522,11 +521,11 @@ var _ = Describe("General testing for all Ali regions", func() {
 		It("is idempotent", func() {
 			s1, err := integration.RunCli(cliPath, configPath, storageType, "ensure-storage-exists")
 			Expect(err).ToNot(HaveOccurred())
-			Expect(s1.ExitCode()).To(BeZero())
+			Expect(s1.ExitCode).To(BeZero())
 
 			s2, err := integration.RunCli(cliPath, configPath, storageType, "ensure-storage-exists")
 			Expect(err).ToNot(HaveOccurred())
-			Expect(s2.ExitCode()).To(BeZero())
+			Expect(s2.ExitCode).To(BeZero())
 		})
 	})
 })
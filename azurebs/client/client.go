@@ -2,13 +2,18 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"crypto/md5"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
 	"strings"
 	"time"
+
+	"github.com/cloudfoundry/storage-cli/azurebs/config"
+	"github.com/cloudfoundry/storage-cli/common"
 )
 
 type AzBlobstore struct {
@@ -30,80 +35,175 @@ func New(storageClient StorageClient) (AzBlobstore, error) {
 	return AzBlobstore{storageClient: storageClient}, nil
 }
 
-func (client *AzBlobstore) Put(sourceFilePath string, dest string) error {
+func (client *AzBlobstore) Put(ctx context.Context, sourceFilePath string, dest string, options common.PutOptions) error {
+	_, err := client.PutWithETag(ctx, sourceFilePath, dest, options)
+	return err
+}
+
+// PutWithETag uploads a file like Put and returns the ETag of the new blob as reported by the upload
+func (client *AzBlobstore) PutWithETag(ctx context.Context, sourceFilePath string, dest string, options common.PutOptions) (string, error) {
 	sourceMD5, err := client.getMD5(sourceFilePath)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	source, err := os.Open(sourceFilePath)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer source.Close() //nolint:errcheck
 	fileSize, err := getFileSize(source)
 	if err != nil {
-		return err
+		return "", err
 	}
+
+	var etag string
 	if fileSize <= singleBlobPutThreshold {
-		md5, err := client.storageClient.Upload(source, dest)
+		var md5 []byte
+		md5, etag, err = client.storageClient.Upload(ctx, common.NewThrottledReader(ctx, options.Bandwidth, source), dest, sourceMD5, options)
 		if err != nil {
-			return fmt.Errorf("upload failure: %w", err)
+			return "", fmt.Errorf("upload failure: %w", err)
 		}
 
 		if !bytes.Equal(sourceMD5, md5) {
 			slog.Error("Upload failed due to MD5 mismatch, deleting blob", "blob", dest, "expected_md5", fmt.Sprintf("%x", sourceMD5), "received_md5", fmt.Sprintf("%x", md5))
 
-			err := client.storageClient.Delete(dest)
+			err := client.storageClient.Delete(ctx, dest)
 			if err != nil {
 				slog.Error("Failed to delete blob after MD5 mismatch", "blob", dest, "error", err)
 
 			}
-			return fmt.Errorf("MD5 mismatch: expected %x, got %x", sourceMD5, md5)
+			return "", fmt.Errorf("MD5 mismatch: expected %x, got %x", sourceMD5, md5)
 		}
 
 		slog.Debug("MD5 verification passed", "blob", dest, "md5", fmt.Sprintf("%x", md5))
 
 	} else {
-		err := client.storageClient.UploadStream(source, dest)
+		etag, err = client.storageClient.UploadStream(ctx, common.NewThrottledReader(ctx, options.Bandwidth, source), dest, sourceMD5, options)
 		if err != nil {
-			return fmt.Errorf("upload failure: %w", err)
+			return "", fmt.Errorf("upload failure: %w", err)
 		}
 	}
 
-	return nil
+	return etag, nil
 }
 
-func (client *AzBlobstore) Get(source string, dest string) error {
+// Get downloads the blob source to dest. A failed download doesn't leave a partial file behind.
+func (client *AzBlobstore) Get(ctx context.Context, source string, dest string, options common.GetOptions) error {
 	dstFile, err := os.Create(dest)
 	if err != nil {
 		return fmt.Errorf("failed to create destination file: %w", err)
 	}
+
+	err = client.download(ctx, source, dstFile, options)
+	if closeErr := dstFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(dest) //nolint:errcheck
+		return err
+	}
+	return nil
+}
+
+// download writes the blob source to dstFile and truncates the file to the size of the blob
+func (client *AzBlobstore) download(ctx context.Context, source string, dstFile *os.File, options common.GetOptions) error {
+	blobSize, err := client.storageClient.Download(ctx, source, dstFile, options)
+	if err != nil {
+		return err
+	}
+
+	info, err := dstFile.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat downloaded file: %w", err)
+	}
+	if info.Size() == blobSize {
+		return nil
+	}
+
+	slog.Debug("Truncating file to blob size", "blob_size", blobSize)
+	if err := dstFile.Truncate(blobSize); err != nil {
+		return fmt.Errorf("failed to truncate downloaded file to the blob size: %w", err)
+	}
+	if info, err = dstFile.Stat(); err != nil {
+		return fmt.Errorf("failed to stat downloaded file: %w", err)
+	}
+	if info.Size() != blobSize {
+		return fmt.Errorf("downloaded size mismatch: expected %d bytes, got %d", blobSize, info.Size())
+	}
+	return nil
+}
+
+func (client *AzBlobstore) GetRange(ctx context.Context, source string, dest string, offset int64, options common.GetOptions) error {
+	dstFile, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open destination file: %w", err)
+	}
 	defer dstFile.Close() //nolint:errcheck
 
-	return client.storageClient.Download(source, dstFile)
+	// Drop anything beyond offset so the ranged download continues right after the kept bytes
+	if err := dstFile.Truncate(offset); err != nil {
+		return fmt.Errorf("failed to truncate destination file: %w", err)
+	}
+
+	return client.storageClient.DownloadRange(ctx, source, dstFile, offset, options)
 }
 
-func (client *AzBlobstore) Delete(dest string) error {
+func (client *AzBlobstore) Delete(ctx context.Context, dest string) error {
 
-	return client.storageClient.Delete(dest)
+	return client.storageClient.Delete(ctx, dest)
 }
 
-func (client *AzBlobstore) DeleteRecursive(prefix string) error {
+func (client *AzBlobstore) DeleteRecursive(ctx context.Context, prefix string, options common.DeleteRecursiveOptions) error {
 
-	return client.storageClient.DeleteRecursive(prefix)
+	return client.storageClient.DeleteRecursive(ctx, prefix, options)
 }
 
-func (client *AzBlobstore) Exists(dest string) (bool, error) {
+func (client *AzBlobstore) Exists(ctx context.Context, dest string) (bool, error) {
 
-	return client.storageClient.Exists(dest)
+	return client.storageClient.Exists(ctx, dest)
 }
 
-func (client *AzBlobstore) Sign(dest string, action string, expiration time.Duration) (string, error) {
+func (client *AzBlobstore) Size(ctx context.Context, dest string) (int64, error) {
+
+	return client.storageClient.Size(ctx, dest)
+}
+
+// SignWithOptions creates a SAS URL that is not valid before options.StartAt. A SAS can't bind
+// the upload's headers, so a content type or MD5 is refused.
+func (client *AzBlobstore) SignWithOptions(ctx context.Context, dest string, action string, expiration time.Duration, options common.SignOptions) (string, error) {
+	if options.ContentType != "" || options.ContentMD5 != "" {
+		return "", errors.New("signing with content type or MD5 is not supported for azurebs")
+	}
+	return client.sign(ctx, dest, action, expiration, options.StartAt)
+}
+
+func (client *AzBlobstore) Sign(ctx context.Context, dest string, action string, expiration time.Duration) (string, error) {
+	return client.sign(ctx, dest, action, expiration, time.Time{})
+}
+
+// ValidateSign checks that a SAS URL for action can be created, which needs the account key,
+// without creating it
+func (client *AzBlobstore) ValidateSign(ctx context.Context, dest string, action string, expiration time.Duration, options common.SignOptions) error {
+	if options.ContentType != "" || options.ContentMD5 != "" {
+		return errors.New("signing with content type or MD5 is not supported for azurebs")
+	}
+	if client.storageClient.Identity().CredentialsSource != config.SharedKeyCredentialsSource {
+		return errors.New("signing URLs requires the account_key, it is not possible with a sas_token or the managed_identity credentials source")
+	}
+
+	action = strings.ToUpper(action)
+	if action != "GET" && action != "PUT" {
+		return fmt.Errorf("action not implemented: %s", action)
+	}
+	return nil
+}
+
+func (client *AzBlobstore) sign(ctx context.Context, dest string, action string, expiration time.Duration, startAt time.Time) (string, error) {
 	action = strings.ToUpper(action)
 	switch action {
 	case "GET", "PUT":
-		return client.storageClient.SignedUrl(action, dest, expiration)
+		return client.storageClient.SignedUrl(ctx, action, dest, expiration, startAt)
 	default:
 		return "", fmt.Errorf("action not implemented: %s", action)
 	}
@@ -126,21 +226,95 @@ func (client *AzBlobstore) getMD5(filePath string) ([]byte, error) {
 	return hash.Sum(nil), nil
 }
 
-func (client *AzBlobstore) List(prefix string) ([]string, error) {
-	return client.storageClient.List(prefix)
+func (client *AzBlobstore) List(ctx context.Context, prefix string) ([]string, error) {
+	return client.storageClient.List(ctx, prefix, 0)
 }
 
-func (client *AzBlobstore) Copy(srcBlob string, dstBlob string) error {
+// ListWithLimit lists like List, but stops paging once limit blobs have been found
+func (client *AzBlobstore) ListWithLimit(ctx context.Context, prefix string, limit int) ([]string, error) {
+	return client.storageClient.List(ctx, prefix, limit)
+}
+
+func (client *AzBlobstore) ListDetailed(ctx context.Context, prefix string) ([]common.ObjectInfo, error) {
+	return client.storageClient.ListDetailed(ctx, prefix, 0)
+}
 
-	return client.storageClient.Copy(srcBlob, dstBlob)
+// ListDetailedWithLimit lists like ListDetailed, but stops paging once limit blobs have been found
+func (client *AzBlobstore) ListDetailedWithLimit(ctx context.Context, prefix string, limit int) ([]common.ObjectInfo, error) {
+	return client.storageClient.ListDetailed(ctx, prefix, limit)
 }
 
-func (client *AzBlobstore) Properties(dest string) error {
+func (client *AzBlobstore) Copy(ctx context.Context, srcBlob string, dstBlob string, options common.CopyOptions) error {
+
+	return client.storageClient.Copy(ctx, srcBlob, dstBlob, options)
+}
+
+func (client *AzBlobstore) CopyFromBucket(ctx context.Context, srcBucket string, srcRegion string, srcBlob string, dstBlob string, options common.CopyOptions) error {
+	return errors.New("not implemented")
+}
 
-	return client.storageClient.Properties(dest)
+// CopyToBucket copies a blob of the configured container into dstContainer of the same storage account
+func (client *AzBlobstore) CopyToBucket(ctx context.Context, srcBlob string, dstContainer string, dstBlob string, options common.CopyOptions) error {
+	return client.storageClient.CopyToContainer(ctx, srcBlob, dstContainer, dstBlob, options)
 }
 
-func (client *AzBlobstore) EnsureStorageExists() error {
+func (client *AzBlobstore) PutWithManifest(ctx context.Context, sourceFilePath string, dest string, manifest common.UploadManifest, options common.PutOptions) error {
+	return errors.New("not implemented")
+}
+
+// Rename copies the blob server-side and deletes the source once the copy has completed. The blob
+// API has no rename, so unlike a native rename this isn't atomic: if the delete fails the blob is
+// left under both names
+func (client *AzBlobstore) Rename(ctx context.Context, srcBlob string, dstBlob string) error {
+	if err := client.storageClient.Copy(ctx, srcBlob, dstBlob, common.CopyOptions{}); err != nil {
+		return err
+	}
+	return client.storageClient.Delete(ctx, srcBlob)
+}
+
+func (client *AzBlobstore) Properties(ctx context.Context, dest string) (common.ObjectProperties, error) {
+
+	return client.storageClient.Properties(ctx, dest, common.PropertiesOptions{})
+}
+
+func (client *AzBlobstore) PropertiesWithOptions(ctx context.Context, dest string, options common.PropertiesOptions) (common.ObjectProperties, error) {
+	return client.storageClient.Properties(ctx, dest, options)
+}
+
+func (client *AzBlobstore) Head(ctx context.Context, dest string) (common.ObjectHead, error) {
+	return client.storageClient.Head(ctx, dest)
+}
+
+func (client *AzBlobstore) EnsureStorageExists(ctx context.Context) error {
+
+	return client.storageClient.EnsureContainerExists(ctx)
+}
+
+// Capabilities lists the operations the client supports
+func (client *AzBlobstore) Capabilities() []string {
+	return []string{
+		common.CapabilityPut,
+		common.CapabilityPutWithETag,
+		common.CapabilityGet,
+		common.CapabilityGetRange,
+		common.CapabilityDelete,
+		common.CapabilityDeleteRecursive,
+		common.CapabilityExists,
+		common.CapabilitySize,
+		common.CapabilitySign,
+		common.CapabilitySignStartAt,
+		common.CapabilityList,
+		common.CapabilityCopy,
+		common.CapabilityCopyToBucket,
+		common.CapabilityRename,
+		common.CapabilityProperties,
+		common.CapabilityHead,
+		common.CapabilityEnsureStorageExists,
+		common.CapabilityIdentity,
+	}
+}
 
-	return client.storageClient.EnsureContainerExists()
+// Identity reports the storage account and how the client authenticates to it
+func (client *AzBlobstore) Identity(ctx context.Context) (common.Identity, error) {
+	return client.storageClient.Identity(), nil
 }
@@ -2,12 +2,19 @@ package client_test
 
 import (
 	"bytes"
+	"context"
+	"crypto/md5"
 	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
+	"time"
 
 	"github.com/cloudfoundry/storage-cli/azurebs/client"
 	"github.com/cloudfoundry/storage-cli/azurebs/client/clientfakes"
+	"github.com/cloudfoundry/storage-cli/azurebs/config"
+	"github.com/cloudfoundry/storage-cli/common"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -24,13 +31,14 @@ var _ = Describe("Client", func() {
 
 			file, _ := os.CreateTemp("", "tmpfile") //nolint:errcheck
 
-			azBlobstore.Put(file.Name(), "target/blob") //nolint:errcheck
+			azBlobstore.Put(context.Background(), file.Name(), "target/blob", common.PutOptions{}) //nolint:errcheck
 
 			Expect(storageClient.UploadCallCount()).To(Equal(1))
-			source, dest := storageClient.UploadArgsForCall(0)
+			_, source, dest, sourceMD5, _ := storageClient.UploadArgsForCall(0)
 
 			Expect(source).To(BeAssignableToTypeOf((*os.File)(nil)))
 			Expect(dest).To(Equal("target/blob"))
+			Expect(fmt.Sprintf("%x", sourceMD5)).To(Equal("d41d8cd98f00b204e9800998ecf8427e"))
 		})
 
 		It("uploads a file with UploadStream", func() {
@@ -47,13 +55,15 @@ var _ = Describe("Client", func() {
 			content := bytes.Repeat([]byte("x"), contentSize)
 			_, _ = file.Write(content) //nolint:errcheck
 
-			azBlobstore.Put(file.Name(), "target/blob") //nolint:errcheck
+			azBlobstore.Put(context.Background(), file.Name(), "target/blob", common.PutOptions{}) //nolint:errcheck
 
 			Expect(storageClient.UploadStreamCallCount()).To(Equal(1))
-			source, dest := storageClient.UploadStreamArgsForCall(0)
+			_, source, dest, sourceMD5, _ := storageClient.UploadStreamArgsForCall(0)
 
 			Expect(source).To(BeAssignableToTypeOf((*os.File)(nil)))
 			Expect(dest).To(Equal("target/blob"))
+			expectedMD5 := md5.Sum(content)
+			Expect(sourceMD5).To(Equal(expectedMD5[:]))
 		})
 
 		It("skips the upload if the md5 cannot be calculated from the file", func() {
@@ -62,7 +72,7 @@ var _ = Describe("Client", func() {
 			azBlobstore, err := client.New(&storageClient)
 			Expect(err).ToNot(HaveOccurred())
 
-			err = azBlobstore.Put("the/path", "target/blob")
+			err = azBlobstore.Put(context.Background(), "the/path", "target/blob", common.PutOptions{})
 
 			Expect(storageClient.UploadCallCount()).To(Equal(0))
 			var expectedError string
@@ -76,25 +86,74 @@ var _ = Describe("Client", func() {
 
 		It("fails if the source file md5 does not match the responded md5", func() {
 			storageClient := clientfakes.FakeStorageClient{}
-			storageClient.UploadReturns([]byte{1, 2, 3}, nil)
+			storageClient.UploadReturns([]byte{1, 2, 3}, "", nil)
 
 			azBlobstore, err := client.New(&storageClient)
 			Expect(err).ToNot(HaveOccurred())
 
 			file, _ := os.CreateTemp("", "tmpfile") //nolint:errcheck
 
-			putError := azBlobstore.Put(file.Name(), "target/blob")
+			putError := azBlobstore.Put(context.Background(), file.Name(), "target/blob", common.PutOptions{})
 			Expect(putError.Error()).To(Equal("MD5 mismatch: expected d41d8cd98f00b204e9800998ecf8427e, got 010203"))
 
 			Expect(storageClient.UploadCallCount()).To(Equal(1))
-			source, dest := storageClient.UploadArgsForCall(0)
+			_, source, dest, _, _ := storageClient.UploadArgsForCall(0)
 			Expect(source).To(BeAssignableToTypeOf((*os.File)(nil)))
 			Expect(dest).To(Equal("target/blob"))
 
 			Expect(storageClient.DeleteCallCount()).To(Equal(1))
-			dest = storageClient.DeleteArgsForCall(0)
+			_, dest = storageClient.DeleteArgsForCall(0)
 			Expect(dest).To(Equal("target/blob"))
 		})
+
+		It("returns the ETag of an uploaded blob", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+			storageClient.UploadReturns([]byte{0xd4, 0x1d, 0x8c, 0xd9, 0x8f, 0x00, 0xb2, 0x04, 0xe9, 0x80, 0x09, 0x98, 0xec, 0xf8, 0x42, 0x7e}, "0x8DC1234", nil)
+
+			azBlobstore, err := client.New(&storageClient)
+			Expect(err).ToNot(HaveOccurred())
+
+			file, _ := os.CreateTemp("", "tmpfile") //nolint:errcheck
+			defer os.Remove(file.Name())            //nolint:errcheck
+
+			etag, err := azBlobstore.PutWithETag(context.Background(), file.Name(), "target/blob", common.PutOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(etag).To(Equal("0x8DC1234"))
+		})
+
+		It("uploads an empty file with a single Upload", func() {
+			emptyMD5 := md5.Sum(nil)
+			storageClient := clientfakes.FakeStorageClient{}
+			storageClient.UploadReturns(emptyMD5[:], "0x8DC1234", nil)
+
+			azBlobstore, err := client.New(&storageClient)
+			Expect(err).ToNot(HaveOccurred())
+
+			source := filepath.Join(GinkgoT().TempDir(), "empty")
+			Expect(os.WriteFile(source, nil, 0644)).To(Succeed())
+
+			Expect(azBlobstore.Put(context.Background(), source, "target/blob", common.PutOptions{})).To(Succeed())
+			Expect(storageClient.UploadCallCount()).To(Equal(1))
+			Expect(storageClient.UploadStreamCallCount()).To(Equal(0))
+			Expect(storageClient.DeleteCallCount()).To(Equal(0))
+		})
+
+		It("returns the ETag of a blob uploaded with UploadStream", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+			storageClient.UploadStreamReturns("0x8DC5678", nil)
+
+			azBlobstore, err := client.New(&storageClient)
+			Expect(err).ToNot(HaveOccurred())
+
+			file, _ := os.CreateTemp("", "tmpfile-test-upload")        //nolint:errcheck
+			defer os.Remove(file.Name())                               //nolint:errcheck
+			_, _ = file.Write(bytes.Repeat([]byte("x"), 1024*1024*64)) //nolint:errcheck
+
+			etag, err := azBlobstore.PutWithETag(context.Background(), file.Name(), "target/blob", common.PutOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(etag).To(Equal("0x8DC5678"))
+			Expect(storageClient.UploadStreamCallCount()).To(Equal(1))
+		})
 	})
 
 	It("get blob downloads to a file", func() {
@@ -106,13 +165,157 @@ var _ = Describe("Client", func() {
 		dstFileName := "tmp-dest-azurebs-get"
 		defer os.Remove("tmp-dest-azurebs-get") //nolint:errcheck
 
-		azBlobstore.Get("source/blob", dstFileName) //nolint:errcheck
+		azBlobstore.Get(context.Background(), "source/blob", dstFileName, common.GetOptions{}) //nolint:errcheck
 
 		Expect(storageClient.DownloadCallCount()).To(Equal(1))
 
-		source, dest := storageClient.DownloadArgsForCall(0)
+		_, source, dest, _ := storageClient.DownloadArgsForCall(0)
+		Expect(source).To(Equal("source/blob"))
+		Expect(dest.Name()).To(Equal(dstFileName))
+	})
+
+	Context("get", func() {
+		var (
+			storageClient *clientfakes.FakeStorageClient
+			azBlobstore   client.AzBlobstore
+			dstFileName   string
+		)
+
+		BeforeEach(func() {
+			storageClient = &clientfakes.FakeStorageClient{}
+			azBlobstore, _ = client.New(storageClient) //nolint:errcheck
+			dstFileName = filepath.Join(GinkgoT().TempDir(), "dest")
+		})
+
+		It("truncates the file to the blob size", func() {
+			storageClient.DownloadStub = func(_ context.Context, _ string, dest *os.File, _ common.GetOptions) (int64, error) {
+				_, err := dest.WriteString("0123456789")
+				return 4, err
+			}
+
+			Expect(azBlobstore.Get(context.Background(), "source/blob", dstFileName, common.GetOptions{})).To(Succeed())
+			Expect(os.ReadFile(dstFileName)).To(BeEquivalentTo("0123"))
+		})
+
+		It("fails and removes the file if it can't be truncated", func() {
+			storageClient.DownloadStub = func(_ context.Context, _ string, dest *os.File, _ common.GetOptions) (int64, error) {
+				_, err := dest.WriteString("0123456789")
+				// A negative size makes the truncate fail
+				return -1, err
+			}
+
+			err := azBlobstore.Get(context.Background(), "source/blob", dstFileName, common.GetOptions{})
+			Expect(err).To(MatchError(ContainSubstring("failed to truncate downloaded file to the blob size")))
+			Expect(dstFileName).ToNot(BeAnExistingFile())
+		})
+
+		It("removes the partial file if the download fails", func() {
+			storageClient.DownloadStub = func(_ context.Context, _ string, dest *os.File, _ common.GetOptions) (int64, error) {
+				dest.WriteString("01234") //nolint:errcheck
+				return 0, errors.New("connection reset")
+			}
+
+			err := azBlobstore.Get(context.Background(), "source/blob", dstFileName, common.GetOptions{})
+			Expect(err).To(MatchError("connection reset"))
+			Expect(dstFileName).ToNot(BeAnExistingFile())
+		})
+	})
+
+	It("get range resumes the download after the kept bytes", func() {
+		storageClient := clientfakes.FakeStorageClient{}
+
+		azBlobstore, err := client.New(&storageClient)
+		Expect(err).ToNot(HaveOccurred())
+
+		dstFileName := "tmp-dest-azurebs-get-range"
+		defer os.Remove(dstFileName) //nolint:errcheck
+		err = os.WriteFile(dstFileName, []byte("0123456789"), 0644)
+		Expect(err).ToNot(HaveOccurred())
+
+		err = azBlobstore.GetRange(context.Background(), "source/blob", dstFileName, 4, common.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(storageClient.DownloadRangeCallCount()).To(Equal(1))
+		_, source, dest, offset, _ := storageClient.DownloadRangeArgsForCall(0)
 		Expect(source).To(Equal("source/blob"))
 		Expect(dest.Name()).To(Equal(dstFileName))
+		Expect(offset).To(BeEquivalentTo(4))
+
+		content, err := os.ReadFile(dstFileName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("0123"))
+	})
+
+	It("copy passes the metadata reset through to the storage client", func() {
+		storageClient := clientfakes.FakeStorageClient{}
+
+		azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
+		err := azBlobstore.Copy(context.Background(), "old/blob", "new/blob", common.CopyOptions{ResetMetadata: true})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(storageClient.CopyCallCount()).To(Equal(1))
+		_, _, _, options := storageClient.CopyArgsForCall(0)
+		Expect(options.ResetMetadata).To(BeTrue())
+	})
+
+	It("passes an absolute source URL through to the storage client", func() {
+		storageClient := clientfakes.FakeStorageClient{}
+
+		azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
+		source := "https://other-account.blob.core.windows.net/other-container/old/blob?sig=some-signature"
+		err := azBlobstore.Copy(context.Background(), source, "new/blob", common.CopyOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, src, dst, _ := storageClient.CopyArgsForCall(0)
+		Expect(src).To(Equal(source))
+		Expect(dst).To(Equal("new/blob"))
+	})
+
+	It("copies into another container through the storage client", func() {
+		storageClient := clientfakes.FakeStorageClient{}
+
+		azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
+		err := azBlobstore.CopyToBucket(context.Background(), "old/blob", "other-container", "new/blob", common.CopyOptions{ResetMetadata: true})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(storageClient.CopyCallCount()).To(Equal(0))
+		Expect(storageClient.CopyToContainerCallCount()).To(Equal(1))
+		_, src, container, dst, options := storageClient.CopyToContainerArgsForCall(0)
+		Expect(src).To(Equal("old/blob"))
+		Expect(container).To(Equal("other-container"))
+		Expect(dst).To(Equal("new/blob"))
+		Expect(options.ResetMetadata).To(BeTrue())
+	})
+
+	Context("rename", func() {
+		It("copies the blob and deletes the source", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+
+			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
+			err := azBlobstore.Rename(context.Background(), "old/blob", "new/blob")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(storageClient.CopyCallCount()).To(Equal(1))
+			_, src, dst, options := storageClient.CopyArgsForCall(0)
+			Expect(src).To(Equal("old/blob"))
+			Expect(dst).To(Equal("new/blob"))
+			Expect(options.ResetMetadata).To(BeFalse())
+
+			Expect(storageClient.DeleteCallCount()).To(Equal(1))
+			_, deleted := storageClient.DeleteArgsForCall(0)
+			Expect(deleted).To(Equal("old/blob"))
+		})
+
+		It("keeps the source if the copy fails", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+			storageClient.CopyReturns(errors.New("boom"))
+
+			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
+			err := azBlobstore.Rename(context.Background(), "old/blob", "new/blob")
+			Expect(err).To(MatchError("boom"))
+
+			Expect(storageClient.DeleteCallCount()).To(Equal(0))
+		})
 	})
 
 	It("delete blob deletes the blob", func() {
@@ -121,10 +324,10 @@ var _ = Describe("Client", func() {
 		azBlobstore, err := client.New(&storageClient)
 		Expect(err).ToNot(HaveOccurred())
 
-		azBlobstore.Delete("blob") //nolint:errcheck
+		azBlobstore.Delete(context.Background(), "blob") //nolint:errcheck
 
 		Expect(storageClient.DeleteCallCount()).To(Equal(1))
-		dest := storageClient.DeleteArgsForCall(0)
+		_, dest := storageClient.DeleteArgsForCall(0)
 
 		Expect(dest).To(Equal("blob"))
 	})
@@ -135,11 +338,11 @@ var _ = Describe("Client", func() {
 			storageClient.ExistsReturns(true, nil)
 
 			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
-			existsState, err := azBlobstore.Exists("blob")
+			existsState, err := azBlobstore.Exists(context.Background(), "blob")
 			Expect(existsState == true).To(BeTrue())
 			Expect(err).ToNot(HaveOccurred())
 
-			dest := storageClient.ExistsArgsForCall(0)
+			_, dest := storageClient.ExistsArgsForCall(0)
 			Expect(dest).To(Equal("blob"))
 		})
 
@@ -148,11 +351,11 @@ var _ = Describe("Client", func() {
 			storageClient.ExistsReturns(false, nil)
 
 			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
-			existsState, err := azBlobstore.Exists("blob")
+			existsState, err := azBlobstore.Exists(context.Background(), "blob")
 			Expect(existsState == false).To(BeTrue())
 			Expect(err).ToNot(HaveOccurred())
 
-			dest := storageClient.ExistsArgsForCall(0)
+			_, dest := storageClient.ExistsArgsForCall(0)
 			Expect(dest).To(Equal("blob"))
 		})
 
@@ -161,11 +364,11 @@ var _ = Describe("Client", func() {
 			storageClient.ExistsReturns(false, errors.New("boom"))
 
 			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
-			existsState, err := azBlobstore.Exists("blob")
+			existsState, err := azBlobstore.Exists(context.Background(), "blob")
 			Expect(existsState == false).To(BeTrue())
 			Expect(err).To(HaveOccurred())
 
-			dest := storageClient.ExistsArgsForCall(0)
+			_, dest := storageClient.ExistsArgsForCall(0)
 			Expect(dest).To(Equal("blob"))
 		})
 	})
@@ -176,14 +379,51 @@ var _ = Describe("Client", func() {
 			storageClient.SignedUrlReturns("https://the-signed-url", nil)
 
 			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
-			url, err := azBlobstore.Sign("blob", "get", 100)
+			url, err := azBlobstore.Sign(context.Background(), "blob", "get", 100)
 			Expect(url == "https://the-signed-url").To(BeTrue())
 			Expect(err).ToNot(HaveOccurred())
 
-			action, dest, expiration := storageClient.SignedUrlArgsForCall(0)
+			_, action, dest, expiration, startAt := storageClient.SignedUrlArgsForCall(0)
 			Expect(action).To(Equal("GET"))
 			Expect(dest).To(Equal("blob"))
 			Expect(int(expiration)).To(Equal(100))
+			Expect(startAt.IsZero()).To(BeTrue())
+		})
+
+		It("passes the start time on", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+			storageClient.SignedUrlReturns("https://the-signed-url", nil)
+
+			startAt := time.Date(2030, time.January, 2, 3, 4, 5, 0, time.UTC)
+			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
+			_, err := azBlobstore.SignWithOptions(context.Background(), "blob", "put", time.Hour, common.SignOptions{StartAt: startAt})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, action, _, _, passedStartAt := storageClient.SignedUrlArgsForCall(0)
+			Expect(action).To(Equal("PUT"))
+			Expect(passedStartAt).To(Equal(startAt))
+		})
+
+		It("refuses to bind the content type", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+
+			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
+			_, err := azBlobstore.SignWithOptions(context.Background(), "blob", "put", time.Hour, common.SignOptions{ContentType: "application/gzip"})
+			Expect(err).To(MatchError(ContainSubstring("not supported for azurebs")))
+			Expect(storageClient.SignedUrlCallCount()).To(Equal(0))
+		})
+
+		It("validates that the account key is configured without signing", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+			storageClient.IdentityReturns(common.Identity{CredentialsSource: config.SharedKeyCredentialsSource})
+
+			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
+			Expect(azBlobstore.ValidateSign(context.Background(), "blob", "get", time.Hour, common.SignOptions{})).To(Succeed())
+
+			storageClient.IdentityReturns(common.Identity{CredentialsSource: config.SASTokenCredentialsSource})
+			err := azBlobstore.ValidateSign(context.Background(), "blob", "get", time.Hour, common.SignOptions{})
+			Expect(err).To(MatchError(ContainSubstring("signing URLs requires the account_key")))
+			Expect(storageClient.SignedUrlCallCount()).To(Equal(0))
 		})
 
 		It("fails on unknown action", func() {
@@ -191,7 +431,7 @@ var _ = Describe("Client", func() {
 			storageClient.SignedUrlReturns("", errors.New("boom"))
 
 			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
-			url, err := azBlobstore.Sign("blob", "unknown", 100)
+			url, err := azBlobstore.Sign(context.Background(), "blob", "unknown", 100)
 			Expect(url).To(Equal(""))
 			Expect(err).To(HaveOccurred())
 
@@ -199,17 +439,35 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Context("identity", func() {
+		It("reports the account name but not the key", func() {
+			storageClient, err := client.NewStorageClient(config.AZStorageConfig{
+				AccountName:   "some-account",
+				AccountKey:    "c29tZS1zZWNyZXQta2V5",
+				ContainerName: "some-container",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			azBlobstore, _ := client.New(storageClient) //nolint:errcheck
+			identity, err := azBlobstore.Identity(context.Background())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(identity.CredentialsSource).To(Equal("shared_key"))
+			Expect(identity.Principal).To(Equal("some-account"))
+			Expect(fmt.Sprintf("%+v", identity)).ToNot(ContainSubstring("c29tZS1zZWNyZXQta2V5"))
+		})
+	})
+
 	Context("list", func() {
 		It("lists blobs in a container", func() {
 			storageClient := clientfakes.FakeStorageClient{}
 			storageClient.ListReturns([]string{"blob1", "blob2"}, nil)
 
 			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
-			blobs, err := azBlobstore.List("")
+			blobs, err := azBlobstore.List(context.Background(), "")
 			Expect(blobs).To(Equal([]string{"blob1", "blob2"}))
 			Expect(err).ToNot(HaveOccurred())
 
-			containerName := storageClient.ListArgsForCall(0)
+			_, containerName, _ := storageClient.ListArgsForCall(0)
 			Expect(containerName).To(Equal(""))
 		})
 
@@ -218,26 +476,65 @@ var _ = Describe("Client", func() {
 			storageClient.ListReturns([]string{"pre-blob1", "pre-blob2"}, nil)
 
 			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
-			blobs, err := azBlobstore.List("pre-")
+			blobs, err := azBlobstore.List(context.Background(), "pre-")
 			Expect(blobs).To(Equal([]string{"pre-blob1", "pre-blob2"}))
 			Expect(err).ToNot(HaveOccurred())
 
-			containerName := storageClient.ListArgsForCall(0)
+			_, containerName, _ := storageClient.ListArgsForCall(0)
 			Expect(containerName).To(Equal("pre-"))
 		})
 
+		It("passes the limit on", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+			storageClient.ListReturns([]string{"blob1"}, nil)
+
+			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
+			blobs, err := azBlobstore.ListWithLimit(context.Background(), "pre-", 1)
+			Expect(blobs).To(Equal([]string{"blob1"}))
+			Expect(err).ToNot(HaveOccurred())
+
+			_, prefix, limit := storageClient.ListArgsForCall(0)
+			Expect(prefix).To(Equal("pre-"))
+			Expect(limit).To(Equal(1))
+		})
+
 		It("returns an error if listing fails", func() {
 			storageClient := clientfakes.FakeStorageClient{}
 			storageClient.ListReturns(nil, errors.New("boom"))
 
 			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
-			blobs, err := azBlobstore.List("container")
+			blobs, err := azBlobstore.List(context.Background(), "container")
 			Expect(blobs).To(BeNil())
 			Expect(err).To(HaveOccurred())
 
-			containerName := storageClient.ListArgsForCall(0)
+			_, containerName, _ := storageClient.ListArgsForCall(0)
 			Expect(containerName).To(Equal("container"))
 		})
+
+		It("lists blobs with their details", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+			details := []common.ObjectInfo{{Key: "pre-blob1", Size: 10, LastModified: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)}}
+			storageClient.ListDetailedReturns(details, nil)
+
+			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
+			blobs, err := azBlobstore.ListDetailed(context.Background(), "pre-")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blobs).To(Equal(details))
+			_, prefix, limit := storageClient.ListDetailedArgsForCall(0)
+			Expect(prefix).To(Equal("pre-"))
+			Expect(limit).To(BeZero())
+		})
+
+		It("passes the limit on for a detailed listing", func() {
+			storageClient := clientfakes.FakeStorageClient{}
+
+			azBlobstore, _ := client.New(&storageClient) //nolint:errcheck
+			_, err := azBlobstore.ListDetailedWithLimit(context.Background(), "pre-", 1)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, _, limit := storageClient.ListDetailedArgsForCall(0)
+			Expect(limit).To(Equal(1))
+		})
 	})
 
 })
@@ -2,20 +2,24 @@
 package clientfakes
 
 import (
+	"context"
 	"io"
 	"os"
 	"sync"
 	"time"
 
 	"github.com/cloudfoundry/storage-cli/azurebs/client"
+	"github.com/cloudfoundry/storage-cli/common"
 )
 
 type FakeStorageClient struct {
-	CopyStub        func(string, string) error
+	CopyStub        func(context.Context, string, string, common.CopyOptions) error
 	copyMutex       sync.RWMutex
 	copyArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
+		arg3 string
+		arg4 common.CopyOptions
 	}
 	copyReturns struct {
 		result1 error
@@ -23,10 +27,26 @@ type FakeStorageClient struct {
 	copyReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DeleteStub        func(string) error
+	CopyToContainerStub        func(context.Context, string, string, string, common.CopyOptions) error
+	copyToContainerMutex       sync.RWMutex
+	copyToContainerArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 common.CopyOptions
+	}
+	copyToContainerReturns struct {
+		result1 error
+	}
+	copyToContainerReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteStub        func(context.Context, string) error
 	deleteMutex       sync.RWMutex
 	deleteArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	deleteReturns struct {
 		result1 error
@@ -34,10 +54,12 @@ type FakeStorageClient struct {
 	deleteReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DeleteRecursiveStub        func(string) error
+	DeleteRecursiveStub        func(context.Context, string, common.DeleteRecursiveOptions) error
 	deleteRecursiveMutex       sync.RWMutex
 	deleteRecursiveArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
+		arg3 common.DeleteRecursiveOptions
 	}
 	deleteRecursiveReturns struct {
 		result1 error
@@ -45,21 +67,41 @@ type FakeStorageClient struct {
 	deleteRecursiveReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DownloadStub        func(string, *os.File) error
+	DownloadStub        func(context.Context, string, *os.File, common.GetOptions) (int64, error)
 	downloadMutex       sync.RWMutex
 	downloadArgsForCall []struct {
-		arg1 string
-		arg2 *os.File
+		arg1 context.Context
+		arg2 string
+		arg3 *os.File
+		arg4 common.GetOptions
 	}
 	downloadReturns struct {
-		result1 error
+		result1 int64
+		result2 error
 	}
 	downloadReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
+	DownloadRangeStub        func(context.Context, string, *os.File, int64, common.GetOptions) error
+	downloadRangeMutex       sync.RWMutex
+	downloadRangeArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *os.File
+		arg4 int64
+		arg5 common.GetOptions
+	}
+	downloadRangeReturns struct {
+		result1 error
+	}
+	downloadRangeReturnsOnCall map[int]struct {
 		result1 error
 	}
-	EnsureContainerExistsStub        func() error
+	EnsureContainerExistsStub        func(context.Context) error
 	ensureContainerExistsMutex       sync.RWMutex
 	ensureContainerExistsArgsForCall []struct {
+		arg1 context.Context
 	}
 	ensureContainerExistsReturns struct {
 		result1 error
@@ -67,10 +109,11 @@ type FakeStorageClient struct {
 	ensureContainerExistsReturnsOnCall map[int]struct {
 		result1 error
 	}
-	ExistsStub        func(string) (bool, error)
+	ExistsStub        func(context.Context, string) (bool, error)
 	existsMutex       sync.RWMutex
 	existsArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	existsReturns struct {
 		result1 bool
@@ -80,10 +123,36 @@ type FakeStorageClient struct {
 		result1 bool
 		result2 error
 	}
-	ListStub        func(string) ([]string, error)
+	HeadStub        func(context.Context, string) (common.ObjectHead, error)
+	headMutex       sync.RWMutex
+	headArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	headReturns struct {
+		result1 common.ObjectHead
+		result2 error
+	}
+	headReturnsOnCall map[int]struct {
+		result1 common.ObjectHead
+		result2 error
+	}
+	IdentityStub        func() common.Identity
+	identityMutex       sync.RWMutex
+	identityArgsForCall []struct {
+	}
+	identityReturns struct {
+		result1 common.Identity
+	}
+	identityReturnsOnCall map[int]struct {
+		result1 common.Identity
+	}
+	ListStub        func(context.Context, string, int) ([]string, error)
 	listMutex       sync.RWMutex
 	listArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
+		arg3 int
 	}
 	listReturns struct {
 		result1 []string
@@ -93,23 +162,44 @@ type FakeStorageClient struct {
 		result1 []string
 		result2 error
 	}
-	PropertiesStub        func(string) error
+	ListDetailedStub        func(context.Context, string, int) ([]common.ObjectInfo, error)
+	listDetailedMutex       sync.RWMutex
+	listDetailedArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+	}
+	listDetailedReturns struct {
+		result1 []common.ObjectInfo
+		result2 error
+	}
+	listDetailedReturnsOnCall map[int]struct {
+		result1 []common.ObjectInfo
+		result2 error
+	}
+	PropertiesStub        func(context.Context, string, common.PropertiesOptions) (common.ObjectProperties, error)
 	propertiesMutex       sync.RWMutex
 	propertiesArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
+		arg3 common.PropertiesOptions
 	}
 	propertiesReturns struct {
-		result1 error
+		result1 common.ObjectProperties
+		result2 error
 	}
 	propertiesReturnsOnCall map[int]struct {
-		result1 error
+		result1 common.ObjectProperties
+		result2 error
 	}
-	SignedUrlStub        func(string, string, time.Duration) (string, error)
+	SignedUrlStub        func(context.Context, string, string, time.Duration, time.Time) (string, error)
 	signedUrlMutex       sync.RWMutex
 	signedUrlArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 time.Duration
+		arg3 string
+		arg4 time.Duration
+		arg5 time.Time
 	}
 	signedUrlReturns struct {
 		result1 string
@@ -119,49 +209,75 @@ type FakeStorageClient struct {
 		result1 string
 		result2 error
 	}
-	UploadStub        func(io.ReadSeekCloser, string) ([]byte, error)
+	SizeStub        func(context.Context, string) (int64, error)
+	sizeMutex       sync.RWMutex
+	sizeArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	sizeReturns struct {
+		result1 int64
+		result2 error
+	}
+	sizeReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
+	UploadStub        func(context.Context, io.ReadSeekCloser, string, []byte, common.PutOptions) ([]byte, string, error)
 	uploadMutex       sync.RWMutex
 	uploadArgsForCall []struct {
-		arg1 io.ReadSeekCloser
-		arg2 string
+		arg1 context.Context
+		arg2 io.ReadSeekCloser
+		arg3 string
+		arg4 []byte
+		arg5 common.PutOptions
 	}
 	uploadReturns struct {
 		result1 []byte
-		result2 error
+		result2 string
+		result3 error
 	}
 	uploadReturnsOnCall map[int]struct {
 		result1 []byte
-		result2 error
+		result2 string
+		result3 error
 	}
-	UploadStreamStub        func(io.ReadSeekCloser, string) error
+	UploadStreamStub        func(context.Context, io.ReadSeekCloser, string, []byte, common.PutOptions) (string, error)
 	uploadStreamMutex       sync.RWMutex
 	uploadStreamArgsForCall []struct {
-		arg1 io.ReadSeekCloser
-		arg2 string
+		arg1 context.Context
+		arg2 io.ReadSeekCloser
+		arg3 string
+		arg4 []byte
+		arg5 common.PutOptions
 	}
 	uploadStreamReturns struct {
-		result1 error
+		result1 string
+		result2 error
 	}
 	uploadStreamReturnsOnCall map[int]struct {
-		result1 error
+		result1 string
+		result2 error
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeStorageClient) Copy(arg1 string, arg2 string) error {
+func (fake *FakeStorageClient) Copy(arg1 context.Context, arg2 string, arg3 string, arg4 common.CopyOptions) error {
 	fake.copyMutex.Lock()
 	ret, specificReturn := fake.copyReturnsOnCall[len(fake.copyArgsForCall)]
 	fake.copyArgsForCall = append(fake.copyArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-	}{arg1, arg2})
+		arg3 string
+		arg4 common.CopyOptions
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.CopyStub
 	fakeReturns := fake.copyReturns
-	fake.recordInvocation("Copy", []interface{}{arg1, arg2})
+	fake.recordInvocation("Copy", []interface{}{arg1, arg2, arg3, arg4})
 	fake.copyMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
@@ -175,17 +291,17 @@ func (fake *FakeStorageClient) CopyCallCount() int {
 	return len(fake.copyArgsForCall)
 }
 
-func (fake *FakeStorageClient) CopyCalls(stub func(string, string) error) {
+func (fake *FakeStorageClient) CopyCalls(stub func(context.Context, string, string, common.CopyOptions) error) {
 	fake.copyMutex.Lock()
 	defer fake.copyMutex.Unlock()
 	fake.CopyStub = stub
 }
 
-func (fake *FakeStorageClient) CopyArgsForCall(i int) (string, string) {
+func (fake *FakeStorageClient) CopyArgsForCall(i int) (context.Context, string, string, common.CopyOptions) {
 	fake.copyMutex.RLock()
 	defer fake.copyMutex.RUnlock()
 	argsForCall := fake.copyArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeStorageClient) CopyReturns(result1 error) {
@@ -211,18 +327,84 @@ func (fake *FakeStorageClient) CopyReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeStorageClient) Delete(arg1 string) error {
+func (fake *FakeStorageClient) CopyToContainer(arg1 context.Context, arg2 string, arg3 string, arg4 string, arg5 common.CopyOptions) error {
+	fake.copyToContainerMutex.Lock()
+	ret, specificReturn := fake.copyToContainerReturnsOnCall[len(fake.copyToContainerArgsForCall)]
+	fake.copyToContainerArgsForCall = append(fake.copyToContainerArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 common.CopyOptions
+	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.CopyToContainerStub
+	fakeReturns := fake.copyToContainerReturns
+	fake.recordInvocation("CopyToContainer", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.copyToContainerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorageClient) CopyToContainerCallCount() int {
+	fake.copyToContainerMutex.RLock()
+	defer fake.copyToContainerMutex.RUnlock()
+	return len(fake.copyToContainerArgsForCall)
+}
+
+func (fake *FakeStorageClient) CopyToContainerCalls(stub func(context.Context, string, string, string, common.CopyOptions) error) {
+	fake.copyToContainerMutex.Lock()
+	defer fake.copyToContainerMutex.Unlock()
+	fake.CopyToContainerStub = stub
+}
+
+func (fake *FakeStorageClient) CopyToContainerArgsForCall(i int) (context.Context, string, string, string, common.CopyOptions) {
+	fake.copyToContainerMutex.RLock()
+	defer fake.copyToContainerMutex.RUnlock()
+	argsForCall := fake.copyToContainerArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeStorageClient) CopyToContainerReturns(result1 error) {
+	fake.copyToContainerMutex.Lock()
+	defer fake.copyToContainerMutex.Unlock()
+	fake.CopyToContainerStub = nil
+	fake.copyToContainerReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorageClient) CopyToContainerReturnsOnCall(i int, result1 error) {
+	fake.copyToContainerMutex.Lock()
+	defer fake.copyToContainerMutex.Unlock()
+	fake.CopyToContainerStub = nil
+	if fake.copyToContainerReturnsOnCall == nil {
+		fake.copyToContainerReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.copyToContainerReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorageClient) Delete(arg1 context.Context, arg2 string) error {
 	fake.deleteMutex.Lock()
 	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
 	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.DeleteStub
 	fakeReturns := fake.deleteReturns
-	fake.recordInvocation("Delete", []interface{}{arg1})
+	fake.recordInvocation("Delete", []interface{}{arg1, arg2})
 	fake.deleteMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -236,17 +418,17 @@ func (fake *FakeStorageClient) DeleteCallCount() int {
 	return len(fake.deleteArgsForCall)
 }
 
-func (fake *FakeStorageClient) DeleteCalls(stub func(string) error) {
+func (fake *FakeStorageClient) DeleteCalls(stub func(context.Context, string) error) {
 	fake.deleteMutex.Lock()
 	defer fake.deleteMutex.Unlock()
 	fake.DeleteStub = stub
 }
 
-func (fake *FakeStorageClient) DeleteArgsForCall(i int) string {
+func (fake *FakeStorageClient) DeleteArgsForCall(i int) (context.Context, string) {
 	fake.deleteMutex.RLock()
 	defer fake.deleteMutex.RUnlock()
 	argsForCall := fake.deleteArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeStorageClient) DeleteReturns(result1 error) {
@@ -272,18 +454,20 @@ func (fake *FakeStorageClient) DeleteReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeStorageClient) DeleteRecursive(arg1 string) error {
+func (fake *FakeStorageClient) DeleteRecursive(arg1 context.Context, arg2 string, arg3 common.DeleteRecursiveOptions) error {
 	fake.deleteRecursiveMutex.Lock()
 	ret, specificReturn := fake.deleteRecursiveReturnsOnCall[len(fake.deleteRecursiveArgsForCall)]
 	fake.deleteRecursiveArgsForCall = append(fake.deleteRecursiveArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+		arg3 common.DeleteRecursiveOptions
+	}{arg1, arg2, arg3})
 	stub := fake.DeleteRecursiveStub
 	fakeReturns := fake.deleteRecursiveReturns
-	fake.recordInvocation("DeleteRecursive", []interface{}{arg1})
+	fake.recordInvocation("DeleteRecursive", []interface{}{arg1, arg2, arg3})
 	fake.deleteRecursiveMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -297,17 +481,17 @@ func (fake *FakeStorageClient) DeleteRecursiveCallCount() int {
 	return len(fake.deleteRecursiveArgsForCall)
 }
 
-func (fake *FakeStorageClient) DeleteRecursiveCalls(stub func(string) error) {
+func (fake *FakeStorageClient) DeleteRecursiveCalls(stub func(context.Context, string, common.DeleteRecursiveOptions) error) {
 	fake.deleteRecursiveMutex.Lock()
 	defer fake.deleteRecursiveMutex.Unlock()
 	fake.DeleteRecursiveStub = stub
 }
 
-func (fake *FakeStorageClient) DeleteRecursiveArgsForCall(i int) string {
+func (fake *FakeStorageClient) DeleteRecursiveArgsForCall(i int) (context.Context, string, common.DeleteRecursiveOptions) {
 	fake.deleteRecursiveMutex.RLock()
 	defer fake.deleteRecursiveMutex.RUnlock()
 	argsForCall := fake.deleteRecursiveArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeStorageClient) DeleteRecursiveReturns(result1 error) {
@@ -333,24 +517,26 @@ func (fake *FakeStorageClient) DeleteRecursiveReturnsOnCall(i int, result1 error
 	}{result1}
 }
 
-func (fake *FakeStorageClient) Download(arg1 string, arg2 *os.File) error {
+func (fake *FakeStorageClient) Download(arg1 context.Context, arg2 string, arg3 *os.File, arg4 common.GetOptions) (int64, error) {
 	fake.downloadMutex.Lock()
 	ret, specificReturn := fake.downloadReturnsOnCall[len(fake.downloadArgsForCall)]
 	fake.downloadArgsForCall = append(fake.downloadArgsForCall, struct {
-		arg1 string
-		arg2 *os.File
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 string
+		arg3 *os.File
+		arg4 common.GetOptions
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.DownloadStub
 	fakeReturns := fake.downloadReturns
-	fake.recordInvocation("Download", []interface{}{arg1, arg2})
+	fake.recordInvocation("Download", []interface{}{arg1, arg2, arg3, arg4})
 	fake.downloadMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeStorageClient) DownloadCallCount() int {
@@ -359,53 +545,122 @@ func (fake *FakeStorageClient) DownloadCallCount() int {
 	return len(fake.downloadArgsForCall)
 }
 
-func (fake *FakeStorageClient) DownloadCalls(stub func(string, *os.File) error) {
+func (fake *FakeStorageClient) DownloadCalls(stub func(context.Context, string, *os.File, common.GetOptions) (int64, error)) {
 	fake.downloadMutex.Lock()
 	defer fake.downloadMutex.Unlock()
 	fake.DownloadStub = stub
 }
 
-func (fake *FakeStorageClient) DownloadArgsForCall(i int) (string, *os.File) {
+func (fake *FakeStorageClient) DownloadArgsForCall(i int) (context.Context, string, *os.File, common.GetOptions) {
 	fake.downloadMutex.RLock()
 	defer fake.downloadMutex.RUnlock()
 	argsForCall := fake.downloadArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
-func (fake *FakeStorageClient) DownloadReturns(result1 error) {
+func (fake *FakeStorageClient) DownloadReturns(result1 int64, result2 error) {
 	fake.downloadMutex.Lock()
 	defer fake.downloadMutex.Unlock()
 	fake.DownloadStub = nil
 	fake.downloadReturns = struct {
-		result1 error
-	}{result1}
+		result1 int64
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeStorageClient) DownloadReturnsOnCall(i int, result1 error) {
+func (fake *FakeStorageClient) DownloadReturnsOnCall(i int, result1 int64, result2 error) {
 	fake.downloadMutex.Lock()
 	defer fake.downloadMutex.Unlock()
 	fake.DownloadStub = nil
 	if fake.downloadReturnsOnCall == nil {
 		fake.downloadReturnsOnCall = make(map[int]struct {
-			result1 error
+			result1 int64
+			result2 error
 		})
 	}
 	fake.downloadReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorageClient) DownloadRange(arg1 context.Context, arg2 string, arg3 *os.File, arg4 int64, arg5 common.GetOptions) error {
+	fake.downloadRangeMutex.Lock()
+	ret, specificReturn := fake.downloadRangeReturnsOnCall[len(fake.downloadRangeArgsForCall)]
+	fake.downloadRangeArgsForCall = append(fake.downloadRangeArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *os.File
+		arg4 int64
+		arg5 common.GetOptions
+	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.DownloadRangeStub
+	fakeReturns := fake.downloadRangeReturns
+	fake.recordInvocation("DownloadRange", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.downloadRangeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorageClient) DownloadRangeCallCount() int {
+	fake.downloadRangeMutex.RLock()
+	defer fake.downloadRangeMutex.RUnlock()
+	return len(fake.downloadRangeArgsForCall)
+}
+
+func (fake *FakeStorageClient) DownloadRangeCalls(stub func(context.Context, string, *os.File, int64, common.GetOptions) error) {
+	fake.downloadRangeMutex.Lock()
+	defer fake.downloadRangeMutex.Unlock()
+	fake.DownloadRangeStub = stub
+}
+
+func (fake *FakeStorageClient) DownloadRangeArgsForCall(i int) (context.Context, string, *os.File, int64, common.GetOptions) {
+	fake.downloadRangeMutex.RLock()
+	defer fake.downloadRangeMutex.RUnlock()
+	argsForCall := fake.downloadRangeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeStorageClient) DownloadRangeReturns(result1 error) {
+	fake.downloadRangeMutex.Lock()
+	defer fake.downloadRangeMutex.Unlock()
+	fake.DownloadRangeStub = nil
+	fake.downloadRangeReturns = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *FakeStorageClient) EnsureContainerExists() error {
+func (fake *FakeStorageClient) DownloadRangeReturnsOnCall(i int, result1 error) {
+	fake.downloadRangeMutex.Lock()
+	defer fake.downloadRangeMutex.Unlock()
+	fake.DownloadRangeStub = nil
+	if fake.downloadRangeReturnsOnCall == nil {
+		fake.downloadRangeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.downloadRangeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorageClient) EnsureContainerExists(arg1 context.Context) error {
 	fake.ensureContainerExistsMutex.Lock()
 	ret, specificReturn := fake.ensureContainerExistsReturnsOnCall[len(fake.ensureContainerExistsArgsForCall)]
 	fake.ensureContainerExistsArgsForCall = append(fake.ensureContainerExistsArgsForCall, struct {
-	}{})
+		arg1 context.Context
+	}{arg1})
 	stub := fake.EnsureContainerExistsStub
 	fakeReturns := fake.ensureContainerExistsReturns
-	fake.recordInvocation("EnsureContainerExists", []interface{}{})
+	fake.recordInvocation("EnsureContainerExists", []interface{}{arg1})
 	fake.ensureContainerExistsMutex.Unlock()
 	if stub != nil {
-		return stub()
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
@@ -419,12 +674,19 @@ func (fake *FakeStorageClient) EnsureContainerExistsCallCount() int {
 	return len(fake.ensureContainerExistsArgsForCall)
 }
 
-func (fake *FakeStorageClient) EnsureContainerExistsCalls(stub func() error) {
+func (fake *FakeStorageClient) EnsureContainerExistsCalls(stub func(context.Context) error) {
 	fake.ensureContainerExistsMutex.Lock()
 	defer fake.ensureContainerExistsMutex.Unlock()
 	fake.EnsureContainerExistsStub = stub
 }
 
+func (fake *FakeStorageClient) EnsureContainerExistsArgsForCall(i int) context.Context {
+	fake.ensureContainerExistsMutex.RLock()
+	defer fake.ensureContainerExistsMutex.RUnlock()
+	argsForCall := fake.ensureContainerExistsArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeStorageClient) EnsureContainerExistsReturns(result1 error) {
 	fake.ensureContainerExistsMutex.Lock()
 	defer fake.ensureContainerExistsMutex.Unlock()
@@ -448,18 +710,19 @@ func (fake *FakeStorageClient) EnsureContainerExistsReturnsOnCall(i int, result1
 	}{result1}
 }
 
-func (fake *FakeStorageClient) Exists(arg1 string) (bool, error) {
+func (fake *FakeStorageClient) Exists(arg1 context.Context, arg2 string) (bool, error) {
 	fake.existsMutex.Lock()
 	ret, specificReturn := fake.existsReturnsOnCall[len(fake.existsArgsForCall)]
 	fake.existsArgsForCall = append(fake.existsArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.ExistsStub
 	fakeReturns := fake.existsReturns
-	fake.recordInvocation("Exists", []interface{}{arg1})
+	fake.recordInvocation("Exists", []interface{}{arg1, arg2})
 	fake.existsMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -473,17 +736,17 @@ func (fake *FakeStorageClient) ExistsCallCount() int {
 	return len(fake.existsArgsForCall)
 }
 
-func (fake *FakeStorageClient) ExistsCalls(stub func(string) (bool, error)) {
+func (fake *FakeStorageClient) ExistsCalls(stub func(context.Context, string) (bool, error)) {
 	fake.existsMutex.Lock()
 	defer fake.existsMutex.Unlock()
 	fake.ExistsStub = stub
 }
 
-func (fake *FakeStorageClient) ExistsArgsForCall(i int) string {
+func (fake *FakeStorageClient) ExistsArgsForCall(i int) (context.Context, string) {
 	fake.existsMutex.RLock()
 	defer fake.existsMutex.RUnlock()
 	argsForCall := fake.existsArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeStorageClient) ExistsReturns(result1 bool, result2 error) {
@@ -512,18 +775,138 @@ func (fake *FakeStorageClient) ExistsReturnsOnCall(i int, result1 bool, result2
 	}{result1, result2}
 }
 
-func (fake *FakeStorageClient) List(arg1 string) ([]string, error) {
+func (fake *FakeStorageClient) Head(arg1 context.Context, arg2 string) (common.ObjectHead, error) {
+	fake.headMutex.Lock()
+	ret, specificReturn := fake.headReturnsOnCall[len(fake.headArgsForCall)]
+	fake.headArgsForCall = append(fake.headArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.HeadStub
+	fakeReturns := fake.headReturns
+	fake.recordInvocation("Head", []interface{}{arg1, arg2})
+	fake.headMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStorageClient) HeadCallCount() int {
+	fake.headMutex.RLock()
+	defer fake.headMutex.RUnlock()
+	return len(fake.headArgsForCall)
+}
+
+func (fake *FakeStorageClient) HeadCalls(stub func(context.Context, string) (common.ObjectHead, error)) {
+	fake.headMutex.Lock()
+	defer fake.headMutex.Unlock()
+	fake.HeadStub = stub
+}
+
+func (fake *FakeStorageClient) HeadArgsForCall(i int) (context.Context, string) {
+	fake.headMutex.RLock()
+	defer fake.headMutex.RUnlock()
+	argsForCall := fake.headArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStorageClient) HeadReturns(result1 common.ObjectHead, result2 error) {
+	fake.headMutex.Lock()
+	defer fake.headMutex.Unlock()
+	fake.HeadStub = nil
+	fake.headReturns = struct {
+		result1 common.ObjectHead
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorageClient) HeadReturnsOnCall(i int, result1 common.ObjectHead, result2 error) {
+	fake.headMutex.Lock()
+	defer fake.headMutex.Unlock()
+	fake.HeadStub = nil
+	if fake.headReturnsOnCall == nil {
+		fake.headReturnsOnCall = make(map[int]struct {
+			result1 common.ObjectHead
+			result2 error
+		})
+	}
+	fake.headReturnsOnCall[i] = struct {
+		result1 common.ObjectHead
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorageClient) Identity() common.Identity {
+	fake.identityMutex.Lock()
+	ret, specificReturn := fake.identityReturnsOnCall[len(fake.identityArgsForCall)]
+	fake.identityArgsForCall = append(fake.identityArgsForCall, struct {
+	}{})
+	stub := fake.IdentityStub
+	fakeReturns := fake.identityReturns
+	fake.recordInvocation("Identity", []interface{}{})
+	fake.identityMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorageClient) IdentityCallCount() int {
+	fake.identityMutex.RLock()
+	defer fake.identityMutex.RUnlock()
+	return len(fake.identityArgsForCall)
+}
+
+func (fake *FakeStorageClient) IdentityCalls(stub func() common.Identity) {
+	fake.identityMutex.Lock()
+	defer fake.identityMutex.Unlock()
+	fake.IdentityStub = stub
+}
+
+func (fake *FakeStorageClient) IdentityReturns(result1 common.Identity) {
+	fake.identityMutex.Lock()
+	defer fake.identityMutex.Unlock()
+	fake.IdentityStub = nil
+	fake.identityReturns = struct {
+		result1 common.Identity
+	}{result1}
+}
+
+func (fake *FakeStorageClient) IdentityReturnsOnCall(i int, result1 common.Identity) {
+	fake.identityMutex.Lock()
+	defer fake.identityMutex.Unlock()
+	fake.IdentityStub = nil
+	if fake.identityReturnsOnCall == nil {
+		fake.identityReturnsOnCall = make(map[int]struct {
+			result1 common.Identity
+		})
+	}
+	fake.identityReturnsOnCall[i] = struct {
+		result1 common.Identity
+	}{result1}
+}
+
+func (fake *FakeStorageClient) List(arg1 context.Context, arg2 string, arg3 int) ([]string, error) {
 	fake.listMutex.Lock()
 	ret, specificReturn := fake.listReturnsOnCall[len(fake.listArgsForCall)]
 	fake.listArgsForCall = append(fake.listArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+		arg3 int
+	}{arg1, arg2, arg3})
 	stub := fake.ListStub
 	fakeReturns := fake.listReturns
-	fake.recordInvocation("List", []interface{}{arg1})
+	fake.recordInvocation("List", []interface{}{arg1, arg2, arg3})
 	fake.listMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -537,17 +920,17 @@ func (fake *FakeStorageClient) ListCallCount() int {
 	return len(fake.listArgsForCall)
 }
 
-func (fake *FakeStorageClient) ListCalls(stub func(string) ([]string, error)) {
+func (fake *FakeStorageClient) ListCalls(stub func(context.Context, string, int) ([]string, error)) {
 	fake.listMutex.Lock()
 	defer fake.listMutex.Unlock()
 	fake.ListStub = stub
 }
 
-func (fake *FakeStorageClient) ListArgsForCall(i int) string {
+func (fake *FakeStorageClient) ListArgsForCall(i int) (context.Context, string, int) {
 	fake.listMutex.RLock()
 	defer fake.listMutex.RUnlock()
 	argsForCall := fake.listArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeStorageClient) ListReturns(result1 []string, result2 error) {
@@ -576,23 +959,91 @@ func (fake *FakeStorageClient) ListReturnsOnCall(i int, result1 []string, result
 	}{result1, result2}
 }
 
-func (fake *FakeStorageClient) Properties(arg1 string) error {
+func (fake *FakeStorageClient) ListDetailed(arg1 context.Context, arg2 string, arg3 int) ([]common.ObjectInfo, error) {
+	fake.listDetailedMutex.Lock()
+	ret, specificReturn := fake.listDetailedReturnsOnCall[len(fake.listDetailedArgsForCall)]
+	fake.listDetailedArgsForCall = append(fake.listDetailedArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+	}{arg1, arg2, arg3})
+	stub := fake.ListDetailedStub
+	fakeReturns := fake.listDetailedReturns
+	fake.recordInvocation("ListDetailed", []interface{}{arg1, arg2, arg3})
+	fake.listDetailedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStorageClient) ListDetailedCallCount() int {
+	fake.listDetailedMutex.RLock()
+	defer fake.listDetailedMutex.RUnlock()
+	return len(fake.listDetailedArgsForCall)
+}
+
+func (fake *FakeStorageClient) ListDetailedCalls(stub func(context.Context, string, int) ([]common.ObjectInfo, error)) {
+	fake.listDetailedMutex.Lock()
+	defer fake.listDetailedMutex.Unlock()
+	fake.ListDetailedStub = stub
+}
+
+func (fake *FakeStorageClient) ListDetailedArgsForCall(i int) (context.Context, string, int) {
+	fake.listDetailedMutex.RLock()
+	defer fake.listDetailedMutex.RUnlock()
+	argsForCall := fake.listDetailedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeStorageClient) ListDetailedReturns(result1 []common.ObjectInfo, result2 error) {
+	fake.listDetailedMutex.Lock()
+	defer fake.listDetailedMutex.Unlock()
+	fake.ListDetailedStub = nil
+	fake.listDetailedReturns = struct {
+		result1 []common.ObjectInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorageClient) ListDetailedReturnsOnCall(i int, result1 []common.ObjectInfo, result2 error) {
+	fake.listDetailedMutex.Lock()
+	defer fake.listDetailedMutex.Unlock()
+	fake.ListDetailedStub = nil
+	if fake.listDetailedReturnsOnCall == nil {
+		fake.listDetailedReturnsOnCall = make(map[int]struct {
+			result1 []common.ObjectInfo
+			result2 error
+		})
+	}
+	fake.listDetailedReturnsOnCall[i] = struct {
+		result1 []common.ObjectInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorageClient) Properties(arg1 context.Context, arg2 string, arg3 common.PropertiesOptions) (common.ObjectProperties, error) {
 	fake.propertiesMutex.Lock()
 	ret, specificReturn := fake.propertiesReturnsOnCall[len(fake.propertiesArgsForCall)]
 	fake.propertiesArgsForCall = append(fake.propertiesArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+		arg3 common.PropertiesOptions
+	}{arg1, arg2, arg3})
 	stub := fake.PropertiesStub
 	fakeReturns := fake.propertiesReturns
-	fake.recordInvocation("Properties", []interface{}{arg1})
+	fake.recordInvocation("Properties", []interface{}{arg1, arg2, arg3})
 	fake.propertiesMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeStorageClient) PropertiesCallCount() int {
@@ -601,56 +1052,61 @@ func (fake *FakeStorageClient) PropertiesCallCount() int {
 	return len(fake.propertiesArgsForCall)
 }
 
-func (fake *FakeStorageClient) PropertiesCalls(stub func(string) error) {
+func (fake *FakeStorageClient) PropertiesCalls(stub func(context.Context, string, common.PropertiesOptions) (common.ObjectProperties, error)) {
 	fake.propertiesMutex.Lock()
 	defer fake.propertiesMutex.Unlock()
 	fake.PropertiesStub = stub
 }
 
-func (fake *FakeStorageClient) PropertiesArgsForCall(i int) string {
+func (fake *FakeStorageClient) PropertiesArgsForCall(i int) (context.Context, string, common.PropertiesOptions) {
 	fake.propertiesMutex.RLock()
 	defer fake.propertiesMutex.RUnlock()
 	argsForCall := fake.propertiesArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *FakeStorageClient) PropertiesReturns(result1 error) {
+func (fake *FakeStorageClient) PropertiesReturns(result1 common.ObjectProperties, result2 error) {
 	fake.propertiesMutex.Lock()
 	defer fake.propertiesMutex.Unlock()
 	fake.PropertiesStub = nil
 	fake.propertiesReturns = struct {
-		result1 error
-	}{result1}
+		result1 common.ObjectProperties
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeStorageClient) PropertiesReturnsOnCall(i int, result1 error) {
+func (fake *FakeStorageClient) PropertiesReturnsOnCall(i int, result1 common.ObjectProperties, result2 error) {
 	fake.propertiesMutex.Lock()
 	defer fake.propertiesMutex.Unlock()
 	fake.PropertiesStub = nil
 	if fake.propertiesReturnsOnCall == nil {
 		fake.propertiesReturnsOnCall = make(map[int]struct {
-			result1 error
+			result1 common.ObjectProperties
+			result2 error
 		})
 	}
 	fake.propertiesReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
+		result1 common.ObjectProperties
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeStorageClient) SignedUrl(arg1 string, arg2 string, arg3 time.Duration) (string, error) {
+func (fake *FakeStorageClient) SignedUrl(arg1 context.Context, arg2 string, arg3 string, arg4 time.Duration, arg5 time.Time) (string, error) {
 	fake.signedUrlMutex.Lock()
 	ret, specificReturn := fake.signedUrlReturnsOnCall[len(fake.signedUrlArgsForCall)]
 	fake.signedUrlArgsForCall = append(fake.signedUrlArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 time.Duration
-	}{arg1, arg2, arg3})
+		arg3 string
+		arg4 time.Duration
+		arg5 time.Time
+	}{arg1, arg2, arg3, arg4, arg5})
 	stub := fake.SignedUrlStub
 	fakeReturns := fake.signedUrlReturns
-	fake.recordInvocation("SignedUrl", []interface{}{arg1, arg2, arg3})
+	fake.recordInvocation("SignedUrl", []interface{}{arg1, arg2, arg3, arg4, arg5})
 	fake.signedUrlMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -664,17 +1120,17 @@ func (fake *FakeStorageClient) SignedUrlCallCount() int {
 	return len(fake.signedUrlArgsForCall)
 }
 
-func (fake *FakeStorageClient) SignedUrlCalls(stub func(string, string, time.Duration) (string, error)) {
+func (fake *FakeStorageClient) SignedUrlCalls(stub func(context.Context, string, string, time.Duration, time.Time) (string, error)) {
 	fake.signedUrlMutex.Lock()
 	defer fake.signedUrlMutex.Unlock()
 	fake.SignedUrlStub = stub
 }
 
-func (fake *FakeStorageClient) SignedUrlArgsForCall(i int) (string, string, time.Duration) {
+func (fake *FakeStorageClient) SignedUrlArgsForCall(i int) (context.Context, string, string, time.Duration, time.Time) {
 	fake.signedUrlMutex.RLock()
 	defer fake.signedUrlMutex.RUnlock()
 	argsForCall := fake.signedUrlArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *FakeStorageClient) SignedUrlReturns(result1 string, result2 error) {
@@ -703,24 +1159,97 @@ func (fake *FakeStorageClient) SignedUrlReturnsOnCall(i int, result1 string, res
 	}{result1, result2}
 }
 
-func (fake *FakeStorageClient) Upload(arg1 io.ReadSeekCloser, arg2 string) ([]byte, error) {
+func (fake *FakeStorageClient) Size(arg1 context.Context, arg2 string) (int64, error) {
+	fake.sizeMutex.Lock()
+	ret, specificReturn := fake.sizeReturnsOnCall[len(fake.sizeArgsForCall)]
+	fake.sizeArgsForCall = append(fake.sizeArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.SizeStub
+	fakeReturns := fake.sizeReturns
+	fake.recordInvocation("Size", []interface{}{arg1, arg2})
+	fake.sizeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStorageClient) SizeCallCount() int {
+	fake.sizeMutex.RLock()
+	defer fake.sizeMutex.RUnlock()
+	return len(fake.sizeArgsForCall)
+}
+
+func (fake *FakeStorageClient) SizeCalls(stub func(context.Context, string) (int64, error)) {
+	fake.sizeMutex.Lock()
+	defer fake.sizeMutex.Unlock()
+	fake.SizeStub = stub
+}
+
+func (fake *FakeStorageClient) SizeArgsForCall(i int) (context.Context, string) {
+	fake.sizeMutex.RLock()
+	defer fake.sizeMutex.RUnlock()
+	argsForCall := fake.sizeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStorageClient) SizeReturns(result1 int64, result2 error) {
+	fake.sizeMutex.Lock()
+	defer fake.sizeMutex.Unlock()
+	fake.SizeStub = nil
+	fake.sizeReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorageClient) SizeReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.sizeMutex.Lock()
+	defer fake.sizeMutex.Unlock()
+	fake.SizeStub = nil
+	if fake.sizeReturnsOnCall == nil {
+		fake.sizeReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
+		})
+	}
+	fake.sizeReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorageClient) Upload(arg1 context.Context, arg2 io.ReadSeekCloser, arg3 string, arg4 []byte, arg5 common.PutOptions) ([]byte, string, error) {
+	var arg4Copy []byte
+	if arg4 != nil {
+		arg4Copy = make([]byte, len(arg4))
+		copy(arg4Copy, arg4)
+	}
 	fake.uploadMutex.Lock()
 	ret, specificReturn := fake.uploadReturnsOnCall[len(fake.uploadArgsForCall)]
 	fake.uploadArgsForCall = append(fake.uploadArgsForCall, struct {
-		arg1 io.ReadSeekCloser
-		arg2 string
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 io.ReadSeekCloser
+		arg3 string
+		arg4 []byte
+		arg5 common.PutOptions
+	}{arg1, arg2, arg3, arg4Copy, arg5})
 	stub := fake.UploadStub
 	fakeReturns := fake.uploadReturns
-	fake.recordInvocation("Upload", []interface{}{arg1, arg2})
+	fake.recordInvocation("Upload", []interface{}{arg1, arg2, arg3, arg4Copy, arg5})
 	fake.uploadMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1, ret.result2, ret.result3
 	}
-	return fakeReturns.result1, fakeReturns.result2
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
 func (fake *FakeStorageClient) UploadCallCount() int {
@@ -729,63 +1258,74 @@ func (fake *FakeStorageClient) UploadCallCount() int {
 	return len(fake.uploadArgsForCall)
 }
 
-func (fake *FakeStorageClient) UploadCalls(stub func(io.ReadSeekCloser, string) ([]byte, error)) {
+func (fake *FakeStorageClient) UploadCalls(stub func(context.Context, io.ReadSeekCloser, string, []byte, common.PutOptions) ([]byte, string, error)) {
 	fake.uploadMutex.Lock()
 	defer fake.uploadMutex.Unlock()
 	fake.UploadStub = stub
 }
 
-func (fake *FakeStorageClient) UploadArgsForCall(i int) (io.ReadSeekCloser, string) {
+func (fake *FakeStorageClient) UploadArgsForCall(i int) (context.Context, io.ReadSeekCloser, string, []byte, common.PutOptions) {
 	fake.uploadMutex.RLock()
 	defer fake.uploadMutex.RUnlock()
 	argsForCall := fake.uploadArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
-func (fake *FakeStorageClient) UploadReturns(result1 []byte, result2 error) {
+func (fake *FakeStorageClient) UploadReturns(result1 []byte, result2 string, result3 error) {
 	fake.uploadMutex.Lock()
 	defer fake.uploadMutex.Unlock()
 	fake.UploadStub = nil
 	fake.uploadReturns = struct {
 		result1 []byte
-		result2 error
-	}{result1, result2}
+		result2 string
+		result3 error
+	}{result1, result2, result3}
 }
 
-func (fake *FakeStorageClient) UploadReturnsOnCall(i int, result1 []byte, result2 error) {
+func (fake *FakeStorageClient) UploadReturnsOnCall(i int, result1 []byte, result2 string, result3 error) {
 	fake.uploadMutex.Lock()
 	defer fake.uploadMutex.Unlock()
 	fake.UploadStub = nil
 	if fake.uploadReturnsOnCall == nil {
 		fake.uploadReturnsOnCall = make(map[int]struct {
 			result1 []byte
-			result2 error
+			result2 string
+			result3 error
 		})
 	}
 	fake.uploadReturnsOnCall[i] = struct {
 		result1 []byte
-		result2 error
-	}{result1, result2}
+		result2 string
+		result3 error
+	}{result1, result2, result3}
 }
 
-func (fake *FakeStorageClient) UploadStream(arg1 io.ReadSeekCloser, arg2 string) error {
+func (fake *FakeStorageClient) UploadStream(arg1 context.Context, arg2 io.ReadSeekCloser, arg3 string, arg4 []byte, arg5 common.PutOptions) (string, error) {
+	var arg4Copy []byte
+	if arg4 != nil {
+		arg4Copy = make([]byte, len(arg4))
+		copy(arg4Copy, arg4)
+	}
 	fake.uploadStreamMutex.Lock()
 	ret, specificReturn := fake.uploadStreamReturnsOnCall[len(fake.uploadStreamArgsForCall)]
 	fake.uploadStreamArgsForCall = append(fake.uploadStreamArgsForCall, struct {
-		arg1 io.ReadSeekCloser
-		arg2 string
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 io.ReadSeekCloser
+		arg3 string
+		arg4 []byte
+		arg5 common.PutOptions
+	}{arg1, arg2, arg3, arg4Copy, arg5})
 	stub := fake.UploadStreamStub
 	fakeReturns := fake.uploadStreamReturns
-	fake.recordInvocation("UploadStream", []interface{}{arg1, arg2})
+	fake.recordInvocation("UploadStream", []interface{}{arg1, arg2, arg3, arg4Copy, arg5})
 	fake.uploadStreamMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeStorageClient) UploadStreamCallCount() int {
@@ -794,40 +1334,43 @@ func (fake *FakeStorageClient) UploadStreamCallCount() int {
 	return len(fake.uploadStreamArgsForCall)
 }
 
-func (fake *FakeStorageClient) UploadStreamCalls(stub func(io.ReadSeekCloser, string) error) {
+func (fake *FakeStorageClient) UploadStreamCalls(stub func(context.Context, io.ReadSeekCloser, string, []byte, common.PutOptions) (string, error)) {
 	fake.uploadStreamMutex.Lock()
 	defer fake.uploadStreamMutex.Unlock()
 	fake.UploadStreamStub = stub
 }
 
-func (fake *FakeStorageClient) UploadStreamArgsForCall(i int) (io.ReadSeekCloser, string) {
+func (fake *FakeStorageClient) UploadStreamArgsForCall(i int) (context.Context, io.ReadSeekCloser, string, []byte, common.PutOptions) {
 	fake.uploadStreamMutex.RLock()
 	defer fake.uploadStreamMutex.RUnlock()
 	argsForCall := fake.uploadStreamArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
-func (fake *FakeStorageClient) UploadStreamReturns(result1 error) {
+func (fake *FakeStorageClient) UploadStreamReturns(result1 string, result2 error) {
 	fake.uploadStreamMutex.Lock()
 	defer fake.uploadStreamMutex.Unlock()
 	fake.UploadStreamStub = nil
 	fake.uploadStreamReturns = struct {
-		result1 error
-	}{result1}
+		result1 string
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeStorageClient) UploadStreamReturnsOnCall(i int, result1 error) {
+func (fake *FakeStorageClient) UploadStreamReturnsOnCall(i int, result1 string, result2 error) {
 	fake.uploadStreamMutex.Lock()
 	defer fake.uploadStreamMutex.Unlock()
 	fake.UploadStreamStub = nil
 	if fake.uploadStreamReturnsOnCall == nil {
 		fake.uploadStreamReturnsOnCall = make(map[int]struct {
-			result1 error
+			result1 string
+			result2 error
 		})
 	}
 	fake.uploadStreamReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
+		result1 string
+		result2 error
+	}{result1, result2}
 }
 
 func (fake *FakeStorageClient) Invocations() map[string][][]interface{} {
@@ -2,17 +2,24 @@ package client
 
 import (
 	"context"
-	"encoding/json"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"net/http"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
@@ -22,65 +29,111 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
 
 	"github.com/cloudfoundry/storage-cli/azurebs/config"
+	"github.com/cloudfoundry/storage-cli/common"
 )
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . StorageClient
 type StorageClient interface {
 	Upload(
+		ctx context.Context,
 		source io.ReadSeekCloser,
 		dest string,
-	) ([]byte, error)
+		sourceMD5 []byte,
+		options common.PutOptions,
+	) (contentMD5 []byte, etag string, err error)
 
 	UploadStream(
+		ctx context.Context,
 		source io.ReadSeekCloser,
 		dest string,
-	) error
+		sourceMD5 []byte,
+		options common.PutOptions,
+	) (etag string, err error)
 
 	Download(
+		ctx context.Context,
+		source string,
+		dest *os.File,
+		options common.GetOptions,
+	) (blobSize int64, err error)
+
+	DownloadRange(
+		ctx context.Context,
 		source string,
 		dest *os.File,
+		offset int64,
+		options common.GetOptions,
 	) error
 
 	Copy(
+		ctx context.Context,
+		srcBlob string,
+		destBlob string,
+		options common.CopyOptions,
+	) error
+
+	CopyToContainer(
+		ctx context.Context,
 		srcBlob string,
+		destContainer string,
 		destBlob string,
+		options common.CopyOptions,
 	) error
 
 	Delete(
+		ctx context.Context,
 		dest string,
 	) error
 
 	DeleteRecursive(
-		dest string,
+		ctx context.Context,
+		prefix string,
+		options common.DeleteRecursiveOptions,
 	) error
 
 	Exists(
+		ctx context.Context,
 		dest string,
 	) (bool, error)
 
+	Size(
+		ctx context.Context,
+		dest string,
+	) (int64, error)
+
 	SignedUrl(
+		ctx context.Context,
 		requestType string,
 		dest string,
 		expiration time.Duration,
+		startAt time.Time,
 	) (string, error)
 
 	List(
+		ctx context.Context,
 		prefix string,
+		limit int,
 	) ([]string, error)
+	ListDetailed(
+		ctx context.Context,
+		prefix string,
+		limit int,
+	) ([]common.ObjectInfo, error)
 	Properties(
+		ctx context.Context,
 		dest string,
-	) error
-	EnsureContainerExists() error
-}
-
-// 4 MB of block size
-const blockSize = int64(4 * 1024 * 1024)
+		options common.PropertiesOptions,
+	) (common.ObjectProperties, error)
+	Head(
+		ctx context.Context,
+		dest string,
+	) (common.ObjectHead, error)
+	EnsureContainerExists(ctx context.Context) error
 
-// number of go routines
-const maxConcurrency = 5
+	Identity() common.Identity
+}
 
-func createContext(dsc DefaultStorageClient) (context.Context, context.CancelFunc, error) {
-	var ctx context.Context
+func createContext(ctx context.Context, dsc DefaultStorageClient) (context.Context, context.CancelFunc, error) {
 	var cancel context.CancelFunc
 
 	if dsc.storageConfig.Timeout != "" {
@@ -94,9 +147,9 @@ func createContext(dsc DefaultStorageClient) (context.Context, context.CancelFun
 			slog.Info("Invalid timeout format, need seconds as number e.g. 30", "timeout", dsc.storageConfig.Timeout)
 			return nil, nil, fmt.Errorf("invalid timeout format: %w", err)
 		}
-		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+		ctx, cancel = context.WithTimeout(ctx, timeout)
 	} else {
-		ctx, cancel = context.WithCancel(context.Background())
+		ctx, cancel = context.WithCancel(ctx)
 	}
 
 	return ctx, cancel, nil
@@ -104,26 +157,108 @@ func createContext(dsc DefaultStorageClient) (context.Context, context.CancelFun
 }
 
 type DefaultStorageClient struct {
-	credential    *azblob.SharedKeyCredential
-	serviceURL    string
-	storageConfig config.AZStorageConfig
+	credential      *azblob.SharedKeyCredential
+	tokenCredential azcore.TokenCredential
+	serviceURL      string
+	storageConfig   config.AZStorageConfig
 }
 
+// NewStorageClient authenticates according to the credentials source: with a token from Azure AD
+// for managed_identity and otherwise with the SAS token if one is configured or the account key
 func NewStorageClient(storageConfig config.AZStorageConfig) (StorageClient, error) {
-	credential, err := azblob.NewSharedKeyCredential(storageConfig.AccountName, storageConfig.AccountKey)
-	if err != nil {
-		return nil, err
+	dsc := DefaultStorageClient{storageConfig: storageConfig}
+	switch storageConfig.CredentialsSource {
+	case config.ManagedIdentityCredentialsSource:
+		tokenCredential, err := azidentity.NewDefaultAzureCredential(nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create managed identity credential: %w", err)
+		}
+		dsc.tokenCredential = tokenCredential
+	case config.StaticCredentialsSource, "":
+		if storageConfig.SASToken == "" {
+			credential, err := azblob.NewSharedKeyCredential(storageConfig.AccountName, storageConfig.AccountKey)
+			if err != nil {
+				return nil, err
+			}
+			dsc.credential = credential
+		}
+	default:
+		return nil, errors.New("unknown credentials_source in configuration")
+	}
+
+	dsc.serviceURL = dsc.containerURL(storageConfig.ContainerName)
+	return dsc, nil
+}
+
+// blockBlobClient returns a client for the blob at blobURL authenticated the configured way
+func (dsc DefaultStorageClient) blockBlobClient(blobURL string) (*blockblob.Client, error) {
+	return dsc.limitedBlockBlobClient(blobURL, nil)
+}
+
+// limitedBlockBlobClient returns a client for the block blob at blobURL that sends each request,
+// every staged block of an upload on its own, in a slot of limiter
+func (dsc DefaultStorageClient) limitedBlockBlobClient(blobURL string, limiter *common.ConcurrencyLimiter) (*blockblob.Client, error) {
+	var options *blockblob.ClientOptions
+	if limiter != nil {
+		options = &blockblob.ClientOptions{}
+		options.PerCallPolicies = []policy.Policy{limiterPolicy{limiter: limiter}}
 	}
 
-	serviceURL := fmt.Sprintf("https://%s.%s/%s", storageConfig.AccountName, storageConfig.StorageEndpoint(), storageConfig.ContainerName)
+	switch {
+	case dsc.tokenCredential != nil:
+		return blockblob.NewClient(blobURL, dsc.tokenCredential, options)
+	case dsc.credential != nil:
+		return blockblob.NewClientWithSharedKeyCredential(blobURL, dsc.credential, options)
+	default:
+		return blockblob.NewClientWithNoCredential(dsc.withSASToken(blobURL), options)
+	}
+}
+
+// limiterPolicy holds a slot of limiter while a request, retries included, is sent
+type limiterPolicy struct {
+	limiter *common.ConcurrencyLimiter
+}
 
-	return DefaultStorageClient{credential: credential, serviceURL: serviceURL, storageConfig: storageConfig}, nil
+func (p limiterPolicy) Do(req *policy.Request) (*http.Response, error) {
+	p.limiter.Acquire()
+	defer p.limiter.Release()
+	return req.Next()
 }
 
+// containerClient returns a client for the container at containerURL authenticated the configured way
+func (dsc DefaultStorageClient) containerClient(containerURL string) (*azContainer.Client, error) {
+	switch {
+	case dsc.tokenCredential != nil:
+		return azContainer.NewClient(containerURL, dsc.tokenCredential, nil)
+	case dsc.credential != nil:
+		return azContainer.NewClientWithSharedKeyCredential(containerURL, dsc.credential, nil)
+	default:
+		return azContainer.NewClientWithNoCredential(dsc.withSASToken(containerURL), nil)
+	}
+}
+
+// withSASToken appends the configured SAS token to resourceURL as its query
+func (dsc DefaultStorageClient) withSASToken(resourceURL string) string {
+	if dsc.storageConfig.SASToken == "" {
+		return resourceURL
+	}
+	return resourceURL + "?" + dsc.storageConfig.SASToken
+}
+
+// containerURL returns the URL of the named container in the configured storage account
+func (dsc DefaultStorageClient) containerURL(container string) string {
+	return fmt.Sprintf("https://%s.%s/%s", dsc.storageConfig.AccountName, dsc.storageConfig.StorageEndpoint(), container)
+}
+
+// Upload puts source into a single block blob. sourceMD5 is sent as the transactional MD5, so the
+// service rejects a body that got corrupted on the way, and is stored as the blob's Content-MD5.
 func (dsc DefaultStorageClient) Upload(
+	ctx context.Context,
 	source io.ReadSeekCloser,
 	dest string,
-) ([]byte, error) {
+	sourceMD5 []byte,
+	options common.PutOptions,
+) ([]byte, string, error) {
 	blobURL := fmt.Sprintf("%s/%s", dsc.serviceURL, dest)
 
 	if dsc.storageConfig.Timeout != "" {
@@ -132,33 +267,75 @@ func (dsc DefaultStorageClient) Upload(
 		slog.Info("Uploading blob to container", "container", dsc.storageConfig.ContainerName, "blob", dest, "url", blobURL)
 	}
 
-	ctx, cancel, err := createContext(dsc)
+	ctx, cancel, err := createContext(ctx, dsc)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer cancel()
 
-	client, err := blockblob.NewClientWithSharedKeyCredential(blobURL, dsc.credential, nil)
+	client, err := dsc.limitedBlockBlobClient(blobURL, options.Concurrency)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	uploadResponse, err := client.Upload(ctx, source, nil)
+	uploadResponse, err := client.Upload(ctx, source, &blockblob.UploadOptions{
+		TransactionalValidation: azBlob.TransferValidationTypeMD5(sourceMD5),
+		HTTPHeaders:             uploadHeaders(sourceMD5, options),
+		Metadata:                uploadMetadata(options),
+		Tier:                    dsc.accessTier(),
+	})
 	if err != nil {
 		if dsc.storageConfig.Timeout != "" && errors.Is(err, context.DeadlineExceeded) {
-			return nil, fmt.Errorf("upload failed: timeout of %s reached while uploading %s", dsc.storageConfig.Timeout, dest)
+			return nil, "", fmt.Errorf("upload failed: timeout of %s reached while uploading %s", dsc.storageConfig.Timeout, dest)
 		}
-		return nil, fmt.Errorf("upload failure: %w", err)
+		return nil, "", fmt.Errorf("upload failure: %w", err)
 	}
 
 	slog.Info("Successfully uploaded blob", "container", dsc.storageConfig.ContainerName, "blob", dest)
-	return uploadResponse.ContentMD5, nil
+	return uploadResponse.ContentMD5, etagString(uploadResponse.ETag), nil
+}
+
+// uploadHeaders are the blob headers stored with an upload: the Content-MD5 and, when one is set,
+// the Content-Type
+func uploadHeaders(sourceMD5 []byte, options common.PutOptions) *azBlob.HTTPHeaders {
+	headers := &azBlob.HTTPHeaders{BlobContentMD5: sourceMD5}
+	if options.ContentType != "" {
+		headers.BlobContentType = &options.ContentType
+	}
+	return headers
 }
 
+// uploadMetadata is the user metadata stored with an upload
+func uploadMetadata(options common.PutOptions) map[string]*string {
+	if len(options.Metadata) == 0 {
+		return nil
+	}
+	blobMetadata := make(map[string]*string, len(options.Metadata))
+	for key, value := range options.Metadata {
+		blobMetadata[key] = &value
+	}
+	return blobMetadata
+}
+
+// accessTier is the configured tier uploads are stored in, or nil for the account's default tier
+func (dsc DefaultStorageClient) accessTier() *azBlob.AccessTier {
+	if dsc.storageConfig.AccessTier == "" {
+		return nil
+	}
+	tier := azBlob.AccessTier(dsc.storageConfig.AccessTier)
+	return &tier
+}
+
+// UploadStream puts source into a block blob in blocks of the configured size, as many of them in
+// parallel as configured. Every block is verified with a CRC64 on upload and sourceMD5 is stored
+// as the Content-MD5 of the committed blob.
 func (dsc DefaultStorageClient) UploadStream(
+	ctx context.Context,
 	source io.ReadSeekCloser,
 	dest string,
-) error {
+	sourceMD5 []byte,
+	options common.PutOptions,
+) (string, error) {
 	blobURL := fmt.Sprintf("%s/%s", dsc.serviceURL, dest)
 
 	if dsc.storageConfig.Timeout != "" {
@@ -167,71 +344,247 @@ func (dsc DefaultStorageClient) UploadStream(
 		slog.Info("UploadStreaming blob to container", "container", dsc.storageConfig.ContainerName, "blob", dest, "url", blobURL)
 	}
 
-	ctx, cancel, err := createContext(dsc)
+	ctx, cancel, err := createContext(ctx, dsc)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer cancel()
 
-	client, err := blockblob.NewClientWithSharedKeyCredential(blobURL, dsc.credential, nil)
+	client, err := dsc.limitedBlockBlobClient(blobURL, options.Concurrency)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	_, err = client.UploadStream(ctx, source, &azblob.UploadStreamOptions{BlockSize: blockSize, Concurrency: maxConcurrency})
+	uploadResponse, err := client.UploadStream(ctx, source, &azblob.UploadStreamOptions{
+		BlockSize:               dsc.storageConfig.UploadBlockSize(),
+		Concurrency:             dsc.storageConfig.UploadMaxConcurrency(),
+		TransactionalValidation: azBlob.TransferValidationTypeComputeCRC64(),
+		HTTPHeaders:             uploadHeaders(sourceMD5, options),
+		Metadata:                uploadMetadata(options),
+		AccessTier:              dsc.accessTier(),
+	})
 	if err != nil {
 		if dsc.storageConfig.Timeout != "" && errors.Is(err, context.DeadlineExceeded) {
-			return fmt.Errorf("upload failed: timeout of %s reached while uploading %s", dsc.storageConfig.Timeout, dest)
+			return "", fmt.Errorf("upload failed: timeout of %s reached while uploading %s", dsc.storageConfig.Timeout, dest)
 		}
-		return fmt.Errorf("upload failure: %w", err)
+		return "", fmt.Errorf("upload failure: %w", err)
 	}
 
 	slog.Info("Successfully uploaded blob", "container", dsc.storageConfig.ContainerName, "blob", dest)
-	return nil
+	return etagString(uploadResponse.ETag), nil
+}
+
+// etagString returns etag without the surrounding quotes, or an empty string if the service didn't report one
+func etagString(etag *azcore.ETag) string {
+	if etag == nil {
+		return ""
+	}
+	return strings.Trim(string(*etag), `"`)
 }
 
+// Download writes the blob source to dest and returns the size of the blob. dest may end up
+// larger than the blob, e.g. when it was not empty, it is up to the caller to truncate it.
 func (dsc DefaultStorageClient) Download(
+	ctx context.Context,
 	source string,
 	dest *os.File,
-) error {
+	options common.GetOptions,
+) (int64, error) {
 	blobURL := fmt.Sprintf("%s/%s", dsc.serviceURL, source)
 	slog.Info("Downloading blob from container", "container", dsc.storageConfig.ContainerName, "blob", source, "local_file", dest.Name())
-	client, err := blockblob.NewClientWithSharedKeyCredential(blobURL, dsc.credential, nil)
+	client, err := dsc.blockBlobClient(blobURL)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	blobSize, err := client.DownloadFile(context.Background(), dest, nil) //nolint:ineffassign,staticcheck
+	// DownloadFile writes to the file directly, so a bandwidth limit or hashing the download needs the stream instead
+	if options.Bandwidth != nil || options.Checksum != nil {
+		resp, err := client.DownloadStream(ctx, nil)
+		if err != nil {
+			return 0, err
+		}
+		body := resp.NewRetryReader(ctx, nil)
+		defer body.Close() //nolint:errcheck
+
+		written, err := io.Copy(common.NewThrottledWriter(ctx, options.Bandwidth, common.NewChecksummingWriter(options.Checksum, dest)), body)
+		if err != nil {
+			return 0, err
+		}
+		if resp.ContentLength != nil && *resp.ContentLength != written {
+			return 0, fmt.Errorf("downloaded size mismatch: expected %d bytes, got %d", *resp.ContentLength, written)
+		}
+		return written, nil
+	}
+
+	return client.DownloadFile(ctx, dest, nil)
+}
+
+func (dsc DefaultStorageClient) DownloadRange(
+	ctx context.Context,
+	source string,
+	dest *os.File,
+	offset int64,
+	options common.GetOptions,
+) error {
+	blobURL := fmt.Sprintf("%s/%s", dsc.serviceURL, source)
+	slog.Info("Resuming download of blob from container", "container", dsc.storageConfig.ContainerName, "blob", source, "local_file", dest.Name(), "offset", offset)
+	client, err := dsc.blockBlobClient(blobURL)
 	if err != nil {
 		return err
 	}
-	info, err := dest.Stat()
+
+	var accessConditions *azBlob.AccessConditions
+	if options.IfMatch != "" {
+		etag := azcore.ETag(`"` + strings.Trim(options.IfMatch, `"`) + `"`)
+		accessConditions = &azBlob.AccessConditions{ModifiedAccessConditions: &azBlob.ModifiedAccessConditions{IfMatch: &etag}}
+	}
+
+	props, err := client.GetProperties(ctx, &azBlob.GetPropertiesOptions{AccessConditions: accessConditions})
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to get properties: %w", preconditionError(err))
 	}
-	if blobSize != info.Size() {
-		slog.Debug("Truncating file to blob size", "blob_size", blobSize)
-		dest.Truncate(blobSize) //nolint:errcheck
+	if props.ContentLength == nil {
+		return fmt.Errorf("unable to determine content length of blob %s", source)
+	}
+
+	size := *props.ContentLength
+	if offset > size {
+		return fmt.Errorf("local file is larger (%d bytes) than the blob (%d bytes)", offset, size)
+	}
+	if offset == size {
+		slog.Info("Blob already fully downloaded", "container", dsc.storageConfig.ContainerName, "blob", source, "size", size)
+		return nil
+	}
+
+	resp, err := client.DownloadStream(ctx, &azBlob.DownloadStreamOptions{
+		Range:            azBlob.HTTPRange{Offset: offset},
+		AccessConditions: accessConditions,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to download blob range: %w", preconditionError(err))
+	}
+	body := resp.NewRetryReader(ctx, nil)
+	defer body.Close() //nolint:errcheck
+
+	written, err := io.Copy(io.NewOffsetWriter(common.NewThrottledWriter(ctx, options.Bandwidth, dest), offset), body)
+	if err != nil {
+		return fmt.Errorf("failed to write blob range: %w", err)
+	}
+	if offset+written != size {
+		return fmt.Errorf("downloaded size mismatch: expected %d bytes, got %d", size, offset+written)
 	}
 
 	return nil
 }
 
 func (dsc DefaultStorageClient) Copy(
+	ctx context.Context,
+	srcBlob string,
+	destBlob string,
+	options common.CopyOptions,
+) error {
+	slog.Info("Copying blob into container", "container", dsc.storageConfig.ContainerName, "source_blob", withoutQuery(srcBlob), "dest_blob", destBlob)
+
+	srcURL, err := dsc.CopySourceURL(srcBlob, options.SourceSAS)
+	if err != nil {
+		return err
+	}
+	return dsc.copyBlob(ctx, srcURL, fmt.Sprintf("%s/%s", dsc.serviceURL, destBlob), options)
+}
+
+// CopySourceURL returns the URL a copy of srcBlob reads from. srcBlob is either the name of a blob
+// in the configured container or the absolute URL of a blob in any container or storage account,
+// which is used as is and so has to carry its own SAS token unless the blob is public. A non-empty
+// sourceSAS is appended to the source URL instead, e.g. to read from another storage account.
+func (dsc DefaultStorageClient) CopySourceURL(srcBlob string, sourceSAS string) (string, error) {
+	sourceSAS = strings.TrimPrefix(sourceSAS, "?")
+	if sourceSAS != "" {
+		if query, err := url.ParseQuery(sourceSAS); err != nil || query.Get("sig") == "" {
+			return "", errors.New("invalid source SAS token: expected a query string with a signature (sig)")
+		}
+	}
+
+	if !isBlobURL(srcBlob) {
+		if sourceSAS == "" {
+			// Unlike the shared key, a SAS token only authorizes reading the source if it is part of its URL
+			return dsc.withSASToken(fmt.Sprintf("%s/%s", dsc.serviceURL, srcBlob)), nil
+		}
+		return fmt.Sprintf("%s/%s?%s", dsc.serviceURL, srcBlob, sourceSAS), nil
+	}
+
+	if sourceSAS == "" {
+		return srcBlob, nil
+	}
+	parsed, err := url.Parse(srcBlob)
+	if err != nil {
+		return "", fmt.Errorf("invalid source URL: %w", err)
+	}
+	if parsed.Scheme != "https" {
+		return "", errors.New("invalid source URL: a source SAS token is only sent over https")
+	}
+	if parsed.RawQuery != "" {
+		return "", errors.New("invalid source URL: it already has a query, it can't be combined with a source SAS token")
+	}
+	parsed.RawQuery = sourceSAS
+	return parsed.String(), nil
+}
+
+// isBlobURL reports whether name is an absolute http(s) URL rather than a blob name
+func isBlobURL(name string) bool {
+	parsed, err := url.Parse(name)
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "https" || parsed.Scheme == "http") && parsed.Host != ""
+}
+
+// CopyToContainer copies a blob of the configured container into another container of the same
+// storage account. The destination container is looked up first, so that a missing container or
+// credentials without access to it are reported before the copy is started.
+func (dsc DefaultStorageClient) CopyToContainer(
+	ctx context.Context,
 	srcBlob string,
+	destContainer string,
 	destBlob string,
+	options common.CopyOptions,
 ) error {
-	slog.Info("Copying blob within container", "container", dsc.storageConfig.ContainerName, "source_blob", srcBlob, "dest_blob", destBlob)
+	slog.Info("Copying blob to another container", "container", dsc.storageConfig.ContainerName, "source_blob", withoutQuery(srcBlob), "dest_container", destContainer, "dest_blob", destBlob)
 
-	srcURL := fmt.Sprintf("%s/%s", dsc.serviceURL, srcBlob)
-	destURL := fmt.Sprintf("%s/%s", dsc.serviceURL, destBlob)
+	destContainerURL := dsc.containerURL(destContainer)
+	containerClient, err := dsc.containerClient(destContainerURL)
+	if err != nil {
+		return fmt.Errorf("failed to create destination container client: %w", err)
+	}
+	if _, err := containerClient.GetProperties(ctx, nil); err != nil {
+		var respErr *azcore.ResponseError
+		if errors.As(err, &respErr) && respErr.StatusCode == http.StatusForbidden {
+			return fmt.Errorf("%w: no access to destination container %s: %w", common.ErrAccessDenied, destContainer, err)
+		}
+		return fmt.Errorf("failed to access destination container %s: %w", destContainer, err)
+	}
 
-	destClient, err := blockblob.NewClientWithSharedKeyCredential(destURL, dsc.credential, nil)
+	srcURL, err := dsc.CopySourceURL(srcBlob, options.SourceSAS)
+	if err != nil {
+		return err
+	}
+	return dsc.copyBlob(ctx, srcURL, fmt.Sprintf("%s/%s", destContainerURL, destBlob), options)
+}
+
+// withoutQuery returns resourceURL without its query, which may hold a SAS token that must not be logged
+func withoutQuery(resourceURL string) string {
+	resource, _, _ := strings.Cut(resourceURL, "?")
+	return resource
+}
+
+// copyBlob starts a server-side copy from srcURL, as returned by CopySourceURL, to destURL and
+// waits until it completed
+func (dsc DefaultStorageClient) copyBlob(ctx context.Context, srcURL string, destURL string, options common.CopyOptions) error {
+	destClient, err := dsc.blockBlobClient(destURL)
 	if err != nil {
 		return fmt.Errorf("failed to create destination client: %w", err)
 	}
 
-	resp, err := destClient.StartCopyFromURL(context.Background(), srcURL, nil)
+	resp, err := destClient.StartCopyFromURL(ctx, srcURL, nil)
 	if err != nil {
 		return fmt.Errorf("failed to start copy: %w", err)
 	}
@@ -241,7 +594,7 @@ func (dsc DefaultStorageClient) Copy(
 
 	// Wait for completion
 	for {
-		props, err := destClient.GetProperties(context.Background(), nil)
+		props, err := destClient.GetProperties(ctx, nil)
 		if err != nil {
 			return fmt.Errorf("failed to get properties: %w", err)
 		}
@@ -251,7 +604,13 @@ func (dsc DefaultStorageClient) Copy(
 
 		switch copyStatus {
 		case "success":
-			slog.Info("Copy completed successfully", "container", dsc.storageConfig.ContainerName, "source_blob", srcBlob, "dest_blob", destBlob)
+			slog.Info("Copy completed successfully", "source_url", withoutQuery(srcURL), "dest_url", destURL)
+			// A copy started without metadata takes over the source's, so it is cleared explicitly afterwards
+			if options.ResetMetadata && len(props.Metadata) > 0 {
+				if _, err := destClient.SetMetadata(ctx, map[string]*string{}, nil); err != nil {
+					return fmt.Errorf("failed to reset metadata: %w", err)
+				}
+			}
 			return nil
 		case "pending":
 			time.Sleep(200 * time.Millisecond)
@@ -262,18 +621,19 @@ func (dsc DefaultStorageClient) Copy(
 }
 
 func (dsc DefaultStorageClient) Delete(
+	ctx context.Context,
 	dest string,
 ) error {
 
 	blobURL := fmt.Sprintf("%s/%s", dsc.serviceURL, dest)
 
 	slog.Info("Deleting blob from container", "container", dsc.storageConfig.ContainerName, "blob", dest, "url", blobURL)
-	client, err := blockblob.NewClientWithSharedKeyCredential(blobURL, dsc.credential, nil)
+	client, err := dsc.blockBlobClient(blobURL)
 	if err != nil {
 		return err
 	}
 
-	_, err = client.Delete(context.Background(), nil)
+	_, err = client.Delete(ctx, nil)
 
 	if err == nil {
 		return nil
@@ -287,7 +647,9 @@ func (dsc DefaultStorageClient) Delete(
 }
 
 func (dsc DefaultStorageClient) DeleteRecursive(
+	ctx context.Context,
 	prefix string,
+	deleteOptions common.DeleteRecursiveOptions,
 ) error {
 	if prefix != "" {
 		slog.Info("Deleting all blobs in container", "container", dsc.storageConfig.ContainerName, "prefix", prefix)
@@ -295,7 +657,7 @@ func (dsc DefaultStorageClient) DeleteRecursive(
 		slog.Info("Deleting all blobs in container", "container", dsc.storageConfig.ContainerName)
 	}
 
-	containerClient, err := azContainer.NewClientWithSharedKeyCredential(dsc.serviceURL, dsc.credential, nil)
+	containerClient, err := dsc.containerClient(dsc.serviceURL)
 	if err != nil {
 		return fmt.Errorf("failed to create container client: %w", err)
 	}
@@ -307,43 +669,105 @@ func (dsc DefaultStorageClient) DeleteRecursive(
 
 	pager := containerClient.NewListBlobsFlatPager(options)
 
-	for pager.More() {
-		resp, err := pager.NextPage(context.Background())
+	// Batches are submitted deleteOptions.Concurrency at a time. Without deleteOptions.ContinueOnError
+	// no further batches are started after the first failure.
+	var (
+		mu     sync.Mutex
+		errs   []error
+		failed atomic.Bool
+		wg     sync.WaitGroup
+	)
+	limiter := common.NewConcurrencyLimiter(deleteOptions.ConcurrencyOr(1))
+	for pager.More() && (deleteOptions.ContinueOnError || !failed.Load()) {
+		resp, err := pager.NextPage(ctx)
 		if err != nil {
-			return fmt.Errorf("error retrieving page of blobs: %w", err)
+			mu.Lock()
+			errs = append(errs, fmt.Errorf("error retrieving page of blobs: %w", err))
+			mu.Unlock()
+			break
 		}
 
+		names := make([]string, 0, len(resp.Segment.BlobItems))
 		for _, blob := range resp.Segment.BlobItems {
-			blobURL := fmt.Sprintf("%s/%s", dsc.serviceURL, *blob.Name)
-			blobClient, err := blockblob.NewClientWithSharedKeyCredential(blobURL, dsc.credential, nil)
-			if err != nil {
-				slog.Error("Failed to create blob client", "blob", *blob.Name, "error", err)
-				continue
-			}
+			names = append(names, *blob.Name)
+		}
+		for len(names) > 0 && (deleteOptions.ContinueOnError || !failed.Load()) {
+			batch := names[:min(len(names), maxBatchDeletes)]
+			names = names[len(batch):]
+
+			limiter.Acquire()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer limiter.Release()
+
+				if batchErrs := dsc.deleteBatch(ctx, containerClient, batch); len(batchErrs) > 0 {
+					failed.Store(true)
+					mu.Lock()
+					errs = append(errs, batchErrs...)
+					mu.Unlock()
+				}
+			}()
+		}
+	}
 
-			_, err = blobClient.BlobClient().Delete(context.Background(), nil)
-			if err != nil && !strings.Contains(err.Error(), "RESPONSE 404") {
-				slog.Error("Failed to delete blob", "blob", *blob.Name, "error", err)
-			}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// maxBatchDeletes is the Azure limit for the number of sub-requests in a single Blob Batch request
+const maxBatchDeletes = 256
+
+// deleteBatch deletes the named blobs with a single Blob Batch request and returns an error for
+// every blob that could not be deleted. Blobs that are already gone count as deleted.
+func (dsc DefaultStorageClient) deleteBatch(ctx context.Context, containerClient *azContainer.Client, names []string) []error {
+	slog.Info("Deleting batch of blobs", "container", dsc.storageConfig.ContainerName, "count", len(names))
+
+	batch, err := containerClient.NewBatchBuilder()
+	if err != nil {
+		return []error{fmt.Errorf("failed to create batch: %w", err)}
+	}
+	for _, name := range names {
+		if err := batch.Delete(name, nil); err != nil {
+			return []error{fmt.Errorf("failed to add blob %s to batch: %w", name, err)}
 		}
 	}
 
-	return nil
+	resp, err := containerClient.SubmitBatch(ctx, batch, nil)
+	if err != nil {
+		slog.Error("Failed to submit batch delete", "count", len(names), "error", err)
+		return []error{fmt.Errorf("failed to delete batch of %d blobs: %w", len(names), err)}
+	}
+
+	var errs []error
+	for _, item := range resp.Responses {
+		if item.Error == nil || strings.Contains(item.Error.Error(), "RESPONSE 404") {
+			continue
+		}
+		var name string
+		if item.BlobName != nil {
+			name = *item.BlobName
+		}
+		slog.Error("Failed to delete blob", "blob", name, "error", item.Error)
+		errs = append(errs, fmt.Errorf("failed to delete blob %s: %w", name, item.Error))
+	}
+	return errs
 }
 
 func (dsc DefaultStorageClient) Exists(
+	ctx context.Context,
 	dest string,
 ) (bool, error) {
 
 	blobURL := fmt.Sprintf("%s/%s", dsc.serviceURL, dest)
 
 	slog.Info("Checking if blob exists", "container", dsc.storageConfig.ContainerName, "blob", dest, "url", blobURL)
-	client, err := blockblob.NewClientWithSharedKeyCredential(blobURL, dsc.credential, nil)
+	client, err := dsc.blockBlobClient(blobURL)
 	if err != nil {
 		return false, err
 	}
 
-	_, err = client.BlobClient().GetProperties(context.Background(), nil)
+	_, err = client.BlobClient().GetProperties(ctx, nil)
 	if err == nil {
 		slog.Info("Blob exists in container", "container", dsc.storageConfig.ContainerName, "blob", dest)
 		return true, nil
@@ -353,16 +777,51 @@ func (dsc DefaultStorageClient) Exists(
 		return false, nil
 	}
 
+	var respErr *azcore.ResponseError
+	if errors.As(err, &respErr) && respErr.StatusCode == http.StatusForbidden {
+		return false, fmt.Errorf("%w: %w", common.ErrAccessDenied, err)
+	}
+
 	return false, err
 }
 
+func (dsc DefaultStorageClient) Size(
+	ctx context.Context,
+	dest string,
+) (int64, error) {
+
+	blobURL := fmt.Sprintf("%s/%s", dsc.serviceURL, dest)
+
+	slog.Info("Getting blob size", "container", dsc.storageConfig.ContainerName, "blob", dest, "url", blobURL)
+	client, err := dsc.blockBlobClient(blobURL)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := client.BlobClient().GetProperties(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get properties for blob %s: %w", dest, err)
+	}
+	if resp.ContentLength == nil {
+		return 0, nil
+	}
+	return *resp.ContentLength, nil
+}
+
 func (dsc DefaultStorageClient) SignedUrl(
+	ctx context.Context,
 	requestType string,
 	dest string,
 	expiration time.Duration,
+	startAt time.Time,
 ) (string, error) {
 
-	blobURL := fmt.Sprintf("%s/%s", dsc.serviceURL, dest)
+	if dsc.credential == nil {
+		return "", errors.New("signing URLs requires the account_key, it is not possible with a sas_token or the managed_identity credentials source")
+	}
+
+	// The SAS URL is handed out as is, so the blob name has to be escaped in it
+	blobURL := fmt.Sprintf("%s/%s", dsc.serviceURL, escapeBlobName(dest))
 
 	slog.Info("Generating SAS URL for blob", "container", dsc.storageConfig.ContainerName, "blob", dest, "request_type", requestType, "expiration", expiration)
 	client, err := azBlob.NewClientWithSharedKeyCredential(blobURL, dsc.credential, nil)
@@ -370,26 +829,47 @@ func (dsc DefaultStorageClient) SignedUrl(
 		return "", err
 	}
 
-	url, err := client.GetSASURL(sas.BlobPermissions{Read: true, Create: true}, time.Now().Add(expiration), nil)
+	// A SAS with a start time is not valid before it, the expiration counts from then
+	var sasOptions *azBlob.GetSASURLOptions
+	expiry := time.Now().Add(expiration)
+	if !startAt.IsZero() {
+		sasOptions = &azBlob.GetSASURLOptions{StartTime: &startAt}
+		expiry = startAt.Add(expiration)
+	}
+
+	url, err := client.GetSASURL(sas.BlobPermissions{Read: true, Create: true}, expiry, sasOptions)
 	if err != nil {
 		return "", err
 	}
 
 	// There could be occasional issues with the Azure Storage Account when requests hitting
 	// the server are not responded to, and then BOSH hangs while expecting a reply from the server.
-	// That's why we implement a server-side timeout here (30 mins for GET and 45 mins for PUT)
+	// That's why we implement a server-side timeout here (by default 30 mins for GET and 45 mins for PUT)
 	// (see: https://learn.microsoft.com/en-us/rest/api/storageservices/setting-timeouts-for-blob-service-operations)
 	if requestType == "GET" {
-		url += "&timeout=1800"
+		url += fmt.Sprintf("&timeout=%d", dsc.storageConfig.SignedURLGetTimeout())
 	} else {
-		url += "&timeout=2700"
+		url += fmt.Sprintf("&timeout=%d", dsc.storageConfig.SignedURLPutTimeout())
 	}
 
 	return url, err
 }
 
+// escapeBlobName escapes every segment of a blob name for use in a URL path, keeping the slashes
+// between virtual directories. Plus signs are escaped too, so they can't be mistaken for spaces.
+func escapeBlobName(name string) string {
+	segments := strings.Split(name, "/")
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(url.PathEscape(segment), "+", "%2B")
+	}
+	return strings.Join(segments, "/")
+}
+
+// List lists the blobs starting with prefix, at most limit of them unless limit is zero or negative
 func (dsc DefaultStorageClient) List(
+	ctx context.Context,
 	prefix string,
+	limit int,
 ) ([]string, error) {
 
 	if prefix != "" {
@@ -398,7 +878,7 @@ func (dsc DefaultStorageClient) List(
 		slog.Info("Listing blobs in container", "container", dsc.storageConfig.ContainerName)
 	}
 
-	client, err := azContainer.NewClientWithSharedKeyCredential(dsc.serviceURL, dsc.credential, nil)
+	client, err := dsc.containerClient(dsc.serviceURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create container client: %w", err)
 	}
@@ -407,74 +887,196 @@ func (dsc DefaultStorageClient) List(
 	if prefix != "" {
 		options.Prefix = &prefix
 	}
+	if limit > 0 {
+		// Don't fetch more than needed, a page holds up to 5000 blobs
+		options.MaxResults = to.Ptr(int32(min(limit, 5000)))
+	}
 
 	pager := client.NewListBlobsFlatPager(options)
 	var blobs []string
 
 	for pager.More() {
-		resp, err := pager.NextPage(context.Background())
+		resp, err := pager.NextPage(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("error retrieving page of blobs: %w", err)
 		}
 
 		for _, blob := range resp.Segment.BlobItems {
 			blobs = append(blobs, *blob.Name)
+			if len(blobs) == limit {
+				return blobs, nil
+			}
 		}
 	}
 
 	return blobs, nil
 }
 
-type BlobProperties struct {
-	ETag          string    `json:"etag,omitempty"`
-	LastModified  time.Time `json:"last_modified,omitempty"`
-	ContentLength int64     `json:"content_length,omitempty"`
+// ListDetailed lists like List, along with the size and last modification time of each blob
+func (dsc DefaultStorageClient) ListDetailed(
+	ctx context.Context,
+	prefix string,
+	limit int,
+) ([]common.ObjectInfo, error) {
+	slog.Info("Listing blobs with details in container", "container", dsc.storageConfig.ContainerName, "prefix", prefix)
+
+	client, err := dsc.containerClient(dsc.serviceURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create container client: %w", err)
+	}
+
+	options := &azContainer.ListBlobsFlatOptions{}
+	if prefix != "" {
+		options.Prefix = &prefix
+	}
+	if limit > 0 {
+		// Don't fetch more than needed, a page holds up to 5000 blobs
+		options.MaxResults = to.Ptr(int32(min(limit, 5000)))
+	}
+
+	pager := client.NewListBlobsFlatPager(options)
+	var blobs []common.ObjectInfo
+
+	for pager.More() {
+		resp, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving page of blobs: %w", err)
+		}
+
+		for _, blob := range resp.Segment.BlobItems {
+			info := common.ObjectInfo{Key: *blob.Name}
+			if blob.Properties != nil {
+				if blob.Properties.ContentLength != nil {
+					info.Size = *blob.Properties.ContentLength
+				}
+				if blob.Properties.LastModified != nil {
+					info.LastModified = *blob.Properties.LastModified
+				}
+			}
+			blobs = append(blobs, info)
+			if len(blobs) == limit {
+				return blobs, nil
+			}
+		}
+	}
+
+	return blobs, nil
 }
 
+// Properties returns the properties of dest, or common.ErrObjectNotFound if it doesn't exist
 func (dsc DefaultStorageClient) Properties(
+	ctx context.Context,
 	dest string,
-) error {
+	options common.PropertiesOptions,
+) (common.ObjectProperties, error) {
 	blobURL := fmt.Sprintf("%s/%s", dsc.serviceURL, dest)
 
 	slog.Info("Getting properties for blob", "container", dsc.storageConfig.ContainerName, "blob", dest, "url", blobURL)
-	client, err := blockblob.NewClientWithSharedKeyCredential(blobURL, dsc.credential, nil)
+	client, err := dsc.blockBlobClient(blobURL)
 	if err != nil {
-		return err
+		return common.ObjectProperties{}, err
 	}
 
-	resp, err := client.GetProperties(context.Background(), nil)
+	resp, err := client.GetProperties(ctx, nil)
 	if err != nil {
 		if strings.Contains(err.Error(), "RESPONSE 404") {
-			fmt.Println(`{}`)
-			return nil
+			return common.ObjectProperties{}, common.ErrObjectNotFound
 		}
-		return fmt.Errorf("failed to get properties for blob %s: %w", dest, err)
+		return common.ObjectProperties{}, fmt.Errorf("failed to get properties for blob %s: %w", dest, err)
+	}
+	if resp.ContentLength == nil {
+		return common.ObjectProperties{}, fmt.Errorf("unable to determine content length of blob %s", dest)
 	}
 
-	props := BlobProperties{
-		ETag:          strings.Trim(string(*resp.ETag), `"`),
+	var accessTier string
+	if resp.AccessTier != nil {
+		accessTier = *resp.AccessTier
+	}
+	return common.ObjectProperties{
+		ETag:          options.ETag(string(*resp.ETag)),
 		LastModified:  *resp.LastModified,
 		ContentLength: *resp.ContentLength,
+		ContentMD5:    base64.StdEncoding.EncodeToString(resp.ContentMD5),
+		AccessTier:    accessTier,
+		Metadata:      blobMetadata(resp.Metadata),
+	}, nil
+}
+
+// Head returns the full properties response for dest, or common.ErrObjectNotFound if it doesn't exist
+func (dsc DefaultStorageClient) Head(ctx context.Context, dest string) (common.ObjectHead, error) {
+	blobURL := fmt.Sprintf("%s/%s", dsc.serviceURL, dest)
+
+	slog.Info("Getting head of blob", "container", dsc.storageConfig.ContainerName, "blob", dest, "url", blobURL)
+	client, err := dsc.blockBlobClient(blobURL)
+	if err != nil {
+		return common.ObjectHead{}, err
 	}
 
-	output, err := json.MarshalIndent(props, "", "  ")
+	resp, err := client.GetProperties(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to marshal blob properties: %w", err)
+		if strings.Contains(err.Error(), "RESPONSE 404") {
+			return common.ObjectHead{}, common.ErrObjectNotFound
+		}
+		return common.ObjectHead{}, fmt.Errorf("failed to get properties for blob %s: %w", dest, err)
+	}
+	if resp.ContentLength == nil {
+		return common.ObjectHead{}, fmt.Errorf("unable to determine content length of blob %s", dest)
 	}
 
-	fmt.Println(string(output))
-	return nil
+	head := common.ObjectHead{
+		ETag:               etagString(resp.ETag),
+		LastModified:       *resp.LastModified,
+		ContentLength:      *resp.ContentLength,
+		ContentType:        valueOf(resp.ContentType),
+		ContentEncoding:    valueOf(resp.ContentEncoding),
+		ContentDisposition: valueOf(resp.ContentDisposition),
+		ContentLanguage:    valueOf(resp.ContentLanguage),
+		CacheControl:       valueOf(resp.CacheControl),
+		StorageClass:       valueOf(resp.AccessTier),
+		VersionID:          valueOf(resp.VersionID),
+		Metadata:           blobMetadata(resp.Metadata),
+	}
+	if len(resp.ContentMD5) > 0 {
+		head.ContentMD5 = base64.StdEncoding.EncodeToString(resp.ContentMD5)
+	}
+	if resp.EncryptionScope != nil || resp.EncryptionKeySHA256 != nil {
+		head.Encryption = &common.ObjectEncryption{
+			KeyID:             valueOf(resp.EncryptionScope),
+			CustomerKeySHA256: valueOf(resp.EncryptionKeySHA256),
+		}
+	}
+	return head, nil
 }
 
-func (dsc DefaultStorageClient) EnsureContainerExists() error {
+// blobMetadata flattens the metadata of a blob properties response
+func blobMetadata(metadata map[string]*string) map[string]string {
+	if len(metadata) == 0 {
+		return nil
+	}
+	flattened := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		flattened[key] = valueOf(value)
+	}
+	return flattened
+}
+
+// valueOf returns the string s points to, or "" for nil
+func valueOf(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func (dsc DefaultStorageClient) EnsureContainerExists(ctx context.Context) error {
 	slog.Info("Ensuring container exists", "container", dsc.storageConfig.ContainerName)
 
-	containerClient, err := azContainer.NewClientWithSharedKeyCredential(dsc.serviceURL, dsc.credential, nil)
+	containerClient, err := dsc.containerClient(dsc.serviceURL)
 	if err != nil {
 		return fmt.Errorf("failed to create container client: %w", err)
 	}
 
-	_, err = containerClient.Create(context.Background(), nil)
+	_, err = containerClient.Create(ctx, nil)
 	if err != nil {
 		var respErr *azcore.ResponseError
 		if errors.As(err, &respErr) && respErr.ErrorCode == string(bloberror.ContainerAlreadyExists) {
@@ -487,3 +1089,23 @@ func (dsc DefaultStorageClient) EnsureContainerExists() error {
 	slog.Info("Container created successfully", "container", dsc.storageConfig.ContainerName)
 	return nil
 }
+
+// Identity reports the credentials source and the storage account, a key or token itself is never included
+func (dsc DefaultStorageClient) Identity() common.Identity {
+	if dsc.tokenCredential != nil {
+		return common.Identity{CredentialsSource: config.ManagedIdentityCredentialsSource, Principal: dsc.storageConfig.AccountName}
+	}
+	if dsc.credential == nil {
+		return common.Identity{CredentialsSource: config.SASTokenCredentialsSource, Principal: dsc.storageConfig.AccountName}
+	}
+	return common.Identity{CredentialsSource: config.SharedKeyCredentialsSource, Principal: dsc.storageConfig.AccountName}
+}
+
+// preconditionError marks err with common.ErrPreconditionFailed if the service rejected an If-Match
+func preconditionError(err error) error {
+	var respErr *azcore.ResponseError
+	if errors.As(err, &respErr) && respErr.StatusCode == http.StatusPreconditionFailed {
+		return fmt.Errorf("%w: %w", common.ErrPreconditionFailed, err)
+	}
+	return err
+}
@@ -0,0 +1,284 @@
+package client_test
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/storage-cli/azurebs/client"
+	"github.com/cloudfoundry/storage-cli/azurebs/config"
+	"github.com/cloudfoundry/storage-cli/common"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DefaultStorageClient", func() {
+	It("does not depend on Ginkgo outside of tests", func() {
+		files, err := filepath.Glob("*.go")
+		Expect(err).ToNot(HaveOccurred())
+
+		for _, file := range files {
+			if strings.HasSuffix(file, "_test.go") {
+				continue
+			}
+			parsed, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.ImportsOnly)
+			Expect(err).ToNot(HaveOccurred())
+			for _, spec := range parsed.Imports {
+				path, err := strconv.Unquote(spec.Path.Value)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(path).ToNot(HavePrefix("github.com/onsi/ginkgo"), "%s imports %s", file, path)
+			}
+		}
+	})
+
+	Context("SignedUrl", func() {
+		It("escapes blob names with spaces, unicode and plus signs", func() {
+			storageClient, err := client.NewStorageClient(config.AZStorageConfig{
+				AccountName:   "some-account",
+				AccountKey:    "c29tZS1rZXk=",
+				ContainerName: "some-container",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			signedURL, err := storageClient.SignedUrl(context.Background(), "GET", "dir a/üñîçød ë file+1.txt", time.Hour, time.Time{})
+			Expect(err).ToNot(HaveOccurred())
+
+			parsed, err := url.Parse(signedURL)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed.EscapedPath()).To(Equal("/some-container/dir%20a/%C3%BC%C3%B1%C3%AE%C3%A7%C3%B8d%20%C3%AB%20file%2B1.txt"))
+			Expect(parsed.Path).To(Equal("/some-container/dir a/üñîçød ë file+1.txt"))
+			Expect(parsed.Query().Get("sig")).ToNot(BeEmpty())
+		})
+
+		It("sets the default server-side timeouts", func() {
+			storageClient, err := client.NewStorageClient(config.AZStorageConfig{
+				AccountName:   "some-account",
+				AccountKey:    "c29tZS1rZXk=",
+				ContainerName: "some-container",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			signedURL, err := storageClient.SignedUrl(context.Background(), "GET", "some-blob", time.Hour, time.Time{})
+			Expect(err).ToNot(HaveOccurred())
+			parsed, err := url.Parse(signedURL)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed.Query().Get("timeout")).To(Equal("1800"))
+
+			signedURL, err = storageClient.SignedUrl(context.Background(), "PUT", "some-blob", time.Hour, time.Time{})
+			Expect(err).ToNot(HaveOccurred())
+			parsed, err = url.Parse(signedURL)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed.Query().Get("timeout")).To(Equal("2700"))
+		})
+
+		It("sets the configured server-side timeouts", func() {
+			storageClient, err := client.NewStorageClient(config.AZStorageConfig{
+				AccountName:                "some-account",
+				AccountKey:                 "c29tZS1rZXk=",
+				ContainerName:              "some-container",
+				SignedURLGetTimeoutSeconds: 3600,
+				SignedURLPutTimeoutSeconds: 7200,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			signedURL, err := storageClient.SignedUrl(context.Background(), "GET", "some-blob", time.Hour, time.Time{})
+			Expect(err).ToNot(HaveOccurred())
+			parsed, err := url.Parse(signedURL)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed.Query().Get("timeout")).To(Equal("3600"))
+
+			signedURL, err = storageClient.SignedUrl(context.Background(), "PUT", "some-blob", time.Hour, time.Time{})
+			Expect(err).ToNot(HaveOccurred())
+			parsed, err = url.Parse(signedURL)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed.Query().Get("timeout")).To(Equal("7200"))
+		})
+
+		It("makes the url valid from the start time on", func() {
+			storageClient, err := client.NewStorageClient(config.AZStorageConfig{
+				AccountName:   "some-account",
+				AccountKey:    "c29tZS1rZXk=",
+				ContainerName: "some-container",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			startAt := time.Date(2030, time.January, 2, 3, 4, 5, 0, time.UTC)
+			signedURL, err := storageClient.SignedUrl(context.Background(), "GET", "some-blob", time.Hour, startAt)
+			Expect(err).ToNot(HaveOccurred())
+			parsed, err := url.Parse(signedURL)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed.Query().Get("st")).To(Equal("2030-01-02T03:04:05Z"))
+			Expect(parsed.Query().Get("se")).To(Equal("2030-01-02T04:04:05Z"))
+		})
+
+		It("refuses to sign with only a SAS token", func() {
+			storageClient, err := client.NewStorageClient(config.AZStorageConfig{
+				AccountName:   "some-account",
+				ContainerName: "some-container",
+				SASToken:      "sv=2022-11-02&sig=some-signature",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = storageClient.SignedUrl(context.Background(), "GET", "some-blob", time.Hour, time.Time{})
+			Expect(err).To(MatchError(ContainSubstring("signing URLs requires the account_key")))
+		})
+
+		It("refuses to sign with the managed identity credentials source", func() {
+			storageClient, err := client.NewStorageClient(config.AZStorageConfig{
+				AccountName:       "some-account",
+				ContainerName:     "some-container",
+				CredentialsSource: config.ManagedIdentityCredentialsSource,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = storageClient.SignedUrl(context.Background(), "GET", "some-blob", time.Hour, time.Time{})
+			Expect(err).To(MatchError(ContainSubstring("signing URLs requires the account_key")))
+		})
+	})
+
+	Context("CopySourceURL", func() {
+		It("resolves a blob name in the configured container", func() {
+			storageClient, err := client.NewStorageClient(config.AZStorageConfig{
+				AccountName:   "some-account",
+				AccountKey:    "c29tZS1rZXk=",
+				ContainerName: "some-container",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			sourceURL, err := storageClient.(client.DefaultStorageClient).CopySourceURL("dir/some-blob", "")
+			Expect(err).ToNot(HaveOccurred())
+			parsed, err := url.Parse(sourceURL)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed.Host).To(HavePrefix("some-account."))
+			Expect(parsed.Path).To(Equal("/some-container/dir/some-blob"))
+			Expect(parsed.RawQuery).To(BeEmpty())
+		})
+
+		It("authorizes a blob name in the configured container with the SAS token", func() {
+			storageClient, err := client.NewStorageClient(config.AZStorageConfig{
+				AccountName:   "some-account",
+				ContainerName: "some-container",
+				SASToken:      "sv=2022-11-02&sig=some-signature",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			sourceURL, err := storageClient.(client.DefaultStorageClient).CopySourceURL("some-blob", "")
+			Expect(err).ToNot(HaveOccurred())
+			parsed, err := url.Parse(sourceURL)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed.Host).To(HavePrefix("some-account."))
+			Expect(parsed.Path).To(Equal("/some-container/some-blob"))
+			Expect(parsed.RawQuery).To(Equal("sv=2022-11-02&sig=some-signature"))
+		})
+
+		It("uses an absolute blob URL of another account as is", func() {
+			storageClient, err := client.NewStorageClient(config.AZStorageConfig{
+				AccountName:   "some-account",
+				ContainerName: "some-container",
+				SASToken:      "sv=2022-11-02&sig=some-signature",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			source := "https://other-account.blob.core.windows.net/other-container/some-blob?sv=2022-11-02&sig=other-signature"
+			Expect(storageClient.(client.DefaultStorageClient).CopySourceURL(source, "")).To(Equal(source))
+		})
+
+		Context("with a source SAS token", func() {
+			const sourceSAS = "?sv=2022-11-02&sr=b&sig=source-signature"
+
+			var storageClient client.DefaultStorageClient
+
+			BeforeEach(func() {
+				configuredClient, err := client.NewStorageClient(config.AZStorageConfig{
+					AccountName:   "some-account",
+					ContainerName: "some-container",
+					SASToken:      "sv=2022-11-02&sig=some-signature",
+				})
+				Expect(err).ToNot(HaveOccurred())
+				storageClient = configuredClient.(client.DefaultStorageClient)
+			})
+
+			It("appends it to an absolute blob URL of another account", func() {
+				sourceURL, err := storageClient.CopySourceURL("https://other-account.blob.core.windows.net/other-container/some-blob", sourceSAS)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sourceURL).To(Equal("https://other-account.blob.core.windows.net/other-container/some-blob?sv=2022-11-02&sr=b&sig=source-signature"))
+			})
+
+			It("uses it instead of the configured SAS token for a blob name", func() {
+				sourceURL, err := storageClient.CopySourceURL("some-blob", sourceSAS)
+				Expect(err).ToNot(HaveOccurred())
+				parsed, err := url.Parse(sourceURL)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(parsed.Path).To(Equal("/some-container/some-blob"))
+				Expect(parsed.RawQuery).To(Equal("sv=2022-11-02&sr=b&sig=source-signature"))
+			})
+
+			It("refuses a source URL that already has a query", func() {
+				_, err := storageClient.CopySourceURL("https://other-account.blob.core.windows.net/other-container/some-blob?sig=other-signature", sourceSAS)
+				Expect(err).To(MatchError(ContainSubstring("already has a query")))
+			})
+
+			It("refuses to send it over http", func() {
+				_, err := storageClient.CopySourceURL("http://other-account.blob.core.windows.net/other-container/some-blob", sourceSAS)
+				Expect(err).To(MatchError(ContainSubstring("only sent over https")))
+			})
+
+			It("refuses a token without a signature", func() {
+				_, err := storageClient.CopySourceURL("https://other-account.blob.core.windows.net/other-container/some-blob", "sv=2022-11-02&sr=b")
+				Expect(err).To(MatchError(ContainSubstring("invalid source SAS token")))
+			})
+		})
+	})
+
+	Context("NewStorageClient", func() {
+		It("fails for an unknown credentials source", func() {
+			_, err := client.NewStorageClient(config.AZStorageConfig{
+				AccountName:       "some-account",
+				ContainerName:     "some-container",
+				CredentialsSource: "bogus",
+			})
+			Expect(err).To(MatchError("unknown credentials_source in configuration"))
+		})
+	})
+
+	Context("Identity", func() {
+		It("reports the shared key as the credentials source", func() {
+			storageClient, err := client.NewStorageClient(config.AZStorageConfig{
+				AccountName:   "some-account",
+				AccountKey:    "c29tZS1rZXk=",
+				ContainerName: "some-container",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(storageClient.Identity()).To(Equal(common.Identity{CredentialsSource: "shared_key", Principal: "some-account"}))
+		})
+
+		It("reports the SAS token as the credentials source without building a shared key", func() {
+			storageClient, err := client.NewStorageClient(config.AZStorageConfig{
+				AccountName:   "some-account",
+				ContainerName: "some-container",
+				SASToken:      "sv=2022-11-02&sig=some-signature",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(storageClient.Identity()).To(Equal(common.Identity{CredentialsSource: "sas_token", Principal: "some-account"}))
+		})
+
+		It("reports the managed identity credentials source", func() {
+			storageClient, err := client.NewStorageClient(config.AZStorageConfig{
+				AccountName:       "some-account",
+				ContainerName:     "some-container",
+				CredentialsSource: config.ManagedIdentityCredentialsSource,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(storageClient.Identity()).To(Equal(common.Identity{CredentialsSource: "managed_identity", Principal: "some-account"}))
+		})
+	})
+})
@@ -3,7 +3,9 @@ package config
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 )
@@ -27,13 +29,54 @@ func init() {
 }
 
 type AZStorageConfig struct {
-	AccountName   string `json:"account_name"`
+	AccountName   string `json:"account_name" required:"true"`
 	AccountKey    string `json:"account_key"`
-	ContainerName string `json:"container_name"`
+	ContainerName string `json:"container_name" required:"true"`
 	Environment   string `json:"environment"`
 	Timeout       string `json:"put_timeout_in_seconds"`
+	// SASToken authenticates with a shared access signature instead of the account key
+	SASToken string `json:"sas_token"`
+	// CredentialsSource is either 'static' (default) to use account_key or sas_token, or
+	// 'managed_identity' to get a token from Azure AD, e.g. for the managed identity of the VM
+	CredentialsSource string `json:"credentials_source"`
+	// UploadBlockSizeMB is the size of the blocks larger files are uploaded in, 4 MB if unset
+	UploadBlockSizeMB int64 `json:"upload_block_size_mb"`
+	// UploadConcurrency is the number of blocks uploaded in parallel, 5 if unset
+	UploadConcurrency int `json:"upload_concurrency"`
+	// AccessTier is the tier uploaded blobs are stored in: Hot, Cool, Cold or Archive. Unset, the
+	// default tier of the storage account applies.
+	AccessTier string `json:"access_tier"`
+	// SignedURLGetTimeoutSeconds is the server-side timeout of requests to signed GET URLs, 1800 if unset
+	SignedURLGetTimeoutSeconds int `json:"signed_url_get_timeout_seconds"`
+	// SignedURLPutTimeoutSeconds is the server-side timeout of requests to signed PUT URLs, 2700 if unset
+	SignedURLPutTimeoutSeconds int `json:"signed_url_put_timeout_seconds"`
 }
 
+const (
+	defaultUploadBlockSizeMB = 4
+	// maxUploadBlockSizeMB is the Azure limit for a single block of a block blob
+	maxUploadBlockSizeMB     = 4000
+	defaultUploadConcurrency = 5
+
+	defaultSignedURLGetTimeoutSeconds = 1800
+	defaultSignedURLPutTimeoutSeconds = 2700
+)
+
+// StaticCredentialsSource authenticates with the account_key or the sas_token of the config
+const StaticCredentialsSource = "static"
+
+// ManagedIdentityCredentialsSource authenticates with a token from Azure AD, found through the default
+// credential chain of azidentity, which includes the managed identity of an Azure VM
+const ManagedIdentityCredentialsSource = "managed_identity"
+
+// SharedKeyCredentialsSource and SASTokenCredentialsSource are reported by identity for the static
+// credentials source, depending on whether the account_key or the sas_token is used. They are not
+// accepted as credentials_source.
+const (
+	SharedKeyCredentialsSource = "shared_key"
+	SASTokenCredentialsSource  = "sas_token"
+)
+
 // NewFromReader returns a new azure-storage-cli configuration struct from the contents of reader.
 // reader.Read() is expected to return valid JSON
 func NewFromReader(reader io.Reader) (AZStorageConfig, error) {
@@ -48,10 +91,35 @@ func NewFromReader(reader io.Reader) (AZStorageConfig, error) {
 		return AZStorageConfig{}, err
 	}
 
+	if config.AccountKey != "" && config.SASToken != "" {
+		return AZStorageConfig{}, errors.New("account_key and sas_token are mutually exclusive")
+	}
+	if config.CredentialsSource == "" {
+		config.CredentialsSource = StaticCredentialsSource
+	}
+	if config.CredentialsSource == ManagedIdentityCredentialsSource && (config.AccountKey != "" || config.SASToken != "") {
+		return AZStorageConfig{}, errors.New("account_key and sas_token can't be used with credentials_source managed_identity")
+	}
+	if config.UploadBlockSizeMB < 0 || config.UploadConcurrency < 0 {
+		return AZStorageConfig{}, errors.New("upload_block_size_mb and upload_concurrency must not be negative")
+	}
+	if config.UploadBlockSizeMB > maxUploadBlockSizeMB {
+		return AZStorageConfig{}, fmt.Errorf("upload_block_size_mb must not exceed %d, the Azure limit for a block", maxUploadBlockSizeMB)
+	}
+	if config.SignedURLGetTimeoutSeconds < 0 || config.SignedURLPutTimeoutSeconds < 0 {
+		return AZStorageConfig{}, errors.New("signed_url_get_timeout_seconds and signed_url_put_timeout_seconds must not be negative")
+	}
+	// The token is appended to URLs as their query, portal copies come with the leading "?"
+	config.SASToken = strings.TrimPrefix(config.SASToken, "?")
+
 	err = config.configureCloud()
 	if err != nil {
 		return AZStorageConfig{}, err
 	}
+	err = config.validateAccessTier()
+	if err != nil {
+		return AZStorageConfig{}, err
+	}
 
 	return config, nil
 }
@@ -60,6 +128,38 @@ func (c AZStorageConfig) StorageEndpoint() string {
 	return cloudConfig.Services[storage].Endpoint
 }
 
+// UploadBlockSize returns the size in bytes of the blocks larger files are uploaded in
+func (c AZStorageConfig) UploadBlockSize() int64 {
+	if c.UploadBlockSizeMB == 0 {
+		return defaultUploadBlockSizeMB * 1024 * 1024
+	}
+	return c.UploadBlockSizeMB * 1024 * 1024
+}
+
+// UploadMaxConcurrency returns the number of blocks uploaded in parallel
+func (c AZStorageConfig) UploadMaxConcurrency() int {
+	if c.UploadConcurrency == 0 {
+		return defaultUploadConcurrency
+	}
+	return c.UploadConcurrency
+}
+
+// SignedURLGetTimeout returns the server-side timeout in seconds of requests to signed GET URLs
+func (c AZStorageConfig) SignedURLGetTimeout() int {
+	if c.SignedURLGetTimeoutSeconds == 0 {
+		return defaultSignedURLGetTimeoutSeconds
+	}
+	return c.SignedURLGetTimeoutSeconds
+}
+
+// SignedURLPutTimeout returns the server-side timeout in seconds of requests to signed PUT URLs
+func (c AZStorageConfig) SignedURLPutTimeout() int {
+	if c.SignedURLPutTimeoutSeconds == 0 {
+		return defaultSignedURLPutTimeoutSeconds
+	}
+	return c.SignedURLPutTimeoutSeconds
+}
+
 func (c *AZStorageConfig) configureCloud() error {
 	switch c.Environment {
 	case "AzureCloud", "":
@@ -74,3 +174,12 @@ func (c *AZStorageConfig) configureCloud() error {
 	}
 	return nil
 }
+
+func (c *AZStorageConfig) validateAccessTier() error {
+	switch c.AccessTier {
+	case "", "Hot", "Cool", "Cold", "Archive":
+		return nil
+	default:
+		return errors.New("unknown access_tier: " + c.AccessTier)
+	}
+}
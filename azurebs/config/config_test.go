@@ -39,6 +39,161 @@ var _ = Describe("Config", func() {
 		Expect(config.AccountKey).Should(BeEmpty())
 	})
 
+	Context("authentication", func() {
+		It("accepts a SAS token instead of the account key", func() {
+			configJson := []byte(`{"account_name": "foo-account-name",
+									"sas_token": "sv=2022-11-02&sig=some-signature",
+									"container_name": "baz-container-name"}`)
+
+			config, err := config.NewFromReader(bytes.NewReader(configJson))
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.SASToken).To(Equal("sv=2022-11-02&sig=some-signature"))
+			Expect(config.AccountKey).To(BeEmpty())
+		})
+
+		It("drops the leading question mark of a SAS token", func() {
+			configJson := []byte(`{"account_name": "foo-account-name",
+									"sas_token": "?sv=2022-11-02&sig=some-signature",
+									"container_name": "baz-container-name"}`)
+
+			config, err := config.NewFromReader(bytes.NewReader(configJson))
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.SASToken).To(Equal("sv=2022-11-02&sig=some-signature"))
+		})
+
+		It("defaults the credentials source to static", func() {
+			configJson := []byte(`{"account_name": "foo-account-name",
+									"account_key": "bar-account-key",
+									"container_name": "baz-container-name"}`)
+
+			config, err := config.NewFromReader(bytes.NewReader(configJson))
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.CredentialsSource).To(Equal("static"))
+		})
+
+		It("accepts the managed identity credentials source without a key or token", func() {
+			configJson := []byte(`{"account_name": "foo-account-name",
+									"credentials_source": "managed_identity",
+									"container_name": "baz-container-name"}`)
+
+			config, err := config.NewFromReader(bytes.NewReader(configJson))
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.CredentialsSource).To(Equal("managed_identity"))
+		})
+
+		It("refuses an account key or SAS token with the managed identity credentials source", func() {
+			configJson := []byte(`{"account_name": "foo-account-name",
+									"credentials_source": "managed_identity",
+									"account_key": "bar-account-key",
+									"container_name": "baz-container-name"}`)
+
+			_, err := config.NewFromReader(bytes.NewReader(configJson))
+
+			Expect(err).To(MatchError("account_key and sas_token can't be used with credentials_source managed_identity"))
+		})
+
+		It("refuses both an account key and a SAS token", func() {
+			configJson := []byte(`{"account_name": "foo-account-name",
+									"account_key": "bar-account-key",
+									"sas_token": "sv=2022-11-02&sig=some-signature",
+									"container_name": "baz-container-name"}`)
+
+			_, err := config.NewFromReader(bytes.NewReader(configJson))
+
+			Expect(err).To(MatchError("account_key and sas_token are mutually exclusive"))
+		})
+	})
+
+	Context("upload tuning", func() {
+		It("defaults to 4 MB blocks and 5 parallel uploads", func() {
+			config, err := config.NewFromReader(bytes.NewReader([]byte(`{"account_name": "foo-account-name"}`)))
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.UploadBlockSize()).To(Equal(int64(4 * 1024 * 1024)))
+			Expect(config.UploadMaxConcurrency()).To(Equal(5))
+		})
+
+		It("takes the block size in MB and the concurrency from the config", func() {
+			configJson := []byte(`{"account_name": "foo-account-name", "upload_block_size_mb": 100, "upload_concurrency": 16}`)
+
+			config, err := config.NewFromReader(bytes.NewReader(configJson))
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.UploadBlockSize()).To(Equal(int64(100 * 1024 * 1024)))
+			Expect(config.UploadMaxConcurrency()).To(Equal(16))
+		})
+
+		It("refuses blocks larger than Azure allows", func() {
+			_, err := config.NewFromReader(bytes.NewReader([]byte(`{"upload_block_size_mb": 4001}`)))
+
+			Expect(err).To(MatchError("upload_block_size_mb must not exceed 4000, the Azure limit for a block"))
+		})
+
+		It("refuses negative values", func() {
+			_, err := config.NewFromReader(bytes.NewReader([]byte(`{"upload_concurrency": -1}`)))
+
+			Expect(err).To(MatchError("upload_block_size_mb and upload_concurrency must not be negative"))
+		})
+	})
+
+	Context("signed URL timeouts", func() {
+		It("defaults to 1800 seconds for GET and 2700 seconds for PUT", func() {
+			config, err := config.NewFromReader(bytes.NewReader([]byte(`{"account_name": "foo-account-name"}`)))
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.SignedURLGetTimeout()).To(Equal(1800))
+			Expect(config.SignedURLPutTimeout()).To(Equal(2700))
+		})
+
+		It("takes the timeouts from the config", func() {
+			configJson := []byte(`{"account_name": "foo-account-name", "signed_url_get_timeout_seconds": 3600, "signed_url_put_timeout_seconds": 7200}`)
+
+			config, err := config.NewFromReader(bytes.NewReader(configJson))
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.SignedURLGetTimeout()).To(Equal(3600))
+			Expect(config.SignedURLPutTimeout()).To(Equal(7200))
+		})
+
+		It("refuses negative values", func() {
+			_, err := config.NewFromReader(bytes.NewReader([]byte(`{"signed_url_put_timeout_seconds": -1}`)))
+
+			Expect(err).To(MatchError("signed_url_get_timeout_seconds and signed_url_put_timeout_seconds must not be negative"))
+		})
+	})
+
+	Context("access tier", func() {
+		It("leaves the tier to the storage account by default", func() {
+			config, err := config.NewFromReader(bytes.NewReader([]byte(`{"account_name": "foo-account-name"}`)))
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.AccessTier).To(BeEmpty())
+		})
+
+		DescribeTable("accepts the tiers of block blobs",
+			func(tier string) {
+				config, err := config.NewFromReader(bytes.NewReader([]byte(`{"account_name": "foo-account-name", "access_tier": "` + tier + `"}`)))
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(config.AccessTier).To(Equal(tier))
+			},
+			Entry("hot", "Hot"),
+			Entry("cool", "Cool"),
+			Entry("cold", "Cold"),
+			Entry("archive", "Archive"),
+		)
+
+		It("refuses an unknown tier", func() {
+			_, err := config.NewFromReader(bytes.NewReader([]byte(`{"account_name": "foo-account-name", "access_tier": "Frozen"}`)))
+
+			Expect(err).To(MatchError("unknown access_tier: Frozen"))
+		})
+	})
+
 	Context("when the configuration file cannot be read", func() {
 		It("returns an error", func() {
 			f := explodingReader{}
@@ -3,12 +3,13 @@ package integration
 import (
 	"bytes"
 	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
 	"io"
 	"os"
 
 	"github.com/cloudfoundry/storage-cli/azurebs/config"
 	. "github.com/onsi/gomega" //nolint:staticcheck
-	"github.com/onsi/gomega/gbytes"
 )
 
 var storageType = "azurebs"
@@ -25,13 +26,13 @@ func AssertPutUsesNoTimeout(cliPath string, cfg *config.AZStorageConfig) {
 
 	sess, err := RunCli(cliPath, configPath, storageType, "put", content, blob)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(sess.ExitCode()).To(BeZero())
-	Expect(sess.Err).Should(gbytes.Say(`"msg":"Uploading blob to container`))
-	Expect(sess.Err).ShouldNot(gbytes.Say(`"timeout":"*"`))
+	Expect(sess.ExitCode).To(BeZero())
+	Expect(string(sess.Stderr)).To(MatchRegexp(`"msg":"Uploading blob to container`))
+	Expect(string(sess.Stderr)).ToNot(MatchRegexp(`"timeout":"*"`))
 
 	sess, err = RunCli(cliPath, configPath, storageType, "delete", blob)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(sess.ExitCode()).To(BeZero())
+	Expect(sess.ExitCode).To(BeZero())
 }
 
 func AssertPutHonorsCustomTimeout(cliPath string, cfg *config.AZStorageConfig) {
@@ -46,13 +47,13 @@ func AssertPutHonorsCustomTimeout(cliPath string, cfg *config.AZStorageConfig) {
 
 	sess, err := RunCli(cliPath, configPath, storageType, "put", content, blob)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(sess.ExitCode()).To(BeZero())
-	Expect(sess.Err).Should(gbytes.Say(`"msg":"Uploading blob to container`))
-	Expect(sess.Err).Should(gbytes.Say(`"timeout":"3"`))
+	Expect(sess.ExitCode).To(BeZero())
+	Expect(string(sess.Stderr)).To(MatchRegexp(`"msg":"Uploading blob to container`))
+	Expect(string(sess.Stderr)).To(MatchRegexp(`"timeout":"3"`))
 
 	sess, err = RunCli(cliPath, configPath, storageType, "delete", blob)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(sess.ExitCode()).To(BeZero())
+	Expect(sess.ExitCode).To(BeZero())
 }
 
 func AssertPutTimesOut(cliPath string, cfg *config.AZStorageConfig) {
@@ -69,8 +70,8 @@ func AssertPutTimesOut(cliPath string, cfg *config.AZStorageConfig) {
 
 	sess, err := RunCli(cliPath, configPath, storageType, "put", content, blob)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(sess.ExitCode()).ToNot(BeZero())
-	Expect(string(sess.Err.Contents())).To(ContainSubstring("timeout of 1 reached while uploading"))
+	Expect(sess.ExitCode).ToNot(BeZero())
+	Expect(string(sess.Stderr)).To(ContainSubstring("timeout of 1 reached while uploading"))
 }
 
 func AssertInvalidTimeoutIsError(cliPath string, cfg *config.AZStorageConfig) {
@@ -85,8 +86,8 @@ func AssertInvalidTimeoutIsError(cliPath string, cfg *config.AZStorageConfig) {
 
 	sess, err := RunCli(cliPath, configPath, storageType, "put", content, blob)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(sess.ExitCode()).ToNot(BeZero())
-	Expect(sess.Err).Should(gbytes.Say(`"error":"upload failure: invalid timeout format: strconv.Atoi: parsing \\"bananas\\": invalid syntax"`))
+	Expect(sess.ExitCode).ToNot(BeZero())
+	Expect(string(sess.Stderr)).To(MatchRegexp(`"error":"upload failure: invalid timeout format: strconv.Atoi: parsing \\"bananas\\": invalid syntax"`))
 }
 
 func AssertZeroTimeoutIsError(cliPath string, cfg *config.AZStorageConfig) {
@@ -101,8 +102,8 @@ func AssertZeroTimeoutIsError(cliPath string, cfg *config.AZStorageConfig) {
 
 	sess, err := RunCli(cliPath, configPath, storageType, "put", content, blob)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(sess.ExitCode()).ToNot(BeZero())
-	Expect(sess.Err).Should(gbytes.Say(`"msg":"Invalid time, need at least 1 second"`))
+	Expect(sess.ExitCode).ToNot(BeZero())
+	Expect(string(sess.Stderr)).To(MatchRegexp(`"msg":"Invalid time, need at least 1 second"`))
 }
 
 func AssertNegativeTimeoutIsError(cliPath string, cfg *config.AZStorageConfig) {
@@ -117,9 +118,9 @@ func AssertNegativeTimeoutIsError(cliPath string, cfg *config.AZStorageConfig) {
 
 	sess, err := RunCli(cliPath, configPath, storageType, "put", content, blob)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(sess.ExitCode()).ToNot(BeZero())
+	Expect(sess.ExitCode).ToNot(BeZero())
 
-	Expect(sess.Err).Should(gbytes.Say(`"msg":"Invalid time, need at least 1 second"`))
+	Expect(string(sess.Stderr)).To(MatchRegexp(`"msg":"Invalid time, need at least 1 second"`))
 }
 
 func AssertSignedURLTimeouts(cliPath string, cfg *config.AZStorageConfig) {
@@ -128,12 +129,12 @@ func AssertSignedURLTimeouts(cliPath string, cfg *config.AZStorageConfig) {
 
 	sess, err := RunCli(cliPath, configPath, storageType, "sign", "some-blob", "get", "60s")
 	Expect(err).ToNot(HaveOccurred())
-	url := string(sess.Out.Contents())
+	url := string(sess.Stdout)
 	Expect(url).To(ContainSubstring("timeout=1800"))
 
 	sess, err = RunCli(cliPath, configPath, storageType, "sign", "some-blob", "put", "60s")
 	Expect(err).ToNot(HaveOccurred())
-	url = string(sess.Out.Contents())
+	url = string(sess.Stdout)
 	Expect(url).To(ContainSubstring("timeout=2700"))
 }
 
@@ -143,11 +144,11 @@ func AssertEnsureStorageIdempotent(cliPath string, cfg *config.AZStorageConfig)
 
 	s1, err := RunCli(cliPath, configPath, storageType, "ensure-storage-exists")
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s1.ExitCode()).To(BeZero())
+	Expect(s1.ExitCode).To(BeZero())
 
 	s2, err := RunCli(cliPath, configPath, storageType, "ensure-storage-exists")
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s2.ExitCode()).To(BeZero())
+	Expect(s2.ExitCode).To(BeZero())
 }
 
 func AssertPutGetWithSpecialNames(cliPath string, cfg *config.AZStorageConfig) {
@@ -161,7 +162,7 @@ func AssertPutGetWithSpecialNames(cliPath string, cfg *config.AZStorageConfig) {
 
 	s, err := RunCli(cliPath, configPath, storageType, "put", f, name)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s.ExitCode()).To(BeZero())
+	Expect(s.ExitCode).To(BeZero())
 
 	tmp, _ := os.CreateTemp("", "dl") //nolint:errcheck
 	tmp.Close()                       //nolint:errcheck
@@ -169,14 +170,14 @@ func AssertPutGetWithSpecialNames(cliPath string, cfg *config.AZStorageConfig) {
 
 	s, err = RunCli(cliPath, configPath, storageType, "get", name, tmp.Name())
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s.ExitCode()).To(BeZero())
+	Expect(s.ExitCode).To(BeZero())
 
 	b, _ := os.ReadFile(tmp.Name()) //nolint:errcheck
 	Expect(string(b)).To(Equal(content))
 
 	s, err = RunCli(cliPath, configPath, storageType, "delete", name)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s.ExitCode()).To(BeZero())
+	Expect(s.ExitCode).To(BeZero())
 }
 
 func AssertLifecycleWorks(cliPath string, cfg *config.AZStorageConfig) {
@@ -192,25 +193,29 @@ func AssertLifecycleWorks(cliPath string, cfg *config.AZStorageConfig) {
 	// Ensure container/bucket exists
 	cliSession, err := RunCli(cliPath, configPath, storageType, "ensure-storage-exists")
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(BeZero())
+	Expect(cliSession.ExitCode).To(BeZero())
 
 	cliSession, err = RunCli(cliPath, configPath, storageType, "put", contentFile, blobName)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(BeZero())
+	Expect(cliSession.ExitCode).To(BeZero())
 
 	cliSession, err = RunCli(cliPath, configPath, storageType, "exists", blobName)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(BeZero())
-	Expect(cliSession.Err).Should(gbytes.Say(`"msg":"Blob exists in container"`))
+	Expect(cliSession.ExitCode).To(BeZero())
+	Expect(string(cliSession.Stderr)).To(MatchRegexp(`"msg":"Blob exists in container"`))
 
 	// Check blob properties
 	cliSession, err = RunCli(cliPath, configPath, storageType, "properties", blobName)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(BeZero())
-	output := string(cliSession.Out.Contents())
-	Expect(output).To(MatchRegexp(`"etag":\s*".+?"`))
-	Expect(output).To(MatchRegexp(`"last_modified":\s*".+?"`))
-	Expect(output).To(MatchRegexp(`"content_length":\s*\d+`))
+	Expect(cliSession.ExitCode).To(BeZero())
+	// Nothing but the JSON document may end up on stdout
+	var properties map[string]any
+	Expect(json.Unmarshal(cliSession.Stdout, &properties)).To(Succeed())
+	Expect(properties).To(HaveKeyWithValue("etag", Not(BeEmpty())))
+	Expect(properties).To(HaveKeyWithValue("last_modified", Not(BeEmpty())))
+	Expect(properties).To(HaveKeyWithValue("content_length", BeNumerically("==", len(expectedString))))
+	expectedMD5 := md5.Sum([]byte(expectedString))
+	Expect(properties).To(HaveKeyWithValue("content_md5", base64.StdEncoding.EncodeToString(expectedMD5[:])))
 
 	tmpLocalFile, err := os.CreateTemp("", "azure-storage-cli-download")
 	Expect(err).ToNot(HaveOccurred())
@@ -220,7 +225,7 @@ func AssertLifecycleWorks(cliPath string, cfg *config.AZStorageConfig) {
 
 	cliSession, err = RunCli(cliPath, configPath, storageType, "get", blobName, tmpLocalFile.Name())
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(BeZero())
+	Expect(cliSession.ExitCode).To(BeZero())
 
 	gottenBytes, err := os.ReadFile(tmpLocalFile.Name())
 	Expect(err).ToNot(HaveOccurred())
@@ -228,17 +233,17 @@ func AssertLifecycleWorks(cliPath string, cfg *config.AZStorageConfig) {
 
 	cliSession, err = RunCli(cliPath, configPath, storageType, "delete", blobName)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(BeZero())
+	Expect(cliSession.ExitCode).To(BeZero())
 
 	cliSession, err = RunCli(cliPath, configPath, storageType, "exists", blobName)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(Equal(3))
-	Expect(cliSession.Err).Should(gbytes.Say(`"msg":"Blob does not exist in container"`))
+	Expect(cliSession.ExitCode).To(Equal(3))
+	Expect(string(cliSession.Stderr)).To(MatchRegexp(`"msg":"Blob does not exist in container"`))
 
 	cliSession, err = RunCli(cliPath, configPath, storageType, "properties", blobName)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(Equal(0))
-	Expect(cliSession.Out.Contents()).To(MatchRegexp("{}"))
+	Expect(cliSession.ExitCode).To(Equal(0))
+	Expect(cliSession.Stdout).To(MatchRegexp("{}"))
 }
 
 func AssertOnCliVersion(cliPath string, cfg *config.AZStorageConfig) {
@@ -247,9 +252,9 @@ func AssertOnCliVersion(cliPath string, cfg *config.AZStorageConfig) {
 
 	cliSession, err := RunCli(cliPath, configPath, storageType, "-v")
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(Equal(0))
+	Expect(cliSession.ExitCode).To(Equal(0))
 
-	consoleOutput := bytes.NewBuffer(cliSession.Out.Contents()).String()
+	consoleOutput := bytes.NewBuffer(cliSession.Stdout).String()
 	Expect(consoleOutput).To(ContainSubstring("version"))
 }
 
@@ -259,7 +264,7 @@ func AssertGetNonexistentFails(cliPath string, cfg *config.AZStorageConfig) {
 
 	cliSession, err := RunCli(cliPath, configPath, storageType, "get", "non-existent-file", "/dev/null")
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).ToNot(BeZero())
+	Expect(cliSession.ExitCode).ToNot(BeZero())
 }
 
 func AssertDeleteNonexistentWorks(cliPath string, cfg *config.AZStorageConfig) {
@@ -268,7 +273,7 @@ func AssertDeleteNonexistentWorks(cliPath string, cfg *config.AZStorageConfig) {
 
 	cliSession, err := RunCli(cliPath, configPath, storageType, "delete", "non-existent-file")
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(BeZero())
+	Expect(cliSession.ExitCode).To(BeZero())
 }
 
 func AssertOnSignedURLs(cliPath string, cfg *config.AZStorageConfig) {
@@ -280,13 +285,13 @@ func AssertOnSignedURLs(cliPath string, cfg *config.AZStorageConfig) {
 	cliSession, err := RunCli(cliPath, configPath, storageType, "sign", "some-blob", "get", "60s")
 	Expect(err).ToNot(HaveOccurred())
 
-	getUrl := bytes.NewBuffer(cliSession.Out.Contents()).String()
+	getUrl := bytes.NewBuffer(cliSession.Stdout).String()
 	Expect(getUrl).To(MatchRegexp(regex))
 
 	cliSession, err = RunCli(cliPath, configPath, storageType, "sign", "some-blob", "put", "60s")
 	Expect(err).ToNot(HaveOccurred())
 
-	putUrl := bytes.NewBuffer(cliSession.Out.Contents()).String()
+	putUrl := bytes.NewBuffer(cliSession.Stdout).String()
 	Expect(putUrl).To(MatchRegexp(regex))
 }
 
@@ -296,12 +301,12 @@ func AssertOnListDeleteLifecyle(cliPath string, cfg *config.AZStorageConfig) {
 
 	cli, err := RunCli(cliPath, configPath, storageType, "delete-recursive", "")
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cli.ExitCode()).To(BeZero())
+	Expect(cli.ExitCode).To(BeZero())
 	cliSession, err := RunCli(cliPath, configPath, storageType, "list")
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(BeZero())
+	Expect(cliSession.ExitCode).To(BeZero())
 
-	Expect(len(cliSession.Out.Contents())).To(BeZero())
+	Expect(len(cliSession.Stdout)).To(BeZero())
 
 	CreateRandomBlobs(cliPath, cfg, 4, "")
 
@@ -314,42 +319,65 @@ func AssertOnListDeleteLifecyle(cliPath string, cfg *config.AZStorageConfig) {
 	// Assert that the blobs are listed correctly
 	cliSession, err = RunCli(cliPath, configPath, storageType, "list")
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(BeZero())
-	Expect(len(bytes.FieldsFunc(cliSession.Out.Contents(), func(r rune) bool { return r == '\n' || r == '\r' }))).To(BeNumerically("==", 10))
+	Expect(cliSession.ExitCode).To(BeZero())
+	Expect(len(bytes.FieldsFunc(cliSession.Stdout, func(r rune) bool { return r == '\n' || r == '\r' }))).To(BeNumerically("==", 10))
 
 	// Assert that the all blobs with custom prefix are listed correctly
 	cliSession, err = RunCli(cliPath, configPath, storageType, "list", customPrefix)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(BeZero())
-	Expect(len(bytes.FieldsFunc(cliSession.Out.Contents(), func(r rune) bool { return r == '\n' || r == '\r' }))).To(BeNumerically("==", 4))
+	Expect(cliSession.ExitCode).To(BeZero())
+	Expect(len(bytes.FieldsFunc(cliSession.Stdout, func(r rune) bool { return r == '\n' || r == '\r' }))).To(BeNumerically("==", 4))
 
 	// Delete all blobs with custom prefix
 	cliSession, err = RunCli(cliPath, configPath, storageType, "delete-recursive", customPrefix)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(BeZero())
+	Expect(cliSession.ExitCode).To(BeZero())
 
 	// Assert that the blobs with custom prefix are deleted
 	cliSession, err = RunCli(cliPath, configPath, storageType, "list", customPrefix)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(BeZero())
-	Expect(len(cliSession.Out.Contents())).To(BeZero())
+	Expect(cliSession.ExitCode).To(BeZero())
+	Expect(len(cliSession.Stdout)).To(BeZero())
 
 	// Assert that the other prefixed blobs are still listed
 	cliSession, err = RunCli(cliPath, configPath, storageType, "list", otherPrefix)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(BeZero())
-	Expect(len(bytes.FieldsFunc(cliSession.Out.Contents(), func(r rune) bool { return r == '\n' || r == '\r' }))).To(BeNumerically("==", 2))
+	Expect(cliSession.ExitCode).To(BeZero())
+	Expect(len(bytes.FieldsFunc(cliSession.Stdout, func(r rune) bool { return r == '\n' || r == '\r' }))).To(BeNumerically("==", 2))
 
 	// Delete all other blobs
 	cliSession, err = RunCli(cliPath, configPath, storageType, "delete-recursive", "")
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(BeZero())
+	Expect(cliSession.ExitCode).To(BeZero())
 
 	// Assert that all blobs are deleted
 	cliSession, err = RunCli(cliPath, configPath, storageType, "list")
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(BeZero())
-	Expect(len(cliSession.Out.Contents())).To(BeZero())
+	Expect(cliSession.ExitCode).To(BeZero())
+	Expect(len(cliSession.Stdout)).To(BeZero())
+}
+
+func AssertOnBatchedDeleteRecursive(cliPath string, cfg *config.AZStorageConfig) {
+	configPath := MakeConfigFile(cfg)
+	defer os.Remove(configPath) //nolint:errcheck
+
+	// One blob more than fits into a single batch
+	prefix := "batch-prefix-"
+	CreateRandomBlobs(cliPath, cfg, 257, prefix)
+
+	cliSession, err := RunCli(cliPath, configPath, storageType, "delete-recursive", prefix)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(cliSession.ExitCode).To(BeZero())
+
+	// The blobs are deleted in two batch requests instead of one request per blob
+	Expect(string(cliSession.Stderr)).To(MatchRegexp(`"msg":"Deleting batch of blobs".*"count":256`))
+	Expect(string(cliSession.Stderr)).To(MatchRegexp(`"msg":"Deleting batch of blobs".*"count":1\b`))
+	Expect(string(cliSession.Stderr)).ToNot(ContainSubstring("Failed to delete blob"))
+
+	cliSession, err = RunCli(cliPath, configPath, storageType, "list", prefix)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(cliSession.ExitCode).To(BeZero())
+	Expect(len(cliSession.Stdout)).To(BeZero())
 }
 
 func AssertOnCopy(cliPath string, cfg *config.AZStorageConfig) {
@@ -364,18 +392,18 @@ func AssertOnCopy(cliPath string, cfg *config.AZStorageConfig) {
 
 	cliSession, err := RunCli(cliPath, configPath, storageType, "put", contentFile, blobName)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(BeZero())
+	Expect(cliSession.ExitCode).To(BeZero())
 
 	// Copy the blob to a new name
 	copiedBlobName := GenerateRandomString()
 	cliSession, err = RunCli(cliPath, configPath, storageType, "copy", blobName, copiedBlobName)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(BeZero())
+	Expect(cliSession.ExitCode).To(BeZero())
 
 	// Assert that the copied blob exists
 	cliSession, err = RunCli(cliPath, configPath, storageType, "exists", copiedBlobName)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(BeZero())
+	Expect(cliSession.ExitCode).To(BeZero())
 
 	// Compare the content of the original and copied blobs
 	tmpLocalFile, err := os.CreateTemp("", "download-copy")
@@ -385,7 +413,7 @@ func AssertOnCopy(cliPath string, cfg *config.AZStorageConfig) {
 	defer os.Remove(tmpLocalFile.Name()) //nolint:errcheck
 	cliSession, err = RunCli(cliPath, configPath, storageType, "get", blobName, tmpLocalFile.Name())
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(BeZero())
+	Expect(cliSession.ExitCode).To(BeZero())
 	gottenBytes, err := os.ReadFile(tmpLocalFile.Name())
 	Expect(err).ToNot(HaveOccurred())
 	Expect(string(gottenBytes)).To(Equal(blobContent))
@@ -393,10 +421,10 @@ func AssertOnCopy(cliPath string, cfg *config.AZStorageConfig) {
 	// Clean up
 	cliSession, err = RunCli(cliPath, configPath, storageType, "delete", blobName)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(BeZero())
+	Expect(cliSession.ExitCode).To(BeZero())
 	cliSession, err = RunCli(cliPath, configPath, storageType, "delete", copiedBlobName)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(BeZero())
+	Expect(cliSession.ExitCode).To(BeZero())
 }
 
 func AssertOnUploadStream(cliPath string, cfg *config.AZStorageConfig) {
@@ -421,13 +449,13 @@ func AssertOnUploadStream(cliPath string, cfg *config.AZStorageConfig) {
 
 	cliSession, err := RunCli(cliPath, configPath, storageType, "put", contentFile, blobName)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(BeZero())
-	Expect(cliSession.Err).Should(gbytes.Say(`"msg":"UploadStreaming blob to container"`))
+	Expect(cliSession.ExitCode).To(BeZero())
+	Expect(string(cliSession.Stderr)).To(MatchRegexp(`"msg":"UploadStreaming blob to container"`))
 
 	// Assert that the copied blob exists
 	cliSession, err = RunCli(cliPath, configPath, storageType, "exists", blobName)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(BeZero())
+	Expect(cliSession.ExitCode).To(BeZero())
 
 	// Compare the content of the original and downloaded blobs
 	tmpLocalFile, err := os.CreateTemp("", "download-big-file")
@@ -437,7 +465,7 @@ func AssertOnUploadStream(cliPath string, cfg *config.AZStorageConfig) {
 	defer os.Remove(tmpLocalFile.Name()) //nolint:errcheck
 	cliSession, err = RunCli(cliPath, configPath, storageType, "get", blobName, tmpLocalFile.Name())
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(BeZero())
+	Expect(cliSession.ExitCode).To(BeZero())
 
 	// Verify file size matches
 	downloadedInfo, err := os.Stat(tmpLocalFile.Name())
@@ -457,7 +485,7 @@ func AssertOnUploadStream(cliPath string, cfg *config.AZStorageConfig) {
 	// Clean up
 	cliSession, err = RunCli(cliPath, configPath, storageType, "delete", blobName)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(cliSession.ExitCode()).To(BeZero())
+	Expect(cliSession.ExitCode).To(BeZero())
 }
 
 func CreateRandomBlobs(cliPath string, cfg *config.AZStorageConfig, count int, prefix string) {
@@ -474,6 +502,6 @@ func CreateRandomBlobs(cliPath string, cfg *config.AZStorageConfig, count int, p
 
 		cliSession, err := RunCli(cliPath, configPath, storageType, "put", contentFile, blobName)
 		Expect(err).ToNot(HaveOccurred())
-		Expect(cliSession.ExitCode()).To(BeZero())
+		Expect(cliSession.ExitCode).To(BeZero())
 	}
 }
@@ -9,7 +9,6 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"github.com/onsi/gomega/gbytes"
 )
 
 var _ = Describe("General testing for all Azure regions", func() {
@@ -92,6 +91,11 @@ var _ = Describe("General testing for all Azure regions", func() {
 		configurations,
 	)
 
+	DescribeTable("Deleting a large prefix uses batch requests",
+		func(cfg *config.AZStorageConfig) { integration.AssertOnBatchedDeleteRecursive(cliPath, cfg) },
+		configurations,
+	)
+
 	DescribeTable("Server-side copy works",
 		func(cfg *config.AZStorageConfig) { integration.AssertOnCopy(cliPath, cfg) },
 		configurations,
@@ -122,24 +126,24 @@ var _ = Describe("General testing for all Azure regions", func() {
 			defer func() {
 				cliSession, err := integration.RunCli(cliPath, configPath, storageType, "delete", blobName)
 				Expect(err).ToNot(HaveOccurred())
-				Expect(cliSession.ExitCode()).To(BeZero())
+				Expect(cliSession.ExitCode).To(BeZero())
 			}()
 
 			cliSession, err := integration.RunCli(cliPath, configPath, storageType, "put", contentFile, blobName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			cliSession, err = integration.RunCli(cliPath, configPath, storageType, "exists", blobName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
-			Expect(cliSession.Err).Should(gbytes.Say(`"msg":"Blob exists in container"`))
+			Expect(cliSession.ExitCode).To(BeZero())
+			Expect(string(cliSession.Stderr)).To(MatchRegexp(`"msg":"Blob exists in container"`))
 		})
 
 		It("overwrites an existing file", func() {
 			defer func() {
 				cliSession, err := integration.RunCli(cliPath, configPath, storageType, "delete", blobName)
 				Expect(err).ToNot(HaveOccurred())
-				Expect(cliSession.ExitCode()).To(BeZero())
+				Expect(cliSession.ExitCode).To(BeZero())
 			}()
 
 			tmpLocalFileName := "azure-storage-cli-download"
@@ -148,11 +152,11 @@ var _ = Describe("General testing for all Azure regions", func() {
 			contentFile = integration.MakeContentFile("initial content")
 			cliSession, err := integration.RunCli(cliPath, configPath, storageType, "put", contentFile, blobName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			cliSession, err = integration.RunCli(cliPath, configPath, storageType, "get", blobName, tmpLocalFileName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			gottenBytes, _ := os.ReadFile(tmpLocalFileName) //nolint:errcheck
 			Expect(string(gottenBytes)).To(Equal("initial content"))
@@ -160,11 +164,11 @@ var _ = Describe("General testing for all Azure regions", func() {
 			contentFile = integration.MakeContentFile("updated content")
 			cliSession, err = integration.RunCli(cliPath, configPath, storageType, "put", contentFile, blobName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			cliSession, err = integration.RunCli(cliPath, configPath, storageType, "get", blobName, tmpLocalFileName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(BeZero())
+			Expect(cliSession.ExitCode).To(BeZero())
 
 			gottenBytes, _ = os.ReadFile(tmpLocalFileName) //nolint:errcheck
 			Expect(string(gottenBytes)).To(Equal("updated content"))
@@ -181,9 +185,9 @@ var _ = Describe("General testing for all Azure regions", func() {
 
 			cliSession, err := integration.RunCli(cliPath, configPath, storageType, "put", contentFile, blobName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(cliSession.ExitCode()).To(Equal(1))
+			Expect(cliSession.ExitCode).To(Equal(1))
 
-			consoleOutput := bytes.NewBuffer(cliSession.Err.Contents()).String()
+			consoleOutput := bytes.NewBuffer(cliSession.Stderr).String()
 			Expect(consoleOutput).To(ContainSubstring("upload failure"))
 		})
 	})
@@ -2,16 +2,15 @@ package integration
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math/rand"
 	"os"
-	"os/exec"
-	"time"
 
-	"github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega"
-	"github.com/onsi/gomega/gexec"
 
 	"github.com/cloudfoundry/storage-cli/azurebs/config"
+	"github.com/cloudfoundry/storage-cli/clitest"
 )
 
 const alphanum = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
@@ -51,7 +50,16 @@ func MakeContentFile(content string) string {
 	return tmpFile.Name()
 }
 
-func RunCli(cliPath string, configPath string, storageType string, subcommand string, args ...string) (*gexec.Session, error) {
+// RunCli runs the CLI with the given config and storage type. It refuses to run
+// if storageType is not "azurebs" or subcommand is empty, which is what a call site
+// that forgot the storageType argument ends up passing.
+func RunCli(cliPath string, configPath string, storageType string, subcommand string, args ...string) (*clitest.Result, error) {
+	if storageType != "azurebs" {
+		return nil, fmt.Errorf("RunCli: storage type must be %q, got %q: is the storageType argument missing?", "azurebs", storageType)
+	}
+	if subcommand == "" {
+		return nil, errors.New("RunCli: no subcommand given")
+	}
 	cmdArgs := []string{
 		"-c",
 		configPath,
@@ -62,11 +70,5 @@ func RunCli(cliPath string, configPath string, storageType string, subcommand st
 		subcommand,
 	}
 	cmdArgs = append(cmdArgs, args...)
-	command := exec.Command(cliPath, cmdArgs...)
-	gexecSession, err := gexec.Start(command, ginkgo.GinkgoWriter, ginkgo.GinkgoWriter)
-	if err != nil {
-		return nil, err
-	}
-	gexecSession.Wait(1 * time.Minute)
-	return gexecSession, nil
+	return clitest.Run(cliPath, cmdArgs...)
 }
@@ -0,0 +1,20 @@
+package integration_test
+
+import (
+	"github.com/cloudfoundry/storage-cli/azurebs/integration"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RunCli", func() {
+	It("rejects a call that is missing the storage type", func() {
+		_, err := integration.RunCli(cliPath, "config.json", "delete-recursive", "")
+		Expect(err).To(MatchError(ContainSubstring(`storage type must be "azurebs", got "delete-recursive"`)))
+	})
+
+	It("rejects a call without a subcommand", func() {
+		_, err := integration.RunCli(cliPath, "config.json", "azurebs", "")
+		Expect(err).To(MatchError("RunCli: no subcommand given"))
+	})
+})
@@ -0,0 +1,39 @@
+// Package clitest runs a built storage-cli binary for the integration suites
+// of the individual backends.
+package clitest
+
+import (
+	"os/exec"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega/gexec"
+)
+
+// Timeout is how long Run waits for the CLI to exit before failing the spec.
+const Timeout = 1 * time.Minute
+
+// Result holds what a finished CLI invocation printed and how it exited.
+type Result struct {
+	Stdout   []byte
+	Stderr   []byte
+	ExitCode int
+}
+
+// Run starts cliPath with args, waits for it to exit and returns its output
+// and exit code. The output is also streamed to the GinkgoWriter so that it
+// shows up next to failing specs.
+func Run(cliPath string, args ...string) (*Result, error) {
+	command := exec.Command(cliPath, args...)
+	session, err := gexec.Start(command, ginkgo.GinkgoWriter, ginkgo.GinkgoWriter)
+	if err != nil {
+		return nil, err
+	}
+	session.Wait(Timeout)
+
+	return &Result{
+		Stdout:   session.Out.Contents(),
+		Stderr:   session.Err.Contents(),
+		ExitCode: session.ExitCode(),
+	}, nil
+}
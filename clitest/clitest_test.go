@@ -0,0 +1,32 @@
+package clitest_test
+
+import (
+	"github.com/cloudfoundry/storage-cli/clitest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Run", func() {
+	It("returns stdout, stderr and the exit code of the command", func() {
+		result, err := clitest.Run("sh", "-c", "echo out; echo err >&2; exit 3")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(string(result.Stdout)).To(Equal("out\n"))
+		Expect(string(result.Stderr)).To(Equal("err\n"))
+		Expect(result.ExitCode).To(Equal(3))
+	})
+
+	It("passes the arguments through unchanged", func() {
+		result, err := clitest.Run("sh", "-c", `printf '%s|' "$@"`, "sh", "-c", "config.json", "has space")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(string(result.Stdout)).To(Equal("-c|config.json|has space|"))
+		Expect(result.ExitCode).To(Equal(0))
+	})
+
+	It("returns an error when the binary cannot be started", func() {
+		_, err := clitest.Run("/does/not/exist/storage-cli")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,139 @@
+package common
+
+import (
+	"context"
+	"io"
+
+	"golang.org/x/time/rate"
+)
+
+// UploadSource is the part of *os.File the backends read uploads from
+type UploadSource interface {
+	io.ReadSeekCloser
+	io.ReaderAt
+}
+
+// DownloadTarget is the part of *os.File the backends write downloads to
+type DownloadTarget interface {
+	io.Writer
+	io.WriterAt
+}
+
+// BandwidthLimiter caps the bytes per second read from upload sources and written to download
+// targets wrapped with NewThrottledReader and NewThrottledWriter. All of them share the one limit,
+// so concurrent parts of a transfer, or concurrent transfers, don't add up to more than it.
+// A nil limiter doesn't limit.
+type BandwidthLimiter struct {
+	limiter *rate.Limiter
+}
+
+// NewBandwidthLimiter returns a limiter allowing bytesPerSec, or nil for zero or a negative value
+func NewBandwidthLimiter(bytesPerSec int64) *BandwidthLimiter {
+	if bytesPerSec <= 0 {
+		return nil
+	}
+	return &BandwidthLimiter{limiter: rate.NewLimiter(rate.Limit(bytesPerSec), int(bytesPerSec))}
+}
+
+// NewThrottledReader paces reads from src to the limit of bandwidth, a read waiting for the
+// limit fails once ctx is done. Without a limit src is returned as is.
+func NewThrottledReader(ctx context.Context, bandwidth *BandwidthLimiter, src UploadSource) UploadSource {
+	if bandwidth == nil {
+		return src
+	}
+	return &throttledReader{ctx: ctx, src: src, limiter: bandwidth.limiter}
+}
+
+// NewThrottledWriter paces writes to dst to the limit of bandwidth, a write waiting for the
+// limit fails once ctx is done. Without a limit dst is returned as is.
+func NewThrottledWriter(ctx context.Context, bandwidth *BandwidthLimiter, dst DownloadTarget) DownloadTarget {
+	if bandwidth == nil {
+		return dst
+	}
+	return &throttledWriter{ctx: ctx, dst: dst, limiter: bandwidth.limiter}
+}
+
+type throttledReader struct {
+	ctx     context.Context
+	src     UploadSource
+	limiter *rate.Limiter
+}
+
+func (t *throttledReader) Read(p []byte) (int, error) {
+	p = limitChunk(t.limiter, p)
+	n, err := t.src.Read(p)
+	return n, waitForBandwidth(t.ctx, t.limiter, n, err)
+}
+
+func (t *throttledReader) ReadAt(p []byte, off int64) (int, error) {
+	p = limitChunk(t.limiter, p)
+	n, err := t.src.ReadAt(p, off)
+	return n, waitForBandwidth(t.ctx, t.limiter, n, err)
+}
+
+func (t *throttledReader) Seek(offset int64, whence int) (int64, error) {
+	return t.src.Seek(offset, whence)
+}
+
+func (t *throttledReader) Close() error {
+	return t.src.Close()
+}
+
+type throttledWriter struct {
+	ctx     context.Context
+	dst     DownloadTarget
+	limiter *rate.Limiter
+}
+
+func (t *throttledWriter) Write(p []byte) (int, error) {
+	var written int
+	for len(p) > 0 {
+		chunk := limitChunk(t.limiter, p)
+		if err := t.limiter.WaitN(t.ctx, len(chunk)); err != nil {
+			return written, err
+		}
+		n, err := t.dst.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+func (t *throttledWriter) WriteAt(p []byte, off int64) (int, error) {
+	var written int
+	for len(p) > 0 {
+		chunk := limitChunk(t.limiter, p)
+		if err := t.limiter.WaitN(t.ctx, len(chunk)); err != nil {
+			return written, err
+		}
+		n, err := t.dst.WriteAt(chunk, off+int64(written))
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// limitChunk shortens p to the limiter's burst, WaitN refuses to wait for more than that at once
+func limitChunk(limiter *rate.Limiter, p []byte) []byte {
+	if len(p) > limiter.Burst() {
+		return p[:limiter.Burst()]
+	}
+	return p
+}
+
+// waitForBandwidth blocks until n bytes may pass. A read error takes precedence
+// so that io.EOF reaches the caller unchanged.
+func waitForBandwidth(ctx context.Context, limiter *rate.Limiter, n int, readErr error) error {
+	if n > 0 {
+		if err := limiter.WaitN(ctx, n); err != nil && readErr == nil {
+			return err
+		}
+	}
+	return readErr
+}
@@ -0,0 +1,64 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bandwidth", func() {
+	const bytesPerSec = 200 * 1024
+
+	var file *os.File
+
+	BeforeEach(func() {
+		var err error
+		file, err = os.Create(filepath.Join(GinkgoT().TempDir(), "transfer"))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(file.Close)
+	})
+
+	It("leaves readers and writers alone without a limit", func() {
+		Expect(NewBandwidthLimiter(0)).To(BeNil())
+		Expect(NewThrottledReader(context.Background(), nil, file)).To(BeIdenticalTo(file))
+		Expect(NewThrottledWriter(context.Background(), nil, file)).To(BeIdenticalTo(file))
+	})
+
+	It("paces uploads to the configured rate", func() {
+		// the limiter allows one second's worth up front, the remaining 1.5s are paced
+		content := bytes.Repeat([]byte("a"), bytesPerSec*5/2)
+		_, err := file.Write(content)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = file.Seek(0, io.SeekStart)
+		Expect(err).ToNot(HaveOccurred())
+
+		start := time.Now()
+		read, err := io.ReadAll(NewThrottledReader(context.Background(), NewBandwidthLimiter(bytesPerSec), file))
+		elapsed := time.Since(start)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(read).To(Equal(content))
+		Expect(elapsed).To(BeNumerically("~", 1500*time.Millisecond, 300*time.Millisecond))
+	})
+
+	It("paces downloads to the configured rate", func() {
+		content := bytes.Repeat([]byte("a"), bytesPerSec*5/2)
+
+		writer := NewThrottledWriter(context.Background(), NewBandwidthLimiter(bytesPerSec), file)
+		start := time.Now()
+		_, err := writer.WriteAt(content[:bytesPerSec], 0)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = io.Copy(io.NewOffsetWriter(writer, bytesPerSec), bytes.NewReader(content[bytesPerSec:]))
+		elapsed := time.Since(start)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(elapsed).To(BeNumerically("~", 1500*time.Millisecond, 300*time.Millisecond))
+		Expect(os.ReadFile(file.Name())).To(Equal(content))
+	})
+})
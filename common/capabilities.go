@@ -0,0 +1,27 @@
+package common
+
+// Capabilities the backends report with Capabilities(). Each is named after the command, or the
+// command and flag, it enables, so orchestrators can check for it before issuing the command.
+const (
+	CapabilityPut                 = "put"
+	CapabilityPutWithETag         = "put --print-etag"
+	CapabilityPutWithManifest     = "put --manifest"
+	CapabilityGet                 = "get"
+	CapabilityGetRange            = "get --continue"
+	CapabilityDelete              = "delete"
+	CapabilityDeleteRecursive     = "delete-recursive"
+	CapabilityExists              = "exists"
+	CapabilitySize                = "size"
+	CapabilitySign                = "sign"
+	CapabilitySignContent         = "sign --content-type --content-md5"
+	CapabilitySignStartAt         = "sign --start-at"
+	CapabilityList                = "list"
+	CapabilityCopy                = "copy"
+	CapabilityCopyFromBucket      = "copy --source-bucket"
+	CapabilityCopyToBucket        = "copy --dest-bucket"
+	CapabilityRename              = "rename"
+	CapabilityProperties          = "properties"
+	CapabilityHead                = "head"
+	CapabilityEnsureStorageExists = "ensure-storage-exists"
+	CapabilityIdentity            = "whoami"
+)
@@ -0,0 +1,41 @@
+package common
+
+// ConcurrencyLimiter bounds the number of operations running at once. A single limiter is meant
+// to be shared by everything that transfers in parallel, e.g. the files of a sync and the parts of
+// their multipart uploads, which get it with PutOptions.Concurrency, so that together they stay
+// within one budget. Only the requests themselves should hold a slot: an operation that holds one
+// while waiting for others to acquire theirs can deadlock once the budget is used up. A nil
+// limiter doesn't limit anything.
+type ConcurrencyLimiter struct {
+	slots chan struct{}
+}
+
+// NewConcurrencyLimiter returns a limiter that lets limit operations run at once. Zero or a
+// negative limit doesn't limit them at all.
+func NewConcurrencyLimiter(limit int) *ConcurrencyLimiter {
+	if limit <= 0 {
+		return &ConcurrencyLimiter{}
+	}
+	return &ConcurrencyLimiter{slots: make(chan struct{}, limit)}
+}
+
+// Acquire blocks until a slot is free and takes it
+func (l *ConcurrencyLimiter) Acquire() {
+	if l != nil && l.slots != nil {
+		l.slots <- struct{}{}
+	}
+}
+
+// Release frees a slot taken with Acquire
+func (l *ConcurrencyLimiter) Release() {
+	if l != nil && l.slots != nil {
+		<-l.slots
+	}
+}
+
+// Do runs fn in a slot
+func (l *ConcurrencyLimiter) Do(fn func() error) error {
+	l.Acquire()
+	defer l.Release()
+	return fn()
+}
@@ -0,0 +1,20 @@
+package common
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ConcurrencyLimiter", func() {
+	It("doesn't limit anything when nil", func() {
+		var limiter *ConcurrencyLimiter
+		Expect(limiter.Do(func() error { return nil })).To(Succeed())
+	})
+
+	It("passes on the error of the operation", func() {
+		err := NewConcurrencyLimiter(1).Do(func() error { return errors.New("boom") })
+		Expect(err).To(MatchError("boom"))
+	})
+})
@@ -1,12 +1,16 @@
 package common
 
 import (
+	"io"
+	"log"
 	"log/slog"
+	"os"
 	"sync"
 )
 
 type Config struct {
-	LogLevel slog.Level
+	LogLevel  slog.Level
+	LogWriter io.Writer
 }
 
 var (
@@ -14,9 +18,18 @@ var (
 	once     sync.Once
 )
 
-func InitConfig(logLevel slog.Level) {
+// InitConfig sets the log level and the writer all logging goes to, nil means os.Stderr. Both the
+// default slog logger, as JSON, and the standard log package write to it, so that stdout is left
+// for command results and, given a log file, stderr stays clean.
+func InitConfig(logLevel slog.Level, logWriter io.Writer) {
 	once.Do(func() {
-		instance = &Config{LogLevel: logLevel}
+		if logWriter == nil {
+			logWriter = os.Stderr
+		}
+		instance = &Config{LogLevel: logLevel, LogWriter: logWriter}
+
+		slog.SetDefault(slog.New(slog.NewJSONHandler(logWriter, &slog.HandlerOptions{Level: logLevel})))
+		log.SetOutput(logWriter)
 	})
 }
 
@@ -33,3 +46,9 @@ func IsDebug() bool {
 	}
 	return false
 }
+
+// ResetConfig forgets the config set by InitConfig so that it can be initialized again
+func ResetConfig() {
+	instance = nil
+	once = sync.Once{}
+}
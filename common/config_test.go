@@ -1,7 +1,11 @@
 package common
 
 import (
+	"bytes"
+	"io"
+	"log"
 	"log/slog"
+	"os"
 	"sync"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -21,7 +25,7 @@ var _ = Describe("Storage-CLI Config", func() {
 
 	Context("when initialized with 'debug' level", func() {
 		BeforeEach(func() {
-			InitConfig(slog.LevelDebug)
+			InitConfig(slog.LevelDebug, io.Discard)
 		})
 
 		It("IsDebug returns true", func() {
@@ -38,9 +42,50 @@ var _ = Describe("Storage-CLI Config", func() {
 		})
 	})
 
+	Context("when initialized with a log writer", func() {
+		var logs *bytes.Buffer
+
+		BeforeEach(func() {
+			logs = &bytes.Buffer{}
+			defaultLogger := slog.Default()
+			DeferCleanup(func() {
+				slog.SetDefault(defaultLogger)
+				log.SetOutput(os.Stderr)
+				ResetConfig()
+			})
+			InitConfig(slog.LevelInfo, logs)
+		})
+
+		It("sends slog and the standard log package to it", func() {
+			slog.Info("some slog message")
+			slog.Debug("some debug message")
+			log.Print("some log message")
+
+			Expect(logs.String()).To(ContainSubstring(`"msg":"some slog message"`))
+			Expect(logs.String()).ToNot(ContainSubstring("some debug message"))
+			Expect(logs.String()).To(ContainSubstring("some log message"))
+			Expect(GetConfig().LogWriter).To(BeIdenticalTo(logs))
+		})
+	})
+
+	Context("when initialized without a log writer", func() {
+		BeforeEach(func() {
+			defaultLogger := slog.Default()
+			DeferCleanup(func() {
+				slog.SetDefault(defaultLogger)
+				ResetConfig()
+			})
+			InitConfig(slog.LevelInfo, nil)
+		})
+
+		It("logs to stderr", func() {
+			Expect(GetConfig().LogWriter).To(BeIdenticalTo(os.Stderr))
+		})
+	})
+
 	Context("when initialized with 'info' level", func() {
 		BeforeEach(func() {
-			InitConfig(slog.LevelInfo)
+			InitConfig(slog.LevelInfo, io.Discard)
 		})
 
 		It("IsDebug returns false", func() {
@@ -0,0 +1,13 @@
+package common
+
+// CopyOptions change how an object is copied
+type CopyOptions struct {
+	// ResetMetadata starts the copy without the source's metadata instead of preserving it
+	ResetMetadata bool
+	// SourceSAS is a SAS token that authorizes reading the source of the copy, e.g. a blob of
+	// another Azure storage account. Empty leaves the source URL as it is.
+	SourceSAS string
+	// NoMultipart copies the object with a single copy request whatever its size, leaving large
+	// copies to the provider instead of copying them in parts
+	NoMultipart bool
+}
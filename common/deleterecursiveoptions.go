@@ -0,0 +1,19 @@
+package common
+
+// DeleteRecursiveOptions change how the objects under a prefix are deleted
+type DeleteRecursiveOptions struct {
+	// ContinueOnError keeps deleting the remaining objects when one fails and reports all
+	// failures at the end, instead of stopping at the first one
+	ContinueOnError bool
+	// Concurrency is how many delete requests are sent at the same time. Zero leaves it to the
+	// backend's default.
+	Concurrency int
+}
+
+// ConcurrencyOr returns Concurrency, or defaultConcurrency if none was set
+func (o DeleteRecursiveOptions) ConcurrencyOr(defaultConcurrency int) int {
+	if o.Concurrency > 0 {
+		return o.Concurrency
+	}
+	return defaultConcurrency
+}
@@ -0,0 +1,104 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"sync"
+)
+
+// DownloadChecksum hashes a download while the backend writes it, so that verifying it doesn't
+// need another pass over the file. Parts written ahead of the hashed position, as by concurrent
+// downloads, are read back from the target once the bytes before them have arrived.
+type DownloadChecksum struct {
+	mu      sync.Mutex
+	hash    hash.Hash
+	hashed  int64
+	pending map[int64]int64 // length of the parts written ahead by their offset
+	target  io.ReaderAt
+	err     error
+}
+
+// NewDownloadChecksum returns a DownloadChecksum computing h over the download
+func NewDownloadChecksum(h hash.Hash) *DownloadChecksum {
+	return &DownloadChecksum{hash: h, pending: map[int64]int64{}}
+}
+
+// NewChecksummingWriter feeds everything written to dst into checksum. Without a checksum dst
+// is returned as is.
+func NewChecksummingWriter(checksum *DownloadChecksum, dst DownloadTarget) DownloadTarget {
+	if checksum == nil {
+		return dst
+	}
+	checksum.mu.Lock()
+	defer checksum.mu.Unlock()
+	if checksum.target != nil {
+		checksum.err = errors.New("more than one download target")
+	}
+	checksum.target, _ = dst.(io.ReaderAt)
+	return &checksummingWriter{dst: dst, checksum: checksum}
+}
+
+// Sum returns the checksum of the download if exactly size bytes were hashed without gaps.
+// Otherwise the download wasn't hashed completely, e.g. because the backend doesn't support
+// it or wrote parts of it twice, and the reason is returned.
+func (c *DownloadChecksum) Sum(size int64) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return nil, c.err
+	}
+	if c.hashed != size || len(c.pending) > 0 {
+		return nil, fmt.Errorf("hashed %d of %d bytes", c.hashed, size)
+	}
+	return c.hash.Sum(nil), nil
+}
+
+// written accounts for p having been written at off
+func (c *DownloadChecksum) written(p []byte, off int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil || len(p) == 0 {
+		return
+	}
+
+	if off != c.hashed {
+		if _, ok := c.pending[off]; ok || off < c.hashed || c.target == nil {
+			c.err = fmt.Errorf("bytes at offset %d were written out of order", off)
+			return
+		}
+		c.pending[off] = int64(len(p))
+		return
+	}
+
+	c.hash.Write(p) //nolint:errcheck
+	c.hashed += int64(len(p))
+	for length, ok := c.pending[c.hashed]; ok; length, ok = c.pending[c.hashed] {
+		delete(c.pending, c.hashed)
+		if _, err := io.Copy(c.hash, io.NewSectionReader(c.target, c.hashed, length)); err != nil {
+			c.err = fmt.Errorf("failed to read back bytes at offset %d: %w", c.hashed, err)
+			return
+		}
+		c.hashed += length
+	}
+}
+
+type checksummingWriter struct {
+	dst      DownloadTarget
+	checksum *DownloadChecksum
+	offset   int64 // where Write continues
+}
+
+func (c *checksummingWriter) Write(p []byte) (int, error) {
+	n, err := c.dst.Write(p)
+	c.checksum.written(p[:n], c.offset)
+	c.offset += int64(n)
+	return n, err
+}
+
+func (c *checksummingWriter) WriteAt(p []byte, off int64) (int, error) {
+	n, err := c.dst.WriteAt(p, off)
+	c.checksum.written(p[:n], off)
+	return n, err
+}
@@ -0,0 +1,77 @@
+package common
+
+import (
+	"bytes"
+	"crypto/md5"
+	"io"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DownloadChecksum", func() {
+	content := []byte("the quick brown fox jumps over the lazy dog")
+	expected := md5.Sum(content)
+
+	var (
+		file     *os.File
+		checksum *DownloadChecksum
+	)
+
+	BeforeEach(func() {
+		var err error
+		file, err = os.Create(filepath.Join(GinkgoT().TempDir(), "download"))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(file.Close)
+
+		checksum = NewDownloadChecksum(md5.New())
+	})
+
+	It("leaves writers alone without a checksum", func() {
+		Expect(NewChecksummingWriter(nil, file)).To(BeIdenticalTo(file))
+	})
+
+	It("hashes a download written in sequence", func() {
+		_, err := io.Copy(NewChecksummingWriter(checksum, file), bytes.NewReader(content))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(checksum.Sum(int64(len(content)))).To(Equal(expected[:]))
+	})
+
+	It("hashes parts written ahead once the bytes before them arrive", func() {
+		writer := NewChecksummingWriter(checksum, file)
+
+		for _, part := range [][2]int{{30, 43}, {10, 20}, {20, 30}, {0, 10}} {
+			_, err := writer.WriteAt(content[part[0]:part[1]], int64(part[0]))
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		Expect(checksum.Sum(int64(len(content)))).To(Equal(expected[:]))
+	})
+
+	It("refuses to report the checksum of an incomplete download", func() {
+		writer := NewChecksummingWriter(checksum, file)
+
+		_, err := writer.WriteAt(content[:10], 0)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = writer.WriteAt(content[20:], 20)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = checksum.Sum(int64(len(content)))
+		Expect(err).To(MatchError("hashed 10 of 43 bytes"))
+	})
+
+	It("refuses to report the checksum when bytes are written twice", func() {
+		writer := NewChecksummingWriter(checksum, file)
+
+		_, err := writer.WriteAt(content, 0)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = writer.WriteAt(content[:10], 0)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = checksum.Sum(int64(len(content)))
+		Expect(err).To(MatchError("bytes at offset 0 were written out of order"))
+	})
+})
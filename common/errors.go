@@ -0,0 +1,15 @@
+package common
+
+import "errors"
+
+// ErrAccessDenied marks a request the provider refused with 403. Buckets that hide which keys
+// exist answer 403 for missing objects too, so callers may choose to treat it as not found.
+var ErrAccessDenied = errors.New("access denied")
+
+// ErrPreconditionFailed marks a ranged download of an object that no longer matches
+// GetOptions.IfMatch or GetOptions.IfGenerationMatch, i.e. one that changed since it was started
+var ErrPreconditionFailed = errors.New("object changed since the download started")
+
+// ErrObjectNotFound is returned for an object that doesn't exist by the operations that report on it
+// instead of failing, like Properties and Head
+var ErrObjectNotFound = errors.New("object not found")
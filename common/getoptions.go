@@ -0,0 +1,15 @@
+package common
+
+// GetOptions change how an object is downloaded
+type GetOptions struct {
+	// Bandwidth limits the download, nil doesn't limit it
+	Bandwidth *BandwidthLimiter
+	// Checksum is fed the download while it is written, nil doesn't hash it
+	Checksum *DownloadChecksum
+	// IfMatch is the ETag the object must still have for a ranged download to continue, empty
+	// doesn't check it (s3 and azurebs)
+	IfMatch string
+	// IfGenerationMatch is the generation, as head reports it in version_id, the object must still
+	// have for a ranged download to continue, empty doesn't check it (gcs)
+	IfGenerationMatch string
+}
@@ -0,0 +1,8 @@
+package common
+
+// Identity describes the credentials a storage client resolved to. It is
+// meant for troubleshooting and must never carry secret material.
+type Identity struct {
+	CredentialsSource string `json:"credentials_source"`
+	Principal         string `json:"principal,omitempty"`
+}
@@ -0,0 +1,81 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// maxPartNumber is the highest part number a multipart upload accepts
+const maxPartNumber = 10000
+
+// UploadPart describes one part of a manifest-driven multipart upload as a byte range of the source file
+type UploadPart struct {
+	PartNumber int32 `json:"part_number"`
+	Offset     int64 `json:"offset"`
+	Size       int64 `json:"size"`
+}
+
+// UploadManifest lists the parts a file is uploaded in. Parts may be listed in any order,
+// the uploaded object is always assembled in part number order.
+type UploadManifest struct {
+	Parts []UploadPart `json:"parts"`
+}
+
+// NewUploadManifestFromReader returns the manifest described by the JSON contents of reader
+func NewUploadManifestFromReader(reader io.Reader) (UploadManifest, error) {
+	bytes, err := io.ReadAll(reader)
+	if err != nil {
+		return UploadManifest{}, err
+	}
+
+	var manifest UploadManifest
+	if err := json.Unmarshal(bytes, &manifest); err != nil {
+		return UploadManifest{}, err
+	}
+	return manifest, nil
+}
+
+// SortedParts returns the parts ordered by part number
+func (m UploadManifest) SortedParts() []UploadPart {
+	parts := append([]UploadPart(nil), m.Parts...)
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].PartNumber < parts[j].PartNumber
+	})
+	return parts
+}
+
+// Validate checks that the parts, taken in part number order, cover a file of fileSize bytes
+// exactly once, so that the assembled object is identical to the file
+func (m UploadManifest) Validate(fileSize int64) error {
+	if len(m.Parts) == 0 {
+		return errors.New("manifest must list at least one part")
+	}
+
+	var next int64
+	var previous int32
+	for _, part := range m.SortedParts() {
+		if part.PartNumber < 1 || part.PartNumber > maxPartNumber {
+			return fmt.Errorf("part number %d must be between 1 and %d", part.PartNumber, maxPartNumber)
+		}
+		if part.PartNumber == previous {
+			return fmt.Errorf("part number %d is listed more than once", part.PartNumber)
+		}
+		if part.Size <= 0 {
+			return fmt.Errorf("part %d must have a positive size", part.PartNumber)
+		}
+		if part.Offset != next {
+			return fmt.Errorf("part %d starts at offset %d but the previous part ends at %d", part.PartNumber, part.Offset, next)
+		}
+
+		previous = part.PartNumber
+		next = part.Offset + part.Size
+	}
+
+	if next != fileSize {
+		return fmt.Errorf("parts cover %d bytes but the file is %d bytes", next, fileSize)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package common
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UploadManifest", func() {
+	It("parses the parts from JSON", func() {
+		manifest, err := NewUploadManifestFromReader(strings.NewReader(`{"parts": [
+			{"part_number": 2, "offset": 5, "size": 3},
+			{"part_number": 1, "offset": 0, "size": 5}
+		]}`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Parts).To(HaveLen(2))
+		Expect(manifest.SortedParts()).To(Equal([]UploadPart{
+			{PartNumber: 1, Offset: 0, Size: 5},
+			{PartNumber: 2, Offset: 5, Size: 3},
+		}))
+	})
+
+	It("accepts parts covering the file exactly", func() {
+		manifest := UploadManifest{Parts: []UploadPart{
+			{PartNumber: 2, Offset: 5, Size: 3},
+			{PartNumber: 1, Offset: 0, Size: 5},
+		}}
+		Expect(manifest.Validate(8)).To(Succeed())
+	})
+
+	DescribeTable("rejects invalid manifests",
+		func(parts []UploadPart, expectedError string) {
+			Expect(UploadManifest{Parts: parts}.Validate(8)).To(MatchError(ContainSubstring(expectedError)))
+		},
+		Entry("without parts", nil, "at least one part"),
+		Entry("with part number 0", []UploadPart{{PartNumber: 0, Offset: 0, Size: 8}}, "must be between 1 and 10000"),
+		Entry("with a duplicate part number", []UploadPart{{PartNumber: 1, Offset: 0, Size: 4}, {PartNumber: 1, Offset: 4, Size: 4}}, "listed more than once"),
+		Entry("with an empty part", []UploadPart{{PartNumber: 1, Offset: 0, Size: 0}}, "positive size"),
+		Entry("with a gap", []UploadPart{{PartNumber: 1, Offset: 0, Size: 4}, {PartNumber: 2, Offset: 5, Size: 3}}, "previous part ends at 4"),
+		Entry("with overlapping parts", []UploadPart{{PartNumber: 1, Offset: 0, Size: 5}, {PartNumber: 2, Offset: 4, Size: 4}}, "previous part ends at 5"),
+		Entry("not covering the whole file", []UploadPart{{PartNumber: 1, Offset: 0, Size: 4}}, "parts cover 4 bytes but the file is 8 bytes"),
+	)
+})
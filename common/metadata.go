@@ -0,0 +1,5 @@
+package common
+
+// MD5MetadataKey is the user metadata key `put --store-md5` stores the hex encoded MD5 of the
+// uploaded file under. Unlike the ETag it is the MD5 of the content for multipart uploads too.
+const MD5MetadataKey = "md5"
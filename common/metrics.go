@@ -0,0 +1,75 @@
+package common
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Stats summarizes the work done during a single storage-cli run
+type Stats struct {
+	BytesTransferred int64 `json:"bytes_transferred"`
+	Requests         int64 `json:"requests"`
+	Retries          int64 `json:"retries"`
+	ElapsedMs        int64 `json:"elapsed_ms"`
+}
+
+type metrics struct {
+	enabled          atomic.Bool
+	bytesTransferred atomic.Int64
+	requests         atomic.Int64
+	retries          atomic.Int64
+	start            atomic.Int64 // unix nanoseconds
+}
+
+var collector = newMetrics()
+
+func newMetrics() *metrics {
+	m := &metrics{}
+	m.start.Store(time.Now().UnixNano())
+	return m
+}
+
+// EnableStats makes backends that only hook into the HTTP layer when needed
+// install the transports which feed the request and byte counters
+func EnableStats() {
+	collector.enabled.Store(true)
+}
+
+func IsStatsEnabled() bool {
+	return collector.enabled.Load()
+}
+
+// AddBytesTransferred records payload bytes sent or received. Non-positive
+// values, e.g. an unknown content length, are ignored.
+func AddBytesTransferred(n int64) {
+	if n > 0 {
+		collector.bytesTransferred.Add(n)
+	}
+}
+
+func IncRequests() {
+	collector.requests.Add(1)
+}
+
+func IncRetries() {
+	collector.retries.Add(1)
+}
+
+// GetStats returns the counters collected so far and the time elapsed since
+// the process started or the counters were last reset
+func GetStats() Stats {
+	return Stats{
+		BytesTransferred: collector.bytesTransferred.Load(),
+		Requests:         collector.requests.Load(),
+		Retries:          collector.retries.Load(),
+		ElapsedMs:        time.Since(time.Unix(0, collector.start.Load())).Milliseconds(),
+	}
+}
+
+// ResetStats zeroes all counters and restarts the elapsed time
+func ResetStats() {
+	collector.bytesTransferred.Store(0)
+	collector.requests.Store(0)
+	collector.retries.Store(0)
+	collector.start.Store(time.Now().UnixNano())
+}
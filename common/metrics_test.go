@@ -0,0 +1,53 @@
+package common
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Metrics", func() {
+	BeforeEach(func() {
+		ResetStats()
+	})
+
+	It("starts with all counters at zero", func() {
+		stats := GetStats()
+		Expect(stats.BytesTransferred).To(BeZero())
+		Expect(stats.Requests).To(BeZero())
+		Expect(stats.Retries).To(BeZero())
+	})
+
+	It("reflects a sequence of operations", func() {
+		// a failed upload, its retry and a download
+		IncRequests()
+		AddBytesTransferred(100)
+		IncRetries()
+		IncRequests()
+		AddBytesTransferred(100)
+		IncRequests()
+		AddBytesTransferred(-1)
+		AddBytesTransferred(42)
+		time.Sleep(2 * time.Millisecond)
+
+		stats := GetStats()
+		Expect(stats.Requests).To(BeEquivalentTo(3))
+		Expect(stats.Retries).To(BeEquivalentTo(1))
+		Expect(stats.BytesTransferred).To(BeEquivalentTo(242))
+		Expect(stats.ElapsedMs).To(BeNumerically(">=", 2))
+	})
+
+	It("zeroes the counters on reset", func() {
+		IncRequests()
+		IncRetries()
+		AddBytesTransferred(10)
+
+		ResetStats()
+
+		stats := GetStats()
+		Expect(stats.BytesTransferred).To(BeZero())
+		Expect(stats.Requests).To(BeZero())
+		Expect(stats.Retries).To(BeZero())
+	})
+})
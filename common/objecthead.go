@@ -0,0 +1,45 @@
+package common
+
+import "time"
+
+// ObjectHead is everything a provider reports about an object when asked for it directly,
+// as printed by the head command. Attributes a provider doesn't report are left out.
+type ObjectHead struct {
+	ETag               string            `json:"etag,omitempty"`
+	LastModified       time.Time         `json:"last_modified,omitzero"`
+	ContentLength      int64             `json:"content_length"`
+	ContentType        string            `json:"content_type,omitempty"`
+	ContentEncoding    string            `json:"content_encoding,omitempty"`
+	ContentDisposition string            `json:"content_disposition,omitempty"`
+	ContentLanguage    string            `json:"content_language,omitempty"`
+	CacheControl       string            `json:"cache_control,omitempty"`
+	ContentMD5         string            `json:"content_md5,omitempty"` // base64 encoded
+	StorageClass       string            `json:"storage_class,omitempty"`
+	VersionID          string            `json:"version_id,omitempty"`
+	Metadata           map[string]string `json:"metadata,omitempty"`
+	Encryption         *ObjectEncryption `json:"encryption,omitempty"`
+	// Checksums of the whole object by algorithm, base64 encoded like ContentMD5. Checksums
+	// combined from those of the parts of a multipart upload aren't included.
+	Checksums map[string]string `json:"checksums,omitempty"`
+}
+
+// Algorithms of the checksums in ObjectHead
+const (
+	ChecksumMD5       = "md5"
+	ChecksumCRC32     = "crc32"
+	ChecksumCRC32C    = "crc32c"
+	ChecksumCRC64NVME = "crc64nvme"
+	ChecksumCRC64ECMA = "crc64ecma"
+	ChecksumSHA1      = "sha1"
+	ChecksumSHA256    = "sha256"
+)
+
+// ObjectEncryption describes how the provider encrypts an object at rest
+type ObjectEncryption struct {
+	// Algorithm is the server-side encryption as the provider names it, e.g. AES256 or aws:kms
+	Algorithm string `json:"algorithm,omitempty"`
+	// KeyID is the KMS key, or for Azure the encryption scope, the object is encrypted with
+	KeyID string `json:"key_id,omitempty"`
+	// CustomerKeySHA256 is the hash of a customer-provided key the object is encrypted with
+	CustomerKeySHA256 string `json:"customer_key_sha256,omitempty"`
+}
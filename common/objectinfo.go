@@ -0,0 +1,11 @@
+package common
+
+import "time"
+
+// ObjectInfo is an entry of a detailed listing: the key of an object along with
+// the attributes the provider returns with the listing, without a request per object.
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
@@ -0,0 +1,24 @@
+package common
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ObjectProperties is the document properties prints for an object, the same for every provider.
+// The fields are sorted by their JSON names, attributes a provider doesn't report are left out.
+type ObjectProperties struct {
+	AccessTier    string            `json:"access_tier,omitempty"` // azurebs only
+	ContentLength int64             `json:"content_length"`
+	ContentMD5    string            `json:"content_md5,omitempty"` // base64 encoded
+	ETag          string            `json:"etag,omitempty"`
+	LastModified  time.Time         `json:"last_modified,omitzero"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+}
+
+// MarshalObjectProperties returns properties as JSON indented by two spaces. The last modification
+// is given in UTC to the second, the precision all providers report it with.
+func MarshalObjectProperties(properties ObjectProperties) ([]byte, error) {
+	properties.LastModified = properties.LastModified.UTC().Truncate(time.Second)
+	return json.MarshalIndent(properties, "", "  ")
+}
@@ -0,0 +1,47 @@
+package common
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MarshalObjectProperties", func() {
+	It("prints the fields sorted, indented by two spaces", func() {
+		output, err := MarshalObjectProperties(ObjectProperties{
+			ETag:          "some-etag",
+			LastModified:  time.Date(2024, 3, 1, 12, 30, 45, 0, time.UTC),
+			ContentLength: 10,
+			ContentMD5:    "JfnnlDI7RTiF9RgfG2JNCw==",
+			AccessTier:    "Hot",
+			Metadata:      map[string]string{"owner": "team-a", "build_id": "42"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(output)).To(Equal(`{
+  "access_tier": "Hot",
+  "content_length": 10,
+  "content_md5": "JfnnlDI7RTiF9RgfG2JNCw==",
+  "etag": "some-etag",
+  "last_modified": "2024-03-01T12:30:45Z",
+  "metadata": {
+    "build_id": "42",
+    "owner": "team-a"
+  }
+}`))
+	})
+
+	It("gives the last modification in UTC to the second", func() {
+		output, err := MarshalObjectProperties(ObjectProperties{
+			LastModified: time.Date(2024, 3, 1, 13, 30, 45, 123000000, time.FixedZone("CET", 3600)),
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(output)).To(ContainSubstring(`"last_modified": "2024-03-01T12:30:45Z"`))
+	})
+
+	It("leaves out what isn't known but the content length", func() {
+		output, err := MarshalObjectProperties(ObjectProperties{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(output)).To(Equal("{\n  \"content_length\": 0\n}"))
+	})
+})
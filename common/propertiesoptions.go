@@ -0,0 +1,17 @@
+package common
+
+import "strings"
+
+// PropertiesOptions change how the properties of an object are reported
+type PropertiesOptions struct {
+	// RawETag keeps the ETag exactly as the provider returns it, including the surrounding quotes
+	RawETag bool
+}
+
+// ETag returns etag as it is reported with these options. By default the surrounding quotes are stripped.
+func (o PropertiesOptions) ETag(etag string) string {
+	if o.RawETag {
+		return etag
+	}
+	return strings.Trim(etag, `"`)
+}
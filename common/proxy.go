@@ -0,0 +1,42 @@
+package common
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ParseProxyURL validates the proxy_url of a configuration. Go's http.Transport dials
+// http, https and SOCKS5 proxies, an empty proxy_url is left to the environment.
+func ParseProxyURL(proxyURL string) (*url.URL, error) {
+	if proxyURL == "" {
+		return nil, nil
+	}
+
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy_url: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("invalid proxy_url '%s': scheme must be http, https, socks5 or socks5h", proxyURL)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy_url '%s': host must be set", proxyURL)
+	}
+	return u, nil
+}
+
+// ProxyFunc returns the Proxy of an http.Transport sending all requests through proxyURL,
+// or through the proxy HTTP_PROXY, HTTPS_PROXY and NO_PROXY select if it is empty
+func ProxyFunc(proxyURL string) (func(*http.Request) (*url.URL, error), error) {
+	u, err := ParseProxyURL(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return http.ProxyFromEnvironment, nil
+	}
+	return http.ProxyURL(u), nil
+}
@@ -0,0 +1,14 @@
+package common
+
+// PutOptions change how an object is uploaded
+type PutOptions struct {
+	// ContentType is the Content-Type the object is stored with. Empty leaves it to the provider's default.
+	ContentType string
+	// Metadata is the user metadata the object is stored with. A nil or empty map stores none.
+	Metadata map[string]string
+	// Bandwidth limits the upload, nil doesn't limit it
+	Bandwidth *BandwidthLimiter
+	// Concurrency is shared with the other uploads of a run. Every request of the upload, each part
+	// of a multipart upload on its own, takes a slot of it. nil doesn't limit the requests.
+	Concurrency *ConcurrencyLimiter
+}
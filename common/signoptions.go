@@ -0,0 +1,16 @@
+package common
+
+import "time"
+
+// SignOptions are headers a signed PUT URL is bound to. An upload to the URL has to send
+// them with exactly these values, otherwise the provider rejects it. StartAt applies to
+// both actions.
+type SignOptions struct {
+	// ContentType is the required Content-Type of the upload
+	ContentType string
+	// ContentMD5 is the required Content-MD5 of the upload, the base64 encoded MD5 of the body
+	ContentMD5 string
+	// StartAt is the time before which the URL is not valid, the expiration counts from it.
+	// The zero value makes the URL valid right away.
+	StartAt time.Time
+}
@@ -1,10 +1,13 @@
 package app
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/cloudfoundry/storage-cli/common"
 	davcmd "github.com/cloudfoundry/storage-cli/dav/cmd"
 	davconfig "github.com/cloudfoundry/storage-cli/dav/config"
 )
@@ -31,19 +34,35 @@ func (app *App) run(args []string) (err error) {
 	return
 }
 
-func (app *App) Put(sourceFilePath string, destinationObject string) error {
-	return app.run([]string{"put", sourceFilePath, destinationObject})
+// errBandwidthLimitNotSupported is returned for throttled transfers, the dav commands only
+// take the object and the file to transfer
+var errBandwidthLimitNotSupported = errors.New("--max-bandwidth is not supported by dav")
+
+func (app *App) Put(ctx context.Context, sourceFilePath string, destinationObject string, options common.PutOptions) error {
+	if options.Bandwidth != nil {
+		return errBandwidthLimitNotSupported
+	}
+	return options.Concurrency.Do(func() error {
+		return app.run([]string{"put", sourceFilePath, destinationObject})
+	})
 }
 
-func (app *App) Get(sourceObject string, dest string) error {
+func (app *App) Get(ctx context.Context, sourceObject string, dest string, options common.GetOptions) error {
+	if options.Bandwidth != nil {
+		return errBandwidthLimitNotSupported
+	}
 	return app.run([]string{"get", sourceObject, dest})
 }
 
-func (app *App) Delete(object string) error {
+func (app *App) GetRange(ctx context.Context, sourceObject string, dest string, offset int64, options common.GetOptions) error {
+	return errors.New("not implemented")
+}
+
+func (app *App) Delete(ctx context.Context, object string) error {
 	return app.run([]string{"delete", object})
 }
 
-func (app *App) Exists(object string) (bool, error) {
+func (app *App) Exists(ctx context.Context, object string) (bool, error) {
 	err := app.run([]string{"exists", object})
 	if err != nil {
 		return false, err
@@ -51,7 +70,7 @@ func (app *App) Exists(object string) (bool, error) {
 	return true, nil
 }
 
-func (app *App) Sign(object string, action string, expiration time.Duration) (string, error) {
+func (app *App) Sign(ctx context.Context, object string, action string, expiration time.Duration) (string, error) {
 	err := app.run([]string{"sign", object, action, expiration.String()})
 	if err != nil {
 		return "", err
@@ -59,22 +78,105 @@ func (app *App) Sign(object string, action string, expiration time.Duration) (st
 	return "", nil
 }
 
-func (app *App) List(prefix string) ([]string, error) {
+func (app *App) SignWithOptions(ctx context.Context, object string, action string, expiration time.Duration, options common.SignOptions) (string, error) {
+	return "", errors.New("signing with content type, MD5 or start time is not supported for dav")
+}
+
+// ValidateSign checks that a URL for action can be signed, which needs the secret of the config
+func (app *App) ValidateSign(ctx context.Context, object string, action string, expiration time.Duration, options common.SignOptions) error {
+	if options != (common.SignOptions{}) {
+		return errors.New("signing with content type, MD5 or start time is not supported for dav")
+	}
+	if app.config.Secret == "" {
+		return errors.New("signing URLs requires the secret")
+	}
+
+	action = strings.ToUpper(action)
+	if action != "GET" && action != "PUT" {
+		return fmt.Errorf("action not implemented: %s", action)
+	}
+	return nil
+}
+
+func (app *App) List(ctx context.Context, prefix string) ([]string, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (app *App) ListWithLimit(ctx context.Context, prefix string, limit int) ([]string, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (app *App) ListDetailed(ctx context.Context, prefix string) ([]common.ObjectInfo, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (app *App) ListDetailedWithLimit(ctx context.Context, prefix string, limit int) ([]common.ObjectInfo, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (app *App) Copy(srcBlob string, dstBlob string) error {
+func (app *App) Copy(ctx context.Context, srcBlob string, dstBlob string, options common.CopyOptions) error {
+	return errors.New("not implemented")
+}
+
+func (app *App) CopyFromBucket(ctx context.Context, srcBucket string, srcRegion string, srcBlob string, dstBlob string, options common.CopyOptions) error {
+	return errors.New("not implemented")
+}
+
+func (app *App) CopyToBucket(ctx context.Context, srcBlob string, dstBucket string, dstBlob string, options common.CopyOptions) error {
+	return errors.New("not implemented")
+}
+
+func (app *App) PutWithETag(ctx context.Context, sourceFilePath string, dest string, options common.PutOptions) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+func (app *App) PutWithManifest(ctx context.Context, sourceFilePath string, dest string, manifest common.UploadManifest, options common.PutOptions) error {
 	return errors.New("not implemented")
 }
 
-func (app *App) Properties(dest string) error {
+func (app *App) Size(ctx context.Context, dest string) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (app *App) Rename(ctx context.Context, srcBlob string, dstBlob string) error {
 	return errors.New("not implemented")
 }
 
-func (app *App) EnsureStorageExists() error {
+func (app *App) Properties(ctx context.Context, dest string) (common.ObjectProperties, error) {
+	return common.ObjectProperties{}, errors.New("not implemented")
+}
+
+func (app *App) PropertiesWithOptions(ctx context.Context, dest string, options common.PropertiesOptions) (common.ObjectProperties, error) {
+	return common.ObjectProperties{}, errors.New("not implemented")
+}
+
+func (app *App) Head(ctx context.Context, dest string) (common.ObjectHead, error) {
+	return common.ObjectHead{}, errors.New("not implemented")
+}
+
+func (app *App) EnsureStorageExists(ctx context.Context) error {
 	return errors.New("not implemented")
 }
 
-func (app *App) DeleteRecursive(prefix string) error {
+func (app *App) DeleteRecursive(ctx context.Context, prefix string, options common.DeleteRecursiveOptions) error {
 	return errors.New("not implemented")
 }
+
+// Capabilities lists the operations the dav client supports
+func (app *App) Capabilities() []string {
+	return []string{
+		common.CapabilityPut,
+		common.CapabilityGet,
+		common.CapabilityDelete,
+		common.CapabilityExists,
+		common.CapabilitySign,
+		common.CapabilityIdentity,
+	}
+}
+
+func (app *App) Identity(ctx context.Context) (common.Identity, error) {
+	if app.config.User == "" {
+		return common.Identity{CredentialsSource: "none"}, nil
+	}
+	return common.Identity{CredentialsSource: "basic_auth", Principal: app.config.User}, nil
+}
@@ -1,6 +1,7 @@
 package app_test
 
 import (
+	"context"
 	"errors"
 	"os"
 	"path/filepath"
@@ -9,6 +10,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/cloudfoundry/storage-cli/common"
 	. "github.com/cloudfoundry/storage-cli/dav/app"
 	davconf "github.com/cloudfoundry/storage-cli/dav/config"
 )
@@ -51,7 +53,7 @@ var _ = Describe("App", func() {
 
 		runner := &FakeRunner{}
 		app := New(runner, davConfig)
-		err := app.Put("localFile", "remoteFile")
+		err := app.Put(context.Background(), "localFile", "remoteFile", common.PutOptions{})
 		Expect(err).ToNot(HaveOccurred())
 
 		expectedConfig := davconf.Config{
@@ -80,7 +82,7 @@ var _ = Describe("App", func() {
 		}
 
 		app := New(runner, davConfig)
-		err := app.Put("localFile", "remoteFile")
+		err := app.Put(context.Background(), "localFile", "remoteFile", common.PutOptions{})
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("Invalid CA Certificate: invalid cert"))
 
@@ -94,7 +96,7 @@ var _ = Describe("App", func() {
 		runner := &FakeRunner{}
 
 		app := New(runner, davConfig)
-		err := app.Put("localFile", "remoteFile")
+		err := app.Put(context.Background(), "localFile", "remoteFile", common.PutOptions{})
 		Expect(err).ToNot(HaveOccurred())
 
 		expectedConfig := davconf.Config{
@@ -120,7 +122,7 @@ var _ = Describe("App", func() {
 		}
 
 		app := New(runner, davConfig)
-		err := app.Put("localFile", "remoteFile")
+		err := app.Put(context.Background(), "localFile", "remoteFile", common.PutOptions{})
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring("fake-run-error"))
 	})
@@ -142,7 +144,7 @@ var _ = Describe("App", func() {
 			}
 			app := New(runner, davConfig)
 
-			exist, err := app.Exists("someObject") //nolint:errcheck
+			exist, err := app.Exists(context.Background(), "someObject") //nolint:errcheck
 
 			Expect(err.Error()).To(ContainSubstring("object does not exist"))
 			Expect(exist).To(BeFalse())
@@ -155,7 +157,7 @@ var _ = Describe("App", func() {
 			}
 
 			app := New(runner, davConfig)
-			signedurl, err := app.Sign("someObject", "SomeObject", time.Second*100)
+			signedurl, err := app.Sign(context.Background(), "someObject", "SomeObject", time.Second*100)
 			Expect(signedurl).To(BeEmpty())
 			Expect(err.Error()).To(ContainSubstring("can't sign"))
 
@@ -8,7 +8,7 @@ import (
 type Config struct {
 	User          string
 	Password      string
-	Endpoint      string
+	Endpoint      string `required:"true"`
 	RetryAttempts uint
 	TLS           TLS
 	Secret        string
@@ -18,22 +18,27 @@ package client
 
 import (
 	"context"
-	"encoding/json"
+	"encoding/base64"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"golang.org/x/oauth2/google"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iterator"
 
 	"cloud.google.com/go/storage"
 	"cloud.google.com/go/storage/transfermanager"
 
+	"github.com/cloudfoundry/storage-cli/common"
 	"github.com/cloudfoundry/storage-cli/gcs/config"
 )
 
@@ -56,12 +61,6 @@ const maxConcurrency = 5
 // Put retries retryAttempts times
 const retryAttempts = 3
 
-type BlobProperties struct {
-	ETag          string    `json:"etag,omitempty"`
-	LastModified  time.Time `json:"last_modified,omitempty"`
-	ContentLength int64     `json:"content_length,omitempty"`
-}
-
 // GCSBlobstore encapsulates interaction with the GCS blobstore
 type GCSBlobstore struct {
 	authenticatedGCS *storage.Client
@@ -74,13 +73,13 @@ type GCSBlobstore struct {
 //
 // If operating in read-only mode, no mutations can be performed
 // so the remote bucket location is always compatible.
-func (client *GCSBlobstore) validateRemoteConfig() error {
+func (client *GCSBlobstore) validateRemoteConfig(ctx context.Context) error {
 	if client.readOnly() {
 		return nil
 	}
 
 	bucket := client.authenticatedGCS.Bucket(client.config.BucketName)
-	_, err := bucket.Attrs(context.Background())
+	_, err := bucket.Attrs(ctx)
 	return err
 }
 
@@ -117,7 +116,7 @@ func New(ctx context.Context, cfg *config.GCSCli) (*GCSBlobstore, error) {
 
 // Get fetches a blob from the GCS blobstore.
 // Destination will be overwritten if it already exists.
-func (client *GCSBlobstore) Get(src string, dest string) error {
+func (client *GCSBlobstore) Get(ctx context.Context, src string, dest string, options common.GetOptions) error {
 	slog.Info("Getting object into file", "bucket", client.config.BucketName, "object_name", src, "local_path", dest)
 
 	destFile, err := os.Create(dest)
@@ -127,9 +126,9 @@ func (client *GCSBlobstore) Get(src string, dest string) error {
 	defer destFile.Close() //nolint:errcheck
 
 	gcsClient := client.publicGCS
-	err = client.checkAccess(client.publicGCS, src)
+	err = client.checkAccess(ctx, client.publicGCS, src)
 	if err != nil && client.authenticatedGCS != nil {
-		err = client.checkAccess(client.authenticatedGCS, src)
+		err = client.checkAccess(ctx, client.authenticatedGCS, src)
 		if err == nil {
 			gcsClient = client.authenticatedGCS
 		}
@@ -142,21 +141,94 @@ func (client *GCSBlobstore) Get(src string, dest string) error {
 	// If object is encrypted, we can't use transfermanager
 	// Fall back to single-part download with encryption support
 	if client.config.EncryptionKey != nil {
-		return client.downloadEncrypted(gcsClient, src, destFile)
+		return client.downloadEncrypted(ctx, gcsClient, src, common.NewThrottledWriter(ctx, options.Bandwidth, common.NewChecksummingWriter(options.Checksum, destFile)))
+	}
+
+	return client.downloadConcurrent(ctx, gcsClient, src, common.NewThrottledWriter(ctx, options.Bandwidth, common.NewChecksummingWriter(options.Checksum, destFile)))
+
+}
+
+// GetRange fetches a blob from the GCS blobstore starting at offset.
+// Anything in dest beyond offset is discarded before the remaining bytes are written.
+// With options.IfGenerationMatch set it fails with common.ErrPreconditionFailed once the object has another generation.
+func (client *GCSBlobstore) GetRange(ctx context.Context, src string, dest string, offset int64, options common.GetOptions) error {
+	slog.Info("Resuming object download into file", "bucket", client.config.BucketName, "object_name", src, "local_path", dest, "offset", offset)
+
+	var generation int64
+	if options.IfGenerationMatch != "" {
+		var err error
+		if generation, err = strconv.ParseInt(options.IfGenerationMatch, 10, 64); err != nil {
+			return fmt.Errorf("invalid generation %q: %w", options.IfGenerationMatch, err)
+		}
+	}
+
+	destFile, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer destFile.Close() //nolint:errcheck
+
+	if err := destFile.Truncate(offset); err != nil {
+		return fmt.Errorf("truncating destination file: %w", err)
+	}
+
+	gcsClient := client.publicGCS
+	attrs, err := client.getObjectHandle(client.publicGCS, src).Attrs(ctx)
+	if err != nil && client.authenticatedGCS != nil {
+		attrs, err = client.getObjectHandle(client.authenticatedGCS, src).Attrs(ctx)
+		if err == nil {
+			gcsClient = client.authenticatedGCS
+		}
 	}
 
-	return client.downloadConcurrent(gcsClient, src, destFile)
+	if err != nil {
+		return err
+	}
+	if generation != 0 && attrs.Generation != generation {
+		return fmt.Errorf("%w: generation is %d, expected %d", common.ErrPreconditionFailed, attrs.Generation, generation)
+	}
+
+	if offset > attrs.Size {
+		return fmt.Errorf("local file is larger (%d bytes) than the remote object (%d bytes)", offset, attrs.Size)
+	}
+	if offset == attrs.Size {
+		slog.Info("Object already fully downloaded", "bucket", client.config.BucketName, "object_name", src, "size", attrs.Size)
+		return nil
+	}
+
+	handle := client.getObjectHandle(gcsClient, src)
+	if generation != 0 {
+		handle = handle.If(storage.Conditions{GenerationMatch: generation})
+	}
+	reader, err := handle.NewRangeReader(ctx, offset, -1)
+	if err != nil {
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusPreconditionFailed {
+			err = fmt.Errorf("%w: %w", common.ErrPreconditionFailed, err)
+		}
+		return fmt.Errorf("creating range reader: %w", err)
+	}
+	defer reader.Close() //nolint:errcheck
 
+	written, err := io.Copy(io.NewOffsetWriter(common.NewThrottledWriter(ctx, options.Bandwidth, destFile), offset), reader)
+	if err != nil {
+		return fmt.Errorf("writing object range: %w", err)
+	}
+	if offset+written != attrs.Size {
+		return fmt.Errorf("downloaded size mismatch: expected %d bytes, got %d", attrs.Size, offset+written)
+	}
+
+	return nil
 }
 
 // If the client can read object attributes,
 // then it can download the object.
-func (client *GCSBlobstore) checkAccess(gcsClient *storage.Client, src string) error {
-	_, err := client.getObjectHandle(gcsClient, src).Attrs(context.Background())
+func (client *GCSBlobstore) checkAccess(ctx context.Context, gcsClient *storage.Client, src string) error {
+	_, err := client.getObjectHandle(gcsClient, src).Attrs(ctx)
 	return err
 }
 
-func (client *GCSBlobstore) downloadConcurrent(gcsClient *storage.Client, src string, destFile *os.File) error {
+func (client *GCSBlobstore) downloadConcurrent(ctx context.Context, gcsClient *storage.Client, src string, dest common.DownloadTarget) error {
 	downloader, err := transfermanager.NewDownloader(gcsClient,
 		transfermanager.WithPartSize(blockSize),
 		transfermanager.WithWorkers(maxConcurrency))
@@ -164,9 +236,9 @@ func (client *GCSBlobstore) downloadConcurrent(gcsClient *storage.Client, src st
 		return fmt.Errorf("creating new downloader: %w", err)
 	}
 
-	in := &transfermanager.DownloadObjectInput{Bucket: client.config.BucketName, Object: src, Destination: destFile}
+	in := &transfermanager.DownloadObjectInput{Bucket: client.config.BucketName, Object: src, Destination: dest}
 
-	if err := downloader.DownloadObject(context.Background(), in); err != nil {
+	if err := downloader.DownloadObject(ctx, in); err != nil {
 		return fmt.Errorf("adding work into queue: %w", err)
 	}
 
@@ -187,88 +259,114 @@ func (client *GCSBlobstore) downloadConcurrent(gcsClient *storage.Client, src st
 	return nil
 }
 
-func (client *GCSBlobstore) downloadEncrypted(gcsClient *storage.Client, src string, destFile *os.File) error {
-	reader, err := client.getObjectHandle(gcsClient, src).NewReader(context.Background())
+func (client *GCSBlobstore) downloadEncrypted(ctx context.Context, gcsClient *storage.Client, src string, dest common.DownloadTarget) error {
+	reader, err := client.getObjectHandle(gcsClient, src).NewReader(ctx)
 	if err != nil {
 		return err
 	}
 	defer reader.Close() //nolint:errcheck
 
-	_, err = io.Copy(destFile, reader)
+	_, err = io.Copy(dest, reader)
 	return err
 }
 
 // Put uploads a blob to the GCS blobstore.
 // Destination will be overwritten if it already exists.
-func (client *GCSBlobstore) Put(sourceFilePath string, dest string) error {
+func (client *GCSBlobstore) Put(ctx context.Context, sourceFilePath string, dest string, options common.PutOptions) error {
+	_, err := client.PutWithETag(ctx, sourceFilePath, dest, options)
+	return err
+}
+
+// PutWithETag uploads a file like Put and returns the ETag of the new object as reported by the upload
+func (client *GCSBlobstore) PutWithETag(ctx context.Context, sourceFilePath string, dest string, options common.PutOptions) (string, error) {
 	slog.Info("Putting file into object", "bucket", client.config.BucketName, "local_path", sourceFilePath, "object_name", dest)
 
 	src, err := os.Open(sourceFilePath)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer src.Close() //nolint:errcheck
 
 	if client.readOnly() {
-		return ErrInvalidROWriteOperation
+		return "", ErrInvalidROWriteOperation
 	}
 
-	if err := client.validateRemoteConfig(); err != nil {
-		return err
+	if err := client.validateRemoteConfig(ctx); err != nil {
+		return "", err
+	}
+
+	if info, err := src.Stat(); err != nil {
+		return "", err
+	} else if client.uploadsInParallel(info.Size()) {
+		// The parts are retried one by one, there is no need to start over
+		return client.putParallel(ctx, common.NewThrottledReader(ctx, options.Bandwidth, src), info.Size(), dest, options)
 	}
 
 	pos, err := src.Seek(0, io.SeekCurrent)
 	if err != nil {
-		return fmt.Errorf("finding buffer position: %v", err)
+		return "", fmt.Errorf("finding buffer position: %v", err)
 	}
 
 	var errs []error
 	for i := range retryAttempts {
-		err := client.putResumable(src, dest)
+		var etag string
+		err := options.Concurrency.Do(func() (err error) {
+			etag, err = client.putResumable(ctx, common.NewThrottledReader(ctx, options.Bandwidth, src), dest, options)
+			return err
+		})
 		if err == nil {
-			return nil
+			return etag, nil
 		}
 
 		errs = append(errs, err)
 		slog.Error("Upload failed", "object_name", dest, "attempt", fmt.Sprintf("%d/%d", i+1, retryAttempts), "error", err)
 
 		if _, err := src.Seek(pos, io.SeekStart); err != nil {
-			return fmt.Errorf("resetting buffer position after failed upload: %v", err)
+			return "", fmt.Errorf("resetting buffer position after failed upload: %v", err)
+		}
+		if i+1 < retryAttempts {
+			common.IncRetries()
 		}
 	}
 
-	return fmt.Errorf("upload failed for %s after %d attempts: %v", dest, retryAttempts, errs)
+	return "", fmt.Errorf("upload failed for %s after %d attempts: %v", dest, retryAttempts, errs)
 }
 
-// putResumable performs a resumable upload in chunks of uploadChunkSize (100MB).
+// putResumable performs a resumable upload in chunks of uploadChunkSize (100MB) and returns the ETag of the new object.
 // Chunks are uploaded sequentially with automatic per-chunk retry on failure.
-func (client *GCSBlobstore) putResumable(src io.ReadSeeker, dest string) error {
-	ctx, cancel := context.WithCancel(context.Background())
+func (client *GCSBlobstore) putResumable(ctx context.Context, src io.ReadSeeker, dest string, options common.PutOptions) (string, error) {
+	ctx, cancel := context.WithCancel(ctx)
 	defer cancel() // Clean up the context after the function completes
 
 	remoteWriter := client.getObjectHandle(client.authenticatedGCS, dest).NewWriter(ctx) //nolint:staticcheck
 	remoteWriter.ObjectAttrs.StorageClass = client.config.StorageClass                   //nolint:staticcheck
 	remoteWriter.ChunkSize = uploadChunkSize
+	remoteWriter.ContentType = options.ContentType
+	remoteWriter.Metadata = options.Metadata
+	remoteWriter.KMSKeyName = client.config.KMSKeyName
 
 	if _, err := io.Copy(remoteWriter, src); err != nil {
 		remoteWriter.Close() //nolint:errcheck
-		return err
+		return "", err
 	}
 
-	return remoteWriter.Close()
+	if err := remoteWriter.Close(); err != nil {
+		return "", err
+	}
+	return strings.Trim(remoteWriter.Attrs().Etag, `"`), nil
 }
 
 // Delete removes a blob from from the GCS blobstore.
 //
 // If the object does not exist, Delete returns a nil error.
-func (client *GCSBlobstore) Delete(dest string) error {
+func (client *GCSBlobstore) Delete(ctx context.Context, dest string) error {
 	slog.Info("Deleting object in bucket", "bucket", client.config.BucketName, "object_name", dest)
 
 	if client.readOnly() {
 		return ErrInvalidROWriteOperation
 	}
 
-	err := client.getObjectHandle(client.authenticatedGCS, dest).Delete(context.Background())
+	err := client.getObjectHandle(client.authenticatedGCS, dest).Delete(ctx)
 	if errors.Is(err, storage.ErrObjectNotExist) {
 		return nil
 	}
@@ -276,23 +374,35 @@ func (client *GCSBlobstore) Delete(dest string) error {
 }
 
 // Exists checks if a blob exists in the GCS blobstore.
-func (client *GCSBlobstore) Exists(dest string) (exists bool, err error) {
+func (client *GCSBlobstore) Exists(ctx context.Context, dest string) (exists bool, err error) {
 	slog.Info("Checking object exists in bucket", "bucket", client.config.BucketName, "object_name", dest)
 
-	if exists, err = client.exists(client.publicGCS, dest); err == nil {
+	if exists, err = client.exists(ctx, client.publicGCS, dest); err == nil {
 		return exists, nil
 	}
 
 	// If the public client fails, try using it as an authenticated actor
 	if client.authenticatedGCS != nil {
-		return client.exists(client.authenticatedGCS, dest)
+		return client.exists(ctx, client.authenticatedGCS, dest)
 	}
 
 	return
 }
 
-func (client *GCSBlobstore) exists(gcs *storage.Client, dest string) (bool, error) {
-	_, err := client.getObjectHandle(gcs, dest).Attrs(context.Background())
+// Size returns the size of the object, looked up the same way Get accesses it
+func (client *GCSBlobstore) Size(ctx context.Context, dest string) (int64, error) {
+	attrs, err := client.getObjectHandle(client.publicGCS, dest).Attrs(ctx)
+	if err != nil && client.authenticatedGCS != nil {
+		attrs, err = client.getObjectHandle(client.authenticatedGCS, dest).Attrs(ctx)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("getting attributes: %w", err)
+	}
+	return attrs.Size, nil
+}
+
+func (client *GCSBlobstore) exists(ctx context.Context, gcs *storage.Client, dest string) (bool, error) {
+	_, err := client.getObjectHandle(gcs, dest).Attrs(ctx)
 	if err == nil {
 		slog.Info("Object exists in bucket", "bucket", client.config.BucketName, "object_name", dest)
 		return true, nil
@@ -307,36 +417,103 @@ func (client *GCSBlobstore) readOnly() bool {
 	return client.authenticatedGCS == nil
 }
 
-func (client *GCSBlobstore) Sign(id string, action string, expiry time.Duration) (string, error) {
+func (client *GCSBlobstore) Sign(ctx context.Context, id string, action string, expiry time.Duration) (string, error) {
+	return client.SignWithOptions(ctx, id, action, expiry, common.SignOptions{})
+}
+
+// SignWithOptions creates a signed URL that also binds the upload to the content type and MD5 in options
+func (client *GCSBlobstore) SignWithOptions(ctx context.Context, id string, action string, expiry time.Duration, options common.SignOptions) (string, error) {
 	slog.Info("Signing object", "bucket", client.config.BucketName, "object_name", id, "method", action, "expiration", expiry.String())
 
-	action = strings.ToUpper(action)
-	token, err := google.JWTConfigFromJSON([]byte(client.config.ServiceAccountFile), storage.ScopeFullControl)
-	if err != nil {
+	if err := client.ValidateSign(ctx, id, action, expiry, options); err != nil {
 		return "", err
 	}
-	options := storage.SignedURLOptions{
-		Method:         action,
-		Expires:        time.Now().Add(expiry),
-		PrivateKey:     token.PrivateKey,
-		GoogleAccessID: token.Email,
-		Scheme:         storage.SigningSchemeV4,
+
+	action = strings.ToUpper(action)
+	signedURLOptions := client.signedURLOptions(action, expiry, options)
+	if client.config.ServiceAccountFile != "" {
+		token, err := google.JWTConfigFromJSON([]byte(client.config.ServiceAccountFile), storage.ScopeFullControl)
+		if err != nil {
+			return "", err
+		}
+		signedURLOptions.PrivateKey = token.PrivateKey
+		signedURLOptions.GoogleAccessID = token.Email
+	} else {
+		// Without a key the URL is signed by the service account of the default credentials through IAM
+		email, signBytes, err := defaultCredentialsSigner(ctx)
+		if err != nil {
+			return "", err
+		}
+		signedURLOptions.GoogleAccessID = email
+		signedURLOptions.SignBytes = signBytes
+	}
+	return storage.SignedURL(client.config.BucketName, id, signedURLOptions)
+}
+
+// maxSignedURLExpiry is the longest a V4 signed URL can be valid
+const maxSignedURLExpiry = 7 * 24 * time.Hour
+
+// ValidateSign checks that a URL for action can be signed, without signing it. The json_key is
+// checked for a private key; the default credentials are only resolved when signing, through IAM.
+func (client *GCSBlobstore) ValidateSign(ctx context.Context, id string, action string, expiry time.Duration, options common.SignOptions) error {
+	// A V4 signed URL is valid from X-Goog-Date on, but the SDK always signs as of now
+	if !options.StartAt.IsZero() {
+		return errors.New("signing with a start time is not supported for gcs")
+	}
+	if expiry > maxSignedURLExpiry {
+		return fmt.Errorf("expiration %s exceeds the maximum of %s for signed URLs", expiry, maxSignedURLExpiry)
+	}
+
+	switch {
+	case client.config.ServiceAccountFile != "":
+		token, err := google.JWTConfigFromJSON([]byte(client.config.ServiceAccountFile), storage.ScopeFullControl)
+		if err != nil {
+			return err
+		}
+		if len(token.PrivateKey) == 0 {
+			return errors.New("signing URLs requires a json_key with a private key")
+		}
+	case client.config.CredentialsSource != config.DefaultCredentialsSource:
+		return errors.New("signing URLs requires a json_key or the default credentials source")
+	}
+
+	action = strings.ToUpper(action)
+	if action != "GET" && action != "PUT" {
+		return fmt.Errorf("action not implemented: %s", action)
+	}
+	return nil
+}
+
+// signedURLOptions returns everything but the credentials needed to sign a URL for action
+func (client *GCSBlobstore) signedURLOptions(action string, expiry time.Duration, options common.SignOptions) *storage.SignedURLOptions {
+	signedURLOptions := &storage.SignedURLOptions{
+		Method:  action,
+		Expires: time.Now().Add(expiry),
+		Scheme:  storage.SigningSchemeV4,
+		// Both become signed headers, a request with other values doesn't match the signature
+		ContentType: options.ContentType,
+		MD5:         options.ContentMD5,
 	}
 
 	// GET/PUT to the resultant signed url must include, in addition to the below:
 	// 'x-goog-encryption-key' and 'x-goog-encryption-key-sha256'
 	willEncrypt := len(client.config.EncryptionKey) > 0
 	if willEncrypt {
-		options.Headers = []string{
+		signedURLOptions.Headers = []string{
 			"x-goog-encryption-algorithm: AES256",
 			fmt.Sprintf("x-goog-encryption-key: %s", client.config.EncryptionKeyEncoded),
 			fmt.Sprintf("x-goog-encryption-key-sha256: %s", client.config.EncryptionKeySha256),
 		}
 	}
-	return storage.SignedURL(client.config.BucketName, id, &options)
+	return signedURLOptions
+}
+
+func (client *GCSBlobstore) List(ctx context.Context, prefix string) ([]string, error) {
+	return client.ListWithLimit(ctx, prefix, 0)
 }
 
-func (client *GCSBlobstore) List(prefix string) ([]string, error) {
+// ListWithLimit lists like List, but stops iterating once limit objects have been found
+func (client *GCSBlobstore) ListWithLimit(ctx context.Context, prefix string, limit int) ([]string, error) {
 	if prefix != "" {
 		slog.Info("Listing all objects in bucket", "bucket", client.config.BucketName, "prefix", prefix)
 	} else {
@@ -348,7 +525,11 @@ func (client *GCSBlobstore) List(prefix string) ([]string, error) {
 
 	bh := client.getBucketHandle(client.authenticatedGCS)
 
-	it := bh.Objects(context.Background(), &storage.Query{Prefix: prefix})
+	it := bh.Objects(ctx, &storage.Query{Prefix: prefix})
+	if limit > 0 {
+		// Don't fetch more than needed, a page holds up to 1000 objects
+		it.PageInfo().MaxSize = min(limit, 1000)
+	}
 
 	var names []string
 	for {
@@ -362,68 +543,215 @@ func (client *GCSBlobstore) List(prefix string) ([]string, error) {
 		}
 
 		names = append(names, attr.Name)
+		if len(names) == limit {
+			break
+		}
 	}
 
 	return names, nil
 
 }
 
-func (client *GCSBlobstore) Copy(srcBlob string, dstBlob string) error {
+// ListDetailed lists like List, along with the size and last modification time of each object
+func (client *GCSBlobstore) ListDetailed(ctx context.Context, prefix string) ([]common.ObjectInfo, error) {
+	return client.ListDetailedWithLimit(ctx, prefix, 0)
+}
+
+// ListDetailedWithLimit lists like ListDetailed, but stops iterating once limit objects have been found
+func (client *GCSBlobstore) ListDetailedWithLimit(ctx context.Context, prefix string, limit int) ([]common.ObjectInfo, error) {
+	slog.Info("Listing objects with details in bucket", "bucket", client.config.BucketName, "prefix", prefix)
+	if client.readOnly() {
+		return nil, ErrInvalidROWriteOperation
+	}
+
+	it := client.getBucketHandle(client.authenticatedGCS).Objects(ctx, &storage.Query{Prefix: prefix})
+	if limit > 0 {
+		// Don't fetch more than needed, a page holds up to 1000 objects
+		it.PageInfo().MaxSize = min(limit, 1000)
+	}
+
+	var objects []common.ObjectInfo
+	for {
+		attr, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, common.ObjectInfo{Key: attr.Name, Size: attr.Size, LastModified: attr.Updated})
+		if len(objects) == limit {
+			break
+		}
+	}
+
+	return objects, nil
+}
+
+// Copy copies an object within the bucket. The copy keeps the source's custom metadata
+// unless options.ResetMetadata is set, in which case it is removed once the copy exists.
+func (client *GCSBlobstore) Copy(ctx context.Context, srcBlob string, dstBlob string, options common.CopyOptions) error {
 	slog.Info("Copying object", "bucket", client.config.BucketName, "source_object", srcBlob, "destination_object", dstBlob)
 
 	if client.readOnly() {
 		return ErrInvalidROWriteOperation
 	}
 
+	return client.copyObject(ctx, srcBlob, client.getObjectHandle(client.authenticatedGCS, dstBlob), options)
+}
+
+// CopyToBucket copies an object of the configured bucket into dstBucket. Before copying, the
+// credentials are checked for permission to create objects in dstBucket, so that missing access
+// is reported as such. Metadata is handled as for Copy.
+func (client *GCSBlobstore) CopyToBucket(ctx context.Context, srcBlob string, dstBucket string, dstBlob string, options common.CopyOptions) error {
+	slog.Info("Copying object to another bucket", "bucket", client.config.BucketName, "source_object", srcBlob, "destination_bucket", dstBucket, "destination_object", dstBlob)
+
+	if client.readOnly() {
+		return ErrInvalidROWriteOperation
+	}
+
+	bucket := client.authenticatedGCS.Bucket(dstBucket)
+	granted, err := bucket.IAM().TestPermissions(ctx, []string{"storage.objects.create"})
+	if err != nil {
+		return fmt.Errorf("checking access to destination bucket %s: %w", dstBucket, err)
+	}
+	if len(granted) == 0 {
+		return fmt.Errorf("%w: no permission to create objects in destination bucket %s", common.ErrAccessDenied, dstBucket)
+	}
+
+	dstHandle := bucket.Object(dstBlob)
+	if client.config.EncryptionKey != nil {
+		dstHandle = dstHandle.Key(client.config.EncryptionKey)
+	}
+	return client.copyObject(ctx, srcBlob, dstHandle, options)
+}
+
+// copyObject copies srcBlob of the configured bucket to dstHandle
+func (client *GCSBlobstore) copyObject(ctx context.Context, srcBlob string, dstHandle *storage.ObjectHandle, options common.CopyOptions) error {
 	srcHandle := client.getObjectHandle(client.authenticatedGCS, srcBlob)
-	dstHandle := client.getObjectHandle(client.authenticatedGCS, dstBlob)
 
-	_, err := dstHandle.CopierFrom(srcHandle).Run(context.Background())
+	copier := dstHandle.CopierFrom(srcHandle)
+	copier.DestinationKMSKeyName = client.config.KMSKeyName
+	attrs, err := copier.Run(ctx)
 	if err != nil {
 		return fmt.Errorf("copying object: %w", err)
 	}
+
+	// The rewrite request can't ask for no metadata, an empty copier metadata means "keep the source's"
+	if options.ResetMetadata && len(attrs.Metadata) > 0 {
+		_, err = dstHandle.Update(ctx, storage.ObjectAttrsToUpdate{Metadata: map[string]string{}})
+		if err != nil {
+			return fmt.Errorf("resetting metadata of copied object: %w", err)
+		}
+	}
 	return nil
 }
 
-func (client *GCSBlobstore) Properties(dest string) error {
+func (client *GCSBlobstore) CopyFromBucket(ctx context.Context, srcBucket string, srcRegion string, srcBlob string, dstBlob string, options common.CopyOptions) error {
+	return errors.New("not implemented")
+}
+
+func (client *GCSBlobstore) PutWithManifest(ctx context.Context, sourceFilePath string, dest string, manifest common.UploadManifest, options common.PutOptions) error {
+	return errors.New("not implemented")
+}
+
+// Rename copies the object to its new name and deletes the original afterwards
+func (client *GCSBlobstore) Rename(ctx context.Context, srcBlob string, dstBlob string) error {
+	if err := client.Copy(ctx, srcBlob, dstBlob, common.CopyOptions{}); err != nil {
+		return err
+	}
+	return client.Delete(ctx, srcBlob)
+}
+
+func (client *GCSBlobstore) Properties(ctx context.Context, dest string) (common.ObjectProperties, error) {
+	return client.PropertiesWithOptions(ctx, dest, common.PropertiesOptions{})
+}
+
+// PropertiesWithOptions returns the properties of an object like Properties, with the ETag formatted as options ask for
+func (client *GCSBlobstore) PropertiesWithOptions(ctx context.Context, dest string, options common.PropertiesOptions) (common.ObjectProperties, error) {
 	slog.Info("Getting properties for object", "bucket", client.config.BucketName, "object_name", dest)
 
 	if client.readOnly() {
-		return ErrInvalidROWriteOperation
+		return common.ObjectProperties{}, ErrInvalidROWriteOperation
 	}
 	oh := client.getObjectHandle(client.authenticatedGCS, dest)
-	attr, err := oh.Attrs(context.Background())
+	attr, err := oh.Attrs(ctx)
 
 	if err != nil {
 		if errors.Is(err, storage.ErrObjectNotExist) {
-			fmt.Println(`{}`)
-			return nil
+			return common.ObjectProperties{}, common.ErrObjectNotFound
 		}
-		return fmt.Errorf("getting attributes: %w", err)
+		return common.ObjectProperties{}, fmt.Errorf("getting attributes: %w", err)
 	}
 
-	props := BlobProperties{
-		ETag:          strings.Trim(attr.Etag, `"`),
+	props := common.ObjectProperties{
+		ETag:          options.ETag(attr.Etag),
 		LastModified:  attr.Updated,
 		ContentLength: attr.Size,
+		Metadata:      attr.Metadata,
+	}
+	// Composite objects have no MD5
+	if len(attr.MD5) > 0 {
+		props.ContentMD5 = base64.StdEncoding.EncodeToString(attr.MD5)
 	}
+	return props, nil
+}
+
+// Head returns the full attributes of dest, or common.ErrObjectNotFound if it doesn't exist
+func (client *GCSBlobstore) Head(ctx context.Context, dest string) (common.ObjectHead, error) {
+	slog.Info("Getting head of object", "bucket", client.config.BucketName, "object_name", dest)
 
-	output, err := json.MarshalIndent(props, "", "  ")
+	if client.readOnly() {
+		return common.ObjectHead{}, ErrInvalidROWriteOperation
+	}
+	attrs, err := client.getObjectHandle(client.authenticatedGCS, dest).Attrs(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to marshal blob properties: %w", err)
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return common.ObjectHead{}, common.ErrObjectNotFound
+		}
+		return common.ObjectHead{}, fmt.Errorf("getting attributes: %w", err)
+	}
+
+	head := common.ObjectHead{
+		ETag:               attrs.Etag,
+		LastModified:       attrs.Updated,
+		ContentLength:      attrs.Size,
+		ContentType:        attrs.ContentType,
+		ContentEncoding:    attrs.ContentEncoding,
+		ContentDisposition: attrs.ContentDisposition,
+		ContentLanguage:    attrs.ContentLanguage,
+		CacheControl:       attrs.CacheControl,
+		StorageClass:       attrs.StorageClass,
+		Metadata:           attrs.Metadata,
+	}
+	if len(attrs.MD5) > 0 {
+		head.ContentMD5 = base64.StdEncoding.EncodeToString(attrs.MD5)
+	}
+	// Every object has a CRC32C, also composite ones which have no MD5. It is zero when it isn't reported.
+	if attrs.CRC32C != 0 || attrs.Size == 0 {
+		head.Checksums = map[string]string{
+			common.ChecksumCRC32C: base64.StdEncoding.EncodeToString(binary.BigEndian.AppendUint32(nil, attrs.CRC32C)),
+		}
 	}
-
-	fmt.Println(string(output))
-	return nil
+	if attrs.Generation != 0 {
+		head.VersionID = strconv.FormatInt(attrs.Generation, 10)
+	}
+	if attrs.KMSKeyName != "" || attrs.CustomerKeySHA256 != "" {
+		head.Encryption = &common.ObjectEncryption{
+			KeyID:             attrs.KMSKeyName,
+			CustomerKeySHA256: attrs.CustomerKeySHA256,
+		}
+	}
+	return head, nil
 }
 
-func (client *GCSBlobstore) EnsureStorageExists() error {
+func (client *GCSBlobstore) EnsureStorageExists(ctx context.Context) error {
 	slog.Info("Ensuring bucket exists", "bucket", client.config.BucketName)
 
 	if client.readOnly() {
 		return ErrInvalidROWriteOperation
 	}
-	ctx := context.Background()
 	bh := client.getBucketHandle(client.authenticatedGCS)
 
 	_, err := bh.Attrs(ctx)
@@ -455,7 +783,7 @@ func (client *GCSBlobstore) EnsureStorageExists() error {
 	return nil
 }
 
-func (client *GCSBlobstore) DeleteRecursive(prefix string) error {
+func (client *GCSBlobstore) DeleteRecursive(ctx context.Context, prefix string, options common.DeleteRecursiveOptions) error {
 	if prefix != "" {
 		slog.Info("Deleting all the objects in bucket", "bucket", client.config.BucketName, "prefix", prefix)
 	} else {
@@ -466,13 +794,17 @@ func (client *GCSBlobstore) DeleteRecursive(prefix string) error {
 		return ErrInvalidROWriteOperation
 	}
 
-	names, err := client.List(prefix)
+	names, err := client.List(ctx, prefix)
 	if err != nil {
 		return fmt.Errorf("listing objects: %w", err)
 	}
 
+	// Without options.ContinueOnError the first failure cancels the deletions that have not started yet
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	errChan := make(chan error, len(names))
-	semaphore := make(chan struct{}, maxConcurrency)
+	semaphore := make(chan struct{}, options.ConcurrencyOr(maxConcurrency))
 	wg := &sync.WaitGroup{}
 	for _, n := range names {
 		name := n
@@ -483,9 +815,19 @@ func (client *GCSBlobstore) DeleteRecursive(prefix string) error {
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 
-			err := client.getObjectHandle(client.authenticatedGCS, name).Delete(context.Background())
+			if ctx.Err() != nil {
+				return
+			}
+
+			err := client.getObjectHandle(client.authenticatedGCS, name).Delete(ctx)
 			if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+				if errors.Is(err, context.Canceled) && !options.ContinueOnError {
+					return
+				}
 				errChan <- fmt.Errorf("deleting object %s: %w", name, err)
+				if !options.ContinueOnError {
+					cancel()
+				}
 			}
 		}()
 	}
@@ -504,3 +846,39 @@ func (client *GCSBlobstore) DeleteRecursive(prefix string) error {
 
 	return nil
 }
+
+// Capabilities lists the operations the client supports
+func (client *GCSBlobstore) Capabilities() []string {
+	return []string{
+		common.CapabilityPut,
+		common.CapabilityPutWithETag,
+		common.CapabilityGet,
+		common.CapabilityGetRange,
+		common.CapabilityDelete,
+		common.CapabilityDeleteRecursive,
+		common.CapabilityExists,
+		common.CapabilitySize,
+		common.CapabilitySign,
+		common.CapabilitySignContent,
+		common.CapabilityList,
+		common.CapabilityCopy,
+		common.CapabilityCopyToBucket,
+		common.CapabilityRename,
+		common.CapabilityProperties,
+		common.CapabilityHead,
+		common.CapabilityEnsureStorageExists,
+		common.CapabilityIdentity,
+	}
+}
+
+// Identity reports the credentials source and the email of the service account used, if any
+func (client *GCSBlobstore) Identity(ctx context.Context) (common.Identity, error) {
+	identity := common.Identity{CredentialsSource: client.config.CredentialsSource}
+
+	email, err := extractClientEmail(ctx, client.config)
+	if err != nil {
+		return identity, err
+	}
+	identity.Principal = email
+	return identity, nil
+}
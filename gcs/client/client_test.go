@@ -0,0 +1,807 @@
+package client_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
+	"github.com/cloudfoundry/storage-cli/gcs/client"
+	"github.com/cloudfoundry/storage-cli/gcs/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// newServiceAccountFile returns the JSON key of a made up service account, good enough to sign URLs offline
+func newServiceAccountFile() string {
+	return newServiceAccountFileWithTokenURI("")
+}
+
+// newServiceAccountFileWithTokenURI returns the JSON key of a made up service account that fetches its tokens from tokenURI
+func newServiceAccountFileWithTokenURI(tokenURI string) string {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	Expect(err).ToNot(HaveOccurred())
+
+	serviceAccount, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "signer@project.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		"token_uri":    tokenURI,
+	})
+	Expect(err).ToNot(HaveOccurred())
+	return string(serviceAccount)
+}
+
+var _ = Describe("GCSBlobstore", func() {
+	Describe("SignWithOptions()", func() {
+		var blobstore *client.GCSBlobstore
+
+		BeforeEach(func() {
+			var err error
+			blobstore, err = client.New(context.Background(), &config.GCSCli{
+				BucketName:         "some-bucket",
+				CredentialsSource:  config.ServiceAccountFileCredentialsSource,
+				ServiceAccountFile: newServiceAccountFile(),
+			})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		signedHeaders := func(signedURL string) string {
+			parsed, err := url.Parse(signedURL)
+			Expect(err).ToNot(HaveOccurred())
+			return parsed.Query().Get("X-Goog-SignedHeaders")
+		}
+
+		It("only signs the host by default", func() {
+			signedURL, err := blobstore.Sign(context.Background(), "some-object", "put", time.Hour)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(signedHeaders(signedURL)).To(Equal("host"))
+		})
+
+		It("makes the content type and MD5 signed headers of a put url", func() {
+			signedURL, err := blobstore.SignWithOptions(context.Background(), "some-object", "put", time.Hour, common.SignOptions{
+				ContentType: "application/gzip",
+				ContentMD5:  "1B2M2Y8AsgTpgAmY7PhCfg==",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(signedURL).To(HavePrefix("https://storage.googleapis.com/some-bucket/some-object?"))
+			Expect(signedHeaders(signedURL)).To(Equal("content-md5;content-type;host"))
+		})
+
+		It("signs only the headers that are given", func() {
+			signedURL, err := blobstore.SignWithOptions(context.Background(), "some-object", "put", time.Hour, common.SignOptions{ContentType: "application/gzip"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(signedHeaders(signedURL)).To(Equal("content-type;host"))
+		})
+
+		It("refuses a start time", func() {
+			_, err := blobstore.SignWithOptions(context.Background(), "some-object", "get", time.Hour, common.SignOptions{StartAt: time.Now().Add(time.Hour)})
+			Expect(err).To(MatchError(ContainSubstring("start time is not supported for gcs")))
+		})
+
+		It("fails clearly when the default credentials have no service account to sign with", func() {
+			credentialsFile := filepath.Join(GinkgoT().TempDir(), "credentials.json")
+			Expect(os.WriteFile(credentialsFile, []byte(`{"type":"authorized_user","client_id":"x","client_secret":"y","refresh_token":"z"}`), 0600)).To(Succeed())
+			GinkgoT().Setenv("GOOGLE_APPLICATION_CREDENTIALS", credentialsFile)
+			metadataServer := httptest.NewServer(http.NotFoundHandler())
+			defer metadataServer.Close()
+			GinkgoT().Setenv("GCE_METADATA_HOST", strings.TrimPrefix(metadataServer.URL, "http://"))
+
+			defaultBlobstore, err := client.New(context.Background(), &config.GCSCli{
+				BucketName:        "some-bucket",
+				CredentialsSource: config.DefaultCredentialsSource,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = defaultBlobstore.Sign(context.Background(), "some-object", "get", time.Hour)
+			Expect(err).To(MatchError(ContainSubstring("signing URLs requires a json_key or default credentials of a service account")))
+
+			// Validating doesn't sign, so it doesn't look for the service account
+			Expect(defaultBlobstore.ValidateSign(context.Background(), "some-object", "get", time.Hour, common.SignOptions{})).To(Succeed())
+		})
+	})
+
+	Describe("ValidateSign()", func() {
+		It("accepts a json_key with a private key", func() {
+			blobstore, err := client.New(context.Background(), &config.GCSCli{
+				BucketName:         "some-bucket",
+				CredentialsSource:  config.ServiceAccountFileCredentialsSource,
+				ServiceAccountFile: newServiceAccountFile(),
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(blobstore.ValidateSign(context.Background(), "some-object", "put", 7*24*time.Hour, common.SignOptions{})).To(Succeed())
+			err = blobstore.ValidateSign(context.Background(), "some-object", "put", 7*24*time.Hour+time.Second, common.SignOptions{})
+			Expect(err).To(MatchError("expiration 168h0m1s exceeds the maximum of 168h0m0s for signed URLs"))
+		})
+
+		It("refuses to sign without credentials", func() {
+			blobstore, err := client.New(context.Background(), &config.GCSCli{
+				BucketName:        "some-bucket",
+				CredentialsSource: config.NoneCredentialsSource,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			err = blobstore.ValidateSign(context.Background(), "some-object", "get", time.Hour, common.SignOptions{})
+			Expect(err).To(MatchError("signing URLs requires a json_key or the default credentials source"))
+		})
+	})
+
+	Describe("PutWithETag()", func() {
+		It("returns the ETag from the attributes of the written object", func() {
+			var uploaded string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/token":
+					w.Header().Set("Content-Type", "application/json")
+					w.Write([]byte(`{"access_token": "some-token", "token_type": "Bearer", "expires_in": 3600}`)) //nolint:errcheck
+				case r.Method == http.MethodGet && r.URL.Path == "/storage/v1/b/some-bucket":
+					w.Write([]byte(`{"name": "some-bucket"}`)) //nolint:errcheck
+				case strings.HasPrefix(r.URL.Path, "/upload/storage/v1/b/some-bucket/o"):
+					body, _ := io.ReadAll(r.Body) //nolint:errcheck
+					uploaded = string(body)
+					w.Write([]byte(`{"bucket": "some-bucket", "name": "some-object", "etag": "CKih16GjycICEAE="}`)) //nolint:errcheck
+				default:
+					w.WriteHeader(http.StatusBadRequest)
+				}
+			}))
+			DeferCleanup(server.Close)
+			GinkgoT().Setenv("STORAGE_EMULATOR_HOST", server.URL)
+
+			blobstore, err := client.New(context.Background(), &config.GCSCli{
+				BucketName:         "some-bucket",
+				CredentialsSource:  config.ServiceAccountFileCredentialsSource,
+				ServiceAccountFile: newServiceAccountFileWithTokenURI(server.URL + "/token"),
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			sourceFile := filepath.Join(GinkgoT().TempDir(), "source")
+			Expect(os.WriteFile(sourceFile, []byte("0123456789"), 0644)).To(Succeed())
+
+			etag, err := blobstore.PutWithETag(context.Background(), sourceFile, "some-object", common.PutOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(uploaded).To(ContainSubstring("0123456789"))
+			Expect(etag).To(Equal("CKih16GjycICEAE="))
+		})
+	})
+
+	Describe("Put()", func() {
+		It("stores the object with the upload content type and metadata", func() {
+			var uploaded string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/token":
+					w.Header().Set("Content-Type", "application/json")
+					w.Write([]byte(`{"access_token": "some-token", "token_type": "Bearer", "expires_in": 3600}`)) //nolint:errcheck
+				case r.Method == http.MethodGet && r.URL.Path == "/storage/v1/b/some-bucket":
+					w.Write([]byte(`{"name": "some-bucket"}`)) //nolint:errcheck
+				case strings.HasPrefix(r.URL.Path, "/upload/storage/v1/b/some-bucket/o"):
+					body, _ := io.ReadAll(r.Body) //nolint:errcheck
+					uploaded = string(body)
+					w.Write([]byte(`{"bucket": "some-bucket", "name": "some-object"}`)) //nolint:errcheck
+				default:
+					w.WriteHeader(http.StatusBadRequest)
+				}
+			}))
+			DeferCleanup(server.Close)
+			GinkgoT().Setenv("STORAGE_EMULATOR_HOST", server.URL)
+
+			blobstore, err := client.New(context.Background(), &config.GCSCli{
+				BucketName:         "some-bucket",
+				CredentialsSource:  config.ServiceAccountFileCredentialsSource,
+				ServiceAccountFile: newServiceAccountFileWithTokenURI(server.URL + "/token"),
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			sourceFile := filepath.Join(GinkgoT().TempDir(), "source")
+			Expect(os.WriteFile(sourceFile, []byte("0123456789"), 0644)).To(Succeed())
+
+			Expect(blobstore.Put(context.Background(), sourceFile, "some-object", common.PutOptions{
+				ContentType: "text/csv",
+				Metadata:    map[string]string{common.MD5MetadataKey: "781e5e245d69b566979b86e28d23f2c7"},
+			})).To(Succeed())
+			Expect(uploaded).To(ContainSubstring(`"contentType":"text/csv"`))
+			Expect(uploaded).To(ContainSubstring(`"metadata":{"md5":"781e5e245d69b566979b86e28d23f2c7"}`))
+		})
+
+		It("uploads an empty file as a zero-byte object", func() {
+			var (
+				uploads int
+				content []byte
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/token":
+					w.Header().Set("Content-Type", "application/json")
+					w.Write([]byte(`{"access_token": "some-token", "token_type": "Bearer", "expires_in": 3600}`)) //nolint:errcheck
+				case r.Method == http.MethodGet && r.URL.Path == "/storage/v1/b/some-bucket":
+					w.Write([]byte(`{"name": "some-bucket"}`)) //nolint:errcheck
+				case strings.HasPrefix(r.URL.Path, "/upload/storage/v1/b/some-bucket/o"):
+					uploads++
+					_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+					Expect(err).ToNot(HaveOccurred())
+					body := multipart.NewReader(r.Body, params["boundary"])
+					_, err = body.NextPart()
+					Expect(err).ToNot(HaveOccurred())
+					contentPart, err := body.NextPart()
+					Expect(err).ToNot(HaveOccurred())
+					content, err = io.ReadAll(contentPart)
+					Expect(err).ToNot(HaveOccurred())
+					fmt.Fprintf(w, `{"bucket": "some-bucket", "name": "empty-object", "size": "%d"}`, len(content)) //nolint:errcheck
+				case r.Method == http.MethodGet && r.URL.Path == "/storage/v1/b/some-bucket/o/empty-object":
+					fmt.Fprintf(w, `{"bucket": "some-bucket", "name": "empty-object", "etag": "some-etag", "size": "%d"}`, len(content)) //nolint:errcheck
+				default:
+					w.WriteHeader(http.StatusBadRequest)
+				}
+			}))
+			DeferCleanup(server.Close)
+			GinkgoT().Setenv("STORAGE_EMULATOR_HOST", server.URL)
+
+			blobstore, err := client.New(context.Background(), &config.GCSCli{
+				BucketName:         "some-bucket",
+				CredentialsSource:  config.ServiceAccountFileCredentialsSource,
+				ServiceAccountFile: newServiceAccountFileWithTokenURI(server.URL + "/token"),
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			sourceFile := filepath.Join(GinkgoT().TempDir(), "empty")
+			Expect(os.WriteFile(sourceFile, nil, 0644)).To(Succeed())
+
+			Expect(blobstore.Put(context.Background(), sourceFile, "empty-object", common.PutOptions{})).To(Succeed())
+			Expect(uploads).To(Equal(1))
+			Expect(content).To(BeEmpty())
+
+			properties, err := blobstore.Properties(context.Background(), "empty-object")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(properties.ContentLength).To(BeZero())
+		})
+	})
+
+	Describe("PutWithETag() of a file above the parallel_upload_threshold", func() {
+		var (
+			cfg          *config.GCSCli
+			server       *httptest.Server
+			lock         sync.Mutex
+			uploads      map[string]string
+			composes     []string
+			deletes      []string
+			failingParts string
+		)
+
+		BeforeEach(func() {
+			uploads, composes, deletes, failingParts = map[string]string{}, nil, nil, ""
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				lock.Lock()
+				defer lock.Unlock()
+				switch {
+				case r.URL.Path == "/token":
+					w.Header().Set("Content-Type", "application/json")
+					w.Write([]byte(`{"access_token": "some-token", "token_type": "Bearer", "expires_in": 3600}`)) //nolint:errcheck
+				case r.Method == http.MethodGet && r.URL.Path == "/storage/v1/b/some-bucket":
+					w.Write([]byte(`{"name": "some-bucket"}`)) //nolint:errcheck
+				case strings.HasPrefix(r.URL.Path, "/upload/storage/v1/b/some-bucket/o"):
+					name := r.URL.Query().Get("name")
+					if failingParts != "" && strings.HasSuffix(name, failingParts) {
+						w.WriteHeader(http.StatusForbidden)
+						return
+					}
+					// The body holds the object's JSON attributes followed by its content
+					_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+					Expect(err).ToNot(HaveOccurred())
+					body := multipart.NewReader(r.Body, params["boundary"])
+					_, err = body.NextPart()
+					Expect(err).ToNot(HaveOccurred())
+					contentPart, err := body.NextPart()
+					Expect(err).ToNot(HaveOccurred())
+					content, err := io.ReadAll(contentPart)
+					Expect(err).ToNot(HaveOccurred())
+					uploads[name] = string(content)
+
+					crc := binary.BigEndian.AppendUint32(nil, crc32.Checksum(content, crc32.MakeTable(crc32.Castagnoli)))
+					fmt.Fprintf(w, `{"bucket": "some-bucket", "name": %q, "crc32c": %q}`, name, base64.StdEncoding.EncodeToString(crc)) //nolint:errcheck
+				case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/compose"):
+					body, _ := io.ReadAll(r.Body) //nolint:errcheck
+					composes = append(composes, string(body))
+					w.Write([]byte(`{"bucket": "some-bucket", "name": "some-object", "etag": "CKih16GjycICEAE="}`)) //nolint:errcheck
+				case r.Method == http.MethodDelete:
+					deletes = append(deletes, strings.TrimPrefix(r.URL.Path, "/storage/v1/b/some-bucket/o/"))
+					w.WriteHeader(http.StatusNoContent)
+				default:
+					w.WriteHeader(http.StatusBadRequest)
+				}
+			}))
+			DeferCleanup(server.Close)
+			GinkgoT().Setenv("STORAGE_EMULATOR_HOST", server.URL)
+
+			cfg = &config.GCSCli{
+				BucketName:              "some-bucket",
+				CredentialsSource:       config.ServiceAccountFileCredentialsSource,
+				ServiceAccountFile:      newServiceAccountFileWithTokenURI(server.URL + "/token"),
+				ParallelUploadThreshold: 10,
+				UploadPartSize:          4,
+			}
+		})
+
+		put := func(content string) (string, error) {
+			blobstore, err := client.New(context.Background(), cfg)
+			Expect(err).ToNot(HaveOccurred())
+
+			sourceFile := filepath.Join(GinkgoT().TempDir(), "source")
+			Expect(os.WriteFile(sourceFile, []byte(content), 0644)).To(Succeed())
+			return blobstore.PutWithETag(context.Background(), sourceFile, "some-object", common.PutOptions{})
+		}
+
+		It("uploads the parts in parallel, composes them and deletes them", func() {
+			etag, err := put("0123456789")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(etag).To(Equal("CKih16GjycICEAE="))
+
+			Expect(uploads).To(HaveLen(3))
+			var partNames []string
+			for name, content := range uploads {
+				Expect(name).To(HavePrefix("some-object.part-"))
+				partNames = append(partNames, name)
+				switch {
+				case strings.HasSuffix(name, "-0"):
+					Expect(content).To(Equal("0123"))
+				case strings.HasSuffix(name, "-1"):
+					Expect(content).To(Equal("4567"))
+				case strings.HasSuffix(name, "-2"):
+					Expect(content).To(Equal("89"))
+				}
+			}
+
+			Expect(composes).To(HaveLen(1))
+			var compose struct {
+				SourceObjects []struct {
+					Name string `json:"name"`
+				} `json:"sourceObjects"`
+			}
+			Expect(json.Unmarshal([]byte(composes[0]), &compose)).To(Succeed())
+			Expect(compose.SourceObjects).To(HaveLen(3))
+			Expect(compose.SourceObjects[0].Name).To(HaveSuffix("-0"))
+			Expect(compose.SourceObjects[2].Name).To(HaveSuffix("-2"))
+
+			Expect(deletes).To(ConsistOf(partNames))
+		})
+
+		It("uploads files below the threshold sequentially", func() {
+			_, err := put("012345678")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(uploads).To(HaveKey("some-object"))
+			Expect(composes).To(BeEmpty())
+		})
+
+		It("uploads sequentially with an encryption_key", func() {
+			cfg.EncryptionKey = make([]byte, 32)
+
+			_, err := put("0123456789")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(uploads).To(HaveKey("some-object"))
+			Expect(composes).To(BeEmpty())
+		})
+
+		It("fails without composing when a part fails and deletes the parts", func() {
+			failingParts = "-1"
+
+			_, err := put("0123456789")
+			Expect(err).To(MatchError(ContainSubstring("uploading parts")))
+			Expect(composes).To(BeEmpty())
+			Expect(deletes).To(HaveLen(3))
+		})
+	})
+
+	Describe("with kms_key_name", func() {
+		const kmsKeyName = "projects/p/locations/us/keyRings/r/cryptoKeys/k"
+
+		var (
+			blobstore *client.GCSBlobstore
+			uploads   []url.Values
+			rewrites  []url.Values
+		)
+
+		BeforeEach(func() {
+			uploads, rewrites = nil, nil
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/token":
+					w.Header().Set("Content-Type", "application/json")
+					w.Write([]byte(`{"access_token": "some-token", "token_type": "Bearer", "expires_in": 3600}`)) //nolint:errcheck
+				case r.Method == http.MethodGet && r.URL.Path == "/storage/v1/b/some-bucket":
+					w.Write([]byte(`{"name": "some-bucket"}`)) //nolint:errcheck
+				case strings.HasPrefix(r.URL.Path, "/upload/storage/v1/b/some-bucket/o"):
+					io.Copy(io.Discard, r.Body) //nolint:errcheck
+					uploads = append(uploads, r.URL.Query())
+					w.Write([]byte(`{"bucket": "some-bucket", "name": "some-object"}`)) //nolint:errcheck
+				case r.Method == http.MethodPost && strings.Contains(r.URL.Path, "/rewriteTo/"):
+					rewrites = append(rewrites, r.URL.Query())
+					w.Write([]byte(`{"done": true, "resource": {"bucket": "some-bucket", "name": "new-object"}}`)) //nolint:errcheck
+				default:
+					w.WriteHeader(http.StatusBadRequest)
+				}
+			}))
+			DeferCleanup(server.Close)
+			GinkgoT().Setenv("STORAGE_EMULATOR_HOST", server.URL)
+
+			var err error
+			blobstore, err = client.New(context.Background(), &config.GCSCli{
+				BucketName:         "some-bucket",
+				CredentialsSource:  config.ServiceAccountFileCredentialsSource,
+				ServiceAccountFile: newServiceAccountFileWithTokenURI(server.URL + "/token"),
+				KMSKeyName:         kmsKeyName,
+			})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("encrypts uploaded objects with the key", func() {
+			sourceFile := filepath.Join(GinkgoT().TempDir(), "source")
+			Expect(os.WriteFile(sourceFile, []byte("0123456789"), 0644)).To(Succeed())
+
+			Expect(blobstore.Put(context.Background(), sourceFile, "some-object", common.PutOptions{})).To(Succeed())
+			Expect(uploads).To(HaveLen(1))
+			Expect(uploads[0].Get("kmsKeyName")).To(Equal(kmsKeyName))
+		})
+
+		It("encrypts copied objects with the key", func() {
+			Expect(blobstore.Copy(context.Background(), "some-object", "new-object", common.CopyOptions{})).To(Succeed())
+			Expect(rewrites).To(HaveLen(1))
+			Expect(rewrites[0].Get("destinationKmsKeyName")).To(Equal(kmsKeyName))
+		})
+	})
+
+	Describe("DeleteRecursive()", func() {
+		var (
+			lock        sync.Mutex
+			deleted     []string
+			inFlight    int
+			maxInFlight int
+			blobstore   *client.GCSBlobstore
+		)
+
+		BeforeEach(func() {
+			deleted, inFlight, maxInFlight = nil, 0, 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/token":
+					w.Header().Set("Content-Type", "application/json")
+					w.Write([]byte(`{"access_token": "some-token", "token_type": "Bearer", "expires_in": 3600}`)) //nolint:errcheck
+				case r.Method == http.MethodGet && r.URL.Path == "/storage/v1/b/some-bucket/o":
+					items := make([]string, 8)
+					for i := range items {
+						items[i] = fmt.Sprintf(`{"bucket": "some-bucket", "name": "object-%d"}`, i)
+					}
+					fmt.Fprintf(w, `{"items": [%s]}`, strings.Join(items, ", ")) //nolint:errcheck
+				case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/storage/v1/b/some-bucket/o/"):
+					lock.Lock()
+					deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/storage/v1/b/some-bucket/o/"))
+					inFlight++
+					maxInFlight = max(maxInFlight, inFlight)
+					lock.Unlock()
+					time.Sleep(20 * time.Millisecond)
+					lock.Lock()
+					inFlight--
+					lock.Unlock()
+					w.WriteHeader(http.StatusNoContent)
+				default:
+					w.WriteHeader(http.StatusBadRequest)
+				}
+			}))
+			DeferCleanup(server.Close)
+			GinkgoT().Setenv("STORAGE_EMULATOR_HOST", server.URL)
+
+			var err error
+			blobstore, err = client.New(context.Background(), &config.GCSCli{
+				BucketName:         "some-bucket",
+				CredentialsSource:  config.ServiceAccountFileCredentialsSource,
+				ServiceAccountFile: newServiceAccountFileWithTokenURI(server.URL + "/token"),
+			})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("deletes up to 5 objects at the same time by default", func() {
+			Expect(blobstore.DeleteRecursive(context.Background(), "", common.DeleteRecursiveOptions{})).To(Succeed())
+
+			Expect(deleted).To(HaveLen(8))
+			Expect(maxInFlight).To(BeNumerically("<=", 5))
+		})
+
+		It("deletes as many objects at once as the delete concurrency allows", func() {
+			Expect(blobstore.DeleteRecursive(context.Background(), "", common.DeleteRecursiveOptions{Concurrency: 1})).To(Succeed())
+
+			Expect(deleted).To(HaveLen(8))
+			Expect(maxInFlight).To(Equal(1))
+		})
+	})
+
+	Describe("CopyToBucket()", func() {
+		var (
+			blobstore   *client.GCSBlobstore
+			permissions string
+			rewrites    []string
+		)
+
+		BeforeEach(func() {
+			permissions = `["storage.objects.create"]`
+			rewrites = nil
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/token":
+					w.Header().Set("Content-Type", "application/json")
+					w.Write([]byte(`{"access_token": "some-token", "token_type": "Bearer", "expires_in": 3600}`)) //nolint:errcheck
+				case r.Method == http.MethodGet && r.URL.Path == "/storage/v1/b/some-bucket":
+					w.Write([]byte(`{"name": "some-bucket"}`)) //nolint:errcheck
+				case r.URL.Path == "/storage/v1/b/other-bucket/iam/testPermissions":
+					Expect(r.URL.Query()["permissions"]).To(Equal([]string{"storage.objects.create"}))
+					fmt.Fprintf(w, `{"permissions": %s}`, permissions) //nolint:errcheck
+				case r.Method == http.MethodPost && strings.Contains(r.URL.Path, "/rewriteTo/"):
+					rewrites = append(rewrites, r.URL.Path)
+					w.Write([]byte(`{"done": true, "resource": {"bucket": "other-bucket", "name": "new-object"}}`)) //nolint:errcheck
+				default:
+					w.WriteHeader(http.StatusBadRequest)
+				}
+			}))
+			DeferCleanup(server.Close)
+			GinkgoT().Setenv("STORAGE_EMULATOR_HOST", server.URL)
+
+			var err error
+			blobstore, err = client.New(context.Background(), &config.GCSCli{
+				BucketName:         "some-bucket",
+				CredentialsSource:  config.ServiceAccountFileCredentialsSource,
+				ServiceAccountFile: newServiceAccountFileWithTokenURI(server.URL + "/token"),
+			})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("rewrites the object into the destination bucket", func() {
+			err := blobstore.CopyToBucket(context.Background(), "some-object", "other-bucket", "new-object", common.CopyOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rewrites).To(Equal([]string{"/storage/v1/b/some-bucket/o/some-object/rewriteTo/b/other-bucket/o/new-object"}))
+		})
+
+		It("refuses to copy without permission to create objects in the destination bucket", func() {
+			permissions = `[]`
+
+			err := blobstore.CopyToBucket(context.Background(), "some-object", "other-bucket", "new-object", common.CopyOptions{})
+			Expect(err).To(MatchError(common.ErrAccessDenied))
+			Expect(err).To(MatchError(ContainSubstring("destination bucket other-bucket")))
+			Expect(rewrites).To(BeEmpty())
+		})
+	})
+
+	Describe("Properties()", func() {
+		It("includes the user metadata of the object", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/token":
+					w.Header().Set("Content-Type", "application/json")
+					w.Write([]byte(`{"access_token": "some-token", "token_type": "Bearer", "expires_in": 3600}`)) //nolint:errcheck
+				case r.Method == http.MethodGet && r.URL.Path == "/storage/v1/b/some-bucket/o/some-object":
+					w.Write([]byte(`{"bucket": "some-bucket", "name": "some-object", "etag": "some-etag", "size": "10", ` + //nolint:errcheck
+						`"updated": "2024-03-01T12:30:45Z", "metadata": {"owner": "team-a"}}`))
+				default:
+					w.WriteHeader(http.StatusBadRequest)
+				}
+			}))
+			DeferCleanup(server.Close)
+			GinkgoT().Setenv("STORAGE_EMULATOR_HOST", server.URL)
+
+			blobstore, err := client.New(context.Background(), &config.GCSCli{
+				BucketName:         "some-bucket",
+				CredentialsSource:  config.ServiceAccountFileCredentialsSource,
+				ServiceAccountFile: newServiceAccountFileWithTokenURI(server.URL + "/token"),
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			properties, err := blobstore.Properties(context.Background(), "some-object")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(properties).To(Equal(common.ObjectProperties{
+				ETag:          "some-etag",
+				LastModified:  time.Date(2024, 3, 1, 12, 30, 45, 0, time.UTC),
+				ContentLength: 10,
+				Metadata:      map[string]string{"owner": "team-a"},
+			}))
+		})
+
+		It("returns the same properties as the other storage types for an equivalent object", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/token":
+					w.Header().Set("Content-Type", "application/json")
+					w.Write([]byte(`{"access_token": "some-token", "token_type": "Bearer", "expires_in": 3600}`)) //nolint:errcheck
+				default:
+					w.Write([]byte(`{"bucket": "some-bucket", "name": "some-object", "etag": "9a0364b9e99bb480dd25e1f0284c8555", "size": "7", ` + //nolint:errcheck
+						`"updated": "2024-03-01T12:30:45.678Z", "metadata": {"owner": "team-a"}}`))
+				}
+			}))
+			DeferCleanup(server.Close)
+			GinkgoT().Setenv("STORAGE_EMULATOR_HOST", server.URL)
+
+			blobstore, err := client.New(context.Background(), &config.GCSCli{
+				BucketName:         "some-bucket",
+				CredentialsSource:  config.ServiceAccountFileCredentialsSource,
+				ServiceAccountFile: newServiceAccountFileWithTokenURI(server.URL + "/token"),
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			properties, err := blobstore.Properties(context.Background(), "some-object")
+			Expect(err).ToNot(HaveOccurred())
+			// The s3 and alioss tests expect the very same document
+			Expect(common.MarshalObjectProperties(properties)).To(BeEquivalentTo(`{
+  "content_length": 7,
+  "etag": "9a0364b9e99bb480dd25e1f0284c8555",
+  "last_modified": "2024-03-01T12:30:45Z",
+  "metadata": {
+    "owner": "team-a"
+  }
+}`))
+		})
+	})
+
+	Describe("proxy_url", func() {
+		var (
+			proxied   []string
+			gcsConfig *config.GCSCli
+		)
+
+		BeforeEach(func() {
+			proxied = nil
+			proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// A proxied request names the host it is meant for in an absolute URL
+				proxied = append(proxied, r.Method+" "+r.URL.Scheme+"://"+r.URL.Host+r.URL.Path)
+				switch r.URL.Path {
+				case "/token":
+					w.Header().Set("Content-Type", "application/json")
+					w.Write([]byte(`{"access_token": "some-token", "token_type": "Bearer", "expires_in": 3600}`)) //nolint:errcheck
+				default:
+					w.Write([]byte(`{"bucket": "some-bucket", "name": "some-object", "size": "10"}`)) //nolint:errcheck
+				}
+			}))
+			DeferCleanup(proxy.Close)
+			GinkgoT().Setenv("STORAGE_EMULATOR_HOST", "http://storage.invalid")
+
+			gcsConfig = &config.GCSCli{BucketName: "some-bucket", ProxyURL: proxy.URL}
+		})
+
+		It("sends the requests of a read-only client through the proxy", func() {
+			gcsConfig.CredentialsSource = config.NoneCredentialsSource
+			blobstore, err := client.New(context.Background(), gcsConfig)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(blobstore.Exists(context.Background(), "some-object")).To(BeTrue())
+			Expect(proxied).To(ContainElement("GET http://storage.invalid/storage/v1/b/some-bucket/o/some-object"))
+		})
+
+		It("fetches tokens through the proxy as well", func() {
+			gcsConfig.CredentialsSource = config.ServiceAccountFileCredentialsSource
+			gcsConfig.ServiceAccountFile = newServiceAccountFileWithTokenURI("http://oauth.invalid/token")
+			blobstore, err := client.New(context.Background(), gcsConfig)
+			Expect(err).ToNot(HaveOccurred())
+
+			head, err := blobstore.Head(context.Background(), "some-object")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(head.ContentLength).To(BeEquivalentTo(10))
+			Expect(proxied).To(ContainElements(
+				"POST http://oauth.invalid/token",
+				"GET http://storage.invalid/storage/v1/b/some-bucket/o/some-object",
+			))
+		})
+	})
+
+	Describe("Head()", func() {
+		var blobstore *client.GCSBlobstore
+
+		BeforeEach(func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/token":
+					w.Header().Set("Content-Type", "application/json")
+					w.Write([]byte(`{"access_token": "some-token", "token_type": "Bearer", "expires_in": 3600}`)) //nolint:errcheck
+				case r.Method == http.MethodGet && r.URL.Path == "/storage/v1/b/some-bucket/o/some-object":
+					w.Write([]byte(`{"bucket": "some-bucket", "name": "some-object", "etag": "some-etag", "size": "10", ` + //nolint:errcheck
+						`"updated": "2024-03-01T12:30:45Z", "contentType": "text/plain", "cacheControl": "no-cache", ` +
+						`"md5Hash": "mgNkuembtIDdJeHwKEyFVQ==", "storageClass": "NEARLINE", "generation": "1709296245000000", ` +
+						`"kmsKeyName": "projects/p/locations/l/keyRings/r/cryptoKeys/k", "metadata": {"owner": "team-a"}}`))
+				case r.Method == http.MethodGet && r.URL.Path == "/storage/v1/b/some-bucket/o/composite-object":
+					w.Write([]byte(`{"bucket": "some-bucket", "name": "composite-object", "size": "9", "crc32c": "4waSgw==", "componentCount": 2}`)) //nolint:errcheck
+				case r.Method == http.MethodGet && r.URL.Path == "/storage/v1/b/some-bucket/o/missing-object":
+					w.WriteHeader(http.StatusNotFound)
+				default:
+					w.WriteHeader(http.StatusBadRequest)
+				}
+			}))
+			DeferCleanup(server.Close)
+			GinkgoT().Setenv("STORAGE_EMULATOR_HOST", server.URL)
+
+			var err error
+			blobstore, err = client.New(context.Background(), &config.GCSCli{
+				BucketName:         "some-bucket",
+				CredentialsSource:  config.ServiceAccountFileCredentialsSource,
+				ServiceAccountFile: newServiceAccountFileWithTokenURI(server.URL + "/token"),
+			})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns the full attributes of the object", func() {
+			head, err := blobstore.Head(context.Background(), "some-object")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(head).To(Equal(common.ObjectHead{
+				ETag:          "some-etag",
+				LastModified:  time.Date(2024, 3, 1, 12, 30, 45, 0, time.UTC),
+				ContentLength: 10,
+				ContentType:   "text/plain",
+				CacheControl:  "no-cache",
+				ContentMD5:    "mgNkuembtIDdJeHwKEyFVQ==",
+				StorageClass:  "NEARLINE",
+				VersionID:     "1709296245000000",
+				Metadata:      map[string]string{"owner": "team-a"},
+				Encryption:    &common.ObjectEncryption{KeyID: "projects/p/locations/l/keyRings/r/cryptoKeys/k"},
+			}))
+		})
+
+		It("includes the CRC32C, which composite objects have instead of an MD5", func() {
+			head, err := blobstore.Head(context.Background(), "composite-object")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(head).To(Equal(common.ObjectHead{
+				ContentLength: 9,
+				Checksums:     map[string]string{common.ChecksumCRC32C: "4waSgw=="},
+			}))
+		})
+
+		It("reports a missing object as not found", func() {
+			_, err := blobstore.Head(context.Background(), "missing-object")
+			Expect(err).To(MatchError(common.ErrObjectNotFound))
+		})
+	})
+
+	Describe("with an operation context", func() {
+		It("aborts a request in flight when the context is cancelled", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/token":
+					w.Header().Set("Content-Type", "application/json")
+					w.Write([]byte(`{"access_token": "some-token", "token_type": "Bearer", "expires_in": 3600}`)) //nolint:errcheck
+				default:
+					select {
+					case <-r.Context().Done():
+					case <-time.After(10 * time.Second):
+					}
+				}
+			}))
+			DeferCleanup(server.Close)
+			GinkgoT().Setenv("STORAGE_EMULATOR_HOST", server.URL)
+
+			blobstore, err := client.New(context.Background(), &config.GCSCli{
+				BucketName:         "some-bucket",
+				CredentialsSource:  config.ServiceAccountFileCredentialsSource,
+				ServiceAccountFile: newServiceAccountFileWithTokenURI(server.URL + "/token"),
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(50*time.Millisecond, cancel)
+			started := time.Now()
+			_, err = blobstore.Exists(ctx, "some-object")
+			Expect(err).To(MatchError(context.Canceled))
+			Expect(time.Since(started)).To(BeNumerically("<", 5*time.Second))
+		})
+	})
+})
@@ -4,6 +4,8 @@ import (
 	"log/slog"
 	"net/http"
 	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
 )
 
 type roundTripperFunc func(req *http.Request) (*http.Response, error)
@@ -24,6 +26,7 @@ func NewLoggingTransport(base http.RoundTripper) http.RoundTripper {
 		start := time.Now()
 		resp, err := base.RoundTrip(req)
 		duration := time.Since(start)
+		countRequest(req, resp)
 
 		if err != nil {
 			slog.Error("http request failed",
@@ -45,3 +48,13 @@ func NewLoggingTransport(base http.RoundTripper) http.RoundTripper {
 	})
 
 }
+
+// countRequest feeds the request and its payload into the --stats counters. HEAD responses
+// advertise the object size without carrying a body, so only their request is counted.
+func countRequest(req *http.Request, resp *http.Response) {
+	common.IncRequests()
+	common.AddBytesTransferred(req.ContentLength)
+	if resp != nil && req.Method != http.MethodHead {
+		common.AddBytesTransferred(resp.ContentLength)
+	}
+}
@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"cloud.google.com/go/storage"
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+// GCS composes at most 32 objects in one request
+const maxComposeParts = 32
+
+// uploadsInParallel reports whether a file of the given size is uploaded in parallel parts.
+// Objects with a customer-supplied encryption key are always uploaded sequentially, just as
+// they are downloaded.
+func (client *GCSBlobstore) uploadsInParallel(size int64) bool {
+	threshold := client.config.ParallelUploadThreshold
+	return threshold > 0 && size >= threshold && client.config.EncryptionKey == nil
+}
+
+// uploadPartSize returns the size of the parts of a parallel upload of size bytes, grown if
+// needed so that all parts can be composed in one request
+func (client *GCSBlobstore) uploadPartSize(size int64) int64 {
+	partSize := int64(uploadChunkSize)
+	if client.config.UploadPartSize > 0 {
+		partSize = client.config.UploadPartSize
+	}
+	return max(partSize, (size+maxComposeParts-1)/maxComposeParts)
+}
+
+// uploadConcurrency returns how many parts of a parallel upload are uploaded at once
+func (client *GCSBlobstore) uploadConcurrency() int {
+	if client.config.UploadConcurrency > 0 {
+		return client.config.UploadConcurrency
+	}
+	return maxConcurrency
+}
+
+// putParallel uploads the size bytes of src as temporary part objects, several at once, composes
+// them into dest and returns the ETag of the new object. The parts are deleted afterwards, whether
+// the upload succeeded or not.
+func (client *GCSBlobstore) putParallel(ctx context.Context, src io.ReaderAt, size int64, dest string, options common.PutOptions) (string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	partSize := client.uploadPartSize(size)
+	parts := make([]*storage.ObjectHandle, (size+partSize-1)/partSize)
+	partPrefix := fmt.Sprintf("%s.part-%s-", dest, rand.Text()[:8])
+	for i := range parts {
+		parts[i] = client.getObjectHandle(client.authenticatedGCS, fmt.Sprintf("%s%d", partPrefix, i))
+	}
+	defer client.deleteParts(ctx, parts)
+
+	slog.Info("Uploading object in parallel parts", "bucket", client.config.BucketName, "object_name", dest, "parts", len(parts), "part_size", partSize)
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	limiter := common.NewConcurrencyLimiter(client.uploadConcurrency())
+	for i, part := range parts {
+		wg.Go(func() {
+			offset := int64(i) * partSize
+			section := io.NewSectionReader(src, offset, min(partSize, size-offset))
+			// The part takes its slot of the shared options.Concurrency only once it may start
+			err := limiter.Do(func() error {
+				return options.Concurrency.Do(func() error { return client.putPart(ctx, section, part) })
+			})
+			if err != nil {
+				// The object can't be composed anymore, stop the other parts
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		})
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return "", fmt.Errorf("uploading parts: %w", firstErr)
+	}
+
+	composer := client.getObjectHandle(client.authenticatedGCS, dest).ComposerFrom(parts...)
+	composer.StorageClass = client.config.StorageClass
+	composer.ContentType = options.ContentType
+	composer.Metadata = options.Metadata
+	composer.KMSKeyName = client.config.KMSKeyName
+	var attrs *storage.ObjectAttrs
+	err := options.Concurrency.Do(func() (err error) {
+		attrs, err = composer.Run(ctx)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("composing parts: %w", err)
+	}
+	return strings.Trim(attrs.Etag, `"`), nil
+}
+
+// putPart uploads a part of a parallel upload, retrying it up to retryAttempts times
+func (client *GCSBlobstore) putPart(ctx context.Context, src *io.SectionReader, part *storage.ObjectHandle) error {
+	var err error
+	for i := range retryAttempts {
+		if i > 0 {
+			common.IncRetries()
+		}
+
+		if _, err = src.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		// A part is a single request, the whole part is retried if it fails
+		writer := part.NewWriter(ctx)
+		writer.ChunkSize = 0
+		writer.KMSKeyName = client.config.KMSKeyName
+		if _, err = io.Copy(writer, src); err != nil {
+			writer.Close() //nolint:errcheck
+		} else if err = writer.Close(); err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+		slog.Error("Part upload failed", "object_name", part.ObjectName(), "attempt", fmt.Sprintf("%d/%d", i+1, retryAttempts), "error", err)
+	}
+	return fmt.Errorf("uploading %s after %d attempts: %w", part.ObjectName(), retryAttempts, err)
+}
+
+// deleteParts removes the temporary objects of a parallel upload
+func (client *GCSBlobstore) deleteParts(ctx context.Context, parts []*storage.ObjectHandle) {
+	// The parts are deleted even when the operation was cancelled
+	for _, part := range parts {
+		err := part.Delete(context.WithoutCancel(ctx))
+		if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+			slog.Warn("Deleting part of parallel upload", "object_name", part.ObjectName(), "error", err)
+		}
+	}
+}
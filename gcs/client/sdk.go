@@ -18,6 +18,7 @@ package client
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -26,10 +27,12 @@ import (
 	"golang.org/x/oauth2/google"
 	"golang.org/x/oauth2/jwt"
 
+	"google.golang.org/api/iamcredentials/v1"
 	"google.golang.org/api/option"
 
 	"net/http"
 
+	"cloud.google.com/go/compute/metadata"
 	"cloud.google.com/go/storage"
 	"github.com/cloudfoundry/storage-cli/common"
 	"github.com/cloudfoundry/storage-cli/gcs/client/middleware"
@@ -39,40 +42,37 @@ import (
 const uaString = "storage-cli-gcs"
 
 func newStorageClients(ctx context.Context, cfg *config.GCSCli) (*storage.Client, *storage.Client, error) {
-	publicClient, err := storage.NewClient(ctx, option.WithUserAgent(uaString), option.WithHTTPClient(http.DefaultClient))
+	transport, err := newTransport(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Tokens are fetched with the client in the context, through the proxy as well
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: transport})
+
+	// Without a proxy or logging the storage library builds its own transport
+	customTransport := cfg.ProxyURL != "" || common.IsDebug() || common.IsStatsEnabled()
+	if common.IsDebug() || common.IsStatsEnabled() {
+		transport = middleware.NewLoggingTransport(transport)
+	}
+
+	publicHTTPClient := http.DefaultClient
+	if customTransport {
+		publicHTTPClient = &http.Client{Transport: transport}
+	}
+	publicClient, err := storage.NewClient(ctx, option.WithUserAgent(uaString), option.WithHTTPClient(publicHTTPClient))
 	var authenticatedClient *storage.Client
 	var tokenSource oauth2.TokenSource
 	var token *jwt.Config
 
 	switch cfg.CredentialsSource {
 	case config.NoneCredentialsSource:
-		if common.IsDebug() {
-			httpClient := &http.Client{
-				Transport: middleware.NewLoggingTransport(http.DefaultTransport),
-			}
-			publicClient, err = storage.NewClient(ctx, option.WithUserAgent(uaString), option.WithHTTPClient(httpClient))
-		}
 	case config.DefaultCredentialsSource:
 		if tokenSource, err = google.DefaultTokenSource(ctx, storage.ScopeFullControl); err == nil {
-			if common.IsDebug() {
-				baseClient := oauth2.NewClient(ctx, tokenSource)
-				baseClient.Transport = middleware.NewLoggingTransport(baseClient.Transport)
-				authenticatedClient, err = storage.NewClient(ctx, option.WithHTTPClient(baseClient), option.WithUserAgent(uaString))
-
-			} else {
-				authenticatedClient, err = storage.NewClient(ctx, option.WithUserAgent(uaString), option.WithTokenSource(tokenSource)) //nolint:ineffassign,staticcheck
-			}
+			authenticatedClient, err = newAuthenticatedClient(ctx, tokenSource, transport, customTransport)
 		}
 	case config.ServiceAccountFileCredentialsSource:
 		if token, err = google.JWTConfigFromJSON([]byte(cfg.ServiceAccountFile), storage.ScopeFullControl); err == nil {
-			if common.IsDebug() {
-				tokenSource := token.TokenSource(ctx)
-				baseClient := oauth2.NewClient(ctx, tokenSource)
-				baseClient.Transport = middleware.NewLoggingTransport(baseClient.Transport)
-				authenticatedClient, err = storage.NewClient(ctx, option.WithHTTPClient(baseClient), option.WithUserAgent(uaString))
-			} else {
-				authenticatedClient, err = storage.NewClient(ctx, option.WithUserAgent(uaString), option.WithTokenSource(token.TokenSource(ctx))) //nolint:ineffassign,staticcheck
-			}
+			authenticatedClient, err = newAuthenticatedClient(ctx, token.TokenSource(ctx), transport, customTransport)
 		}
 	default:
 		return nil, nil, errors.New("unknown credentials_source in configuration")
@@ -80,6 +80,109 @@ func newStorageClients(ctx context.Context, cfg *config.GCSCli) (*storage.Client
 	return authenticatedClient, publicClient, err
 }
 
+// newTransport returns the transport requests are sent with, through the proxy_url if it is set
+func newTransport(cfg *config.GCSCli) (http.RoundTripper, error) {
+	if cfg.ProxyURL == "" {
+		return http.DefaultTransport, nil
+	}
+	proxy, err := common.ProxyFunc(cfg.ProxyURL)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	return transport, nil
+}
+
+// newAuthenticatedClient returns a client authorizing its requests with tokens from tokenSource,
+// sending them with transport if customTransport is set
+func newAuthenticatedClient(ctx context.Context, tokenSource oauth2.TokenSource, transport http.RoundTripper, customTransport bool) (*storage.Client, error) {
+	if !customTransport {
+		return storage.NewClient(ctx, option.WithUserAgent(uaString), option.WithTokenSource(tokenSource))
+	}
+	httpClient := &http.Client{Transport: &oauth2.Transport{Source: tokenSource, Base: transport}}
+	return storage.NewClient(ctx, option.WithHTTPClient(httpClient), option.WithUserAgent(uaString))
+}
+
+// extractClientEmail returns the service account email the credentials belong to,
+// or an empty string if the credentials are not tied to a service account key
+func extractClientEmail(ctx context.Context, cfg *config.GCSCli) (string, error) {
+	var credentialsJSON []byte
+	switch cfg.CredentialsSource {
+	case config.ServiceAccountFileCredentialsSource:
+		credentialsJSON = []byte(cfg.ServiceAccountFile)
+
+	case config.DefaultCredentialsSource:
+		creds, err := google.FindDefaultCredentials(ctx, storage.ScopeFullControl)
+		if err != nil {
+			return "", fmt.Errorf("finding default credentials: %w", err)
+		}
+		credentialsJSON = creds.JSON
+
+	case config.NoneCredentialsSource:
+		return "", nil
+
+	default:
+		return "", errors.New("unknown credentials_source")
+	}
+
+	if len(credentialsJSON) == 0 {
+		return "", nil
+	}
+
+	var serviceAccount struct {
+		ClientEmail string `json:"client_email"`
+	}
+	if err := json.Unmarshal(credentialsJSON, &serviceAccount); err != nil {
+		return "", fmt.Errorf("parsing credentials JSON: %w", err)
+	}
+	return serviceAccount.ClientEmail, nil
+}
+
+// errNoSigningIdentity is returned when signing without a json_key and the default credentials
+// don't belong to a service account that could sign
+var errNoSigningIdentity = errors.New("signing URLs requires a json_key or default credentials of a service account, e.g. of the GCE VM")
+
+// defaultCredentialsSigner returns the service account email of the application default credentials
+// and a function that signs bytes as that service account with the IAM Credentials API. This way URLs
+// can be signed without a private key, e.g. with the service account of a GCE VM, which needs the
+// "Service Account Token Creator" role on itself.
+func defaultCredentialsSigner(ctx context.Context) (string, func([]byte) ([]byte, error), error) {
+	creds, err := google.FindDefaultCredentials(ctx, iamcredentials.CloudPlatformScope)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %w", errNoSigningIdentity, err)
+	}
+
+	email, err := extractClientEmail(ctx, &config.GCSCli{CredentialsSource: config.DefaultCredentialsSource})
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %w", errNoSigningIdentity, err)
+	}
+	// Credentials of a VM are not a key file, the metadata server knows their service account
+	if email == "" && len(creds.JSON) == 0 && metadata.OnGCEWithContext(ctx) {
+		if email, err = metadata.EmailWithContext(ctx, "default"); err != nil {
+			return "", nil, fmt.Errorf("%w: getting the email of the VM's service account: %w", errNoSigningIdentity, err)
+		}
+	}
+	if email == "" {
+		return "", nil, errNoSigningIdentity
+	}
+
+	service, err := iamcredentials.NewService(ctx, option.WithCredentials(creds), option.WithUserAgent(uaString))
+	if err != nil {
+		return "", nil, fmt.Errorf("creating IAM credentials client: %w", err)
+	}
+	name := "projects/-/serviceAccounts/" + email
+	signBytes := func(payload []byte) ([]byte, error) {
+		request := &iamcredentials.SignBlobRequest{Payload: base64.StdEncoding.EncodeToString(payload)}
+		response, err := service.Projects.ServiceAccounts.SignBlob(name, request).Context(ctx).Do()
+		if err != nil {
+			return nil, fmt.Errorf("signing as %s with the IAM credentials API: %w", email, err)
+		}
+		return base64.StdEncoding.DecodeString(response.SignedBlob)
+	}
+	return email, signBytes, nil
+}
+
 func extractProjectID(ctx context.Context, cfg *config.GCSCli) (string, error) {
 	switch cfg.CredentialsSource {
 	case config.ServiceAccountFileCredentialsSource:
@@ -22,12 +22,14 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+
+	"github.com/cloudfoundry/storage-cli/common"
 )
 
 // GCSCli represents the configuration for the gcscli
 type GCSCli struct {
 	// BucketName is the GCS bucket operations will use.
-	BucketName string `json:"bucket_name"`
+	BucketName string `json:"bucket_name" required:"true"`
 	// CredentialsSource is the location of a Service Account File.
 	// If left empty, Application Default Credentials will be used if available.
 	// If equal to 'none', read-only scope will be used.
@@ -50,9 +52,34 @@ type GCSCli struct {
 	// GCS transparently encrypts data using server-side encryption keys.
 	// https://cloud.google.com/storage/docs/encryption
 	EncryptionKey []byte `json:"encryption_key"`
-
-	EncryptionKeyEncoded string
-	EncryptionKeySha256  string
+	// KMSKeyName is the resource name of a Cloud KMS key used to encrypt
+	// objects added to the bucket, e.g.
+	// projects/my-project/locations/us/keyRings/my-ring/cryptoKeys/my-key.
+	// Mutually exclusive with EncryptionKey.
+	// https://cloud.google.com/storage/docs/encryption/customer-managed-keys
+	KMSKeyName string `json:"kms_key_name"`
+
+	// ParallelUploadThreshold is the size in bytes from which files are
+	// uploaded in parallel parts that are composed into the object afterwards.
+	// Composite objects have a CRC32C but no MD5 checksum, and the upload
+	// needs permission to delete the temporary parts. If left empty, files
+	// are uploaded sequentially. Ignored with an encryption_key.
+	// https://cloud.google.com/storage/docs/parallel-composite-uploads
+	ParallelUploadThreshold int64 `json:"parallel_upload_threshold"`
+	// UploadConcurrency is the number of parts of a parallel upload that are
+	// uploaded at once. Default: 5
+	UploadConcurrency int `json:"upload_concurrency"`
+	// UploadPartSize is the size in bytes of the parts of a parallel upload.
+	// It grows as needed to fit a file into 32 parts. Default: 100MB
+	UploadPartSize int64 `json:"upload_part_size"`
+
+	// ProxyURL is the proxy all requests go through, including those for
+	// tokens, e.g. http://proxy:3128 or socks5://proxy:1080.
+	// If left empty, HTTP_PROXY, HTTPS_PROXY and NO_PROXY select the proxy.
+	ProxyURL string `json:"proxy_url"`
+
+	EncryptionKeyEncoded string `json:"-"`
+	EncryptionKeySha256  string `json:"-"`
 }
 
 // DefaultCredentialsSource specifies that credentials should be detected.
@@ -79,6 +106,10 @@ var ErrEmptyServiceAccountFile = errors.New("json_key must be set")
 // in the config is not exactly 32 bytes.
 var ErrWrongLengthEncryptionKey = errors.New("encryption_key not 32 bytes")
 
+// ErrConflictingEncryptionKeys is returned when both encryption_key and
+// kms_key_name are set in the config.
+var ErrConflictingEncryptionKeys = errors.New("encryption_key and kms_key_name are mutually exclusive")
+
 // NewFromReader returns the new gcscli configuration struct from the
 // contents of the reader.
 //
@@ -104,6 +135,14 @@ func NewFromReader(reader io.Reader) (GCSCli, error) {
 		return GCSCli{}, ErrWrongLengthEncryptionKey
 	}
 
+	if len(c.EncryptionKey) > 0 && c.KMSKeyName != "" {
+		return GCSCli{}, ErrConflictingEncryptionKeys
+	}
+
+	if _, err := common.ParseProxyURL(c.ProxyURL); err != nil {
+		return GCSCli{}, err
+	}
+
 	if len(c.EncryptionKey) > 0 {
 		c.EncryptionKeyEncoded = base64.StdEncoding.EncodeToString(c.EncryptionKey)
 
@@ -103,6 +103,28 @@ var _ = Describe("BlobstoreClient configuration", func() {
 		})
 	})
 
+	Describe("when encryption_key and kms_key_name are both specified", func() {
+		dummyJSONBytes := []byte(`{"encryption_key": "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8=", "kms_key_name": "projects/p/locations/us/keyRings/r/cryptoKeys/k", "bucket_name": "some-bucket"}`)
+		dummyJSONReader := bytes.NewReader(dummyJSONBytes)
+
+		It("returns an error", func() {
+			_, err := NewFromReader(dummyJSONReader)
+			Expect(err).To(Equal(ErrConflictingEncryptionKeys))
+		})
+	})
+
+	Describe("when kms_key_name is specified", func() {
+		dummyJSONBytes := []byte(`{"kms_key_name": "projects/p/locations/us/keyRings/r/cryptoKeys/k", "bucket_name": "some-bucket"}`)
+		dummyJSONReader := bytes.NewReader(dummyJSONBytes)
+
+		It("uses the given key", func() {
+			c, err := NewFromReader(dummyJSONReader)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(c.KMSKeyName).To(Equal("projects/p/locations/us/keyRings/r/cryptoKeys/k"))
+			Expect(c.EncryptionKey).To(BeNil())
+		})
+	})
+
 	Describe("when encryption_key is too long", func() {
 		// encryption_key = []byte{0, 1, 2, ..., 31, 32} as base64
 		dummyJSONBytes := []byte(`{"encryption_key": "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8g", "bucket_name": "some-bucket"}`)
@@ -169,4 +191,19 @@ var _ = Describe("BlobstoreClient configuration", func() {
 		})
 	})
 
+	Describe("when proxy_url is set", func() {
+		It("accepts http and SOCKS5 proxies", func() {
+			for _, proxyURL := range []string{"http://proxy:3128", "socks5://proxy:1080"} {
+				c, err := NewFromReader(bytes.NewReader([]byte(`{"bucket_name": "some-bucket", "proxy_url": "` + proxyURL + `"}`)))
+				Expect(err).To(BeNil())
+				Expect(c.ProxyURL).To(Equal(proxyURL))
+			}
+		})
+
+		It("returns an error for a url without host", func() {
+			_, err := NewFromReader(bytes.NewReader([]byte(`{"bucket_name": "some-bucket", "proxy_url": "http://"}`)))
+			Expect(err).To(MatchError("invalid proxy_url 'http://': host must be set"))
+		})
+	})
+
 })
@@ -40,19 +40,19 @@ func AssertLifecycleWorks(gcsCLIPath string, ctx AssertContext) {
 	storageType := "gcs"
 	session, err := RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "put", ctx.ContentFile, ctx.GCSFileName)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(BeZero())
+	Expect(session.ExitCode).To(BeZero())
 
 	session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "exists", ctx.GCSFileName)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(BeZero())
-	Expect(session.Err.Contents()).To(MatchRegexp("Object exists in bucket"))
+	Expect(session.ExitCode).To(BeZero())
+	Expect(session.Stderr).To(MatchRegexp("Object exists in bucket"))
 
 	tmpLocalFileName := "gcscli-download"
 	defer os.Remove(tmpLocalFileName) //nolint:errcheck
 
 	session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "get", ctx.GCSFileName, tmpLocalFileName)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(BeZero())
+	Expect(session.ExitCode).To(BeZero())
 
 	gottenBytes, err := os.ReadFile(tmpLocalFileName)
 	Expect(err).ToNot(HaveOccurred())
@@ -60,12 +60,12 @@ func AssertLifecycleWorks(gcsCLIPath string, ctx AssertContext) {
 
 	session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "delete", ctx.GCSFileName)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(BeZero())
+	Expect(session.ExitCode).To(BeZero())
 
 	session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "exists", ctx.GCSFileName)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(Equal(3))
-	Expect(session.Err.Contents()).To(MatchRegexp("Object does not exist in bucket"))
+	Expect(session.ExitCode).To(Equal(3))
+	Expect(session.Stderr).To(MatchRegexp("Object does not exist in bucket"))
 }
 
 func AssertDeleteRecursiveWithPrefixLifecycle(gcsCLIPath string, ctx AssertContext) {
@@ -84,39 +84,39 @@ func AssertDeleteRecursiveWithPrefixLifecycle(gcsCLIPath string, ctx AssertConte
 
 	session, err := RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "put", fileName1, dstObject1)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(BeZero())
+	Expect(session.ExitCode).To(BeZero())
 
 	session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "put", fileName2, dstObject2)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(BeZero())
+	Expect(session.ExitCode).To(BeZero())
 
 	session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "put", fileName3, dstObject3)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(BeZero())
+	Expect(session.ExitCode).To(BeZero())
 
 	session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "delete-recursive", prefix)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(BeZero())
+	Expect(session.ExitCode).To(BeZero())
 
 	session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "exists", dstObject3)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(BeZero())
-	Expect(session.Err.Contents()).To(MatchRegexp("Object exists in bucket"))
+	Expect(session.ExitCode).To(BeZero())
+	Expect(session.Stderr).To(MatchRegexp("Object exists in bucket"))
 
 	session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "exists", dstObject1)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(Equal(3))
-	Expect(session.Err.Contents()).To(MatchRegexp("Object does not exist in bucket"))
+	Expect(session.ExitCode).To(Equal(3))
+	Expect(session.Stderr).To(MatchRegexp("Object does not exist in bucket"))
 
 	session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "exists", dstObject1)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(Equal(3))
-	Expect(session.Err.Contents()).To(MatchRegexp("Object does not exist in bucket"))
+	Expect(session.ExitCode).To(Equal(3))
+	Expect(session.Stderr).To(MatchRegexp("Object does not exist in bucket"))
 
 	//cleanup artifact
 	session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "delete", dstObject3)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(BeZero())
+	Expect(session.ExitCode).To(BeZero())
 
 }
 
@@ -128,17 +128,17 @@ func AssertCopyLifecycle(gcsCLIPath string, ctx AssertContext) {
 
 	session, err := RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "put", ctx.ContentFile, dstNameToPut)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(BeZero())
+	Expect(session.ExitCode).To(BeZero())
 
 	session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "copy", dstNameToPut, dstNameToCopy)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(BeZero())
+	Expect(session.ExitCode).To(BeZero())
 
 	tmpFileName := "copy-lifecycle"
 	defer os.Remove(tmpFileName) //nolint:errcheck
 	session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "get", dstNameToCopy, tmpFileName)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(BeZero())
+	Expect(session.ExitCode).To(BeZero())
 
 	contentGet, err := os.ReadFile(tmpFileName)
 	Expect(err).ToNot(HaveOccurred())
@@ -146,11 +146,11 @@ func AssertCopyLifecycle(gcsCLIPath string, ctx AssertContext) {
 
 	session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "delete", dstNameToPut)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(BeZero())
+	Expect(session.ExitCode).To(BeZero())
 
 	session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "delete", dstNameToCopy)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(BeZero())
+	Expect(session.ExitCode).To(BeZero())
 }
 
 func AssertListMultipleWithPrefixLifecycle(gcsCLIPath string, ctx AssertContext) {
@@ -168,59 +168,59 @@ func AssertListMultipleWithPrefixLifecycle(gcsCLIPath string, ctx AssertContext)
 
 	session, err := RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "put", fileName1, dstObject1)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(BeZero())
+	Expect(session.ExitCode).To(BeZero())
 
 	session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "put", fileName2, dstObject2)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(BeZero())
+	Expect(session.ExitCode).To(BeZero())
 
 	session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "put", fileName3, dstObject3)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(BeZero())
+	Expect(session.ExitCode).To(BeZero())
 
 	session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "list", prefix)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(BeZero())
+	Expect(session.ExitCode).To(BeZero())
 
-	objs := string(session.Out.Contents())
+	objs := string(session.Stdout)
 	Expect(objs).To(ContainSubstring(dstObject1))
 	Expect(objs).To(ContainSubstring(dstObject2))
 	Expect(objs).ToNot(ContainSubstring(dstObject3))
 
 	session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "delete", dstObject1)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(BeZero())
+	Expect(session.ExitCode).To(BeZero())
 
 	session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "delete", dstObject2)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(BeZero())
+	Expect(session.ExitCode).To(BeZero())
 
 	session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "delete", dstObject3)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(BeZero())
+	Expect(session.ExitCode).To(BeZero())
 }
 
 func AssertPropertiesLifecycle(gcsCLIPath string, ctx AssertContext) {
 	storageType := "gcs"
 	session, err := RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "put", ctx.ContentFile, ctx.GCSFileName)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(BeZero())
+	Expect(session.ExitCode).To(BeZero())
 
 	session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "properties", ctx.GCSFileName)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(BeZero())
-	output := string(session.Out.Contents())
+	Expect(session.ExitCode).To(BeZero())
+	output := string(session.Stdout)
 	Expect(output).To(MatchRegexp(`"etag":\s*".+?"`))
 	Expect(output).To(MatchRegexp(`"last_modified":\s*".+?"`))
 	Expect(output).To(MatchRegexp(`"content_length":\s*\d+`))
 
 	session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "delete", ctx.GCSFileName)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(BeZero())
+	Expect(session.ExitCode).To(BeZero())
 
 	session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "properties", ctx.GCSFileName)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(session.ExitCode()).To(BeZero())
-	Expect(string(session.Out.Contents())).To(MatchRegexp("{}"))
+	Expect(session.ExitCode).To(BeZero())
+	Expect(string(session.Stdout)).To(MatchRegexp("{}"))
 
 }
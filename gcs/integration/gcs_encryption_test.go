@@ -17,11 +17,13 @@
 package integration
 
 import (
+	"context"
 	"crypto/sha256"
 	"io"
 	"log"
 	"os"
 
+	"github.com/cloudfoundry/storage-cli/common"
 	"github.com/cloudfoundry/storage-cli/gcs/client"
 	"github.com/cloudfoundry/storage-cli/gcs/config"
 	. "github.com/onsi/ginkgo/v2"
@@ -67,7 +69,7 @@ var _ = Describe("Integration", func() {
 
 			session, err := RunGCSCLI(gcsCLIPath, env.ConfigPath, storageType, "put", env.ContentFile, env.GCSFileName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(session.ExitCode()).To(BeZero())
+			Expect(session.ExitCode).To(BeZero())
 
 			blobstoreClient, err := client.New(env.ctx, env.Config)
 			Expect(err).ToNot(HaveOccurred())
@@ -76,12 +78,12 @@ var _ = Describe("Integration", func() {
 
 			tmpFileName := "gcscli-test-wrong-enc-key"
 			defer os.Remove(tmpFileName) //nolint:errcheck
-			err = blobstoreClient.Get(env.GCSFileName, tmpFileName)
+			err = blobstoreClient.Get(context.Background(), env.GCSFileName, tmpFileName, common.GetOptions{})
 			Expect(err).To(HaveOccurred())
 
 			session, err = RunGCSCLI(gcsCLIPath, env.ConfigPath, storageType, "delete", env.GCSFileName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(session.ExitCode()).To(BeZero())
+			Expect(session.ExitCode).To(BeZero())
 		})
 
 		// tests that uploading a blob with encryption
@@ -92,7 +94,7 @@ var _ = Describe("Integration", func() {
 
 			session, err := RunGCSCLI(gcsCLIPath, env.ConfigPath, storageType, "put", env.ContentFile, env.GCSFileName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(session.ExitCode()).To(BeZero())
+			Expect(session.ExitCode).To(BeZero())
 
 			blobstoreClient, err := client.New(env.ctx, env.Config)
 			Expect(err).ToNot(HaveOccurred())
@@ -101,12 +103,12 @@ var _ = Describe("Integration", func() {
 
 			tmpFileName := "gcscli-test-no-enc-key"
 			defer os.Remove(tmpFileName) //nolint:errcheck
-			err = blobstoreClient.Get(env.GCSFileName, tmpFileName)
+			err = blobstoreClient.Get(context.Background(), env.GCSFileName, tmpFileName, common.GetOptions{})
 			Expect(err).To(HaveOccurred())
 
 			session, err = RunGCSCLI(gcsCLIPath, env.ConfigPath, storageType, "delete", env.GCSFileName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(session.ExitCode()).To(BeZero())
+			Expect(session.ExitCode).To(BeZero())
 		})
 	})
 })
@@ -17,12 +17,14 @@
 package integration
 
 import (
-	context "context"
+	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"syscall"
 
+	"github.com/cloudfoundry/storage-cli/common"
 	"github.com/cloudfoundry/storage-cli/gcs/client"
 	"github.com/cloudfoundry/storage-cli/gcs/config"
 	. "github.com/onsi/ginkgo/v2"
@@ -56,7 +58,7 @@ var _ = Describe("Integration", func() {
 
 				session, err := RunGCSCLI(gcsCLIPath, env.ConfigPath, storageType, "delete", env.GCSFileName)
 				Expect(err).ToNot(HaveOccurred())
-				Expect(session.ExitCode()).To(BeZero())
+				Expect(session.ExitCode).To(BeZero())
 			},
 			configurations)
 
@@ -83,12 +85,45 @@ var _ = Describe("Integration", func() {
 				blobstoreClient, err := client.New(env.ctx, env.Config)
 				Expect(err).ToNot(HaveOccurred())
 
-				err = blobstoreClient.Put(largeFile, env.GCSFileName)
+				err = blobstoreClient.Put(context.Background(), largeFile, env.GCSFileName, common.PutOptions{})
 				Expect(err).ToNot(HaveOccurred())
 
-				blobstoreClient.Delete(env.GCSFileName) //nolint:errcheck
+				blobstoreClient.Delete(context.Background(), env.GCSFileName) //nolint:errcheck
 				Expect(err).ToNot(HaveOccurred())
 			})
+
+			It("can perform large file upload in parallel parts", func() {
+				if os.Getenv(NoLongEnv) != "" {
+					Skip(fmt.Sprintf(NoLongMsg, NoLongEnv))
+				}
+
+				const size = 1024 * 1024 * 64
+
+				content := GenerateRandomString(size)
+				largeFile := MakeContentFile(content)
+				defer os.Remove(largeFile) //nolint:errcheck
+
+				parallelConfig := *env.Config
+				parallelConfig.ParallelUploadThreshold = size / 2
+				parallelConfig.UploadPartSize = size / 8
+				blobstoreClient, err := client.New(env.ctx, &parallelConfig)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = blobstoreClient.Put(context.Background(), largeFile, env.GCSFileName, common.PutOptions{})
+				Expect(err).ToNot(HaveOccurred())
+				defer blobstoreClient.Delete(context.Background(), env.GCSFileName) //nolint:errcheck
+
+				downloaded := filepath.Join(GinkgoT().TempDir(), "downloaded")
+				err = blobstoreClient.Get(context.Background(), env.GCSFileName, downloaded, common.GetOptions{})
+				Expect(err).ToNot(HaveOccurred())
+				downloadedContent, err := os.ReadFile(downloaded)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(downloadedContent) == content).To(BeTrue(), "downloaded content differs from the uploaded one")
+
+				objects, err := blobstoreClient.List(context.Background(), env.GCSFileName)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(objects).To(Equal([]string{env.GCSFileName}), "temporary parts are left behind")
+			})
 		})
 
 		DescribeTable("Invalid Put should fail",
@@ -111,7 +146,7 @@ var _ = Describe("Integration", func() {
 					}
 				}()
 
-				err = blobstoreClient.Put(pipePath, env.GCSFileName)
+				err = blobstoreClient.Put(context.Background(), pipePath, env.GCSFileName, common.PutOptions{})
 				Expect(err).To(MatchError(ContainSubstring("illegal seek")))
 			},
 			configurations)
@@ -122,8 +157,8 @@ var _ = Describe("Integration", func() {
 
 				session, err := RunGCSCLI(gcsCLIPath, env.ConfigPath, storageType, "get", env.GCSFileName, "/dev/null")
 				Expect(err).ToNot(HaveOccurred())
-				Expect(session.ExitCode()).ToNot(BeZero())
-				Expect(session.Err.Contents()).To(ContainSubstring("object doesn't exist"))
+				Expect(session.ExitCode).ToNot(BeZero())
+				Expect(session.Stderr).To(ContainSubstring("object doesn't exist"))
 			},
 			configurations)
 
@@ -137,8 +172,8 @@ var _ = Describe("Integration", func() {
 
 			session, err := RunGCSCLI(gcsCLIPath, env.ConfigPath, storageType, "copy", "source-object", "dest-object")
 			Expect(err).ToNot(HaveOccurred())
-			Expect(session.ExitCode()).ToNot(BeZero())
-			Expect(string(session.Err.Contents())).To(ContainSubstring("object doesn't exist"))
+			Expect(session.ExitCode).ToNot(BeZero())
+			Expect(string(session.Stderr)).To(ContainSubstring("object doesn't exist"))
 
 		}, configurations)
 
@@ -158,7 +193,7 @@ var _ = Describe("Integration", func() {
 
 				session, err := RunGCSCLI(gcsCLIPath, env.ConfigPath, storageType, "ensure-storage-exists")
 				Expect(err).ToNot(HaveOccurred())
-				Expect(session.ExitCode()).To(BeZero())
+				Expect(session.ExitCode).To(BeZero())
 
 				deleteBucket(context.Background(), newCfg.BucketName, env.ConfigPath)
 			}, configurations)
@@ -169,7 +204,7 @@ var _ = Describe("Integration", func() {
 				env.AddConfig(cfg)
 				session, err := RunGCSCLI(gcsCLIPath, env.ConfigPath, storageType, "ensure-storage-exists")
 				Expect(err).ToNot(HaveOccurred())
-				Expect(session.ExitCode()).To(BeZero())
+				Expect(session.ExitCode).To(BeZero())
 
 			}, configurations)
 		})
@@ -192,11 +227,11 @@ var _ = Describe("Integration", func() {
 
 			session, err := RunGCSCLI(gcsCLIPath, env.ConfigPath, storageType, "delete-recursive")
 			Expect(err).ToNot(HaveOccurred())
-			Expect(session.ExitCode()).To(BeZero())
+			Expect(session.ExitCode).To(BeZero())
 
 			session, err = RunGCSCLI(gcsCLIPath, env.ConfigPath, storageType, "delete-recursive")
 			Expect(err).ToNot(HaveOccurred())
-			Expect(session.ExitCode()).To(BeZero())
+			Expect(session.ExitCode).To(BeZero())
 
 		}, configurations)
 	})
@@ -72,7 +72,7 @@ var _ = Describe("GCS Public Bucket", func() {
 			It("can check if it exists", func() {
 				session, err := RunGCSCLI(gcsCLIPath, publicEnv.ConfigPath, storageType, "exists", setupEnv.GCSFileName)
 				Expect(err).ToNot(HaveOccurred())
-				Expect(session.ExitCode()).To(BeZero())
+				Expect(session.ExitCode).To(BeZero())
 			})
 
 			It("can get", func() {
@@ -81,7 +81,7 @@ var _ = Describe("GCS Public Bucket", func() {
 
 				session, err := RunGCSCLI(gcsCLIPath, publicEnv.ConfigPath, storageType, "get", setupEnv.GCSFileName, tmpLocalFileName)
 				Expect(err).ToNot(HaveOccurred())
-				Expect(session.ExitCode()).To(BeZero(), fmt.Sprintf("unexpected '%s'", session.Err.Contents()))
+				Expect(session.ExitCode).To(BeZero(), fmt.Sprintf("unexpected '%s'", session.Stderr))
 
 				gottenBytes, err := os.ReadFile(tmpLocalFileName)
 				Expect(err).ToNot(HaveOccurred())
@@ -92,57 +92,57 @@ var _ = Describe("GCS Public Bucket", func() {
 		It("fails to get a missing file", func() {
 			session, err := RunGCSCLI(gcsCLIPath, publicEnv.ConfigPath, storageType, "get", setupEnv.GCSFileName, "/dev/null")
 			Expect(err).ToNot(HaveOccurred())
-			Expect(session.ExitCode()).ToNot(BeZero())
-			Expect(string(session.Err.Contents())).To(MatchRegexp("object doesn't exist"))
+			Expect(session.ExitCode).ToNot(BeZero())
+			Expect(string(session.Stderr)).To(MatchRegexp("object doesn't exist"))
 		})
 
 		It("fails to put", func() {
 			session, err := RunGCSCLI(gcsCLIPath, publicEnv.ConfigPath, storageType, "put", publicEnv.ContentFile, publicEnv.GCSFileName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(session.ExitCode()).ToNot(BeZero())
-			Expect(string(session.Err.Contents())).To(ContainSubstring(client.ErrInvalidROWriteOperation.Error()))
+			Expect(session.ExitCode).ToNot(BeZero())
+			Expect(string(session.Stderr)).To(ContainSubstring(client.ErrInvalidROWriteOperation.Error()))
 		})
 
 		It("fails to delete", func() {
 			session, err := RunGCSCLI(gcsCLIPath, publicEnv.ConfigPath, storageType, "delete", publicEnv.GCSFileName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(session.ExitCode()).ToNot(BeZero())
-			Expect(string(session.Err.Contents())).To(ContainSubstring(client.ErrInvalidROWriteOperation.Error()))
+			Expect(session.ExitCode).ToNot(BeZero())
+			Expect(string(session.Stderr)).To(ContainSubstring(client.ErrInvalidROWriteOperation.Error()))
 		})
 
 		It("fails to list", func() {
 			session, err := RunGCSCLI(gcsCLIPath, publicEnv.ConfigPath, storageType, "list", "prefix")
 			Expect(err).ToNot(HaveOccurred())
-			Expect(session.ExitCode()).ToNot(BeZero())
-			Expect(string(session.Err.Contents())).To(ContainSubstring(client.ErrInvalidROWriteOperation.Error()))
+			Expect(session.ExitCode).ToNot(BeZero())
+			Expect(string(session.Stderr)).To(ContainSubstring(client.ErrInvalidROWriteOperation.Error()))
 		})
 
 		It("fails to get properties", func() {
 			session, err := RunGCSCLI(gcsCLIPath, publicEnv.ConfigPath, storageType, "properties", publicEnv.GCSFileName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(session.ExitCode()).ToNot(BeZero())
-			Expect(string(session.Err.Contents())).To(ContainSubstring(client.ErrInvalidROWriteOperation.Error()))
+			Expect(session.ExitCode).ToNot(BeZero())
+			Expect(string(session.Stderr)).To(ContainSubstring(client.ErrInvalidROWriteOperation.Error()))
 		})
 
 		It("fails to delete-recursive", func() {
 			session, err := RunGCSCLI(gcsCLIPath, publicEnv.ConfigPath, storageType, "delete-recursive", "prefix")
 			Expect(err).ToNot(HaveOccurred())
-			Expect(session.ExitCode()).ToNot(BeZero())
-			Expect(string(session.Err.Contents())).To(ContainSubstring(client.ErrInvalidROWriteOperation.Error()))
+			Expect(session.ExitCode).ToNot(BeZero())
+			Expect(string(session.Stderr)).To(ContainSubstring(client.ErrInvalidROWriteOperation.Error()))
 		})
 
 		It("fails to copy", func() {
 			session, err := RunGCSCLI(gcsCLIPath, publicEnv.ConfigPath, storageType, "copy", publicEnv.GCSFileName, "destination-object")
 			Expect(err).ToNot(HaveOccurred())
-			Expect(session.ExitCode()).ToNot(BeZero())
-			Expect(string(session.Err.Contents())).To(ContainSubstring(client.ErrInvalidROWriteOperation.Error()))
+			Expect(session.ExitCode).ToNot(BeZero())
+			Expect(string(session.Stderr)).To(ContainSubstring(client.ErrInvalidROWriteOperation.Error()))
 		})
 
 		It("fails to create bucket", func() {
 			session, err := RunGCSCLI(gcsCLIPath, publicEnv.ConfigPath, storageType, "ensure-storage-exists")
 			Expect(err).ToNot(HaveOccurred())
-			Expect(session.ExitCode()).ToNot(BeZero())
-			Expect(string(session.Err.Contents())).To(ContainSubstring(client.ErrInvalidROWriteOperation.Error()))
+			Expect(session.ExitCode).ToNot(BeZero())
+			Expect(string(session.Stderr)).To(ContainSubstring(client.ErrInvalidROWriteOperation.Error()))
 		})
 	})
 })
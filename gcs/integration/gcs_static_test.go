@@ -50,15 +50,15 @@ var _ = Describe("Integration", func() {
 		It("validates the action is valid", func() {
 			session, err := RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "sign", ctx.GCSFileName, "not-valid", "1h")
 			Expect(err).NotTo(HaveOccurred())
-			Expect(session.ExitCode()).ToNot(Equal(0))
+			Expect(session.ExitCode).ToNot(Equal(0))
 		})
 
 		It("can generate a signed url for a given object and action", func() {
 			session, err := RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "sign", ctx.GCSFileName, "put", "1h")
 
 			Expect(err).ToNot(HaveOccurred())
-			Expect(session.ExitCode()).To(Equal(0))
-			url := string(session.Out.Contents())
+			Expect(session.ExitCode).To(Equal(0))
+			url := string(session.Stdout)
 			Expect(url).To(MatchRegexp("https://"))
 
 			body := strings.NewReader(`bar`)
@@ -73,10 +73,40 @@ var _ = Describe("Integration", func() {
 			//delete test artifact
 			session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "delete", ctx.GCSFileName)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(session.ExitCode()).To(BeZero())
+			Expect(session.ExitCode).To(BeZero())
 
 		})
 
+		It("can generate a signed put url bound to a content type", func() {
+			session, err := RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "sign", "--content-type", "text/plain", ctx.GCSFileName, "put", "1h")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(session.ExitCode).To(Equal(0))
+			url := string(session.Stdout)
+
+			req, err := http.NewRequest("PUT", url, strings.NewReader(`bar`))
+			Expect(err).ToNot(HaveOccurred())
+			req.Header.Set("Content-Type", "application/octet-stream")
+
+			resp, err := http.DefaultClient.Do(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(403))
+			resp.Body.Close() //nolint:errcheck
+
+			req, err = http.NewRequest("PUT", url, strings.NewReader(`bar`))
+			Expect(err).ToNot(HaveOccurred())
+			req.Header.Set("Content-Type", "text/plain")
+
+			resp, err = http.DefaultClient.Do(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(200))
+			resp.Body.Close() //nolint:errcheck
+
+			//delete test artifact
+			session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "delete", ctx.GCSFileName)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(session.ExitCode).To(BeZero())
+		})
+
 		Context("encryption key is set", func() {
 			var key string
 
@@ -99,12 +129,12 @@ var _ = Describe("Integration", func() {
 
 				session, err := RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "sign", ctx.GCSFileName, "put", "1h")
 				Expect(err).ToNot(HaveOccurred())
-				signedPutUrl := string(session.Out.Contents())
+				signedPutUrl := string(session.Stdout)
 				Expect(signedPutUrl).ToNot(BeNil())
 
 				session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "sign", ctx.GCSFileName, "get", "1h")
 				Expect(err).ToNot(HaveOccurred())
-				signedGetUrl := string(session.Out.Contents())
+				signedGetUrl := string(session.Stdout)
 				Expect(signedGetUrl).ToNot(BeNil())
 
 				stuff := strings.NewReader(`stuff`)                      //nolint:errcheck
@@ -133,7 +163,7 @@ var _ = Describe("Integration", func() {
 				//delete test artifact
 				session, err = RunGCSCLI(gcsCLIPath, ctx.ConfigPath, storageType, "delete", ctx.GCSFileName)
 				Expect(err).ToNot(HaveOccurred())
-				Expect(session.ExitCode()).To(BeZero())
+				Expect(session.ExitCode).To(BeZero())
 			})
 		})
 	})
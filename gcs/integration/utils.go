@@ -21,13 +21,10 @@ import (
 	"encoding/json"
 	"math/rand"
 	"os"
-	"os/exec"
-	"time"
 
+	"github.com/cloudfoundry/storage-cli/clitest"
 	"github.com/cloudfoundry/storage-cli/gcs/config"
-	"github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega" //nolint:staticcheck
-	"github.com/onsi/gomega/gexec"
 )
 
 const alphanum = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
@@ -81,10 +78,9 @@ func deleteBucket(ctx context.Context, bucketName string, configPath string) {
 	Expect(err).ToNot(HaveOccurred())
 }
 
-// RunGCSCLI run the gcscli and outputs the session
+// RunGCSCLI runs the gcscli and returns its output and exit code
 // after waiting for it to finish
-func RunGCSCLI(gcsCLIPath, configPath, storageType, subcommand string, args ...string) (*gexec.Session, error) {
-
+func RunGCSCLI(gcsCLIPath, configPath, storageType, subcommand string, args ...string) (*clitest.Result, error) {
 	cmdArgs := []string{
 		"-c",
 		configPath,
@@ -95,14 +91,5 @@ func RunGCSCLI(gcsCLIPath, configPath, storageType, subcommand string, args ...s
 		subcommand,
 	}
 	cmdArgs = append(cmdArgs, args...)
-
-	command := exec.Command(gcsCLIPath, cmdArgs...)
-	gexecSession, err := gexec.Start(command,
-		ginkgo.GinkgoWriter, ginkgo.GinkgoWriter)
-	if err != nil {
-		return nil, err
-	}
-	gexecSession.Wait(1 * time.Minute)
-
-	return gexecSession, nil
+	return clitest.Run(gcsCLIPath, cmdArgs...)
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
@@ -8,7 +9,9 @@ import (
 	"log"
 	"log/slog"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strings"
 
 	"github.com/cloudfoundry/storage-cli/common"
 	storage "github.com/cloudfoundry/storage-cli/storage"
@@ -20,13 +23,12 @@ func fatalLog(cmd string, err error) {
 	if err == nil {
 		return
 	}
-	// If the object exists the exit status is 0, otherwise it is 3
-	// We are using `3` since `1` and `2` have special meanings
-	if _, ok := err.(*storage.NotExistsError); ok {
-		os.Exit(3)
+	// A missing object or an empty `list --fail-if-empty` exits with 3, not as a failure
+	code := storage.ExitCode(err)
+	if code != storage.ExitCodeNotFound {
+		slog.Error("performing operation", "command", cmd, "error", err)
 	}
-	slog.Error("performing operation", "command", cmd, "error", err)
-	os.Exit(1)
+	os.Exit(code)
 
 }
 
@@ -62,24 +64,22 @@ func parseLogLevel(logLevel string) slog.Level {
 
 }
 
-// Configure slog to be json formated, set log level and
-// stream to file if provided, by default it streams to os.Stderr
-func configureSlog(m io.Writer, logLevel string) {
-	level := parseLogLevel(logLevel)
-
-	hOpt := &slog.HandlerOptions{Level: level}
-
-	logger := slog.New(slog.NewJSONHandler(m, hOpt))
-	slog.SetDefault(logger)
-}
-
 func main() {
 
-	configPath := flag.String("c", "", "configuration path")
+	configPath := flag.String("c", "", "configuration path, - to read it from stdin (default: the JSON in the STORAGE_CLI_CONFIG environment variable)")
 	showVer := flag.Bool("v", false, "version")
 	storageType := flag.String("s", "", "storage type: azurebs|alioss|s3|gcs|dav")
-	logFile := flag.String("log-file", "", "optional file with full path to write logs(if not specified log to os.Stderr, default behavior)")
+	profile := flag.String("profile", "", "use this named profile of a config file holding several, its provider replaces -s")
+	logFile := flag.String("log-file", "", "optional file with full path to write all logs to instead of os.Stderr")
 	logLevel := flag.String("log-level", "warn", "log level: debug|info|warn|error")
+	var debug bool
+	flag.BoolVar(&debug, "debug", false, "shorthand for -log-level debug, also logs the HTTP requests of s3 and gcs")
+	flag.BoolVar(&debug, "d", false, "shorthand for -debug")
+	endpointHealthTimeout := flag.Duration("endpoint-health-timeout", 0, "dial the configured endpoint with this timeout before running the command and fail fast if it is unreachable, e.g. 2s (0 disables the check)")
+	stats := flag.Bool("stats", false, "print bytes transferred, number of requests, retries and elapsed time as JSON to stderr once the command finished")
+	whoami := flag.Bool("whoami", false, "print the credentials source and identity the client resolves to, same as the whoami command")
+	noFolderPrefix := flag.Bool("no-folder-prefix", false, "use object keys as given, without the folder_name of the s3 config in front of them")
+	timeout := flag.Duration("timeout", 0, "abort the command if it hasn't finished after this long, e.g. 10m (0 waits indefinitely); Ctrl-C aborts it as well")
 	flag.Parse()
 
 	if *showVer {
@@ -87,43 +87,100 @@ func main() {
 		os.Exit(0)
 	}
 
-	// configure slog
-	writers := []io.Writer{os.Stderr}
+	if debug {
+		*logLevel = "debug"
+	}
+
+	// configure storage-cli config, logs go to the log file if given, to stderr otherwise
+	var logWriter io.Writer = os.Stderr
 	if *logFile != "" {
 		f := createOrUseProvided(*logFile)
 		defer f.Close() //nolint:errcheck
-		writers = append(writers, f)
+		logWriter = f
+	}
+	common.InitConfig(parseLogLevel(*logLevel), logWriter)
+	if *stats {
+		common.EnableStats()
 	}
-	configureSlog(io.MultiWriter(writers...), *logLevel)
 
-	// configure storage-cli config
-	common.InitConfig(parseLogLevel(*logLevel))
+	// Ctrl-C and --timeout cancel the requests of s3, gcs and azurebs. A second Ctrl-C ends the cli
+	// right away, e.g. for storage types that don't abort their requests.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	// the schema command describes the config file, so it must not require one
+	nonFlagArgs := flag.Args()
+	if len(nonFlagArgs) > 0 && nonFlagArgs[0] == "schema" {
+		fatalLog("schema", storage.PrintConfigSchema(*storageType))
+		os.Exit(0)
+	}
 
-	// check client config file exists
-	configFile, err := os.Open(*configPath)
+	// put-signed only talks to the signed url, it needs neither a config nor a client
+	if len(nonFlagArgs) > 0 && nonFlagArgs[0] == "put-signed" {
+		fatalLog("put-signed", storage.NewCommandExecuter(nil).Execute(ctx, "put-signed", nonFlagArgs[1:]))
+		os.Exit(0)
+	}
+
+	// open the client config, from the file, stdin or the environment
+	configFile, err := storage.OpenConfig(*configPath)
 	if err != nil {
 		fatalLog("", err)
 	}
 	defer configFile.Close() //nolint:errcheck
 
 	// create client
-	client, err := storage.NewStorageClient(*storageType, configFile)
+	storage.SetEndpointHealthTimeout(*endpointHealthTimeout)
+	storage.SetAllowedEndpoints(strings.Split(os.Getenv(storage.AllowedEndpointsEnvVar), ","))
+	clientOptions := storage.ClientOptions{NoFolderPrefix: *noFolderPrefix}
+	var client storage.Storager
+	if *profile != "" {
+		client, err = storage.NewStorageClientFromProfile(*storageType, *profile, configFile, clientOptions)
+	} else {
+		client, err = storage.NewStorageClientWithOptions(*storageType, configFile, clientOptions)
+	}
 	if err != nil {
 		fatalLog("", err)
 	}
 
+	if common.IsDebug() {
+		storage.LogIdentity(ctx, client)
+	}
+
 	// inject client into executor
 	cex := storage.NewCommandExecuter(client)
 
+	if *whoami {
+		fatalLog("whoami", cex.Execute(ctx, "whoami", nil))
+		os.Exit(0)
+	}
+
 	// simple check for any command
-	nonFlagArgs := flag.Args()
 	if len(nonFlagArgs) < 1 {
 		fatalLog("", errors.New("expected at least 1 argument (command) got 0"))
 	}
 
 	// execute command
 	cmd := nonFlagArgs[0]
-	err = cex.Execute(cmd, nonFlagArgs[1:])
+	err = cex.Execute(ctx, cmd, nonFlagArgs[1:])
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("not finished within the timeout of %s: %w", *timeout, err)
+	} else if err != nil && ctx.Err() != nil {
+		err = fmt.Errorf("interrupted: %w", err)
+	}
+	if *stats {
+		if statsErr := storage.PrintStats(os.Stderr); statsErr != nil {
+			slog.Warn("printing stats", "error", statsErr)
+		}
+	}
 	fatalLog(cmd, err)
 
 }
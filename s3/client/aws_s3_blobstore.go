@@ -1,22 +1,32 @@
 package client
 
 import (
-	"encoding/json"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"context"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 
+	"github.com/cloudfoundry/storage-cli/common"
 	"github.com/cloudfoundry/storage-cli/s3/config"
 )
 
@@ -33,6 +43,11 @@ const (
 	defaultMultipartCopyThreshold = int64(5 * 1024 * 1024 * 1024) // 5 GB
 	defaultMultipartCopyPartSize  = int64(100 * 1024 * 1024)      // 100 MB
 	maxRetries                    = 3
+	// Failed uploads are retried after this delay, doubling with every retry up to maxUploadRetryDelay
+	defaultUploadRetryBackoff = 1 * time.Second
+	maxUploadRetryDelay       = 1 * time.Minute
+	// S3 Express One Zone directory bucket names end with this suffix, only those support RenameObject
+	directoryBucketSuffix = "--x-s3"
 )
 
 // awsS3Client encapsulates AWS S3 blobstore interactions
@@ -42,7 +57,7 @@ type awsS3Client struct {
 }
 
 // Get fetches a blob, destination will be overwritten if exists
-func (b *awsS3Client) Get(src string, dest io.WriterAt) error {
+func (b *awsS3Client) Get(ctx context.Context, src string, dest io.WriterAt) error {
 	cfg := b.s3cliConfig
 
 	downloader := manager.NewDownloader(b.s3Client, func(d *manager.Downloader) { //nolint:staticcheck
@@ -57,9 +72,10 @@ func (b *awsS3Client) Get(src string, dest io.WriterAt) error {
 		}
 	})
 
-	_, err := downloader.Download(context.TODO(), dest, &s3.GetObjectInput{ //nolint:staticcheck
-		Bucket: aws.String(b.s3cliConfig.BucketName),
-		Key:    b.key(src),
+	_, err := downloader.Download(ctx, dest, &s3.GetObjectInput{ //nolint:staticcheck
+		Bucket:       aws.String(b.s3cliConfig.BucketName),
+		RequestPayer: b.requestPayer(),
+		Key:          b.key(src),
 	})
 
 	if err != nil {
@@ -69,14 +85,101 @@ func (b *awsS3Client) Get(src string, dest io.WriterAt) error {
 	return nil
 }
 
-// Put uploads a blob
-func (b *awsS3Client) Put(src io.ReadSeeker, dest string) error {
+// GetRange fetches the blob starting at offset and writes it to dest at the same offset,
+// so that an interrupted download can be resumed without refetching what is already there.
+// With ifMatch set it fails with common.ErrPreconditionFailed once the ETag of the blob differs.
+func (b *awsS3Client) GetRange(ctx context.Context, src string, dest io.WriterAt, offset int64, ifMatch string) error {
+	var ifMatchHeader *string
+	if ifMatch != "" {
+		ifMatchHeader = aws.String(`"` + strings.Trim(ifMatch, `"`) + `"`)
+	}
+
+	headOutput, err := b.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:       aws.String(b.s3cliConfig.BucketName),
+		RequestPayer: b.requestPayer(),
+		Key:          b.key(src),
+		IfMatch:      ifMatchHeader,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get object metadata: %w", preconditionError(err))
+	}
+	if headOutput.ContentLength == nil {
+		return errors.New("unable to determine object content length from S3 metadata")
+	}
+
+	size := *headOutput.ContentLength
+	if offset > size {
+		return fmt.Errorf("local file is larger (%d bytes) than the remote object (%d bytes)", offset, size)
+	}
+	if offset == size {
+		slog.Info("Object already fully downloaded", "bucket", b.s3cliConfig.BucketName, "blob", src, "size", size)
+		return nil
+	}
+
+	slog.Info("Resuming download", "bucket", b.s3cliConfig.BucketName, "blob", src, "offset", offset, "size", size)
+	output, err := b.s3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket:       aws.String(b.s3cliConfig.BucketName),
+		RequestPayer: b.requestPayer(),
+		Key:          b.key(src),
+		Range:        aws.String(fmt.Sprintf("bytes=%d-", offset)),
+		IfMatch:      ifMatchHeader,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get object range: %w", preconditionError(err))
+	}
+	defer output.Body.Close() //nolint:errcheck
+
+	written, err := io.Copy(io.NewOffsetWriter(dest, offset), output.Body)
+	if err != nil {
+		return fmt.Errorf("failed to write object range: %w", err)
+	}
+	if offset+written != size {
+		return fmt.Errorf("downloaded size mismatch: expected %d bytes, got %d", size, offset+written)
+	}
+
+	return nil
+}
+
+// limitedUploadClient sends the object and part uploads of an upload each in a slot of limiter
+type limitedUploadClient struct {
+	manager.UploadAPIClient //nolint:staticcheck
+	limiter                 *common.ConcurrencyLimiter
+}
+
+func (c limitedUploadClient) PutObject(ctx context.Context, input *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	c.limiter.Acquire()
+	defer c.limiter.Release()
+	return c.UploadAPIClient.PutObject(ctx, input, optFns...)
+}
+
+func (c limitedUploadClient) UploadPart(ctx context.Context, input *s3.UploadPartInput, optFns ...func(*s3.Options)) (*s3.UploadPartOutput, error) {
+	c.limiter.Acquire()
+	defer c.limiter.Release()
+	return c.UploadAPIClient.UploadPart(ctx, input, optFns...)
+}
+
+// uploadClient returns the client uploads are sent with, limited by limiter
+func (b *awsS3Client) uploadClient(limiter *common.ConcurrencyLimiter) limitedUploadClient {
+	return limitedUploadClient{UploadAPIClient: b.s3Client, limiter: limiter}
+}
+
+// preconditionError marks err with common.ErrPreconditionFailed if S3 rejected an If-Match
+func preconditionError(err error) error {
+	var respErr *smithyhttp.ResponseError
+	if errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusPreconditionFailed {
+		return fmt.Errorf("%w: %w", common.ErrPreconditionFailed, err)
+	}
+	return err
+}
+
+// Put uploads a blob and returns its ETag
+func (b *awsS3Client) Put(ctx context.Context, src io.ReadSeeker, dest string, options common.PutOptions) (string, error) {
 	cfg := b.s3cliConfig
 	if cfg.CredentialsSource == config.NoneCredentialsSource {
-		return errorInvalidCredentialsSourceValue
+		return "", errorInvalidCredentialsSourceValue
 	}
 
-	uploader := manager.NewUploader(b.s3Client, func(u *manager.Uploader) { //nolint:staticcheck
+	uploader := manager.NewUploader(b.uploadClient(options.Concurrency), func(u *manager.Uploader) { //nolint:staticcheck
 		u.LeavePartsOnError = false
 
 		u.Concurrency = defaultTransferConcurrency
@@ -106,33 +209,40 @@ func (b *awsS3Client) Put(src io.ReadSeeker, dest string) error {
 	if cfg.SSEKMSKeyID != "" {
 		uploadInput.SSEKMSKeyId = aws.String(cfg.SSEKMSKeyID)
 	}
+	if options.ContentType != "" {
+		uploadInput.ContentType = aws.String(options.ContentType)
+	}
+	uploadInput.Metadata = options.Metadata
 
 	retry := 0
 	for {
-		putResult, err := uploader.Upload(context.TODO(), uploadInput) //nolint:staticcheck
+		putResult, err := uploader.Upload(ctx, uploadInput) //nolint:staticcheck
 		if err != nil {
 			if _, ok := err.(manager.MultiUploadFailure); ok {
-				if retry == maxRetries {
-					return fmt.Errorf("upload retry limit exceeded: %s", err.Error())
+				if retry == b.uploadRetryLimit() {
+					return "", fmt.Errorf("upload retry limit exceeded: %s", err.Error())
 				}
 				retry++
-				time.Sleep(time.Second * time.Duration(retry))
+				common.IncRetries()
+				if err := sleepOrDone(ctx, b.uploadRetryDelay(retry)); err != nil {
+					return "", fmt.Errorf("upload failure: %w", err)
+				}
 				continue
 			}
-			return fmt.Errorf("upload failure: %s", err.Error())
+			return "", fmt.Errorf("upload failure: %s", err.Error())
 		}
 
 		slog.Info("Successfully uploaded file", "location", putResult.Location)
-		return nil
+		return strings.Trim(aws.ToString(putResult.ETag), `"`), nil
 	}
 }
 
-// PutSinglePart uploads a blob using a single PutObject call (no multipart).
+// PutSinglePart uploads a blob using a single PutObject call (no multipart) and returns its ETag.
 // Use this for small files where multipart overhead is unnecessary.
-func (b *awsS3Client) PutSinglePart(src io.ReadSeeker, dest string) error {
+func (b *awsS3Client) PutSinglePart(ctx context.Context, src io.ReadSeeker, dest string, options common.PutOptions) (string, error) {
 	cfg := b.s3cliConfig
 	if cfg.CredentialsSource == config.NoneCredentialsSource {
-		return errorInvalidCredentialsSourceValue
+		return "", errorInvalidCredentialsSourceValue
 	}
 
 	input := &s3.PutObjectInput{
@@ -146,33 +256,125 @@ func (b *awsS3Client) PutSinglePart(src io.ReadSeeker, dest string) error {
 	if cfg.SSEKMSKeyID != "" {
 		input.SSEKMSKeyId = aws.String(cfg.SSEKMSKeyID)
 	}
+	if options.ContentType != "" {
+		input.ContentType = aws.String(options.ContentType)
+	}
+	input.Metadata = options.Metadata
 
 	retry := 0
 	for {
 		// Seek back to the start on retries so the full body is re-sent
 		if retry > 0 {
 			if _, seekErr := src.Seek(0, io.SeekStart); seekErr != nil {
-				return fmt.Errorf("failed to seek source for retry: %s", seekErr.Error())
+				return "", fmt.Errorf("failed to seek source for retry: %s", seekErr.Error())
 			}
 		}
 
-		_, err := b.s3Client.PutObject(context.TODO(), input)
+		output, err := b.uploadClient(options.Concurrency).PutObject(ctx, input)
 		if err != nil {
-			if retry == maxRetries {
-				return fmt.Errorf("single part upload retry limit exceeded: %s", err.Error())
+			if retry == b.uploadRetryLimit() {
+				return "", fmt.Errorf("single part upload retry limit exceeded: %s", err.Error())
 			}
 			retry++
-			time.Sleep(time.Second * time.Duration(retry))
+			common.IncRetries()
+			if err := sleepOrDone(ctx, b.uploadRetryDelay(retry)); err != nil {
+				return "", fmt.Errorf("single part upload failure: %w", err)
+			}
 			continue
 		}
 
 		slog.Info("Successfully uploaded file (single part)", "key", dest)
-		return nil
+		return strings.Trim(aws.ToString(output.ETag), `"`), nil
 	}
 }
 
+// PutParts uploads src as a multipart upload whose parts are exactly the ones listed in the manifest.
+// The manifest is expected to be validated against the size of src.
+func (b *awsS3Client) PutParts(ctx context.Context, src io.ReaderAt, dest string, manifest common.UploadManifest, options common.PutOptions) error {
+	cfg := b.s3cliConfig
+	if cfg.CredentialsSource == config.NoneCredentialsSource {
+		return errorInvalidCredentialsSourceValue
+	}
+
+	createInput := &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(cfg.BucketName),
+		Key:    b.key(dest),
+	}
+	if cfg.ServerSideEncryption != "" {
+		createInput.ServerSideEncryption = types.ServerSideEncryption(cfg.ServerSideEncryption)
+	}
+	if cfg.SSEKMSKeyID != "" {
+		createInput.SSEKMSKeyId = aws.String(cfg.SSEKMSKeyID)
+	}
+	if options.ContentType != "" {
+		createInput.ContentType = aws.String(options.ContentType)
+	}
+	createInput.Metadata = options.Metadata
+
+	createOutput, err := b.s3Client.CreateMultipartUpload(ctx, createInput)
+	if err != nil {
+		return fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+
+	uploadID := *createOutput.UploadId
+
+	var completed bool
+	defer func() {
+		if !completed {
+			// The upload is aborted even when the operation was cancelled
+			_, err := b.s3Client.AbortMultipartUpload(context.WithoutCancel(ctx), &s3.AbortMultipartUploadInput{
+				Bucket:   aws.String(cfg.BucketName),
+				Key:      b.key(dest),
+				UploadId: aws.String(uploadID),
+			})
+			if err != nil {
+				slog.Warn("Failed to abort multipart upload", "uploadId", uploadID, "error", err)
+			}
+		}
+	}()
+
+	uploader := b.uploadClient(options.Concurrency)
+	parts := manifest.SortedParts()
+	completedParts := make([]types.CompletedPart, 0, len(parts))
+	for _, part := range parts {
+		output, err := uploader.UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:        aws.String(cfg.BucketName),
+			Key:           b.key(dest),
+			Body:          io.NewSectionReader(src, part.Offset, part.Size),
+			ContentLength: aws.Int64(part.Size),
+			PartNumber:    aws.Int32(part.PartNumber),
+			UploadId:      aws.String(uploadID),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to upload part %d: %w", part.PartNumber, err)
+		}
+
+		completedParts = append(completedParts, types.CompletedPart{
+			ETag:       output.ETag,
+			PartNumber: aws.Int32(part.PartNumber),
+		})
+		slog.Debug("Uploaded part", "part", part.PartNumber, "offset", part.Offset, "size", part.Size)
+	}
+
+	_, err = b.s3Client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:   aws.String(cfg.BucketName),
+		Key:      b.key(dest),
+		UploadId: aws.String(uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{
+			Parts: completedParts,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+
+	completed = true
+	slog.Info("Successfully uploaded file (manifest)", "key", dest, "parts", len(parts))
+	return nil
+}
+
 // Delete removes a blob - no error is returned if the object does not exist
-func (b *awsS3Client) Delete(dest string) error {
+func (b *awsS3Client) Delete(ctx context.Context, dest string) error {
 	if b.s3cliConfig.CredentialsSource == config.NoneCredentialsSource {
 		return errorInvalidCredentialsSourceValue
 	}
@@ -182,7 +384,7 @@ func (b *awsS3Client) Delete(dest string) error {
 		Key:    b.key(dest),
 	}
 
-	_, err := b.s3Client.DeleteObject(context.TODO(), deleteParams)
+	_, err := b.s3Client.DeleteObject(ctx, deleteParams)
 
 	if err == nil {
 		return nil
@@ -196,13 +398,14 @@ func (b *awsS3Client) Delete(dest string) error {
 }
 
 // Exists checks if blob exists
-func (b *awsS3Client) Exists(dest string) (bool, error) {
+func (b *awsS3Client) Exists(ctx context.Context, dest string) (bool, error) {
 	existsParams := &s3.HeadObjectInput{
-		Bucket: aws.String(b.s3cliConfig.BucketName),
-		Key:    b.key(dest),
+		Bucket:       aws.String(b.s3cliConfig.BucketName),
+		RequestPayer: b.requestPayer(),
+		Key:          b.key(dest),
 	}
 
-	_, err := b.s3Client.HeadObject(context.TODO(), existsParams)
+	_, err := b.s3Client.HeadObject(ctx, existsParams)
 
 	if err == nil {
 		slog.Info("Blob exists in bucket", "bucket", b.s3cliConfig.BucketName, "blob", dest)
@@ -214,39 +417,126 @@ func (b *awsS3Client) Exists(dest string) (bool, error) {
 		slog.Info("Blob does not exist in bucket", "bucket", b.s3cliConfig.BucketName, "blob", dest)
 		return false, nil
 	}
+
+	var respErr *smithyhttp.ResponseError
+	if errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusForbidden {
+		return false, fmt.Errorf("%w: %w", common.ErrAccessDenied, err)
+	}
 	return false, err
 }
 
+// Size returns the content length of a blob
+func (b *awsS3Client) Size(ctx context.Context, dest string) (int64, error) {
+	output, err := b.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:       aws.String(b.s3cliConfig.BucketName),
+		RequestPayer: b.requestPayer(),
+		Key:          b.key(dest),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch blob size: %w", err)
+	}
+	return aws.ToInt64(output.ContentLength), nil
+}
+
+// maxPresignExpiration is the longest a SigV4 presigned URL can be valid
+const maxPresignExpiration = 7 * 24 * time.Hour
+
 // Sign creates a presigned URL
-func (b *awsS3Client) Sign(objectID string, action string, expiration time.Duration) (string, error) {
+func (b *awsS3Client) Sign(ctx context.Context, objectID string, action string, expiration time.Duration) (string, error) {
+	return b.SignWithOptions(ctx, objectID, action, expiration, common.SignOptions{})
+}
+
+// SignWithOptions creates a presigned URL that is not valid before options.StartAt, a PUT URL
+// is also bound to the content type and MD5 in options
+func (b *awsS3Client) SignWithOptions(ctx context.Context, objectID string, action string, expiration time.Duration, options common.SignOptions) (string, error) {
+	if err := b.ValidateSign(action, expiration); err != nil {
+		return "", err
+	}
+
+	if strings.ToUpper(action) == "GET" {
+		return b.getSigned(ctx, objectID, expiration, options)
+	}
+	return b.putSigned(ctx, objectID, expiration, options)
+}
+
+// ValidateSign checks, without sending any request, that a URL for action can be presigned for expiration
+func (b *awsS3Client) ValidateSign(action string, expiration time.Duration) error {
+	if b.s3cliConfig.CredentialsSource == config.NoneCredentialsSource {
+		return errors.New("signing URLs requires credentials, it is not possible with the none credentials source")
+	}
+	if expiration > maxPresignExpiration {
+		return fmt.Errorf("expiration %s exceeds the maximum of %s for presigned URLs", expiration, maxPresignExpiration)
+	}
+
 	action = strings.ToUpper(action)
-	switch action {
-	case "GET":
-		return b.getSigned(objectID, expiration)
-	case "PUT":
-		return b.putSigned(objectID, expiration)
-	default:
-		return "", fmt.Errorf("action not implemented: %s", action)
+	if action != "GET" && action != "PUT" {
+		return fmt.Errorf("action not implemented: %s", action)
 	}
+	return nil
 }
 
-func (b *awsS3Client) key(srcOrDest string) *string {
-	formattedKey := aws.String(srcOrDest)
-	if len(b.s3cliConfig.FolderName) != 0 {
-		formattedKey = aws.String(fmt.Sprintf("%s/%s", b.s3cliConfig.FolderName, srcOrDest))
+// uploadRetryLimit returns how often a failed upload is retried
+func (b *awsS3Client) uploadRetryLimit() int {
+	if b.s3cliConfig.UploadMaxRetries > 0 {
+		return b.s3cliConfig.UploadMaxRetries
 	}
+	return maxRetries
+}
 
-	return formattedKey
+// uploadRetryDelay returns how long to wait before the given retry of a failed upload. The delay
+// doubles with every retry, and a random share of up to half of it is dropped so that clients
+// which failed at the same time don't retry in lockstep.
+func (b *awsS3Client) uploadRetryDelay(retry int) time.Duration {
+	delay := defaultUploadRetryBackoff
+	if b.s3cliConfig.UploadRetryBackoffMs > 0 {
+		delay = time.Duration(b.s3cliConfig.UploadRetryBackoffMs) * time.Millisecond
+	}
+	limit := max(delay, maxUploadRetryDelay)
+	for i := 1; i < retry && delay < limit; i++ {
+		delay *= 2
+	}
+	delay = min(delay, limit)
+	return delay - rand.N(delay/2+1)
 }
 
-func (b *awsS3Client) getSigned(objectID string, expiration time.Duration) (string, error) {
+// sleepOrDone waits for delay, or returns the error of ctx if it is done before
+func sleepOrDone(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (b *awsS3Client) key(srcOrDest string) *string {
+	return aws.String(b.s3cliConfig.ObjectKey(srcOrDest))
+}
+
+// requestPayer confirms that the requester pays for reads and copies with request_payer set,
+// which requester pays buckets refuse otherwise
+func (b *awsS3Client) requestPayer() types.RequestPayer {
+	if b.s3cliConfig.RequestPayer == config.RequesterRequestPayer {
+		return types.RequestPayerRequester
+	}
+	return ""
+}
+
+func (b *awsS3Client) getSigned(ctx context.Context, objectID string, expiration time.Duration, options common.SignOptions) (string, error) {
 	presignClient := s3.NewPresignClient(b.s3Client)
 	signParams := &s3.GetObjectInput{
 		Bucket: aws.String(b.s3cliConfig.BucketName),
 		Key:    b.key(objectID),
 	}
+	presignOptions := []func(*s3.PresignOptions){s3.WithPresignExpires(expiration)}
+	if !options.StartAt.IsZero() {
+		presignOptions = append(presignOptions, withSigningTime(options.StartAt))
+	}
 
-	req, err := presignClient.PresignGetObject(context.TODO(), signParams, s3.WithPresignExpires(expiration))
+	req, err := presignClient.PresignGetObject(ctx, signParams, presignOptions...)
 	if err != nil {
 		return "", err
 	}
@@ -254,14 +544,25 @@ func (b *awsS3Client) getSigned(objectID string, expiration time.Duration) (stri
 	return req.URL, nil
 }
 
-func (b *awsS3Client) putSigned(objectID string, expiration time.Duration) (string, error) {
+func (b *awsS3Client) putSigned(ctx context.Context, objectID string, expiration time.Duration, options common.SignOptions) (string, error) {
 	presignClient := s3.NewPresignClient(b.s3Client)
 	signParams := &s3.PutObjectInput{
 		Bucket: aws.String(b.s3cliConfig.BucketName),
 		Key:    b.key(objectID),
 	}
+	presignOptions := []func(*s3.PresignOptions){s3.WithPresignExpires(expiration)}
+	if options.ContentType != "" {
+		// The presigner strips Content-Type from PutObject, put it back so that it is signed
+		presignOptions = append(presignOptions, withSignedContentType(options.ContentType))
+	}
+	if options.ContentMD5 != "" {
+		signParams.ContentMD5 = aws.String(options.ContentMD5)
+	}
+	if !options.StartAt.IsZero() {
+		presignOptions = append(presignOptions, withSigningTime(options.StartAt))
+	}
 
-	req, err := presignClient.PresignPutObject(context.TODO(), signParams, s3.WithPresignExpires(expiration))
+	req, err := presignClient.PresignPutObject(ctx, signParams, presignOptions...)
 	if err != nil {
 		return "", err
 	}
@@ -269,9 +570,51 @@ func (b *awsS3Client) putSigned(objectID string, expiration time.Duration) (stri
 	return req.URL, nil
 }
 
-func (b *awsS3Client) EnsureStorageExists() error {
+// withSignedContentType sets the Content-Type header after the presigner removed it and before the request is signed
+func withSignedContentType(contentType string) func(*s3.PresignOptions) {
+	setContentType := middleware.BuildMiddlewareFunc("SetSignedContentType",
+		func(ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler) (middleware.BuildOutput, middleware.Metadata, error) {
+			if req, ok := in.Request.(*smithyhttp.Request); ok {
+				req.Header.Set("Content-Type", contentType)
+			}
+			return next.HandleBuild(ctx, in)
+		},
+	)
+
+	return func(o *s3.PresignOptions) {
+		o.ClientOptions = append(o.ClientOptions, func(o *s3.Options) {
+			o.APIOptions = append(o.APIOptions, func(stack *middleware.Stack) error {
+				return stack.Build.Add(setContentType, middleware.After)
+			})
+		})
+	}
+}
+
+// withSigningTime signs the URL as of signingTime. S3 refuses a presigned URL before its X-Amz-Date,
+// and X-Amz-Expires counts from it, so the URL is valid from signingTime on.
+func withSigningTime(signingTime time.Time) func(*s3.PresignOptions) {
+	return func(o *s3.PresignOptions) {
+		o.Presigner = signingTimePresigner{HTTPPresignerV4: o.Presigner, signingTime: signingTime}
+	}
+}
+
+// signingTimePresigner presigns with a fixed signing time instead of the current one
+type signingTimePresigner struct {
+	s3.HTTPPresignerV4
+	signingTime time.Time
+}
+
+func (p signingTimePresigner) PresignHTTP(
+	ctx context.Context, credentials aws.Credentials, r *http.Request,
+	payloadHash string, service string, region string, _ time.Time,
+	optFns ...func(*v4.SignerOptions),
+) (string, http.Header, error) {
+	return p.HTTPPresignerV4.PresignHTTP(ctx, credentials, r, payloadHash, service, region, p.signingTime, optFns...)
+}
+
+func (b *awsS3Client) EnsureStorageExists(ctx context.Context) error {
 	slog.Info("Ensuring bucket exists", "bucket", b.s3cliConfig.BucketName)
-	_, err := b.s3Client.HeadBucket(context.TODO(), &s3.HeadBucketInput{
+	_, err := b.s3Client.HeadBucket(ctx, &s3.HeadBucketInput{
 		Bucket: aws.String(b.s3cliConfig.BucketName),
 	})
 
@@ -285,6 +628,10 @@ func (b *awsS3Client) EnsureStorageExists() error {
 		return fmt.Errorf("failed to check if bucket exists: %w", err)
 	}
 
+	if b.s3cliConfig.CredentialsSource == config.NoneCredentialsSource {
+		return errorInvalidCredentialsSourceValue
+	}
+
 	slog.Info("Bucket does not exist, creating it", "bucket", b.s3cliConfig.BucketName)
 	createBucketInput := &s3.CreateBucketInput{
 		Bucket: aws.String(b.s3cliConfig.BucketName),
@@ -297,7 +644,7 @@ func (b *awsS3Client) EnsureStorageExists() error {
 		}
 	}
 
-	_, err = b.s3Client.CreateBucket(context.TODO(), createBucketInput)
+	_, err = b.s3Client.CreateBucket(ctx, createBucketInput)
 	if err != nil {
 		var alreadyOwned *types.BucketAlreadyOwnedByYou
 		var alreadyExists *types.BucketAlreadyExists
@@ -312,8 +659,25 @@ func (b *awsS3Client) EnsureStorageExists() error {
 	return nil
 }
 
-func (b *awsS3Client) Copy(srcBlob string, dstBlob string) error {
+// Copy copies a blob within the configured bucket. The copy keeps the source's metadata
+// and content headers unless options.ResetMetadata is set, in which case it starts without any.
+func (b *awsS3Client) Copy(ctx context.Context, srcBlob string, dstBlob string, options common.CopyOptions) error {
+	return b.copyObject(ctx, b.s3cliConfig.BucketName, "", *b.key(srcBlob), dstBlob, options)
+}
+
+// CopyFromBucket copies a blob from another bucket, which may live in a different region.
+// The copy is always issued against the configured (destination) bucket, srcRegion is only
+// needed to look up the source object itself. srcBlob is used as-is, folder_name only
+// applies to the configured bucket. Metadata is handled as for Copy.
+func (b *awsS3Client) CopyFromBucket(ctx context.Context, srcBucket string, srcRegion string, srcBlob string, dstBlob string, options common.CopyOptions) error {
+	return b.copyObject(ctx, srcBucket, srcRegion, srcBlob, dstBlob, options)
+}
+
+func (b *awsS3Client) copyObject(ctx context.Context, srcBucket string, srcRegion string, srcKey string, dstBlob string, options common.CopyOptions) error {
 	cfg := b.s3cliConfig
+	if cfg.CredentialsSource == config.NoneCredentialsSource {
+		return errorInvalidCredentialsSourceValue
+	}
 
 	copyThreshold := defaultMultipartCopyThreshold
 	if cfg.MultipartCopyThreshold > 0 {
@@ -324,9 +688,14 @@ func (b *awsS3Client) Copy(srcBlob string, dstBlob string) error {
 		copyPartSize = cfg.MultipartCopyPartSize
 	}
 
-	headOutput, err := b.s3Client.HeadObject(context.TODO(), &s3.HeadObjectInput{
-		Bucket: aws.String(cfg.BucketName),
-		Key:    b.key(srcBlob),
+	headOutput, err := b.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:       aws.String(srcBucket),
+		RequestPayer: b.requestPayer(),
+		Key:          aws.String(srcKey),
+	}, func(o *s3.Options) {
+		if srcRegion != "" {
+			o.Region = srcRegion
+		}
 	})
 	if err != nil {
 		return fmt.Errorf("failed to get object metadata: %w", err)
@@ -336,24 +705,34 @@ func (b *awsS3Client) Copy(srcBlob string, dstBlob string) error {
 	}
 
 	objectSize := *headOutput.ContentLength
-	copySource := fmt.Sprintf("%s/%s", cfg.BucketName, *b.key(srcBlob))
+	copySource := encodeCopySource(srcBucket, srcKey)
 
 	// Use simple copy if file is below threshold or is empty
 	if objectSize < copyThreshold {
-		slog.Info("Copying object", "source", srcBlob, "destination", dstBlob, "size", objectSize)
-		return b.simpleCopy(copySource, dstBlob)
+		slog.Info("Copying object", "source", copySource, "destination", dstBlob, "size", objectSize)
+		return b.simpleCopy(ctx, copySource, dstBlob, options)
+	}
+	if cfg.NoMultipartCopy || options.NoMultipart {
+		slog.Info("Copying large object with a single copy, multipart copy is disabled", "source", copySource, "destination", dstBlob, "size", objectSize)
+		return b.simpleCopy(ctx, copySource, dstBlob, options)
+	}
+
+	// Unlike CopyObject, a multipart upload doesn't take over the source's metadata on its own
+	var srcMetadata *s3.HeadObjectOutput
+	if !options.ResetMetadata {
+		srcMetadata = headOutput
 	}
 
 	// For large files, try multipart copy first (works for AWS, MinIO, Ceph, AliCloud)
 	// Fall back to simple copy if provider doesn't support UploadPartCopy (e.g., GCS)
-	slog.Info("Copying large object using multipart copy", "source", srcBlob, "destination", dstBlob, "size", objectSize)
+	slog.Info("Copying large object using multipart copy", "source", copySource, "destination", dstBlob, "size", objectSize)
 
-	err = b.multipartCopy(copySource, dstBlob, objectSize, copyPartSize)
+	err = b.multipartCopy(ctx, copySource, dstBlob, objectSize, copyPartSize, srcMetadata)
 	if err != nil {
 		var apiErr smithy.APIError
 		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NotImplemented" {
-			slog.Info("Multipart copy not supported by provider, falling back to simple copy", "source", srcBlob, "destination", dstBlob)
-			return b.simpleCopy(copySource, dstBlob)
+			slog.Info("Multipart copy not supported by provider, falling back to simple copy", "source", copySource, "destination", dstBlob)
+			return b.simpleCopy(ctx, copySource, dstBlob, options)
 		}
 		return err
 	}
@@ -361,14 +740,57 @@ func (b *awsS3Client) Copy(srcBlob string, dstBlob string) error {
 	return nil
 }
 
+// Rename moves a blob to a new key in the same bucket. Directory buckets support this natively,
+// for all other buckets the blob is copied server-side and the source is deleted afterwards.
+func (b *awsS3Client) Rename(ctx context.Context, srcBlob string, dstBlob string) error {
+	cfg := b.s3cliConfig
+	if cfg.CredentialsSource == config.NoneCredentialsSource {
+		return errorInvalidCredentialsSourceValue
+	}
+
+	if !strings.HasSuffix(cfg.BucketName, directoryBucketSuffix) {
+		if err := b.Copy(ctx, srcBlob, dstBlob, common.CopyOptions{}); err != nil {
+			return err
+		}
+		return b.Delete(ctx, srcBlob)
+	}
+
+	slog.Info("Renaming object", "source", srcBlob, "destination", dstBlob)
+	_, err := b.s3Client.RenameObject(ctx, &s3.RenameObjectInput{
+		Bucket:       aws.String(cfg.BucketName),
+		Key:          b.key(dstBlob),
+		RenameSource: aws.String(encodeCopySource(cfg.BucketName, *b.key(srcBlob))),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to rename object: %w", err)
+	}
+	return nil
+}
+
+// encodeCopySource returns the bucket/key value of an x-amz-copy-source or x-amz-rename-source
+// header. S3 URL-decodes it, so every segment of the key is escaped, '+' included, which S3
+// would otherwise read as a space.
+func encodeCopySource(bucket string, key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(url.PathEscape(segment), "+", "%2B")
+	}
+	return bucket + "/" + strings.Join(segments, "/")
+}
+
 // simpleCopy performs a single CopyObject request
-func (b *awsS3Client) simpleCopy(copySource string, dstBlob string) error {
+func (b *awsS3Client) simpleCopy(ctx context.Context, copySource string, dstBlob string, options common.CopyOptions) error {
 	cfg := b.s3cliConfig
 
 	copyInput := &s3.CopyObjectInput{
-		Bucket:     aws.String(cfg.BucketName),
-		CopySource: aws.String(copySource),
-		Key:        b.key(dstBlob),
+		Bucket:       aws.String(cfg.BucketName),
+		RequestPayer: b.requestPayer(),
+		CopySource:   aws.String(copySource),
+		Key:          b.key(dstBlob),
+	}
+	if options.ResetMetadata {
+		// REPLACE without any metadata in the request leaves the copy with none
+		copyInput.MetadataDirective = types.MetadataDirectiveReplace
 	}
 	if cfg.ServerSideEncryption != "" {
 		copyInput.ServerSideEncryption = types.ServerSideEncryption(cfg.ServerSideEncryption)
@@ -377,23 +799,25 @@ func (b *awsS3Client) simpleCopy(copySource string, dstBlob string) error {
 		copyInput.SSEKMSKeyId = aws.String(cfg.SSEKMSKeyID)
 	}
 
-	_, err := b.s3Client.CopyObject(context.TODO(), copyInput)
+	_, err := b.s3Client.CopyObject(ctx, copyInput)
 	if err != nil {
 		return fmt.Errorf("failed to copy object: %w", err)
 	}
 	return nil
 }
 
-// multipartCopy performs a multipart copy using CreateMultipartUpload, UploadPartCopy, and CompleteMultipartUpload
-func (b *awsS3Client) multipartCopy(copySource string, dstBlob string, objectSize int64, copyPartSize int64) error {
+// multipartCopy performs a multipart copy using CreateMultipartUpload, UploadPartCopy, and CompleteMultipartUpload.
+// The metadata and content headers of srcMetadata, if given, are set on the copy.
+func (b *awsS3Client) multipartCopy(ctx context.Context, copySource string, dstBlob string, objectSize int64, copyPartSize int64, srcMetadata *s3.HeadObjectOutput) error {
 	cfg := b.s3cliConfig
 	// Calculate number of parts using ceiling division (avoids floating-point arithmetic).
 	// Example: objectSize=550MB, partSize=100MB => (550 + 100 - 1) / 100 = 6 parts
 	numParts := int((objectSize + copyPartSize - 1) / copyPartSize)
 
 	createInput := &s3.CreateMultipartUploadInput{
-		Bucket: aws.String(cfg.BucketName),
-		Key:    b.key(dstBlob),
+		Bucket:       aws.String(cfg.BucketName),
+		RequestPayer: b.requestPayer(),
+		Key:          b.key(dstBlob),
 	}
 	if cfg.ServerSideEncryption != "" {
 		createInput.ServerSideEncryption = types.ServerSideEncryption(cfg.ServerSideEncryption)
@@ -401,8 +825,16 @@ func (b *awsS3Client) multipartCopy(copySource string, dstBlob string, objectSiz
 	if cfg.SSEKMSKeyID != "" {
 		createInput.SSEKMSKeyId = aws.String(cfg.SSEKMSKeyID)
 	}
+	if srcMetadata != nil {
+		createInput.Metadata = srcMetadata.Metadata
+		createInput.ContentType = srcMetadata.ContentType
+		createInput.ContentEncoding = srcMetadata.ContentEncoding
+		createInput.ContentDisposition = srcMetadata.ContentDisposition
+		createInput.ContentLanguage = srcMetadata.ContentLanguage
+		createInput.CacheControl = srcMetadata.CacheControl
+	}
 
-	createOutput, err := b.s3Client.CreateMultipartUpload(context.TODO(), createInput)
+	createOutput, err := b.s3Client.CreateMultipartUpload(ctx, createInput)
 	if err != nil {
 		return fmt.Errorf("failed to create multipart upload: %w", err)
 	}
@@ -412,10 +844,12 @@ func (b *awsS3Client) multipartCopy(copySource string, dstBlob string, objectSiz
 	var completed bool
 	defer func() {
 		if !completed {
-			_, err := b.s3Client.AbortMultipartUpload(context.TODO(), &s3.AbortMultipartUploadInput{
-				Bucket:   aws.String(cfg.BucketName),
-				Key:      b.key(dstBlob),
-				UploadId: aws.String(uploadID),
+			// The upload is aborted even when the operation was cancelled
+			_, err := b.s3Client.AbortMultipartUpload(context.WithoutCancel(ctx), &s3.AbortMultipartUploadInput{
+				Bucket:       aws.String(cfg.BucketName),
+				RequestPayer: b.requestPayer(),
+				Key:          b.key(dstBlob),
+				UploadId:     aws.String(uploadID),
 			})
 			if err != nil {
 				slog.Warn("Failed to abort multipart upload", "uploadId", uploadID, "error", err)
@@ -433,8 +867,9 @@ func (b *awsS3Client) multipartCopy(copySource string, dstBlob string, objectSiz
 		}
 		byteRange := fmt.Sprintf("bytes=%d-%d", start, end)
 
-		output, err := b.s3Client.UploadPartCopy(context.TODO(), &s3.UploadPartCopyInput{
+		output, err := b.s3Client.UploadPartCopy(ctx, &s3.UploadPartCopyInput{
 			Bucket:          aws.String(cfg.BucketName),
+			RequestPayer:    b.requestPayer(),
 			CopySource:      aws.String(copySource),
 			CopySourceRange: aws.String(byteRange),
 			Key:             b.key(dstBlob),
@@ -452,10 +887,11 @@ func (b *awsS3Client) multipartCopy(copySource string, dstBlob string, objectSiz
 		slog.Debug("Copied part", "part", partNumber, "range", byteRange)
 	}
 
-	_, err = b.s3Client.CompleteMultipartUpload(context.TODO(), &s3.CompleteMultipartUploadInput{
-		Bucket:   aws.String(cfg.BucketName),
-		Key:      b.key(dstBlob),
-		UploadId: aws.String(uploadID),
+	_, err = b.s3Client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:       aws.String(cfg.BucketName),
+		RequestPayer: b.requestPayer(),
+		Key:          b.key(dstBlob),
+		UploadId:     aws.String(uploadID),
 		MultipartUpload: &types.CompletedMultipartUpload{
 			Parts: completedParts,
 		},
@@ -469,32 +905,27 @@ func (b *awsS3Client) multipartCopy(copySource string, dstBlob string, objectSiz
 	return nil
 }
 
-type BlobProperties struct {
-	ETag          string    `json:"etag,omitempty"`
-	LastModified  time.Time `json:"last_modified,omitempty"`
-	ContentLength int64     `json:"content_length,omitempty"`
-}
-
-func (b *awsS3Client) Properties(dest string) error {
+// Properties returns the properties of dest, or common.ErrObjectNotFound if it doesn't exist
+func (b *awsS3Client) Properties(ctx context.Context, dest string, options common.PropertiesOptions) (common.ObjectProperties, error) {
 	slog.Info("Fetching blob properties", "bucket", b.s3cliConfig.BucketName, "blob", dest)
 
-	headObjectOutput, err := b.s3Client.HeadObject(context.TODO(), &s3.HeadObjectInput{
-		Bucket: aws.String(b.s3cliConfig.BucketName),
-		Key:    b.key(dest),
+	headObjectOutput, err := b.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:       aws.String(b.s3cliConfig.BucketName),
+		RequestPayer: b.requestPayer(),
+		Key:          b.key(dest),
 	})
 
 	if err != nil {
 		var apiErr smithy.APIError
 		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NotFound" {
-			fmt.Println(`{}`)
-			return nil
+			return common.ObjectProperties{}, common.ErrObjectNotFound
 		}
-		return fmt.Errorf("failed to fetch blob properties: %w", err)
+		return common.ObjectProperties{}, fmt.Errorf("failed to fetch blob properties: %w", err)
 	}
 
-	properties := BlobProperties{}
+	properties := common.ObjectProperties{}
 	if headObjectOutput.ETag != nil {
-		properties.ETag = strings.Trim(*headObjectOutput.ETag, `"`)
+		properties.ETag = options.ETag(*headObjectOutput.ETag)
 	}
 	if headObjectOutput.LastModified != nil {
 		properties.LastModified = *headObjectOutput.LastModified
@@ -502,25 +933,98 @@ func (b *awsS3Client) Properties(dest string) error {
 	if headObjectOutput.ContentLength != nil {
 		properties.ContentLength = *headObjectOutput.ContentLength
 	}
+	properties.Metadata = headObjectOutput.Metadata
 
-	output, err := json.MarshalIndent(properties, "", "  ")
+	return properties, nil
+}
+
+// Head returns the full HeadObject response for dest, or common.ErrObjectNotFound if it doesn't exist
+func (b *awsS3Client) Head(ctx context.Context, dest string) (common.ObjectHead, error) {
+	slog.Info("Fetching blob head", "bucket", b.s3cliConfig.BucketName, "blob", dest)
+
+	output, err := b.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:       aws.String(b.s3cliConfig.BucketName),
+		RequestPayer: b.requestPayer(),
+		Key:          b.key(dest),
+		ChecksumMode: types.ChecksumModeEnabled,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to marshal blob properties: %w", err)
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NotFound" {
+			return common.ObjectHead{}, common.ErrObjectNotFound
+		}
+		return common.ObjectHead{}, fmt.Errorf("failed to fetch blob head: %w", err)
 	}
 
-	fmt.Println(string(output))
+	head := common.ObjectHead{
+		ETag:               common.PropertiesOptions{}.ETag(aws.ToString(output.ETag)),
+		LastModified:       aws.ToTime(output.LastModified),
+		ContentLength:      aws.ToInt64(output.ContentLength),
+		ContentType:        aws.ToString(output.ContentType),
+		ContentEncoding:    aws.ToString(output.ContentEncoding),
+		ContentDisposition: aws.ToString(output.ContentDisposition),
+		ContentLanguage:    aws.ToString(output.ContentLanguage),
+		CacheControl:       aws.ToString(output.CacheControl),
+		StorageClass:       string(output.StorageClass),
+		VersionID:          aws.ToString(output.VersionId),
+		Metadata:           output.Metadata,
+	}
+	if output.ServerSideEncryption != "" {
+		head.Encryption = &common.ObjectEncryption{
+			Algorithm: string(output.ServerSideEncryption),
+			KeyID:     aws.ToString(output.SSEKMSKeyId),
+		}
+	}
+	head.Checksums = objectChecksums(output, head.ETag)
 
-	return nil
+	return head, nil
+}
+
+// objectChecksums collects the checksums of the whole object from a HeadObject response.
+// The ETag is the MD5 of the object unless it was uploaded in parts or is encrypted with KMS.
+func objectChecksums(output *s3.HeadObjectOutput, etag string) map[string]string {
+	checksums := map[string]string{}
+	if md5, err := hex.DecodeString(etag); err == nil && len(md5) == 16 && !strings.HasPrefix(string(output.ServerSideEncryption), "aws:kms") {
+		checksums[common.ChecksumMD5] = base64.StdEncoding.EncodeToString(md5)
+	}
+
+	// Checksums of multipart uploads are composed of those of the parts, unless the upload asked for a full object checksum
+	if output.ChecksumType != types.ChecksumTypeComposite {
+		for algorithm, checksum := range map[string]*string{
+			common.ChecksumCRC32:     output.ChecksumCRC32,
+			common.ChecksumCRC32C:    output.ChecksumCRC32C,
+			common.ChecksumCRC64NVME: output.ChecksumCRC64NVME,
+			common.ChecksumSHA1:      output.ChecksumSHA1,
+			common.ChecksumSHA256:    output.ChecksumSHA256,
+		} {
+			// Composite checksums carry the number of parts, e.g. "AAAAAA==-3"
+			if value := aws.ToString(checksum); value != "" && !strings.Contains(value, "-") {
+				checksums[algorithm] = value
+			}
+		}
+	}
+
+	if len(checksums) == 0 {
+		return nil
+	}
+	return checksums
 }
 
-func (b *awsS3Client) List(prefix string) ([]string, error) {
+// List lists the objects starting with prefix, at most limit of them unless limit is zero or negative.
+// Like the other operations it is confined to folder_name, keys are relative to it.
+func (b *awsS3Client) List(ctx context.Context, prefix string, limit int) ([]string, error) {
 	input := &s3.ListObjectsV2Input{
-		Bucket: aws.String(b.s3cliConfig.BucketName),
+		Bucket:       aws.String(b.s3cliConfig.BucketName),
+		RequestPayer: b.requestPayer(),
+		Prefix:       b.key(prefix),
+	}
+	if limit > 0 {
+		// Don't fetch more than needed, a page holds up to 1000 keys
+		input.MaxKeys = aws.Int32(int32(min(limit, 1000)))
 	}
 
 	if prefix != "" {
 		slog.Info("Listing all objects in bucket with prefix", "bucket", b.s3cliConfig.BucketName, "prefix", prefix)
-		input.Prefix = b.key(prefix)
 	} else {
 		slog.Info("Listing all objects in bucket", "bucket", b.s3cliConfig.BucketName)
 	}
@@ -528,7 +1032,7 @@ func (b *awsS3Client) List(prefix string) ([]string, error) {
 	var names []string
 	objectPaginator := s3.NewListObjectsV2Paginator(b.s3Client, input)
 	for objectPaginator.HasMorePages() {
-		page, err := objectPaginator.NextPage(context.TODO())
+		page, err := objectPaginator.NextPage(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list objects: %w", err)
 		}
@@ -538,46 +1042,210 @@ func (b *awsS3Client) List(prefix string) ([]string, error) {
 		}
 
 		for _, obj := range page.Contents {
-			names = append(names, *obj.Key)
+			names = append(names, b.s3cliConfig.RelativeKey(aws.ToString(obj.Key)))
+			if len(names) == limit {
+				return names, nil
+			}
 		}
 	}
 
 	return names, nil
 }
 
-func (b *awsS3Client) DeleteRecursive(prefix string) error {
+// ListDetailed lists like List, along with the size and last modification time of each object
+func (b *awsS3Client) ListDetailed(ctx context.Context, prefix string, limit int) ([]common.ObjectInfo, error) {
 	input := &s3.ListObjectsV2Input{
-		Bucket: aws.String(b.s3cliConfig.BucketName),
+		Bucket:       aws.String(b.s3cliConfig.BucketName),
+		RequestPayer: b.requestPayer(),
+		Prefix:       b.key(prefix),
+	}
+	if limit > 0 {
+		// Don't fetch more than needed, a page holds up to 1000 keys
+		input.MaxKeys = aws.Int32(int32(min(limit, 1000)))
+	}
+
+	slog.Info("Listing objects with details in bucket", "bucket", b.s3cliConfig.BucketName, "prefix", prefix)
+
+	var objects []common.ObjectInfo
+	objectPaginator := s3.NewListObjectsV2Paginator(b.s3Client, input)
+	for objectPaginator.HasMorePages() {
+		page, err := objectPaginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+
+		for _, obj := range page.Contents {
+			objects = append(objects, common.ObjectInfo{
+				Key:          b.s3cliConfig.RelativeKey(aws.ToString(obj.Key)),
+				Size:         aws.ToInt64(obj.Size),
+				LastModified: aws.ToTime(obj.LastModified),
+			})
+			if len(objects) == limit {
+				return objects, nil
+			}
+		}
+	}
+
+	return objects, nil
+}
+
+// maxDeleteObjectsKeys is the S3 limit for the number of keys a single DeleteObjects request deletes
+const maxDeleteObjectsKeys = 1000
+
+// DeleteRecursive deletes the objects under prefix with DeleteObjects, 1000 keys at a time, sending
+// options.Concurrency batches at once. GCS has no DeleteObjects, there the objects are deleted one by
+// one. Without options.ContinueOnError no further batches are started after the first failure.
+func (b *awsS3Client) DeleteRecursive(ctx context.Context, prefix string, options common.DeleteRecursiveOptions) error {
+	if b.s3cliConfig.CredentialsSource == config.NoneCredentialsSource {
+		return errorInvalidCredentialsSourceValue
+	}
+
+	input := &s3.ListObjectsV2Input{
+		Bucket:       aws.String(b.s3cliConfig.BucketName),
+		RequestPayer: b.requestPayer(),
+		Prefix:       b.key(prefix),
 	}
 
 	if prefix != "" {
 		slog.Info("Deleting all objects in bucket with given prefix", "bucket", b.s3cliConfig.BucketName, "prefix", prefix)
-		input.Prefix = b.key(prefix)
 	} else {
 		slog.Info("Deleting all objects in bucket", "bucket", b.s3cliConfig.BucketName)
 	}
 
+	batchSize := maxDeleteObjectsKeys
+	if b.s3cliConfig.IsGoogle() {
+		batchSize = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu   sync.Mutex
+		errs []error
+		wg   sync.WaitGroup
+	)
+	fail := func(batchErrs []error) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, err := range batchErrs {
+			// Batches cut short by an earlier failure have nothing to add to it
+			if ctx.Err() != nil && errors.Is(err, context.Canceled) {
+				continue
+			}
+			errs = append(errs, err)
+		}
+		if len(batchErrs) > 0 && !options.ContinueOnError {
+			cancel()
+		}
+	}
+
+	// Acquiring before starting a batch keeps the listing from running ahead of the deletions
+	limiter := common.NewConcurrencyLimiter(options.ConcurrencyOr(1))
 	objectPaginator := s3.NewListObjectsV2Paginator(b.s3Client, input)
-	for objectPaginator.HasMorePages() {
-		page, err := objectPaginator.NextPage(context.TODO())
+	for objectPaginator.HasMorePages() && ctx.Err() == nil {
+		page, err := objectPaginator.NextPage(ctx)
 		if err != nil {
-			return fmt.Errorf("failed to list objects for deletion: %w", err)
+			fail([]error{fmt.Errorf("failed to list objects for deletion: %w", err)})
+			break
 		}
 
+		keys := make([]string, 0, len(page.Contents))
 		for _, obj := range page.Contents {
-			slog.Debug("Deleting object", "key", *obj.Key)
-			_, err := b.s3Client.DeleteObject(context.TODO(), &s3.DeleteObjectInput{
-				Bucket: aws.String(b.s3cliConfig.BucketName),
-				Key:    obj.Key,
-			})
-			if err != nil {
-				var apiErr smithy.APIError
-				if errors.As(err, &apiErr) && (apiErr.ErrorCode() == "NotFound" || apiErr.ErrorCode() == "NoSuchKey") {
-					continue // Object already deleted, which is fine
-				}
-				return fmt.Errorf("failed to delete object '%s': %w", *obj.Key, err)
-			}
+			keys = append(keys, *obj.Key)
+		}
+		for len(keys) > 0 && ctx.Err() == nil {
+			batch := keys[:min(len(keys), batchSize)]
+			keys = keys[len(batch):]
+
+			limiter.Acquire()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer limiter.Release()
+				fail(b.deleteBatch(ctx, batch))
+			}()
 		}
 	}
-	return nil
+
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// deleteBatch deletes the given keys and returns an error for every key that could not be deleted.
+// Objects that are already gone count as deleted.
+func (b *awsS3Client) deleteBatch(ctx context.Context, keys []string) []error {
+	if len(keys) == 1 && b.s3cliConfig.IsGoogle() {
+		slog.Debug("Deleting object", "key", keys[0])
+		_, err := b.s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(b.s3cliConfig.BucketName),
+			Key:    aws.String(keys[0]),
+		})
+		var apiErr smithy.APIError
+		if err == nil || (errors.As(err, &apiErr) && isNotFoundCode(apiErr.ErrorCode())) {
+			return nil
+		}
+		return []error{fmt.Errorf("failed to delete object '%s': %w", keys[0], err)}
+	}
+
+	slog.Debug("Deleting batch of objects", "count", len(keys))
+	objects := make([]types.ObjectIdentifier, 0, len(keys))
+	for _, key := range keys {
+		objects = append(objects, types.ObjectIdentifier{Key: aws.String(key)})
+	}
+	output, err := b.s3Client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+		Bucket: aws.String(b.s3cliConfig.BucketName),
+		Delete: &types.Delete{Objects: objects, Quiet: aws.Bool(true)},
+	})
+	if err != nil {
+		return []error{fmt.Errorf("failed to delete batch of %d objects: %w", len(keys), err)}
+	}
+
+	var errs []error
+	for _, deleteErr := range output.Errors {
+		if isNotFoundCode(aws.ToString(deleteErr.Code)) {
+			continue
+		}
+		errs = append(errs, fmt.Errorf("failed to delete object '%s': %s: %s",
+			aws.ToString(deleteErr.Key), aws.ToString(deleteErr.Code), aws.ToString(deleteErr.Message)))
+	}
+	return errs
+}
+
+// isNotFoundCode reports whether an S3 error code means the object doesn't exist (anymore)
+func isNotFoundCode(code string) bool {
+	return code == "NotFound" || code == "NoSuchKey"
+}
+
+// Identity reports the credentials source and the principal the credentials belong to.
+// On AWS the principal is resolved through STS, other S3 compatible providers have no
+// STS so the access key ID is reported instead.
+func (b *awsS3Client) Identity(ctx context.Context) (common.Identity, error) {
+	cfg := b.s3cliConfig
+	identity := common.Identity{CredentialsSource: cfg.CredentialsSource}
+	if cfg.CredentialsSource == config.NoneCredentialsSource {
+		return identity, nil
+	}
+
+	options := b.s3Client.Options()
+	if cfg.Host == "" || config.Provider(cfg.Host) == "aws" {
+		stsClient := sts.New(sts.Options{
+			Region:      options.Region,
+			Credentials: options.Credentials,
+			HTTPClient:  options.HTTPClient,
+		})
+		callerIdentity, err := stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+		if err != nil {
+			return identity, fmt.Errorf("failed to get caller identity: %w", err)
+		}
+		identity.Principal = aws.ToString(callerIdentity.Arn)
+		return identity, nil
+	}
+
+	credentials, err := options.Credentials.Retrieve(ctx)
+	if err != nil {
+		return identity, fmt.Errorf("failed to retrieve credentials: %w", err)
+	}
+	identity.Principal = credentials.AccessKeyID
+	return identity, nil
 }
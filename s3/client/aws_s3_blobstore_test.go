@@ -0,0 +1,1174 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/smithy-go/middleware"
+
+	"github.com/cloudfoundry/storage-cli/common"
+	"github.com/cloudfoundry/storage-cli/s3/client"
+	"github.com/cloudfoundry/storage-cli/s3/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeS3Object serves a single object from an in-memory S3 endpoint and records the requests it receives.
+// A plain PUT replaces its content.
+type fakeS3Object struct {
+	content []byte
+	etag    string
+
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func (f *fakeS3Object) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.requests = append(f.requests, r.Clone(r.Context()))
+	f.mu.Unlock()
+
+	query := r.URL.Query()
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		w.Write([]byte(`<InitiateMultipartUploadResult><UploadId>some-upload-id</UploadId></InitiateMultipartUploadResult>`)) //nolint:errcheck
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		w.Write([]byte(`<CompleteMultipartUploadResult></CompleteMultipartUploadResult>`)) //nolint:errcheck
+	case r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "" && query.Has("partNumber"):
+		fmt.Fprintf(w, `<CopyPartResult><ETag>"etag-%s"</ETag></CopyPartResult>`, query.Get("partNumber")) //nolint:errcheck
+	case r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "":
+		w.Write([]byte(`<CopyObjectResult></CopyObjectResult>`)) //nolint:errcheck
+	case r.Method == http.MethodPut && !query.Has("partNumber"):
+		content, _ := io.ReadAll(r.Body) //nolint:errcheck
+		f.mu.Lock()
+		f.content = content
+		f.mu.Unlock()
+	case r.Method == http.MethodDelete:
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		if f.etag != "" {
+			w.Header().Set("ETag", `"`+f.etag+`"`)
+		}
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(f.content))
+	}
+}
+
+func (f *fakeS3Object) Requests(method string) []*http.Request {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var requests []*http.Request
+	for _, r := range f.requests {
+		if r.Method == method {
+			requests = append(requests, r)
+		}
+	}
+	return requests
+}
+
+// newFakeS3Config returns a path style configuration pointing to the given test server
+func newFakeS3Config(server *httptest.Server) *config.S3Cli {
+	serverURL, err := url.Parse(server.URL)
+	Expect(err).ToNot(HaveOccurred())
+	port, err := strconv.Atoi(serverURL.Port())
+	Expect(err).ToNot(HaveOccurred())
+
+	return &config.S3Cli{
+		AccessKeyID:       "id",
+		SecretAccessKey:   "key",
+		CredentialsSource: config.StaticCredentialsSource,
+		BucketName:        "some-bucket",
+		Host:              serverURL.Hostname(),
+		Port:              port,
+		Region:            "us-east-1",
+		SSLVerifyPeer:     true,
+	}
+}
+
+var _ = Describe("awsS3Client", func() {
+	Describe("Get()", func() {
+		It("hashes the object while downloading its parts concurrently", func() {
+			object := &fakeS3Object{content: []byte("the quick brown fox jumps over the lazy dog")}
+			server := httptest.NewServer(object)
+			DeferCleanup(server.Close)
+
+			s3Config := newFakeS3Config(server)
+			s3Config.DownloadPartSize = 5
+			s3Config.DownloadConcurrency = 4
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			checksum := common.NewDownloadChecksum(md5.New())
+			dest := filepath.Join(GinkgoT().TempDir(), "object")
+			Expect(client.New(s3Client, s3Config).Get(context.Background(), "some-object", dest, common.GetOptions{Checksum: checksum})).To(Succeed())
+			Expect(object.Requests(http.MethodGet)).To(HaveLen(9))
+
+			expected := md5.Sum(object.content)
+			Expect(checksum.Sum(int64(len(object.content)))).To(Equal(expected[:]))
+		})
+	})
+
+	Describe("Put()", func() {
+		It("uploads an empty file as a single zero-byte object", func() {
+			object := &fakeS3Object{content: []byte("previous content")}
+			server := httptest.NewServer(object)
+			DeferCleanup(server.Close)
+
+			s3Config := newFakeS3Config(server)
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+			blobstoreClient := client.New(s3Client, s3Config)
+
+			source := filepath.Join(GinkgoT().TempDir(), "empty")
+			Expect(os.WriteFile(source, nil, 0644)).To(Succeed())
+
+			Expect(blobstoreClient.Put(context.Background(), source, "empty-object", common.PutOptions{})).To(Succeed())
+
+			puts := object.Requests(http.MethodPut)
+			Expect(puts).To(HaveLen(1))
+			Expect(puts[0].URL.Path).To(Equal("/some-bucket/empty-object"))
+			Expect(puts[0].ContentLength).To(BeZero())
+			Expect(object.Requests(http.MethodPost)).To(BeEmpty())
+
+			properties, err := blobstoreClient.Properties(context.Background(), "empty-object")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(properties.ContentLength).To(BeZero())
+		})
+	})
+
+	Describe("GetRange()", func() {
+		var (
+			object   *fakeS3Object
+			server   *httptest.Server
+			s3Config *config.S3Cli
+			dest     string
+		)
+
+		BeforeEach(func() {
+			object = &fakeS3Object{content: []byte("the quick brown fox jumps over the lazy dog")}
+			server = httptest.NewServer(object)
+			DeferCleanup(server.Close)
+
+			s3Config = newFakeS3Config(server)
+			dest = filepath.Join(GinkgoT().TempDir(), "object.part")
+		})
+
+		It("fetches only the bytes missing from a partial file", func() {
+			err := os.WriteFile(dest, object.content[:10], 0644)
+			Expect(err).ToNot(HaveOccurred())
+
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = client.New(s3Client, s3Config).GetRange(context.Background(), "some-object", dest, 10, common.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			gets := object.Requests(http.MethodGet)
+			Expect(gets).To(HaveLen(1))
+			Expect(gets[0].URL.Path).To(Equal("/some-bucket/some-object"))
+			Expect(gets[0].Header.Get("Range")).To(Equal("bytes=10-"))
+
+			downloaded, err := os.ReadFile(dest)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(downloaded).To(HaveLen(len(object.content)))
+			Expect(md5.Sum(downloaded)).To(Equal(md5.Sum(object.content)))
+		})
+
+		It("only continues while the object still has the ETag the download started with", func() {
+			object.etag = "some-etag"
+			err := os.WriteFile(dest, object.content[:10], 0644)
+			Expect(err).ToNot(HaveOccurred())
+
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = client.New(s3Client, s3Config).GetRange(context.Background(), "some-object", dest, 10, common.GetOptions{IfMatch: "some-etag"})
+			Expect(err).ToNot(HaveOccurred())
+
+			gets := object.Requests(http.MethodGet)
+			Expect(gets).To(HaveLen(1))
+			Expect(gets[0].Header.Get("If-Match")).To(Equal(`"some-etag"`))
+		})
+
+		It("fails with a precondition error once the object has another ETag", func() {
+			object.etag = "new-etag"
+
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = client.New(s3Client, s3Config).GetRange(context.Background(), "some-object", dest, 10, common.GetOptions{IfMatch: "old-etag"})
+			Expect(err).To(MatchError(common.ErrPreconditionFailed))
+			Expect(object.Requests(http.MethodGet)).To(BeEmpty())
+		})
+
+		It("does not fetch anything when the partial file is already complete", func() {
+			err := os.WriteFile(dest, object.content, 0644)
+			Expect(err).ToNot(HaveOccurred())
+
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = client.New(s3Client, s3Config).GetRange(context.Background(), "some-object", dest, int64(len(object.content)), common.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(object.Requests(http.MethodGet)).To(BeEmpty())
+		})
+
+		It("fails when the partial file is larger than the object", func() {
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = client.New(s3Client, s3Config).GetRange(context.Background(), "some-object", dest, int64(len(object.content)+1), common.GetOptions{})
+			Expect(err).To(MatchError(ContainSubstring("than the remote object")))
+		})
+	})
+
+	Describe("Rename()", func() {
+		It("copies the object and deletes the source", func() {
+			object := &fakeS3Object{content: []byte("some content")}
+			server := httptest.NewServer(object)
+			DeferCleanup(server.Close)
+
+			s3Config := newFakeS3Config(server)
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = client.New(s3Client, s3Config).Rename(context.Background(), "old-object", "new-object")
+			Expect(err).ToNot(HaveOccurred())
+
+			puts := object.Requests(http.MethodPut)
+			Expect(puts).To(HaveLen(1))
+			Expect(puts[0].URL.Path).To(Equal("/some-bucket/new-object"))
+			Expect(puts[0].Header.Get("X-Amz-Copy-Source")).To(Equal("some-bucket/old-object"))
+
+			deletes := object.Requests(http.MethodDelete)
+			Expect(deletes).To(HaveLen(1))
+			Expect(deletes[0].URL.Path).To(Equal("/some-bucket/old-object"))
+		})
+
+		It("renames natively in directory buckets", func() {
+			s3Config := &config.S3Cli{
+				AccessKeyID:       "id",
+				SecretAccessKey:   "key",
+				CredentialsSource: config.StaticCredentialsSource,
+				BucketName:        "some-bucket--usw2-az1--x-s3",
+				Region:            "us-west-2",
+			}
+
+			var operations []string
+			s3Client, err := client.NewAwsS3ClientWithApiOptions(s3Config, []func(stack *middleware.Stack) error{captureOperation(&operations)})
+			Expect(err).ToNot(HaveOccurred())
+
+			err = client.New(s3Client, s3Config).Rename(context.Background(), "old-object", "new-object")
+			Expect(err).To(MatchError(errRequestCaptured))
+			Expect(operations).To(Equal([]string{"RenameObject"}))
+		})
+	})
+
+	Describe("Sign()", func() {
+		It("escapes keys with spaces, unicode and plus signs so the url reaches the object", func() {
+			object := &fakeS3Object{content: []byte("some content")}
+			server := httptest.NewServer(object)
+			DeferCleanup(server.Close)
+
+			s3Config := newFakeS3Config(server)
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			signedURL, err := client.New(s3Client, s3Config).Sign(context.Background(), "dir a/üñîçød ë file+1.txt", "get", time.Hour)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(signedURL).To(ContainSubstring("/some-bucket/dir%20a/%C3%BC%C3%B1%C3%AE%C3%A7%C3%B8d%20%C3%AB%20file%2B1.txt?"))
+
+			resp, err := http.Get(signedURL)
+			Expect(err).ToNot(HaveOccurred())
+			resp.Body.Close() //nolint:errcheck
+
+			gets := object.Requests(http.MethodGet)
+			Expect(gets).To(HaveLen(1))
+			Expect(gets[0].URL.Path).To(Equal("/some-bucket/dir a/üñîçød ë file+1.txt"))
+		})
+	})
+
+	Describe("SignWithOptions()", func() {
+		It("makes the content type and MD5 signed headers of a put url", func() {
+			server := httptest.NewServer(&fakeS3Object{})
+			DeferCleanup(server.Close)
+
+			s3Config := newFakeS3Config(server)
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			signedURL, err := client.New(s3Client, s3Config).SignWithOptions(context.Background(), "some-object", "put", time.Hour, common.SignOptions{
+				ContentType: "application/gzip",
+				ContentMD5:  "1B2M2Y8AsgTpgAmY7PhCfg==",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			parsed, err := url.Parse(signedURL)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed.Query().Get("X-Amz-SignedHeaders")).To(Equal("content-md5;content-type;host"))
+		})
+
+		It("dates a url with a start time at the start", func() {
+			server := httptest.NewServer(&fakeS3Object{})
+			DeferCleanup(server.Close)
+
+			s3Config := newFakeS3Config(server)
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			startAt := time.Date(2030, time.January, 2, 3, 4, 5, 0, time.UTC)
+			for _, action := range []string{"get", "put"} {
+				signedURL, err := client.New(s3Client, s3Config).SignWithOptions(context.Background(), "some-object", action, time.Hour, common.SignOptions{StartAt: startAt})
+				Expect(err).ToNot(HaveOccurred())
+
+				parsed, err := url.Parse(signedURL)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(parsed.Query().Get("X-Amz-Date")).To(Equal("20300102T030405Z"))
+				Expect(parsed.Query().Get("X-Amz-Expires")).To(Equal("3600"))
+			}
+		})
+
+		It("is not supported for openstack swift", func() {
+			s3Config := &config.S3Cli{BucketName: "some-bucket", SwiftAuthAccount: "account"}
+
+			_, err := client.New(nil, s3Config).SignWithOptions(context.Background(), "some-object", "put", time.Hour, common.SignOptions{ContentType: "application/gzip"})
+			Expect(err).To(MatchError(ContainSubstring("not supported for openstack swift")))
+		})
+	})
+
+	Describe("Size()", func() {
+		It("returns the content length of the object", func() {
+			object := &fakeS3Object{content: []byte("some content")}
+			server := httptest.NewServer(object)
+			DeferCleanup(server.Close)
+
+			s3Config := newFakeS3Config(server)
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			size, err := client.New(s3Client, s3Config).Size(context.Background(), "some-object")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(size).To(BeEquivalentTo(len("some content")))
+			Expect(object.Requests(http.MethodHead)).To(HaveLen(1))
+		})
+	})
+
+	Describe("CopyFromBucket()", func() {
+		It("looks up the source in its own region and copies into the configured bucket", func() {
+			s3Config := &config.S3Cli{
+				AccessKeyID:       "id",
+				SecretAccessKey:   "key",
+				CredentialsSource: config.StaticCredentialsSource,
+				BucketName:        "some-bucket",
+				Region:            "us-east-1",
+				UseSSL:            true,
+			}
+
+			var requests []*http.Request
+			s3Client, err := client.NewAwsS3ClientWithApiOptions(s3Config, []func(stack *middleware.Stack) error{stubResponses(&requests)})
+			Expect(err).ToNot(HaveOccurred())
+
+			err = client.New(s3Client, s3Config).CopyFromBucket(context.Background(), "source-bucket", "eu-west-1", "some/key", "new-key", common.CopyOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(requests).To(HaveLen(2))
+			Expect(requests[0].Method).To(Equal(http.MethodHead))
+			Expect(requests[0].URL.Host).To(Equal("s3.eu-west-1.amazonaws.com"))
+			Expect(requests[0].URL.Path).To(Equal("/source-bucket/some/key"))
+			Expect(requests[0].Header.Get("Authorization")).To(ContainSubstring("/eu-west-1/s3/"))
+
+			Expect(requests[1].Method).To(Equal(http.MethodPut))
+			Expect(requests[1].URL.Host).To(Equal("s3.us-east-1.amazonaws.com"))
+			Expect(requests[1].URL.Path).To(Equal("/some-bucket/new-key"))
+			Expect(requests[1].Header.Get("X-Amz-Copy-Source")).To(Equal("source-bucket/some/key"))
+		})
+	})
+
+	Describe("Copy()", func() {
+		var (
+			object   *fakeS3Object
+			s3Config *config.S3Cli
+		)
+
+		BeforeEach(func() {
+			object = &fakeS3Object{content: []byte("some content")}
+			server := httptest.NewServer(object)
+			DeferCleanup(server.Close)
+
+			s3Config = newFakeS3Config(server)
+		})
+
+		It("keeps the metadata of the source object by default", func() {
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = client.New(s3Client, s3Config).Copy(context.Background(), "old-object", "new-object", common.CopyOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			puts := object.Requests(http.MethodPut)
+			Expect(puts).To(HaveLen(1))
+			Expect(puts[0].Header.Get("X-Amz-Metadata-Directive")).To(BeEmpty())
+		})
+
+		It("replaces the metadata with --overwrite-metadata-on-copy", func() {
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = client.New(s3Client, s3Config).Copy(context.Background(), "old-object", "new-object", common.CopyOptions{ResetMetadata: true})
+			Expect(err).ToNot(HaveOccurred())
+
+			puts := object.Requests(http.MethodPut)
+			Expect(puts).To(HaveLen(1))
+			Expect(puts[0].Header.Get("X-Amz-Metadata-Directive")).To(Equal("REPLACE"))
+		})
+
+		It("URL-encodes the key of the copy source", func() {
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = client.New(s3Client, s3Config).Copy(context.Background(), "dir/some key+100%.txt", "new-object", common.CopyOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			puts := object.Requests(http.MethodPut)
+			Expect(puts).To(HaveLen(1))
+			Expect(puts[0].Header.Get("X-Amz-Copy-Source")).To(Equal("some-bucket/dir/some%20key%2B100%25.txt"))
+		})
+
+		Context("around the multipart copy threshold", func() {
+			BeforeEach(func() {
+				s3Config.MultipartCopyPartSize = 5
+			})
+
+			It("copies an object just below the threshold with a single CopyObject", func() {
+				s3Config.MultipartCopyThreshold = int64(len(object.content)) + 1
+
+				s3Client, err := client.NewAwsS3Client(s3Config)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = client.New(s3Client, s3Config).Copy(context.Background(), "old-object", "new-object", common.CopyOptions{})
+				Expect(err).ToNot(HaveOccurred())
+
+				puts := object.Requests(http.MethodPut)
+				Expect(puts).To(HaveLen(1))
+				Expect(puts[0].URL.Query().Has("partNumber")).To(BeFalse())
+				Expect(object.Requests(http.MethodPost)).To(BeEmpty())
+			})
+
+			It("copies an object at the threshold in parts", func() {
+				s3Config.MultipartCopyThreshold = int64(len(object.content))
+
+				s3Client, err := client.NewAwsS3Client(s3Config)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = client.New(s3Client, s3Config).Copy(context.Background(), "old-object", "new-object", common.CopyOptions{})
+				Expect(err).ToNot(HaveOccurred())
+
+				posts := object.Requests(http.MethodPost)
+				Expect(posts).To(HaveLen(2))
+				Expect(posts[0].URL.Query().Has("uploads")).To(BeTrue())
+				Expect(posts[1].URL.Query().Get("uploadId")).To(Equal("some-upload-id"))
+
+				puts := object.Requests(http.MethodPut)
+				Expect(puts).To(HaveLen(3))
+				for i, put := range puts {
+					Expect(put.URL.Query().Get("partNumber")).To(Equal(strconv.Itoa(i + 1)))
+				}
+				Expect(puts[0].Header.Get("X-Amz-Copy-Source-Range")).To(Equal("bytes=0-4"))
+				Expect(puts[2].Header.Get("X-Amz-Copy-Source-Range")).To(Equal("bytes=10-11"))
+			})
+
+			It("copies an object above the threshold with a single CopyObject when no_multipart_copy is set", func() {
+				s3Config.MultipartCopyThreshold = 1
+				s3Config.NoMultipartCopy = true
+
+				s3Client, err := client.NewAwsS3Client(s3Config)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = client.New(s3Client, s3Config).Copy(context.Background(), "old-object", "new-object", common.CopyOptions{})
+				Expect(err).ToNot(HaveOccurred())
+
+				puts := object.Requests(http.MethodPut)
+				Expect(puts).To(HaveLen(1))
+				Expect(puts[0].URL.Query().Has("partNumber")).To(BeFalse())
+				Expect(object.Requests(http.MethodPost)).To(BeEmpty())
+			})
+
+			It("copies an object above the threshold with a single CopyObject with --no-multipart-copy", func() {
+				s3Config.MultipartCopyThreshold = 1
+
+				s3Client, err := client.NewAwsS3Client(s3Config)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = client.New(s3Client, s3Config).CopyFromBucket(context.Background(), "some-bucket", "", "old-object", "new-object", common.CopyOptions{NoMultipart: true})
+				Expect(err).ToNot(HaveOccurred())
+
+				puts := object.Requests(http.MethodPut)
+				Expect(puts).To(HaveLen(1))
+				Expect(puts[0].Header.Get("X-Amz-Copy-Source")).To(Equal("some-bucket/old-object"))
+				Expect(object.Requests(http.MethodPost)).To(BeEmpty())
+			})
+		})
+
+		It("copies through the S3 API for openstack swift, which only signs urls differently", func() {
+			s3Config.SwiftAuthAccount = "account"
+			s3Config.SwiftTempURLKey = "key"
+
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = client.New(s3Client, s3Config).Copy(context.Background(), "old-object", "new-object", common.CopyOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			puts := object.Requests(http.MethodPut)
+			Expect(puts).To(HaveLen(1))
+			Expect(puts[0].URL.Path).To(Equal("/some-bucket/new-object"))
+			Expect(puts[0].Header.Get("X-Amz-Copy-Source")).To(Equal("some-bucket/old-object"))
+		})
+	})
+
+	Describe("Identity()", func() {
+		It("reports the access key ID but not the secret for non AWS providers", func() {
+			s3Config := &config.S3Cli{
+				AccessKeyID:       "some-access-key-id",
+				SecretAccessKey:   "some-secret-access-key",
+				CredentialsSource: config.StaticCredentialsSource,
+				BucketName:        "some-bucket",
+				Host:              "minio.example.com",
+				Region:            "us-east-1",
+			}
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			identity, err := client.New(s3Client, s3Config).Identity(context.Background())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(identity.CredentialsSource).To(Equal(config.StaticCredentialsSource))
+			Expect(identity.Principal).To(Equal("some-access-key-id"))
+			Expect(fmt.Sprintf("%+v", identity)).ToNot(ContainSubstring("some-secret-access-key"))
+		})
+	})
+
+	Describe("PutWithManifest()", func() {
+		var (
+			partBodies   map[string]string
+			completeBody string
+			aborted      bool
+			s3Config     *config.S3Cli
+			sourceFile   string
+		)
+
+		BeforeEach(func() {
+			partBodies = map[string]string{}
+			completeBody = ""
+			aborted = false
+			var mu sync.Mutex
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body) //nolint:errcheck
+				mu.Lock()
+				defer mu.Unlock()
+
+				query := r.URL.Query()
+				switch {
+				case r.Method == http.MethodPost && query.Has("uploads"):
+					w.Write([]byte(`<InitiateMultipartUploadResult><UploadId>some-upload-id</UploadId></InitiateMultipartUploadResult>`)) //nolint:errcheck
+				case r.Method == http.MethodPut && query.Get("uploadId") == "some-upload-id":
+					partBodies[query.Get("partNumber")] = string(body)
+					w.Header().Set("ETag", fmt.Sprintf(`"etag-%s"`, query.Get("partNumber")))
+				case r.Method == http.MethodPost && query.Get("uploadId") == "some-upload-id":
+					completeBody = string(body)
+					w.Write([]byte(`<CompleteMultipartUploadResult><Key>some-object</Key></CompleteMultipartUploadResult>`)) //nolint:errcheck
+				case r.Method == http.MethodDelete:
+					aborted = true
+					w.WriteHeader(http.StatusNoContent)
+				default:
+					w.WriteHeader(http.StatusBadRequest)
+				}
+			}))
+			DeferCleanup(server.Close)
+
+			s3Config = newFakeS3Config(server)
+
+			sourceFile = filepath.Join(GinkgoT().TempDir(), "source")
+			Expect(os.WriteFile(sourceFile, []byte("0123456789"), 0644)).To(Succeed())
+		})
+
+		It("uploads the parts listed in the manifest and completes them in part number order", func() {
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			manifest := common.UploadManifest{Parts: []common.UploadPart{
+				{PartNumber: 2, Offset: 6, Size: 4},
+				{PartNumber: 1, Offset: 0, Size: 6},
+			}}
+			err = client.New(s3Client, s3Config).PutWithManifest(context.Background(), sourceFile, "some-object", manifest, common.PutOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(partBodies).To(Equal(map[string]string{"1": "012345", "2": "6789"}))
+			Expect(completeBody).To(ContainSubstring(`<Part><ETag>&#34;etag-1&#34;</ETag><PartNumber>1</PartNumber></Part><Part><ETag>&#34;etag-2&#34;</ETag><PartNumber>2</PartNumber></Part>`))
+			Expect(aborted).To(BeFalse())
+		})
+
+		It("rejects a manifest that doesn't cover the file", func() {
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			manifest := common.UploadManifest{Parts: []common.UploadPart{{PartNumber: 1, Offset: 0, Size: 6}}}
+			err = client.New(s3Client, s3Config).PutWithManifest(context.Background(), sourceFile, "some-object", manifest, common.PutOptions{})
+			Expect(err).To(MatchError(ContainSubstring("invalid manifest: parts cover 6 bytes but the file is 10 bytes")))
+			Expect(partBodies).To(BeEmpty())
+		})
+	})
+
+	Describe("PutWithETag()", func() {
+		It("returns the ETag of the uploaded object without quotes", func() {
+			var uploaded []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPut {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				uploaded, _ = io.ReadAll(r.Body) //nolint:errcheck
+				w.Header().Set("ETag", fmt.Sprintf(`"%x"`, md5.Sum(uploaded)))
+			}))
+			DeferCleanup(server.Close)
+
+			s3Config := newFakeS3Config(server)
+			sourceFile := filepath.Join(GinkgoT().TempDir(), "source")
+			Expect(os.WriteFile(sourceFile, []byte("0123456789"), 0644)).To(Succeed())
+
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			etag, err := client.New(s3Client, s3Config).PutWithETag(context.Background(), sourceFile, "some-object", common.PutOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(uploaded)).To(Equal("0123456789"))
+			Expect(etag).To(Equal("781e5e245d69b566979b86e28d23f2c7"))
+		})
+	})
+
+	Describe("upload content type and metadata", func() {
+		var (
+			contentTypes []string
+			storedMD5s   []string
+			s3Config     *config.S3Cli
+			sourceFile   string
+			options      common.PutOptions
+		)
+
+		BeforeEach(func() {
+			contentTypes = nil
+			storedMD5s = nil
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				io.Copy(io.Discard, r.Body) //nolint:errcheck
+				switch {
+				case r.Method == http.MethodPost && r.URL.Query().Has("uploads"):
+					contentTypes = append(contentTypes, r.Header.Get("Content-Type"))
+					storedMD5s = append(storedMD5s, r.Header.Get("X-Amz-Meta-Md5"))
+					w.Write([]byte(`<InitiateMultipartUploadResult><UploadId>some-upload-id</UploadId></InitiateMultipartUploadResult>`)) //nolint:errcheck
+				case r.Method == http.MethodPut && !r.URL.Query().Has("uploadId"):
+					contentTypes = append(contentTypes, r.Header.Get("Content-Type"))
+					storedMD5s = append(storedMD5s, r.Header.Get("X-Amz-Meta-Md5"))
+				case r.Method == http.MethodPost:
+					w.Write([]byte(`<CompleteMultipartUploadResult><Key>some-object</Key></CompleteMultipartUploadResult>`)) //nolint:errcheck
+				}
+			}))
+			DeferCleanup(server.Close)
+
+			s3Config = newFakeS3Config(server)
+			sourceFile = filepath.Join(GinkgoT().TempDir(), "source")
+			Expect(os.WriteFile(sourceFile, []byte("0123456789"), 0644)).To(Succeed())
+
+			options = common.PutOptions{
+				ContentType: "text/csv",
+				Metadata:    map[string]string{common.MD5MetadataKey: "781e5e245d69b566979b86e28d23f2c7"},
+			}
+		})
+
+		It("stores the object with the content type and metadata on a put", func() {
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(client.New(s3Client, s3Config).Put(context.Background(), sourceFile, "some-object", options)).To(Succeed())
+			Expect(contentTypes).To(Equal([]string{"text/csv"}))
+			Expect(storedMD5s).To(Equal([]string{"781e5e245d69b566979b86e28d23f2c7"}))
+		})
+
+		It("stores the object with the content type and metadata on a single part put", func() {
+			s3Config.SingleUploadThreshold = 100
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(client.New(s3Client, s3Config).Put(context.Background(), sourceFile, "some-object", options)).To(Succeed())
+			Expect(contentTypes).To(Equal([]string{"text/csv"}))
+			Expect(storedMD5s).To(Equal([]string{"781e5e245d69b566979b86e28d23f2c7"}))
+		})
+
+		It("starts the multipart upload of a manifest with the content type and metadata", func() {
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			manifest := common.UploadManifest{Parts: []common.UploadPart{{PartNumber: 1, Offset: 0, Size: 10}}}
+			Expect(client.New(s3Client, s3Config).PutWithManifest(context.Background(), sourceFile, "some-object", manifest, options)).To(Succeed())
+			Expect(contentTypes).To(Equal([]string{"text/csv"}))
+			Expect(storedMD5s).To(Equal([]string{"781e5e245d69b566979b86e28d23f2c7"}))
+		})
+	})
+
+	Describe("Put() with a shared concurrency budget", func() {
+		It("uploads no more parts at the same time than the budget allows", func() {
+			var inFlight, maxInFlight, parts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				io.Copy(io.Discard, r.Body) //nolint:errcheck
+				switch {
+				case r.Method == http.MethodPost && r.URL.Query().Has("uploads"):
+					w.Write([]byte(`<InitiateMultipartUploadResult><UploadId>some-upload-id</UploadId></InitiateMultipartUploadResult>`)) //nolint:errcheck
+				case r.Method == http.MethodPut && r.URL.Query().Has("partNumber"):
+					current := inFlight.Add(1)
+					defer inFlight.Add(-1)
+					for {
+						highest := maxInFlight.Load()
+						if current <= highest || maxInFlight.CompareAndSwap(highest, current) {
+							break
+						}
+					}
+					parts.Add(1)
+					time.Sleep(20 * time.Millisecond)
+					w.Header().Set("ETag", `"etag-`+r.URL.Query().Get("partNumber")+`"`)
+				case r.Method == http.MethodPost:
+					w.Write([]byte(`<CompleteMultipartUploadResult><Key>some-object</Key></CompleteMultipartUploadResult>`)) //nolint:errcheck
+				}
+			}))
+			DeferCleanup(server.Close)
+
+			s3Config := newFakeS3Config(server)
+			s3Config.UploadConcurrency = 4
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+			blobstore := client.New(s3Client, s3Config)
+
+			sourceFile := filepath.Join(GinkgoT().TempDir(), "source")
+			Expect(os.WriteFile(sourceFile, make([]byte, 20*1024*1024), 0644)).To(Succeed())
+
+			options := common.PutOptions{Concurrency: common.NewConcurrencyLimiter(3)}
+			var wg sync.WaitGroup
+			for _, dest := range []string{"some-object", "other-object"} {
+				wg.Go(func() {
+					defer GinkgoRecover()
+					Expect(blobstore.Put(context.Background(), sourceFile, dest, options)).To(Succeed())
+				})
+			}
+			wg.Wait()
+
+			Expect(parts.Load()).To(Equal(int32(8)))
+			Expect(maxInFlight.Load()).To(BeNumerically("<=", 3))
+		})
+	})
+
+	Describe("Properties()", func() {
+		It("returns the same properties as the other storage types for an equivalent object", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("ETag", `"9a0364b9e99bb480dd25e1f0284c8555"`)
+				w.Header().Set("Last-Modified", "Fri, 01 Mar 2024 12:30:45 GMT")
+				w.Header().Set("Content-Length", "7")
+				w.Header().Set("X-Amz-Meta-Owner", "team-a")
+			}))
+			DeferCleanup(server.Close)
+
+			s3Config := newFakeS3Config(server)
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			properties, err := client.New(s3Client, s3Config).Properties(context.Background(), "some-object")
+			Expect(err).ToNot(HaveOccurred())
+			// The gcs and alioss tests expect the very same document
+			Expect(common.MarshalObjectProperties(properties)).To(BeEquivalentTo(`{
+  "content_length": 7,
+  "etag": "9a0364b9e99bb480dd25e1f0284c8555",
+  "last_modified": "2024-03-01T12:30:45Z",
+  "metadata": {
+    "owner": "team-a"
+  }
+}`))
+		})
+	})
+
+	Describe("ListDetailed()", func() {
+		var (
+			listed   []*http.Request
+			s3Config *config.S3Cli
+		)
+
+		BeforeEach(func() {
+			listed = nil
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				listed = append(listed, r)
+				w.Write([]byte(`<ListBucketResult><Name>some-bucket</Name><IsTruncated>false</IsTruncated>` + //nolint:errcheck
+					`<Contents><Key>folder/cache/a</Key><Size>10</Size><LastModified>2024-05-01T10:00:00.000Z</LastModified></Contents>` +
+					`<Contents><Key>folder/cache/b</Key><Size>20</Size><LastModified>2024-06-01T10:00:00.000Z</LastModified></Contents></ListBucketResult>`))
+			}))
+			DeferCleanup(server.Close)
+
+			s3Config = newFakeS3Config(server)
+			s3Config.FolderName = "folder"
+		})
+
+		It("returns the size and last modification time of the objects with keys relative to the folder", func() {
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			objects, err := client.New(s3Client, s3Config).ListDetailed(context.Background(), "cache/")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(listed).To(HaveLen(1))
+			Expect(listed[0].URL.Query().Get("prefix")).To(Equal("folder/cache/"))
+			Expect(objects).To(Equal([]common.ObjectInfo{
+				{Key: "cache/a", Size: 10, LastModified: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)},
+				{Key: "cache/b", Size: 20, LastModified: time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)},
+			}))
+		})
+
+		It("lists the same keys as List", func() {
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+			blobstore := client.New(s3Client, s3Config)
+
+			keys, err := blobstore.List(context.Background(), "")
+			Expect(err).ToNot(HaveOccurred())
+			objects, err := blobstore.ListDetailed(context.Background(), "")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(listed).To(HaveLen(2))
+			Expect(listed[0].URL.Query().Get("prefix")).To(Equal("folder/"))
+			Expect(listed[1].URL.Query().Get("prefix")).To(Equal("folder/"))
+			Expect(keys).To(Equal([]string{"cache/a", "cache/b"}))
+			Expect(objects[0].Key).To(Equal(keys[0]))
+			Expect(objects[1].Key).To(Equal(keys[1]))
+		})
+
+		It("asks for no more keys than the limit and stops there", func() {
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			objects, err := client.New(s3Client, s3Config).ListDetailedWithLimit(context.Background(), "cache/", 1)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(listed).To(HaveLen(1))
+			Expect(listed[0].URL.Query().Get("max-keys")).To(Equal("1"))
+			Expect(objects).To(Equal([]common.ObjectInfo{
+				{Key: "cache/a", Size: 10, LastModified: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)},
+			}))
+		})
+	})
+
+	Describe("Exists()", func() {
+		var (
+			status   int
+			s3Config *config.S3Cli
+		)
+
+		BeforeEach(func() {
+			status = http.StatusOK
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(status)
+			}))
+			DeferCleanup(server.Close)
+
+			s3Config = newFakeS3Config(server)
+		})
+
+		It("reports a missing object", func() {
+			status = http.StatusNotFound
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			exists, err := client.New(s3Client, s3Config).Exists(context.Background(), "some-object")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(exists).To(BeFalse())
+		})
+
+		It("marks a denied access", func() {
+			status = http.StatusForbidden
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = client.New(s3Client, s3Config).Exists(context.Background(), "some-object")
+			Expect(err).To(MatchError(common.ErrAccessDenied))
+		})
+	})
+
+	Context("with public buckets and requester pays buckets", func() {
+		var (
+			object   *fakeS3Object
+			s3Config *config.S3Cli
+		)
+
+		BeforeEach(func() {
+			object = &fakeS3Object{content: []byte("some content")}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !r.URL.Query().Has("list-type") {
+					object.ServeHTTP(w, r)
+					return
+				}
+				object.mu.Lock()
+				object.requests = append(object.requests, r.Clone(r.Context()))
+				object.mu.Unlock()
+				w.Write([]byte(`<ListBucketResult><Name>some-bucket</Name><IsTruncated>false</IsTruncated>` + //nolint:errcheck
+					`<Contents><Key>some-object</Key><Size>12</Size></Contents></ListBucketResult>`))
+			}))
+			DeferCleanup(server.Close)
+
+			s3Config = newFakeS3Config(server)
+		})
+
+		Context("with the none credentials source", func() {
+			var blobstoreClient *client.S3CompatibleClient
+
+			BeforeEach(func() {
+				s3Config.AccessKeyID = ""
+				s3Config.SecretAccessKey = ""
+				s3Config.CredentialsSource = config.NoneCredentialsSource
+				s3Client, err := client.NewAwsS3Client(s3Config)
+				Expect(err).ToNot(HaveOccurred())
+				blobstoreClient = client.New(s3Client, s3Config)
+			})
+
+			It("reads without signing the requests", func() {
+				dest := filepath.Join(GinkgoT().TempDir(), "object")
+				Expect(blobstoreClient.Get(context.Background(), "some-object", dest, common.GetOptions{})).To(Succeed())
+				Expect(os.ReadFile(dest)).To(Equal(object.content))
+
+				exists, err := blobstoreClient.Exists(context.Background(), "some-object")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(exists).To(BeTrue())
+
+				keys, err := blobstoreClient.List(context.Background(), "")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(keys).To(Equal([]string{"some-object"}))
+
+				requests := append(object.Requests(http.MethodGet), object.Requests(http.MethodHead)...)
+				Expect(requests).To(HaveLen(3))
+				for _, request := range requests {
+					Expect(request.Header.Get("Authorization")).To(BeEmpty())
+				}
+			})
+
+			It("refuses to write with the read only error", func() {
+				source := filepath.Join(GinkgoT().TempDir(), "source")
+				Expect(os.WriteFile(source, []byte("content"), 0644)).To(Succeed())
+
+				readOnly := ContainSubstring("the client operates in read only mode")
+				Expect(blobstoreClient.Put(context.Background(), source, "some-object", common.PutOptions{})).To(MatchError(readOnly))
+				Expect(blobstoreClient.Delete(context.Background(), "some-object")).To(MatchError(readOnly))
+				Expect(blobstoreClient.DeleteRecursive(context.Background(), "", common.DeleteRecursiveOptions{})).To(MatchError(readOnly))
+				Expect(blobstoreClient.Copy(context.Background(), "some-object", "copied-object", common.CopyOptions{})).To(MatchError(readOnly))
+				Expect(blobstoreClient.CopyFromBucket(context.Background(), "other-bucket", "", "some-object", "copied-object", common.CopyOptions{})).To(MatchError(readOnly))
+				Expect(blobstoreClient.Rename(context.Background(), "some-object", "renamed-object")).To(MatchError(readOnly))
+
+				Expect(object.Requests(http.MethodPut)).To(BeEmpty())
+				Expect(object.Requests(http.MethodPost)).To(BeEmpty())
+				Expect(object.Requests(http.MethodDelete)).To(BeEmpty())
+			})
+		})
+
+		It("confirms that the requester pays for reads and copies with request_payer", func() {
+			s3Config.RequestPayer = config.RequesterRequestPayer
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+			blobstoreClient := client.New(s3Client, s3Config)
+
+			Expect(blobstoreClient.Get(context.Background(), "some-object", filepath.Join(GinkgoT().TempDir(), "object"), common.GetOptions{})).To(Succeed())
+			_, err = blobstoreClient.Exists(context.Background(), "some-object")
+			Expect(err).ToNot(HaveOccurred())
+			_, err = blobstoreClient.List(context.Background(), "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blobstoreClient.Copy(context.Background(), "some-object", "copied-object", common.CopyOptions{})).To(Succeed())
+
+			requests := append(object.Requests(http.MethodGet), object.Requests(http.MethodHead)...)
+			requests = append(requests, object.Requests(http.MethodPut)...)
+			Expect(requests).To(HaveLen(5))
+			for _, request := range requests {
+				Expect(request.Header.Get("X-Amz-Request-Payer")).To(Equal("requester"), "%s %s", request.Method, request.URL)
+			}
+		})
+
+		It("doesn't send the request payer header without request_payer", func() {
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = client.New(s3Client, s3Config).Exists(context.Background(), "some-object")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(object.Requests(http.MethodHead)[0].Header.Get("X-Amz-Request-Payer")).To(BeEmpty())
+		})
+	})
+
+	Describe("DeleteRecursive()", func() {
+		var (
+			lock           sync.Mutex
+			batches        [][]string
+			deleted        []string
+			failing        map[string]string
+			inFlight       int
+			maxInFlight    int
+			s3Config       *config.S3Cli
+			blobstore      *client.S3CompatibleClient
+			newBlobstore   func()
+			listedKeyCount int
+		)
+		deleteObjects := regexp.MustCompile(`<Key>([^<]*)</Key>`)
+
+		BeforeEach(func() {
+			batches, deleted, failing, inFlight, maxInFlight = nil, nil, map[string]string{}, 0, 0
+			listedKeyCount = 2500
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodGet:
+					listing := &strings.Builder{}
+					listing.WriteString(`<ListBucketResult><Name>some-bucket</Name><IsTruncated>false</IsTruncated>`)
+					for i := range listedKeyCount {
+						fmt.Fprintf(listing, `<Contents><Key>key-%04d</Key></Contents>`, i)
+					}
+					listing.WriteString(`</ListBucketResult>`)
+					w.Write([]byte(listing.String())) //nolint:errcheck
+				case r.Method == http.MethodPost && r.URL.Query().Has("delete"):
+					body, _ := io.ReadAll(r.Body) //nolint:errcheck
+					var keys []string
+					for _, match := range deleteObjects.FindAllStringSubmatch(string(body), -1) {
+						keys = append(keys, match[1])
+					}
+
+					lock.Lock()
+					batches = append(batches, keys)
+					inFlight++
+					maxInFlight = max(maxInFlight, inFlight)
+					lock.Unlock()
+					time.Sleep(20 * time.Millisecond)
+					lock.Lock()
+					inFlight--
+					lock.Unlock()
+
+					result := &strings.Builder{}
+					result.WriteString(`<DeleteResult>`)
+					for _, key := range keys {
+						if code, ok := failing[key]; ok {
+							fmt.Fprintf(result, `<Error><Key>%s</Key><Code>%s</Code><Message>some message</Message></Error>`, key, code)
+						}
+					}
+					result.WriteString(`</DeleteResult>`)
+					w.Write([]byte(result.String())) //nolint:errcheck
+				case r.Method == http.MethodDelete:
+					lock.Lock()
+					deleted = append(deleted, r.URL.Path)
+					lock.Unlock()
+					w.WriteHeader(http.StatusNoContent)
+				default:
+					w.WriteHeader(http.StatusBadRequest)
+				}
+			}))
+			DeferCleanup(server.Close)
+
+			s3Config = newFakeS3Config(server)
+			newBlobstore = func() {
+				s3Client, err := client.NewAwsS3Client(s3Config)
+				Expect(err).ToNot(HaveOccurred())
+				blobstore = client.New(s3Client, s3Config)
+			}
+			newBlobstore()
+		})
+
+		It("deletes the objects with DeleteObjects, 1000 keys at a time, one batch after the other", func() {
+			Expect(blobstore.DeleteRecursive(context.Background(), "", common.DeleteRecursiveOptions{})).To(Succeed())
+
+			Expect(batches).To(HaveLen(3))
+			Expect(batches[0]).To(HaveLen(1000))
+			Expect(batches[0][0]).To(Equal("key-0000"))
+			Expect(batches[1]).To(HaveLen(1000))
+			Expect(batches[2]).To(HaveLen(500))
+			Expect(batches[2][499]).To(Equal("key-2499"))
+			Expect(maxInFlight).To(Equal(1))
+			Expect(deleted).To(BeEmpty())
+		})
+
+		It("sends as many batches at once as the delete concurrency allows", func() {
+			Expect(blobstore.DeleteRecursive(context.Background(), "", common.DeleteRecursiveOptions{Concurrency: 2})).To(Succeed())
+
+			Expect(batches).To(HaveLen(3))
+			Expect(maxInFlight).To(Equal(2))
+		})
+
+		It("tolerates objects that are already gone", func() {
+			failing["key-0001"] = "NoSuchKey"
+
+			Expect(blobstore.DeleteRecursive(context.Background(), "", common.DeleteRecursiveOptions{})).To(Succeed())
+		})
+
+		It("stops after the batch with the first failing object when failing fast", func() {
+			failing["key-0001"] = "AccessDenied"
+
+			err := blobstore.DeleteRecursive(context.Background(), "", common.DeleteRecursiveOptions{})
+			Expect(err).To(MatchError("failed to delete object 'key-0001': AccessDenied: some message"))
+			Expect(batches).To(HaveLen(1))
+		})
+
+		It("deletes the remaining batches and reports all failures when continuing on error", func() {
+			failing["key-0001"] = "AccessDenied"
+			failing["key-2001"] = "AccessDenied"
+
+			err := blobstore.DeleteRecursive(context.Background(), "", common.DeleteRecursiveOptions{ContinueOnError: true})
+			Expect(err).To(MatchError(ContainSubstring("failed to delete object 'key-0001'")))
+			Expect(err).To(MatchError(ContainSubstring("failed to delete object 'key-2001'")))
+			Expect(batches).To(HaveLen(3))
+		})
+
+		It("deletes object by object on Google Cloud Storage, which has no DeleteObjects", func() {
+			listedKeyCount = 3
+
+			proxyURL := fmt.Sprintf("http://%s:%d", s3Config.Host, s3Config.Port)
+			s3Config.Host = "storage.googleapis.com"
+			s3Config.Port = 0
+			s3Config.ProxyURL = proxyURL
+			newBlobstore()
+
+			Expect(blobstore.DeleteRecursive(context.Background(), "", common.DeleteRecursiveOptions{})).To(Succeed())
+			Expect(batches).To(BeEmpty())
+			Expect(deleted).To(ConsistOf("/some-bucket/key-0000", "/some-bucket/key-0001", "/some-bucket/key-0002"))
+		})
+	})
+
+	Describe("with an operation context", func() {
+		It("aborts a request in flight when the context is cancelled", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-r.Context().Done():
+				case <-time.After(10 * time.Second):
+				}
+			}))
+			DeferCleanup(server.Close)
+
+			s3Config := newFakeS3Config(server)
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(50*time.Millisecond, cancel)
+			started := time.Now()
+			_, err = client.New(s3Client, s3Config).Exists(ctx, "some-object")
+			Expect(err).To(MatchError(context.Canceled))
+			Expect(time.Since(started)).To(BeNumerically("<", 5*time.Second))
+		})
+	})
+})
@@ -1,14 +1,23 @@
 package client
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 
+	"github.com/cloudfoundry/storage-cli/common"
 	"github.com/cloudfoundry/storage-cli/s3/config"
 )
 
+// Files up to this size are buffered in memory before they are uploaded
+const inMemoryUploadThreshold = int64(1024 * 1024) // 1 MB
+
 type S3CompatibleClient struct {
 	s3cliConfig             *config.S3Cli
 	awsS3BlobstoreClient    *awsS3Client
@@ -29,69 +38,218 @@ func New(s3Client *s3.Client, s3cliConfig *config.S3Cli) *S3CompatibleClient {
 	}
 }
 
-func (c *S3CompatibleClient) Get(src string, dest string) error {
+func (c *S3CompatibleClient) Get(ctx context.Context, src string, dest string, options common.GetOptions) error {
 	dstFile, err := os.Create(dest)
 	if err != nil {
 		return err
 	}
 	defer dstFile.Close() //nolint:errcheck
-	return c.awsS3BlobstoreClient.Get(src, dstFile)
+	return c.awsS3BlobstoreClient.Get(ctx, src, common.NewThrottledWriter(ctx, options.Bandwidth, common.NewChecksummingWriter(options.Checksum, dstFile)))
 }
 
-func (c *S3CompatibleClient) Put(src string, dest string) error {
-	sourceFile, err := os.Open(src)
+func (c *S3CompatibleClient) GetRange(ctx context.Context, src string, dest string, offset int64, options common.GetOptions) error {
+	dstFile, err := openRangeDestination(dest, offset)
 	if err != nil {
 		return err
 	}
+	defer dstFile.Close() //nolint:errcheck
+	return c.awsS3BlobstoreClient.GetRange(ctx, src, common.NewThrottledWriter(ctx, options.Bandwidth, dstFile), offset, options.IfMatch)
+}
+
+func (c *S3CompatibleClient) Put(ctx context.Context, src string, dest string, options common.PutOptions) error {
+	_, err := c.PutWithETag(ctx, src, dest, options)
+	return err
+}
+
+// PutWithETag uploads src like Put and returns the ETag of the new object as reported by the upload
+func (c *S3CompatibleClient) PutWithETag(ctx context.Context, src string, dest string, options common.PutOptions) (string, error) {
+	sourceFile, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
 	defer sourceFile.Close() //nolint:errcheck
 
 	info, err := sourceFile.Stat()
 	if err != nil {
-		return err
+		return "", err
 	}
 	size := info.Size()
+	source := common.NewThrottledReader(ctx, options.Bandwidth, sourceFile)
+
+	// Small files are read in one go and sent from memory, which saves the
+	// SDK from seeking and re-reading the file handle while signing and sending
+	if size <= inMemoryUploadThreshold {
+		content, err := io.ReadAll(source)
+		if err != nil {
+			return "", err
+		}
+		return c.awsS3BlobstoreClient.PutSinglePart(ctx, bytes.NewReader(content), dest, options)
+	}
 
 	if size <= c.s3cliConfig.SingleUploadThreshold {
-		return c.awsS3BlobstoreClient.PutSinglePart(sourceFile, dest)
+		return c.awsS3BlobstoreClient.PutSinglePart(ctx, source, dest, options)
 	}
-	return c.awsS3BlobstoreClient.Put(sourceFile, dest)
+	return c.awsS3BlobstoreClient.Put(ctx, source, dest, options)
 }
 
-func (c *S3CompatibleClient) Delete(dest string) error {
-	return c.awsS3BlobstoreClient.Delete(dest)
+// PutWithManifest uploads src in the parts listed in the manifest
+func (c *S3CompatibleClient) PutWithManifest(ctx context.Context, src string, dest string, manifest common.UploadManifest, options common.PutOptions) error {
+	sourceFile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer sourceFile.Close() //nolint:errcheck
+
+	info, err := sourceFile.Stat()
+	if err != nil {
+		return err
+	}
+	if err := manifest.Validate(info.Size()); err != nil {
+		return fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	return c.awsS3BlobstoreClient.PutParts(ctx, common.NewThrottledReader(ctx, options.Bandwidth, sourceFile), dest, manifest, options)
+}
+
+func (c *S3CompatibleClient) Delete(ctx context.Context, dest string) error {
+	return c.awsS3BlobstoreClient.Delete(ctx, dest)
+}
+
+func (c *S3CompatibleClient) Exists(ctx context.Context, dest string) (bool, error) {
+	return c.awsS3BlobstoreClient.Exists(ctx, dest)
 }
 
-func (c *S3CompatibleClient) Exists(dest string) (bool, error) {
-	return c.awsS3BlobstoreClient.Exists(dest)
+func (c *S3CompatibleClient) Size(ctx context.Context, dest string) (int64, error) {
+	return c.awsS3BlobstoreClient.Size(ctx, dest)
 }
 
-func (c *S3CompatibleClient) Sign(objectID string, action string, expiration time.Duration) (string, error) {
+func (c *S3CompatibleClient) Sign(ctx context.Context, objectID string, action string, expiration time.Duration) (string, error) {
 	if c.s3cliConfig.SwiftAuthAccount != "" {
 		return c.openstackSwiftBlobstore.Sign(objectID, action, expiration)
 	}
 
-	return c.awsS3BlobstoreClient.Sign(objectID, action, expiration)
+	return c.awsS3BlobstoreClient.Sign(ctx, objectID, action, expiration)
 }
 
-func (c *S3CompatibleClient) EnsureStorageExists() error {
-	return c.awsS3BlobstoreClient.EnsureStorageExists()
+func (c *S3CompatibleClient) SignWithOptions(ctx context.Context, objectID string, action string, expiration time.Duration, options common.SignOptions) (string, error) {
+	if c.s3cliConfig.SwiftAuthAccount != "" {
+		return "", errors.New("signing with content type, MD5 or start time is not supported for openstack swift")
+	}
+
+	return c.awsS3BlobstoreClient.SignWithOptions(ctx, objectID, action, expiration, options)
 }
 
-func (c *S3CompatibleClient) Copy(srcBlob string, dstBlob string) error {
-	return c.awsS3BlobstoreClient.Copy(srcBlob, dstBlob)
+// ValidateSign checks that SignWithOptions would sign the URL, without signing it
+func (c *S3CompatibleClient) ValidateSign(ctx context.Context, objectID string, action string, expiration time.Duration, options common.SignOptions) error {
+	if c.s3cliConfig.SwiftAuthAccount != "" {
+		if options != (common.SignOptions{}) {
+			return errors.New("signing with content type, MD5 or start time is not supported for openstack swift")
+		}
+		return c.openstackSwiftBlobstore.ValidateSign(action)
+	}
 
+	return c.awsS3BlobstoreClient.ValidateSign(action, expiration)
 }
 
-func (c *S3CompatibleClient) Properties(dest string) error {
-	return c.awsS3BlobstoreClient.Properties(dest)
+func (c *S3CompatibleClient) EnsureStorageExists(ctx context.Context) error {
+	return c.awsS3BlobstoreClient.EnsureStorageExists(ctx)
+}
+
+func (c *S3CompatibleClient) Copy(ctx context.Context, srcBlob string, dstBlob string, options common.CopyOptions) error {
+	return c.awsS3BlobstoreClient.Copy(ctx, srcBlob, dstBlob, options)
+
+}
+
+func (c *S3CompatibleClient) CopyFromBucket(ctx context.Context, srcBucket string, srcRegion string, srcBlob string, dstBlob string, options common.CopyOptions) error {
+	return c.awsS3BlobstoreClient.CopyFromBucket(ctx, srcBucket, srcRegion, srcBlob, dstBlob, options)
+}
+
+func (c *S3CompatibleClient) CopyToBucket(ctx context.Context, srcBlob string, dstBucket string, dstBlob string, options common.CopyOptions) error {
+	return errors.New("not implemented")
+}
+
+func (c *S3CompatibleClient) Rename(ctx context.Context, srcBlob string, dstBlob string) error {
+	return c.awsS3BlobstoreClient.Rename(ctx, srcBlob, dstBlob)
+}
+
+// Capabilities lists the operations the client supports, all but copying into another bucket.
+// OpenStack Swift signs urls itself and can't sign them with options.
+func (c *S3CompatibleClient) Capabilities() []string {
+	capabilities := []string{
+		common.CapabilityPut,
+		common.CapabilityPutWithETag,
+		common.CapabilityPutWithManifest,
+		common.CapabilityGet,
+		common.CapabilityGetRange,
+		common.CapabilityDelete,
+		common.CapabilityDeleteRecursive,
+		common.CapabilityExists,
+		common.CapabilitySize,
+		common.CapabilitySign,
+	}
+	if c.s3cliConfig.SwiftAuthAccount == "" {
+		capabilities = append(capabilities, common.CapabilitySignContent, common.CapabilitySignStartAt)
+	}
+	return append(capabilities,
+		common.CapabilityList,
+		common.CapabilityCopy,
+		common.CapabilityCopyFromBucket,
+		common.CapabilityRename,
+		common.CapabilityProperties,
+		common.CapabilityHead,
+		common.CapabilityEnsureStorageExists,
+		common.CapabilityIdentity,
+	)
+}
+
+func (c *S3CompatibleClient) Identity(ctx context.Context) (common.Identity, error) {
+	return c.awsS3BlobstoreClient.Identity(ctx)
+}
 
+func (c *S3CompatibleClient) Properties(ctx context.Context, dest string) (common.ObjectProperties, error) {
+	return c.awsS3BlobstoreClient.Properties(ctx, dest, common.PropertiesOptions{})
 }
 
-func (c *S3CompatibleClient) List(prefix string) ([]string, error) {
-	return c.awsS3BlobstoreClient.List(prefix)
+func (c *S3CompatibleClient) PropertiesWithOptions(ctx context.Context, dest string, options common.PropertiesOptions) (common.ObjectProperties, error) {
+	return c.awsS3BlobstoreClient.Properties(ctx, dest, options)
 
 }
 
-func (c *S3CompatibleClient) DeleteRecursive(prefix string) error {
-	return c.awsS3BlobstoreClient.DeleteRecursive(prefix)
+func (c *S3CompatibleClient) Head(ctx context.Context, dest string) (common.ObjectHead, error) {
+	return c.awsS3BlobstoreClient.Head(ctx, dest)
+}
+
+func (c *S3CompatibleClient) List(ctx context.Context, prefix string) ([]string, error) {
+	return c.awsS3BlobstoreClient.List(ctx, prefix, 0)
+
+}
+
+func (c *S3CompatibleClient) ListWithLimit(ctx context.Context, prefix string, limit int) ([]string, error) {
+	return c.awsS3BlobstoreClient.List(ctx, prefix, limit)
+}
+
+func (c *S3CompatibleClient) ListDetailed(ctx context.Context, prefix string) ([]common.ObjectInfo, error) {
+	return c.awsS3BlobstoreClient.ListDetailed(ctx, prefix, 0)
+}
+
+func (c *S3CompatibleClient) ListDetailedWithLimit(ctx context.Context, prefix string, limit int) ([]common.ObjectInfo, error) {
+	return c.awsS3BlobstoreClient.ListDetailed(ctx, prefix, limit)
+}
+
+func (c *S3CompatibleClient) DeleteRecursive(ctx context.Context, prefix string, options common.DeleteRecursiveOptions) error {
+	return c.awsS3BlobstoreClient.DeleteRecursive(ctx, prefix, options)
+}
+
+// openRangeDestination opens dest for writing and drops anything beyond offset,
+// so that the ranged download always continues right after the kept bytes
+func openRangeDestination(dest string, offset int64) (*os.File, error) {
+	dstFile, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := dstFile.Truncate(offset); err != nil {
+		dstFile.Close() //nolint:errcheck
+		return nil, err
+	}
+	return dstFile, nil
 }
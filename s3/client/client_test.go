@@ -1,12 +1,21 @@
 package client_test
 
 import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go/middleware"
 
+	"github.com/cloudfoundry/storage-cli/common"
 	"github.com/cloudfoundry/storage-cli/s3/client"
 	"github.com/cloudfoundry/storage-cli/s3/config"
 
@@ -62,7 +71,7 @@ var _ = Describe("S3CompatibleClient", func() {
 				})
 
 				It("returns a signed URL", func() {
-					url, err := blobstoreClient.Sign(objectId, action, expiration)
+					url, err := blobstoreClient.Sign(context.Background(), objectId, action, expiration)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(url).To(MatchRegexp(urlRegexp))
@@ -75,20 +84,43 @@ var _ = Describe("S3CompatibleClient", func() {
 				})
 
 				It("returns a signed URL", func() {
-					url, err := blobstoreClient.Sign(objectId, action, expiration)
+					url, err := blobstoreClient.Sign(context.Background(), objectId, action, expiration)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(url).To(MatchRegexp(urlRegexp))
 				})
 			})
 
+			It("refuses an expiration beyond 7 days", func() {
+				_, err := blobstoreClient.Sign(context.Background(), objectId, "get", 7*24*time.Hour+time.Second)
+				Expect(err).To(MatchError("expiration 168h0m1s exceeds the maximum of 168h0m0s for presigned URLs"))
+			})
+
+			It("refuses to sign with the none credentials source", func() {
+				s3Config.CredentialsSource = config.NoneCredentialsSource
+
+				_, err := blobstoreClient.Sign(context.Background(), objectId, "get", expiration)
+				Expect(err).To(MatchError(ContainSubstring("signing URLs requires credentials")))
+			})
+
+			It("validates the same limits without signing", func() {
+				Expect(blobstoreClient.ValidateSign(context.Background(), objectId, "put", 7*24*time.Hour, common.SignOptions{})).To(Succeed())
+
+				err := blobstoreClient.ValidateSign(context.Background(), objectId, "get", 7*24*time.Hour+time.Second, common.SignOptions{})
+				Expect(err).To(MatchError("expiration 168h0m1s exceeds the maximum of 168h0m0s for presigned URLs"))
+
+				s3Config.CredentialsSource = config.NoneCredentialsSource
+				err = blobstoreClient.ValidateSign(context.Background(), objectId, "get", expiration, common.SignOptions{})
+				Expect(err).To(MatchError(ContainSubstring("signing URLs requires credentials")))
+			})
+
 			Context("when the action is neither GET nor PUT", func() {
 				BeforeEach(func() {
 					action = "UNSUPPORTED_ACTION"
 				})
 
 				It("returns an error", func() {
-					_, err := blobstoreClient.Sign(objectId, action, expiration)
+					_, err := blobstoreClient.Sign(context.Background(), objectId, action, expiration)
 					Expect(err).To(HaveOccurred())
 				})
 			})
@@ -122,7 +154,7 @@ var _ = Describe("S3CompatibleClient", func() {
 				})
 
 				It("returns a signed URL", func() {
-					url, err := blobstoreClient.Sign(objectId, action, expiration)
+					url, err := blobstoreClient.Sign(context.Background(), objectId, action, expiration)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(url).To(MatchRegexp(urlRegexp))
@@ -135,7 +167,7 @@ var _ = Describe("S3CompatibleClient", func() {
 				})
 
 				It("returns a signed URL", func() {
-					url, err := blobstoreClient.Sign(objectId, action, expiration)
+					url, err := blobstoreClient.Sign(context.Background(), objectId, action, expiration)
 					Expect(err).NotTo(HaveOccurred())
 
 					Expect(url).To(MatchRegexp(urlRegexp))
@@ -148,10 +180,256 @@ var _ = Describe("S3CompatibleClient", func() {
 				})
 
 				It("returns an error", func() {
-					_, err := blobstoreClient.Sign(objectId, action, expiration)
+					_, err := blobstoreClient.Sign(context.Background(), objectId, action, expiration)
 					Expect(err).To(HaveOccurred())
 				})
 			})
+
+			It("validates that the temp url key is configured", func() {
+				Expect(blobstoreClient.ValidateSign(context.Background(), objectId, "get", expiration, common.SignOptions{})).To(Succeed())
+
+				s3Config.SwiftTempURLKey = ""
+				err := blobstoreClient.ValidateSign(context.Background(), objectId, "get", expiration, common.SignOptions{})
+				Expect(err).To(MatchError("signing URLs for openstack swift requires the swift_temp_url_key"))
+			})
+		})
+	})
+
+	Describe("Put()", func() {
+		var (
+			bodies   []io.Reader
+			requests []*http.Request
+			source   string
+		)
+
+		BeforeEach(func() {
+			bodies = nil
+			requests = nil
+			source = filepath.Join(GinkgoT().TempDir(), "source")
+
+			s3Config = &config.S3Cli{
+				AccessKeyID:       "id",
+				SecretAccessKey:   "key",
+				CredentialsSource: config.StaticCredentialsSource,
+				BucketName:        "some-bucket",
+				Region:            "us-east-1",
+			}
+			s3Client, err := client.NewAwsS3ClientWithApiOptions(s3Config, []func(stack *middleware.Stack) error{
+				capturePutObjectBody(&bodies),
+				stubResponses(&requests),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			blobstoreClient = client.New(s3Client, s3Config)
+		})
+
+		It("uploads small files from memory", func() {
+			err := os.WriteFile(source, bytes.Repeat([]byte("x"), 1024), 0644)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = blobstoreClient.Put(context.Background(), source, "some-object", common.PutOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(bodies).To(HaveLen(1))
+			Expect(bodies[0]).To(BeAssignableToTypeOf(&bytes.Reader{}))
+		})
+
+		It("streams larger files from disk", func() {
+			err := os.WriteFile(source, bytes.Repeat([]byte("x"), 2*1024*1024), 0644)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = blobstoreClient.Put(context.Background(), source, "some-object", common.PutOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(bodies).To(HaveLen(1))
+			Expect(bodies[0]).To(BeAssignableToTypeOf(&io.SectionReader{}))
+		})
+	})
+
+	Describe("upload retries", func() {
+		var (
+			attempts int
+			source   string
+		)
+
+		BeforeEach(func() {
+			attempts = 0
+			source = filepath.Join(GinkgoT().TempDir(), "source")
+
+			s3Config = &config.S3Cli{
+				AccessKeyID:          "id",
+				SecretAccessKey:      "key",
+				CredentialsSource:    config.StaticCredentialsSource,
+				BucketName:           "some-bucket",
+				Region:               "us-east-1",
+				UploadRetryBackoffMs: 1,
+			}
+		})
+
+		put := func(size int) error {
+			err := os.WriteFile(source, bytes.Repeat([]byte("x"), size), 0644)
+			Expect(err).ToNot(HaveOccurred())
+
+			s3Client, err := client.NewAwsS3ClientWithApiOptions(s3Config, []func(stack *middleware.Stack) error{
+				failUploads(&attempts),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			return client.New(s3Client, s3Config).Put(context.Background(), source, "some-object", common.PutOptions{})
+		}
+
+		It("retries a failing single part upload three times by default", func() {
+			err := put(1024)
+			Expect(err).To(MatchError(ContainSubstring("single part upload retry limit exceeded")))
+			Expect(attempts).To(Equal(4))
+		})
+
+		It("retries a failing single part upload as often as configured", func() {
+			s3Config.UploadMaxRetries = 5
+
+			err := put(1024)
+			Expect(err).To(MatchError(ContainSubstring("single part upload retry limit exceeded")))
+			Expect(attempts).To(Equal(6))
+		})
+
+		It("retries a failing multipart upload as often as configured", func() {
+			s3Config.UploadMaxRetries = 1
+
+			err := put(6 * 1024 * 1024)
+			Expect(err).To(MatchError(ContainSubstring("upload retry limit exceeded")))
+			Expect(attempts).To(Equal(2))
+		})
+	})
+
+	Describe("bucket commands", func() {
+		var (
+			requests     []string
+			maxKeys      string
+			checksumMode string
+		)
+
+		BeforeEach(func() {
+			requests, maxKeys, checksumMode = nil, "", ""
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				switch {
+				case r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2":
+					maxKeys = r.URL.Query().Get("max-keys")
+					w.Write([]byte(`<ListBucketResult><Name>some-bucket</Name><IsTruncated>false</IsTruncated>` + //nolint:errcheck
+						`<Contents><Key>a</Key></Contents><Contents><Key>b</Key></Contents></ListBucketResult>`))
+				case r.Method == http.MethodHead && r.URL.Path == "/some-bucket/some-object":
+					w.Header().Set("ETag", `"some-etag-2"`)
+					w.Header().Set("Content-Length", "10")
+					w.Header().Set("X-Amz-Meta-Owner", "team-a")
+					w.Header().Set("Content-Type", "text/plain")
+					w.Header().Set("Cache-Control", "no-cache")
+					w.Header().Set("X-Amz-Storage-Class", "STANDARD_IA")
+					w.Header().Set("X-Amz-Server-Side-Encryption", "aws:kms")
+					w.Header().Set("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id", "some-key")
+				case r.Method == http.MethodHead && r.URL.Path == "/some-bucket/uploaded-object":
+					checksumMode = r.Header.Get("X-Amz-Checksum-Mode")
+					w.Header().Set("ETag", `"25f9e794323b453885f5181f1b624d0b"`)
+					w.Header().Set("Content-Length", "9")
+					w.Header().Set("X-Amz-Checksum-Crc64nvme", "rosUhgp5mIg=")
+					w.Header().Set("X-Amz-Checksum-Type", "FULL_OBJECT")
+				case r.Method == http.MethodHead && r.URL.Path == "/some-bucket/multipart-object":
+					w.Header().Set("ETag", `"d41d8cd98f00b204e9800998ecf8427e-2"`)
+					w.Header().Set("Content-Length", "9")
+					w.Header().Set("X-Amz-Checksum-Crc32", "AAAAAA==-2")
+					w.Header().Set("X-Amz-Checksum-Type", "COMPOSITE")
+				case r.Method == http.MethodHead && r.URL.Path == "/some-bucket/missing-object":
+					w.WriteHeader(http.StatusNotFound)
+				case r.Method == http.MethodHead, r.Method == http.MethodDelete:
+					w.WriteHeader(http.StatusOK)
+				case r.Method == http.MethodPost && r.URL.Query().Has("delete"):
+					w.Write([]byte(`<DeleteResult></DeleteResult>`)) //nolint:errcheck
+				default:
+					w.WriteHeader(http.StatusBadRequest)
+				}
+			}))
+			DeferCleanup(server.Close)
+
+			s3Config = newFakeS3Config(server)
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+			blobstoreClient = client.New(s3Client, s3Config)
+		})
+
+		It("lists the objects in the bucket", func() {
+			names, err := blobstoreClient.List(context.Background(), "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{"a", "b"}))
+			Expect(maxKeys).To(BeEmpty())
+		})
+
+		It("stops listing at the limit", func() {
+			names, err := blobstoreClient.ListWithLimit(context.Background(), "", 1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names).To(Equal([]string{"a"}))
+			Expect(maxKeys).To(Equal("1"))
+		})
+
+		It("fetches the properties of an object", func() {
+			properties, err := blobstoreClient.Properties(context.Background(), "some-object")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(requests).To(Equal([]string{"HEAD /some-bucket/some-object"}))
+			Expect(properties.ETag).To(Equal("some-etag-2"))
+		})
+
+		It("includes the user metadata in the properties", func() {
+			properties, err := blobstoreClient.Properties(context.Background(), "some-object")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(properties.Metadata).To(Equal(map[string]string{"owner": "team-a"}))
+		})
+
+		It("returns the full head of an object", func() {
+			head, err := blobstoreClient.Head(context.Background(), "some-object")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(head).To(Equal(common.ObjectHead{
+				ETag:          "some-etag-2",
+				ContentLength: 10,
+				ContentType:   "text/plain",
+				CacheControl:  "no-cache",
+				StorageClass:  "STANDARD_IA",
+				Metadata:      map[string]string{"owner": "team-a"},
+				Encryption:    &common.ObjectEncryption{Algorithm: "aws:kms", KeyID: "some-key"},
+			}))
+		})
+
+		It("includes the checksums of the whole object in the head", func() {
+			head, err := blobstoreClient.Head(context.Background(), "uploaded-object")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(checksumMode).To(Equal("ENABLED"))
+			Expect(head).To(Equal(common.ObjectHead{
+				ETag:          "25f9e794323b453885f5181f1b624d0b",
+				ContentLength: 9,
+				Checksums:     map[string]string{common.ChecksumMD5: "JfnnlDI7RTiF9RgfG2JNCw==", common.ChecksumCRC64NVME: "rosUhgp5mIg="},
+			}))
+		})
+
+		It("leaves the ETag and checksums of a multipart upload out of the checksums", func() {
+			head, err := blobstoreClient.Head(context.Background(), "multipart-object")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(head.Checksums).To(BeEmpty())
+		})
+
+		It("reports a missing object as not found", func() {
+			_, err := blobstoreClient.Head(context.Background(), "missing-object")
+			Expect(err).To(MatchError(common.ErrObjectNotFound))
+		})
+
+		It("keeps the quotes of the ETag when asked to", func() {
+			properties, err := blobstoreClient.PropertiesWithOptions(context.Background(), "some-object", common.PropertiesOptions{RawETag: true})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(properties.ETag).To(Equal(`"some-etag-2"`))
+		})
+
+		It("deletes the listed objects recursively", func() {
+			Expect(blobstoreClient.DeleteRecursive(context.Background(), "", common.DeleteRecursiveOptions{})).To(Succeed())
+			Expect(requests).To(Equal([]string{"GET /some-bucket", "POST /some-bucket"}))
+		})
+
+		It("checks that the bucket exists", func() {
+			Expect(blobstoreClient.EnsureStorageExists(context.Background())).To(Succeed())
+			Expect(requests).To(Equal([]string{"HEAD /some-bucket"}))
 		})
 	})
 })
@@ -4,6 +4,7 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -27,6 +28,19 @@ func (c *openstackSwiftS3Client) Sign(objectID string, action string, expiration
 	}
 }
 
+// ValidateSign checks that a temp URL for action can be signed, which needs the swift_temp_url_key
+func (c *openstackSwiftS3Client) ValidateSign(action string) error {
+	if c.s3cliConfig.SwiftTempURLKey == "" {
+		return errors.New("signing URLs for openstack swift requires the swift_temp_url_key")
+	}
+
+	action = strings.ToUpper(action)
+	if action != "GET" && action != "PUT" {
+		return fmt.Errorf("action not implemented: %s", action)
+	}
+	return nil
+}
+
 func (c *openstackSwiftS3Client) signedURL(action string, objectID string, expiration time.Duration) (string, error) {
 	path := fmt.Sprintf("/v1/%s/%s/%s", c.s3cliConfig.SwiftAuthAccount, c.s3cliConfig.BucketName, objectID)
 
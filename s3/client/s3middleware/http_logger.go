@@ -4,6 +4,8 @@ import (
 	"log/slog"
 	"net/http"
 	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
 )
 
 type roundTripperFunc func(*http.Request) (*http.Response, error)
@@ -19,6 +21,7 @@ func NewS3LoggingTransport(base http.RoundTripper) http.RoundTripper {
 		start := time.Now()
 		resp, err := base.RoundTrip(req)
 		duration := time.Since(start)
+		countRequest(req, resp)
 
 		attrs := []any{
 			"method", req.Method,
@@ -46,6 +49,16 @@ func NewS3LoggingTransport(base http.RoundTripper) http.RoundTripper {
 	})
 }
 
+// countRequest feeds the request and its payload into the --stats counters. HEAD responses
+// advertise the object size without carrying a body, so only their request is counted.
+func countRequest(req *http.Request, resp *http.Response) {
+	common.IncRequests()
+	common.AddBytesTransferred(req.ContentLength)
+	if resp != nil && req.Method != http.MethodHead {
+		common.AddBytesTransferred(resp.ContentLength)
+	}
+}
+
 func parseResponseFields(resp *http.Response) map[string]any {
 	responseFields := make(map[string]any)
 	responseFields["status_code"] = resp.StatusCode
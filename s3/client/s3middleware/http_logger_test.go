@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"strings"
 
+	"github.com/cloudfoundry/storage-cli/common"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -62,4 +63,28 @@ var _ = Describe("HttpLogger", func() {
 			Expect(logs).To(ContainSubstring("no such host"))
 		})
 	})
+
+	Context("when collecting stats,", func() {
+		BeforeEach(func() {
+			common.ResetStats()
+		})
+
+		It("counts requests and the bytes sent and received", func() {
+			mockTransport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader("")), ContentLength: 7}, nil
+			})
+			loggingTransport := NewS3LoggingTransport(mockTransport)
+
+			put := httptest.NewRequest("PUT", "http://example.com/test", strings.NewReader("some-body"))
+			_, _ = loggingTransport.RoundTrip(put) //nolint:errcheck
+			head := httptest.NewRequest("HEAD", "http://example.com/test", nil)
+			_, _ = loggingTransport.RoundTrip(head) //nolint:errcheck
+			get := httptest.NewRequest("GET", "http://example.com/test", nil)
+			_, _ = loggingTransport.RoundTrip(get) //nolint:errcheck
+
+			stats := common.GetStats()
+			Expect(stats.Requests).To(BeEquivalentTo(3))
+			Expect(stats.BytesTransferred).To(BeEquivalentTo(len("some-body") + 7 + 7))
+		})
+	})
 })
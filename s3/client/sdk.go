@@ -2,8 +2,11 @@ package client
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -11,6 +14,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
+	smithyendpoints "github.com/aws/smithy-go/endpoints"
 	"github.com/aws/smithy-go/middleware"
 	boshhttp "github.com/cloudfoundry/bosh-utils/httpclient"
 	"github.com/cloudfoundry/storage-cli/common"
@@ -41,8 +45,13 @@ func NewAwsS3ClientWithApiOptions(
 	} else {
 		httpClient = boshhttp.CreateDefaultClientInsecureSkipVerify()
 	}
+	proxy, err := common.ProxyFunc(c.ProxyURL)
+	if err != nil {
+		return nil, err
+	}
+	httpClient.Transport.(*http.Transport).Proxy = proxy
 
-	if common.IsDebug() {
+	if common.IsDebug() || common.IsStatsEnabled() {
 		httpClient.Transport = s3middleware.NewS3LoggingTransport(httpClient.Transport)
 	}
 
@@ -67,10 +76,20 @@ func NewAwsS3ClientWithApiOptions(
 		return nil, err
 	}
 
-	if c.AssumeRoleArn != "" {
+	if c.CredentialsSource == s3cli_config.WebIdentityCredentialsSource {
 		stsClient := sts.NewFromConfig(awsConfig)
-		provider := stscreds.NewAssumeRoleProvider(stsClient, c.AssumeRoleArn)
+		provider := stscreds.NewWebIdentityRoleProvider(stsClient, c.AssumeRoleArn, stscreds.IdentityTokenFile(c.WebIdentityTokenFile), func(o *stscreds.WebIdentityRoleOptions) {
+			o.RoleSessionName = c.AssumeRoleSessionName
+			o.Duration = time.Duration(c.AssumeRoleDurationSeconds) * time.Second
+		})
 		awsConfig.Credentials = aws.NewCredentialsCache(provider)
+	} else if c.AssumeRoleArn != "" {
+		awsConfig.Credentials = assumeRole(awsConfig, c, c.AssumeRoleArn)
+	}
+
+	// Role chaining: every further role is assumed with the credentials of the one before
+	for _, roleArn := range c.AssumeRoleChain {
+		awsConfig.Credentials = assumeRole(awsConfig, c, roleArn)
 	}
 
 	if c.ShouldDisableRequestChecksumCalculation() {
@@ -82,7 +101,7 @@ func NewAwsS3ClientWithApiOptions(
 	}
 
 	s3Client := s3.NewFromConfig(awsConfig, func(o *s3.Options) {
-		o.UsePathStyle = !c.HostStyle
+		o.UsePathStyle = c.UsePathStyle()
 		if endpoint := c.S3Endpoint(); endpoint != "" {
 			// AWS SDK v2 requires full URI with protocol
 			if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
@@ -93,6 +112,9 @@ func NewAwsS3ClientWithApiOptions(
 				}
 			}
 			o.BaseEndpoint = aws.String(endpoint)
+			if c.BucketInHost {
+				o.EndpointResolverV2 = bucketInHostEndpointResolver{endpoint: endpoint}
+			}
 		}
 		// Apply custom middlewares if provided
 		o.APIOptions = append(o.APIOptions, apiOptions...)
@@ -100,3 +122,31 @@ func NewAwsS3ClientWithApiOptions(
 
 	return s3Client, nil
 }
+
+// assumeRole returns a provider for the credentials of roleArn, assumed with the credentials of awsConfig
+func assumeRole(awsConfig aws.Config, c *s3cli_config.S3Cli, roleArn string) aws.CredentialsProvider {
+	stsClient := sts.NewFromConfig(awsConfig)
+	provider := stscreds.NewAssumeRoleProvider(stsClient, roleArn, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = c.AssumeRoleSessionName
+		o.Duration = time.Duration(c.AssumeRoleDurationSeconds) * time.Second
+		if c.AssumeRoleExternalID != "" {
+			o.ExternalID = aws.String(c.AssumeRoleExternalID)
+		}
+	})
+	return aws.NewCredentialsCache(provider)
+}
+
+// bucketInHostEndpointResolver resolves every request to the configured endpoint as-is.
+// It is used when the endpoint is a custom domain (CNAME) which already maps to the bucket,
+// so the SDK must neither prefix the host with the bucket nor add it to the path.
+type bucketInHostEndpointResolver struct {
+	endpoint string
+}
+
+func (r bucketInHostEndpointResolver) ResolveEndpoint(_ context.Context, _ s3.EndpointParameters) (smithyendpoints.Endpoint, error) {
+	u, err := url.Parse(r.endpoint)
+	if err != nil {
+		return smithyendpoints.Endpoint{}, fmt.Errorf("parsing endpoint %s: %w", r.endpoint, err)
+	}
+	return smithyendpoints.Endpoint{URI: *u}, nil
+}
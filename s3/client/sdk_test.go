@@ -0,0 +1,424 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+
+	"github.com/cloudfoundry/storage-cli/common"
+	"github.com/cloudfoundry/storage-cli/s3/client"
+	"github.com/cloudfoundry/storage-cli/s3/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var errRequestCaptured = errors.New("request captured")
+
+// captureRequestURL records the URL of the signed request and aborts it before it hits the network
+func captureRequestURL(captured **url.URL) func(stack *middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		return stack.Finalize.Add(middleware.FinalizeMiddlewareFunc("CaptureRequestURL",
+			func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+				if req, ok := in.Request.(*smithyhttp.Request); ok {
+					*captured = req.URL
+				}
+				return middleware.FinalizeOutput{}, middleware.Metadata{}, errRequestCaptured
+			},
+		), middleware.After)
+	}
+}
+
+// captureOperation records the name of the invoked operation and aborts it before anything is sent
+func captureOperation(operations *[]string) func(stack *middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("CaptureOperation",
+			func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				*operations = append(*operations, middleware.GetOperationName(ctx))
+				return middleware.InitializeOutput{}, middleware.Metadata{}, errRequestCaptured
+			},
+		), middleware.Before)
+	}
+}
+
+// capturePutObjectBody records the body of every PutObject call as handed to the SDK
+func capturePutObjectBody(bodies *[]io.Reader) func(stack *middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("CapturePutObjectBody",
+			func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				if input, ok := in.Parameters.(*s3.PutObjectInput); ok {
+					*bodies = append(*bodies, input.Body)
+				}
+				return next.HandleInitialize(ctx, in)
+			},
+		), middleware.Before)
+	}
+}
+
+// stubResponses records every signed request and answers it with an empty successful response
+// instead of sending it over the network
+func stubResponses(requests *[]*http.Request) func(stack *middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		return stack.Deserialize.Add(middleware.DeserializeMiddlewareFunc("StubResponses",
+			func(ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler) (middleware.DeserializeOutput, middleware.Metadata, error) {
+				req := in.Request.(*smithyhttp.Request).Build(ctx)
+				*requests = append(*requests, req)
+
+				resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(""))}
+				switch req.Method {
+				case http.MethodHead:
+					resp.Header.Set("Content-Length", "10")
+				case http.MethodPut:
+					resp.Body = io.NopCloser(strings.NewReader("<CopyObjectResult></CopyObjectResult>"))
+				}
+				return middleware.DeserializeOutput{RawResponse: &smithyhttp.Response{Response: resp}}, middleware.Metadata{}, nil
+			},
+		), middleware.After)
+	}
+}
+
+// failUploads fails every PutObject and UploadPart call without sending anything and counts the
+// upload attempts, i.e. the PutObject and CreateMultipartUpload calls
+func failUploads(attempts *int) func(stack *middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("FailUploads",
+			func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				switch middleware.GetOperationName(ctx) {
+				case "PutObject":
+					*attempts++
+					return middleware.InitializeOutput{}, middleware.Metadata{}, errors.New("upload failed")
+				case "CreateMultipartUpload":
+					*attempts++
+					return middleware.InitializeOutput{Result: &s3.CreateMultipartUploadOutput{UploadId: aws.String("some-upload-id")}}, middleware.Metadata{}, nil
+				case "UploadPart":
+					return middleware.InitializeOutput{}, middleware.Metadata{}, errors.New("upload failed")
+				case "AbortMultipartUpload":
+					return middleware.InitializeOutput{Result: &s3.AbortMultipartUploadOutput{}}, middleware.Metadata{}, nil
+				}
+				return next.HandleInitialize(ctx, in)
+			},
+		), middleware.Before)
+	}
+}
+
+var _ = Describe("NewAwsS3Client", func() {
+	Describe("addressing", func() {
+		var s3Config *config.S3Cli
+
+		BeforeEach(func() {
+			s3Config = &config.S3Cli{
+				AccessKeyID:       "id",
+				SecretAccessKey:   "key",
+				CredentialsSource: config.StaticCredentialsSource,
+				BucketName:        "some-bucket",
+				Host:              "cdn.example.com",
+				Region:            "us-east-1",
+				UseSSL:            true,
+				SSLVerifyPeer:     true,
+			}
+		})
+
+		DescribeTable("builds the request host and path",
+			func(hostStyle bool, addressingStyle string, bucketInHost bool, expectedHost string, expectedPath string) {
+				s3Config.HostStyle = hostStyle
+				s3Config.AddressingStyle = addressingStyle
+				s3Config.BucketInHost = bucketInHost
+
+				var captured *url.URL
+				s3Client, err := client.NewAwsS3ClientWithApiOptions(s3Config, []func(stack *middleware.Stack) error{captureRequestURL(&captured)})
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = s3Client.HeadObject(context.Background(), &s3.HeadObjectInput{
+					Bucket: aws.String(s3Config.BucketName),
+					Key:    aws.String("some/key"),
+				})
+				Expect(err).To(MatchError(errRequestCaptured))
+
+				Expect(captured).ToNot(BeNil())
+				Expect(captured.Host).To(Equal(expectedHost))
+				Expect(captured.Path).To(Equal(expectedPath))
+			},
+			Entry("default is path style", false, "", false, "cdn.example.com", "/some-bucket/some/key"),
+			Entry("host_style uses virtual host", true, "", false, "some-bucket.cdn.example.com", "/some/key"),
+			Entry("explicit path style overrides host_style", true, config.PathAddressingStyle, false, "cdn.example.com", "/some-bucket/some/key"),
+			Entry("explicit virtual style", false, config.VirtualAddressingStyle, false, "some-bucket.cdn.example.com", "/some/key"),
+			Entry("bucket_in_host uses the endpoint as-is", false, "", true, "cdn.example.com", "/some/key"),
+			Entry("bucket_in_host ignores host_style", true, "", true, "cdn.example.com", "/some/key"),
+			Entry("bucket_in_host with virtual style", false, config.VirtualAddressingStyle, true, "cdn.example.com", "/some/key"),
+		)
+
+		It("signs URLs against the custom domain when bucket_in_host is set", func() {
+			s3Config.BucketInHost = true
+
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			url, err := client.New(s3Client, s3Config).Sign(context.Background(), "some/key", "get", time.Minute)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(url).To(HavePrefix("https://cdn.example.com/some/key?"))
+		})
+	})
+
+	Describe("credentials", func() {
+		It("assumes the role with the web identity token for the web_identity credentials source", func() {
+			s3Config := &config.S3Cli{
+				CredentialsSource:    config.WebIdentityCredentialsSource,
+				WebIdentityTokenFile: "/var/run/secrets/token",
+				AssumeRoleArn:        "arn:aws:iam::123456789012:role/some-role",
+				BucketName:           "some-bucket",
+				Region:               "us-east-1",
+			}
+
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			credentials := s3Client.Options().Credentials
+			Expect(aws.IsCredentialsProvider(credentials, (*stscreds.WebIdentityRoleProvider)(nil))).To(BeTrue())
+		})
+
+		It("assumes the role with the resolved credentials otherwise", func() {
+			s3Config := &config.S3Cli{
+				AccessKeyID:       "id",
+				SecretAccessKey:   "key",
+				CredentialsSource: config.StaticCredentialsSource,
+				AssumeRoleArn:     "arn:aws:iam::123456789012:role/some-role",
+				BucketName:        "some-bucket",
+				Region:            "us-east-1",
+			}
+
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			credentials := s3Client.Options().Credentials
+			Expect(aws.IsCredentialsProvider(credentials, (*stscreds.AssumeRoleProvider)(nil))).To(BeTrue())
+			Expect(aws.IsCredentialsProvider(credentials, (*stscreds.WebIdentityRoleProvider)(nil))).To(BeFalse())
+		})
+
+		Context("when assuming a role", func() {
+			var (
+				assumeRoleForm url.Values
+				assumedRoles   []string
+				signingKeys    []string
+				s3Config       *config.S3Cli
+			)
+
+			BeforeEach(func() {
+				assumeRoleForm = nil
+				assumedRoles = nil
+				signingKeys = nil
+				// Answers with credentials named after the assumed role and records which credentials signed the request
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					Expect(r.ParseForm()).To(Succeed())
+					assumeRoleForm = r.PostForm
+
+					role := r.PostForm.Get("RoleArn")
+					role = role[strings.LastIndex(role, "/")+1:]
+					assumedRoles = append(assumedRoles, role)
+					credential := strings.SplitN(r.Header.Get("Authorization"), "Credential=", 2)[1]
+					signingKeys = append(signingKeys, credential[:strings.Index(credential, "/")])
+
+					fmt.Fprintf(w, `<AssumeRoleResponse><AssumeRoleResult><Credentials>`+
+						`<AccessKeyId>assumed-%s</AccessKeyId><SecretAccessKey>assumed-key</SecretAccessKey>`+
+						`<SessionToken>token</SessionToken><Expiration>2099-01-01T00:00:00Z</Expiration>`+
+						`</Credentials></AssumeRoleResult></AssumeRoleResponse>`, role)
+				}))
+				DeferCleanup(server.Close)
+				GinkgoT().Setenv("AWS_ENDPOINT_URL_STS", server.URL)
+
+				s3Config = &config.S3Cli{
+					AccessKeyID:       "id",
+					SecretAccessKey:   "key",
+					CredentialsSource: config.StaticCredentialsSource,
+					AssumeRoleArn:     "arn:aws:iam::123456789012:role/some-role",
+					BucketName:        "some-bucket",
+					Region:            "us-east-1",
+				}
+			})
+
+			It("passes the session name, external id and duration to STS", func() {
+				s3Config.AssumeRoleSessionName = "some-session"
+				s3Config.AssumeRoleExternalID = "some-external-id"
+				s3Config.AssumeRoleDurationSeconds = 3600
+
+				s3Client, err := client.NewAwsS3Client(s3Config)
+				Expect(err).ToNot(HaveOccurred())
+
+				credentials, err := s3Client.Options().Credentials.Retrieve(context.Background())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(credentials.AccessKeyID).To(Equal("assumed-some-role"))
+
+				Expect(assumeRoleForm.Get("Action")).To(Equal("AssumeRole"))
+				Expect(assumeRoleForm.Get("RoleArn")).To(Equal("arn:aws:iam::123456789012:role/some-role"))
+				Expect(assumeRoleForm.Get("RoleSessionName")).To(Equal("some-session"))
+				Expect(assumeRoleForm.Get("ExternalId")).To(Equal("some-external-id"))
+				Expect(assumeRoleForm.Get("DurationSeconds")).To(Equal("3600"))
+			})
+
+			It("leaves them to the SDK defaults when unset", func() {
+				s3Client, err := client.NewAwsS3Client(s3Config)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = s3Client.Options().Credentials.Retrieve(context.Background())
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(assumeRoleForm.Get("RoleSessionName")).To(HavePrefix("aws-go-sdk-"))
+				Expect(assumeRoleForm.Has("ExternalId")).To(BeFalse())
+				Expect(assumeRoleForm.Get("DurationSeconds")).To(Equal("900"))
+			})
+
+			It("assumes the chained roles in order, each with the credentials of the previous one", func() {
+				s3Config.AssumeRoleChain = []string{
+					"arn:aws:iam::210987654321:role/second-role",
+					"arn:aws:iam::210987654321:role/third-role",
+				}
+
+				s3Client, err := client.NewAwsS3Client(s3Config)
+				Expect(err).ToNot(HaveOccurred())
+
+				credentials, err := s3Client.Options().Credentials.Retrieve(context.Background())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(credentials.AccessKeyID).To(Equal("assumed-third-role"))
+
+				Expect(assumedRoles).To(Equal([]string{"some-role", "second-role", "third-role"}))
+				Expect(signingKeys).To(Equal([]string{"id", "assumed-some-role", "assumed-second-role"}))
+			})
+		})
+	})
+
+	Describe("request logging", func() {
+		var (
+			server   *httptest.Server
+			s3Config *config.S3Cli
+			logs     *bytes.Buffer
+		)
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("x-amz-request-id", "some-request-id")
+				w.WriteHeader(http.StatusOK)
+			}))
+			DeferCleanup(server.Close)
+			s3Config = newFakeS3Config(server)
+
+			logs = &bytes.Buffer{}
+			defaultLogger := slog.Default()
+			DeferCleanup(func() {
+				slog.SetDefault(defaultLogger)
+				common.ResetConfig()
+			})
+		})
+
+		headObject := func() {
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = s3Client.HeadObject(context.Background(), &s3.HeadObjectInput{
+				Bucket: aws.String("some-bucket"),
+				Key:    aws.String("some-object"),
+			})
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		It("logs every request through the logging transport at debug level", func() {
+			common.InitConfig(slog.LevelDebug, logs)
+
+			headObject()
+
+			Expect(logs.String()).To(ContainSubstring(`"msg":"s3 http request"`))
+			Expect(logs.String()).To(ContainSubstring(`"method":"HEAD"`))
+			Expect(logs.String()).To(ContainSubstring(`"status_code":200`))
+			Expect(logs.String()).To(ContainSubstring(`"request_id":"some-request-id"`))
+			Expect(logs.String()).To(ContainSubstring(`"duration_ms":`))
+		})
+
+		It("does not install the logging transport below debug level", func() {
+			common.InitConfig(slog.LevelInfo, logs)
+
+			headObject()
+
+			Expect(logs.String()).ToNot(ContainSubstring("s3 http request"))
+		})
+	})
+
+	Describe("proxy", func() {
+		var (
+			proxied  []string
+			s3Config *config.S3Cli
+		)
+
+		BeforeEach(func() {
+			proxied = nil
+			proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// A proxied request names the host it is meant for in an absolute URL
+				proxied = append(proxied, r.Method+" "+r.URL.String())
+				w.Header().Set("Content-Length", "10")
+				w.WriteHeader(http.StatusOK)
+			}))
+			DeferCleanup(proxy.Close)
+
+			s3Config = &config.S3Cli{
+				AccessKeyID:       "id",
+				SecretAccessKey:   "key",
+				CredentialsSource: config.StaticCredentialsSource,
+				BucketName:        "some-bucket",
+				Host:              "s3.invalid",
+				Region:            "us-east-1",
+				AddressingStyle:   config.PathAddressingStyle,
+				SSLVerifyPeer:     true,
+				ProxyURL:          proxy.URL,
+			}
+
+			DeferCleanup(common.ResetConfig)
+		})
+
+		headObject := func() {
+			s3Client, err := client.NewAwsS3Client(s3Config)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = s3Client.HeadObject(context.Background(), &s3.HeadObjectInput{
+				Bucket: aws.String("some-bucket"),
+				Key:    aws.String("some-object"),
+			})
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		It("sends the requests through the proxy_url", func() {
+			headObject()
+
+			Expect(proxied).To(Equal([]string{"HEAD http://s3.invalid/some-bucket/some-object"}))
+		})
+
+		It("sends the requests through the proxy_url with the logging transport installed", func() {
+			logs := &bytes.Buffer{}
+			defaultLogger := slog.Default()
+			DeferCleanup(slog.SetDefault, defaultLogger)
+			common.InitConfig(slog.LevelDebug, logs)
+
+			headObject()
+
+			Expect(proxied).To(HaveLen(1))
+			Expect(logs.String()).To(ContainSubstring(`"msg":"s3 http request"`))
+		})
+
+		It("rejects an unsupported proxy_url", func() {
+			s3Config.ProxyURL = "ftp://proxy.invalid"
+
+			_, err := client.NewAwsS3Client(s3Config)
+			Expect(err).To(MatchError("invalid proxy_url 'ftp://proxy.invalid': scheme must be http, https, socks5 or socks5h"))
+		})
+	})
+})
@@ -6,15 +6,20 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"slices"
 	"strings"
+
+	"github.com/cloudfoundry/storage-cli/common"
 )
 
 // The S3Cli represents configuration for the s3cli
 type S3Cli struct {
 	AccessKeyID                               string `json:"access_key_id"`
 	SecretAccessKey                           string `json:"secret_access_key"`
-	BucketName                                string `json:"bucket_name"`
+	BucketName                                string `json:"bucket_name" required:"true"`
 	FolderName                                string `json:"folder_name"`
+	KeySeparator                              string `json:"key_separator"`         // Default: "/" - placed between folder_name and the object key
+	DisableKeySeparator                       bool   `json:"disable_key_separator"` // Prepend folder_name to the object key as-is
 	CredentialsSource                         string `json:"credentials_source"`
 	Host                                      string `json:"host"`
 	Port                                      int    `json:"port"` // 0 means no custom port
@@ -24,12 +29,21 @@ type S3Cli struct {
 	ServerSideEncryption                      string `json:"server_side_encryption"`
 	SSEKMSKeyID                               string `json:"sse_kms_key_id"`
 	AssumeRoleArn                             string `json:"assume_role_arn"`
+	AssumeRoleSessionName                     string `json:"assume_role_session_name"`
+	AssumeRoleExternalID                      string `json:"assume_role_external_id"`
+	AssumeRoleDurationSeconds                 int    `json:"assume_role_duration_seconds"` // 0 means the STS default of 15 minutes
+	WebIdentityTokenFile                      string `json:"web_identity_token_file"`
 	HostStyle                                 bool   `json:"host_style"`
+	AddressingStyle                           string `json:"addressing_style"`
+	BucketInHost                              bool   `json:"bucket_in_host"`
 	SwiftAuthAccount                          string `json:"swift_auth_account"`
 	SwiftTempURLKey                           string `json:"swift_temp_url_key"`
 	RequestChecksumCalculationEnabled         bool   `json:"request_checksum_calculation_enabled"`
 	ResponseChecksumCalculationEnabled        bool   `json:"response_checksum_calculation_enabled"`
 	UploaderRequestChecksumCalculationEnabled bool   `json:"uploader_request_checksum_calculation_enabled"`
+	// Proxy all requests go through, e.g. http://proxy:3128 or socks5://proxy:1080.
+	// If empty, HTTP_PROXY, HTTPS_PROXY and NO_PROXY select the proxy.
+	ProxyURL string `json:"proxy_url"`
 	// Optional knobs to tune transfer performance.
 	// If zero, the client will apply sensible defaults (handled by the S3 client layer).
 	// Part size values are provided in bytes.
@@ -37,16 +51,27 @@ type S3Cli struct {
 	DownloadPartSize       int64 `json:"download_part_size"`
 	UploadConcurrency      int   `json:"upload_concurrency"`
 	UploadPartSize         int64 `json:"upload_part_size"`
-	MultipartCopyThreshold int64 `json:"multipart_copy_threshold"` // Default: 5GB - files larger than this use multipart copy
+	MultipartCopyThreshold int64 `json:"multipart_copy_threshold"` // Default: 5GB - files of this size or larger use multipart copy
 	MultipartCopyPartSize  int64 `json:"multipart_copy_part_size"` // Default: 100MB - size of each part in multipart copy
+	NoMultipartCopy        bool  `json:"no_multipart_copy"`        // Copy with a single CopyObject whatever the size, for providers that mishandle UploadPartCopy
+	UploadMaxRetries       int   `json:"upload_max_retries"`       // Default: 3 - how often a failed upload is retried
+	UploadRetryBackoffMs   int   `json:"upload_retry_backoff_ms"`  // Default: 1000 - delay before the first retry, doubled for every further one
 
 	// Files smaller than or equal to this size (in bytes) are uploaded using a single PutObject call.
 	// Files exceeding this size use multipart upload. Omit or set to 0 to always use multipart upload.
 	// Must not exceed 5GB (AWS S3 hard limit for PutObject, https://docs.aws.amazon.com/AmazonS3/latest/userguide/upload-objects.html).
 	// For GCS, leave this unset (0); it will be automatically set to math.MaxInt64 since GCS requires single put for all uploads but has no size limit.
 	SingleUploadThreshold int64 `json:"single_upload_threshold"`
+
+	// Roles assumed one after the other once assume_role_arn has been assumed, each with the credentials of the previous one.
+	AssumeRoleChain []string `json:"assume_role_chain"`
+
+	// Set to "requester" to read from and copy within requester pays buckets, the requester is charged for the requests and the transfer.
+	RequestPayer string `json:"request_payer"`
 }
 
+const defaultKeySeparator = "/"
+
 const (
 	// multipartCopyMinPartSize is the AWS minimum part size for multipart operations.
 	// Other providers may have different limits - users should consult their provider's documentation.
@@ -54,6 +79,12 @@ const (
 
 	// singlePutMaxSize is the AWS S3 hard limit for a single PutObject call.
 	singlePutMaxSize = int64(5 * 1024 * 1024 * 1024) // 5GB
+
+	// maxAssumeRoleChainLength limits how many roles can be chained after assume_role_arn.
+	maxAssumeRoleChainLength = 5
+
+	// chainedRoleMaxDurationSeconds is the AWS limit for sessions of chained roles.
+	chainedRoleMaxDurationSeconds = 3600
 )
 
 const defaultAWSRegion = "us-east-1" //nolint:unused
@@ -66,11 +97,26 @@ const NoneCredentialsSource = "none"
 
 const credentialsSourceEnvOrProfile = "env_or_profile"
 
+// WebIdentityCredentialsSource specifies that assume_role_arn is assumed with the OIDC token in
+// web_identity_token_file, e.g. the one EKS mounts for IAM roles for service accounts (IRSA)
+const WebIdentityCredentialsSource = "web_identity"
+
 // Nothing was provided in configuration
 const noCredentialsSourceProvided = ""
 
+// RequesterRequestPayer makes the requester pay for the requests to a requester pays bucket
+const RequesterRequestPayer = "requester"
+
+// PathAddressingStyle places the bucket name in the request path, e.g. https://host/bucket/key
+const PathAddressingStyle = "path"
+
+// VirtualAddressingStyle places the bucket name in the request host, e.g. https://bucket.host/key
+const VirtualAddressingStyle = "virtual"
+
 var errorStaticCredentialsMissing = errors.New("access_key_id and secret_access_key must be provided")
 
+var errorWebIdentityMissing = errors.New("web_identity_token_file and assume_role_arn must be provided")
+
 type errorStaticCredentialsPresent struct {
 	credentialsSource string
 }
@@ -109,6 +155,10 @@ func NewFromReader(reader io.Reader) (S3Cli, error) {
 		return S3Cli{}, errors.New("bucket_name must be set")
 	}
 
+	if _, err := common.ParseProxyURL(c.ProxyURL); err != nil {
+		return S3Cli{}, err
+	}
+
 	// Validate single put threshold
 	if c.SingleUploadThreshold < 0 {
 		return S3Cli{}, errors.New("single_upload_threshold must not be negative")
@@ -119,6 +169,10 @@ func NewFromReader(reader io.Reader) (S3Cli, error) {
 		return S3Cli{}, errors.New("download/upload concurrency and part sizes must be non-negative")
 	}
 
+	if c.UploadMaxRetries < 0 || c.UploadRetryBackoffMs < 0 {
+		return S3Cli{}, errors.New("upload_max_retries and upload_retry_backoff_ms must be non-negative (0 means use default)")
+	}
+
 	// Validate multipart copy settings (0 means "use defaults")
 	// Note: Default threshold is 5GB (AWS limit), but users can configure higher values for providers
 	// that support larger simple copies (e.g., GCS has no limit). Users should consult their provider's documentation.
@@ -132,6 +186,44 @@ func NewFromReader(reader io.Reader) (S3Cli, error) {
 		return S3Cli{}, fmt.Errorf("multipart_copy_part_size must be at least %d bytes (5MB - AWS minimum)", multipartCopyMinPartSize)
 	}
 
+	if c.AssumeRoleDurationSeconds < 0 {
+		return S3Cli{}, errors.New("assume_role_duration_seconds must not be negative")
+	}
+	if c.AssumeRoleArn == "" && (c.AssumeRoleSessionName != "" || c.AssumeRoleExternalID != "" || c.AssumeRoleDurationSeconds != 0) {
+		return S3Cli{}, errors.New("assume_role_session_name, assume_role_external_id and assume_role_duration_seconds require assume_role_arn")
+	}
+	if len(c.AssumeRoleChain) > 0 {
+		if c.AssumeRoleArn == "" {
+			return S3Cli{}, errors.New("assume_role_chain requires assume_role_arn")
+		}
+		if len(c.AssumeRoleChain) > maxAssumeRoleChainLength {
+			return S3Cli{}, fmt.Errorf("assume_role_chain must not contain more than %d roles", maxAssumeRoleChainLength)
+		}
+		if slices.Contains(c.AssumeRoleChain, "") {
+			return S3Cli{}, errors.New("assume_role_chain must not contain empty role ARNs")
+		}
+		if c.AssumeRoleDurationSeconds > chainedRoleMaxDurationSeconds {
+			return S3Cli{}, fmt.Errorf("assume_role_duration_seconds must not exceed %d with assume_role_chain", chainedRoleMaxDurationSeconds)
+		}
+	}
+
+	switch c.AddressingStyle {
+	case "", PathAddressingStyle, VirtualAddressingStyle:
+	default:
+		return S3Cli{}, fmt.Errorf("invalid addressing_style: %s (expected '%s' or '%s')", c.AddressingStyle, PathAddressingStyle, VirtualAddressingStyle)
+	}
+
+	// With bucket_in_host the endpoint itself (e.g. a CNAME like cdn.example.com) already
+	// identifies the bucket, so neither the host nor the path may carry the bucket name.
+	if c.BucketInHost {
+		if c.Host == "" {
+			return S3Cli{}, errors.New("bucket_in_host requires host to be set")
+		}
+		if c.AddressingStyle == PathAddressingStyle {
+			return S3Cli{}, errors.New("bucket_in_host can't be combined with 'path' addressing_style")
+		}
+	}
+
 	switch c.CredentialsSource {
 	case StaticCredentialsSource:
 		if c.AccessKeyID == "" || c.SecretAccessKey == "" {
@@ -145,10 +237,22 @@ func NewFromReader(reader io.Reader) (S3Cli, error) {
 		if c.AccessKeyID != "" || c.SecretAccessKey != "" {
 			return S3Cli{}, newStaticCredentialsPresentError(NoneCredentialsSource)
 		}
+	case WebIdentityCredentialsSource:
+		if c.AccessKeyID != "" || c.SecretAccessKey != "" {
+			return S3Cli{}, newStaticCredentialsPresentError(WebIdentityCredentialsSource)
+		}
+		if c.WebIdentityTokenFile == "" || c.AssumeRoleArn == "" {
+			return S3Cli{}, errorWebIdentityMissing
+		}
 
 	case noCredentialsSourceProvided:
 		if c.SecretAccessKey != "" && c.AccessKeyID != "" {
 			c.CredentialsSource = StaticCredentialsSource
+		} else if c.SecretAccessKey == "" && c.AccessKeyID == "" && c.WebIdentityTokenFile != "" {
+			if c.AssumeRoleArn == "" {
+				return S3Cli{}, errorWebIdentityMissing
+			}
+			c.CredentialsSource = WebIdentityCredentialsSource
 		} else if c.SecretAccessKey == "" && c.AccessKeyID == "" {
 			c.CredentialsSource = NoneCredentialsSource
 		} else {
@@ -158,6 +262,16 @@ func NewFromReader(reader io.Reader) (S3Cli, error) {
 		return S3Cli{}, fmt.Errorf("invalid credentials_source: %s", c.CredentialsSource)
 	}
 
+	switch c.RequestPayer {
+	case "":
+	case RequesterRequestPayer:
+		if c.CredentialsSource == NoneCredentialsSource {
+			return S3Cli{}, errors.New("request_payer requires credentials, anonymous requests can't be charged to the requester")
+		}
+	default:
+		return S3Cli{}, fmt.Errorf("invalid request_payer: %s (expected '%s')", c.RequestPayer, RequesterRequestPayer)
+	}
+
 	switch Provider(c.Host) {
 	case "aws":
 		c.configureAWS()
@@ -247,6 +361,46 @@ func (c *S3Cli) S3Endpoint() string {
 	return c.Host
 }
 
+// ObjectKey returns the key under which the given object is stored. With folder_name set, every key
+// is stored below the folder, whatever it starts with: folder_name and the separator, which isn't
+// doubled if folder_name already ends with it, are put in front of it. Listed keys are relative to
+// the folder, see RelativeKey, so that they can be passed to the other operations as they are.
+func (c *S3Cli) ObjectKey(key string) string {
+	return c.folderPrefix() + key
+}
+
+// RelativeKey returns the key of a listed object as the other operations take it, without the
+// folder_name ObjectKey puts in front of it
+func (c *S3Cli) RelativeKey(objectKey string) string {
+	return strings.TrimPrefix(objectKey, c.folderPrefix())
+}
+
+// folderPrefix returns what ObjectKey puts in front of keys: folder_name followed by the separator
+func (c *S3Cli) folderPrefix() string {
+	if c.FolderName == "" || c.DisableKeySeparator {
+		return c.FolderName
+	}
+
+	separator := c.KeySeparator
+	if separator == "" {
+		separator = defaultKeySeparator
+	}
+	return strings.TrimSuffix(c.FolderName, separator) + separator
+}
+
+// UsePathStyle reports whether the bucket name is sent as part of the request path.
+// An explicit addressing_style takes precedence over host_style.
+func (c *S3Cli) UsePathStyle() bool {
+	switch c.AddressingStyle {
+	case PathAddressingStyle:
+		return true
+	case VirtualAddressingStyle:
+		return false
+	default:
+		return !c.HostStyle
+	}
+}
+
 func (c *S3Cli) IsGoogle() bool {
 	return Provider(c.Host) == "google"
 }
@@ -160,6 +160,55 @@ var _ = Describe("BlobstoreClient configuration", func() {
 			})
 		})
 
+		Describe("building object keys", func() {
+			It("returns the key unchanged when no folder is set", func() {
+				c := config.S3Cli{}
+				Expect(c.ObjectKey("some-key")).To(Equal("some-key"))
+			})
+
+			It("joins folder and key with a slash by default", func() {
+				c := config.S3Cli{FolderName: "some-folder"}
+				Expect(c.ObjectKey("some-key")).To(Equal("some-folder/some-key"))
+			})
+
+			It("does not double the separator when the folder already ends with it", func() {
+				c := config.S3Cli{FolderName: "some-folder/"}
+				Expect(c.ObjectKey("some-key")).To(Equal("some-folder/some-key"))
+			})
+
+			It("puts the folder in front of a key that already starts with it", func() {
+				c := config.S3Cli{FolderName: "some-folder"}
+				Expect(c.ObjectKey("some-folder/some-key")).To(Equal("some-folder/some-folder/some-key"))
+			})
+
+			It("puts the folder in front of a key that only starts with the folder name", func() {
+				c := config.S3Cli{FolderName: "some-folder"}
+				Expect(c.ObjectKey("some-folder-backup/some-key")).To(Equal("some-folder/some-folder-backup/some-key"))
+			})
+
+			It("uses the configured key_separator", func() {
+				c, err := config.NewFromReader(bytes.NewReader([]byte(`{
+					"bucket_name": "some-bucket",
+					"credentials_source": "none",
+					"folder_name": "some-folder",
+					"key_separator": ":"
+				}`)))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(c.ObjectKey("some-key")).To(Equal("some-folder:some-key"))
+			})
+
+			It("prepends the folder as-is when disable_key_separator is set", func() {
+				c, err := config.NewFromReader(bytes.NewReader([]byte(`{
+					"bucket_name": "some-bucket",
+					"credentials_source": "none",
+					"folder_name": "backup-",
+					"disable_key_separator": true
+				}`)))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(c.ObjectKey("some-key")).To(Equal("backup-some-key"))
+			})
+		})
+
 		Describe("Default SSL options", func() {
 			emptyJSONBytes := []byte(`{"access_key_id": "id", "secret_access_key": "key", "bucket_name": "some-bucket"}`)
 			emptyJSONReader := bytes.NewReader(emptyJSONBytes)
@@ -230,6 +279,61 @@ var _ = Describe("BlobstoreClient configuration", func() {
 			})
 		})
 
+		Describe("configuring the addressing style", func() {
+			It("derives path style from host_style when addressing_style is not set", func() {
+				configBytes := []byte(`{"access_key_id": "id", "secret_access_key": "key", "bucket_name": "some-bucket", "host": "cdn.example.com", "host_style": true}`)
+
+				c, err := config.NewFromReader(bytes.NewReader(configBytes))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(c.UsePathStyle()).To(BeFalse())
+			})
+
+			It("lets an explicit addressing_style take precedence over host_style", func() {
+				configBytes := []byte(`{"access_key_id": "id", "secret_access_key": "key", "bucket_name": "some-bucket", "host": "cdn.example.com", "host_style": true, "addressing_style": "path"}`)
+
+				c, err := config.NewFromReader(bytes.NewReader(configBytes))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(c.UsePathStyle()).To(BeTrue())
+			})
+
+			It("uses virtual host style when requested", func() {
+				configBytes := []byte(`{"access_key_id": "id", "secret_access_key": "key", "bucket_name": "some-bucket", "host": "cdn.example.com", "addressing_style": "virtual"}`)
+
+				c, err := config.NewFromReader(bytes.NewReader(configBytes))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(c.UsePathStyle()).To(BeFalse())
+			})
+
+			It("rejects an unknown addressing_style", func() {
+				configBytes := []byte(`{"access_key_id": "id", "secret_access_key": "key", "bucket_name": "some-bucket", "addressing_style": "dns"}`)
+
+				_, err := config.NewFromReader(bytes.NewReader(configBytes))
+				Expect(err).To(MatchError("invalid addressing_style: dns (expected 'path' or 'virtual')"))
+			})
+
+			It("accepts bucket_in_host with a custom host", func() {
+				configBytes := []byte(`{"access_key_id": "id", "secret_access_key": "key", "bucket_name": "some-bucket", "host": "cdn.example.com", "bucket_in_host": true}`)
+
+				c, err := config.NewFromReader(bytes.NewReader(configBytes))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(c.BucketInHost).To(BeTrue())
+			})
+
+			It("requires a host for bucket_in_host", func() {
+				configBytes := []byte(`{"access_key_id": "id", "secret_access_key": "key", "bucket_name": "some-bucket", "bucket_in_host": true}`)
+
+				_, err := config.NewFromReader(bytes.NewReader(configBytes))
+				Expect(err).To(MatchError("bucket_in_host requires host to be set"))
+			})
+
+			It("rejects bucket_in_host combined with path addressing_style", func() {
+				configBytes := []byte(`{"access_key_id": "id", "secret_access_key": "key", "bucket_name": "some-bucket", "host": "cdn.example.com", "bucket_in_host": true, "addressing_style": "path"}`)
+
+				_, err := config.NewFromReader(bytes.NewReader(configBytes))
+				Expect(err).To(MatchError("bucket_in_host can't be combined with 'path' addressing_style"))
+			})
+		})
+
 		Context("when the configuration file cannot be read", func() {
 			It("returns an error", func() {
 				f := explodingReader{}
@@ -308,6 +412,19 @@ var _ = Describe("BlobstoreClient configuration", func() {
 			Expect(err).To(MatchError("download/upload concurrency and part sizes must be non-negative"))
 		})
 
+		It("rejects negative upload retry settings", func() {
+			dummyJSONBytes := []byte(`{
+				"access_key_id":"id",
+				"secret_access_key":"key",
+				"bucket_name":"some-bucket",
+				"upload_retry_backoff_ms": -1
+			}`)
+			dummyJSONReader := bytes.NewReader(dummyJSONBytes)
+
+			_, err := config.NewFromReader(dummyJSONReader)
+			Expect(err).To(MatchError("upload_max_retries and upload_retry_backoff_ms must be non-negative (0 means use default)"))
+		})
+
 		Describe("multipart copy tuning fields", func() {
 			It("rejects negative multipart copy threshold", func() {
 				dummyJSONBytes := []byte(`{
@@ -380,6 +497,20 @@ var _ = Describe("BlobstoreClient configuration", func() {
 				Expect(c.MultipartCopyPartSize).To(Equal(int64(104857600)))   // 100MB
 			})
 
+			It("reads no_multipart_copy", func() {
+				dummyJSONBytes := []byte(`{
+					"access_key_id":"id",
+					"secret_access_key":"key",
+					"bucket_name":"some-bucket",
+					"no_multipart_copy": true
+				}`)
+				dummyJSONReader := bytes.NewReader(dummyJSONBytes)
+
+				c, err := config.NewFromReader(dummyJSONReader)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(c.NoMultipartCopy).To(BeTrue())
+			})
+
 			It("accepts threshold above AWS limit for providers with higher limits", func() {
 				dummyJSONBytes := []byte(`{
 					"access_key_id":"id",
@@ -539,6 +670,126 @@ var _ = Describe("BlobstoreClient configuration", func() {
 				Expect(err).To(MatchError("can't use access_key_id and secret_access_key with none credentials_source"))
 			})
 		})
+
+		Context("when the requester pays", func() {
+			It("reads request_payer", func() {
+				dummyJSONBytes := []byte(`{"bucket_name": "some-bucket", "access_key_id": "id", "secret_access_key": "key", "request_payer": "requester"}`)
+				c, err := config.NewFromReader(bytes.NewReader(dummyJSONBytes))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(c.RequestPayer).To(Equal(config.RequesterRequestPayer))
+			})
+
+			It("rejects an unknown request_payer", func() {
+				dummyJSONBytes := []byte(`{"bucket_name": "some-bucket", "access_key_id": "id", "secret_access_key": "key", "request_payer": "owner"}`)
+				_, err := config.NewFromReader(bytes.NewReader(dummyJSONBytes))
+				Expect(err).To(MatchError("invalid request_payer: owner (expected 'requester')"))
+			})
+
+			It("rejects request_payer without credentials", func() {
+				dummyJSONBytes := []byte(`{"bucket_name": "some-bucket", "credentials_source": "none", "request_payer": "requester"}`)
+				_, err := config.NewFromReader(bytes.NewReader(dummyJSONBytes))
+				Expect(err).To(MatchError(ContainSubstring("request_payer requires credentials")))
+			})
+		})
+
+		Context("when assuming a role", func() {
+			It("reads the session name, external id and duration", func() {
+				dummyJSONBytes := []byte(`{"bucket_name": "some-bucket", "assume_role_arn": "arn:aws:iam::123456789012:role/some-role", "assume_role_session_name": "some-session", "assume_role_external_id": "some-external-id", "assume_role_duration_seconds": 3600}`)
+				c, err := config.NewFromReader(bytes.NewReader(dummyJSONBytes))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(c.AssumeRoleSessionName).To(Equal("some-session"))
+				Expect(c.AssumeRoleExternalID).To(Equal("some-external-id"))
+				Expect(c.AssumeRoleDurationSeconds).To(Equal(3600))
+			})
+
+			It("rejects a negative duration", func() {
+				dummyJSONBytes := []byte(`{"bucket_name": "some-bucket", "assume_role_arn": "arn:aws:iam::123456789012:role/some-role", "assume_role_duration_seconds": -1}`)
+				_, err := config.NewFromReader(bytes.NewReader(dummyJSONBytes))
+				Expect(err).To(MatchError("assume_role_duration_seconds must not be negative"))
+			})
+
+			It("reads the chain of roles to assume after assume_role_arn", func() {
+				dummyJSONBytes := []byte(`{"bucket_name": "some-bucket", "assume_role_arn": "arn:aws:iam::123456789012:role/first", "assume_role_chain": ["arn:aws:iam::123456789012:role/second", "arn:aws:iam::123456789012:role/third"]}`)
+				c, err := config.NewFromReader(bytes.NewReader(dummyJSONBytes))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(c.AssumeRoleChain).To(Equal([]string{"arn:aws:iam::123456789012:role/second", "arn:aws:iam::123456789012:role/third"}))
+			})
+
+			DescribeTable("validates the chain of roles",
+				func(jsonConfig string, expectedErr string) {
+					_, err := config.NewFromReader(bytes.NewReader([]byte(jsonConfig)))
+					Expect(err).To(MatchError(expectedErr))
+				},
+				Entry("without assume_role_arn",
+					`{"bucket_name": "some-bucket", "assume_role_chain": ["arn:aws:iam::123456789012:role/second"]}`,
+					"assume_role_chain requires assume_role_arn"),
+				Entry("with too many roles",
+					`{"bucket_name": "some-bucket", "assume_role_arn": "a", "assume_role_chain": ["b", "c", "d", "e", "f", "g"]}`,
+					"assume_role_chain must not contain more than 5 roles"),
+				Entry("with an empty role",
+					`{"bucket_name": "some-bucket", "assume_role_arn": "a", "assume_role_chain": ["b", ""]}`,
+					"assume_role_chain must not contain empty role ARNs"),
+				Entry("with a duration over one hour",
+					`{"bucket_name": "some-bucket", "assume_role_arn": "a", "assume_role_chain": ["b"], "assume_role_duration_seconds": 7200}`,
+					"assume_role_duration_seconds must not exceed 3600 with assume_role_chain"),
+			)
+
+			DescribeTable("validates the proxy_url",
+				func(proxyURL string, expectedErr string) {
+					dummyJSONBytes := []byte(`{"bucket_name": "some-bucket", "proxy_url": "` + proxyURL + `"}`)
+					c, err := config.NewFromReader(bytes.NewReader(dummyJSONBytes))
+					if expectedErr == "" {
+						Expect(err).ToNot(HaveOccurred())
+						Expect(c.ProxyURL).To(Equal(proxyURL))
+					} else {
+						Expect(err).To(MatchError(expectedErr))
+					}
+				},
+				Entry("with an http proxy", "http://proxy:3128", ""),
+				Entry("with a SOCKS5 proxy", "socks5h://proxy:1080", ""),
+				Entry("with an unsupported scheme", "ftp://proxy:21", "invalid proxy_url 'ftp://proxy:21': scheme must be http, https, socks5 or socks5h"),
+				Entry("without host", "http://", "invalid proxy_url 'http://': host must be set"),
+			)
+
+			It("requires assume_role_arn for the assume role settings", func() {
+				dummyJSONBytes := []byte(`{"bucket_name": "some-bucket", "assume_role_external_id": "some-external-id"}`)
+				_, err := config.NewFromReader(bytes.NewReader(dummyJSONBytes))
+				Expect(err).To(MatchError("assume_role_session_name, assume_role_external_id and assume_role_duration_seconds require assume_role_arn"))
+			})
+		})
+
+		Context("when the credentials source is `web_identity`", func() {
+			It("validates that the token file and role are set", func() {
+				dummyJSONBytes := []byte(`{"bucket_name": "some-bucket", "credentials_source": "web_identity", "web_identity_token_file": "/var/run/token", "assume_role_arn": "arn:aws:iam::123456789012:role/some-role"}`)
+				_, err := config.NewFromReader(bytes.NewReader(dummyJSONBytes))
+				Expect(err).ToNot(HaveOccurred())
+
+				dummyJSONBytes = []byte(`{"bucket_name": "some-bucket", "credentials_source": "web_identity", "web_identity_token_file": "/var/run/token"}`)
+				_, err = config.NewFromReader(bytes.NewReader(dummyJSONBytes))
+				Expect(err).To(MatchError("web_identity_token_file and assume_role_arn must be provided"))
+
+				dummyJSONBytes = []byte(`{"bucket_name": "some-bucket", "credentials_source": "web_identity", "assume_role_arn": "arn:aws:iam::123456789012:role/some-role"}`)
+				_, err = config.NewFromReader(bytes.NewReader(dummyJSONBytes))
+				Expect(err).To(MatchError("web_identity_token_file and assume_role_arn must be provided"))
+			})
+
+			It("validates that access key and secret key are not set", func() {
+				dummyJSONBytes := []byte(`{"bucket_name": "some-bucket", "credentials_source": "web_identity", "web_identity_token_file": "/var/run/token", "assume_role_arn": "arn:aws:iam::123456789012:role/some-role", "access_key_id": "some_id"}`)
+				_, err := config.NewFromReader(bytes.NewReader(dummyJSONBytes))
+				Expect(err).To(MatchError("can't use access_key_id and secret_access_key with web_identity credentials_source"))
+			})
+
+			It("is implied by a token file when no credentials source is given", func() {
+				dummyJSONBytes := []byte(`{"bucket_name": "some-bucket", "web_identity_token_file": "/var/run/token", "assume_role_arn": "arn:aws:iam::123456789012:role/some-role"}`)
+				c, err := config.NewFromReader(bytes.NewReader(dummyJSONBytes))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(c.CredentialsSource).To(Equal(config.WebIdentityCredentialsSource))
+
+				dummyJSONBytes = []byte(`{"bucket_name": "some-bucket", "web_identity_token_file": "/var/run/token"}`)
+				_, err = config.NewFromReader(bytes.NewReader(dummyJSONBytes))
+				Expect(err).To(MatchError("web_identity_token_file and assume_role_arn must be provided"))
+			})
+		})
 	})
 
 	Describe("returning the alibaba cloud region", func() {
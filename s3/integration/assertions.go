@@ -14,6 +14,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/cloudfoundry/storage-cli/common"
 	"github.com/cloudfoundry/storage-cli/s3/client"
 	"github.com/cloudfoundry/storage-cli/s3/config"
 	. "github.com/onsi/gomega" //nolint:staticcheck
@@ -22,8 +23,30 @@ import (
 var (
 	// expectedPutUploadCalls represents the expected API calls for put requests
 	expectedPutUploadCalls = []string{"PutObject"}
+	// expectedSimpleCopyCalls represents the expected API calls for copies below the multipart copy threshold
+	expectedSimpleCopyCalls = []string{"HeadObject", "CopyObject"}
 )
 
+// isMultipartCopyPattern checks if calls follow the multipart copy pattern:
+// the source lookup, CreateMultipart, one or more UploadPartCopy calls, CompleteMultipart
+func isMultipartCopyPattern(calls []string) bool {
+	if len(calls) < 4 {
+		return false
+	}
+	if calls[0] != "HeadObject" || calls[1] != "CreateMultipart" {
+		return false
+	}
+	if calls[len(calls)-1] != "CompleteMultipart" {
+		return false
+	}
+	for _, call := range calls[2 : len(calls)-1] {
+		if call != "UploadPartCopy" {
+			return false
+		}
+	}
+	return true
+}
+
 // isMultipartUploadPattern checks if calls follow the multipart upload pattern:
 // starts with CreateMultipart, has one or more UploadPart calls, ends with CompleteMultipart
 func isMultipartUploadPattern(calls []string) bool {
@@ -59,7 +82,7 @@ func AssertLifecycleWorks(s3CLIPath string, cfg *config.S3Cli) {
 
 	s3CLISession, err := RunS3CLI(s3CLIPath, configPath, storageType, "put", contentFile, s3Filename)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
+	Expect(s3CLISession.ExitCode).To(BeZero())
 
 	if len(cfg.FolderName) != 0 {
 		folderName := cfg.FolderName
@@ -70,12 +93,12 @@ func AssertLifecycleWorks(s3CLIPath string, cfg *config.S3Cli) {
 		s3CLISession, err :=
 			RunS3CLI(s3CLIPath, noFolderConfigPath, storageType, "exists", fmt.Sprintf("%s/%s", folderName, s3Filename))
 		Expect(err).ToNot(HaveOccurred())
-		Expect(s3CLISession.ExitCode()).To(BeZero())
+		Expect(s3CLISession.ExitCode).To(BeZero())
 	}
 
 	s3CLISession, err = RunS3CLI(s3CLIPath, configPath, storageType, "exists", s3Filename)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
+	Expect(s3CLISession.ExitCode).To(BeZero())
 
 	tmpLocalFile, err := os.CreateTemp("", "s3cli-download")
 	Expect(err).ToNot(HaveOccurred())
@@ -85,7 +108,7 @@ func AssertLifecycleWorks(s3CLIPath string, cfg *config.S3Cli) {
 
 	s3CLISession, err = RunS3CLI(s3CLIPath, configPath, storageType, "get", s3Filename, tmpLocalFile.Name())
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
+	Expect(s3CLISession.ExitCode).To(BeZero())
 
 	gottenBytes, err := os.ReadFile(tmpLocalFile.Name())
 	Expect(err).ToNot(HaveOccurred())
@@ -93,18 +116,18 @@ func AssertLifecycleWorks(s3CLIPath string, cfg *config.S3Cli) {
 
 	s3CLISession, err = RunS3CLI(s3CLIPath, configPath, storageType, "properties", s3Filename)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
-	Expect(s3CLISession.Out.Contents()).To(ContainSubstring(fmt.Sprintf("\"content_length\": %d", len(expectedString))))
-	Expect(s3CLISession.Out.Contents()).To(ContainSubstring("\"etag\":"))
-	Expect(s3CLISession.Out.Contents()).To(ContainSubstring("\"last_modified\":"))
+	Expect(s3CLISession.ExitCode).To(BeZero())
+	Expect(s3CLISession.Stdout).To(ContainSubstring(fmt.Sprintf("\"content_length\": %d", len(expectedString))))
+	Expect(s3CLISession.Stdout).To(ContainSubstring("\"etag\":"))
+	Expect(s3CLISession.Stdout).To(ContainSubstring("\"last_modified\":"))
 
 	s3CLISession, err = RunS3CLI(s3CLIPath, configPath, storageType, "copy", s3Filename, s3Filename+"_copy")
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
+	Expect(s3CLISession.ExitCode).To(BeZero())
 
 	s3CLISession, err = RunS3CLI(s3CLIPath, configPath, storageType, "exists", s3Filename+"_copy")
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
+	Expect(s3CLISession.ExitCode).To(BeZero())
 
 	tmpCopiedFile, err := os.CreateTemp("", "s3cli-download-copy")
 	Expect(err).ToNot(HaveOccurred())
@@ -114,7 +137,7 @@ func AssertLifecycleWorks(s3CLIPath string, cfg *config.S3Cli) {
 
 	s3CLISession, err = RunS3CLI(s3CLIPath, configPath, storageType, "get", s3Filename+"_copy", tmpCopiedFile.Name())
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
+	Expect(s3CLISession.ExitCode).To(BeZero())
 
 	copiedBytes, err := os.ReadFile(tmpCopiedFile.Name())
 	Expect(err).ToNot(HaveOccurred())
@@ -122,20 +145,20 @@ func AssertLifecycleWorks(s3CLIPath string, cfg *config.S3Cli) {
 
 	s3CLISession, err = RunS3CLI(s3CLIPath, configPath, storageType, "delete", s3Filename+"_copy")
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
+	Expect(s3CLISession.ExitCode).To(BeZero())
 
 	s3CLISession, err = RunS3CLI(s3CLIPath, configPath, storageType, "delete", s3Filename)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
+	Expect(s3CLISession.ExitCode).To(BeZero())
 
 	s3CLISession, err = RunS3CLI(s3CLIPath, configPath, storageType, "exists", s3Filename)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(Equal(3))
+	Expect(s3CLISession.ExitCode).To(Equal(3))
 
 	s3CLISession, err = RunS3CLI(s3CLIPath, configPath, storageType, "properties", s3Filename)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
-	Expect(s3CLISession.Out.Contents()).To(ContainSubstring("{}"))
+	Expect(s3CLISession.ExitCode).To(BeZero())
+	Expect(s3CLISession.Stdout).To(ContainSubstring("{}"))
 
 }
 
@@ -165,17 +188,17 @@ func AssertMultipartCopyWorks(s3CLIPath string, cfg *config.S3Cli) {
 	// Upload the test file
 	s3CLISession, err := RunS3CLI(s3CLIPath, configPath, storageType, "put", contentFile, s3Filename)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
+	Expect(s3CLISession.ExitCode).To(BeZero())
 
 	// Copy the file - this should trigger multipart copy since file size (15MB) > threshold (10MB)
 	s3CLISession, err = RunS3CLI(s3CLIPath, configPath, storageType, "copy", s3Filename, s3Filename+"_multipart_copy")
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
+	Expect(s3CLISession.ExitCode).To(BeZero())
 
 	// Verify the copied file exists
 	s3CLISession, err = RunS3CLI(s3CLIPath, configPath, storageType, "exists", s3Filename+"_multipart_copy")
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
+	Expect(s3CLISession.ExitCode).To(BeZero())
 
 	// Download and verify content matches
 	tmpCopiedFile, err := os.CreateTemp("", "s3cli-download-multipart-copy")
@@ -186,7 +209,7 @@ func AssertMultipartCopyWorks(s3CLIPath string, cfg *config.S3Cli) {
 
 	s3CLISession, err = RunS3CLI(s3CLIPath, configPath, storageType, "get", s3Filename+"_multipart_copy", tmpCopiedFile.Name())
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
+	Expect(s3CLISession.ExitCode).To(BeZero())
 
 	copiedBytes, err := os.ReadFile(tmpCopiedFile.Name())
 	Expect(err).ToNot(HaveOccurred())
@@ -195,17 +218,73 @@ func AssertMultipartCopyWorks(s3CLIPath string, cfg *config.S3Cli) {
 	// Verify file size matches
 	s3CLISession, err = RunS3CLI(s3CLIPath, configPath, storageType, "properties", s3Filename+"_multipart_copy")
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
-	Expect(s3CLISession.Out.Contents()).To(ContainSubstring(fmt.Sprintf("\"content_length\": %d", contentSize)))
+	Expect(s3CLISession.ExitCode).To(BeZero())
+	Expect(s3CLISession.Stdout).To(ContainSubstring(fmt.Sprintf("\"content_length\": %d", contentSize)))
 
 	// Clean up
 	s3CLISession, err = RunS3CLI(s3CLIPath, configPath, storageType, "delete", s3Filename+"_multipart_copy")
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
+	Expect(s3CLISession.ExitCode).To(BeZero())
 
 	s3CLISession, err = RunS3CLI(s3CLIPath, configPath, storageType, "delete", s3Filename)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
+	Expect(s3CLISession.ExitCode).To(BeZero())
+}
+
+// AssertMultipartCopyThresholdBoundary verifies that copy switches from a single CopyObject to a
+// multipart copy exactly at the configured multipart copy threshold
+func AssertMultipartCopyThresholdBoundary(s3CLIPath string, cfg *config.S3Cli) {
+	storageType := "s3"
+	threshold := 5 * 1024 * 1024 // 5 MB
+
+	cfg.MultipartCopyThreshold = int64(threshold)
+	cfg.MultipartCopyPartSize = 5 * 1024 * 1024 // 5 MB (AWS minimum)
+
+	configPath := MakeConfigFile(cfg)
+	defer os.Remove(configPath) //nolint:errcheck
+
+	configFile, err := os.Open(configPath)
+	Expect(err).ToNot(HaveOccurred())
+
+	s3Config, err := config.NewFromReader(configFile)
+	Expect(err).ToNot(HaveOccurred())
+
+	// Track API calls to tell a single CopyObject from a multipart copy
+	calls := []string{}
+	s3Client, err := CreateTracingS3Client(&s3Config, &calls)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	blobstoreClient := client.New(s3Client, &s3Config)
+
+	for _, size := range []int{threshold - 1, threshold + 1} {
+		s3Filename := GenerateRandomString()
+		contentFile := MakeContentFile(GenerateRandomString(size))
+		defer os.Remove(contentFile) //nolint:errcheck
+
+		err = blobstoreClient.Put(context.Background(), contentFile, s3Filename, common.PutOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		calls = calls[:0]
+		err = blobstoreClient.Copy(context.Background(), s3Filename, s3Filename+"_copy", common.CopyOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		if size < threshold {
+			Expect(calls).To(Equal(expectedSimpleCopyCalls), "Expected a single CopyObject below the threshold, got: %v", calls)
+		} else {
+			Expect(isMultipartCopyPattern(calls)).To(BeTrue(), "Expected multipart copy pattern (CreateMultipart -> UploadPartCopy(s) -> CompleteMultipart) above the threshold, got: %v", calls)
+		}
+
+		// Clean up
+		s3CLISession, err := RunS3CLI(s3CLIPath, configPath, storageType, "delete", s3Filename+"_copy")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(s3CLISession.ExitCode).To(BeZero())
+
+		s3CLISession, err = RunS3CLI(s3CLIPath, configPath, storageType, "delete", s3Filename)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(s3CLISession.ExitCode).To(BeZero())
+	}
 }
 
 func AssertOnBulkOperations(s3CLIPath string, cfg *config.S3Cli) {
@@ -224,36 +303,111 @@ func AssertOnBulkOperations(s3CLIPath string, cfg *config.S3Cli) {
 
 		s3CLISession, err := RunS3CLI(s3CLIPath, configPath, storageType, "put", localFile, s3Filename)
 		Expect(err).ToNot(HaveOccurred())
-		Expect(s3CLISession.ExitCode()).To(BeZero())
+		Expect(s3CLISession.ExitCode).To(BeZero())
 	}
 
 	s3CLISession, err := RunS3CLI(s3CLIPath, configPath, storageType, "list", s3FilenamePrefix)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
-	output := strings.TrimSpace(string(s3CLISession.Out.Contents()))
+	Expect(s3CLISession.ExitCode).To(BeZero())
+	output := strings.TrimSpace(string(s3CLISession.Stdout))
 	Expect(strings.Split(output, "\n")).To(HaveLen(numFiles))
 
 	s3CLISession, err = RunS3CLI(s3CLIPath, configPath, storageType, "delete-recursive", fmt.Sprintf("%sAAA", s3FilenamePrefix))
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
+	Expect(s3CLISession.ExitCode).To(BeZero())
 
 	s3CLISession, err = RunS3CLI(s3CLIPath, configPath, storageType, "list", s3FilenamePrefix)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
-	output = strings.TrimSpace(string(s3CLISession.Out.Contents()))
+	Expect(s3CLISession.ExitCode).To(BeZero())
+	output = strings.TrimSpace(string(s3CLISession.Stdout))
 	Expect(strings.Split(output, "\n")).To(HaveLen(3))
 
 	s3CLISession, err = RunS3CLI(s3CLIPath, configPath, storageType, "delete-recursive")
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
+	Expect(s3CLISession.ExitCode).To(BeZero())
 
 	s3CLISession, err = RunS3CLI(s3CLIPath, configPath, storageType, "list", s3FilenamePrefix)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
-	output = strings.TrimSpace(string(s3CLISession.Out.Contents()))
+	Expect(s3CLISession.ExitCode).To(BeZero())
+	output = strings.TrimSpace(string(s3CLISession.Stdout))
 	Expect(output).To(BeEmpty())
 }
 
+// AssertBatchedDeleteRecursiveWorks verifies that delete-recursive removes a prefix of more objects
+// than fit into one DeleteObjects request with one request per 1000 keys, and that per-key errors
+// of a DeleteObjects response are reported while objects that are already gone are not
+func AssertBatchedDeleteRecursiveWorks(s3CLIPath string, cfg *config.S3Cli) {
+	storageType := "s3"
+	numFiles := 1001
+	s3FilenamePrefix := GenerateRandomString() + "/"
+	localFile := MakeContentFile(GenerateRandomString())
+	defer os.Remove(localFile) //nolint:errcheck
+
+	configPath := MakeConfigFile(cfg)
+	defer os.Remove(configPath) //nolint:errcheck
+
+	configFile, err := os.Open(configPath)
+	Expect(err).ToNot(HaveOccurred())
+
+	s3Config, err := config.NewFromReader(configFile)
+	Expect(err).ToNot(HaveOccurred())
+
+	s3Client, err := client.NewAwsS3Client(&s3Config)
+	Expect(err).ToNot(HaveOccurred())
+	blobstoreClient := client.New(s3Client, &s3Config)
+
+	putAll := func() {
+		for i := 0; i < numFiles; i++ {
+			err := blobstoreClient.Put(context.Background(), localFile, fmt.Sprintf("%s%04d", s3FilenamePrefix, i), common.PutOptions{})
+			Expect(err).ToNot(HaveOccurred())
+		}
+	}
+	putAll()
+
+	// Track API calls to tell batched deletes from one request per object
+	calls := []string{}
+	tracingS3Client, err := CreateTracingS3Client(&s3Config, &calls)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = client.New(tracingS3Client, &s3Config).DeleteRecursive(context.Background(), s3FilenamePrefix, common.DeleteRecursiveOptions{})
+	Expect(err).ToNot(HaveOccurred())
+
+	deleteCalls := []string{}
+	for _, call := range calls {
+		if strings.HasPrefix(call, "Delete") {
+			deleteCalls = append(deleteCalls, call)
+		}
+	}
+	Expect(deleteCalls).To(Equal([]string{"DeleteObjects", "DeleteObjects"}), "Expected two DeleteObjects requests for %d objects, got: %v", numFiles, deleteCalls)
+
+	s3CLISession, err := RunS3CLI(s3CLIPath, configPath, storageType, "list", s3FilenamePrefix)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(s3CLISession.ExitCode).To(BeZero())
+	Expect(strings.TrimSpace(string(s3CLISession.Stdout))).To(BeEmpty())
+
+	// Per-key errors are reported, keys that are already gone count as deleted. DeleteObjects is sent
+	// the keys as stored, with the folder_name in front of them.
+	putAll()
+	failingKey := s3Config.ObjectKey(s3FilenamePrefix + "0001")
+	goneKey := s3Config.ObjectKey(s3FilenamePrefix + "1000")
+	failingS3Client, err := CreateS3ClientWithDeleteObjectsErrors(&s3Config, map[string]string{
+		failingKey: "AccessDenied",
+		goneKey:    "NoSuchKey",
+	})
+	Expect(err).ToNot(HaveOccurred())
+
+	err = client.New(failingS3Client, &s3Config).DeleteRecursive(context.Background(), s3FilenamePrefix, common.DeleteRecursiveOptions{ContinueOnError: true})
+	Expect(err).To(HaveOccurred())
+	Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("failed to delete object '%s': AccessDenied", failingKey)))
+	Expect(err.Error()).ToNot(ContainSubstring(goneKey))
+
+	// The injected errors don't keep the objects from being deleted, nothing is left to clean up
+	s3CLISession, err = RunS3CLI(s3CLIPath, configPath, storageType, "list", s3FilenamePrefix)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(s3CLISession.ExitCode).To(BeZero())
+	Expect(strings.TrimSpace(string(s3CLISession.Stdout))).To(BeEmpty())
+}
+
 func AssertOnStorageExists(s3CLIPath string, cfg *config.S3Cli) {
 	cfgCopy := *cfg
 	cfgCopy.BucketName = fmt.Sprintf("%s-%s", cfg.BucketName, strings.ToLower(GenerateRandomString(4)))
@@ -288,7 +442,7 @@ func AssertOnStorageExists(s3CLIPath string, cfg *config.S3Cli) {
 	// --- Scenario 1: Bucket does not exist, should be created ---
 	s3CLISession, err := RunS3CLI(s3CLIPath, configPath, "s3", "ensure-storage-exists")
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
+	Expect(s3CLISession.ExitCode).To(BeZero())
 
 	// Verify the bucket now exists using the client created earlier.
 	_, headBucketErr := verificationClient.HeadBucket(context.TODO(), &s3.HeadBucketInput{
@@ -299,7 +453,7 @@ func AssertOnStorageExists(s3CLIPath string, cfg *config.S3Cli) {
 	// --- Scenario 2: Bucket already exists, command should still succeed (idempotency) ---
 	s3CLISession, err = RunS3CLI(s3CLIPath, configPath, "s3", "ensure-storage-exists")
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
+	Expect(s3CLISession.ExitCode).To(BeZero())
 }
 
 func AssertOnPutFailures(cfg *config.S3Cli, content, errorMessage string) {
@@ -321,7 +475,7 @@ func AssertOnPutFailures(cfg *config.S3Cli, content, errorMessage string) {
 	}
 	blobstoreClient := client.New(s3Client, &s3Config)
 
-	err = blobstoreClient.Put(sourceFile, s3Filename)
+	err = blobstoreClient.Put(context.Background(), sourceFile, s3Filename, common.PutOptions{})
 	Expect(err).To(HaveOccurred())
 	Expect(err.Error()).To(ContainSubstring(errorMessage))
 }
@@ -343,7 +497,7 @@ func AssertPutOptionsApplied(s3CLIPath string, cfg *config.S3Cli) {
 
 	s3CLISession, err := RunS3CLI(s3CLIPath, configPath, storageType, "put", contentFile, s3Filename) //nolint:ineffassign,staticcheck
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
+	Expect(s3CLISession.ExitCode).To(BeZero())
 
 	s3Config, err := config.NewFromReader(configFile)
 	Expect(err).ToNot(HaveOccurred())
@@ -379,8 +533,8 @@ func AssertGetNonexistentFails(s3CLIPath string, cfg *config.S3Cli) {
 
 	s3CLISession, err := RunS3CLI(s3CLIPath, configPath, storageType, "get", "non-existent-file", "/dev/null")
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).ToNot(BeZero())
-	Expect(s3CLISession.Err.Contents()).To(ContainSubstring("NoSuchKey"))
+	Expect(s3CLISession.ExitCode).ToNot(BeZero())
+	Expect(s3CLISession.Stderr).To(ContainSubstring("NoSuchKey"))
 }
 
 // AssertDeleteNonexistentWorks asserts that `s3cli delete` on a non-existent
@@ -392,7 +546,7 @@ func AssertDeleteNonexistentWorks(s3CLIPath string, cfg *config.S3Cli) {
 
 	s3CLISession, err := RunS3CLI(s3CLIPath, configPath, storageType, "delete", "non-existent-file")
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
+	Expect(s3CLISession.ExitCode).To(BeZero())
 }
 
 func AssertOnMultipartUploads(s3CLIPath string, cfg *config.S3Cli, content string) {
@@ -418,7 +572,7 @@ func AssertOnMultipartUploads(s3CLIPath string, cfg *config.S3Cli, content strin
 
 	blobstoreClient := client.New(s3Client, &s3Config)
 
-	err = blobstoreClient.Put(sourceFile, s3Filename)
+	err = blobstoreClient.Put(context.Background(), sourceFile, s3Filename, common.PutOptions{})
 	Expect(err).ToNot(HaveOccurred())
 
 	switch config.Provider(cfg.Host) {
@@ -462,12 +616,12 @@ func AssertOnSignedURLs(s3CLIPath string, cfg *config.S3Cli) {
 
 	s3CLISession, err := RunS3CLI(s3CLIPath, configPath, storageType, "put", contentFile, s3Filename)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
+	Expect(s3CLISession.ExitCode).To(BeZero())
 
 	regex := `(?m)((([A-Za-z]{3,9}:(?:\/\/?)?)(?:[-;:&=\+\$,\w]+@)?[A-Za-z0-9.-]+(:[0-9]+)?|(?:www.|[-;:&=\+\$,\w]+@)[A-Za-z0-9.-]+)((?:\/[\+~%\/.\w-_]*)?\??(?:[-\+=&;%@.\w_]*)#?(?:[\w]*))?)`
 
 	// Test GET signed URL
-	getURL, err := blobstoreClient.Sign(s3Filename, "get", 1*time.Minute)
+	getURL, err := blobstoreClient.Sign(context.Background(), s3Filename, "get", 1*time.Minute)
 	Expect(err).ToNot(HaveOccurred())
 	Expect(getURL).To(MatchRegexp(regex))
 
@@ -482,7 +636,7 @@ func AssertOnSignedURLs(s3CLIPath string, cfg *config.S3Cli) {
 	Expect(string(body)).To(Equal(expectedContent))
 
 	// Test PUT signed URL
-	putURL, err := blobstoreClient.Sign(s3Filename+"_put_test", "put", 1*time.Minute)
+	putURL, err := blobstoreClient.Sign(context.Background(), s3Filename+"_put_test", "put", 1*time.Minute)
 	Expect(err).ToNot(HaveOccurred())
 	Expect(putURL).To(MatchRegexp(regex))
 
@@ -535,7 +689,7 @@ func AssertSinglePartUploadWorks(s3CLIPath string, cfg *config.S3Cli) {
 
 	blobstoreClient := client.New(s3Client, &s3Config)
 
-	err = blobstoreClient.Put(sourceFile, s3Filename)
+	err = blobstoreClient.Put(context.Background(), sourceFile, s3Filename, common.PutOptions{})
 	Expect(err).ToNot(HaveOccurred())
 
 	// A single PutObject call is the fingerprint of PutSinglePart
@@ -550,7 +704,7 @@ func AssertSinglePartUploadWorks(s3CLIPath string, cfg *config.S3Cli) {
 
 	s3CLISession, err := RunS3CLI(s3CLIPath, configPath, storageType, "get", s3Filename, tmpLocalFile.Name())
 	Expect(err).ToNot(HaveOccurred())
-	Expect(s3CLISession.ExitCode()).To(BeZero())
+	Expect(s3CLISession.ExitCode).To(BeZero())
 
 	gottenBytes, err := os.ReadFile(tmpLocalFile.Name())
 	Expect(err).ToNot(HaveOccurred())
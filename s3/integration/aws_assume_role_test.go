@@ -50,7 +50,7 @@ var _ = Describe("Testing AWS assume role ", Label("aws", "assume-role"), func()
 			s3CLISession, err := integration.RunS3CLI(s3CLIPath, notAssumeRoleConfigPath, storageType, "exists", s3Filename)
 			GinkgoWriter.Println("error is %v", err)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(s3CLISession.ExitCode()).ToNot(BeZero())
+			Expect(s3CLISession.ExitCode).ToNot(BeZero())
 
 			assumeRoleConfigPath := integration.MakeConfigFile(assumedRoleCfg)
 			defer os.Remove(assumeRoleConfigPath) //nolint:errcheck
@@ -58,7 +58,7 @@ var _ = Describe("Testing AWS assume role ", Label("aws", "assume-role"), func()
 			s3CLISession, err = integration.RunS3CLI(s3CLIPath, assumeRoleConfigPath, storageType, "exists", s3Filename)
 			GinkgoWriter.Println("error is %v", err)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(s3CLISession.ExitCode()).To(BeZero())
+			Expect(s3CLISession.ExitCode).To(BeZero())
 		})
 	})
 })
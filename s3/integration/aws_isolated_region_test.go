@@ -44,13 +44,13 @@ var _ = Describe("Testing in any AWS region isolated from the US standard region
 
 			s3CLISession, err := integration.RunS3CLI(s3CLIPath, configPath, storageType, "put", contentFile, s3Filename)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(s3CLISession.ExitCode()).ToNot(BeZero())
-			Expect(s3CLISession.Err.Contents()).To(ContainSubstring("AuthorizationHeaderMalformed"))
+			Expect(s3CLISession.ExitCode).ToNot(BeZero())
+			Expect(s3CLISession.Stderr).To(ContainSubstring("AuthorizationHeaderMalformed"))
 
 			s3CLISession, err = integration.RunS3CLI(s3CLIPath, configPath, storageType, "delete", s3Filename)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(s3CLISession.ExitCode()).ToNot(BeZero())
-			Expect(s3CLISession.Err.Contents()).To(ContainSubstring("AuthorizationHeaderMalformed"))
+			Expect(s3CLISession.ExitCode).ToNot(BeZero())
+			Expect(s3CLISession.Stderr).To(ContainSubstring("AuthorizationHeaderMalformed"))
 		})
 	})
 })
@@ -62,7 +62,7 @@ var _ = Describe("Testing gets against a public AWS S3 bucket", Label("aws", "pu
 			Expect(err).ToNot(HaveOccurred())
 
 			defer os.Remove("public-file") //nolint:errcheck
-			Expect(s3CLISession.ExitCode()).To(BeZero())
+			Expect(s3CLISession.ExitCode).To(BeZero())
 
 			gottenBytes, err := os.ReadFile("public-file")
 			Expect(err).ToNot(HaveOccurred())
@@ -70,7 +70,7 @@ var _ = Describe("Testing gets against a public AWS S3 bucket", Label("aws", "pu
 
 			s3CLISession, err = integration.RunS3CLI(s3CLIPath, configPath, storageType, "exists", s3Filename)
 			Expect(err).ToNot(HaveOccurred())
-			Expect(s3CLISession.ExitCode()).To(BeZero())
+			Expect(s3CLISession.ExitCode).To(BeZero())
 
 			// Clean up the uploaded file
 			_, err = s3Client.DeleteObject(context.TODO(), &s3.DeleteObjectInput{
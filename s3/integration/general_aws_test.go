@@ -68,6 +68,10 @@ var _ = Describe("General testing for all AWS regions", Label("aws", "static", "
 			func(cfg *config.S3Cli) { integration.AssertOnBulkOperations(s3CLIPath, cfg) },
 			configurations,
 		)
+		DescribeTable("Invoking `s3cli delete-recursive` deletes in batches",
+			func(cfg *config.S3Cli) { integration.AssertBatchedDeleteRecursiveWorks(s3CLIPath, cfg) },
+			configurations,
+		)
 		DescribeTable("Invoking `s3cli get` on a non-existent-key fails",
 			func(cfg *config.S3Cli) { integration.AssertGetNonexistentFails(s3CLIPath, cfg) },
 			configurations,
@@ -84,6 +88,10 @@ var _ = Describe("General testing for all AWS regions", Label("aws", "static", "
 			func(cfg *config.S3Cli) { integration.AssertMultipartCopyWorks(s3CLIPath, cfg) },
 			configurations,
 		)
+		DescribeTable("Copy switches to multipart copy at the configured threshold",
+			func(cfg *config.S3Cli) { integration.AssertMultipartCopyThresholdBoundary(s3CLIPath, cfg) },
+			configurations,
+		)
 		DescribeTable("Single part upload works when threshold exceeds file size",
 			func(cfg *config.S3Cli) { integration.AssertSinglePartUploadWorks(s3CLIPath, cfg) },
 			configurations,
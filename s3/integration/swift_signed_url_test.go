@@ -57,14 +57,14 @@ var _ = Describe("Testing for working signed URLs all Swift/OpenStack regions",
 			It("returns 0 for an existing blob", func() {
 				cliSession, err := integration.RunS3CLI(s3CLIPath, configPath, storageType, "sign", "some-blob", "get", "60s")
 				Expect(err).ToNot(HaveOccurred())
-				Expect(cliSession.ExitCode()).To(BeZero())
+				Expect(cliSession.ExitCode).To(BeZero())
 
-				getUrl := bytes.NewBuffer(cliSession.Out.Contents()).String()
+				getUrl := bytes.NewBuffer(cliSession.Stdout).String()
 				Expect(getUrl).To(MatchRegexp("https://" + swiftHost + ".*?" + "/some-blob"))
 				cliSession, err = integration.RunS3CLI(s3CLIPath, configPath, storageType, "sign", "some-blob", "put", "60s")
 				Expect(err).ToNot(HaveOccurred())
 
-				putUrl := bytes.NewBuffer(cliSession.Out.Contents()).String()
+				putUrl := bytes.NewBuffer(cliSession.Stdout).String()
 				Expect(putUrl).To(MatchRegexp("https://" + swiftHost + ".*?" + "/some-blob"))
 			})
 		})
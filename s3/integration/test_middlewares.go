@@ -4,7 +4,9 @@ import (
 	"context"
 	"sync/atomic"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/smithy-go/middleware"
 	smithyhttp "github.com/aws/smithy-go/transport/http"
 )
@@ -92,13 +94,45 @@ func (m *S3TracingMiddleware) HandleInitialize(
 		*m.calls = append(*m.calls, "CompleteMultipart")
 	case *s3.PutObjectInput:
 		*m.calls = append(*m.calls, "PutObject")
+	case *s3.CopyObjectInput:
+		*m.calls = append(*m.calls, "CopyObject")
+	case *s3.UploadPartCopyInput:
+		*m.calls = append(*m.calls, "UploadPartCopy")
 	case *s3.GetObjectInput:
 		*m.calls = append(*m.calls, "GetObject")
 	case *s3.DeleteObjectInput:
 		*m.calls = append(*m.calls, "DeleteObject")
+	case *s3.DeleteObjectsInput:
+		*m.calls = append(*m.calls, "DeleteObjects")
 	case *s3.HeadObjectInput:
 		*m.calls = append(*m.calls, "HeadObject")
 	}
 
 	return next.HandleInitialize(ctx, in)
 }
+
+// createDeleteObjectsErrorsMiddleware creates an Initialize middleware that adds a per-key error
+// with the given code for each key in errorCodes to the DeleteObjects responses deleting that key
+func createDeleteObjectsErrorsMiddleware(errorCodes map[string]string) middleware.InitializeMiddleware {
+	return middleware.InitializeMiddlewareFunc("DeleteObjectsErrorsMiddleware", func(
+		ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler,
+	) (middleware.InitializeOutput, middleware.Metadata, error) {
+		out, metadata, err := next.HandleInitialize(ctx, in)
+		input, isDeleteObjects := in.Parameters.(*s3.DeleteObjectsInput)
+		output, hasOutput := out.Result.(*s3.DeleteObjectsOutput)
+		if err != nil || !isDeleteObjects || !hasOutput {
+			return out, metadata, err
+		}
+
+		for _, object := range input.Delete.Objects {
+			if code, ok := errorCodes[aws.ToString(object.Key)]; ok {
+				output.Errors = append(output.Errors, types.Error{
+					Key:     object.Key,
+					Code:    aws.String(code),
+					Message: aws.String("injected by the test"),
+				})
+			}
+		}
+		return out, metadata, err
+	})
+}
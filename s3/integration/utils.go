@@ -4,18 +4,15 @@ import (
 	"encoding/json"
 	"math/rand"
 	"os"
-	"os/exec"
-	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/smithy-go/middleware"
+	"github.com/cloudfoundry/storage-cli/clitest"
 	"github.com/cloudfoundry/storage-cli/s3/client"
 	"github.com/cloudfoundry/storage-cli/s3/client/s3middleware"
 	"github.com/cloudfoundry/storage-cli/s3/config"
 
-	. "github.com/onsi/ginkgo/v2" //nolint:staticcheck
 	"github.com/onsi/gomega"
-	"github.com/onsi/gomega/gexec"
 )
 
 const alphaNum = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
@@ -58,8 +55,8 @@ func MakeContentFile(content string) string {
 	return tmpFile.Name()
 }
 
-// RunS3CLI runs the s3cli and outputs the session after waiting for it to finish
-func RunS3CLI(cliPath string, configPath string, storageType string, subcommand string, args ...string) (*gexec.Session, error) {
+// RunS3CLI runs the s3cli and returns its output and exit code after waiting for it to finish
+func RunS3CLI(cliPath string, configPath string, storageType string, subcommand string, args ...string) (*clitest.Result, error) {
 	cmdArgs := []string{
 		"-c",
 		configPath,
@@ -68,13 +65,7 @@ func RunS3CLI(cliPath string, configPath string, storageType string, subcommand
 		subcommand,
 	}
 	cmdArgs = append(cmdArgs, args...)
-	command := exec.Command(cliPath, cmdArgs...)
-	session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
-	if err != nil {
-		return nil, err
-	}
-	session.Wait(1 * time.Minute)
-	return session, nil
+	return clitest.Run(cliPath, cmdArgs...)
 }
 
 // CreateS3ClientWithFailureInjection creates an S3 client with failure injection middleware
@@ -107,3 +98,15 @@ func CreateTracingS3Client(s3Config *config.S3Cli, calls *[]string) (*s3.Client,
 
 	return client.NewAwsS3ClientWithApiOptions(s3Config, apiOptions)
 }
+
+// CreateS3ClientWithDeleteObjectsErrors creates an S3 client whose DeleteObjects responses report
+// the given error code for each key in errorCodes, while the objects are still deleted
+func CreateS3ClientWithDeleteObjectsErrors(s3Config *config.S3Cli, errorCodes map[string]string) (*s3.Client, error) {
+	var apiOptions []func(stack *middleware.Stack) error
+	apiOptions = append(apiOptions, s3middleware.AddFixAcceptEncodingMiddleware)
+	apiOptions = append(apiOptions, func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(createDeleteObjectsErrorsMiddleware(errorCodes), middleware.Before)
+	})
+
+	return client.NewAwsS3ClientWithApiOptions(s3Config, apiOptions)
+}
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AllowedEndpointsEnvVar names the environment variable with the comma separated hosts the CLI may connect to.
+// It lives outside the config on purpose, so a tampered config can't add its own endpoint to the list.
+const AllowedEndpointsEnvVar = "STORAGE_CLI_ALLOWED_ENDPOINTS"
+
+// allowedEndpoints are the hosts clients may be created for, empty allows every host
+var allowedEndpoints []string
+
+// SetAllowedEndpoints restricts the clients created afterwards to endpoints whose host is on the list.
+// An entry is a host name, or "*." followed by a domain to allow all of its subdomains.
+// Blank entries are ignored and an empty list allows every host.
+func SetAllowedEndpoints(hosts []string) {
+	allowedEndpoints = nil
+	for _, host := range hosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host != "" {
+			allowedEndpoints = append(allowedEndpoints, host)
+		}
+	}
+}
+
+// checkEndpointAllowed refuses endpoints whose host is not on the allowlist set with SetAllowedEndpoints
+func checkEndpointAllowed(endpoint string) error {
+	if len(allowedEndpoints) == 0 {
+		return nil
+	}
+
+	u, err := parseEndpoint(endpoint)
+	if err != nil {
+		return fmt.Errorf("endpoint not allowed: %w", err)
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, allowed := range allowedEndpoints {
+		if host == allowed {
+			return nil
+		}
+		if domain, ok := strings.CutPrefix(allowed, "*"); ok && strings.HasPrefix(domain, ".") && strings.HasSuffix(host, domain) {
+			return nil
+		}
+	}
+	return fmt.Errorf("endpoint not allowed: %q is not listed in %s", host, AllowedEndpointsEnvVar)
+}
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Endpoint allowlist", func() {
+	BeforeEach(func() {
+		DeferCleanup(SetAllowedEndpoints, []string(nil))
+		SetAllowedEndpoints([]string{"s3.eu-central-1.amazonaws.com", " *.Blob.Core.Windows.Net ", ""})
+	})
+
+	It("allows every endpoint when no allowlist is set", func() {
+		SetAllowedEndpoints(strings.Split("", ","))
+		Expect(checkEndpointAllowed("https://attacker.example.com")).To(Succeed())
+	})
+
+	It("allows listed hosts regardless of scheme and port", func() {
+		Expect(checkEndpointAllowed("s3.eu-central-1.amazonaws.com")).To(Succeed())
+		Expect(checkEndpointAllowed("http://S3.eu-central-1.amazonaws.com:8080")).To(Succeed())
+	})
+
+	It("allows subdomains of wildcard entries", func() {
+		Expect(checkEndpointAllowed("myaccount.blob.core.windows.net")).To(Succeed())
+	})
+
+	It("refuses hosts that are not listed", func() {
+		err := checkEndpointAllowed("https://attacker.example.com")
+		Expect(err).To(MatchError(`endpoint not allowed: "attacker.example.com" is not listed in STORAGE_CLI_ALLOWED_ENDPOINTS`))
+	})
+
+	It("refuses hosts that only end like a listed host", func() {
+		Expect(checkEndpointAllowed("evil-s3.eu-central-1.amazonaws.com")).To(MatchError(ContainSubstring("endpoint not allowed")))
+		Expect(checkEndpointAllowed("blob.core.windows.net.example.com")).To(MatchError(ContainSubstring("endpoint not allowed")))
+	})
+
+	It("does not let a wildcard entry match the bare domain", func() {
+		Expect(checkEndpointAllowed("blob.core.windows.net")).To(MatchError(ContainSubstring("endpoint not allowed")))
+	})
+
+	It("refuses an empty endpoint", func() {
+		Expect(checkEndpointAllowed("")).To(MatchError(ContainSubstring("endpoint not allowed")))
+	})
+
+	It("is checked when the client is created", func() {
+		client, err := NewStorageClient("dav", strings.NewReader(`{"Endpoint": "https://attacker.example.com/blobs"}`))
+		Expect(err).To(MatchError(ContainSubstring("endpoint not allowed")))
+		Expect(client).To(BeNil())
+
+		client, err = NewStorageClient("s3", strings.NewReader(`{"bucket_name": "some-bucket", "region": "eu-central-1", "credentials_source": "none"}`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client).ToNot(BeNil())
+	})
+})
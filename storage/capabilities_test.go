@@ -0,0 +1,195 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	alioss "github.com/cloudfoundry/storage-cli/alioss/client"
+	aliossfakes "github.com/cloudfoundry/storage-cli/alioss/client/clientfakes"
+	azurebs "github.com/cloudfoundry/storage-cli/azurebs/client"
+	azurebsfakes "github.com/cloudfoundry/storage-cli/azurebs/client/clientfakes"
+	"github.com/cloudfoundry/storage-cli/common"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// capabilityProbes invoke the operation behind each capability with harmless arguments
+var capabilityProbes = map[string]func(str Storager, dir string) error{
+	common.CapabilityPut: func(str Storager, dir string) error {
+		return str.Put(context.Background(), filepath.Join(dir, "source"), "object", common.PutOptions{})
+	},
+	common.CapabilityPutWithETag: func(str Storager, dir string) error {
+		_, err := str.PutWithETag(context.Background(), filepath.Join(dir, "source"), "object", common.PutOptions{})
+		return err
+	},
+	common.CapabilityPutWithManifest: func(str Storager, dir string) error {
+		manifest := common.UploadManifest{Parts: []common.UploadPart{{PartNumber: 1, Offset: 0, Size: 10}}}
+		return str.PutWithManifest(context.Background(), filepath.Join(dir, "source"), "object", manifest, common.PutOptions{})
+	},
+	common.CapabilityGet: func(str Storager, dir string) error {
+		return str.Get(context.Background(), "object", filepath.Join(dir, "destination"), common.GetOptions{})
+	},
+	common.CapabilityGetRange: func(str Storager, dir string) error {
+		return str.GetRange(context.Background(), "object", filepath.Join(dir, "destination"), 0, common.GetOptions{})
+	},
+	common.CapabilityDelete: func(str Storager, dir string) error {
+		return str.Delete(context.Background(), "object")
+	},
+	common.CapabilityDeleteRecursive: func(str Storager, dir string) error {
+		return str.DeleteRecursive(context.Background(), "prefix/", common.DeleteRecursiveOptions{})
+	},
+	common.CapabilityExists: func(str Storager, dir string) error {
+		_, err := str.Exists(context.Background(), "object")
+		return err
+	},
+	common.CapabilitySize: func(str Storager, dir string) error {
+		_, err := str.Size(context.Background(), "object")
+		return err
+	},
+	common.CapabilitySign: func(str Storager, dir string) error {
+		_, err := str.Sign(context.Background(), "object", "put", time.Minute)
+		return err
+	},
+	common.CapabilitySignContent: func(str Storager, dir string) error {
+		_, err := str.SignWithOptions(context.Background(), "object", "put", time.Minute, common.SignOptions{ContentType: "text/plain"})
+		return err
+	},
+	common.CapabilitySignStartAt: func(str Storager, dir string) error {
+		_, err := str.SignWithOptions(context.Background(), "object", "get", time.Minute, common.SignOptions{StartAt: time.Now().Add(time.Hour)})
+		return err
+	},
+	common.CapabilityList: func(str Storager, dir string) error {
+		_, err := str.List(context.Background(), "prefix/")
+		return err
+	},
+	common.CapabilityCopy: func(str Storager, dir string) error {
+		return str.Copy(context.Background(), "object", "copy", common.CopyOptions{})
+	},
+	common.CapabilityCopyFromBucket: func(str Storager, dir string) error {
+		return str.CopyFromBucket(context.Background(), "other-bucket", "", "object", "copy", common.CopyOptions{})
+	},
+	common.CapabilityCopyToBucket: func(str Storager, dir string) error {
+		return str.CopyToBucket(context.Background(), "object", "other-bucket", "copy", common.CopyOptions{})
+	},
+	common.CapabilityRename: func(str Storager, dir string) error {
+		return str.Rename(context.Background(), "object", "renamed")
+	},
+	common.CapabilityProperties: func(str Storager, dir string) error {
+		_, err := str.Properties(context.Background(), "object")
+		return err
+	},
+	common.CapabilityHead: func(str Storager, dir string) error {
+		_, err := str.Head(context.Background(), "object")
+		return err
+	},
+	common.CapabilityEnsureStorageExists: func(str Storager, dir string) error {
+		return str.EnsureStorageExists(context.Background())
+	},
+	common.CapabilityIdentity: func(str Storager, dir string) error {
+		_, err := str.Identity(context.Background())
+		return err
+	},
+}
+
+// isUnsupported tells the errors of operations a backend doesn't implement from any other failure
+func isUnsupported(err error) bool {
+	return err != nil && (strings.Contains(err.Error(), "not implemented") || strings.Contains(err.Error(), "not supported"))
+}
+
+var _ = Describe("Capabilities", func() {
+	var (
+		dir    string
+		server *httptest.Server
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "source"), []byte("0123456789"), 0644)).To(Succeed())
+
+		// Every request is denied, so the supported operations fail fast without being retried
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		DeferCleanup(server.Close)
+
+		stdout := os.Stdout
+		devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+		Expect(err).ToNot(HaveOccurred())
+		os.Stdout = devNull
+		DeferCleanup(func() {
+			os.Stdout = stdout
+			devNull.Close() //nolint:errcheck
+		})
+	})
+
+	newFromConfig := func(storageType string, config string) Storager {
+		str, err := NewStorageClient(storageType, strings.NewReader(config))
+		Expect(err).ToNot(HaveOccurred())
+		return str
+	}
+
+	DescribeTable("reports exactly the operations the backend implements",
+		func(newStorager func() Storager) {
+			str := newStorager()
+			capabilities := str.Capabilities()
+			Expect(capabilities).ToNot(BeEmpty())
+
+			for capability, probe := range capabilityProbes {
+				err := probe(str, dir)
+				if isUnsupported(err) {
+					Expect(capabilities).ToNot(ContainElement(capability), "%s is reported but fails with: %s", capability, err)
+				} else {
+					Expect(capabilities).To(ContainElement(capability), "%s is not reported but implemented", capability)
+				}
+			}
+			for _, capability := range capabilities {
+				Expect(capabilityProbes).To(HaveKey(capability))
+			}
+		},
+		Entry("s3", func() Storager {
+			serverURL, err := url.Parse(server.URL)
+			Expect(err).ToNot(HaveOccurred())
+			return newFromConfig("s3", fmt.Sprintf(`{"access_key_id": "id", "secret_access_key": "key", "bucket_name": "some-bucket", "host": %q, "port": %s, "use_ssl": false, "region": "us-east-1", "upload_retry_backoff_ms": 1}`,
+				serverURL.Hostname(), serverURL.Port()))
+		}),
+		Entry("s3 for openstack swift", func() Storager {
+			serverURL, err := url.Parse(server.URL)
+			Expect(err).ToNot(HaveOccurred())
+			return newFromConfig("s3", fmt.Sprintf(`{"access_key_id": "id", "secret_access_key": "key", "bucket_name": "some-bucket", "host": %q, "port": %s, "use_ssl": false, "region": "us-east-1", "upload_retry_backoff_ms": 1, "swift_auth_account": "account", "swift_temp_url_key": "key"}`,
+				serverURL.Hostname(), serverURL.Port()))
+		}),
+		Entry("gcs", func() Storager {
+			GinkgoT().Setenv("STORAGE_EMULATOR_HOST", server.URL)
+			return newFromConfig("gcs", `{"bucket_name": "some-bucket", "credentials_source": "none"}`)
+		}),
+		Entry("azurebs", func() Storager {
+			client, err := azurebs.New(&azurebsfakes.FakeStorageClient{})
+			Expect(err).ToNot(HaveOccurred())
+			return &client
+		}),
+		Entry("alioss", func() Storager {
+			client, err := alioss.New(&aliossfakes.FakeStorageClient{})
+			Expect(err).ToNot(HaveOccurred())
+			return &client
+		}),
+		Entry("dav", func() Storager {
+			return newFromConfig("dav", fmt.Sprintf(`{"endpoint": %q}`, server.URL))
+		}),
+	)
+
+	It("passes the capabilities of the backend through a key_prefix", func() {
+		fake := &FakeStorager{}
+		fake.CapabilitiesReturns([]string{common.CapabilityPut, common.CapabilityGet})
+
+		str := &prefixedStorager{str: fake, prefix: "some-prefix/"}
+		Expect(str.Capabilities()).To(Equal([]string{common.CapabilityPut, common.CapabilityGet}))
+	})
+})
@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// caseCollisions returns the groups of keys that differ only by case, each sorted, in the order
+// their first key appears in keys. Keys appearing more than once count once.
+func caseCollisions(keys []string) [][]string {
+	var folded []string
+	groups := map[string][]string{}
+	for _, key := range keys {
+		fold := strings.ToLower(key)
+		group, seen := groups[fold]
+		if !seen {
+			folded = append(folded, fold)
+		}
+		if !slices.Contains(group, key) {
+			groups[fold] = append(group, key)
+		}
+	}
+
+	var collisions [][]string
+	for _, fold := range folded {
+		if group := groups[fold]; len(group) > 1 {
+			slices.Sort(group)
+			collisions = append(collisions, group)
+		}
+	}
+	return collisions
+}
+
+// warnCaseCollisions logs a warning for every group of keys that differ only by case. Stores like
+// S3 and GCS keep them apart, tools assuming case-insensitive keys would mix them up.
+func warnCaseCollisions(keys []string) {
+	for _, group := range caseCollisions(keys) {
+		slog.Warn("Keys differ only by case", "keys", group)
+	}
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/storage-cli/common"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("case collisions", func() {
+	It("groups the keys that differ only by case", func() {
+		collisions := caseCollisions([]string{"logs/App.log", "logs/b.log", "logs/app.log", "logs/APP.log", "logs/B.LOG", "logs/c.log"})
+		Expect(collisions).To(Equal([][]string{
+			{"logs/APP.log", "logs/App.log", "logs/app.log"},
+			{"logs/B.LOG", "logs/b.log"},
+		}))
+	})
+
+	It("doesn't count a key appearing twice as a collision", func() {
+		Expect(caseCollisions([]string{"a", "a", "b"})).To(BeEmpty())
+	})
+
+	Context("with --warn-case-collisions", func() {
+		var (
+			commandExecuter *CommandExecuter
+			fakeStorager    *FakeStorager
+			logs            *bytes.Buffer
+		)
+
+		BeforeEach(func() {
+			fakeStorager = &FakeStorager{}
+			commandExecuter = NewCommandExecuter(fakeStorager)
+			logs = &bytes.Buffer{}
+
+			original := slog.Default()
+			slog.SetDefault(slog.New(slog.NewJSONHandler(logs, nil)))
+			DeferCleanup(func() {
+				slog.SetDefault(original)
+			})
+		})
+
+		It("warns about listed keys that differ only by case", func() {
+			fakeStorager.ListReturns([]string{"logs/App.log", "logs/app.log", "logs/other.log"}, nil)
+
+			var err error
+			output := captureStdout(func() {
+				err = commandExecuter.Execute(context.Background(), "list", []string{"--warn-case-collisions", "logs/"})
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output).To(Equal("logs/App.log\nlogs/app.log\nlogs/other.log\n"))
+			Expect(logs.String()).To(ContainSubstring(`"level":"WARN","msg":"Keys differ only by case","keys":["logs/App.log","logs/app.log"]`))
+			Expect(logs.String()).ToNot(ContainSubstring("other.log"))
+		})
+
+		It("warns about keys that differ only by case in the json format", func() {
+			fakeStorager.ListDetailedReturns([]common.ObjectInfo{{Key: "README.md"}, {Key: "readme.md"}}, nil)
+
+			captureStdout(func() {
+				Expect(commandExecuter.Execute(context.Background(), "list", []string{"--warn-case-collisions", "--format", "json"})).To(Succeed())
+			})
+			Expect(logs.String()).To(ContainSubstring(`"keys":["README.md","readme.md"]`))
+		})
+
+		It("doesn't look for collisions without the flag", func() {
+			fakeStorager.ListReturns([]string{"logs/App.log", "logs/app.log"}, nil)
+
+			captureStdout(func() {
+				Expect(commandExecuter.Execute(context.Background(), "list", []string{"logs/"})).To(Succeed())
+			})
+			Expect(logs.String()).ToNot(ContainSubstring("Keys differ only by case"))
+		})
+
+		It("warns about local files that differ only by case from remote objects on sync", func() {
+			localDir := GinkgoT().TempDir()
+			Expect(os.WriteFile(filepath.Join(localDir, "Report.txt"), []byte("report"), 0644)).To(Succeed())
+			fakeStorager.CapabilitiesReturns([]string{common.CapabilityPut, common.CapabilityList})
+			fakeStorager.ListDetailedReturns([]common.ObjectInfo{{Key: "release/report.txt", Size: 6}}, nil)
+
+			var err error
+			captureStdout(func() {
+				err = commandExecuter.Execute(context.Background(), "sync", []string{"--dry-run", "--warn-case-collisions", localDir, "release"})
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(logs.String()).To(ContainSubstring(`"keys":["release/Report.txt","release/report.txt"]`))
+		})
+	})
+})
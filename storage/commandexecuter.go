@@ -1,10 +1,19 @@
 package storage
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
 )
 
 type NotExistsError struct{}
@@ -13,6 +22,12 @@ func (e *NotExistsError) Error() string {
 	return "object does not exist"
 }
 
+type EmptyListError struct{}
+
+func (e *EmptyListError) Error() string {
+	return "no objects found"
+}
+
 type CommandExecuter struct {
 	str Storager
 }
@@ -25,59 +40,371 @@ func (sty *CommandExecuter) SetStorager(s Storager) {
 	sty.str = s
 }
 
-func (sty *CommandExecuter) Execute(cmd string, nonFlagArgs []string) error {
+func (sty *CommandExecuter) Execute(ctx context.Context, cmd string, nonFlagArgs []string) error {
 
 	switch cmd {
 	case "put":
-		if len(nonFlagArgs) != 2 {
-			return fmt.Errorf("put method expected 2 arguments got %d", len(nonFlagArgs))
+		flags := flag.NewFlagSet("put", flag.ContinueOnError)
+		maxUploadSize := flags.Int64("max-upload-size", 0, "refuse to upload files larger than this many bytes (0 means no limit)")
+		manifestPath := flags.String("manifest", "", "upload the file in the parts listed in this JSON manifest")
+		maxBandwidth := flags.Int64("max-bandwidth", 0, "limit the upload to this many bytes per second (0 means no limit)")
+		contentAddressed := flags.Bool("content-addressed", false, "upload to <key-prefix><sha256 of the file> and print that key, skipping the upload if it already exists")
+		printETag := flags.Bool("print-etag", false, "print the ETag of the uploaded object")
+		contentType := flags.String("content-type", "", "store the object with this Content-Type instead of detecting it from the file")
+		storeMD5 := flags.Bool("store-md5", false, "store the MD5 of the file as user metadata of the object")
+		metadata := metadataFlag{}
+		flags.Var(metadata, "meta", "store this key=value pair as user metadata of the object, can be repeated")
+		args, err := parseFlags(flags, nonFlagArgs)
+		if err != nil {
+			return err
+		}
+
+		if *contentAddressed {
+			if len(args) != 1 && len(args) != 2 {
+				return fmt.Errorf("put method with --content-addressed expected 1 or 2 arguments got %d", len(args))
+			}
+		} else if len(args) != 2 {
+			return fmt.Errorf("put method expected 2 arguments got %d", len(args))
+		}
+		if *maxBandwidth < 0 {
+			return errors.New("--max-bandwidth must not be negative")
+		}
+		if *printETag && *manifestPath != "" {
+			return errors.New("--print-etag can't be combined with --manifest")
+		}
+		if *storeMD5 && metadata.has(common.MD5MetadataKey) {
+			return fmt.Errorf("--meta %s can't be combined with --store-md5", common.MD5MetadataKey)
+		}
+		sourceFilePath := args[0]
+		if sourceFilePath == stdinSource {
+			spooled, err := spoolStdin(*maxUploadSize)
+			if err != nil {
+				return err
+			}
+			defer os.Remove(spooled) //nolint:errcheck
+			sourceFilePath = spooled
 		}
-		sourceFilePath, dst := nonFlagArgs[0], nonFlagArgs[1]
 
-		_, err := os.Stat(sourceFilePath)
+		info, err := os.Stat(sourceFilePath)
 		if err != nil {
 			return fmt.Errorf("%w", err)
 		}
-		return sty.str.Put(sourceFilePath, dst)
+		if *maxUploadSize > 0 && info.Size() > *maxUploadSize {
+			return fmt.Errorf("%s is %d bytes which exceeds the maximum upload size of %d bytes", sourceFilePath, info.Size(), *maxUploadSize)
+		}
 
-	case "get":
+		var dst string
+		if *contentAddressed {
+			var prefix string
+			if len(args) == 2 {
+				prefix = args[1]
+			}
+			if dst, err = contentAddressedKey(sourceFilePath, prefix); err != nil {
+				return err
+			}
+			fmt.Println(dst)
+
+			exists, err := sty.str.Exists(ctx, dst)
+			if err != nil {
+				return fmt.Errorf("failed to check exist: %w", err)
+			}
+			if exists {
+				slog.Info("Skipping upload, content is already stored", "object", dst)
+				return nil
+			}
+		} else {
+			dst = args[1]
+		}
+
+		if *contentType == "" {
+			if *contentType, err = fileContentType(sourceFilePath); err != nil {
+				return fmt.Errorf("failed to detect content type: %w", err)
+			}
+		}
+		options := common.PutOptions{ContentType: *contentType}
+		if *storeMD5 {
+			md5, err := fileMD5(sourceFilePath)
+			if err != nil {
+				return err
+			}
+			metadata[common.MD5MetadataKey] = md5
+		}
+		if len(metadata) > 0 {
+			options.Metadata = metadata
+		}
+		options.Bandwidth = common.NewBandwidthLimiter(*maxBandwidth)
+		if *manifestPath != "" {
+			manifest, err := readUploadManifest(*manifestPath)
+			if err != nil {
+				return err
+			}
+			return sty.str.PutWithManifest(ctx, sourceFilePath, dst, manifest, options)
+		}
+		if *printETag {
+			etag, err := sty.str.PutWithETag(ctx, sourceFilePath, dst, options)
+			if err != nil {
+				return err
+			}
+			fmt.Println(etag)
+			return nil
+		}
+		return sty.str.Put(ctx, sourceFilePath, dst, options)
+
+	case "put-signed":
 		if len(nonFlagArgs) != 2 {
-			return fmt.Errorf("get method expected 2 arguments got %d", len(nonFlagArgs))
+			return fmt.Errorf("put-signed method expected 2 arguments got %d", len(nonFlagArgs))
+		}
+		signedURL, sourceFilePath := nonFlagArgs[0], nonFlagArgs[1]
+		return putSigned(ctx, signedURL, sourceFilePath)
+
+	case "get":
+		flags := flag.NewFlagSet("get", flag.ContinueOnError)
+		resume := flags.Bool("continue", false, "resume a previous download from <dest>.part instead of starting over")
+		retries := flags.Int("eventual-consistency-retries", 0, "retry this many times with backoff while the object is not found yet")
+		noSpaceCheck := flags.Bool("no-space-check", false, "skip checking that the destination filesystem has room for the object")
+		maxBandwidth := flags.Int64("max-bandwidth", 0, "limit the download to this many bytes per second (0 means no limit)")
+		noMkdir := flags.Bool("no-mkdir", false, "fail instead of creating missing parent directories of the destination")
+		cacheDir := flags.String("cache-dir", "", "keep a copy of the object in this directory and reuse it while the object's ETag doesn't change")
+		verify := flags.Bool("verify", false, "compare the checksum of the download with the object's and remove the download if they differ")
+		args, err := parseFlags(flags, nonFlagArgs)
+		if err != nil {
+			return err
 		}
-		src, dst := nonFlagArgs[0], nonFlagArgs[1]
-		return sty.str.Get(src, dst)
+
+		if len(args) != 2 {
+			return fmt.Errorf("get method expected 2 arguments got %d", len(args))
+		}
+		if *retries < 0 {
+			return errors.New("--eventual-consistency-retries must not be negative")
+		}
+		if *maxBandwidth < 0 {
+			return errors.New("--max-bandwidth must not be negative")
+		}
+		if *resume && *cacheDir != "" {
+			return errors.New("--continue can't be combined with --cache-dir")
+		}
+		src, dst := args[0], args[1]
+		if *verify && !slices.Contains(sty.str.Capabilities(), common.CapabilityHead) {
+			return errors.New("--verify is not supported by this storage type")
+		}
+
+		// If the object still does not show up, fall through so the backend reports the failure as usual
+		if *retries > 0 {
+			if _, err := sty.waitForObject(ctx, src, *retries, false); err != nil {
+				return fmt.Errorf("failed to check exist: %w", err)
+			}
+		}
+		if !*noMkdir {
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				return fmt.Errorf("failed to create destination directory: %w", err)
+			}
+		}
+		if !*noSpaceCheck {
+			if err := sty.checkDiskSpace(ctx, src, dst, *resume); err != nil {
+				return err
+			}
+		}
+		options := common.GetOptions{Bandwidth: common.NewBandwidthLimiter(*maxBandwidth)}
+		var verification *downloadVerification
+		// Resumed downloads are verified anyway
+		if *verify && !*resume {
+			if verification, err = sty.prepareVerification(ctx, src); err != nil {
+				return err
+			}
+			if verification != nil {
+				options.Checksum = verification.inline
+			}
+		}
+
+		switch {
+		case *resume:
+			err = sty.resumeGet(ctx, src, dst, options)
+		case *cacheDir != "":
+			err = sty.cachedGet(ctx, src, dst, *cacheDir, options)
+		default:
+			err = sty.str.Get(ctx, src, dst, options)
+		}
+		if err != nil || verification == nil {
+			return err
+		}
+		return verification.verify(src, dst)
 
 	case "copy":
+		flags := flag.NewFlagSet("copy", flag.ContinueOnError)
+		srcBucket := flags.String("source-bucket", "", "copy from this bucket instead of the configured one")
+		srcRegion := flags.String("source-region", "", "region of the source bucket, if it differs from the configured one")
+		var dstBucket string
+		flags.StringVar(&dstBucket, "dest-bucket", "", "copy into this bucket instead of the configured one")
+		flags.StringVar(&dstBucket, "dest-container", "", "same as --dest-bucket")
+		resetMetadata := flags.Bool("overwrite-metadata-on-copy", false, "start the copy without the source's metadata instead of preserving it")
+		sourceSAS := flags.String("source-sas", "", "SAS token that authorizes reading the source, e.g. of another storage account (azurebs only)")
+		noMultipartCopy := flags.Bool("no-multipart-copy", false, "copy with a single request whatever the object's size, for providers that mishandle UploadPartCopy (s3 only)")
+		args, err := parseFlags(flags, nonFlagArgs)
+		if err != nil {
+			return err
+		}
+
+		if len(args) != 2 {
+			return fmt.Errorf("copy method expected 2 arguments got %d", len(args))
+		}
+		options := common.CopyOptions{ResetMetadata: *resetMetadata, SourceSAS: *sourceSAS, NoMultipart: *noMultipartCopy}
+
+		srcBlob, dstBlob := args[0], args[1]
+		if dstBucket != "" {
+			if *srcBucket != "" || *srcRegion != "" {
+				return errors.New("--dest-bucket can't be combined with --source-bucket or --source-region")
+			}
+			return sty.str.CopyToBucket(ctx, srcBlob, dstBucket, dstBlob, options)
+		}
+		if *srcBucket != "" {
+			return sty.str.CopyFromBucket(ctx, *srcBucket, *srcRegion, srcBlob, dstBlob, options)
+		}
+		if *srcRegion != "" {
+			return errors.New("--source-region requires --source-bucket")
+		}
+		return sty.str.Copy(ctx, srcBlob, dstBlob, options)
+
+	case "rename":
+		if len(nonFlagArgs) != 2 {
+			return fmt.Errorf("rename method expected 2 arguments got %d", len(nonFlagArgs))
+		}
+
+		srcBlob, dstBlob := nonFlagArgs[0], nonFlagArgs[1]
+		return sty.str.Rename(ctx, srcBlob, dstBlob)
+
+	case "move", "mv":
 		if len(nonFlagArgs) != 2 {
-			return fmt.Errorf("copy method expected 2 arguments got %d", len(nonFlagArgs))
+			return fmt.Errorf("move method expected 2 arguments got %d", len(nonFlagArgs))
 		}
 
 		srcBlob, dstBlob := nonFlagArgs[0], nonFlagArgs[1]
-		return sty.str.Copy(srcBlob, dstBlob)
+		return sty.move(ctx, srcBlob, dstBlob)
 
 	case "delete":
 		if len(nonFlagArgs) != 1 {
 			return fmt.Errorf("delete method expected 1 argument got %d", len(nonFlagArgs))
 		}
-		return sty.str.Delete(nonFlagArgs[0])
+		return sty.str.Delete(ctx, nonFlagArgs[0])
 
 	case "delete-recursive":
+		flags := flag.NewFlagSet("delete-recursive", flag.ContinueOnError)
+		dryRun := flags.Bool("dry-run", false, "print the objects that would be deleted instead of deleting them")
+		planFormat := flags.String("format", jsonPlanFormat, "output format of --dry-run: json|lines")
+		continueOnError := flags.Bool("continue-on-error", false, "keep deleting the remaining objects when one fails and report all failures at the end")
+		flags.BoolFunc("fail-fast", "stop at the first object that fails to delete (default)", func(string) error {
+			*continueOnError = false
+			return nil
+		})
+		concurrency := flags.Int("concurrency", 0, "send this many delete requests at the same time (0 keeps the storage type's default)")
+		args, err := parseFlags(flags, nonFlagArgs)
+		if err != nil {
+			return err
+		}
+		if *concurrency < 0 {
+			return fmt.Errorf("--concurrency must not be negative, got %d", *concurrency)
+		}
+		if err := validatePlanFormat(*planFormat); err != nil {
+			return err
+		}
+
 		var prefix string
-		if len(nonFlagArgs) > 1 {
-			return fmt.Errorf("delete-recursive takes at most 1 argument (prefix) got %d", len(nonFlagArgs))
+		if len(args) > 1 {
+			return fmt.Errorf("delete-recursive takes at most 1 argument (prefix) got %d", len(args))
 		}
-		if len(nonFlagArgs) == 1 {
-			prefix = nonFlagArgs[0]
+		if len(args) == 1 {
+			prefix = args[0]
 		}
 
-		return sty.str.DeleteRecursive(prefix)
+		if *dryRun {
+			return sty.printDeleteRecursivePlan(ctx, prefix, *planFormat)
+		}
+		return sty.deleteRecursive(ctx, prefix, common.DeleteRecursiveOptions{ContinueOnError: *continueOnError, Concurrency: *concurrency})
+
+	case "sweep":
+		flags := flag.NewFlagSet("sweep", flag.ContinueOnError)
+		maxAge := flags.Duration("older-than", 0, "delete the objects last modified longer ago than this, e.g. 168h")
+		dryRun := flags.Bool("dry-run", false, "print the objects that would be deleted instead of deleting them")
+		planFormat := flags.String("format", jsonPlanFormat, "output format of --dry-run: json|lines")
+		totalConcurrency := flags.Int("total-concurrency", 0, "send at most this many requests at the same time, 0 for no limit")
+		continueOnError := flags.Bool("continue-on-error", false, "keep deleting the remaining objects when one fails and report all failures at the end")
+		flags.BoolFunc("fail-fast", "stop at the first object that fails to delete (default)", func(string) error {
+			*continueOnError = false
+			return nil
+		})
+		args, err := parseFlags(flags, nonFlagArgs)
+		if err != nil {
+			return err
+		}
+
+		if len(args) != 1 {
+			return fmt.Errorf("sweep method expected 1 argument (prefix) got %d", len(args))
+		}
+		if *maxAge <= 0 {
+			return errors.New("--older-than must be a positive duration")
+		}
+		if *totalConcurrency < 0 {
+			return fmt.Errorf("--total-concurrency must not be negative, got %d", *totalConcurrency)
+		}
+		if err := validatePlanFormat(*planFormat); err != nil {
+			return err
+		}
+
+		return sty.sweep(ctx, args[0], *maxAge, *dryRun, *planFormat, *continueOnError, common.NewConcurrencyLimiter(*totalConcurrency))
+
+	case "sync":
+		flags := flag.NewFlagSet("sync", flag.ContinueOnError)
+		concurrency := flags.Int("concurrency", defaultSyncConcurrency, "upload this many files at the same time")
+		dryRun := flags.Bool("dry-run", false, "print which files are new, changed or unchanged instead of uploading them")
+		checkCase := flags.Bool("warn-case-collisions", false, "log a warning for keys, local or remote, that differ only by case")
+		totalConcurrency := flags.Int("total-concurrency", 0, "send at most this many requests at the same time, 0 for no limit")
+		noGuessContentType := flags.Bool("no-guess-content-type", false, "store the objects with the content type the provider picks instead of one guessed from each file")
+		continueOnError := flags.Bool("continue-on-error", false, "keep uploading the remaining files when one fails and report all failures at the end")
+		flags.BoolFunc("fail-fast", "stop at the first file that fails to upload (default)", func(string) error {
+			*continueOnError = false
+			return nil
+		})
+		args, err := parseFlags(flags, nonFlagArgs)
+		if err != nil {
+			return err
+		}
+
+		if len(args) != 2 {
+			return fmt.Errorf("sync method expected 2 arguments (local directory and prefix) got %d", len(args))
+		}
+		if *concurrency < 1 {
+			return fmt.Errorf("--concurrency must be at least 1, got %d", *concurrency)
+		}
+		if *totalConcurrency < 0 {
+			return fmt.Errorf("--total-concurrency must not be negative, got %d", *totalConcurrency)
+		}
+		info, err := os.Stat(args[0])
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("%s is not a directory", args[0])
+		}
+
+		limiter := common.NewConcurrencyLimiter(*totalConcurrency)
+		return sty.syncDir(ctx, args[0], args[1], *concurrency, *dryRun, *checkCase, !*noGuessContentType, *continueOnError, limiter)
 
 	case "exists":
-		if len(nonFlagArgs) != 1 {
-			return fmt.Errorf("exists method expected 1 argument got %d", len(nonFlagArgs))
+		flags := flag.NewFlagSet("exists", flag.ContinueOnError)
+		retries := flags.Int("eventual-consistency-retries", 0, "retry this many times with backoff while the object is not found yet")
+		treat403AsAbsent := flags.Bool("treat-403-as-absent", false, "report the object as not found if the provider denies access to it, for buckets that answer 403 for missing keys")
+		args, err := parseFlags(flags, nonFlagArgs)
+		if err != nil {
+			return err
+		}
+
+		if len(args) != 1 {
+			return fmt.Errorf("exists method expected 1 argument got %d", len(args))
+		}
+		if *retries < 0 {
+			return errors.New("--eventual-consistency-retries must not be negative")
 		}
 
-		exists, err := sty.str.Exists(nonFlagArgs[0])
+		exists, err := sty.waitForObject(ctx, args[0], *retries, *treat403AsAbsent)
 		if err == nil && !exists {
 			return &NotExistsError{}
 		}
@@ -86,57 +413,176 @@ func (sty *CommandExecuter) Execute(cmd string, nonFlagArgs []string) error {
 		}
 
 	case "sign":
-		if len(nonFlagArgs) != 3 {
-			return fmt.Errorf("sign method expects 3 arguments got %d", len(nonFlagArgs))
+		flags := flag.NewFlagSet("sign", flag.ContinueOnError)
+		contentType := flags.String("content-type", "", "require uploads to the signed put url to send this Content-Type")
+		contentMD5 := flags.String("content-md5", "", "require uploads to the signed put url to send this base64 encoded Content-MD5")
+		startAt := flags.String("start-at", "", "RFC3339 time before which the signed url is not valid, the expiration counts from it")
+		validate := flags.Bool("validate", false, "only check that the url can be signed and print when it would be valid instead of the url")
+		args, err := parseFlags(flags, nonFlagArgs)
+		if err != nil {
+			return err
+		}
+
+		if len(args) != 3 {
+			return fmt.Errorf("sign method expects 3 arguments got %d", len(args))
 		}
 
-		objectID, action := nonFlagArgs[0], nonFlagArgs[1]
+		objectID, action := args[0], args[1]
 		action = strings.ToLower(action)
 		if action != "get" && action != "put" {
 			return fmt.Errorf("action not implemented: %s. Available actions are 'get' and 'put'", action)
 		}
 
-		expiration, err := time.ParseDuration(nonFlagArgs[2])
+		expiration, err := time.ParseDuration(args[2])
 		if err != nil {
-			return fmt.Errorf("expiration should be in the format of a duration i.e. 1h, 60m, 3600s. Got: %s", nonFlagArgs[2])
+			return fmt.Errorf("expiration should be in the format of a duration i.e. 1h, 60m, 3600s. Got: %s", args[2])
+		}
+		if *validate && expiration <= 0 {
+			return fmt.Errorf("expiration must be positive. Got: %s", args[2])
+		}
+
+		var signedURL string
+		options := common.SignOptions{ContentType: *contentType, ContentMD5: *contentMD5}
+		if (options.ContentType != "" || options.ContentMD5 != "") && action != "put" {
+			return errors.New("--content-type and --content-md5 can only be used with the put action")
+		}
+		if *startAt != "" {
+			options.StartAt, err = time.Parse(time.RFC3339, *startAt)
+			if err != nil {
+				return fmt.Errorf("--start-at should be an RFC3339 time i.e. 2006-01-02T15:04:05Z. Got: %s", *startAt)
+			}
+		}
+		// Validating only checks the configuration, nothing is signed
+		if *validate {
+			if err := sty.str.ValidateSign(ctx, objectID, action, expiration, options); err != nil {
+				return fmt.Errorf("failed to sign request: %w", err)
+			}
+			return printJSON(newSignValidation(objectID, action, expiration, options.StartAt))
 		}
 
-		signedURL, err := sty.str.Sign(objectID, action, expiration)
+		if options != (common.SignOptions{}) {
+			signedURL, err = sty.str.SignWithOptions(ctx, objectID, action, expiration, options)
+		} else {
+			signedURL, err = sty.str.Sign(ctx, objectID, action, expiration)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to sign request: %w", err)
 		}
 		fmt.Print(signedURL)
 
 	case "list":
-		var prefix string
-		if len(nonFlagArgs) > 1 {
-			return fmt.Errorf("list method takes at most 1 argument (prefix) got %d", len(nonFlagArgs))
+		flags := flag.NewFlagSet("list", flag.ContinueOnError)
+		format := flags.String("list-format", defaultListFormat, "output format: default|s3cli-compat|json")
+		flags.StringVar(format, "format", defaultListFormat, "alias for --list-format")
+		failIfEmpty := flags.Bool("fail-if-empty", false, "exit with code 3 if no objects are found")
+		countOnly := flags.Bool("count-only", false, "print only the number of objects instead of their keys")
+		limit := flags.Int("limit", 0, "stop listing once this many objects have been found (0 lists all)")
+		checkCase := flags.Bool("warn-case-collisions", false, "log a warning for keys that differ only by case")
+		prefixes, err := parseFlags(flags, nonFlagArgs)
+		if err != nil {
+			return err
 		}
-		if len(nonFlagArgs) == 1 {
-			prefix = nonFlagArgs[0]
+		if len(prefixes) == 0 {
+			prefixes = []string{""}
 		}
 
-		var objects []string
-		objects, err := sty.str.List(prefix)
-		if err != nil {
-			return fmt.Errorf("failed to list objects: %w", err)
+		if *format != jsonListFormat && validateListFormat(*format) != nil {
+			return fmt.Errorf("unknown list format: '%s'. Available formats are '%s', '%s' and '%s'", *format, defaultListFormat, s3cliCompatListFormat, jsonListFormat)
+		}
+		if *limit < 0 {
+			return fmt.Errorf("--limit must not be negative, got %d", *limit)
 		}
 
-		for _, object := range objects {
-			fmt.Println(object)
+		var count int
+		if *format == jsonListFormat && !*countOnly {
+			objects, err := sty.listPrefixesDetailed(ctx, prefixes, *limit)
+			if err != nil {
+				return fmt.Errorf("failed to list objects: %w", err)
+			}
+			if err := printJSONList(objects); err != nil {
+				return err
+			}
+			count = len(objects)
+			if *checkCase {
+				keys := make([]string, 0, len(objects))
+				for _, object := range objects {
+					keys = append(keys, object.Key)
+				}
+				warnCaseCollisions(keys)
+			}
+		} else {
+			objects, err := sty.listPrefixes(ctx, prefixes, *limit)
+			if err != nil {
+				return fmt.Errorf("failed to list objects: %w", err)
+			}
+			if *countOnly {
+				fmt.Println(len(objects))
+			} else {
+				printList(objects, *format)
+			}
+			count = len(objects)
+			if *checkCase {
+				warnCaseCollisions(objects)
+			}
+		}
+
+		if *failIfEmpty && count == 0 {
+			return &EmptyListError{}
 		}
 
 	case "properties":
+		flags := flag.NewFlagSet("properties", flag.ContinueOnError)
+		format := flags.String("list-format", defaultListFormat, "output format: default|s3cli-compat")
+		rawETag := flags.Bool("raw-etag", false, "print the ETag exactly as the provider returns it, including the quotes")
+		args, err := parseFlags(flags, nonFlagArgs)
+		if err != nil {
+			return err
+		}
+
+		if len(args) != 1 {
+			return fmt.Errorf("properties method expected 1 argument got %d", len(args))
+		}
+		if err := validateListFormat(*format); err != nil {
+			return err
+		}
+
+		return sty.printProperties(ctx, args[0], *format, common.PropertiesOptions{RawETag: *rawETag})
+
+	case "head":
+		if len(nonFlagArgs) != 1 {
+			return fmt.Errorf("head method expected 1 argument got %d", len(nonFlagArgs))
+		}
+		return sty.printHead(ctx, nonFlagArgs[0])
+
+	case "size":
 		if len(nonFlagArgs) != 1 {
-			return fmt.Errorf("properties method expected 1 argument got %d", len(nonFlagArgs))
+			return fmt.Errorf("size method expected 1 argument got %d", len(nonFlagArgs))
 		}
-		return sty.str.Properties(nonFlagArgs[0])
+		size, err := sty.str.Size(ctx, nonFlagArgs[0])
+		if err != nil {
+			return fmt.Errorf("failed to get size: %w", err)
+		}
+		fmt.Println(size)
 
 	case "ensure-storage-exists":
 		if len(nonFlagArgs) != 0 {
 			return fmt.Errorf("ensureStorageExists method expected 0 argument got %d", len(nonFlagArgs))
 		}
-		return sty.str.EnsureStorageExists()
+		return sty.str.EnsureStorageExists(ctx)
+
+	case "whoami":
+		if len(nonFlagArgs) != 0 {
+			return fmt.Errorf("whoami method expected 0 arguments got %d", len(nonFlagArgs))
+		}
+		return printIdentity(ctx, sty.str)
+
+	case "capabilities":
+		if len(nonFlagArgs) != 0 {
+			return fmt.Errorf("capabilities method expected 0 arguments got %d", len(nonFlagArgs))
+		}
+		for _, capability := range sty.str.Capabilities() {
+			fmt.Println(capability)
+		}
 
 	default:
 		return fmt.Errorf("unknown command: '%s'", cmd)
@@ -144,3 +590,295 @@ func (sty *CommandExecuter) Execute(cmd string, nonFlagArgs []string) error {
 
 	return nil
 }
+
+func readUploadManifest(path string) (common.UploadManifest, error) {
+	manifestFile, err := os.Open(path)
+	if err != nil {
+		return common.UploadManifest{}, fmt.Errorf("failed to open manifest: %w", err)
+	}
+	defer manifestFile.Close() //nolint:errcheck
+
+	manifest, err := common.NewUploadManifestFromReader(manifestFile)
+	if err != nil {
+		return common.UploadManifest{}, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// eventualConsistencyBackoff is the wait before the first not-found retry, it doubles with every further retry
+var eventualConsistencyBackoff = 200 * time.Millisecond
+
+// waitForObject checks whether the object exists, retrying up to retries times while it is not found.
+// Eventually consistent stores may briefly report an object as missing right after it has been put.
+// Errors are returned right away, retrying those is left to the backends. With treat403AsAbsent
+// a denied access counts as not found, see common.ErrAccessDenied.
+func (sty *CommandExecuter) waitForObject(ctx context.Context, object string, retries int, treat403AsAbsent bool) (bool, error) {
+	backoff := eventualConsistencyBackoff
+	for attempt := 0; ; attempt++ {
+		exists, err := sty.str.Exists(ctx, object)
+		if treat403AsAbsent && errors.Is(err, common.ErrAccessDenied) {
+			slog.Debug("Access to object denied, treating it as not found", "object", object, "error", err)
+			exists, err = false, nil
+		}
+		if err != nil || exists || attempt == retries {
+			return exists, err
+		}
+
+		slog.Debug("Object not found yet, retrying", "object", object, "attempt", fmt.Sprintf("%d/%d", attempt+1, retries), "backoff", backoff)
+		common.IncRetries()
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// resumeState is the version of the object a partial download holds the beginning of
+type resumeState struct {
+	ETag      string `json:"etag,omitempty"`
+	VersionID string `json:"version_id,omitempty"`
+}
+
+// resumeGet downloads into <dst>.part, continuing from its current size if it already
+// exists, and moves it to dst once the object has been fully fetched. On failure the
+// partial file is kept so that a later invocation can pick up where this one stopped.
+// The version of the object is recorded in <dst>.part.version when the partial file is
+// started; a partial file of another version is discarded, and the ranged download fails
+// if the object changes while it runs. The complete file is verified against the
+// checksum of the object, if it has one.
+func (sty *CommandExecuter) resumeGet(ctx context.Context, src string, dst string, options common.GetOptions) error {
+	partFile := dst + ".part"
+	stateFile := partFile + ".version"
+
+	head, err := sty.str.Head(ctx, src)
+	if err != nil {
+		return fmt.Errorf("failed to get object version: %w", err)
+	}
+	state := resumeState{ETag: head.ETag, VersionID: head.VersionID}
+
+	var offset int64
+	info, err := os.Stat(partFile)
+	if err == nil {
+		offset = info.Size()
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to stat partial download: %w", err)
+	}
+	if offset > 0 {
+		var started resumeState
+		content, err := os.ReadFile(stateFile)
+		if err == nil {
+			err = json.Unmarshal(content, &started)
+		}
+		if err != nil || started != state {
+			slog.Info("Discarding partial download of another version of the object", "object", src, "local_path", partFile)
+			if err := os.Remove(partFile); err != nil {
+				return fmt.Errorf("failed to remove partial download: %w", err)
+			}
+			offset = 0
+		}
+	}
+	if offset == 0 {
+		content, err := json.Marshal(state)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(stateFile, content, 0644); err != nil {
+			return fmt.Errorf("failed to record object version: %w", err)
+		}
+	}
+
+	options.IfMatch = state.ETag
+	options.IfGenerationMatch = state.VersionID
+	if err := sty.str.GetRange(ctx, src, partFile, offset, options); err != nil {
+		if errors.Is(err, common.ErrPreconditionFailed) {
+			// The next invocation starts over with the current version
+			os.Remove(partFile)  //nolint:errcheck
+			os.Remove(stateFile) //nolint:errcheck
+		}
+		return err
+	}
+
+	if err := os.Rename(partFile, dst); err != nil {
+		return fmt.Errorf("failed to move partial download into place: %w", err)
+	}
+	if err := os.Remove(stateFile); err != nil && !os.IsNotExist(err) {
+		slog.Warn("Failed to remove the recorded object version", "file", stateFile, "error", err)
+	}
+
+	verification, err := newVerification(src, head)
+	if err != nil || verification == nil {
+		return err
+	}
+	return verification.verify(src, dst)
+}
+
+// move copies srcBlob to dstBlob server-side and deletes srcBlob once the copy exists,
+// so every backend that can copy can also move. The source is kept if the copy fails.
+func (sty *CommandExecuter) move(ctx context.Context, srcBlob string, dstBlob string) error {
+	if err := sty.str.Copy(ctx, srcBlob, dstBlob, common.CopyOptions{}); err != nil {
+		return fmt.Errorf("failed to copy %s to %s: %w", srcBlob, dstBlob, err)
+	}
+
+	if err := sty.str.Delete(ctx, srcBlob); err != nil {
+		return fmt.Errorf("copied %s to %s but failed to delete the source: %w", srcBlob, dstBlob, err)
+	}
+	return nil
+}
+
+// listPrefixes lists the objects under each of prefixes in turn and merges them, keeping the
+// first occurrence of keys that overlapping prefixes list more than once. A positive limit
+// caps the merged list.
+func (sty *CommandExecuter) listPrefixes(ctx context.Context, prefixes []string, limit int) ([]string, error) {
+	if len(prefixes) == 1 {
+		if limit > 0 {
+			return sty.str.ListWithLimit(ctx, prefixes[0], limit)
+		}
+		return sty.str.List(ctx, prefixes[0])
+	}
+
+	var merged []string
+	seen := map[string]bool{}
+	for _, prefix := range prefixes {
+		var (
+			objects []string
+			err     error
+		)
+		if limit > 0 {
+			// Keys already listed under an overlapping prefix come up again, list enough to get past them
+			listed := 0
+			for _, object := range merged {
+				if strings.HasPrefix(object, prefix) {
+					listed++
+				}
+			}
+			objects, err = sty.str.ListWithLimit(ctx, prefix, limit-len(merged)+listed)
+		} else {
+			objects, err = sty.str.List(ctx, prefix)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, object := range objects {
+			if seen[object] {
+				continue
+			}
+			seen[object] = true
+			merged = append(merged, object)
+			if len(merged) == limit {
+				return merged, nil
+			}
+		}
+	}
+	return merged, nil
+}
+
+// listPrefixesDetailed is listPrefixes for the json list format
+func (sty *CommandExecuter) listPrefixesDetailed(ctx context.Context, prefixes []string, limit int) ([]common.ObjectInfo, error) {
+	merged := []common.ObjectInfo{}
+	seen := map[string]bool{}
+	for _, prefix := range prefixes {
+		var (
+			objects []common.ObjectInfo
+			err     error
+		)
+		if limit > 0 {
+			// Keys already listed under an overlapping prefix come up again, list enough to get past them
+			listed := 0
+			for _, object := range merged {
+				if strings.HasPrefix(object.Key, prefix) {
+					listed++
+				}
+			}
+			objects, err = sty.str.ListDetailedWithLimit(ctx, prefix, limit-len(merged)+listed)
+		} else {
+			objects, err = sty.str.ListDetailed(ctx, prefix)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, object := range objects {
+			if seen[object.Key] {
+				continue
+			}
+			seen[object.Key] = true
+			merged = append(merged, object)
+			if len(merged) == limit {
+				return merged, nil
+			}
+		}
+	}
+	return merged, nil
+}
+
+const (
+	jsonPlanFormat  = "json"
+	linesPlanFormat = "lines"
+)
+
+func validatePlanFormat(format string) error {
+	switch format {
+	case jsonPlanFormat, linesPlanFormat:
+		return nil
+	default:
+		return fmt.Errorf("unknown format: '%s'. Available formats are '%s' and '%s'", format, jsonPlanFormat, linesPlanFormat)
+	}
+}
+
+type deleteRecursivePlan struct {
+	Keys      []string `json:"keys"`
+	Count     int      `json:"count"`
+	TotalSize int64    `json:"total_size"`
+}
+
+// newDeleteRecursivePlan plans the deletion of the given objects
+func newDeleteRecursivePlan(objects []common.ObjectInfo) deleteRecursivePlan {
+	plan := deleteRecursivePlan{Keys: make([]string, 0, len(objects)), Count: len(objects)}
+	for _, object := range objects {
+		plan.Keys = append(plan.Keys, object.Key)
+		plan.TotalSize += object.Size
+	}
+	return plan
+}
+
+// print prints the plan as JSON or, in the lines format, one key per line followed by
+// the count and total size
+func (plan deleteRecursivePlan) print(format string) error {
+	if format != linesPlanFormat {
+		return printJSON(plan)
+	}
+
+	for _, key := range plan.Keys {
+		fmt.Println(key)
+	}
+	fmt.Printf("total: %d objects, %d bytes\n", plan.Count, plan.TotalSize)
+	return nil
+}
+
+// printDeleteRecursivePlan prints the objects a delete-recursive with the same prefix would remove,
+// folder markers included
+func (sty *CommandExecuter) printDeleteRecursivePlan(ctx context.Context, prefix string, format string) error {
+	objects, err := sty.str.ListDetailed(ctx, prefix)
+	if err != nil {
+		return fmt.Errorf("failed to list objects: %w", err)
+	}
+
+	keys := make([]string, 0, len(objects))
+	for _, object := range objects {
+		keys = append(keys, object.Key)
+	}
+
+	// The markers are empty, so they don't add to the total size
+	markers, err := sty.existingFolderMarkers(ctx, prefix, keys)
+	if err != nil {
+		return err
+	}
+	for _, marker := range markers {
+		objects = append(objects, common.ObjectInfo{Key: marker})
+	}
+
+	return newDeleteRecursivePlan(objects).print(format)
+}
@@ -1,9 +1,20 @@
 package storage
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"log"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -27,64 +38,764 @@ var _ = Describe("Execute Command", func() {
 			DeferCleanup(func() {
 				os.Remove(tempFile.Name()) //nolint:errcheck
 			})
-			err := commandExecuter.Execute("put", []string{tempFile.Name(), "destination"})
+			err := commandExecuter.Execute(context.Background(), "put", []string{tempFile.Name(), "destination"})
 			Expect(fakeStorager.PutCallCount()).To(BeEquivalentTo(1))
 			Expect(err).ToNot(HaveOccurred())
 
 		})
 
 		It("No Source File", func() {
-			err := commandExecuter.Execute("put", []string{"source", "destination"})
+			err := commandExecuter.Execute(context.Background(), "put", []string{"source", "destination"})
 			Expect(errors.Unwrap(err).Error()).To(ContainSubstring("no such file or directory"))
 		})
 
 		It("Wrong number of parameters", func() {
-			err := commandExecuter.Execute("put", []string{"source"})
+			err := commandExecuter.Execute(context.Background(), "put", []string{"source"})
 			Expect(err.Error()).To(ContainSubstring("put method expected 2 arguments got"))
 		})
 
+		Context("from stdin", func() {
+			var uploaded []string
+
+			BeforeEach(func() {
+				uploaded = nil
+				DeferCleanup(func(original io.Reader, fromStdin bool) { stdin, configFromStdin = original, fromStdin }, stdin, configFromStdin)
+				stdin = strings.NewReader("generated on the fly")
+
+				upload := func(_ context.Context, src string, dst string, _ common.PutOptions) error {
+					content, err := os.ReadFile(src)
+					Expect(err).ToNot(HaveOccurred())
+					uploaded = append(uploaded, string(content))
+					return nil
+				}
+				fakeStorager.PutStub = upload
+				fakeStorager.PutWithETagStub = func(ctx context.Context, src string, dst string, options common.PutOptions) (string, error) {
+					return "some-etag", upload(ctx, src, dst, options)
+				}
+			})
+
+			It("uploads what is read from stdin for a source of -", func() {
+				err := commandExecuter.Execute(context.Background(), "put", []string{"-", "destination"})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(uploaded).To(Equal([]string{"generated on the fly"}))
+				_, src, dst, _ := fakeStorager.PutArgsForCall(0)
+				Expect(dst).To(Equal("destination"))
+				Expect(src).ToNot(BeAnExistingFile())
+			})
+
+			It("combines with the other put flags", func() {
+				output := captureStdout(func() {
+					err := commandExecuter.Execute(context.Background(), "put", []string{"--print-etag", "--max-upload-size", "20", "-", "destination"})
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				Expect(output).To(Equal("some-etag\n"))
+				Expect(uploaded).To(Equal([]string{"generated on the fly"}))
+			})
+
+			It("refuses stdin over the maximum upload size", func() {
+				err := commandExecuter.Execute(context.Background(), "put", []string{"--max-upload-size", "19", "-", "destination"})
+				Expect(err).To(MatchError("stdin exceeds the maximum upload size of 19 bytes"))
+				Expect(fakeStorager.PutCallCount()).To(BeEquivalentTo(0))
+			})
+
+			It("refuses stdin when the config was read from it", func() {
+				configFromStdin = true
+
+				err := commandExecuter.Execute(context.Background(), "put", []string{"-", "destination"})
+				Expect(err).To(MatchError(ContainSubstring("it was already used for the config (-c -)")))
+				Expect(fakeStorager.PutCallCount()).To(BeEquivalentTo(0))
+			})
+		})
+
+		Context("with --max-upload-size", func() {
+			var source string
+
+			BeforeEach(func() {
+				source = filepath.Join(GinkgoT().TempDir(), "source")
+				err := os.WriteFile(source, []byte("0123456789"), 0644)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("uploads files up to the limit", func() {
+				err := commandExecuter.Execute(context.Background(), "put", []string{"--max-upload-size", "10", source, "destination"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeStorager.PutCallCount()).To(BeEquivalentTo(1))
+			})
+
+			It("refuses files over the limit", func() {
+				err := commandExecuter.Execute(context.Background(), "put", []string{"--max-upload-size", "9", source, "destination"})
+				Expect(err).To(MatchError(ContainSubstring("is 10 bytes which exceeds the maximum upload size of 9 bytes")))
+				Expect(fakeStorager.PutCallCount()).To(BeEquivalentTo(0))
+			})
+
+			It("fails on an invalid size", func() {
+				err := commandExecuter.Execute(context.Background(), "put", []string{"--max-upload-size", "lots", source, "destination"})
+				Expect(err).To(HaveOccurred())
+				Expect(fakeStorager.PutCallCount()).To(BeEquivalentTo(0))
+			})
+		})
+
+		Context("with --manifest", func() {
+			var source, manifest string
+
+			BeforeEach(func() {
+				dir := GinkgoT().TempDir()
+				source = filepath.Join(dir, "source")
+				Expect(os.WriteFile(source, []byte("0123456789"), 0644)).To(Succeed())
+				manifest = filepath.Join(dir, "manifest.json")
+			})
+
+			It("uploads the file in the parts listed in the manifest", func() {
+				Expect(os.WriteFile(manifest, []byte(`{"parts": [{"part_number": 1, "offset": 0, "size": 6}, {"part_number": 2, "offset": 6, "size": 4}]}`), 0644)).To(Succeed())
+
+				err := commandExecuter.Execute(context.Background(), "put", []string{"--manifest", manifest, source, "destination"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeStorager.PutCallCount()).To(BeEquivalentTo(0))
+				Expect(fakeStorager.PutWithManifestCallCount()).To(BeEquivalentTo(1))
+
+				_, src, dst, parsed, _ := fakeStorager.PutWithManifestArgsForCall(0)
+				Expect(src).To(Equal(source))
+				Expect(dst).To(Equal("destination"))
+				Expect(parsed.Parts).To(Equal([]common.UploadPart{
+					{PartNumber: 1, Offset: 0, Size: 6},
+					{PartNumber: 2, Offset: 6, Size: 4},
+				}))
+			})
+
+			It("fails on a manifest that is not valid JSON", func() {
+				Expect(os.WriteFile(manifest, []byte(`parts`), 0644)).To(Succeed())
+
+				err := commandExecuter.Execute(context.Background(), "put", []string{"--manifest", manifest, source, "destination"})
+				Expect(err).To(MatchError(ContainSubstring("failed to read manifest")))
+				Expect(fakeStorager.PutWithManifestCallCount()).To(BeEquivalentTo(0))
+			})
+
+			It("fails on a missing manifest", func() {
+				err := commandExecuter.Execute(context.Background(), "put", []string{"--manifest", manifest, source, "destination"})
+				Expect(err).To(MatchError(ContainSubstring("failed to open manifest")))
+			})
+		})
+
+		Context("with --max-bandwidth", func() {
+			var source string
+
+			BeforeEach(func() {
+				source = filepath.Join(GinkgoT().TempDir(), "source")
+				Expect(os.WriteFile(source, []byte("0123456789"), 0644)).To(Succeed())
+			})
+
+			It("limits the bandwidth for the upload", func() {
+				err := commandExecuter.Execute(context.Background(), "put", []string{"--max-bandwidth", "1024", source, "destination"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeStorager.PutCallCount()).To(BeEquivalentTo(1))
+				_, _, _, options := fakeStorager.PutArgsForCall(0)
+				Expect(options.Bandwidth).ToNot(BeNil())
+			})
+
+			It("doesn't limit the bandwidth without it", func() {
+				err := commandExecuter.Execute(context.Background(), "put", []string{source, "destination"})
+				Expect(err).ToNot(HaveOccurred())
+				_, _, _, options := fakeStorager.PutArgsForCall(0)
+				Expect(options.Bandwidth).To(BeNil())
+			})
+
+			It("refuses a negative bandwidth", func() {
+				err := commandExecuter.Execute(context.Background(), "put", []string{"--max-bandwidth", "-1", source, "destination"})
+				Expect(err).To(MatchError("--max-bandwidth must not be negative"))
+				Expect(fakeStorager.PutCallCount()).To(BeEquivalentTo(0))
+			})
+		})
+
+		Context("content type", func() {
+			var contentType string
+
+			BeforeEach(func() {
+				contentType = ""
+				fakeStorager.PutStub = func(_ context.Context, _ string, _ string, options common.PutOptions) error {
+					contentType = options.ContentType
+					return nil
+				}
+			})
+
+			It("detects it from the file extension", func() {
+				source := filepath.Join(GinkgoT().TempDir(), "source.json")
+				Expect(os.WriteFile(source, []byte("{}"), 0644)).To(Succeed())
+
+				err := commandExecuter.Execute(context.Background(), "put", []string{source, "destination"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(contentType).To(Equal("application/json"))
+			})
+
+			It("sniffs it from the content without a known extension", func() {
+				source := filepath.Join(GinkgoT().TempDir(), "source")
+				Expect(os.WriteFile(source, []byte("<html><body></body></html>"), 0644)).To(Succeed())
+
+				err := commandExecuter.Execute(context.Background(), "put", []string{source, "destination"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(contentType).To(Equal("text/html; charset=utf-8"))
+			})
+
+			It("takes it from --content-type", func() {
+				source := filepath.Join(GinkgoT().TempDir(), "source.json")
+				Expect(os.WriteFile(source, []byte("{}"), 0644)).To(Succeed())
+
+				err := commandExecuter.Execute(context.Background(), "put", []string{"--content-type", "text/csv", source, "destination"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(contentType).To(Equal("text/csv"))
+			})
+		})
+
+		Context("with --store-md5", func() {
+			var (
+				source   string
+				metadata map[string]string
+			)
+
+			BeforeEach(func() {
+				source = filepath.Join(GinkgoT().TempDir(), "source")
+				Expect(os.WriteFile(source, []byte("0123456789"), 0644)).To(Succeed())
+
+				metadata = nil
+				fakeStorager.PutStub = func(_ context.Context, _ string, _ string, options common.PutOptions) error {
+					metadata = options.Metadata
+					return nil
+				}
+			})
+
+			It("stores the MD5 of the file as metadata", func() {
+				err := commandExecuter.Execute(context.Background(), "put", []string{"--store-md5", source, "destination"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(metadata).To(Equal(map[string]string{"md5": "781e5e245d69b566979b86e28d23f2c7"}))
+			})
+
+			It("stores no metadata without it", func() {
+				err := commandExecuter.Execute(context.Background(), "put", []string{source, "destination"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(metadata).To(BeNil())
+			})
+		})
+
+		Context("with --meta", func() {
+			var (
+				source   string
+				metadata map[string]string
+			)
+
+			BeforeEach(func() {
+				source = filepath.Join(GinkgoT().TempDir(), "source")
+				Expect(os.WriteFile(source, []byte("0123456789"), 0644)).To(Succeed())
+
+				metadata = nil
+				fakeStorager.PutStub = func(_ context.Context, _ string, _ string, options common.PutOptions) error {
+					metadata = options.Metadata
+					return nil
+				}
+			})
+
+			It("stores every given pair as metadata", func() {
+				err := commandExecuter.Execute(context.Background(), "put", []string{"--meta", "owner=team-a", "--meta", "build_id=42=final", "--meta", "empty=", source, "destination"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(metadata).To(Equal(map[string]string{"owner": "team-a", "build_id": "42=final", "empty": ""}))
+			})
+
+			It("stores the pairs along with the MD5 of --store-md5", func() {
+				err := commandExecuter.Execute(context.Background(), "put", []string{"--meta", "owner=team-a", "--store-md5", source, "destination"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(metadata).To(Equal(map[string]string{"owner": "team-a", "md5": "781e5e245d69b566979b86e28d23f2c7"}))
+			})
+
+			It("refuses to overwrite the MD5 of --store-md5", func() {
+				err := commandExecuter.Execute(context.Background(), "put", []string{"--meta", "MD5=something", "--store-md5", source, "destination"})
+				Expect(err).To(MatchError("--meta md5 can't be combined with --store-md5"))
+				Expect(fakeStorager.PutCallCount()).To(BeZero())
+			})
+
+			DescribeTable("rejects malformed pairs",
+				func(pair string, message string) {
+					err := commandExecuter.Execute(context.Background(), "put", []string{"--meta", pair, source, "destination"})
+					Expect(err).To(MatchError(ContainSubstring(message)))
+					Expect(fakeStorager.PutCallCount()).To(BeZero())
+				},
+				Entry("without a value", "owner", "metadata 'owner' is not of the form key=value"),
+				Entry("without a key", "=team-a", "invalid metadata key ''"),
+				Entry("with a space in the key", "the owner=team-a", "invalid metadata key 'the owner'"),
+				Entry("with a non-ASCII key", "ownér=team-a", "invalid metadata key 'ownér'"),
+				Entry("with a newline in the value", "owner=team\na", "invalid metadata value for key 'owner'"),
+			)
+
+			It("rejects keys given twice, ignoring case", func() {
+				err := commandExecuter.Execute(context.Background(), "put", []string{"--meta", "owner=a", "--meta", "Owner=b", source, "destination"})
+				Expect(err).To(MatchError(ContainSubstring("metadata key 'Owner' is given more than once")))
+				Expect(fakeStorager.PutCallCount()).To(BeZero())
+			})
+		})
+
+		Context("with --content-addressed", func() {
+			const sha256OfSource = "84d89877f0d4041efb6bf91a16f0248f2fd573e6af05c19f96bedb9f882f7882"
+			var source string
+
+			BeforeEach(func() {
+				source = filepath.Join(GinkgoT().TempDir(), "source")
+				Expect(os.WriteFile(source, []byte("0123456789"), 0644)).To(Succeed())
+			})
+
+			It("uploads to the SHA256 of the content and prints the key", func() {
+				var err error
+				out := captureStdout(func() {
+					err = commandExecuter.Execute(context.Background(), "put", []string{"--content-addressed", source})
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(out).To(Equal(sha256OfSource + "\n"))
+
+				_, key := fakeStorager.ExistsArgsForCall(0)
+
+				Expect(key).To(Equal(sha256OfSource))
+				Expect(fakeStorager.PutCallCount()).To(BeEquivalentTo(1))
+				_, src, dst, _ := fakeStorager.PutArgsForCall(0)
+				Expect(src).To(Equal(source))
+				Expect(dst).To(Equal(sha256OfSource))
+			})
+
+			It("puts the key prefix in front of the hash", func() {
+				var err error
+				out := captureStdout(func() {
+					err = commandExecuter.Execute(context.Background(), "put", []string{"--content-addressed", source, "cache/sha256-"})
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(out).To(Equal("cache/sha256-" + sha256OfSource + "\n"))
+
+				_, _, dst, _ := fakeStorager.PutArgsForCall(0)
+				Expect(dst).To(Equal("cache/sha256-" + sha256OfSource))
+			})
+
+			It("skips the upload if the content is already stored", func() {
+				fakeStorager.ExistsReturns(true, nil)
+
+				var err error
+				out := captureStdout(func() {
+					err = commandExecuter.Execute(context.Background(), "put", []string{"--content-addressed", source})
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(out).To(Equal(sha256OfSource + "\n"))
+				Expect(fakeStorager.PutCallCount()).To(BeEquivalentTo(0))
+			})
+
+			It("fails if the existence check fails", func() {
+				fakeStorager.ExistsReturns(false, errors.New("boom"))
+
+				captureStdout(func() {
+					err := commandExecuter.Execute(context.Background(), "put", []string{"--content-addressed", source})
+					Expect(err).To(MatchError(ContainSubstring("boom")))
+				})
+				Expect(fakeStorager.PutCallCount()).To(BeEquivalentTo(0))
+			})
+
+			It("refuses more than a key prefix", func() {
+				err := commandExecuter.Execute(context.Background(), "put", []string{"--content-addressed", source, "prefix", "extra"})
+				Expect(err).To(MatchError(ContainSubstring("put method with --content-addressed expected 1 or 2 arguments got 3")))
+			})
+		})
+
+		Context("with --print-etag", func() {
+			var source string
+
+			BeforeEach(func() {
+				source = filepath.Join(GinkgoT().TempDir(), "source")
+				Expect(os.WriteFile(source, []byte("0123456789"), 0644)).To(Succeed())
+			})
+
+			It("prints the ETag of the uploaded object", func() {
+				fakeStorager.PutWithETagReturns("some-etag", nil)
+
+				output := captureStdout(func() {
+					err := commandExecuter.Execute(context.Background(), "put", []string{"--print-etag", source, "destination"})
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				Expect(output).To(Equal("some-etag\n"))
+				Expect(fakeStorager.PutCallCount()).To(BeEquivalentTo(0))
+				Expect(fakeStorager.PutWithETagCallCount()).To(BeEquivalentTo(1))
+				_, uploaded, dest, _ := fakeStorager.PutWithETagArgsForCall(0)
+				Expect(uploaded).To(Equal(source))
+				Expect(dest).To(Equal("destination"))
+			})
+
+			It("returns the upload error", func() {
+				fakeStorager.PutWithETagReturns("", errors.New("boom"))
+
+				err := commandExecuter.Execute(context.Background(), "put", []string{"--print-etag", source, "destination"})
+				Expect(err).To(MatchError("boom"))
+			})
+
+			It("can't be combined with --manifest", func() {
+				err := commandExecuter.Execute(context.Background(), "put", []string{"--print-etag", "--manifest", "manifest.json", source, "destination"})
+				Expect(err).To(MatchError("--print-etag can't be combined with --manifest"))
+				Expect(fakeStorager.PutWithETagCallCount()).To(BeEquivalentTo(0))
+			})
+		})
+
 	})
 
 	Context("Get", func() {
 		It("Successfull", func() {
-			err := commandExecuter.Execute("get", []string{"source", "destination"})
+			err := commandExecuter.Execute(context.Background(), "get", []string{"source", "destination"})
 			Expect(fakeStorager.GetCallCount()).To(BeEquivalentTo(1))
 			Expect(err).ToNot(HaveOccurred())
 
 		})
 
 		It("Wrong number of parameters", func() {
-			err := commandExecuter.Execute("get", []string{"source"})
+			err := commandExecuter.Execute(context.Background(), "get", []string{"source"})
 			Expect(err.Error()).To(ContainSubstring("get method expected 2 arguments got"))
 		})
 
+		Context("to a nested destination", func() {
+			var dst string
+
+			BeforeEach(func() {
+				dst = filepath.Join(GinkgoT().TempDir(), "a", "b", "c.txt")
+				fakeStorager.GetStub = func(_ context.Context, src string, dest string, _ common.GetOptions) error {
+					f, err := os.Create(dest)
+					if err != nil {
+						return err
+					}
+					return f.Close()
+				}
+			})
+
+			It("creates the missing parent directories", func() {
+				err := commandExecuter.Execute(context.Background(), "get", []string{"source", dst})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(dst).To(BeARegularFile())
+			})
+
+			It("fails for missing parent directories with --no-mkdir", func() {
+				err := commandExecuter.Execute(context.Background(), "get", []string{"--no-mkdir", "source", dst})
+				Expect(err).To(MatchError(ContainSubstring("no such file or directory")))
+				Expect(filepath.Dir(dst)).ToNot(BeADirectory())
+			})
+
+			It("accepts the flags after the arguments", func() {
+				err := commandExecuter.Execute(context.Background(), "get", []string{"source", dst, "--no-mkdir"})
+				Expect(err).To(MatchError(ContainSubstring("no such file or directory")))
+				Expect(filepath.Dir(dst)).ToNot(BeADirectory())
+			})
+		})
+
+		Context("with --continue", func() {
+			var dst string
+
+			BeforeEach(func() {
+				dst = filepath.Join(GinkgoT().TempDir(), "destination")
+			})
+
+			It("resumes from the size of an existing partial file", func() {
+				fakeStorager.HeadReturns(common.ObjectHead{ETag: "some-etag", VersionID: "1"}, nil)
+				err := os.WriteFile(dst+".part", []byte("12345"), 0644)
+				Expect(err).ToNot(HaveOccurred())
+				err = os.WriteFile(dst+".part.version", []byte(`{"etag":"some-etag","version_id":"1"}`), 0644)
+				Expect(err).ToNot(HaveOccurred())
+
+				fakeStorager.GetRangeStub = func(_ context.Context, src string, dest string, offset int64, _ common.GetOptions) error {
+					f, err := os.OpenFile(dest, os.O_WRONLY|os.O_APPEND, 0644)
+					Expect(err).ToNot(HaveOccurred())
+					defer f.Close() //nolint:errcheck
+					_, err = f.WriteString("67890")
+					return err
+				}
+
+				err = commandExecuter.Execute(context.Background(), "get", []string{"--continue", "source", dst})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fakeStorager.GetCallCount()).To(BeZero())
+				Expect(fakeStorager.GetRangeCallCount()).To(Equal(1))
+				_, src, dest, offset, options := fakeStorager.GetRangeArgsForCall(0)
+				Expect(src).To(Equal("source"))
+				Expect(dest).To(Equal(dst + ".part"))
+				Expect(offset).To(BeEquivalentTo(5))
+				Expect(options.IfMatch).To(Equal("some-etag"))
+				Expect(options.IfGenerationMatch).To(Equal("1"))
+
+				content, err := os.ReadFile(dst)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(content)).To(Equal("1234567890"))
+				Expect(dst + ".part").ToNot(BeAnExistingFile())
+				Expect(dst + ".part.version").ToNot(BeAnExistingFile())
+			})
+
+			It("starts over when the partial file is of another version of the object", func() {
+				fakeStorager.HeadReturns(common.ObjectHead{ETag: "new-etag"}, nil)
+				err := os.WriteFile(dst+".part", []byte("12345"), 0644)
+				Expect(err).ToNot(HaveOccurred())
+				err = os.WriteFile(dst+".part.version", []byte(`{"etag":"old-etag"}`), 0644)
+				Expect(err).ToNot(HaveOccurred())
+				fakeStorager.GetRangeStub = func(_ context.Context, src string, dest string, offset int64, _ common.GetOptions) error {
+					Expect(dest).ToNot(BeAnExistingFile())
+					return os.WriteFile(dest, []byte("abcde"), 0644)
+				}
+
+				err = commandExecuter.Execute(context.Background(), "get", []string{"--continue", "source", dst})
+				Expect(err).ToNot(HaveOccurred())
+
+				_, _, _, offset, options := fakeStorager.GetRangeArgsForCall(0)
+				Expect(offset).To(BeZero())
+				Expect(options.IfMatch).To(Equal("new-etag"))
+				content, err := os.ReadFile(dst)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(content)).To(Equal("abcde"))
+			})
+
+			It("starts over when the version of the partial file was not recorded", func() {
+				err := os.WriteFile(dst+".part", []byte("12345"), 0644)
+				Expect(err).ToNot(HaveOccurred())
+				fakeStorager.GetRangeStub = func(_ context.Context, src string, dest string, offset int64, _ common.GetOptions) error {
+					return os.WriteFile(dest, []byte("1234567890"), 0644)
+				}
+
+				err = commandExecuter.Execute(context.Background(), "get", []string{"--continue", "source", dst})
+				Expect(err).ToNot(HaveOccurred())
+
+				_, _, _, offset, _ := fakeStorager.GetRangeArgsForCall(0)
+				Expect(offset).To(BeZero())
+			})
+
+			It("discards the partial file when the object changes during the download", func() {
+				fakeStorager.GetRangeStub = func(_ context.Context, src string, dest string, offset int64, _ common.GetOptions) error {
+					Expect(os.WriteFile(dest, []byte("12345"), 0644)).To(Succeed())
+					return fmt.Errorf("failed to get object range: %w", common.ErrPreconditionFailed)
+				}
+
+				err := commandExecuter.Execute(context.Background(), "get", []string{"--continue", "source", dst})
+				Expect(err).To(MatchError(common.ErrPreconditionFailed))
+
+				Expect(dst + ".part").ToNot(BeAnExistingFile())
+				Expect(dst + ".part.version").ToNot(BeAnExistingFile())
+				Expect(dst).ToNot(BeAnExistingFile())
+			})
+
+			It("verifies the complete file against the checksum of the object", func() {
+				// MD5 of "1234567890"
+				fakeStorager.HeadReturns(common.ObjectHead{ContentMD5: "6Afx/PgtEy+bsBjKZzihnw=="}, nil)
+				fakeStorager.GetRangeStub = func(_ context.Context, src string, dest string, offset int64, _ common.GetOptions) error {
+					return os.WriteFile(dest, []byte("1234567899"), 0644)
+				}
+
+				err := commandExecuter.Execute(context.Background(), "get", []string{"--continue", "source", dst})
+				Expect(err).To(MatchError(ContainSubstring("md5 checksum mismatch for source")))
+				Expect(dst).ToNot(BeAnExistingFile())
+			})
+
+			It("starts from the beginning when there is no partial file", func() {
+				fakeStorager.GetRangeStub = func(_ context.Context, src string, dest string, offset int64, _ common.GetOptions) error {
+					return os.WriteFile(dest, []byte("1234567890"), 0644)
+				}
+
+				err := commandExecuter.Execute(context.Background(), "get", []string{"--continue", "source", dst})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fakeStorager.GetRangeCallCount()).To(Equal(1))
+				_, _, _, offset, _ := fakeStorager.GetRangeArgsForCall(0)
+				Expect(offset).To(BeZero())
+			})
+
+			It("keeps the partial file when the download fails", func() {
+				err := os.WriteFile(dst+".part", []byte("12345"), 0644)
+				Expect(err).ToNot(HaveOccurred())
+				err = os.WriteFile(dst+".part.version", []byte("{}"), 0644)
+				Expect(err).ToNot(HaveOccurred())
+				fakeStorager.GetRangeReturns(errors.New("connection reset"))
+
+				err = commandExecuter.Execute(context.Background(), "get", []string{"--continue", "source", dst})
+				Expect(err).To(MatchError("connection reset"))
+
+				Expect(dst + ".part").To(BeAnExistingFile())
+				Expect(dst).ToNot(BeAnExistingFile())
+			})
+
+			It("Wrong number of parameters", func() {
+				err := commandExecuter.Execute(context.Background(), "get", []string{"--continue", "source"})
+				Expect(err.Error()).To(ContainSubstring("get method expected 2 arguments got 1"))
+			})
+		})
+
 	})
 
 	Context("Copy", func() {
 		It("Successfull", func() {
-			err := commandExecuter.Execute("copy", []string{"source", "destination"})
+			err := commandExecuter.Execute(context.Background(), "copy", []string{"source", "destination"})
 			Expect(fakeStorager.CopyCallCount()).To(BeEquivalentTo(1))
 			Expect(err).ToNot(HaveOccurred())
 
 		})
 
 		It("Wrong number of parameters", func() {
-			err := commandExecuter.Execute("copy", []string{"source"})
+			err := commandExecuter.Execute(context.Background(), "copy", []string{"source"})
 			Expect(err.Error()).To(ContainSubstring("copy method expected 2 arguments got"))
 		})
 
+		It("passes the --source-sas to the copy", func() {
+			err := commandExecuter.Execute(context.Background(), "copy", []string{"--source-sas", "sv=2022-11-02&sig=some-signature", "https://other-account.blob.core.windows.net/c/source", "destination"})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, _, _, options := fakeStorager.CopyArgsForCall(0)
+			Expect(options.SourceSAS).To(Equal("sv=2022-11-02&sig=some-signature"))
+		})
+
+		It("disables multipart copies with --no-multipart-copy", func() {
+			err := commandExecuter.Execute(context.Background(), "copy", []string{"--no-multipart-copy", "source", "destination"})
+			Expect(err).ToNot(HaveOccurred())
+			_, _, _, options := fakeStorager.CopyArgsForCall(0)
+			Expect(options.NoMultipart).To(BeTrue())
+
+			err = commandExecuter.Execute(context.Background(), "copy", []string{"source", "destination"})
+			Expect(err).ToNot(HaveOccurred())
+			_, _, _, options = fakeStorager.CopyArgsForCall(1)
+			Expect(options.NoMultipart).To(BeFalse())
+		})
+
+		It("copies from another bucket with --source-bucket", func() {
+			err := commandExecuter.Execute(context.Background(), "copy", []string{"--source-bucket", "other-bucket", "--source-region", "eu-west-1", "source", "destination"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeStorager.CopyCallCount()).To(BeEquivalentTo(0))
+			Expect(fakeStorager.CopyFromBucketCallCount()).To(BeEquivalentTo(1))
+
+			_, bucket, region, src, dst, options := fakeStorager.CopyFromBucketArgsForCall(0)
+			Expect(bucket).To(Equal("other-bucket"))
+			Expect(region).To(Equal("eu-west-1"))
+			Expect(src).To(Equal("source"))
+			Expect(dst).To(Equal("destination"))
+			Expect(options.ResetMetadata).To(BeFalse())
+		})
+
+		It("preserves the source's metadata by default", func() {
+			err := commandExecuter.Execute(context.Background(), "copy", []string{"source", "destination"})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, _, _, options := fakeStorager.CopyArgsForCall(0)
+			Expect(options.ResetMetadata).To(BeFalse())
+		})
+
+		It("resets the metadata with --overwrite-metadata-on-copy", func() {
+			err := commandExecuter.Execute(context.Background(), "copy", []string{"--overwrite-metadata-on-copy", "source", "destination"})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, src, dst, options := fakeStorager.CopyArgsForCall(0)
+			Expect(src).To(Equal("source"))
+			Expect(dst).To(Equal("destination"))
+			Expect(options.ResetMetadata).To(BeTrue())
+		})
+
+		It("rejects --source-region without --source-bucket", func() {
+			err := commandExecuter.Execute(context.Background(), "copy", []string{"--source-region", "eu-west-1", "source", "destination"})
+			Expect(err).To(MatchError("--source-region requires --source-bucket"))
+			Expect(fakeStorager.CopyCallCount()).To(BeEquivalentTo(0))
+		})
+
+		It("copies into another bucket with --dest-bucket", func() {
+			err := commandExecuter.Execute(context.Background(), "copy", []string{"--dest-bucket", "other-bucket", "--overwrite-metadata-on-copy", "source", "destination"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeStorager.CopyCallCount()).To(BeEquivalentTo(0))
+			Expect(fakeStorager.CopyToBucketCallCount()).To(BeEquivalentTo(1))
+
+			_, src, bucket, dst, options := fakeStorager.CopyToBucketArgsForCall(0)
+			Expect(src).To(Equal("source"))
+			Expect(bucket).To(Equal("other-bucket"))
+			Expect(dst).To(Equal("destination"))
+			Expect(options.ResetMetadata).To(BeTrue())
+		})
+
+		It("accepts --dest-container as an alias of --dest-bucket", func() {
+			err := commandExecuter.Execute(context.Background(), "copy", []string{"--dest-container", "other-container", "source", "destination"})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, _, container, _, _ := fakeStorager.CopyToBucketArgsForCall(0)
+			Expect(container).To(Equal("other-container"))
+		})
+
+		It("rejects --dest-bucket together with --source-bucket", func() {
+			err := commandExecuter.Execute(context.Background(), "copy", []string{"--dest-bucket", "other-bucket", "--source-bucket", "third-bucket", "source", "destination"})
+			Expect(err).To(MatchError("--dest-bucket can't be combined with --source-bucket or --source-region"))
+			Expect(fakeStorager.CopyToBucketCallCount()).To(BeEquivalentTo(0))
+			Expect(fakeStorager.CopyFromBucketCallCount()).To(BeEquivalentTo(0))
+		})
+
+	})
+
+	Context("Rename", func() {
+		It("Successfull", func() {
+			err := commandExecuter.Execute(context.Background(), "rename", []string{"source", "destination"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeStorager.RenameCallCount()).To(BeEquivalentTo(1))
+			_, src, dst := fakeStorager.RenameArgsForCall(0)
+			Expect(src).To(Equal("source"))
+			Expect(dst).To(Equal("destination"))
+		})
+
+		It("Wrong number of parameters", func() {
+			err := commandExecuter.Execute(context.Background(), "rename", []string{"source"})
+			Expect(err.Error()).To(ContainSubstring("rename method expected 2 arguments got"))
+		})
+
+	})
+
+	Context("Move", func() {
+		It("copies the object and deletes the source", func() {
+			err := commandExecuter.Execute(context.Background(), "move", []string{"source", "destination"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeStorager.CopyCallCount()).To(BeEquivalentTo(1))
+			_, src, dst, options := fakeStorager.CopyArgsForCall(0)
+			Expect(src).To(Equal("source"))
+			Expect(dst).To(Equal("destination"))
+			Expect(options.ResetMetadata).To(BeFalse())
+
+			Expect(fakeStorager.DeleteCallCount()).To(BeEquivalentTo(1))
+			_, key := fakeStorager.DeleteArgsForCall(0)
+
+			Expect(key).To(Equal("source"))
+		})
+
+		It("is available as mv", func() {
+			err := commandExecuter.Execute(context.Background(), "mv", []string{"source", "destination"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeStorager.CopyCallCount()).To(BeEquivalentTo(1))
+			Expect(fakeStorager.DeleteCallCount()).To(BeEquivalentTo(1))
+		})
+
+		It("keeps the source if the copy fails", func() {
+			fakeStorager.CopyReturns(errors.New("boom"))
+
+			err := commandExecuter.Execute(context.Background(), "move", []string{"source", "destination"})
+			Expect(err).To(MatchError("failed to copy source to destination: boom"))
+			Expect(fakeStorager.DeleteCallCount()).To(BeEquivalentTo(0))
+		})
+
+		It("reports a source that could not be deleted", func() {
+			fakeStorager.DeleteReturns(errors.New("boom"))
+
+			err := commandExecuter.Execute(context.Background(), "move", []string{"source", "destination"})
+			Expect(err).To(MatchError("copied source to destination but failed to delete the source: boom"))
+		})
+
+		It("Wrong number of parameters", func() {
+			err := commandExecuter.Execute(context.Background(), "move", []string{"source"})
+			Expect(err).To(MatchError("move method expected 2 arguments got 1"))
+			Expect(fakeStorager.CopyCallCount()).To(BeEquivalentTo(0))
+		})
 	})
 
 	Context("Delete", func() {
 		It("Successfull", func() {
-			err := commandExecuter.Execute("delete", []string{"destination"})
+			err := commandExecuter.Execute(context.Background(), "delete", []string{"destination"})
 			Expect(fakeStorager.DeleteCallCount()).To(BeEquivalentTo(1))
 			Expect(err).ToNot(HaveOccurred())
 
 		})
 
 		It("Wrong number of parameters", func() {
-			err := commandExecuter.Execute("delete", []string{})
+			err := commandExecuter.Execute(context.Background(), "delete", []string{})
 			Expect(err.Error()).To(ContainSubstring("delete method expected 1 argument got"))
 		})
 
@@ -92,34 +803,222 @@ var _ = Describe("Execute Command", func() {
 
 	Context("Delete-Recursive", func() {
 		It("Successfull", func() {
-			err := commandExecuter.Execute("delete-recursive", []string{})
+			err := commandExecuter.Execute(context.Background(), "delete-recursive", []string{})
 			Expect(fakeStorager.DeleteRecursiveCallCount()).To(BeEquivalentTo(1))
-			Expect(fakeStorager.deleteRecursiveArgsForCall[0].arg1).To(Equal(""))
+			Expect(fakeStorager.deleteRecursiveArgsForCall[0].arg2).To(Equal(""))
 			Expect(err).ToNot(HaveOccurred())
 
 		})
 
 		It("Successfull With Prefix", func() {
-			err := commandExecuter.Execute("delete-recursive", []string{"prefix"})
+			err := commandExecuter.Execute(context.Background(), "delete-recursive", []string{"prefix"})
 			Expect(fakeStorager.DeleteRecursiveCallCount()).To(BeEquivalentTo(1))
-			Expect(fakeStorager.deleteRecursiveArgsForCall[0].arg1).To(Equal("prefix"))
+			Expect(fakeStorager.deleteRecursiveArgsForCall[0].arg2).To(Equal("prefix"))
+			Expect(err).ToNot(HaveOccurred())
+
+		})
+
+		It("Fails fast by default", func() {
+			err := commandExecuter.Execute(context.Background(), "delete-recursive", []string{"prefix"})
 			Expect(err).ToNot(HaveOccurred())
+			_, _, options := fakeStorager.DeleteRecursiveArgsForCall(0)
+			Expect(options.ContinueOnError).To(BeFalse())
+		})
+
+		It("Continues on error with --continue-on-error", func() {
+			err := commandExecuter.Execute(context.Background(), "delete-recursive", []string{"--continue-on-error", "prefix"})
+			Expect(err).ToNot(HaveOccurred())
+			_, prefix, options := fakeStorager.DeleteRecursiveArgsForCall(0)
+			Expect(prefix).To(Equal("prefix"))
+			Expect(options.ContinueOnError).To(BeTrue())
+		})
+
+		It("Lets the last of --continue-on-error and --fail-fast win", func() {
+			err := commandExecuter.Execute(context.Background(), "delete-recursive", []string{"--continue-on-error", "--fail-fast", "prefix"})
+			Expect(err).ToNot(HaveOccurred())
+			_, _, options := fakeStorager.DeleteRecursiveArgsForCall(0)
+			Expect(options.ContinueOnError).To(BeFalse())
+		})
 
+		It("Passes --concurrency on to the storage type", func() {
+			Expect(commandExecuter.Execute(context.Background(), "delete-recursive", []string{"--concurrency", "20", "prefix"})).To(Succeed())
+			Expect(fakeStorager.DeleteRecursiveCallCount()).To(Equal(1))
+			_, _, options := fakeStorager.DeleteRecursiveArgsForCall(0)
+			Expect(options.ConcurrencyOr(5)).To(Equal(20))
+		})
+
+		It("Keeps the storage type's default concurrency without --concurrency", func() {
+			Expect(commandExecuter.Execute(context.Background(), "delete-recursive", []string{"prefix"})).To(Succeed())
+			_, _, options := fakeStorager.DeleteRecursiveArgsForCall(0)
+			Expect(options.ConcurrencyOr(5)).To(Equal(5))
+		})
+
+		It("Refuses a negative --concurrency", func() {
+			err := commandExecuter.Execute(context.Background(), "delete-recursive", []string{"--concurrency", "-1", "prefix"})
+			Expect(err).To(MatchError("--concurrency must not be negative, got -1"))
+			Expect(fakeStorager.DeleteRecursiveCallCount()).To(Equal(0))
 		})
 
 		It("Wrong number of parameters", func() {
-			err := commandExecuter.Execute("delete-recursive", []string{"prefix", "extra-prefix"})
+			err := commandExecuter.Execute(context.Background(), "delete-recursive", []string{"prefix", "extra-prefix"})
 			Expect(err.Error()).To(ContainSubstring("delete-recursive takes at most 1 argument (prefix) got"))
 		})
 
+		It("Prints the objects instead of deleting them with --dry-run", func() {
+			fakeStorager.ListDetailedReturns([]common.ObjectInfo{{Key: "prefix/a", Size: 3}, {Key: "prefix/b", Size: 4}}, nil)
+
+			var err error
+			out := captureStdout(func() {
+				err = commandExecuter.Execute(context.Background(), "delete-recursive", []string{"--dry-run", "prefix"})
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeStorager.DeleteRecursiveCallCount()).To(BeEquivalentTo(0))
+			_, prefix := fakeStorager.ListDetailedArgsForCall(0)
+
+			Expect(prefix).To(Equal("prefix"))
+			Expect(out).To(MatchJSON(`{"keys": ["prefix/a", "prefix/b"], "count": 2, "total_size": 7}`))
+		})
+
+		It("Prints one key per line and the totals with --dry-run --format lines", func() {
+			fakeStorager.ListDetailedReturns([]common.ObjectInfo{{Key: "prefix/a", Size: 3}, {Key: "prefix/b", Size: 4}}, nil)
+
+			var err error
+			out := captureStdout(func() {
+				err = commandExecuter.Execute(context.Background(), "delete-recursive", []string{"--dry-run", "--format", "lines", "prefix"})
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeStorager.DeleteRecursiveCallCount()).To(BeEquivalentTo(0))
+			Expect(out).To(Equal("prefix/a\nprefix/b\ntotal: 2 objects, 7 bytes\n"))
+		})
+
+		It("Refuses an unknown --format", func() {
+			err := commandExecuter.Execute(context.Background(), "delete-recursive", []string{"--dry-run", "--format", "yaml", "prefix"})
+			Expect(err).To(MatchError("unknown format: 'yaml'. Available formats are 'json' and 'lines'"))
+			Expect(fakeStorager.ListDetailedCallCount()).To(Equal(0))
+		})
+
+		Context("with folder markers", func() {
+			var deleted []string
+
+			BeforeEach(func() {
+				deleted = nil
+				fakeStorager.ExistsStub = func(_ context.Context, key string) (bool, error) {
+					return key == "logs" || key == "logs/", nil
+				}
+				fakeStorager.DeleteStub = func(_ context.Context, key string) error {
+					deleted = append(deleted, key)
+					return nil
+				}
+			})
+
+			It("deletes the folder markers alongside the objects", func() {
+				Expect(commandExecuter.Execute(context.Background(), "delete-recursive", []string{"logs/"})).To(Succeed())
+				Expect(fakeStorager.DeleteRecursiveCallCount()).To(Equal(1))
+				Expect(deleted).To(Equal([]string{"logs", "logs/"}))
+			})
+
+			It("leaves the markers the recursive delete removed alone", func() {
+				fakeStorager.ExistsReturns(false, nil)
+				fakeStorager.ExistsStub = nil
+
+				Expect(commandExecuter.Execute(context.Background(), "delete-recursive", []string{"logs"})).To(Succeed())
+				Expect(fakeStorager.ExistsCallCount()).To(Equal(2))
+				Expect(deleted).To(BeEmpty())
+			})
+
+			It("doesn't look for markers of the bucket root", func() {
+				Expect(commandExecuter.Execute(context.Background(), "delete-recursive", []string{})).To(Succeed())
+				Expect(fakeStorager.ExistsCallCount()).To(Equal(0))
+				Expect(deleted).To(BeEmpty())
+			})
+
+			It("doesn't delete the markers when the recursive delete fails", func() {
+				fakeStorager.DeleteRecursiveReturns(errors.New("boom"))
+
+				err := commandExecuter.Execute(context.Background(), "delete-recursive", []string{"logs/"})
+				Expect(err).To(MatchError("boom"))
+				Expect(deleted).To(BeEmpty())
+			})
+
+			It("still deletes the markers with --continue-on-error and reports all failures", func() {
+				fakeStorager.DeleteRecursiveReturns(errors.New("boom"))
+
+				err := commandExecuter.Execute(context.Background(), "delete-recursive", []string{"--continue-on-error", "logs/"})
+				Expect(err).To(MatchError("boom"))
+				Expect(deleted).To(Equal([]string{"logs", "logs/"}))
+			})
+
+			It("reports a marker that fails to delete", func() {
+				fakeStorager.DeleteStub = func(_ context.Context, key string) error {
+					return errors.New("denied")
+				}
+
+				err := commandExecuter.Execute(context.Background(), "delete-recursive", []string{"logs/"})
+				Expect(err).To(MatchError("failed to delete folder marker logs: denied"))
+				Expect(fakeStorager.DeleteCallCount()).To(Equal(1))
+			})
+
+			It("keeps an object named like the folder that is not empty", func() {
+				fakeStorager.SizeStub = func(_ context.Context, key string) (int64, error) {
+					if key == "logs" {
+						return 42, nil
+					}
+					return 0, nil
+				}
+
+				Expect(commandExecuter.Execute(context.Background(), "delete-recursive", []string{"logs/"})).To(Succeed())
+				Expect(deleted).To(Equal([]string{"logs/"}))
+			})
+
+			It("leaves objects named like the folder that are not empty out of the --dry-run plan", func() {
+				fakeStorager.ListDetailedReturns([]common.ObjectInfo{{Key: "logs/a", Size: 5}}, nil)
+				fakeStorager.SizeStub = func(_ context.Context, key string) (int64, error) {
+					if key == "logs" {
+						return 42, nil
+					}
+					return 0, nil
+				}
+
+				var err error
+				out := captureStdout(func() {
+					err = commandExecuter.Execute(context.Background(), "delete-recursive", []string{"--dry-run", "logs/"})
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(out).To(MatchJSON(`{"keys": ["logs/a", "logs/"], "count": 2, "total_size": 5}`))
+			})
+
+			It("lists the markers the listing doesn't cover with --dry-run", func() {
+				fakeStorager.ListDetailedReturns([]common.ObjectInfo{{Key: "logs/"}, {Key: "logs/a", Size: 5}}, nil)
+
+				var err error
+				out := captureStdout(func() {
+					err = commandExecuter.Execute(context.Background(), "delete-recursive", []string{"--dry-run", "logs/"})
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeStorager.ExistsCallCount()).To(Equal(1))
+				Expect(deleted).To(BeEmpty())
+				Expect(out).To(MatchJSON(`{"keys": ["logs/", "logs/a", "logs"], "count": 3, "total_size": 5}`))
+			})
+		})
+
+		It("Prints an empty list with --dry-run when nothing matches", func() {
+			var err error
+			out := captureStdout(func() {
+				err = commandExecuter.Execute(context.Background(), "delete-recursive", []string{"--dry-run"})
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeStorager.DeleteRecursiveCallCount()).To(BeEquivalentTo(0))
+			Expect(out).To(MatchJSON(`{"keys": [], "count": 0, "total_size": 0}`))
+		})
+
 	})
 
 	Context("Exists", func() {
 		It("Successfull", func() {
-			fakeStorager.ExistsStub = func(file string) (bool, error) {
+			fakeStorager.ExistsStub = func(_ context.Context, file string) (bool, error) {
 				return true, nil
 			}
-			err := commandExecuter.Execute("exists", []string{"object"})
+			err := commandExecuter.Execute(context.Background(), "exists", []string{"object"})
 
 			Expect(fakeStorager.ExistsCallCount()).To(BeEquivalentTo(1))
 			Expect(err).ToNot(HaveOccurred())
@@ -127,26 +1026,110 @@ var _ = Describe("Execute Command", func() {
 		})
 
 		It("Not found", func() {
-			fakeStorager.ExistsStub = func(file string) (bool, error) {
+			fakeStorager.ExistsStub = func(_ context.Context, file string) (bool, error) {
 				return false, nil
 			}
 
-			err := commandExecuter.Execute("exists", []string{"object"})
+			err := commandExecuter.Execute(context.Background(), "exists", []string{"object"})
 			Expect(fakeStorager.ExistsCallCount()).To(BeEquivalentTo(1))
 			Expect(err).To(BeAssignableToTypeOf(&NotExistsError{}))
 
 		})
 
 		It("Wrong number of parameters", func() {
-			err := commandExecuter.Execute("exists", []string{"object", "extra-object"})
+			err := commandExecuter.Execute(context.Background(), "exists", []string{"object", "extra-object"})
 			Expect(err.Error()).To(ContainSubstring("exists method expected 1 argument got"))
 		})
 
+		Context("when the provider denies access", func() {
+			BeforeEach(func() {
+				fakeStorager.ExistsReturns(false, fmt.Errorf("%w: forbidden", common.ErrAccessDenied))
+			})
+
+			It("fails without the flag", func() {
+				err := commandExecuter.Execute(context.Background(), "exists", []string{"object"})
+				Expect(err).To(MatchError("failed to check exist: access denied: forbidden"))
+			})
+
+			It("reports the object as not found with --treat-403-as-absent", func() {
+				err := commandExecuter.Execute(context.Background(), "exists", []string{"--treat-403-as-absent", "object"})
+				Expect(err).To(BeAssignableToTypeOf(&NotExistsError{}))
+			})
+
+			It("still fails on other errors with --treat-403-as-absent", func() {
+				fakeStorager.ExistsReturns(false, errors.New("boom"))
+
+				err := commandExecuter.Execute(context.Background(), "exists", []string{"--treat-403-as-absent", "object"})
+				Expect(err).To(MatchError("failed to check exist: boom"))
+			})
+		})
+
+		Context("with --eventual-consistency-retries", func() {
+			BeforeEach(func() {
+				DeferCleanup(func(backoff time.Duration) {
+					eventualConsistencyBackoff = backoff
+				}, eventualConsistencyBackoff)
+				eventualConsistencyBackoff = time.Millisecond
+
+				fakeStorager.ExistsReturnsOnCall(0, false, nil)
+				fakeStorager.ExistsReturnsOnCall(1, false, nil)
+				fakeStorager.ExistsReturnsOnCall(2, true, nil)
+			})
+
+			It("fails on the first not found without the flag", func() {
+				err := commandExecuter.Execute(context.Background(), "exists", []string{"object"})
+				Expect(err).To(BeAssignableToTypeOf(&NotExistsError{}))
+				Expect(fakeStorager.ExistsCallCount()).To(BeEquivalentTo(1))
+			})
+
+			It("retries until the object shows up", func() {
+				err := commandExecuter.Execute(context.Background(), "exists", []string{"--eventual-consistency-retries", "3", "object"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeStorager.ExistsCallCount()).To(BeEquivalentTo(3))
+			})
+
+			It("gives up once the retries are used up", func() {
+				err := commandExecuter.Execute(context.Background(), "exists", []string{"--eventual-consistency-retries", "1", "object"})
+				Expect(err).To(BeAssignableToTypeOf(&NotExistsError{}))
+				Expect(fakeStorager.ExistsCallCount()).To(BeEquivalentTo(2))
+			})
+
+			It("does not retry errors", func() {
+				fakeStorager.ExistsReturnsOnCall(0, false, errors.New("access denied"))
+
+				err := commandExecuter.Execute(context.Background(), "exists", []string{"--eventual-consistency-retries", "3", "object"})
+				Expect(err).To(MatchError(ContainSubstring("access denied")))
+				Expect(fakeStorager.ExistsCallCount()).To(BeEquivalentTo(1))
+			})
+
+			It("waits for the object before downloading it", func() {
+				fakeStorager.GetStub = func(_ context.Context, source string, dest string, _ common.GetOptions) error {
+					if fakeStorager.ExistsCallCount() < 3 {
+						return errors.New("NoSuchKey")
+					}
+					return nil
+				}
+
+				err := commandExecuter.Execute(context.Background(), "get", []string{"object", "destination"})
+				Expect(err).To(MatchError("NoSuchKey"))
+
+				err = commandExecuter.Execute(context.Background(), "get", []string{"--eventual-consistency-retries", "3", "object", "destination"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeStorager.ExistsCallCount()).To(BeEquivalentTo(3))
+				Expect(fakeStorager.GetCallCount()).To(BeEquivalentTo(2))
+			})
+
+			It("rejects a negative number of retries", func() {
+				err := commandExecuter.Execute(context.Background(), "get", []string{"--eventual-consistency-retries", "-1", "object", "destination"})
+				Expect(err).To(MatchError("--eventual-consistency-retries must not be negative"))
+			})
+		})
+
 	})
 
 	Context("Sign", func() {
 		It("Successfull", func() {
-			err := commandExecuter.Execute("sign", []string{"object", "put", "10s"})
+			err := commandExecuter.Execute(context.Background(), "sign", []string{"object", "put", "10s"})
 
 			Expect(fakeStorager.SignCallCount()).To(BeEquivalentTo(1))
 			Expect(err).ToNot(HaveOccurred())
@@ -154,83 +1137,570 @@ var _ = Describe("Execute Command", func() {
 		})
 
 		It("Wrong action", func() {
-			err := commandExecuter.Execute("sign", []string{"object", "delete", "10s"})
+			err := commandExecuter.Execute(context.Background(), "sign", []string{"object", "delete", "10s"})
 			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("action not implemented: %s. Available actions are 'get' and 'put'", "delete")))
 
 		})
 
 		It("Wrong time format", func() {
-			err := commandExecuter.Execute("sign", []string{"object", "put", "10"})
+			err := commandExecuter.Execute(context.Background(), "sign", []string{"object", "put", "10"})
 			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("expiration should be in the format of a duration i.e. 1h, 60m, 3600s. Got: %s", "10")))
 
 		})
 
 		It("Wrong number of parameters", func() {
-			err := commandExecuter.Execute("sign", []string{"object", "put"})
+			err := commandExecuter.Execute(context.Background(), "sign", []string{"object", "put"})
 			Expect(err.Error()).To(ContainSubstring("sign method expects 3 arguments got"))
 
 		})
 
+		Context("with --validate", func() {
+			It("reports when the url would be valid without signing it", func() {
+				var err error
+				output := captureStdout(func() {
+					err = commandExecuter.Execute(context.Background(), "sign", []string{"--validate", "--start-at", "2030-01-02T03:04:05Z", "object", "get", "1h"})
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeStorager.ValidateSignCallCount()).To(Equal(1))
+				_, object, action, expiration, options := fakeStorager.ValidateSignArgsForCall(0)
+				Expect(object).To(Equal("object"))
+				Expect(action).To(Equal("get"))
+				Expect(expiration).To(Equal(time.Hour))
+				Expect(options.StartAt).To(Equal(time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)))
+				Expect(fakeStorager.SignCallCount()).To(Equal(0))
+				Expect(fakeStorager.SignWithOptionsCallCount()).To(Equal(0))
+				Expect(output).To(MatchJSON(`{
+					"object": "object",
+					"action": "get",
+					"valid_from": "2030-01-02T03:04:05Z",
+					"expires_at": "2030-01-02T04:04:05Z"
+				}`))
+			})
+
+			It("counts the validity from now without a start time", func() {
+				output := captureStdout(func() {
+					Expect(commandExecuter.Execute(context.Background(), "sign", []string{"--validate", "object", "put", "1h"})).To(Succeed())
+				})
+				var validation signValidation
+				Expect(json.Unmarshal([]byte(output), &validation)).To(Succeed())
+				Expect(validation.ValidFrom).To(BeTemporally("~", time.Now(), 5*time.Second))
+				Expect(validation.ExpiresAt.Sub(validation.ValidFrom)).To(Equal(time.Hour))
+			})
+
+			It("fails when the storage type can't sign with the configured credentials", func() {
+				fakeStorager.ValidateSignReturns(errors.New("signing URLs requires the account_key"))
+
+				output := captureStdout(func() {
+					err := commandExecuter.Execute(context.Background(), "sign", []string{"--validate", "object", "get", "1h"})
+					Expect(err).To(MatchError("failed to sign request: signing URLs requires the account_key"))
+				})
+				Expect(output).To(BeEmpty())
+			})
+
+			It("refuses an expiration that isn't positive", func() {
+				err := commandExecuter.Execute(context.Background(), "sign", []string{"--validate", "object", "get", "0s"})
+				Expect(err).To(MatchError("expiration must be positive. Got: 0s"))
+				Expect(fakeStorager.ValidateSignCallCount()).To(Equal(0))
+			})
+		})
+
+		It("binds a put url to the content type and MD5", func() {
+			err := commandExecuter.Execute(context.Background(), "sign", []string{"--content-type", "application/gzip", "--content-md5", "1B2M2Y8AsgTpgAmY7PhCfg==", "object", "put", "10s"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeStorager.SignCallCount()).To(BeEquivalentTo(0))
+			Expect(fakeStorager.SignWithOptionsCallCount()).To(BeEquivalentTo(1))
+
+			_, object, action, expiration, options := fakeStorager.SignWithOptionsArgsForCall(0)
+			Expect(object).To(Equal("object"))
+			Expect(action).To(Equal("put"))
+			Expect(expiration).To(Equal(10 * time.Second))
+			Expect(options).To(Equal(common.SignOptions{ContentType: "application/gzip", ContentMD5: "1B2M2Y8AsgTpgAmY7PhCfg=="}))
+		})
+
+		It("prints the url to stdout and logs to the log file", func() {
+			logFile := filepath.Join(GinkgoT().TempDir(), "storage-cli.log")
+			f, err := os.Create(logFile)
+			Expect(err).ToNot(HaveOccurred())
+			defaultLogger := slog.Default()
+			DeferCleanup(func() {
+				f.Close() //nolint:errcheck
+				slog.SetDefault(defaultLogger)
+				log.SetOutput(os.Stderr)
+				common.ResetConfig()
+			})
+			common.InitConfig(slog.LevelInfo, f)
+
+			fakeStorager.SignStub = func(_ context.Context, object string, action string, expiration time.Duration) (string, error) {
+				slog.Info("Signing object", "object_name", object)
+				return "https://the-signed-url", nil
+			}
+
+			output := captureStdout(func() {
+				Expect(commandExecuter.Execute(context.Background(), "sign", []string{"object", "get", "10s"})).To(Succeed())
+			})
+			Expect(output).To(Equal("https://the-signed-url"))
+
+			logs, err := os.ReadFile(logFile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(logs)).To(ContainSubstring(`"msg":"Signing object"`))
+			Expect(string(logs)).ToNot(ContainSubstring("https://the-signed-url"))
+		})
+
+		It("makes a url valid from the start time", func() {
+			err := commandExecuter.Execute(context.Background(), "sign", []string{"--start-at", "2030-01-02T03:04:05Z", "object", "get", "10s"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeStorager.SignCallCount()).To(BeEquivalentTo(0))
+			Expect(fakeStorager.SignWithOptionsCallCount()).To(BeEquivalentTo(1))
+
+			_, _, action, _, options := fakeStorager.SignWithOptionsArgsForCall(0)
+			Expect(action).To(Equal("get"))
+			Expect(options.StartAt).To(BeTemporally("==", time.Date(2030, time.January, 2, 3, 4, 5, 0, time.UTC)))
+		})
+
+		It("refuses a start time that is not RFC3339", func() {
+			err := commandExecuter.Execute(context.Background(), "sign", []string{"--start-at", "tomorrow", "object", "get", "10s"})
+			Expect(err).To(MatchError(ContainSubstring("--start-at should be an RFC3339 time")))
+			Expect(fakeStorager.SignWithOptionsCallCount()).To(BeEquivalentTo(0))
+		})
+
+		It("refuses content headers for get urls", func() {
+			err := commandExecuter.Execute(context.Background(), "sign", []string{"--content-type", "application/gzip", "object", "get", "10s"})
+			Expect(err).To(MatchError("--content-type and --content-md5 can only be used with the put action"))
+			Expect(fakeStorager.SignWithOptionsCallCount()).To(BeEquivalentTo(0))
+		})
+
 	})
 
 	Context("List", func() {
 		It("Successfull", func() {
-			err := commandExecuter.Execute("list", []string{})
+			err := commandExecuter.Execute(context.Background(), "list", []string{})
 
 			Expect(fakeStorager.ListCallCount()).To(BeEquivalentTo(1))
-			Expect(fakeStorager.listArgsForCall[0].arg1).To(Equal(""))
+			Expect(fakeStorager.listArgsForCall[0].arg2).To(Equal(""))
 			Expect(err).ToNot(HaveOccurred())
 
 		})
 
 		It("With Prefix", func() {
-			err := commandExecuter.Execute("exists", []string{"prefix"})
+			err := commandExecuter.Execute(context.Background(), "exists", []string{"prefix"})
 			Expect(fakeStorager.ExistsCallCount()).To(BeEquivalentTo(1))
-			Expect(fakeStorager.existsArgsForCall[0].arg1).To(Equal("prefix"))
+			Expect(fakeStorager.existsArgsForCall[0].arg2).To(Equal("prefix"))
 			Expect(err).To(BeAssignableToTypeOf(&NotExistsError{}))
 
 		})
 
+		Context("with several prefixes", func() {
+			BeforeEach(func() {
+				listings := map[string][]string{
+					"a/":   {"a/1", "a/2", "a/b/1"},
+					"a/b/": {"a/b/1", "a/b/2"},
+					"c/":   {"c/1"},
+				}
+				fakeStorager.ListStub = func(_ context.Context, prefix string) ([]string, error) {
+					return listings[prefix], nil
+				}
+				fakeStorager.ListWithLimitStub = func(_ context.Context, prefix string, limit int) ([]string, error) {
+					return listings[prefix][:min(limit, len(listings[prefix]))], nil
+				}
+			})
+
+			It("lists the keys of all prefixes", func() {
+				output := captureStdout(func() {
+					Expect(commandExecuter.Execute(context.Background(), "list", []string{"a/", "c/"})).To(Succeed())
+				})
+				Expect(output).To(Equal("a/1\na/2\na/b/1\nc/1\n"))
+				Expect(fakeStorager.ListCallCount()).To(Equal(2))
+			})
+
+			It("lists keys of overlapping prefixes once", func() {
+				output := captureStdout(func() {
+					Expect(commandExecuter.Execute(context.Background(), "list", []string{"a/", "a/b/"})).To(Succeed())
+				})
+				Expect(output).To(Equal("a/1\na/2\na/b/1\na/b/2\n"))
+			})
+
+			It("counts the merged keys", func() {
+				output := captureStdout(func() {
+					Expect(commandExecuter.Execute(context.Background(), "list", []string{"--count-only", "a/", "a/b/", "c/"})).To(Succeed())
+				})
+				Expect(output).To(Equal("5\n"))
+			})
+
+			It("caps the merged keys at the limit, looking past keys already listed", func() {
+				output := captureStdout(func() {
+					Expect(commandExecuter.Execute(context.Background(), "list", []string{"--limit", "4", "a/", "a/b/", "c/"})).To(Succeed())
+				})
+				Expect(output).To(Equal("a/1\na/2\na/b/1\na/b/2\n"))
+
+				_, _, limit := fakeStorager.ListWithLimitArgsForCall(1)
+				Expect(limit).To(Equal(2))
+				Expect(fakeStorager.ListWithLimitCallCount()).To(Equal(2))
+			})
+		})
+
+		Context("with --fail-if-empty", func() {
+			It("succeeds when objects are found", func() {
+				fakeStorager.ListReturns([]string{"prefix/a", "prefix/b"}, nil)
+
+				err := commandExecuter.Execute(context.Background(), "list", []string{"--fail-if-empty", "prefix/"})
+				Expect(err).ToNot(HaveOccurred())
+				_, prefix := fakeStorager.ListArgsForCall(0)
+
+				Expect(prefix).To(Equal("prefix/"))
+			})
+
+			It("fails with an EmptyListError when nothing is found", func() {
+				fakeStorager.ListReturns(nil, nil)
+
+				err := commandExecuter.Execute(context.Background(), "list", []string{"--fail-if-empty", "prefix/"})
+				Expect(err).To(BeAssignableToTypeOf(&EmptyListError{}))
+			})
+
+			It("still reports listing errors as such", func() {
+				fakeStorager.ListReturns(nil, errors.New("boom"))
+
+				err := commandExecuter.Execute(context.Background(), "list", []string{"--fail-if-empty", "prefix/"})
+				Expect(err).To(MatchError(ContainSubstring("failed to list objects: boom")))
+			})
+		})
+
+		Context("with --limit", func() {
+			It("lists at most that many objects", func() {
+				fakeStorager.ListWithLimitReturns([]string{"prefix/a", "prefix/b"}, nil)
+
+				output := captureStdout(func() {
+					Expect(commandExecuter.Execute(context.Background(), "list", []string{"--limit", "2", "prefix/"})).To(Succeed())
+				})
+				Expect(output).To(Equal("prefix/a\nprefix/b\n"))
+				Expect(fakeStorager.ListCallCount()).To(BeZero())
+
+				_, prefix, limit := fakeStorager.ListWithLimitArgsForCall(0)
+				Expect(prefix).To(Equal("prefix/"))
+				Expect(limit).To(Equal(2))
+			})
+
+			It("lists all objects with a limit of 0", func() {
+				err := commandExecuter.Execute(context.Background(), "list", []string{"--limit", "0", "prefix/"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeStorager.ListCallCount()).To(Equal(1))
+				Expect(fakeStorager.ListWithLimitCallCount()).To(BeZero())
+			})
+
+			It("refuses a negative limit", func() {
+				err := commandExecuter.Execute(context.Background(), "list", []string{"--limit", "-1", "prefix/"})
+				Expect(err).To(MatchError("--limit must not be negative, got -1"))
+				Expect(fakeStorager.ListWithLimitCallCount()).To(BeZero())
+			})
+
+			It("refuses a limit that is not a number", func() {
+				err := commandExecuter.Execute(context.Background(), "list", []string{"--limit", "some", "prefix/"})
+				Expect(err).To(MatchError(ContainSubstring(`invalid value "some" for flag -limit`)))
+				Expect(fakeStorager.ListWithLimitCallCount()).To(BeZero())
+			})
+
+			It("accepts the flags after the prefixes", func() {
+				fakeStorager.ListWithLimitReturns([]string{"prefix/a"}, nil)
+
+				output := captureStdout(func() {
+					Expect(commandExecuter.Execute(context.Background(), "list", []string{"prefix/", "--limit", "3", "--count-only"})).To(Succeed())
+				})
+				Expect(output).To(Equal("1\n"))
+
+				_, prefix, limit := fakeStorager.ListWithLimitArgsForCall(0)
+				Expect(prefix).To(Equal("prefix/"))
+				Expect(limit).To(Equal(3))
+			})
+
+			It("accepts the flags between the prefixes", func() {
+				captureStdout(func() {
+					Expect(commandExecuter.Execute(context.Background(), "list", []string{"a/", "--limit", "3", "b/"})).To(Succeed())
+				})
+
+				Expect(fakeStorager.ListWithLimitCallCount()).To(Equal(2))
+				_, prefix, _ := fakeStorager.ListWithLimitArgsForCall(1)
+				Expect(prefix).To(Equal("b/"))
+			})
+
+			It("takes everything after -- as prefixes", func() {
+				captureStdout(func() {
+					Expect(commandExecuter.Execute(context.Background(), "list", []string{"--", "-dashed/", "--limit"})).To(Succeed())
+				})
+
+				Expect(fakeStorager.ListCallCount()).To(Equal(2))
+				_, prefix := fakeStorager.ListArgsForCall(0)
+				Expect(prefix).To(Equal("-dashed/"))
+				_, prefix = fakeStorager.ListArgsForCall(1)
+				Expect(prefix).To(Equal("--limit"))
+			})
+		})
+
+		Context("with --count-only", func() {
+			It("prints only the number of objects", func() {
+				fakeStorager.ListReturns([]string{"prefix/a", "prefix/b", "prefix/c"}, nil)
+
+				output := captureStdout(func() {
+					Expect(commandExecuter.Execute(context.Background(), "list", []string{"--count-only", "prefix/"})).To(Succeed())
+				})
+				Expect(output).To(Equal("3\n"))
+				_, prefix := fakeStorager.ListArgsForCall(0)
+
+				Expect(prefix).To(Equal("prefix/"))
+			})
+
+			It("prints 0 and still fails with --fail-if-empty when nothing is found", func() {
+				fakeStorager.ListReturns(nil, nil)
+
+				var err error
+				output := captureStdout(func() {
+					err = commandExecuter.Execute(context.Background(), "list", []string{"--count-only", "--fail-if-empty", "prefix/"})
+				})
+				Expect(output).To(Equal("0\n"))
+				Expect(err).To(BeAssignableToTypeOf(&EmptyListError{}))
+			})
+		})
+
+		Context("with --format json", func() {
+			modified := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+
+			BeforeEach(func() {
+				fakeStorager.ListDetailedReturns([]common.ObjectInfo{
+					{Key: "prefix/a", Size: 12, LastModified: modified},
+					{Key: "prefix/quote\"new\nline<&>", Size: 0},
+				}, nil)
+			})
+
+			It("prints the objects as a JSON array", func() {
+				output := captureStdout(func() {
+					Expect(commandExecuter.Execute(context.Background(), "list", []string{"--format", "json", "prefix/"})).To(Succeed())
+				})
+				Expect(output).To(HaveSuffix("\n"))
+				Expect(strings.Count(output, "\n")).To(Equal(1))
+
+				var objects []map[string]any
+				Expect(json.Unmarshal([]byte(output), &objects)).To(Succeed())
+				Expect(objects).To(Equal([]map[string]any{
+					{"name": "prefix/a", "size": float64(12), "last_modified": "2026-03-04T05:06:07Z"},
+					{"name": "prefix/quote\"new\nline<&>", "size": float64(0)},
+				}))
+				_, prefix := fakeStorager.ListDetailedArgsForCall(0)
+
+				Expect(prefix).To(Equal("prefix/"))
+				Expect(fakeStorager.ListCallCount()).To(BeZero())
+			})
+
+			It("accepts json through --list-format", func() {
+				output := captureStdout(func() {
+					Expect(commandExecuter.Execute(context.Background(), "list", []string{"--list-format", "json", "prefix/"})).To(Succeed())
+				})
+				Expect(json.Valid([]byte(output))).To(BeTrue())
+			})
+
+			It("prints an empty array when nothing is found", func() {
+				fakeStorager.ListDetailedReturns(nil, nil)
+
+				var err error
+				output := captureStdout(func() {
+					err = commandExecuter.Execute(context.Background(), "list", []string{"--format", "json", "--fail-if-empty", "prefix/"})
+				})
+				Expect(output).To(Equal("[]\n"))
+				Expect(err).To(BeAssignableToTypeOf(&EmptyListError{}))
+			})
+
+			It("merges several prefixes and passes the limit to the backend", func() {
+				fakeStorager.ListDetailedWithLimitStub = func(_ context.Context, prefix string, limit int) ([]common.ObjectInfo, error) {
+					objects := []common.ObjectInfo{{Key: prefix + "1"}, {Key: prefix + "2"}}
+					return objects[:min(limit, len(objects))], nil
+				}
+
+				output := captureStdout(func() {
+					Expect(commandExecuter.Execute(context.Background(), "list", []string{"--format", "json", "--limit", "3", "a/", "b/", "c/"})).To(Succeed())
+				})
+				Expect(output).To(Equal(`[{"name":"a/1","size":0},{"name":"a/2","size":0},{"name":"b/1","size":0}]` + "\n"))
+				Expect(fakeStorager.ListDetailedCallCount()).To(BeZero())
+				Expect(fakeStorager.ListDetailedWithLimitCallCount()).To(Equal(2))
+
+				_, _, limit := fakeStorager.ListDetailedWithLimitArgsForCall(0)
+				Expect(limit).To(Equal(3))
+				_, _, limit = fakeStorager.ListDetailedWithLimitArgsForCall(1)
+				Expect(limit).To(Equal(1))
+			})
+
+			It("reports listing errors", func() {
+				fakeStorager.ListDetailedReturns(nil, errors.New("not implemented"))
+
+				err := commandExecuter.Execute(context.Background(), "list", []string{"--format", "json", "prefix/"})
+				Expect(err).To(MatchError("failed to list objects: not implemented"))
+			})
+
+			It("refuses unknown formats", func() {
+				err := commandExecuter.Execute(context.Background(), "list", []string{"--format", "xml", "prefix/"})
+				Expect(err).To(MatchError(ContainSubstring("unknown list format: 'xml'")))
+			})
+		})
+
+		It("succeeds on an empty listing without --fail-if-empty", func() {
+			fakeStorager.ListReturns(nil, nil)
+
+			err := commandExecuter.Execute(context.Background(), "list", []string{"prefix/"})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+	})
+
+	Context("Size", func() {
+		It("prints the size of the object", func() {
+			fakeStorager.SizeReturns(1024, nil)
+
+			output := captureStdout(func() {
+				Expect(commandExecuter.Execute(context.Background(), "size", []string{"object"})).To(Succeed())
+			})
+			Expect(output).To(Equal("1024\n"))
+			_, key := fakeStorager.SizeArgsForCall(0)
+
+			Expect(key).To(Equal("object"))
+		})
+
+		It("fails for a missing object", func() {
+			fakeStorager.SizeReturns(0, errors.New("not found"))
+
+			output := captureStdout(func() {
+				err := commandExecuter.Execute(context.Background(), "size", []string{"object"})
+				Expect(err).To(MatchError("failed to get size: not found"))
+			})
+			Expect(output).To(BeEmpty())
+		})
+
 		It("Wrong number of parameters", func() {
-			err := commandExecuter.Execute("list", []string{"prefix-1", "prefix-2"})
-			Expect(err.Error()).To(ContainSubstring("list method takes at most 1 argument (prefix) got"))
+			err := commandExecuter.Execute(context.Background(), "size", []string{"object-1", "object-2"})
+			Expect(err.Error()).To(ContainSubstring("size method expected 1 argument got 2"))
+		})
+	})
+
+	Context("Capabilities", func() {
+		It("prints the capabilities of the backend one per line", func() {
+			fakeStorager.CapabilitiesReturns([]string{common.CapabilityPut, common.CapabilityCopyToBucket})
+
+			output := captureStdout(func() {
+				Expect(commandExecuter.Execute(context.Background(), "capabilities", []string{})).To(Succeed())
+			})
+			Expect(output).To(Equal("put\ncopy --dest-bucket\n"))
+		})
+
+		It("Wrong number of parameters", func() {
+			err := commandExecuter.Execute(context.Background(), "capabilities", []string{"put"})
+			Expect(err).To(MatchError("capabilities method expected 0 arguments got 1"))
+			Expect(fakeStorager.CapabilitiesCallCount()).To(BeZero())
+		})
+	})
+
+	Context("Head", func() {
+		It("prints the head of the object", func() {
+			fakeStorager.HeadReturns(common.ObjectHead{ETag: "some-etag", ContentLength: 10, ContentType: "text/plain"}, nil)
+
+			var err error
+			output := captureStdout(func() {
+				err = commandExecuter.Execute(context.Background(), "head", []string{"object"})
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output).To(MatchJSON(`{"etag": "some-etag", "content_length": 10, "content_type": "text/plain"}`))
+			_, key := fakeStorager.HeadArgsForCall(0)
+
+			Expect(key).To(Equal("object"))
+		})
+
+		It("prints an empty document and succeeds for a missing object", func() {
+			fakeStorager.HeadReturns(common.ObjectHead{}, common.ErrObjectNotFound)
+
+			var err error
+			output := captureStdout(func() {
+				err = commandExecuter.Execute(context.Background(), "head", []string{"missing-object"})
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output).To(Equal("{}\n"))
+		})
+
+		It("reports failures of the backend", func() {
+			fakeStorager.HeadReturns(common.ObjectHead{}, errors.New("access denied"))
+
+			err := commandExecuter.Execute(context.Background(), "head", []string{"object"})
+			Expect(err).To(MatchError("access denied"))
 		})
 
+		It("Wrong number of parameters", func() {
+			err := commandExecuter.Execute(context.Background(), "head", []string{})
+			Expect(err).To(MatchError("head method expected 1 argument got 0"))
+
+			err = commandExecuter.Execute(context.Background(), "head", []string{"object-1", "object-2"})
+			Expect(err).To(MatchError("head method expected 1 argument got 2"))
+			Expect(fakeStorager.HeadCallCount()).To(BeZero())
+		})
 	})
 
 	Context("Properties", func() {
 		It("Successfull", func() {
-			err := commandExecuter.Execute("properties", []string{"object"})
+			err := commandExecuter.Execute(context.Background(), "properties", []string{"object"})
 			Expect(fakeStorager.PropertiesCallCount()).To(BeEquivalentTo(1))
 			Expect(err).ToNot(HaveOccurred())
 
 		})
 
 		It("Wrong number of parameters", func() {
-			err := commandExecuter.Execute("properties", []string{})
+			err := commandExecuter.Execute(context.Background(), "properties", []string{})
 			Expect(err.Error()).To(ContainSubstring("properties method expected 1 argument got"))
 		})
 
+		It("keeps the quotes of the ETag with --raw-etag", func() {
+			err := commandExecuter.Execute(context.Background(), "properties", []string{"--raw-etag", "object"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeStorager.PropertiesCallCount()).To(BeEquivalentTo(0))
+			Expect(fakeStorager.PropertiesWithOptionsCallCount()).To(BeEquivalentTo(1))
+			_, dest, options := fakeStorager.PropertiesWithOptionsArgsForCall(0)
+			Expect(dest).To(Equal("object"))
+			Expect(options).To(Equal(common.PropertiesOptions{RawETag: true}))
+		})
+
 	})
 
 	Context("Ensure storage exists", func() {
 		It("Successfull", func() {
-			err := commandExecuter.Execute("ensure-storage-exists", []string{})
+			err := commandExecuter.Execute(context.Background(), "ensure-storage-exists", []string{})
 			Expect(fakeStorager.EnsureStorageExistsCallCount()).To(BeEquivalentTo(1))
 			Expect(err).ToNot(HaveOccurred())
 
 		})
 
 		It("Wrong number of parameters", func() {
-			err := commandExecuter.Execute("ensure-storage-exists", []string{"extra-parameter"})
+			err := commandExecuter.Execute(context.Background(), "ensure-storage-exists", []string{"extra-parameter"})
 			Expect(err.Error()).To(ContainSubstring("ensureStorageExists method expected 0 argument got"))
 		})
 
 	})
 
+	Context("Whoami", func() {
+		It("Successfull", func() {
+			fakeStorager.IdentityReturns(common.Identity{CredentialsSource: "static", Principal: "some-principal"}, nil)
+			err := commandExecuter.Execute(context.Background(), "whoami", []string{})
+			Expect(fakeStorager.IdentityCallCount()).To(BeEquivalentTo(1))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Fails when the identity cannot be resolved", func() {
+			fakeStorager.IdentityReturns(common.Identity{}, errors.New("boom"))
+			err := commandExecuter.Execute(context.Background(), "whoami", []string{})
+			Expect(err).To(MatchError("failed to resolve identity: boom"))
+		})
+
+		It("Wrong number of parameters", func() {
+			err := commandExecuter.Execute(context.Background(), "whoami", []string{"extra-parameter"})
+			Expect(err.Error()).To(ContainSubstring("whoami method expected 0 arguments got"))
+		})
+
+	})
+
 	Context("Unsupported command", func() {
 		It("Successfull", func() {
-			err := commandExecuter.Execute("unsupported-command", []string{})
+			err := commandExecuter.Execute(context.Background(), "unsupported-command", []string{})
 			Expect(err.Error()).To(ContainSubstring("unknown command: '%s'", "unsupported-command"))
 
 		})
@@ -238,3 +1708,24 @@ var _ = Describe("Execute Command", func() {
 	})
 
 })
+
+// captureStdout returns everything written to stdout while f runs
+func captureStdout(f func()) string {
+	old := os.Stdout
+	r, w, _ := os.Pipe() //nolint:errcheck
+	os.Stdout = w
+
+	outC := make(chan string)
+	// copy the output in a separate goroutine so printing can't block indefinitely
+	go func() {
+		var buf bytes.Buffer
+		io.Copy(&buf, r) //nolint:errcheck
+		outC <- buf.String()
+	}()
+
+	f()
+
+	w.Close() //nolint:errcheck
+	os.Stdout = old
+	return <-outC
+}
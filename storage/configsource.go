@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ConfigEnvVar holds the JSON config when no config path is given
+const ConfigEnvVar = "STORAGE_CLI_CONFIG"
+
+// stdin is where a config path or put source of "-" reads from
+var stdin io.Reader = os.Stdin
+
+// configFromStdin is set once the config was read from stdin, which leaves nothing there for put
+var configFromStdin bool
+
+// OpenConfig opens the config file at path. A path of "-" reads the config from stdin and an
+// empty path falls back to the JSON in STORAGE_CLI_CONFIG, so that secrets injected by CI
+// pipelines don't have to be written to disk first.
+func OpenConfig(path string) (io.ReadCloser, error) {
+	switch path {
+	case "-":
+		configFromStdin = true
+		return io.NopCloser(stdin), nil
+	case "":
+		config := os.Getenv(ConfigEnvVar)
+		if config == "" {
+			return nil, fmt.Errorf("no config given: pass -c <path>, -c - to read it from stdin or set %s", ConfigEnvVar)
+		}
+		return io.NopCloser(strings.NewReader(config)), nil
+	default:
+		return os.Open(path)
+	}
+}
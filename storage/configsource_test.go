@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OpenConfig", func() {
+	readAll := func(config io.ReadCloser) string {
+		defer config.Close() //nolint:errcheck
+		content, err := io.ReadAll(config)
+		Expect(err).ToNot(HaveOccurred())
+		return string(content)
+	}
+
+	It("opens the file at the given path", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "config.json")
+		Expect(os.WriteFile(path, []byte(`{"bucket_name": "from-file"}`), 0600)).To(Succeed())
+
+		config, err := OpenConfig(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(readAll(config)).To(Equal(`{"bucket_name": "from-file"}`))
+	})
+
+	It("fails on a missing file", func() {
+		_, err := OpenConfig(filepath.Join(GinkgoT().TempDir(), "missing.json"))
+		Expect(err).To(MatchError(os.ErrNotExist))
+	})
+
+	It("reads the config from stdin for -", func() {
+		DeferCleanup(func(original io.Reader, fromStdin bool) { stdin, configFromStdin = original, fromStdin }, stdin, configFromStdin)
+		stdin = strings.NewReader(`{"bucket_name": "from-stdin"}`)
+
+		config, err := OpenConfig("-")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(readAll(config)).To(Equal(`{"bucket_name": "from-stdin"}`))
+	})
+
+	It("falls back to the environment without a path", func() {
+		GinkgoT().Setenv(ConfigEnvVar, `{"bucket_name": "from-env"}`)
+
+		config, err := OpenConfig("")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(readAll(config)).To(Equal(`{"bucket_name": "from-env"}`))
+	})
+
+	It("fails without a path and without the environment variable", func() {
+		GinkgoT().Setenv(ConfigEnvVar, "")
+
+		_, err := OpenConfig("")
+		Expect(err).To(MatchError("no config given: pass -c <path>, -c - to read it from stdin or set STORAGE_CLI_CONFIG"))
+	})
+
+	It("can be used to create a client", func() {
+		GinkgoT().Setenv(ConfigEnvVar, `{"bucket_name": "some-bucket", "credentials_source": "none"}`)
+
+		config, err := OpenConfig("")
+		Expect(err).ToNot(HaveOccurred())
+		defer config.Close() //nolint:errcheck
+
+		_, err = NewStorageClient("gcs", config)
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
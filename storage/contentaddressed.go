@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+)
+
+// contentAddressedKey returns prefix followed by the hex encoded SHA256 of the file at path,
+// so that identical content always ends up under the same key
+func contentAddressedKey(path string, prefix string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close() //nolint:errcheck
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to calculate sha256 of %s: %w", path, err)
+	}
+	return prefix + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// fileMD5 returns the hex encoded MD5 of the file at path
+func fileMD5(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close() //nolint:errcheck
+
+	hash := md5.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to calculate md5 of %s: %w", path, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
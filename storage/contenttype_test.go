@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("fileContentType", func() {
+	var dir string
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+	})
+
+	contentTypeOf := func(name string, content string) string {
+		path := filepath.Join(dir, name)
+		Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+
+		contentType, err := fileContentType(path)
+		Expect(err).ToNot(HaveOccurred())
+		return contentType
+	}
+
+	It("guesses the content type of static website files from their extension", func() {
+		Expect(contentTypeOf("index.html", "<html></html>")).To(Equal("text/html; charset=utf-8"))
+		Expect(contentTypeOf("style.css", "body {}")).To(Equal("text/css; charset=utf-8"))
+		Expect(contentTypeOf("app.js", "let a = 1")).To(Equal("text/javascript; charset=utf-8"))
+	})
+
+	It("sniffs the content type of files without a known extension", func() {
+		Expect(contentTypeOf("page", "<html></html>")).To(Equal("text/html; charset=utf-8"))
+		Expect(contentTypeOf("notes", "plain text")).To(Equal("text/plain; charset=utf-8"))
+	})
+
+	It("fails for a file that doesn't exist", func() {
+		_, err := fileContentType(filepath.Join(dir, "missing.css"))
+		Expect(err).To(MatchError(os.ErrNotExist))
+	})
+})
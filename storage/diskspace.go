@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// availableSpace is swapped out in tests to simulate a full disk
+var availableSpace = availableDiskSpace
+
+// checkDiskSpace fails if the filesystem dst is written to has no room for the object src, so that a
+// download doesn't fill up the disk and leave a partial file behind. When resuming, only the bytes still
+// missing from <dst>.part are needed. The check is best effort: if either size can't be determined the
+// download goes ahead and the backend reports any problem with the object itself.
+func (sty *CommandExecuter) checkDiskSpace(ctx context.Context, src string, dst string, resume bool) error {
+	size, err := sty.str.Size(ctx, src)
+	if err != nil {
+		slog.Debug("Skipping disk space check, object size unknown", "object", src, "error", err)
+		return nil
+	}
+
+	needed := size
+	if resume {
+		if info, err := os.Stat(dst + ".part"); err == nil {
+			needed -= info.Size()
+		}
+	}
+	if needed <= 0 {
+		return nil
+	}
+
+	dir := filepath.Dir(dst)
+	available, err := availableSpace(dir)
+	if err != nil {
+		slog.Debug("Skipping disk space check, available space unknown", "dir", dir, "error", err)
+		return nil
+	}
+
+	if uint64(needed) > available {
+		return fmt.Errorf("insufficient disk space: %s needs %d bytes but only %d bytes are available in %s", src, needed, available, dir)
+	}
+	return nil
+}
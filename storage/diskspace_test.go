@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Disk space check", func() {
+	var (
+		commandExecuter *CommandExecuter
+		fakeStorager    *FakeStorager
+		dst             string
+		checkedDir      string
+	)
+
+	BeforeEach(func() {
+		fakeStorager = &FakeStorager{}
+		commandExecuter = NewCommandExecuter(fakeStorager)
+		dst = filepath.Join(GinkgoT().TempDir(), "destination")
+
+		DeferCleanup(func(original func(string) (uint64, error)) {
+			availableSpace = original
+		}, availableSpace)
+		availableSpace = func(dir string) (uint64, error) {
+			checkedDir = dir
+			return 100, nil
+		}
+	})
+
+	It("aborts the download when the object doesn't fit", func() {
+		fakeStorager.SizeReturns(101, nil)
+
+		err := commandExecuter.Execute(context.Background(), "get", []string{"object", dst})
+		Expect(err).To(MatchError(ContainSubstring("insufficient disk space: object needs 101 bytes but only 100 bytes are available")))
+		_, key := fakeStorager.SizeArgsForCall(0)
+
+		Expect(key).To(Equal("object"))
+		Expect(checkedDir).To(Equal(filepath.Dir(dst)))
+		Expect(fakeStorager.GetCallCount()).To(BeEquivalentTo(0))
+	})
+
+	It("downloads objects that fit", func() {
+		fakeStorager.SizeReturns(100, nil)
+
+		err := commandExecuter.Execute(context.Background(), "get", []string{"object", dst})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fakeStorager.GetCallCount()).To(BeEquivalentTo(1))
+	})
+
+	It("skips the check with --no-space-check", func() {
+		fakeStorager.SizeReturns(101, nil)
+
+		err := commandExecuter.Execute(context.Background(), "get", []string{"--no-space-check", "object", dst})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fakeStorager.SizeCallCount()).To(BeEquivalentTo(0))
+		Expect(fakeStorager.GetCallCount()).To(BeEquivalentTo(1))
+	})
+
+	It("only needs room for the missing bytes when resuming", func() {
+		fakeStorager.SizeReturns(150, nil)
+		Expect(os.WriteFile(dst+".part", make([]byte, 50), 0644)).To(Succeed())
+		Expect(os.WriteFile(dst+".part.version", []byte("{}"), 0644)).To(Succeed())
+
+		err := commandExecuter.Execute(context.Background(), "get", []string{"--continue", "object", dst})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fakeStorager.GetRangeCallCount()).To(BeEquivalentTo(1))
+	})
+
+	It("downloads anyway when the object size is unknown", func() {
+		fakeStorager.SizeReturns(0, errors.New("not implemented"))
+
+		err := commandExecuter.Execute(context.Background(), "get", []string{"object", dst})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fakeStorager.GetCallCount()).To(BeEquivalentTo(1))
+	})
+
+	It("downloads anyway when the available space is unknown", func() {
+		fakeStorager.SizeReturns(101, nil)
+		availableSpace = func(string) (uint64, error) {
+			return 0, errors.New("statfs failed")
+		}
+
+		err := commandExecuter.Execute(context.Background(), "get", []string{"object", dst})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fakeStorager.GetCallCount()).To(BeEquivalentTo(1))
+	})
+
+	It("reports the space available on a real filesystem", func() {
+		available, err := availableDiskSpace(GinkgoT().TempDir())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(available).To(BeNumerically(">", 0))
+	})
+})
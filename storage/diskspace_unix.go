@@ -0,0 +1,14 @@
+//go:build !windows
+
+package storage
+
+import "golang.org/x/sys/unix"
+
+// availableDiskSpace returns the number of bytes an unprivileged user can still write to the filesystem holding dir
+func availableDiskSpace(dir string) (uint64, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(dir, &stat); err != nil {
+		return 0, err
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil //nolint:unconvert
+}
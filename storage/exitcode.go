@@ -0,0 +1,23 @@
+package storage
+
+import "errors"
+
+// ExitCodeNotFound is the exit code of exists for a missing object and of list --fail-if-empty for
+// no objects. 1 and 2 already have special meanings.
+const ExitCodeNotFound = 3
+
+// ExitCode returns the exit code the cli ends with after a command returned err, the same for
+// every storage type: 0 without an error, ExitCodeNotFound for a *NotExistsError or
+// *EmptyListError, also when wrapped, and 1 for any other error
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var notExists *NotExistsError
+	var emptyList *EmptyListError
+	if errors.As(err, &notExists) || errors.As(err, &emptyList) {
+		return ExitCodeNotFound
+	}
+	return 1
+}
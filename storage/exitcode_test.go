@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ExitCode", func() {
+	It("is 0 without an error", func() {
+		Expect(ExitCode(nil)).To(Equal(0))
+	})
+
+	It("is 3 for a missing object", func() {
+		Expect(ExitCode(&NotExistsError{})).To(Equal(ExitCodeNotFound))
+		Expect(ExitCodeNotFound).To(Equal(3))
+	})
+
+	It("is 3 for an empty list", func() {
+		Expect(ExitCode(&EmptyListError{})).To(Equal(ExitCodeNotFound))
+	})
+
+	It("is 3 for a wrapped missing object", func() {
+		Expect(ExitCode(fmt.Errorf("checking object: %w", &NotExistsError{}))).To(Equal(ExitCodeNotFound))
+	})
+
+	It("is 1 for any other error", func() {
+		Expect(ExitCode(errors.New("object does not exist"))).To(Equal(1))
+	})
+
+	Context("for commands", func() {
+		var (
+			commandExecuter *CommandExecuter
+			fakeStorager    *FakeStorager
+		)
+
+		BeforeEach(func() {
+			fakeStorager = &FakeStorager{}
+			commandExecuter = NewCommandExecuter(fakeStorager)
+		})
+
+		It("is 0 when exists finds the object", func() {
+			fakeStorager.ExistsReturns(true, nil)
+			Expect(ExitCode(commandExecuter.Execute(context.Background(), "exists", []string{"object"}))).To(Equal(0))
+		})
+
+		It("is 3 when exists doesn't find the object", func() {
+			fakeStorager.ExistsReturns(false, nil)
+			Expect(ExitCode(commandExecuter.Execute(context.Background(), "exists", []string{"object"}))).To(Equal(3))
+		})
+
+		It("is 1 when exists fails", func() {
+			fakeStorager.ExistsReturns(false, errors.New("boom"))
+			Expect(ExitCode(commandExecuter.Execute(context.Background(), "exists", []string{"object"}))).To(Equal(1))
+		})
+
+		It("is 3 when list --fail-if-empty finds nothing", func() {
+			captureStdout(func() {
+				Expect(ExitCode(commandExecuter.Execute(context.Background(), "list", []string{"--fail-if-empty"}))).To(Equal(3))
+			})
+		})
+	})
+})
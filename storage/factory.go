@@ -3,7 +3,7 @@ package storage
 import (
 	"context"
 	"fmt"
-	"os"
+	"io"
 
 	boshlog "github.com/cloudfoundry/bosh-utils/logger"
 	alioss "github.com/cloudfoundry/storage-cli/alioss/client"
@@ -19,11 +19,39 @@ import (
 	s3config "github.com/cloudfoundry/storage-cli/s3/config"
 )
 
-var newAzurebsClient = func(configFile *os.File) (Storager, error) {
+const gcsEndpoint = "storage.googleapis.com"
+
+// s3FolderNameConfigKey names the prefix of the s3 config that ClientOptions.NoFolderPrefix removes
+const s3FolderNameConfigKey = "folder_name"
+
+// s3Endpoint returns the URL requests are sent to, the regional AWS endpoint if no host is configured
+func s3Endpoint(c *s3config.S3Cli) string {
+	if c.Host == "" && c.Region == "" {
+		return "s3.amazonaws.com"
+	}
+	if c.Host == "" {
+		return fmt.Sprintf("s3.%s.amazonaws.com", c.Region)
+	}
+
+	scheme := "https"
+	if !c.UseSSL {
+		scheme = "http"
+	}
+	return scheme + "://" + c.S3Endpoint()
+}
+
+var newAzurebsClient = func(configFile io.Reader) (Storager, error) {
 	conf, err := azureconfigbs.NewFromReader(configFile)
 	if err != nil {
 		return nil, err
 	}
+	endpoint := conf.AccountName + "." + conf.StorageEndpoint()
+	if err := checkEndpointAllowed(endpoint); err != nil {
+		return nil, err
+	}
+	if err := checkEndpoint(endpoint); err != nil {
+		return nil, err
+	}
 
 	sc, err := azurebs.NewStorageClient(conf)
 	if err != nil {
@@ -37,11 +65,17 @@ var newAzurebsClient = func(configFile *os.File) (Storager, error) {
 	return &azClient, nil
 }
 
-var newAliossClient = func(configFile *os.File) (Storager, error) {
+var newAliossClient = func(configFile io.Reader) (Storager, error) {
 	aliConfig, err := aliossconfig.NewFromReader(configFile)
 	if err != nil {
 		return nil, err
 	}
+	if err := checkEndpointAllowed(aliConfig.Endpoint); err != nil {
+		return nil, err
+	}
+	if err := checkEndpoint(aliConfig.Endpoint); err != nil {
+		return nil, err
+	}
 
 	storageClient, err := alioss.NewStorageClient(aliConfig)
 	if err != nil {
@@ -56,14 +90,19 @@ var newAliossClient = func(configFile *os.File) (Storager, error) {
 	return &aliClient, nil
 }
 
-var newGcsClient = func(configFile *os.File) (Storager, error) {
+var newGcsClient = func(configFile io.Reader) (Storager, error) {
 	gcsConfig, err := gcsconfig.NewFromReader(configFile)
 	if err != nil {
 		return nil, err
 	}
+	if err := checkEndpointAllowed(gcsEndpoint); err != nil {
+		return nil, err
+	}
+	if err := checkEndpoint(gcsEndpoint); err != nil {
+		return nil, err
+	}
 
-	ctx := context.Background()
-	gcsClient, err := gcs.New(ctx, &gcsConfig)
+	gcsClient, err := gcs.New(context.Background(), &gcsConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -71,11 +110,18 @@ var newGcsClient = func(configFile *os.File) (Storager, error) {
 
 }
 
-var newS3Client = func(configFile *os.File) (Storager, error) {
+var newS3Client = func(configFile io.Reader) (Storager, error) {
 	s3Config, err := s3config.NewFromReader(configFile)
 	if err != nil {
 		return nil, err
 	}
+	endpoint := s3Endpoint(&s3Config)
+	if err := checkEndpointAllowed(endpoint); err != nil {
+		return nil, err
+	}
+	if err := checkEndpoint(endpoint); err != nil {
+		return nil, err
+	}
 
 	s3Client, err := s3.NewAwsS3Client(&s3Config)
 	if err != nil {
@@ -86,11 +132,17 @@ var newS3Client = func(configFile *os.File) (Storager, error) {
 
 }
 
-var newDavClient = func(configFile *os.File) (Storager, error) {
+var newDavClient = func(configFile io.Reader) (Storager, error) {
 	davConfig, err := davconfig.NewFromReader(configFile)
 	if err != nil {
 		return nil, err
 	}
+	if err := checkEndpointAllowed(davConfig.Endpoint); err != nil {
+		return nil, err
+	}
+	if err := checkEndpoint(davConfig.Endpoint); err != nil {
+		return nil, err
+	}
 
 	logger := boshlog.NewLogger(boshlog.LevelNone)
 	cmdFactory := davcmd.NewFactory(logger)
@@ -100,7 +152,38 @@ var newDavClient = func(configFile *os.File) (Storager, error) {
 	return davapp.New(cmdRunner, davConfig), nil
 }
 
-func NewStorageClient(storageType string, configFile *os.File) (Storager, error) {
+// ClientOptions change the config of a client for one invocation
+type ClientOptions struct {
+	// NoFolderPrefix removes the folder_name from an s3 config, so that object keys are used as given
+	NoFolderPrefix bool
+}
+
+// NewStorageClient creates the client of the given storage type. If the config sets a key_prefix,
+// the client puts it in front of all object keys.
+func NewStorageClient(storageType string, configFile io.Reader) (Storager, error) {
+	return NewStorageClientWithOptions(storageType, configFile, ClientOptions{})
+}
+
+// NewStorageClientWithOptions creates the client like NewStorageClient, with its config changed by options
+func NewStorageClientWithOptions(storageType string, configFile io.Reader, options ClientOptions) (Storager, error) {
+	keyPrefix, configFile, err := extractKeyPrefix(configFile)
+	if err != nil {
+		return nil, err
+	}
+	if options.NoFolderPrefix && storageType == "s3" {
+		if configFile, err = removeConfigKey(configFile, s3FolderNameConfigKey); err != nil {
+			return nil, err
+		}
+	}
+
+	client, err := newBackendClient(storageType, configFile)
+	if err != nil || keyPrefix == "" {
+		return client, err
+	}
+	return &prefixedStorager{str: client, prefix: keyPrefix}, nil
+}
+
+func newBackendClient(storageType string, configFile io.Reader) (Storager, error) {
 	switch storageType {
 	case "azurebs":
 		return newAzurebsClient(configFile)
@@ -1,7 +1,18 @@
 package storage
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -28,7 +39,7 @@ var _ = Describe("Factory", func() {
 				})
 
 				mockClient := &FakeStorager{}
-				newAliossClient = func(configFile *os.File) (Storager, error) {
+				newAliossClient = func(configFile io.Reader) (Storager, error) {
 					return mockClient, nil
 				}
 
@@ -48,7 +59,7 @@ var _ = Describe("Factory", func() {
 				})
 
 				mockClient := &FakeStorager{}
-				newAzurebsClient = func(configFile *os.File) (Storager, error) {
+				newAzurebsClient = func(configFile io.Reader) (Storager, error) {
 					return mockClient, nil
 				}
 
@@ -68,7 +79,7 @@ var _ = Describe("Factory", func() {
 				})
 
 				mockClient := &FakeStorager{}
-				newDavClient = func(configFile *os.File) (Storager, error) {
+				newDavClient = func(configFile io.Reader) (Storager, error) {
 					return mockClient, nil
 				}
 
@@ -88,7 +99,7 @@ var _ = Describe("Factory", func() {
 				})
 
 				mockClient := &FakeStorager{}
-				newGcsClient = func(configFile *os.File) (Storager, error) {
+				newGcsClient = func(configFile io.Reader) (Storager, error) {
 					return mockClient, nil
 				}
 
@@ -108,7 +119,7 @@ var _ = Describe("Factory", func() {
 				})
 
 				mockClient := &FakeStorager{}
-				newS3Client = func(configFile *os.File) (Storager, error) {
+				newS3Client = func(configFile io.Reader) (Storager, error) {
 					return mockClient, nil
 				}
 
@@ -118,6 +129,76 @@ var _ = Describe("Factory", func() {
 				Expect(client).To(Equal(mockClient))
 			})
 
+			Context("folder_name", func() {
+				var (
+					requestedPaths []string
+					config         string
+				)
+
+				BeforeEach(func() {
+					requestedPaths = nil
+					server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						requestedPaths = append(requestedPaths, r.URL.Path)
+					}))
+					DeferCleanup(server.Close)
+
+					serverURL, err := url.Parse(server.URL)
+					Expect(err).ToNot(HaveOccurred())
+					config = fmt.Sprintf(`{"bucket_name": "some-bucket", "folder_name": "some-folder", "host": %q, "port": %s, "use_ssl": false, `+
+						`"region": "us-east-1", "credentials_source": "static", "access_key_id": "id", "secret_access_key": "key"}`, serverURL.Hostname(), serverURL.Port())
+				})
+
+				It("is put in front of every key", func() {
+					client, err := NewStorageClient("s3", strings.NewReader(config))
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(client.Exists(context.Background(), "some-key")).To(BeTrue())
+					Expect(client.Exists(context.Background(), "some-folder/some-key")).To(BeTrue())
+					Expect(requestedPaths).To(Equal([]string{"/some-bucket/some-folder/some-key", "/some-bucket/some-folder/some-folder/some-key"}))
+				})
+
+				It("round trips listed keys to get together with a key_prefix", func() {
+					var objectPaths []string
+					server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						if r.URL.Query().Has("list-type") {
+							Expect(r.URL.Query().Get("prefix")).To(Equal("some-folder/staging/"))
+							w.Write([]byte(`<ListBucketResult><Name>some-bucket</Name><IsTruncated>false</IsTruncated>` + //nolint:errcheck
+								`<Contents><Key>some-folder/staging/some-key</Key><Size>12</Size></Contents></ListBucketResult>`))
+							return
+						}
+						objectPaths = append(objectPaths, r.URL.Path)
+						http.ServeContent(w, r, "", time.Time{}, strings.NewReader("some content"))
+					}))
+					DeferCleanup(server.Close)
+
+					serverURL, err := url.Parse(server.URL)
+					Expect(err).ToNot(HaveOccurred())
+					config := fmt.Sprintf(`{"bucket_name": "some-bucket", "folder_name": "some-folder", "key_prefix": "staging/", "host": %q, "port": %s, "use_ssl": false, `+
+						`"region": "us-east-1", "credentials_source": "static", "access_key_id": "id", "secret_access_key": "key"}`, serverURL.Hostname(), serverURL.Port())
+					client, err := NewStorageClient("s3", strings.NewReader(config))
+					Expect(err).ToNot(HaveOccurred())
+
+					keys, err := client.List(context.Background(), "")
+					Expect(err).ToNot(HaveOccurred())
+					Expect(keys).To(Equal([]string{"some-key"}))
+
+					dst := filepath.Join(GinkgoT().TempDir(), "some-file")
+					Expect(client.Get(context.Background(), keys[0], dst, common.GetOptions{})).To(Succeed())
+					Expect(objectPaths).ToNot(BeEmpty())
+					for _, path := range objectPaths {
+						Expect(path).To(Equal("/some-bucket/some-folder/staging/some-key"))
+					}
+					Expect(os.ReadFile(dst)).To(Equal([]byte("some content")))
+				})
+
+				It("is left out with NoFolderPrefix", func() {
+					client, err := NewStorageClientWithOptions("s3", strings.NewReader(config), ClientOptions{NoFolderPrefix: true})
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(client.Exists(context.Background(), "some-key")).To(BeTrue())
+					Expect(requestedPaths).To(Equal([]string{"/some-bucket/some-key"}))
+				})
+			})
 		})
 
 		It("Unimplemented Client", func() {
@@ -2,16 +2,31 @@
 package storage
 
 import (
+	"context"
 	"sync"
 	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
 )
 
 type FakeStorager struct {
-	CopyStub        func(string, string) error
+	CapabilitiesStub        func() []string
+	capabilitiesMutex       sync.RWMutex
+	capabilitiesArgsForCall []struct {
+	}
+	capabilitiesReturns struct {
+		result1 []string
+	}
+	capabilitiesReturnsOnCall map[int]struct {
+		result1 []string
+	}
+	CopyStub        func(context.Context, string, string, common.CopyOptions) error
 	copyMutex       sync.RWMutex
 	copyArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
+		arg3 string
+		arg4 common.CopyOptions
 	}
 	copyReturns struct {
 		result1 error
@@ -19,10 +34,42 @@ type FakeStorager struct {
 	copyReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DeleteStub        func(string) error
+	CopyFromBucketStub        func(context.Context, string, string, string, string, common.CopyOptions) error
+	copyFromBucketMutex       sync.RWMutex
+	copyFromBucketArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 string
+		arg6 common.CopyOptions
+	}
+	copyFromBucketReturns struct {
+		result1 error
+	}
+	copyFromBucketReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CopyToBucketStub        func(context.Context, string, string, string, common.CopyOptions) error
+	copyToBucketMutex       sync.RWMutex
+	copyToBucketArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 common.CopyOptions
+	}
+	copyToBucketReturns struct {
+		result1 error
+	}
+	copyToBucketReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteStub        func(context.Context, string) error
 	deleteMutex       sync.RWMutex
 	deleteArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	deleteReturns struct {
 		result1 error
@@ -30,10 +77,12 @@ type FakeStorager struct {
 	deleteReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DeleteRecursiveStub        func(string) error
+	DeleteRecursiveStub        func(context.Context, string, common.DeleteRecursiveOptions) error
 	deleteRecursiveMutex       sync.RWMutex
 	deleteRecursiveArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
+		arg3 common.DeleteRecursiveOptions
 	}
 	deleteRecursiveReturns struct {
 		result1 error
@@ -41,9 +90,10 @@ type FakeStorager struct {
 	deleteRecursiveReturnsOnCall map[int]struct {
 		result1 error
 	}
-	EnsureStorageExistsStub        func() error
+	EnsureStorageExistsStub        func(context.Context) error
 	ensureStorageExistsMutex       sync.RWMutex
 	ensureStorageExistsArgsForCall []struct {
+		arg1 context.Context
 	}
 	ensureStorageExistsReturns struct {
 		result1 error
@@ -51,10 +101,11 @@ type FakeStorager struct {
 	ensureStorageExistsReturnsOnCall map[int]struct {
 		result1 error
 	}
-	ExistsStub        func(string) (bool, error)
+	ExistsStub        func(context.Context, string) (bool, error)
 	existsMutex       sync.RWMutex
 	existsArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	existsReturns struct {
 		result1 bool
@@ -64,11 +115,13 @@ type FakeStorager struct {
 		result1 bool
 		result2 error
 	}
-	GetStub        func(string, string) error
+	GetStub        func(context.Context, string, string, common.GetOptions) error
 	getMutex       sync.RWMutex
 	getArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
+		arg3 string
+		arg4 common.GetOptions
 	}
 	getReturns struct {
 		result1 error
@@ -76,10 +129,53 @@ type FakeStorager struct {
 	getReturnsOnCall map[int]struct {
 		result1 error
 	}
-	ListStub        func(string) ([]string, error)
+	GetRangeStub        func(context.Context, string, string, int64, common.GetOptions) error
+	getRangeMutex       sync.RWMutex
+	getRangeArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 int64
+		arg5 common.GetOptions
+	}
+	getRangeReturns struct {
+		result1 error
+	}
+	getRangeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	HeadStub        func(context.Context, string) (common.ObjectHead, error)
+	headMutex       sync.RWMutex
+	headArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	headReturns struct {
+		result1 common.ObjectHead
+		result2 error
+	}
+	headReturnsOnCall map[int]struct {
+		result1 common.ObjectHead
+		result2 error
+	}
+	IdentityStub        func(context.Context) (common.Identity, error)
+	identityMutex       sync.RWMutex
+	identityArgsForCall []struct {
+		arg1 context.Context
+	}
+	identityReturns struct {
+		result1 common.Identity
+		result2 error
+	}
+	identityReturnsOnCall map[int]struct {
+		result1 common.Identity
+		result2 error
+	}
+	ListStub        func(context.Context, string) ([]string, error)
 	listMutex       sync.RWMutex
 	listArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	listReturns struct {
 		result1 []string
@@ -89,22 +185,86 @@ type FakeStorager struct {
 		result1 []string
 		result2 error
 	}
-	PropertiesStub        func(string) error
+	ListDetailedStub        func(context.Context, string) ([]common.ObjectInfo, error)
+	listDetailedMutex       sync.RWMutex
+	listDetailedArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	listDetailedReturns struct {
+		result1 []common.ObjectInfo
+		result2 error
+	}
+	listDetailedReturnsOnCall map[int]struct {
+		result1 []common.ObjectInfo
+		result2 error
+	}
+	ListDetailedWithLimitStub        func(context.Context, string, int) ([]common.ObjectInfo, error)
+	listDetailedWithLimitMutex       sync.RWMutex
+	listDetailedWithLimitArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+	}
+	listDetailedWithLimitReturns struct {
+		result1 []common.ObjectInfo
+		result2 error
+	}
+	listDetailedWithLimitReturnsOnCall map[int]struct {
+		result1 []common.ObjectInfo
+		result2 error
+	}
+	ListWithLimitStub        func(context.Context, string, int) ([]string, error)
+	listWithLimitMutex       sync.RWMutex
+	listWithLimitArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+	}
+	listWithLimitReturns struct {
+		result1 []string
+		result2 error
+	}
+	listWithLimitReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
+	PropertiesStub        func(context.Context, string) (common.ObjectProperties, error)
 	propertiesMutex       sync.RWMutex
 	propertiesArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	propertiesReturns struct {
-		result1 error
+		result1 common.ObjectProperties
+		result2 error
 	}
 	propertiesReturnsOnCall map[int]struct {
-		result1 error
+		result1 common.ObjectProperties
+		result2 error
 	}
-	PutStub        func(string, string) error
+	PropertiesWithOptionsStub        func(context.Context, string, common.PropertiesOptions) (common.ObjectProperties, error)
+	propertiesWithOptionsMutex       sync.RWMutex
+	propertiesWithOptionsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 common.PropertiesOptions
+	}
+	propertiesWithOptionsReturns struct {
+		result1 common.ObjectProperties
+		result2 error
+	}
+	propertiesWithOptionsReturnsOnCall map[int]struct {
+		result1 common.ObjectProperties
+		result2 error
+	}
+	PutStub        func(context.Context, string, string, common.PutOptions) error
 	putMutex       sync.RWMutex
 	putArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
+		arg3 string
+		arg4 common.PutOptions
 	}
 	putReturns struct {
 		result1 error
@@ -112,12 +272,57 @@ type FakeStorager struct {
 	putReturnsOnCall map[int]struct {
 		result1 error
 	}
-	SignStub        func(string, string, time.Duration) (string, error)
+	PutWithETagStub        func(context.Context, string, string, common.PutOptions) (string, error)
+	putWithETagMutex       sync.RWMutex
+	putWithETagArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 common.PutOptions
+	}
+	putWithETagReturns struct {
+		result1 string
+		result2 error
+	}
+	putWithETagReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	PutWithManifestStub        func(context.Context, string, string, common.UploadManifest, common.PutOptions) error
+	putWithManifestMutex       sync.RWMutex
+	putWithManifestArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 common.UploadManifest
+		arg5 common.PutOptions
+	}
+	putWithManifestReturns struct {
+		result1 error
+	}
+	putWithManifestReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RenameStub        func(context.Context, string, string) error
+	renameMutex       sync.RWMutex
+	renameArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	renameReturns struct {
+		result1 error
+	}
+	renameReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SignStub        func(context.Context, string, string, time.Duration) (string, error)
 	signMutex       sync.RWMutex
 	signArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 time.Duration
+		arg3 string
+		arg4 time.Duration
 	}
 	signReturns struct {
 		result1 string
@@ -127,23 +332,124 @@ type FakeStorager struct {
 		result1 string
 		result2 error
 	}
+	SignWithOptionsStub        func(context.Context, string, string, time.Duration, common.SignOptions) (string, error)
+	signWithOptionsMutex       sync.RWMutex
+	signWithOptionsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 time.Duration
+		arg5 common.SignOptions
+	}
+	signWithOptionsReturns struct {
+		result1 string
+		result2 error
+	}
+	signWithOptionsReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	SizeStub        func(context.Context, string) (int64, error)
+	sizeMutex       sync.RWMutex
+	sizeArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	sizeReturns struct {
+		result1 int64
+		result2 error
+	}
+	sizeReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
+	ValidateSignStub        func(context.Context, string, string, time.Duration, common.SignOptions) error
+	validateSignMutex       sync.RWMutex
+	validateSignArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 time.Duration
+		arg5 common.SignOptions
+	}
+	validateSignReturns struct {
+		result1 error
+	}
+	validateSignReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeStorager) Copy(arg1 string, arg2 string) error {
+func (fake *FakeStorager) Capabilities() []string {
+	fake.capabilitiesMutex.Lock()
+	ret, specificReturn := fake.capabilitiesReturnsOnCall[len(fake.capabilitiesArgsForCall)]
+	fake.capabilitiesArgsForCall = append(fake.capabilitiesArgsForCall, struct {
+	}{})
+	stub := fake.CapabilitiesStub
+	fakeReturns := fake.capabilitiesReturns
+	fake.recordInvocation("Capabilities", []interface{}{})
+	fake.capabilitiesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorager) CapabilitiesCallCount() int {
+	fake.capabilitiesMutex.RLock()
+	defer fake.capabilitiesMutex.RUnlock()
+	return len(fake.capabilitiesArgsForCall)
+}
+
+func (fake *FakeStorager) CapabilitiesCalls(stub func() []string) {
+	fake.capabilitiesMutex.Lock()
+	defer fake.capabilitiesMutex.Unlock()
+	fake.CapabilitiesStub = stub
+}
+
+func (fake *FakeStorager) CapabilitiesReturns(result1 []string) {
+	fake.capabilitiesMutex.Lock()
+	defer fake.capabilitiesMutex.Unlock()
+	fake.CapabilitiesStub = nil
+	fake.capabilitiesReturns = struct {
+		result1 []string
+	}{result1}
+}
+
+func (fake *FakeStorager) CapabilitiesReturnsOnCall(i int, result1 []string) {
+	fake.capabilitiesMutex.Lock()
+	defer fake.capabilitiesMutex.Unlock()
+	fake.CapabilitiesStub = nil
+	if fake.capabilitiesReturnsOnCall == nil {
+		fake.capabilitiesReturnsOnCall = make(map[int]struct {
+			result1 []string
+		})
+	}
+	fake.capabilitiesReturnsOnCall[i] = struct {
+		result1 []string
+	}{result1}
+}
+
+func (fake *FakeStorager) Copy(arg1 context.Context, arg2 string, arg3 string, arg4 common.CopyOptions) error {
 	fake.copyMutex.Lock()
 	ret, specificReturn := fake.copyReturnsOnCall[len(fake.copyArgsForCall)]
 	fake.copyArgsForCall = append(fake.copyArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-	}{arg1, arg2})
+		arg3 string
+		arg4 common.CopyOptions
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.CopyStub
 	fakeReturns := fake.copyReturns
-	fake.recordInvocation("Copy", []interface{}{arg1, arg2})
+	fake.recordInvocation("Copy", []interface{}{arg1, arg2, arg3, arg4})
 	fake.copyMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
@@ -157,17 +463,17 @@ func (fake *FakeStorager) CopyCallCount() int {
 	return len(fake.copyArgsForCall)
 }
 
-func (fake *FakeStorager) CopyCalls(stub func(string, string) error) {
+func (fake *FakeStorager) CopyCalls(stub func(context.Context, string, string, common.CopyOptions) error) {
 	fake.copyMutex.Lock()
 	defer fake.copyMutex.Unlock()
 	fake.CopyStub = stub
 }
 
-func (fake *FakeStorager) CopyArgsForCall(i int) (string, string) {
+func (fake *FakeStorager) CopyArgsForCall(i int) (context.Context, string, string, common.CopyOptions) {
 	fake.copyMutex.RLock()
 	defer fake.copyMutex.RUnlock()
 	argsForCall := fake.copyArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeStorager) CopyReturns(result1 error) {
@@ -193,18 +499,150 @@ func (fake *FakeStorager) CopyReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeStorager) Delete(arg1 string) error {
+func (fake *FakeStorager) CopyFromBucket(arg1 context.Context, arg2 string, arg3 string, arg4 string, arg5 string, arg6 common.CopyOptions) error {
+	fake.copyFromBucketMutex.Lock()
+	ret, specificReturn := fake.copyFromBucketReturnsOnCall[len(fake.copyFromBucketArgsForCall)]
+	fake.copyFromBucketArgsForCall = append(fake.copyFromBucketArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 string
+		arg6 common.CopyOptions
+	}{arg1, arg2, arg3, arg4, arg5, arg6})
+	stub := fake.CopyFromBucketStub
+	fakeReturns := fake.copyFromBucketReturns
+	fake.recordInvocation("CopyFromBucket", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
+	fake.copyFromBucketMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorager) CopyFromBucketCallCount() int {
+	fake.copyFromBucketMutex.RLock()
+	defer fake.copyFromBucketMutex.RUnlock()
+	return len(fake.copyFromBucketArgsForCall)
+}
+
+func (fake *FakeStorager) CopyFromBucketCalls(stub func(context.Context, string, string, string, string, common.CopyOptions) error) {
+	fake.copyFromBucketMutex.Lock()
+	defer fake.copyFromBucketMutex.Unlock()
+	fake.CopyFromBucketStub = stub
+}
+
+func (fake *FakeStorager) CopyFromBucketArgsForCall(i int) (context.Context, string, string, string, string, common.CopyOptions) {
+	fake.copyFromBucketMutex.RLock()
+	defer fake.copyFromBucketMutex.RUnlock()
+	argsForCall := fake.copyFromBucketArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
+}
+
+func (fake *FakeStorager) CopyFromBucketReturns(result1 error) {
+	fake.copyFromBucketMutex.Lock()
+	defer fake.copyFromBucketMutex.Unlock()
+	fake.CopyFromBucketStub = nil
+	fake.copyFromBucketReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorager) CopyFromBucketReturnsOnCall(i int, result1 error) {
+	fake.copyFromBucketMutex.Lock()
+	defer fake.copyFromBucketMutex.Unlock()
+	fake.CopyFromBucketStub = nil
+	if fake.copyFromBucketReturnsOnCall == nil {
+		fake.copyFromBucketReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.copyFromBucketReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorager) CopyToBucket(arg1 context.Context, arg2 string, arg3 string, arg4 string, arg5 common.CopyOptions) error {
+	fake.copyToBucketMutex.Lock()
+	ret, specificReturn := fake.copyToBucketReturnsOnCall[len(fake.copyToBucketArgsForCall)]
+	fake.copyToBucketArgsForCall = append(fake.copyToBucketArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 string
+		arg5 common.CopyOptions
+	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.CopyToBucketStub
+	fakeReturns := fake.copyToBucketReturns
+	fake.recordInvocation("CopyToBucket", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.copyToBucketMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorager) CopyToBucketCallCount() int {
+	fake.copyToBucketMutex.RLock()
+	defer fake.copyToBucketMutex.RUnlock()
+	return len(fake.copyToBucketArgsForCall)
+}
+
+func (fake *FakeStorager) CopyToBucketCalls(stub func(context.Context, string, string, string, common.CopyOptions) error) {
+	fake.copyToBucketMutex.Lock()
+	defer fake.copyToBucketMutex.Unlock()
+	fake.CopyToBucketStub = stub
+}
+
+func (fake *FakeStorager) CopyToBucketArgsForCall(i int) (context.Context, string, string, string, common.CopyOptions) {
+	fake.copyToBucketMutex.RLock()
+	defer fake.copyToBucketMutex.RUnlock()
+	argsForCall := fake.copyToBucketArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeStorager) CopyToBucketReturns(result1 error) {
+	fake.copyToBucketMutex.Lock()
+	defer fake.copyToBucketMutex.Unlock()
+	fake.CopyToBucketStub = nil
+	fake.copyToBucketReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorager) CopyToBucketReturnsOnCall(i int, result1 error) {
+	fake.copyToBucketMutex.Lock()
+	defer fake.copyToBucketMutex.Unlock()
+	fake.CopyToBucketStub = nil
+	if fake.copyToBucketReturnsOnCall == nil {
+		fake.copyToBucketReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.copyToBucketReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorager) Delete(arg1 context.Context, arg2 string) error {
 	fake.deleteMutex.Lock()
 	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
 	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.DeleteStub
 	fakeReturns := fake.deleteReturns
-	fake.recordInvocation("Delete", []interface{}{arg1})
+	fake.recordInvocation("Delete", []interface{}{arg1, arg2})
 	fake.deleteMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -218,17 +656,17 @@ func (fake *FakeStorager) DeleteCallCount() int {
 	return len(fake.deleteArgsForCall)
 }
 
-func (fake *FakeStorager) DeleteCalls(stub func(string) error) {
+func (fake *FakeStorager) DeleteCalls(stub func(context.Context, string) error) {
 	fake.deleteMutex.Lock()
 	defer fake.deleteMutex.Unlock()
 	fake.DeleteStub = stub
 }
 
-func (fake *FakeStorager) DeleteArgsForCall(i int) string {
+func (fake *FakeStorager) DeleteArgsForCall(i int) (context.Context, string) {
 	fake.deleteMutex.RLock()
 	defer fake.deleteMutex.RUnlock()
 	argsForCall := fake.deleteArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeStorager) DeleteReturns(result1 error) {
@@ -254,18 +692,20 @@ func (fake *FakeStorager) DeleteReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeStorager) DeleteRecursive(arg1 string) error {
+func (fake *FakeStorager) DeleteRecursive(arg1 context.Context, arg2 string, arg3 common.DeleteRecursiveOptions) error {
 	fake.deleteRecursiveMutex.Lock()
 	ret, specificReturn := fake.deleteRecursiveReturnsOnCall[len(fake.deleteRecursiveArgsForCall)]
 	fake.deleteRecursiveArgsForCall = append(fake.deleteRecursiveArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+		arg3 common.DeleteRecursiveOptions
+	}{arg1, arg2, arg3})
 	stub := fake.DeleteRecursiveStub
 	fakeReturns := fake.deleteRecursiveReturns
-	fake.recordInvocation("DeleteRecursive", []interface{}{arg1})
+	fake.recordInvocation("DeleteRecursive", []interface{}{arg1, arg2, arg3})
 	fake.deleteRecursiveMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -279,17 +719,17 @@ func (fake *FakeStorager) DeleteRecursiveCallCount() int {
 	return len(fake.deleteRecursiveArgsForCall)
 }
 
-func (fake *FakeStorager) DeleteRecursiveCalls(stub func(string) error) {
+func (fake *FakeStorager) DeleteRecursiveCalls(stub func(context.Context, string, common.DeleteRecursiveOptions) error) {
 	fake.deleteRecursiveMutex.Lock()
 	defer fake.deleteRecursiveMutex.Unlock()
 	fake.DeleteRecursiveStub = stub
 }
 
-func (fake *FakeStorager) DeleteRecursiveArgsForCall(i int) string {
+func (fake *FakeStorager) DeleteRecursiveArgsForCall(i int) (context.Context, string, common.DeleteRecursiveOptions) {
 	fake.deleteRecursiveMutex.RLock()
 	defer fake.deleteRecursiveMutex.RUnlock()
 	argsForCall := fake.deleteRecursiveArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeStorager) DeleteRecursiveReturns(result1 error) {
@@ -315,17 +755,18 @@ func (fake *FakeStorager) DeleteRecursiveReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeStorager) EnsureStorageExists() error {
+func (fake *FakeStorager) EnsureStorageExists(arg1 context.Context) error {
 	fake.ensureStorageExistsMutex.Lock()
 	ret, specificReturn := fake.ensureStorageExistsReturnsOnCall[len(fake.ensureStorageExistsArgsForCall)]
 	fake.ensureStorageExistsArgsForCall = append(fake.ensureStorageExistsArgsForCall, struct {
-	}{})
+		arg1 context.Context
+	}{arg1})
 	stub := fake.EnsureStorageExistsStub
 	fakeReturns := fake.ensureStorageExistsReturns
-	fake.recordInvocation("EnsureStorageExists", []interface{}{})
+	fake.recordInvocation("EnsureStorageExists", []interface{}{arg1})
 	fake.ensureStorageExistsMutex.Unlock()
 	if stub != nil {
-		return stub()
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
@@ -339,12 +780,19 @@ func (fake *FakeStorager) EnsureStorageExistsCallCount() int {
 	return len(fake.ensureStorageExistsArgsForCall)
 }
 
-func (fake *FakeStorager) EnsureStorageExistsCalls(stub func() error) {
+func (fake *FakeStorager) EnsureStorageExistsCalls(stub func(context.Context) error) {
 	fake.ensureStorageExistsMutex.Lock()
 	defer fake.ensureStorageExistsMutex.Unlock()
 	fake.EnsureStorageExistsStub = stub
 }
 
+func (fake *FakeStorager) EnsureStorageExistsArgsForCall(i int) context.Context {
+	fake.ensureStorageExistsMutex.RLock()
+	defer fake.ensureStorageExistsMutex.RUnlock()
+	argsForCall := fake.ensureStorageExistsArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeStorager) EnsureStorageExistsReturns(result1 error) {
 	fake.ensureStorageExistsMutex.Lock()
 	defer fake.ensureStorageExistsMutex.Unlock()
@@ -368,18 +816,19 @@ func (fake *FakeStorager) EnsureStorageExistsReturnsOnCall(i int, result1 error)
 	}{result1}
 }
 
-func (fake *FakeStorager) Exists(arg1 string) (bool, error) {
+func (fake *FakeStorager) Exists(arg1 context.Context, arg2 string) (bool, error) {
 	fake.existsMutex.Lock()
 	ret, specificReturn := fake.existsReturnsOnCall[len(fake.existsArgsForCall)]
 	fake.existsArgsForCall = append(fake.existsArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.ExistsStub
 	fakeReturns := fake.existsReturns
-	fake.recordInvocation("Exists", []interface{}{arg1})
+	fake.recordInvocation("Exists", []interface{}{arg1, arg2})
 	fake.existsMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -393,17 +842,17 @@ func (fake *FakeStorager) ExistsCallCount() int {
 	return len(fake.existsArgsForCall)
 }
 
-func (fake *FakeStorager) ExistsCalls(stub func(string) (bool, error)) {
+func (fake *FakeStorager) ExistsCalls(stub func(context.Context, string) (bool, error)) {
 	fake.existsMutex.Lock()
 	defer fake.existsMutex.Unlock()
 	fake.ExistsStub = stub
 }
 
-func (fake *FakeStorager) ExistsArgsForCall(i int) string {
+func (fake *FakeStorager) ExistsArgsForCall(i int) (context.Context, string) {
 	fake.existsMutex.RLock()
 	defer fake.existsMutex.RUnlock()
 	argsForCall := fake.existsArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeStorager) ExistsReturns(result1 bool, result2 error) {
@@ -432,19 +881,21 @@ func (fake *FakeStorager) ExistsReturnsOnCall(i int, result1 bool, result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeStorager) Get(arg1 string, arg2 string) error {
+func (fake *FakeStorager) Get(arg1 context.Context, arg2 string, arg3 string, arg4 common.GetOptions) error {
 	fake.getMutex.Lock()
 	ret, specificReturn := fake.getReturnsOnCall[len(fake.getArgsForCall)]
 	fake.getArgsForCall = append(fake.getArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-	}{arg1, arg2})
+		arg3 string
+		arg4 common.GetOptions
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.GetStub
 	fakeReturns := fake.getReturns
-	fake.recordInvocation("Get", []interface{}{arg1, arg2})
+	fake.recordInvocation("Get", []interface{}{arg1, arg2, arg3, arg4})
 	fake.getMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
@@ -458,17 +909,17 @@ func (fake *FakeStorager) GetCallCount() int {
 	return len(fake.getArgsForCall)
 }
 
-func (fake *FakeStorager) GetCalls(stub func(string, string) error) {
+func (fake *FakeStorager) GetCalls(stub func(context.Context, string, string, common.GetOptions) error) {
 	fake.getMutex.Lock()
 	defer fake.getMutex.Unlock()
 	fake.GetStub = stub
 }
 
-func (fake *FakeStorager) GetArgsForCall(i int) (string, string) {
+func (fake *FakeStorager) GetArgsForCall(i int) (context.Context, string, string, common.GetOptions) {
 	fake.getMutex.RLock()
 	defer fake.getMutex.RUnlock()
 	argsForCall := fake.getArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeStorager) GetReturns(result1 error) {
@@ -494,53 +945,248 @@ func (fake *FakeStorager) GetReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeStorager) List(arg1 string) ([]string, error) {
-	fake.listMutex.Lock()
-	ret, specificReturn := fake.listReturnsOnCall[len(fake.listArgsForCall)]
-	fake.listArgsForCall = append(fake.listArgsForCall, struct {
-		arg1 string
-	}{arg1})
-	stub := fake.ListStub
-	fakeReturns := fake.listReturns
-	fake.recordInvocation("List", []interface{}{arg1})
-	fake.listMutex.Unlock()
+func (fake *FakeStorager) GetRange(arg1 context.Context, arg2 string, arg3 string, arg4 int64, arg5 common.GetOptions) error {
+	fake.getRangeMutex.Lock()
+	ret, specificReturn := fake.getRangeReturnsOnCall[len(fake.getRangeArgsForCall)]
+	fake.getRangeArgsForCall = append(fake.getRangeArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 int64
+		arg5 common.GetOptions
+	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.GetRangeStub
+	fakeReturns := fake.getRangeReturns
+	fake.recordInvocation("GetRange", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.getRangeMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1
 	}
-	return fakeReturns.result1, fakeReturns.result2
+	return fakeReturns.result1
 }
 
-func (fake *FakeStorager) ListCallCount() int {
-	fake.listMutex.RLock()
-	defer fake.listMutex.RUnlock()
-	return len(fake.listArgsForCall)
+func (fake *FakeStorager) GetRangeCallCount() int {
+	fake.getRangeMutex.RLock()
+	defer fake.getRangeMutex.RUnlock()
+	return len(fake.getRangeArgsForCall)
 }
 
-func (fake *FakeStorager) ListCalls(stub func(string) ([]string, error)) {
-	fake.listMutex.Lock()
-	defer fake.listMutex.Unlock()
-	fake.ListStub = stub
+func (fake *FakeStorager) GetRangeCalls(stub func(context.Context, string, string, int64, common.GetOptions) error) {
+	fake.getRangeMutex.Lock()
+	defer fake.getRangeMutex.Unlock()
+	fake.GetRangeStub = stub
 }
 
-func (fake *FakeStorager) ListArgsForCall(i int) string {
-	fake.listMutex.RLock()
-	defer fake.listMutex.RUnlock()
-	argsForCall := fake.listArgsForCall[i]
-	return argsForCall.arg1
+func (fake *FakeStorager) GetRangeArgsForCall(i int) (context.Context, string, string, int64, common.GetOptions) {
+	fake.getRangeMutex.RLock()
+	defer fake.getRangeMutex.RUnlock()
+	argsForCall := fake.getRangeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
-func (fake *FakeStorager) ListReturns(result1 []string, result2 error) {
-	fake.listMutex.Lock()
-	defer fake.listMutex.Unlock()
-	fake.ListStub = nil
-	fake.listReturns = struct {
-		result1 []string
-		result2 error
-	}{result1, result2}
-}
+func (fake *FakeStorager) GetRangeReturns(result1 error) {
+	fake.getRangeMutex.Lock()
+	defer fake.getRangeMutex.Unlock()
+	fake.GetRangeStub = nil
+	fake.getRangeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorager) GetRangeReturnsOnCall(i int, result1 error) {
+	fake.getRangeMutex.Lock()
+	defer fake.getRangeMutex.Unlock()
+	fake.GetRangeStub = nil
+	if fake.getRangeReturnsOnCall == nil {
+		fake.getRangeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.getRangeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorager) Head(arg1 context.Context, arg2 string) (common.ObjectHead, error) {
+	fake.headMutex.Lock()
+	ret, specificReturn := fake.headReturnsOnCall[len(fake.headArgsForCall)]
+	fake.headArgsForCall = append(fake.headArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.HeadStub
+	fakeReturns := fake.headReturns
+	fake.recordInvocation("Head", []interface{}{arg1, arg2})
+	fake.headMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStorager) HeadCallCount() int {
+	fake.headMutex.RLock()
+	defer fake.headMutex.RUnlock()
+	return len(fake.headArgsForCall)
+}
+
+func (fake *FakeStorager) HeadCalls(stub func(context.Context, string) (common.ObjectHead, error)) {
+	fake.headMutex.Lock()
+	defer fake.headMutex.Unlock()
+	fake.HeadStub = stub
+}
+
+func (fake *FakeStorager) HeadArgsForCall(i int) (context.Context, string) {
+	fake.headMutex.RLock()
+	defer fake.headMutex.RUnlock()
+	argsForCall := fake.headArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStorager) HeadReturns(result1 common.ObjectHead, result2 error) {
+	fake.headMutex.Lock()
+	defer fake.headMutex.Unlock()
+	fake.HeadStub = nil
+	fake.headReturns = struct {
+		result1 common.ObjectHead
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) HeadReturnsOnCall(i int, result1 common.ObjectHead, result2 error) {
+	fake.headMutex.Lock()
+	defer fake.headMutex.Unlock()
+	fake.HeadStub = nil
+	if fake.headReturnsOnCall == nil {
+		fake.headReturnsOnCall = make(map[int]struct {
+			result1 common.ObjectHead
+			result2 error
+		})
+	}
+	fake.headReturnsOnCall[i] = struct {
+		result1 common.ObjectHead
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) Identity(arg1 context.Context) (common.Identity, error) {
+	fake.identityMutex.Lock()
+	ret, specificReturn := fake.identityReturnsOnCall[len(fake.identityArgsForCall)]
+	fake.identityArgsForCall = append(fake.identityArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.IdentityStub
+	fakeReturns := fake.identityReturns
+	fake.recordInvocation("Identity", []interface{}{arg1})
+	fake.identityMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStorager) IdentityCallCount() int {
+	fake.identityMutex.RLock()
+	defer fake.identityMutex.RUnlock()
+	return len(fake.identityArgsForCall)
+}
+
+func (fake *FakeStorager) IdentityCalls(stub func(context.Context) (common.Identity, error)) {
+	fake.identityMutex.Lock()
+	defer fake.identityMutex.Unlock()
+	fake.IdentityStub = stub
+}
+
+func (fake *FakeStorager) IdentityArgsForCall(i int) context.Context {
+	fake.identityMutex.RLock()
+	defer fake.identityMutex.RUnlock()
+	argsForCall := fake.identityArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeStorager) IdentityReturns(result1 common.Identity, result2 error) {
+	fake.identityMutex.Lock()
+	defer fake.identityMutex.Unlock()
+	fake.IdentityStub = nil
+	fake.identityReturns = struct {
+		result1 common.Identity
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) IdentityReturnsOnCall(i int, result1 common.Identity, result2 error) {
+	fake.identityMutex.Lock()
+	defer fake.identityMutex.Unlock()
+	fake.IdentityStub = nil
+	if fake.identityReturnsOnCall == nil {
+		fake.identityReturnsOnCall = make(map[int]struct {
+			result1 common.Identity
+			result2 error
+		})
+	}
+	fake.identityReturnsOnCall[i] = struct {
+		result1 common.Identity
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) List(arg1 context.Context, arg2 string) ([]string, error) {
+	fake.listMutex.Lock()
+	ret, specificReturn := fake.listReturnsOnCall[len(fake.listArgsForCall)]
+	fake.listArgsForCall = append(fake.listArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ListStub
+	fakeReturns := fake.listReturns
+	fake.recordInvocation("List", []interface{}{arg1, arg2})
+	fake.listMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStorager) ListCallCount() int {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	return len(fake.listArgsForCall)
+}
+
+func (fake *FakeStorager) ListCalls(stub func(context.Context, string) ([]string, error)) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = stub
+}
+
+func (fake *FakeStorager) ListArgsForCall(i int) (context.Context, string) {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	argsForCall := fake.listArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStorager) ListReturns(result1 []string, result2 error) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = nil
+	fake.listReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
 
 func (fake *FakeStorager) ListReturnsOnCall(i int, result1 []string, result2 error) {
 	fake.listMutex.Lock()
@@ -558,23 +1204,221 @@ func (fake *FakeStorager) ListReturnsOnCall(i int, result1 []string, result2 err
 	}{result1, result2}
 }
 
-func (fake *FakeStorager) Properties(arg1 string) error {
+func (fake *FakeStorager) ListDetailed(arg1 context.Context, arg2 string) ([]common.ObjectInfo, error) {
+	fake.listDetailedMutex.Lock()
+	ret, specificReturn := fake.listDetailedReturnsOnCall[len(fake.listDetailedArgsForCall)]
+	fake.listDetailedArgsForCall = append(fake.listDetailedArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ListDetailedStub
+	fakeReturns := fake.listDetailedReturns
+	fake.recordInvocation("ListDetailed", []interface{}{arg1, arg2})
+	fake.listDetailedMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStorager) ListDetailedCallCount() int {
+	fake.listDetailedMutex.RLock()
+	defer fake.listDetailedMutex.RUnlock()
+	return len(fake.listDetailedArgsForCall)
+}
+
+func (fake *FakeStorager) ListDetailedCalls(stub func(context.Context, string) ([]common.ObjectInfo, error)) {
+	fake.listDetailedMutex.Lock()
+	defer fake.listDetailedMutex.Unlock()
+	fake.ListDetailedStub = stub
+}
+
+func (fake *FakeStorager) ListDetailedArgsForCall(i int) (context.Context, string) {
+	fake.listDetailedMutex.RLock()
+	defer fake.listDetailedMutex.RUnlock()
+	argsForCall := fake.listDetailedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStorager) ListDetailedReturns(result1 []common.ObjectInfo, result2 error) {
+	fake.listDetailedMutex.Lock()
+	defer fake.listDetailedMutex.Unlock()
+	fake.ListDetailedStub = nil
+	fake.listDetailedReturns = struct {
+		result1 []common.ObjectInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) ListDetailedReturnsOnCall(i int, result1 []common.ObjectInfo, result2 error) {
+	fake.listDetailedMutex.Lock()
+	defer fake.listDetailedMutex.Unlock()
+	fake.ListDetailedStub = nil
+	if fake.listDetailedReturnsOnCall == nil {
+		fake.listDetailedReturnsOnCall = make(map[int]struct {
+			result1 []common.ObjectInfo
+			result2 error
+		})
+	}
+	fake.listDetailedReturnsOnCall[i] = struct {
+		result1 []common.ObjectInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) ListDetailedWithLimit(arg1 context.Context, arg2 string, arg3 int) ([]common.ObjectInfo, error) {
+	fake.listDetailedWithLimitMutex.Lock()
+	ret, specificReturn := fake.listDetailedWithLimitReturnsOnCall[len(fake.listDetailedWithLimitArgsForCall)]
+	fake.listDetailedWithLimitArgsForCall = append(fake.listDetailedWithLimitArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+	}{arg1, arg2, arg3})
+	stub := fake.ListDetailedWithLimitStub
+	fakeReturns := fake.listDetailedWithLimitReturns
+	fake.recordInvocation("ListDetailedWithLimit", []interface{}{arg1, arg2, arg3})
+	fake.listDetailedWithLimitMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStorager) ListDetailedWithLimitCallCount() int {
+	fake.listDetailedWithLimitMutex.RLock()
+	defer fake.listDetailedWithLimitMutex.RUnlock()
+	return len(fake.listDetailedWithLimitArgsForCall)
+}
+
+func (fake *FakeStorager) ListDetailedWithLimitCalls(stub func(context.Context, string, int) ([]common.ObjectInfo, error)) {
+	fake.listDetailedWithLimitMutex.Lock()
+	defer fake.listDetailedWithLimitMutex.Unlock()
+	fake.ListDetailedWithLimitStub = stub
+}
+
+func (fake *FakeStorager) ListDetailedWithLimitArgsForCall(i int) (context.Context, string, int) {
+	fake.listDetailedWithLimitMutex.RLock()
+	defer fake.listDetailedWithLimitMutex.RUnlock()
+	argsForCall := fake.listDetailedWithLimitArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeStorager) ListDetailedWithLimitReturns(result1 []common.ObjectInfo, result2 error) {
+	fake.listDetailedWithLimitMutex.Lock()
+	defer fake.listDetailedWithLimitMutex.Unlock()
+	fake.ListDetailedWithLimitStub = nil
+	fake.listDetailedWithLimitReturns = struct {
+		result1 []common.ObjectInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) ListDetailedWithLimitReturnsOnCall(i int, result1 []common.ObjectInfo, result2 error) {
+	fake.listDetailedWithLimitMutex.Lock()
+	defer fake.listDetailedWithLimitMutex.Unlock()
+	fake.ListDetailedWithLimitStub = nil
+	if fake.listDetailedWithLimitReturnsOnCall == nil {
+		fake.listDetailedWithLimitReturnsOnCall = make(map[int]struct {
+			result1 []common.ObjectInfo
+			result2 error
+		})
+	}
+	fake.listDetailedWithLimitReturnsOnCall[i] = struct {
+		result1 []common.ObjectInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) ListWithLimit(arg1 context.Context, arg2 string, arg3 int) ([]string, error) {
+	fake.listWithLimitMutex.Lock()
+	ret, specificReturn := fake.listWithLimitReturnsOnCall[len(fake.listWithLimitArgsForCall)]
+	fake.listWithLimitArgsForCall = append(fake.listWithLimitArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+	}{arg1, arg2, arg3})
+	stub := fake.ListWithLimitStub
+	fakeReturns := fake.listWithLimitReturns
+	fake.recordInvocation("ListWithLimit", []interface{}{arg1, arg2, arg3})
+	fake.listWithLimitMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStorager) ListWithLimitCallCount() int {
+	fake.listWithLimitMutex.RLock()
+	defer fake.listWithLimitMutex.RUnlock()
+	return len(fake.listWithLimitArgsForCall)
+}
+
+func (fake *FakeStorager) ListWithLimitCalls(stub func(context.Context, string, int) ([]string, error)) {
+	fake.listWithLimitMutex.Lock()
+	defer fake.listWithLimitMutex.Unlock()
+	fake.ListWithLimitStub = stub
+}
+
+func (fake *FakeStorager) ListWithLimitArgsForCall(i int) (context.Context, string, int) {
+	fake.listWithLimitMutex.RLock()
+	defer fake.listWithLimitMutex.RUnlock()
+	argsForCall := fake.listWithLimitArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeStorager) ListWithLimitReturns(result1 []string, result2 error) {
+	fake.listWithLimitMutex.Lock()
+	defer fake.listWithLimitMutex.Unlock()
+	fake.ListWithLimitStub = nil
+	fake.listWithLimitReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) ListWithLimitReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.listWithLimitMutex.Lock()
+	defer fake.listWithLimitMutex.Unlock()
+	fake.ListWithLimitStub = nil
+	if fake.listWithLimitReturnsOnCall == nil {
+		fake.listWithLimitReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.listWithLimitReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) Properties(arg1 context.Context, arg2 string) (common.ObjectProperties, error) {
 	fake.propertiesMutex.Lock()
 	ret, specificReturn := fake.propertiesReturnsOnCall[len(fake.propertiesArgsForCall)]
 	fake.propertiesArgsForCall = append(fake.propertiesArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.PropertiesStub
 	fakeReturns := fake.propertiesReturns
-	fake.recordInvocation("Properties", []interface{}{arg1})
+	fake.recordInvocation("Properties", []interface{}{arg1, arg2})
 	fake.propertiesMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeStorager) PropertiesCallCount() int {
@@ -583,55 +1427,126 @@ func (fake *FakeStorager) PropertiesCallCount() int {
 	return len(fake.propertiesArgsForCall)
 }
 
-func (fake *FakeStorager) PropertiesCalls(stub func(string) error) {
+func (fake *FakeStorager) PropertiesCalls(stub func(context.Context, string) (common.ObjectProperties, error)) {
 	fake.propertiesMutex.Lock()
 	defer fake.propertiesMutex.Unlock()
 	fake.PropertiesStub = stub
 }
 
-func (fake *FakeStorager) PropertiesArgsForCall(i int) string {
+func (fake *FakeStorager) PropertiesArgsForCall(i int) (context.Context, string) {
 	fake.propertiesMutex.RLock()
 	defer fake.propertiesMutex.RUnlock()
 	argsForCall := fake.propertiesArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeStorager) PropertiesReturns(result1 error) {
+func (fake *FakeStorager) PropertiesReturns(result1 common.ObjectProperties, result2 error) {
 	fake.propertiesMutex.Lock()
 	defer fake.propertiesMutex.Unlock()
 	fake.PropertiesStub = nil
 	fake.propertiesReturns = struct {
-		result1 error
-	}{result1}
+		result1 common.ObjectProperties
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeStorager) PropertiesReturnsOnCall(i int, result1 error) {
+func (fake *FakeStorager) PropertiesReturnsOnCall(i int, result1 common.ObjectProperties, result2 error) {
 	fake.propertiesMutex.Lock()
 	defer fake.propertiesMutex.Unlock()
 	fake.PropertiesStub = nil
 	if fake.propertiesReturnsOnCall == nil {
 		fake.propertiesReturnsOnCall = make(map[int]struct {
-			result1 error
+			result1 common.ObjectProperties
+			result2 error
 		})
 	}
 	fake.propertiesReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
+		result1 common.ObjectProperties
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) PropertiesWithOptions(arg1 context.Context, arg2 string, arg3 common.PropertiesOptions) (common.ObjectProperties, error) {
+	fake.propertiesWithOptionsMutex.Lock()
+	ret, specificReturn := fake.propertiesWithOptionsReturnsOnCall[len(fake.propertiesWithOptionsArgsForCall)]
+	fake.propertiesWithOptionsArgsForCall = append(fake.propertiesWithOptionsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 common.PropertiesOptions
+	}{arg1, arg2, arg3})
+	stub := fake.PropertiesWithOptionsStub
+	fakeReturns := fake.propertiesWithOptionsReturns
+	fake.recordInvocation("PropertiesWithOptions", []interface{}{arg1, arg2, arg3})
+	fake.propertiesWithOptionsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStorager) PropertiesWithOptionsCallCount() int {
+	fake.propertiesWithOptionsMutex.RLock()
+	defer fake.propertiesWithOptionsMutex.RUnlock()
+	return len(fake.propertiesWithOptionsArgsForCall)
+}
+
+func (fake *FakeStorager) PropertiesWithOptionsCalls(stub func(context.Context, string, common.PropertiesOptions) (common.ObjectProperties, error)) {
+	fake.propertiesWithOptionsMutex.Lock()
+	defer fake.propertiesWithOptionsMutex.Unlock()
+	fake.PropertiesWithOptionsStub = stub
+}
+
+func (fake *FakeStorager) PropertiesWithOptionsArgsForCall(i int) (context.Context, string, common.PropertiesOptions) {
+	fake.propertiesWithOptionsMutex.RLock()
+	defer fake.propertiesWithOptionsMutex.RUnlock()
+	argsForCall := fake.propertiesWithOptionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *FakeStorager) Put(arg1 string, arg2 string) error {
+func (fake *FakeStorager) PropertiesWithOptionsReturns(result1 common.ObjectProperties, result2 error) {
+	fake.propertiesWithOptionsMutex.Lock()
+	defer fake.propertiesWithOptionsMutex.Unlock()
+	fake.PropertiesWithOptionsStub = nil
+	fake.propertiesWithOptionsReturns = struct {
+		result1 common.ObjectProperties
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) PropertiesWithOptionsReturnsOnCall(i int, result1 common.ObjectProperties, result2 error) {
+	fake.propertiesWithOptionsMutex.Lock()
+	defer fake.propertiesWithOptionsMutex.Unlock()
+	fake.PropertiesWithOptionsStub = nil
+	if fake.propertiesWithOptionsReturnsOnCall == nil {
+		fake.propertiesWithOptionsReturnsOnCall = make(map[int]struct {
+			result1 common.ObjectProperties
+			result2 error
+		})
+	}
+	fake.propertiesWithOptionsReturnsOnCall[i] = struct {
+		result1 common.ObjectProperties
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) Put(arg1 context.Context, arg2 string, arg3 string, arg4 common.PutOptions) error {
 	fake.putMutex.Lock()
 	ret, specificReturn := fake.putReturnsOnCall[len(fake.putArgsForCall)]
 	fake.putArgsForCall = append(fake.putArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-	}{arg1, arg2})
+		arg3 string
+		arg4 common.PutOptions
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.PutStub
 	fakeReturns := fake.putReturns
-	fake.recordInvocation("Put", []interface{}{arg1, arg2})
+	fake.recordInvocation("Put", []interface{}{arg1, arg2, arg3, arg4})
 	fake.putMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
@@ -645,17 +1560,17 @@ func (fake *FakeStorager) PutCallCount() int {
 	return len(fake.putArgsForCall)
 }
 
-func (fake *FakeStorager) PutCalls(stub func(string, string) error) {
+func (fake *FakeStorager) PutCalls(stub func(context.Context, string, string, common.PutOptions) error) {
 	fake.putMutex.Lock()
 	defer fake.putMutex.Unlock()
 	fake.PutStub = stub
 }
 
-func (fake *FakeStorager) PutArgsForCall(i int) (string, string) {
+func (fake *FakeStorager) PutArgsForCall(i int) (context.Context, string, string, common.PutOptions) {
 	fake.putMutex.RLock()
 	defer fake.putMutex.RUnlock()
 	argsForCall := fake.putArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeStorager) PutReturns(result1 error) {
@@ -681,20 +1596,216 @@ func (fake *FakeStorager) PutReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeStorager) Sign(arg1 string, arg2 string, arg3 time.Duration) (string, error) {
+func (fake *FakeStorager) PutWithETag(arg1 context.Context, arg2 string, arg3 string, arg4 common.PutOptions) (string, error) {
+	fake.putWithETagMutex.Lock()
+	ret, specificReturn := fake.putWithETagReturnsOnCall[len(fake.putWithETagArgsForCall)]
+	fake.putWithETagArgsForCall = append(fake.putWithETagArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 common.PutOptions
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.PutWithETagStub
+	fakeReturns := fake.putWithETagReturns
+	fake.recordInvocation("PutWithETag", []interface{}{arg1, arg2, arg3, arg4})
+	fake.putWithETagMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStorager) PutWithETagCallCount() int {
+	fake.putWithETagMutex.RLock()
+	defer fake.putWithETagMutex.RUnlock()
+	return len(fake.putWithETagArgsForCall)
+}
+
+func (fake *FakeStorager) PutWithETagCalls(stub func(context.Context, string, string, common.PutOptions) (string, error)) {
+	fake.putWithETagMutex.Lock()
+	defer fake.putWithETagMutex.Unlock()
+	fake.PutWithETagStub = stub
+}
+
+func (fake *FakeStorager) PutWithETagArgsForCall(i int) (context.Context, string, string, common.PutOptions) {
+	fake.putWithETagMutex.RLock()
+	defer fake.putWithETagMutex.RUnlock()
+	argsForCall := fake.putWithETagArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeStorager) PutWithETagReturns(result1 string, result2 error) {
+	fake.putWithETagMutex.Lock()
+	defer fake.putWithETagMutex.Unlock()
+	fake.PutWithETagStub = nil
+	fake.putWithETagReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) PutWithETagReturnsOnCall(i int, result1 string, result2 error) {
+	fake.putWithETagMutex.Lock()
+	defer fake.putWithETagMutex.Unlock()
+	fake.PutWithETagStub = nil
+	if fake.putWithETagReturnsOnCall == nil {
+		fake.putWithETagReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.putWithETagReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) PutWithManifest(arg1 context.Context, arg2 string, arg3 string, arg4 common.UploadManifest, arg5 common.PutOptions) error {
+	fake.putWithManifestMutex.Lock()
+	ret, specificReturn := fake.putWithManifestReturnsOnCall[len(fake.putWithManifestArgsForCall)]
+	fake.putWithManifestArgsForCall = append(fake.putWithManifestArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 common.UploadManifest
+		arg5 common.PutOptions
+	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.PutWithManifestStub
+	fakeReturns := fake.putWithManifestReturns
+	fake.recordInvocation("PutWithManifest", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.putWithManifestMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorager) PutWithManifestCallCount() int {
+	fake.putWithManifestMutex.RLock()
+	defer fake.putWithManifestMutex.RUnlock()
+	return len(fake.putWithManifestArgsForCall)
+}
+
+func (fake *FakeStorager) PutWithManifestCalls(stub func(context.Context, string, string, common.UploadManifest, common.PutOptions) error) {
+	fake.putWithManifestMutex.Lock()
+	defer fake.putWithManifestMutex.Unlock()
+	fake.PutWithManifestStub = stub
+}
+
+func (fake *FakeStorager) PutWithManifestArgsForCall(i int) (context.Context, string, string, common.UploadManifest, common.PutOptions) {
+	fake.putWithManifestMutex.RLock()
+	defer fake.putWithManifestMutex.RUnlock()
+	argsForCall := fake.putWithManifestArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeStorager) PutWithManifestReturns(result1 error) {
+	fake.putWithManifestMutex.Lock()
+	defer fake.putWithManifestMutex.Unlock()
+	fake.PutWithManifestStub = nil
+	fake.putWithManifestReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorager) PutWithManifestReturnsOnCall(i int, result1 error) {
+	fake.putWithManifestMutex.Lock()
+	defer fake.putWithManifestMutex.Unlock()
+	fake.PutWithManifestStub = nil
+	if fake.putWithManifestReturnsOnCall == nil {
+		fake.putWithManifestReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.putWithManifestReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorager) Rename(arg1 context.Context, arg2 string, arg3 string) error {
+	fake.renameMutex.Lock()
+	ret, specificReturn := fake.renameReturnsOnCall[len(fake.renameArgsForCall)]
+	fake.renameArgsForCall = append(fake.renameArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.RenameStub
+	fakeReturns := fake.renameReturns
+	fake.recordInvocation("Rename", []interface{}{arg1, arg2, arg3})
+	fake.renameMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorager) RenameCallCount() int {
+	fake.renameMutex.RLock()
+	defer fake.renameMutex.RUnlock()
+	return len(fake.renameArgsForCall)
+}
+
+func (fake *FakeStorager) RenameCalls(stub func(context.Context, string, string) error) {
+	fake.renameMutex.Lock()
+	defer fake.renameMutex.Unlock()
+	fake.RenameStub = stub
+}
+
+func (fake *FakeStorager) RenameArgsForCall(i int) (context.Context, string, string) {
+	fake.renameMutex.RLock()
+	defer fake.renameMutex.RUnlock()
+	argsForCall := fake.renameArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeStorager) RenameReturns(result1 error) {
+	fake.renameMutex.Lock()
+	defer fake.renameMutex.Unlock()
+	fake.RenameStub = nil
+	fake.renameReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorager) RenameReturnsOnCall(i int, result1 error) {
+	fake.renameMutex.Lock()
+	defer fake.renameMutex.Unlock()
+	fake.RenameStub = nil
+	if fake.renameReturnsOnCall == nil {
+		fake.renameReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.renameReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorager) Sign(arg1 context.Context, arg2 string, arg3 string, arg4 time.Duration) (string, error) {
 	fake.signMutex.Lock()
 	ret, specificReturn := fake.signReturnsOnCall[len(fake.signArgsForCall)]
 	fake.signArgsForCall = append(fake.signArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 time.Duration
-	}{arg1, arg2, arg3})
+		arg3 string
+		arg4 time.Duration
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.SignStub
 	fakeReturns := fake.signReturns
-	fake.recordInvocation("Sign", []interface{}{arg1, arg2, arg3})
+	fake.recordInvocation("Sign", []interface{}{arg1, arg2, arg3, arg4})
 	fake.signMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -708,17 +1819,17 @@ func (fake *FakeStorager) SignCallCount() int {
 	return len(fake.signArgsForCall)
 }
 
-func (fake *FakeStorager) SignCalls(stub func(string, string, time.Duration) (string, error)) {
+func (fake *FakeStorager) SignCalls(stub func(context.Context, string, string, time.Duration) (string, error)) {
 	fake.signMutex.Lock()
 	defer fake.signMutex.Unlock()
 	fake.SignStub = stub
 }
 
-func (fake *FakeStorager) SignArgsForCall(i int) (string, string, time.Duration) {
+func (fake *FakeStorager) SignArgsForCall(i int) (context.Context, string, string, time.Duration) {
 	fake.signMutex.RLock()
 	defer fake.signMutex.RUnlock()
 	argsForCall := fake.signArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeStorager) SignReturns(result1 string, result2 error) {
@@ -747,6 +1858,204 @@ func (fake *FakeStorager) SignReturnsOnCall(i int, result1 string, result2 error
 	}{result1, result2}
 }
 
+func (fake *FakeStorager) SignWithOptions(arg1 context.Context, arg2 string, arg3 string, arg4 time.Duration, arg5 common.SignOptions) (string, error) {
+	fake.signWithOptionsMutex.Lock()
+	ret, specificReturn := fake.signWithOptionsReturnsOnCall[len(fake.signWithOptionsArgsForCall)]
+	fake.signWithOptionsArgsForCall = append(fake.signWithOptionsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 time.Duration
+		arg5 common.SignOptions
+	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.SignWithOptionsStub
+	fakeReturns := fake.signWithOptionsReturns
+	fake.recordInvocation("SignWithOptions", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.signWithOptionsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStorager) SignWithOptionsCallCount() int {
+	fake.signWithOptionsMutex.RLock()
+	defer fake.signWithOptionsMutex.RUnlock()
+	return len(fake.signWithOptionsArgsForCall)
+}
+
+func (fake *FakeStorager) SignWithOptionsCalls(stub func(context.Context, string, string, time.Duration, common.SignOptions) (string, error)) {
+	fake.signWithOptionsMutex.Lock()
+	defer fake.signWithOptionsMutex.Unlock()
+	fake.SignWithOptionsStub = stub
+}
+
+func (fake *FakeStorager) SignWithOptionsArgsForCall(i int) (context.Context, string, string, time.Duration, common.SignOptions) {
+	fake.signWithOptionsMutex.RLock()
+	defer fake.signWithOptionsMutex.RUnlock()
+	argsForCall := fake.signWithOptionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeStorager) SignWithOptionsReturns(result1 string, result2 error) {
+	fake.signWithOptionsMutex.Lock()
+	defer fake.signWithOptionsMutex.Unlock()
+	fake.SignWithOptionsStub = nil
+	fake.signWithOptionsReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) SignWithOptionsReturnsOnCall(i int, result1 string, result2 error) {
+	fake.signWithOptionsMutex.Lock()
+	defer fake.signWithOptionsMutex.Unlock()
+	fake.SignWithOptionsStub = nil
+	if fake.signWithOptionsReturnsOnCall == nil {
+		fake.signWithOptionsReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.signWithOptionsReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) Size(arg1 context.Context, arg2 string) (int64, error) {
+	fake.sizeMutex.Lock()
+	ret, specificReturn := fake.sizeReturnsOnCall[len(fake.sizeArgsForCall)]
+	fake.sizeArgsForCall = append(fake.sizeArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.SizeStub
+	fakeReturns := fake.sizeReturns
+	fake.recordInvocation("Size", []interface{}{arg1, arg2})
+	fake.sizeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeStorager) SizeCallCount() int {
+	fake.sizeMutex.RLock()
+	defer fake.sizeMutex.RUnlock()
+	return len(fake.sizeArgsForCall)
+}
+
+func (fake *FakeStorager) SizeCalls(stub func(context.Context, string) (int64, error)) {
+	fake.sizeMutex.Lock()
+	defer fake.sizeMutex.Unlock()
+	fake.SizeStub = stub
+}
+
+func (fake *FakeStorager) SizeArgsForCall(i int) (context.Context, string) {
+	fake.sizeMutex.RLock()
+	defer fake.sizeMutex.RUnlock()
+	argsForCall := fake.sizeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeStorager) SizeReturns(result1 int64, result2 error) {
+	fake.sizeMutex.Lock()
+	defer fake.sizeMutex.Unlock()
+	fake.SizeStub = nil
+	fake.sizeReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) SizeReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.sizeMutex.Lock()
+	defer fake.sizeMutex.Unlock()
+	fake.SizeStub = nil
+	if fake.sizeReturnsOnCall == nil {
+		fake.sizeReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
+		})
+	}
+	fake.sizeReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStorager) ValidateSign(arg1 context.Context, arg2 string, arg3 string, arg4 time.Duration, arg5 common.SignOptions) error {
+	fake.validateSignMutex.Lock()
+	ret, specificReturn := fake.validateSignReturnsOnCall[len(fake.validateSignArgsForCall)]
+	fake.validateSignArgsForCall = append(fake.validateSignArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+		arg4 time.Duration
+		arg5 common.SignOptions
+	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.ValidateSignStub
+	fakeReturns := fake.validateSignReturns
+	fake.recordInvocation("ValidateSign", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.validateSignMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeStorager) ValidateSignCallCount() int {
+	fake.validateSignMutex.RLock()
+	defer fake.validateSignMutex.RUnlock()
+	return len(fake.validateSignArgsForCall)
+}
+
+func (fake *FakeStorager) ValidateSignCalls(stub func(context.Context, string, string, time.Duration, common.SignOptions) error) {
+	fake.validateSignMutex.Lock()
+	defer fake.validateSignMutex.Unlock()
+	fake.ValidateSignStub = stub
+}
+
+func (fake *FakeStorager) ValidateSignArgsForCall(i int) (context.Context, string, string, time.Duration, common.SignOptions) {
+	fake.validateSignMutex.RLock()
+	defer fake.validateSignMutex.RUnlock()
+	argsForCall := fake.validateSignArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeStorager) ValidateSignReturns(result1 error) {
+	fake.validateSignMutex.Lock()
+	defer fake.validateSignMutex.Unlock()
+	fake.ValidateSignStub = nil
+	fake.validateSignReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStorager) ValidateSignReturnsOnCall(i int, result1 error) {
+	fake.validateSignMutex.Lock()
+	defer fake.validateSignMutex.Unlock()
+	fake.ValidateSignStub = nil
+	if fake.validateSignReturnsOnCall == nil {
+		fake.validateSignReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.validateSignReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeStorager) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
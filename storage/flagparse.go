@@ -0,0 +1,24 @@
+package storage
+
+import "flag"
+
+// parseFlags parses args with flags and returns the positional arguments. Unlike FlagSet.Parse,
+// which stops at the first positional argument, flags may come before, between and after them,
+// e.g. `list some/prefix --limit 3`. Everything after "--" is positional, also arguments that
+// start with a dash.
+func parseFlags(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := flags.Args()
+		parsed := len(args) - len(rest)
+		if len(rest) == 0 || (parsed > 0 && args[parsed-1] == "--") {
+			return append(positional, rest...), nil
+		}
+
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+// folderMarkers returns the keys tools that emulate folders use as marker for prefix: the prefix
+// itself without and with a trailing slash. There are none for the bucket root.
+func folderMarkers(prefix string) []string {
+	folder := strings.TrimRight(prefix, "/")
+	if folder == "" {
+		return nil
+	}
+	return []string{folder, folder + "/"}
+}
+
+// existingFolderMarkers returns the folder markers of prefix that exist, leaving out those in listed.
+// Only empty objects count as markers, a file that happens to be named like the folder is kept.
+func (sty *CommandExecuter) existingFolderMarkers(ctx context.Context, prefix string, listed []string) ([]string, error) {
+	var markers []string
+	for _, marker := range folderMarkers(prefix) {
+		if slices.Contains(listed, marker) {
+			continue
+		}
+		exists, err := sty.str.Exists(ctx, marker)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check folder marker %s: %w", marker, err)
+		}
+		if !exists {
+			continue
+		}
+		size, err := sty.str.Size(ctx, marker)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check folder marker %s: %w", marker, err)
+		}
+		if size != 0 {
+			slog.Info("Keeping object named like the folder, it is not an empty folder marker", "object", marker, "size", size)
+			continue
+		}
+		markers = append(markers, marker)
+	}
+	return markers, nil
+}
+
+// deleteRecursive deletes the objects under prefix and then the empty folder markers of prefix the
+// listing doesn't cover, e.g. "logs" for the prefix "logs/"
+func (sty *CommandExecuter) deleteRecursive(ctx context.Context, prefix string, options common.DeleteRecursiveOptions) error {
+	err := sty.str.DeleteRecursive(ctx, prefix, options)
+	if err != nil && !options.ContinueOnError {
+		return err
+	}
+	errs := []error{err}
+
+	markers, markerErr := sty.existingFolderMarkers(ctx, prefix, nil)
+	if markerErr != nil {
+		return errors.Join(append(errs, markerErr)...)
+	}
+	for _, marker := range markers {
+		slog.Info("Deleting folder marker", "object", marker)
+		if err := sty.str.Delete(ctx, marker); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete folder marker %s: %w", marker, err))
+			if !options.ContinueOnError {
+				break
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
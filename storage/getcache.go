@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+// cachedGet downloads src to dst through a local cache in cacheDir. Cached copies are kept in
+// <cacheDir>/<sha256 of the object>/<sha256 of its ETag>, so a copy is only used while the object
+// still has the ETag it was downloaded with. An object without an ETag is downloaded as usual.
+func (sty *CommandExecuter) cachedGet(ctx context.Context, src string, dst string, cacheDir string, options common.GetOptions) error {
+	properties, found, err := sty.fetchProperties(ctx, src, common.PropertiesOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get properties: %w", err)
+	}
+	// Leave reporting a missing object to the backend's Get
+	if !found || properties.ETag == "" {
+		return sty.str.Get(ctx, src, dst, options)
+	}
+
+	entryDir := filepath.Join(cacheDir, sha256Hex(src))
+	entry := filepath.Join(entryDir, sha256Hex(properties.ETag))
+	err = copyFile(entry, dst)
+	if err == nil {
+		slog.Info("Copied object from the cache", "object", src, "etag", properties.ETag, "cache_entry", entry)
+		return nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to copy from the cache: %w", err)
+	}
+
+	if err := sty.str.Get(ctx, src, dst, options); err != nil {
+		return err
+	}
+
+	// The object may have been replaced during the download, only cache it under an ETag it is known to have
+	current, found, err := sty.fetchProperties(ctx, src, common.PropertiesOptions{})
+	if err != nil || !found || current.ETag != properties.ETag {
+		slog.Warn("Not caching the object, it changed during the download", "object", src)
+		return nil
+	}
+	if err := populateCache(entryDir, entry, dst); err != nil {
+		slog.Warn("Failed to populate the cache", "object", src, "error", err)
+	}
+	return nil
+}
+
+// populateCache copies the downloaded file into entry and removes the copies of older
+// versions of the object next to it
+func populateCache(entryDir string, entry string, downloaded string) error {
+	if err := os.MkdirAll(entryDir, 0755); err != nil {
+		return err
+	}
+
+	// Copy under a temporary name first, so that an interrupted copy is never taken for a cached object
+	tempFile, err := os.CreateTemp(entryDir, ".download-")
+	if err != nil {
+		return err
+	}
+	tempFile.Close()                 //nolint:errcheck
+	defer os.Remove(tempFile.Name()) //nolint:errcheck
+
+	if err := copyFile(downloaded, tempFile.Name()); err != nil {
+		return err
+	}
+	if err := os.Rename(tempFile.Name(), entry); err != nil {
+		return err
+	}
+
+	stale, err := filepath.Glob(filepath.Join(entryDir, "*"))
+	if err != nil {
+		return err
+	}
+	for _, path := range stale {
+		// Temporary files belong to downloads that are still being cached
+		if path != entry && !strings.HasPrefix(filepath.Base(path), ".") {
+			os.Remove(path) //nolint:errcheck
+		}
+	}
+	return nil
+}
+
+// copyFile copies the content of src to dst, replacing dst if it exists
+func copyFile(src string, dst string) error {
+	source, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer source.Close() //nolint:errcheck
+
+	destination, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(destination, source); err != nil {
+		destination.Close() //nolint:errcheck
+		return err
+	}
+	return destination.Close()
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/storage-cli/common"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("get --cache-dir", func() {
+	var (
+		commandExecuter *CommandExecuter
+		fakeStorager    *FakeStorager
+		etag            string
+		content         string
+		cacheDir        string
+		dst             string
+	)
+
+	BeforeEach(func() {
+		fakeStorager = &FakeStorager{}
+		commandExecuter = NewCommandExecuter(fakeStorager)
+
+		etag = "some-etag"
+		content = "some content"
+		fakeStorager.PropertiesStub = func(context.Context, string) (common.ObjectProperties, error) {
+			return common.ObjectProperties{ETag: etag, ContentLength: int64(len(content))}, nil
+		}
+		fakeStorager.GetStub = func(_ context.Context, _ string, dst string, _ common.GetOptions) error {
+			return os.WriteFile(dst, []byte(content), 0644)
+		}
+
+		cacheDir = filepath.Join(GinkgoT().TempDir(), "cache")
+		dst = filepath.Join(GinkgoT().TempDir(), "object")
+	})
+
+	cachedEntries := func() []string {
+		entries, err := filepath.Glob(filepath.Join(cacheDir, "*", "*"))
+		Expect(err).ToNot(HaveOccurred())
+		return entries
+	}
+
+	It("downloads on a miss and populates the cache", func() {
+		Expect(commandExecuter.Execute(context.Background(), "get", []string{"--cache-dir", cacheDir, "object", dst})).To(Succeed())
+
+		Expect(fakeStorager.GetCallCount()).To(Equal(1))
+		Expect(os.ReadFile(dst)).To(BeEquivalentTo("some content"))
+		Expect(cachedEntries()).To(HaveLen(1))
+		Expect(os.ReadFile(cachedEntries()[0])).To(BeEquivalentTo("some content"))
+	})
+
+	It("copies from the cache on a hit without downloading", func() {
+		Expect(commandExecuter.Execute(context.Background(), "get", []string{"--cache-dir", cacheDir, "object", dst})).To(Succeed())
+		Expect(os.Remove(dst)).To(Succeed())
+
+		Expect(commandExecuter.Execute(context.Background(), "get", []string{"--cache-dir", cacheDir, "object", dst})).To(Succeed())
+		Expect(fakeStorager.GetCallCount()).To(Equal(1))
+		Expect(os.ReadFile(dst)).To(BeEquivalentTo("some content"))
+	})
+
+	It("downloads again once the ETag changed and replaces the cached copy", func() {
+		Expect(commandExecuter.Execute(context.Background(), "get", []string{"--cache-dir", cacheDir, "object", dst})).To(Succeed())
+
+		etag, content = "other-etag", "other content"
+		Expect(commandExecuter.Execute(context.Background(), "get", []string{"--cache-dir", cacheDir, "object", dst})).To(Succeed())
+		Expect(fakeStorager.GetCallCount()).To(Equal(2))
+		Expect(os.ReadFile(dst)).To(BeEquivalentTo("other content"))
+		Expect(cachedEntries()).To(HaveLen(1))
+		Expect(os.ReadFile(cachedEntries()[0])).To(BeEquivalentTo("other content"))
+	})
+
+	It("doesn't cache an object that changed during the download", func() {
+		fakeStorager.GetStub = func(_ context.Context, _ string, dst string, _ common.GetOptions) error {
+			etag = "other-etag"
+			return os.WriteFile(dst, []byte(content), 0644)
+		}
+
+		Expect(commandExecuter.Execute(context.Background(), "get", []string{"--cache-dir", cacheDir, "object", dst})).To(Succeed())
+		Expect(os.ReadFile(dst)).To(BeEquivalentTo("some content"))
+		Expect(cachedEntries()).To(BeEmpty())
+	})
+
+	It("leaves a missing object to the backend", func() {
+		fakeStorager.PropertiesReturns(common.ObjectProperties{}, common.ErrObjectNotFound)
+		fakeStorager.GetReturns(fmt.Errorf("object not found"))
+
+		err := commandExecuter.Execute(context.Background(), "get", []string{"--cache-dir", cacheDir, "object", dst})
+		Expect(err).To(MatchError("object not found"))
+		Expect(fakeStorager.GetCallCount()).To(Equal(1))
+	})
+
+	It("can't be combined with --continue", func() {
+		err := commandExecuter.Execute(context.Background(), "get", []string{"--cache-dir", cacheDir, "--continue", "object", dst})
+		Expect(err).To(MatchError("--continue can't be combined with --cache-dir"))
+		Expect(fakeStorager.GetCallCount()).To(Equal(0))
+	})
+})
@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+)
+
+// LogIdentity logs the credentials source and principal the client resolved to.
+// Resolving the identity may cost an extra request, so callers should only do this when debugging.
+func LogIdentity(ctx context.Context, s Storager) {
+	identity, err := s.Identity(ctx)
+	if err != nil {
+		slog.Debug("Failed to resolve identity", "error", err)
+		return
+	}
+	slog.Debug("Resolved identity", "credentials_source", identity.CredentialsSource, "principal", identity.Principal)
+}
+
+func printIdentity(ctx context.Context, s Storager) error {
+	identity, err := s.Identity(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to resolve identity: %w", err)
+	}
+
+	output, err := json.MarshalIndent(identity, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal identity: %w", err)
+	}
+
+	fmt.Println(string(output))
+	return nil
+}
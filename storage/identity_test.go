@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/cloudfoundry/storage-cli/common"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LogIdentity", func() {
+	var (
+		fakeStorager *FakeStorager
+		logs         *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		fakeStorager = &FakeStorager{}
+		logs = &bytes.Buffer{}
+
+		original := slog.Default()
+		slog.SetDefault(slog.New(slog.NewJSONHandler(logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+		DeferCleanup(func() {
+			slog.SetDefault(original)
+		})
+	})
+
+	It("logs the credentials source and principal", func() {
+		fakeStorager.IdentityReturns(common.Identity{CredentialsSource: "static", Principal: "arn:aws:iam::123456789012:user/some-user"}, nil)
+
+		LogIdentity(context.Background(), fakeStorager)
+
+		Expect(logs.String()).To(ContainSubstring(`"credentials_source":"static"`))
+		Expect(logs.String()).To(ContainSubstring(`"principal":"arn:aws:iam::123456789012:user/some-user"`))
+	})
+
+	It("logs why the identity could not be resolved", func() {
+		fakeStorager.IdentityReturns(common.Identity{}, errors.New("boom"))
+
+		LogIdentity(context.Background(), fakeStorager)
+
+		Expect(logs.String()).To(ContainSubstring("Failed to resolve identity"))
+		Expect(logs.String()).To(ContainSubstring("boom"))
+	})
+})
@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+// keyPrefixConfigKey names the prefix every backend config may set for all object keys. Like the
+// provider of a profile it is removed before the config is handed to the backend.
+const keyPrefixConfigKey = "key_prefix"
+
+// extractKeyPrefix returns the key prefix set in the config and the config without it. A config
+// that can't be parsed is passed on as is, so the backend reports the error as usual.
+func extractKeyPrefix(configFile io.Reader) (string, io.Reader, error) {
+	raw, err := io.ReadAll(configFile)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var config map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return "", bytes.NewReader(raw), nil
+	}
+	rawPrefix, ok := config[keyPrefixConfigKey]
+	if !ok {
+		return "", bytes.NewReader(raw), nil
+	}
+
+	var prefix string
+	if err := json.Unmarshal(rawPrefix, &prefix); err != nil {
+		return "", nil, fmt.Errorf("%s must be a string", keyPrefixConfigKey)
+	}
+	delete(config, keyPrefixConfigKey)
+	backendConfig, err := json.Marshal(config)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	return prefix, bytes.NewReader(backendConfig), nil
+}
+
+// removeConfigKey returns the config without key. Like with extractKeyPrefix, a config that can't be
+// parsed is passed on as is.
+func removeConfigKey(configFile io.Reader, key string) (io.Reader, error) {
+	raw, err := io.ReadAll(configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var config map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return bytes.NewReader(raw), nil
+	}
+	if _, ok := config[key]; !ok {
+		return bytes.NewReader(raw), nil
+	}
+
+	delete(config, key)
+	backendConfig, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	return bytes.NewReader(backendConfig), nil
+}
+
+// prefixedStorager puts prefix in front of every object key it passes to str and strips it from
+// the keys str lists, so that the same commands work on environments that differ only by prefix.
+// Keys in other buckets, like the source of CopyFromBucket, are left alone.
+type prefixedStorager struct {
+	str    Storager
+	prefix string
+}
+
+func (p *prefixedStorager) key(name string) string {
+	return p.prefix + name
+}
+
+func (p *prefixedStorager) Put(ctx context.Context, sourceFilePath string, dest string, options common.PutOptions) error {
+	return p.str.Put(ctx, sourceFilePath, p.key(dest), options)
+}
+
+func (p *prefixedStorager) PutWithETag(ctx context.Context, sourceFilePath string, dest string, options common.PutOptions) (string, error) {
+	return p.str.PutWithETag(ctx, sourceFilePath, p.key(dest), options)
+}
+
+func (p *prefixedStorager) PutWithManifest(ctx context.Context, sourceFilePath string, dest string, manifest common.UploadManifest, options common.PutOptions) error {
+	return p.str.PutWithManifest(ctx, sourceFilePath, p.key(dest), manifest, options)
+}
+
+func (p *prefixedStorager) Get(ctx context.Context, source string, dest string, options common.GetOptions) error {
+	return p.str.Get(ctx, p.key(source), dest, options)
+}
+
+func (p *prefixedStorager) GetRange(ctx context.Context, source string, dest string, offset int64, options common.GetOptions) error {
+	return p.str.GetRange(ctx, p.key(source), dest, offset, options)
+}
+
+func (p *prefixedStorager) Delete(ctx context.Context, dest string) error {
+	return p.str.Delete(ctx, p.key(dest))
+}
+
+func (p *prefixedStorager) DeleteRecursive(ctx context.Context, prefix string, options common.DeleteRecursiveOptions) error {
+	return p.str.DeleteRecursive(ctx, p.key(prefix), options)
+}
+
+func (p *prefixedStorager) Exists(ctx context.Context, dest string) (bool, error) {
+	return p.str.Exists(ctx, p.key(dest))
+}
+
+func (p *prefixedStorager) Size(ctx context.Context, dest string) (int64, error) {
+	return p.str.Size(ctx, p.key(dest))
+}
+
+func (p *prefixedStorager) Sign(ctx context.Context, dest string, action string, expiration time.Duration) (string, error) {
+	return p.str.Sign(ctx, p.key(dest), action, expiration)
+}
+
+func (p *prefixedStorager) SignWithOptions(ctx context.Context, dest string, action string, expiration time.Duration, options common.SignOptions) (string, error) {
+	return p.str.SignWithOptions(ctx, p.key(dest), action, expiration, options)
+}
+
+func (p *prefixedStorager) ValidateSign(ctx context.Context, dest string, action string, expiration time.Duration, options common.SignOptions) error {
+	return p.str.ValidateSign(ctx, p.key(dest), action, expiration, options)
+}
+
+func (p *prefixedStorager) List(ctx context.Context, prefix string) ([]string, error) {
+	return p.stripPrefix(p.str.List(ctx, p.key(prefix)))
+}
+
+func (p *prefixedStorager) ListWithLimit(ctx context.Context, prefix string, limit int) ([]string, error) {
+	return p.stripPrefix(p.str.ListWithLimit(ctx, p.key(prefix), limit))
+}
+
+// stripPrefix removes the key prefix from listed objects
+func (p *prefixedStorager) stripPrefix(objects []string, err error) ([]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	for i, object := range objects {
+		objects[i] = strings.TrimPrefix(object, p.prefix)
+	}
+	return objects, nil
+}
+
+func (p *prefixedStorager) ListDetailed(ctx context.Context, prefix string) ([]common.ObjectInfo, error) {
+	return p.stripPrefixDetailed(p.str.ListDetailed(ctx, p.key(prefix)))
+}
+
+func (p *prefixedStorager) ListDetailedWithLimit(ctx context.Context, prefix string, limit int) ([]common.ObjectInfo, error) {
+	return p.stripPrefixDetailed(p.str.ListDetailedWithLimit(ctx, p.key(prefix), limit))
+}
+
+// stripPrefixDetailed removes the key prefix from objects listed with details
+func (p *prefixedStorager) stripPrefixDetailed(objects []common.ObjectInfo, err error) ([]common.ObjectInfo, error) {
+	if err != nil {
+		return nil, err
+	}
+	for i := range objects {
+		objects[i].Key = strings.TrimPrefix(objects[i].Key, p.prefix)
+	}
+	return objects, nil
+}
+
+func (p *prefixedStorager) Copy(ctx context.Context, srcBlob string, dstBlob string, options common.CopyOptions) error {
+	return p.str.Copy(ctx, p.key(srcBlob), p.key(dstBlob), options)
+}
+
+func (p *prefixedStorager) CopyFromBucket(ctx context.Context, srcBucket string, srcRegion string, srcBlob string, dstBlob string, options common.CopyOptions) error {
+	return p.str.CopyFromBucket(ctx, srcBucket, srcRegion, srcBlob, p.key(dstBlob), options)
+}
+
+func (p *prefixedStorager) CopyToBucket(ctx context.Context, srcBlob string, dstBucket string, dstBlob string, options common.CopyOptions) error {
+	return p.str.CopyToBucket(ctx, p.key(srcBlob), dstBucket, dstBlob, options)
+}
+
+func (p *prefixedStorager) Rename(ctx context.Context, srcBlob string, dstBlob string) error {
+	return p.str.Rename(ctx, p.key(srcBlob), p.key(dstBlob))
+}
+
+func (p *prefixedStorager) Properties(ctx context.Context, dest string) (common.ObjectProperties, error) {
+	return p.str.Properties(ctx, p.key(dest))
+}
+
+func (p *prefixedStorager) PropertiesWithOptions(ctx context.Context, dest string, options common.PropertiesOptions) (common.ObjectProperties, error) {
+	return p.str.PropertiesWithOptions(ctx, p.key(dest), options)
+}
+
+func (p *prefixedStorager) Head(ctx context.Context, dest string) (common.ObjectHead, error) {
+	return p.str.Head(ctx, p.key(dest))
+}
+
+func (p *prefixedStorager) EnsureStorageExists(ctx context.Context) error {
+	return p.str.EnsureStorageExists(ctx)
+}
+
+func (p *prefixedStorager) Identity(ctx context.Context) (common.Identity, error) {
+	return p.str.Identity(ctx)
+}
+
+func (p *prefixedStorager) Capabilities() []string {
+	return p.str.Capabilities()
+}
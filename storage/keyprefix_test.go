@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("key_prefix", func() {
+	Context("in the config", func() {
+		var backendConfig []byte
+
+		BeforeEach(func() {
+			original := newS3Client
+			DeferCleanup(func() {
+				newS3Client = original
+			})
+
+			backendConfig = nil
+			newS3Client = func(configFile io.Reader) (Storager, error) {
+				var err error
+				backendConfig, err = io.ReadAll(configFile)
+				Expect(err).ToNot(HaveOccurred())
+				return &FakeStorager{}, nil
+			}
+		})
+
+		It("wraps the client and is removed from the backend config", func() {
+			client, err := NewStorageClient("s3", strings.NewReader(`{"bucket_name": "some-bucket", "key_prefix": "staging/"}`))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(client).To(BeAssignableToTypeOf(&prefixedStorager{}))
+			Expect(client.(*prefixedStorager).prefix).To(Equal("staging/"))
+			Expect(backendConfig).To(MatchJSON(`{"bucket_name": "some-bucket"}`))
+		})
+
+		It("passes the config on untouched without a key_prefix", func() {
+			client, err := NewStorageClient("s3", strings.NewReader(`{"bucket_name": "some-bucket"}`))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(client).To(BeAssignableToTypeOf(&FakeStorager{}))
+			Expect(string(backendConfig)).To(Equal(`{"bucket_name": "some-bucket"}`))
+		})
+
+		It("leaves a config that is not valid JSON to the backend", func() {
+			_, err := NewStorageClient("s3", strings.NewReader(`~`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(backendConfig)).To(Equal(`~`))
+		})
+
+		It("must be a string", func() {
+			_, err := NewStorageClient("s3", strings.NewReader(`{"key_prefix": 1}`))
+			Expect(err).To(MatchError("key_prefix must be a string"))
+		})
+
+		It("is part of every config schema", func() {
+			fields, err := ConfigSchema("gcs")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fields).To(ContainElement(ConfigField{Name: "key_prefix", Type: "string", Required: false}))
+		})
+	})
+
+	Context("on commands", func() {
+		var (
+			fakeStorager    *FakeStorager
+			commandExecuter *CommandExecuter
+		)
+
+		BeforeEach(func() {
+			fakeStorager = &FakeStorager{}
+			commandExecuter = NewCommandExecuter(&prefixedStorager{str: fakeStorager, prefix: "staging/"})
+		})
+
+		It("puts the prefix in front of the key on put", func() {
+			source := filepath.Join(GinkgoT().TempDir(), "source")
+			Expect(os.WriteFile(source, []byte("0123456789"), 0644)).To(Succeed())
+
+			Expect(commandExecuter.Execute(context.Background(), "put", []string{source, "some-object"})).To(Succeed())
+			_, _, dest, _ := fakeStorager.PutArgsForCall(0)
+			Expect(dest).To(Equal("staging/some-object"))
+		})
+
+		It("puts the prefix in front of the key on get", func() {
+			dst := filepath.Join(GinkgoT().TempDir(), "object")
+
+			Expect(commandExecuter.Execute(context.Background(), "get", []string{"--no-space-check", "some-object", dst})).To(Succeed())
+			_, src, _, _ := fakeStorager.GetArgsForCall(0)
+			Expect(src).To(Equal("staging/some-object"))
+		})
+
+		It("lists under the prefix and strips it from the listed keys", func() {
+			fakeStorager.ListReturns([]string{"staging/releases/a", "staging/releases/b"}, nil)
+
+			output := captureStdout(func() {
+				Expect(commandExecuter.Execute(context.Background(), "list", []string{"releases/"})).To(Succeed())
+			})
+			_, prefix := fakeStorager.ListArgsForCall(0)
+
+			Expect(prefix).To(Equal("staging/releases/"))
+			Expect(output).To(Equal("releases/a\nreleases/b\n"))
+		})
+
+		It("strips the prefix from detailed listings", func() {
+			fakeStorager.ListDetailedReturns([]common.ObjectInfo{{Key: "staging/cache/old", LastModified: time.Now().Add(-48 * time.Hour)}}, nil)
+
+			output := captureStdout(func() {
+				Expect(commandExecuter.Execute(context.Background(), "sweep", []string{"--older-than", "24h", "--dry-run", "cache/"})).To(Succeed())
+			})
+			_, prefix := fakeStorager.ListDetailedArgsForCall(0)
+
+			Expect(prefix).To(Equal("staging/cache/"))
+			Expect(output).To(ContainSubstring("cache/old"))
+			Expect(output).ToNot(ContainSubstring("staging/"))
+		})
+
+		It("prefixes only the key in the configured bucket on copies between buckets", func() {
+			Expect(commandExecuter.Execute(context.Background(), "copy", []string{"--source-bucket", "other-bucket", "their-object", "our-object"})).To(Succeed())
+			_, srcBucket, _, srcBlob, dstBlob, _ := fakeStorager.CopyFromBucketArgsForCall(0)
+			Expect(srcBucket).To(Equal("other-bucket"))
+			Expect(srcBlob).To(Equal("their-object"))
+			Expect(dstBlob).To(Equal("staging/our-object"))
+		})
+	})
+})
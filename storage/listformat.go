@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+const (
+	defaultListFormat     = "default"
+	s3cliCompatListFormat = "s3cli-compat"
+	jsonListFormat        = "json"
+)
+
+func validateListFormat(format string) error {
+	switch format {
+	case defaultListFormat, s3cliCompatListFormat:
+		return nil
+	default:
+		return fmt.Errorf("unknown list format: '%s'. Available formats are '%s' and '%s'", format, defaultListFormat, s3cliCompatListFormat)
+	}
+}
+
+// printList prints one object per line. The legacy CLIs always listed in lexicographic
+// key order, which not every backend guarantees, so the compat format sorts the keys.
+func printList(objects []string, format string) {
+	if format == s3cliCompatListFormat {
+		objects = append([]string(nil), objects...)
+		sort.Strings(objects)
+	}
+
+	for _, object := range objects {
+		fmt.Println(object)
+	}
+}
+
+// listedObject is one entry of the json list format. The backends report size and
+// modification time along with the key, so they are included at no extra cost.
+type listedObject struct {
+	Name         string    `json:"name"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified,omitzero"`
+}
+
+// printJSONList prints the objects as a single JSON array, which stays parseable
+// whatever characters the keys contain.
+func printJSONList(objects []common.ObjectInfo) error {
+	listed := make([]listedObject, 0, len(objects))
+	for _, object := range objects {
+		listed = append(listed, listedObject{
+			Name:         object.Key,
+			Size:         object.Size,
+			LastModified: object.LastModified,
+		})
+	}
+
+	output, err := json.Marshal(listed)
+	if err != nil {
+		return fmt.Errorf("failed to marshal object list: %w", err)
+	}
+
+	fmt.Println(string(output))
+	return nil
+}
+
+// s3cliCompatProperties is the properties document of the legacy CLIs: a single line and
+// second precision UTC timestamps
+type s3cliCompatProperties struct {
+	ETag          string `json:"etag"`
+	LastModified  string `json:"last_modified"`
+	ContentLength int64  `json:"content_length"`
+}
+
+// fetchProperties returns the properties of dest. For objects that don't exist found is false.
+func (sty *CommandExecuter) fetchProperties(ctx context.Context, dest string, options common.PropertiesOptions) (properties common.ObjectProperties, found bool, err error) {
+	if options.RawETag {
+		properties, err = sty.str.PropertiesWithOptions(ctx, dest, options)
+	} else {
+		properties, err = sty.str.Properties(ctx, dest)
+	}
+	if errors.Is(err, common.ErrObjectNotFound) {
+		return common.ObjectProperties{}, false, nil
+	}
+	if err != nil {
+		return common.ObjectProperties{}, false, err
+	}
+	return properties, true, nil
+}
+
+// printProperties prints the properties of dest in the given format. Objects that don't exist are
+// reported as an empty document in both formats.
+func (sty *CommandExecuter) printProperties(ctx context.Context, dest string, format string, options common.PropertiesOptions) error {
+	properties, found, err := sty.fetchProperties(ctx, dest, options)
+	if err != nil {
+		return err
+	}
+	if !found {
+		fmt.Println(`{}`)
+		return nil
+	}
+
+	var output []byte
+	if format == s3cliCompatListFormat {
+		output, err = json.Marshal(s3cliCompatProperties{
+			ETag:          properties.ETag,
+			LastModified:  properties.LastModified.UTC().Format(time.RFC3339),
+			ContentLength: properties.ContentLength,
+		})
+	} else {
+		output, err = common.MarshalObjectProperties(properties)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal blob properties: %w", err)
+	}
+
+	fmt.Println(string(output))
+	return nil
+}
+
+// printHead prints the head of dest, or an empty document if it doesn't exist
+func (sty *CommandExecuter) printHead(ctx context.Context, dest string) error {
+	head, found, err := sty.fetchHead(ctx, dest)
+	if err != nil {
+		return err
+	}
+	if !found {
+		fmt.Println(`{}`)
+		return nil
+	}
+
+	output, err := json.MarshalIndent(head, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal blob head: %w", err)
+	}
+
+	fmt.Println(string(output))
+	return nil
+}
@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func readFixture(name string) string {
+	content, err := os.ReadFile(filepath.Join("testdata", "s3cli-compat", name))
+	Expect(err).ToNot(HaveOccurred())
+	return string(content)
+}
+
+var _ = Describe("s3cli-compat list format", func() {
+	var (
+		commandExecuter *CommandExecuter
+		fakeStorager    *FakeStorager
+	)
+
+	BeforeEach(func() {
+		fakeStorager = &FakeStorager{}
+		commandExecuter = NewCommandExecuter(fakeStorager)
+	})
+
+	Context("list", func() {
+		BeforeEach(func() {
+			fakeStorager.ListReturns([]string{"blobs/c/d", "blobs/a", "blobs/b"}, nil)
+		})
+
+		It("prints the keys in legacy order", func() {
+			var err error
+			out := captureStdout(func() {
+				err = commandExecuter.Execute(context.Background(), "list", []string{"--list-format", "s3cli-compat", "blobs/"})
+			})
+			Expect(err).ToNot(HaveOccurred())
+			_, prefix := fakeStorager.ListArgsForCall(0)
+
+			Expect(prefix).To(Equal("blobs/"))
+			Expect(out).To(Equal(readFixture("list.txt")))
+		})
+
+		It("keeps the backend order by default", func() {
+			out := captureStdout(func() {
+				Expect(commandExecuter.Execute(context.Background(), "list", []string{})).To(Succeed())
+			})
+			Expect(out).To(Equal("blobs/c/d\nblobs/a\nblobs/b\n"))
+		})
+
+		It("rejects unknown formats", func() {
+			err := commandExecuter.Execute(context.Background(), "list", []string{"--list-format", "xml"})
+			Expect(err).To(MatchError(ContainSubstring("unknown list format: 'xml'")))
+			Expect(fakeStorager.ListCallCount()).To(BeEquivalentTo(0))
+		})
+	})
+
+	Context("properties", func() {
+		DescribeTable("matches the legacy format",
+			func(properties common.ObjectProperties, err error, fixture string) {
+				fakeStorager.PropertiesReturns(properties, err)
+
+				out := captureStdout(func() {
+					Expect(commandExecuter.Execute(context.Background(), "properties", []string{"--list-format", "s3cli-compat", "object"})).To(Succeed())
+				})
+				_, key := fakeStorager.PropertiesArgsForCall(0)
+
+				Expect(key).To(Equal("object"))
+				Expect(out).To(Equal(readFixture(fixture)))
+			},
+			Entry("for an object", common.ObjectProperties{
+				ETag:          "9a0364b9e99bb480dd25e1f0284c8555",
+				LastModified:  time.Date(2024, 3, 1, 13, 30, 45, 123000000, time.FixedZone("", 3600)),
+				ContentLength: 7,
+			}, nil, "properties.json"),
+			Entry("for an empty object", common.ObjectProperties{
+				ETag:         "d41d8cd98f00b204e9800998ecf8427e",
+				LastModified: time.Date(2024, 3, 1, 12, 30, 45, 0, time.UTC),
+			}, nil, "properties-empty-object.json"),
+			Entry("for a missing object", common.ObjectProperties{}, common.ErrObjectNotFound, "properties-missing.json"),
+		)
+
+		It("prints the properties document by default", func() {
+			fakeStorager.PropertiesReturns(common.ObjectProperties{ETag: "some-etag", ContentLength: 7}, nil)
+
+			out := captureStdout(func() {
+				Expect(commandExecuter.Execute(context.Background(), "properties", []string{"object"})).To(Succeed())
+			})
+			Expect(out).To(Equal("{\n  \"content_length\": 7,\n  \"etag\": \"some-etag\"\n}\n"))
+		})
+
+		It("prints an empty document for a missing object by default", func() {
+			fakeStorager.PropertiesReturns(common.ObjectProperties{}, common.ErrObjectNotFound)
+
+			out := captureStdout(func() {
+				Expect(commandExecuter.Execute(context.Background(), "properties", []string{"object"})).To(Succeed())
+			})
+			Expect(out).To(Equal("{}\n"))
+		})
+
+		It("keeps the quotes of the ETag with --raw-etag", func() {
+			fakeStorager.PropertiesWithOptionsReturns(common.ObjectProperties{
+				ETag:          `"9a0364b9e99bb480dd25e1f0284c8555-2"`,
+				LastModified:  time.Date(2024, 3, 1, 12, 30, 45, 0, time.UTC),
+				ContentLength: 7,
+			}, nil)
+
+			out := captureStdout(func() {
+				Expect(commandExecuter.Execute(context.Background(), "properties", []string{"--list-format", "s3cli-compat", "--raw-etag", "object"})).To(Succeed())
+			})
+			Expect(fakeStorager.PropertiesCallCount()).To(Equal(0))
+			Expect(out).To(Equal(`{"etag":"\"9a0364b9e99bb480dd25e1f0284c8555-2\"","last_modified":"2024-03-01T12:30:45Z","content_length":7}` + "\n"))
+		})
+
+		It("returns backend errors without printing anything", func() {
+			fakeStorager.PropertiesReturns(common.ObjectProperties{}, fmt.Errorf("boom"))
+
+			var err error
+			out := captureStdout(func() {
+				err = commandExecuter.Execute(context.Background(), "properties", []string{"--list-format", "s3cli-compat", "object"})
+			})
+			Expect(err).To(MatchError("boom"))
+			Expect(out).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// metadataFlag collects the key=value pairs of the repeatable put --meta flag
+type metadataFlag map[string]string
+
+func (m metadataFlag) String() string {
+	pairs := make([]string, 0, len(m))
+	for key, value := range m {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// Set adds one key=value pair. Keys are restricted to what every backend accepts as a header
+// name, values must fit in a header. Keys only differing in case are rejected, S3 lowercases them.
+func (m metadataFlag) Set(pair string) error {
+	key, value, found := strings.Cut(pair, "=")
+	if !found {
+		return fmt.Errorf("metadata '%s' is not of the form key=value", pair)
+	}
+	if key == "" || strings.ContainsFunc(key, func(r rune) bool {
+		return r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_')
+	}) {
+		return fmt.Errorf("invalid metadata key '%s': keys may only contain letters, digits, '-' and '_'", key)
+	}
+	if strings.ContainsFunc(value, unicode.IsControl) {
+		return fmt.Errorf("invalid metadata value for key '%s': values must not contain control characters", key)
+	}
+	if m.has(key) {
+		return fmt.Errorf("metadata key '%s' is given more than once", key)
+	}
+
+	m[key] = value
+	return nil
+}
+
+// has reports whether key is set, ignoring case
+func (m metadataFlag) has(key string) bool {
+	for existing := range m {
+		if strings.EqualFold(existing, key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// endpointHealthTimeout bounds the preflight dial done while creating a client, 0 disables the preflight
+var endpointHealthTimeout time.Duration
+
+// SetEndpointHealthTimeout enables a quick reachability check of the configured endpoint when the
+// client is created, so an unreachable endpoint fails within timeout instead of the full dial timeout
+func SetEndpointHealthTimeout(timeout time.Duration) {
+	endpointHealthTimeout = timeout
+}
+
+// checkEndpoint dials the endpoint and, for https, completes a TLS handshake within endpointHealthTimeout.
+// Certificates are deliberately not verified here, the client itself does that with its own TLS settings.
+func checkEndpoint(endpoint string) error {
+	if endpointHealthTimeout <= 0 || endpoint == "" {
+		return nil
+	}
+
+	u, err := parseEndpoint(endpoint)
+	if err != nil {
+		return fmt.Errorf("endpoint unreachable: %w", err)
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+	}
+	address := net.JoinHostPort(u.Hostname(), port)
+
+	slog.Debug("Checking endpoint", "address", address, "timeout", endpointHealthTimeout)
+	dialer := &net.Dialer{Timeout: endpointHealthTimeout}
+	var conn net.Conn
+	if u.Scheme == "https" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, &tls.Config{ServerName: u.Hostname(), InsecureSkipVerify: true}) //nolint:gosec
+	} else {
+		conn, err = dialer.Dial("tcp", address)
+	}
+	if err != nil {
+		return fmt.Errorf("endpoint unreachable: %s: %w", address, err)
+	}
+	return conn.Close()
+}
+
+// parseEndpoint parses an endpoint that is either a URL or a bare host[:port], which is assumed to be served over https
+func parseEndpoint(endpoint string) (*url.URL, error) {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+	return u, nil
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"fmt"
+	"net"
+	"net/http/httptest"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Endpoint preflight", func() {
+	var closedPortURL string
+
+	BeforeEach(func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+		closedPortURL = "http://" + listener.Addr().String()
+		Expect(listener.Close()).To(Succeed())
+
+		DeferCleanup(SetEndpointHealthTimeout, time.Duration(0))
+		SetEndpointHealthTimeout(500 * time.Millisecond)
+	})
+
+	It("fails quickly when the endpoint is unreachable", func() {
+		start := time.Now()
+		err := checkEndpoint(closedPortURL)
+		Expect(err).To(MatchError(ContainSubstring("endpoint unreachable")))
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+	})
+
+	It("succeeds when the endpoint accepts connections", func() {
+		server := httptest.NewServer(nil)
+		DeferCleanup(server.Close)
+
+		Expect(checkEndpoint(server.URL)).To(Succeed())
+	})
+
+	It("completes a TLS handshake for https endpoints", func() {
+		server := httptest.NewTLSServer(nil)
+		DeferCleanup(server.Close)
+
+		Expect(checkEndpoint(server.URL)).To(Succeed())
+	})
+
+	It("is skipped when no timeout is set", func() {
+		SetEndpointHealthTimeout(0)
+		Expect(checkEndpoint(closedPortURL)).To(Succeed())
+	})
+
+	It("runs when the client is created", func() {
+		configFile, err := os.CreateTemp("", "dav-config")
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(os.Remove, configFile.Name())
+		DeferCleanup(configFile.Close)
+		_, err = fmt.Fprintf(configFile, `{"Endpoint": %q}`, closedPortURL)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = configFile.Seek(0, 0)
+		Expect(err).ToNot(HaveOccurred())
+
+		client, err := NewStorageClient("dav", configFile)
+		Expect(err).To(MatchError(ContainSubstring("endpoint unreachable")))
+		Expect(client).To(BeNil())
+	})
+})
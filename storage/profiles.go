@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// profileProviderKey names the backend inside a profile. It is removed before the
+// profile is handed to the backend, so the backend configs don't have to know about it.
+const profileProviderKey = "provider"
+
+// NewStorageClientFromProfile creates the client for one named profile of a config file that holds
+// several, e.g. {"prod": {"provider": "s3", "bucket_name": "..."}, "backup": {"provider": "gcs", ...}}.
+// The backend is the profile's provider, storageType is used for profiles that don't name one.
+func NewStorageClientFromProfile(storageType string, profile string, configFile io.Reader, options ClientOptions) (Storager, error) {
+	storageType, profileConfig, err := selectProfile(storageType, profile, configFile)
+	if err != nil {
+		return nil, err
+	}
+	return NewStorageClientWithOptions(storageType, bytes.NewReader(profileConfig), options)
+}
+
+// selectProfile returns the storage type and backend config of the named profile
+func selectProfile(storageType string, profile string, configFile io.Reader) (string, []byte, error) {
+	var profiles map[string]map[string]json.RawMessage
+	if err := json.NewDecoder(configFile).Decode(&profiles); err != nil {
+		return "", nil, fmt.Errorf("failed to parse profiles: %w", err)
+	}
+
+	profileConfig, ok := profiles[profile]
+	if !ok {
+		names := make([]string, 0, len(profiles))
+		for name := range profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", nil, fmt.Errorf("unknown profile %q, available profiles are: %s", profile, strings.Join(names, ", "))
+	}
+
+	if rawProvider, ok := profileConfig[profileProviderKey]; ok {
+		var provider string
+		if err := json.Unmarshal(rawProvider, &provider); err != nil {
+			return "", nil, fmt.Errorf("profile %q: %s must be a string", profile, profileProviderKey)
+		}
+		if storageType != "" && storageType != provider {
+			return "", nil, fmt.Errorf("profile %q is for %s but -s is %s", profile, provider, storageType)
+		}
+		storageType = provider
+		delete(profileConfig, profileProviderKey)
+	}
+	if storageType == "" {
+		return "", nil, fmt.Errorf("profile %q has no %s, set it or pass -s", profile, profileProviderKey)
+	}
+
+	backendConfig, err := json.Marshal(profileConfig)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal profile %q: %w", profile, err)
+	}
+	return storageType, backendConfig, nil
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Profiles", func() {
+	var (
+		configFile   *os.File
+		mockClient   *FakeStorager
+		backendType  string
+		parsedConfig string
+	)
+
+	fakeBackend := func(storageType string) func(io.Reader) (Storager, error) {
+		return func(configFile io.Reader) (Storager, error) {
+			content, err := io.ReadAll(configFile)
+			Expect(err).ToNot(HaveOccurred())
+			backendType, parsedConfig = storageType, string(content)
+			return mockClient, nil
+		}
+	}
+
+	BeforeEach(func() {
+		var err error
+		configFile, err = os.Open("testdata/profiles.json")
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(configFile.Close)
+
+		originalS3, originalGcs := newS3Client, newGcsClient
+		DeferCleanup(func() {
+			newS3Client, newGcsClient = originalS3, originalGcs
+		})
+		newS3Client, newGcsClient = fakeBackend("s3"), fakeBackend("gcs")
+
+		mockClient = &FakeStorager{}
+		backendType, parsedConfig = "", ""
+	})
+
+	It("creates the client of the selected profile's provider", func() {
+		client, err := NewStorageClientFromProfile("", "backup", configFile, ClientOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client).To(Equal(mockClient))
+		Expect(backendType).To(Equal("gcs"))
+		Expect(parsedConfig).To(MatchJSON(`{"bucket_name": "backup-bucket"}`))
+	})
+
+	It("passes only the selected profile without its provider to the backend", func() {
+		_, err := NewStorageClientFromProfile("s3", "prod", configFile, ClientOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backendType).To(Equal("s3"))
+		Expect(parsedConfig).To(MatchJSON(`{"bucket_name": "prod-bucket", "region": "eu-central-1"}`))
+	})
+
+	It("uses the storage type for profiles without a provider", func() {
+		_, err := NewStorageClientFromProfile("s3", "legacy", configFile, ClientOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backendType).To(Equal("s3"))
+		Expect(parsedConfig).To(MatchJSON(`{"bucket_name": "legacy-bucket"}`))
+	})
+
+	It("removes the folder_name of an s3 profile with NoFolderPrefix", func() {
+		profiles := `{"prod": {"provider": "s3", "bucket_name": "prod-bucket", "folder_name": "some-folder"}}`
+		_, err := NewStorageClientFromProfile("", "prod", strings.NewReader(profiles), ClientOptions{NoFolderPrefix: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsedConfig).To(MatchJSON(`{"bucket_name": "prod-bucket"}`))
+	})
+
+	It("fails on a profile without a provider and no storage type", func() {
+		_, err := NewStorageClientFromProfile("", "legacy", configFile, ClientOptions{})
+		Expect(err).To(MatchError(`profile "legacy" has no provider, set it or pass -s`))
+	})
+
+	It("fails when the storage type contradicts the profile's provider", func() {
+		_, err := NewStorageClientFromProfile("gcs", "prod", configFile, ClientOptions{})
+		Expect(err).To(MatchError(`profile "prod" is for s3 but -s is gcs`))
+		Expect(backendType).To(BeEmpty())
+	})
+
+	It("fails on an unknown profile and lists the available ones", func() {
+		client, err := NewStorageClientFromProfile("", "staging", configFile, ClientOptions{})
+		Expect(err).To(MatchError(`unknown profile "staging", available profiles are: backup, legacy, prod`))
+		Expect(client).To(BeNil())
+	})
+
+	It("fails on a file that is not a map of profiles", func() {
+		_, err := NewStorageClientFromProfile("s3", "prod", strings.NewReader(`{"bucket_name": "prod-bucket"}`), ClientOptions{})
+		Expect(err).To(MatchError(ContainSubstring("failed to parse profiles")))
+	})
+})
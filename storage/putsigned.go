@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// putSigned uploads a local file to a signed PUT URL as generated by the sign command
+func putSigned(ctx context.Context, signedURL string, sourceFilePath string) error {
+	parsedURL, err := url.Parse(signedURL)
+	if err != nil {
+		return fmt.Errorf("invalid signed url: %w", err)
+	}
+
+	file, err := os.Open(sourceFilePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close() //nolint:errcheck
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	contentType, err := detectContentType(file)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, signedURL, file)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = info.Size()
+	req.Header.Set("Content-Type", contentType)
+	// Azure refuses blob uploads through a SAS URL that don't state the blob type
+	if query := parsedURL.Query(); query.Has("sv") && query.Has("sig") {
+		req.Header.Set("x-ms-blob-type", "BlockBlob")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload to signed url: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024)) //nolint:errcheck
+		return fmt.Errorf("upload to signed url failed with status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
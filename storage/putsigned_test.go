@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("put-signed", func() {
+	var (
+		received   *http.Request
+		body       []byte
+		statusCode int
+		server     *httptest.Server
+		dir        string
+	)
+
+	BeforeEach(func() {
+		received = nil
+		statusCode = http.StatusOK
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+			body, _ = io.ReadAll(r.Body) //nolint:errcheck
+			w.WriteHeader(statusCode)
+		}))
+		DeferCleanup(server.Close)
+		dir = GinkgoT().TempDir()
+	})
+
+	It("uploads the file with a content type derived from its extension", func() {
+		source := filepath.Join(dir, "data.json")
+		err := os.WriteFile(source, []byte(`{"some":"content"}`), 0644)
+		Expect(err).ToNot(HaveOccurred())
+
+		err = NewCommandExecuter(nil).Execute(context.Background(), "put-signed", []string{server.URL + "/some-bucket/some-object?X-Amz-Signature=abc", source})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(received.Method).To(Equal(http.MethodPut))
+		Expect(received.URL.Path).To(Equal("/some-bucket/some-object"))
+		Expect(received.URL.Query().Get("X-Amz-Signature")).To(Equal("abc"))
+		Expect(received.Header.Get("Content-Type")).To(Equal("application/json"))
+		Expect(received.Header.Get("x-ms-blob-type")).To(BeEmpty())
+		Expect(received.ContentLength).To(BeEquivalentTo(18))
+		Expect(string(body)).To(Equal(`{"some":"content"}`))
+	})
+
+	It("sniffs the content type of files without a known extension", func() {
+		source := filepath.Join(dir, "data")
+		err := os.WriteFile(source, []byte("just some text"), 0644)
+		Expect(err).ToNot(HaveOccurred())
+
+		err = NewCommandExecuter(nil).Execute(context.Background(), "put-signed", []string{server.URL + "/some-object", source})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(received.Header.Get("Content-Type")).To(Equal("text/plain; charset=utf-8"))
+		Expect(string(body)).To(Equal("just some text"))
+	})
+
+	It("sets the blob type for Azure SAS urls", func() {
+		source := filepath.Join(dir, "data")
+		err := os.WriteFile(source, []byte("just some text"), 0644)
+		Expect(err).ToNot(HaveOccurred())
+
+		err = NewCommandExecuter(nil).Execute(context.Background(), "put-signed", []string{server.URL + "/container/blob?sv=2021-08-06&sig=abc", source})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(received.Header.Get("x-ms-blob-type")).To(Equal("BlockBlob"))
+	})
+
+	It("fails when the upload is rejected", func() {
+		statusCode = http.StatusForbidden
+		source := filepath.Join(dir, "data")
+		err := os.WriteFile(source, []byte("just some text"), 0644)
+		Expect(err).ToNot(HaveOccurred())
+
+		err = NewCommandExecuter(nil).Execute(context.Background(), "put-signed", []string{server.URL + "/some-object", source})
+		Expect(err).To(MatchError(ContainSubstring("upload to signed url failed with status 403 Forbidden")))
+	})
+
+	It("fails with the wrong number of arguments", func() {
+		err := NewCommandExecuter(nil).Execute(context.Background(), "put-signed", []string{server.URL})
+		Expect(err).To(MatchError("put-signed method expected 2 arguments got 1"))
+	})
+})
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+)
+
+// stdinSource is the put source that reads the object body from stdin
+const stdinSource = "-"
+
+// spoolStdin copies the object body from stdin into a temporary file and returns its path. The
+// backends upload from a file, S3 and Alibaba Cloud need to seek in it to retry and upload in parts,
+// so the body can't be streamed as is. A maxSize above 0 aborts spooling once stdin exceeds it.
+// The caller has to remove the file.
+func spoolStdin(maxSize int64) (string, error) {
+	if configFromStdin {
+		return "", errors.New("can't read the object from stdin, it was already used for the config (-c -)")
+	}
+
+	file, err := os.CreateTemp("", "storage-cli-stdin-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create a temporary file for stdin: %w", err)
+	}
+	defer file.Close() //nolint:errcheck
+
+	source := stdin
+	if maxSize > 0 {
+		source = io.LimitReader(stdin, maxSize+1)
+	}
+	written, err := io.Copy(file, source)
+	if err == nil {
+		err = file.Close()
+	}
+	if err != nil {
+		os.Remove(file.Name()) //nolint:errcheck
+		return "", fmt.Errorf("failed to read the object from stdin: %w", err)
+	}
+	if maxSize > 0 && written > maxSize {
+		os.Remove(file.Name()) //nolint:errcheck
+		return "", fmt.Errorf("stdin exceeds the maximum upload size of %d bytes", maxSize)
+	}
+
+	slog.Debug("Spooled stdin to a temporary file", "path", file.Name(), "size", written)
+	return file.Name(), nil
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	aliossconfig "github.com/cloudfoundry/storage-cli/alioss/config"
+	azureconfigbs "github.com/cloudfoundry/storage-cli/azurebs/config"
+	davconfig "github.com/cloudfoundry/storage-cli/dav/config"
+	gcsconfig "github.com/cloudfoundry/storage-cli/gcs/config"
+	s3config "github.com/cloudfoundry/storage-cli/s3/config"
+)
+
+var configTypes = map[string]reflect.Type{
+	"azurebs": reflect.TypeOf(azureconfigbs.AZStorageConfig{}),
+	"alioss":  reflect.TypeOf(aliossconfig.AliStorageConfig{}),
+	"s3":      reflect.TypeOf(s3config.S3Cli{}),
+	"gcs":     reflect.TypeOf(gcsconfig.GCSCli{}),
+	"dav":     reflect.TypeOf(davconfig.Config{}),
+}
+
+// ConfigField describes a single field of a storage configuration file
+type ConfigField struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+}
+
+// ConfigSchema returns the fields accepted in the configuration file of the given storage type.
+// Nested objects are flattened into dot separated names.
+func ConfigSchema(storageType string) ([]ConfigField, error) {
+	configType, ok := configTypes[storageType]
+	if !ok {
+		return nil, fmt.Errorf("storage %s not implemented", storageType)
+	}
+
+	// key_prefix is handled for all backends before their config is parsed
+	return append(schemaFields(configType, ""), ConfigField{Name: keyPrefixConfigKey, Type: "string"}), nil
+}
+
+// PrintConfigSchema writes the configuration schema of the given storage type to stdout as JSON
+func PrintConfigSchema(storageType string) error {
+	fields, err := ConfigSchema(storageType)
+	if err != nil {
+		return err
+	}
+
+	output, err := json.MarshalIndent(fields, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config schema: %w", err)
+	}
+
+	fmt.Println(string(output))
+	return nil
+}
+
+func schemaFields(t reflect.Type, prefix string) []ConfigField {
+	var fields []ConfigField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			tagName, _, _ := strings.Cut(tag, ",")
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+		}
+		name = prefix + name
+
+		if field.Type.Kind() == reflect.Struct {
+			fields = append(fields, schemaFields(field.Type, name+".")...)
+			continue
+		}
+
+		fields = append(fields, ConfigField{
+			Name:     name,
+			Type:     schemaType(field.Type),
+			Required: field.Tag.Get("required") == "true",
+		})
+	}
+	return fields
+}
+
+func schemaType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice:
+		// encoding/json represents byte slices as base64 strings
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string"
+		}
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	case reflect.Pointer:
+		return schemaType(t.Elem())
+	default:
+		return t.Kind().String()
+	}
+}
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ConfigSchema", func() {
+	It("describes the s3 configuration", func() {
+		fields, err := ConfigSchema("s3")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(fields).To(ContainElement(ConfigField{Name: "bucket_name", Type: "string", Required: true}))
+		Expect(fields).To(ContainElement(ConfigField{Name: "access_key_id", Type: "string", Required: false}))
+		Expect(fields).To(ContainElement(ConfigField{Name: "port", Type: "integer", Required: false}))
+		Expect(fields).To(ContainElement(ConfigField{Name: "use_ssl", Type: "boolean", Required: false}))
+	})
+
+	It("describes the alioss configuration", func() {
+		fields, err := ConfigSchema("alioss")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(fields).To(ContainElement(ConfigField{Name: "access_key_id", Type: "string", Required: true}))
+		Expect(fields).To(ContainElement(ConfigField{Name: "bucket_name", Type: "string", Required: true}))
+	})
+
+	It("skips fields that are not read from the configuration file", func() {
+		fields, err := ConfigSchema("gcs")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(fields).To(ContainElement(ConfigField{Name: "encryption_key", Type: "string", Required: false}))
+		Expect(fields).ToNot(ContainElement(HaveField("Name", "EncryptionKeyEncoded")))
+	})
+
+	It("flattens nested objects of the dav configuration", func() {
+		fields, err := ConfigSchema("dav")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(fields).To(ContainElement(ConfigField{Name: "Endpoint", Type: "string", Required: true}))
+		Expect(fields).To(ContainElement(ConfigField{Name: "TLS.Cert.CA", Type: "string", Required: false}))
+	})
+
+	It("fails for an unknown storage type", func() {
+		_, err := ConfigSchema("unknown")
+		Expect(err).To(MatchError("storage unknown not implemented"))
+	})
+})
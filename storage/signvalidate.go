@@ -0,0 +1,23 @@
+package storage
+
+import "time"
+
+// signValidation is what sign --validate prints instead of the signed URL: the URL could be
+// signed, and when it would have been valid
+type signValidation struct {
+	Object    string    `json:"object"`
+	Action    string    `json:"action"`
+	ValidFrom time.Time `json:"valid_from"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// newSignValidation reports a URL for action on object that was signed to be valid for expiration
+// from startAt on, or from now if startAt is zero
+func newSignValidation(object string, action string, expiration time.Duration, startAt time.Time) signValidation {
+	validFrom := startAt
+	if validFrom.IsZero() {
+		validFrom = time.Now()
+	}
+	validFrom = validFrom.UTC().Truncate(time.Second)
+	return signValidation{Object: object, Action: action, ValidFrom: validFrom, ExpiresAt: validFrom.Add(expiration)}
+}
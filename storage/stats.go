@@ -0,0 +1,21 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+// PrintStats writes the counters collected during the run as JSON. It goes to a separate
+// writer so it doesn't get mixed up with command output such as list or sign results.
+func PrintStats(w io.Writer) error {
+	output, err := json.Marshal(common.GetStats())
+	if err != nil {
+		return fmt.Errorf("failed to marshal stats: %w", err)
+	}
+
+	_, err = fmt.Fprintln(w, string(output))
+	return err
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PrintStats", func() {
+	BeforeEach(func() {
+		common.ResetStats()
+	})
+
+	It("prints the collected counters as JSON", func() {
+		common.IncRequests()
+		common.IncRequests()
+		common.IncRetries()
+		common.AddBytesTransferred(1024)
+
+		var out bytes.Buffer
+		Expect(PrintStats(&out)).To(Succeed())
+
+		var stats common.Stats
+		Expect(json.Unmarshal(out.Bytes(), &stats)).To(Succeed())
+		Expect(stats.Requests).To(BeEquivalentTo(2))
+		Expect(stats.Retries).To(BeEquivalentTo(1))
+		Expect(stats.BytesTransferred).To(BeEquivalentTo(1024))
+		Expect(out.String()).To(ContainSubstring(`"elapsed_ms":`))
+	})
+
+	It("counts eventual consistency retries", func() {
+		DeferCleanup(func(backoff time.Duration) {
+			eventualConsistencyBackoff = backoff
+		}, eventualConsistencyBackoff)
+		eventualConsistencyBackoff = time.Millisecond
+
+		fakeStorager := &FakeStorager{}
+		fakeStorager.ExistsReturnsOnCall(0, false, nil)
+		fakeStorager.ExistsReturnsOnCall(1, true, nil)
+
+		err := NewCommandExecuter(fakeStorager).Execute(context.Background(), "exists", []string{"--eventual-consistency-retries", "2", "object"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(common.GetStats().Retries).To(BeEquivalentTo(1))
+	})
+})
@@ -1,18 +1,37 @@
 package storage
 
 import (
+	"context"
 	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
 )
 
 type Storager interface {
-	Put(sourceFilePath string, dest string) error
-	Get(source string, dest string) error
-	Delete(dest string) error
-	DeleteRecursive(prefix string) error
-	Exists(dest string) (bool, error)
-	Sign(dest string, action string, expiration time.Duration) (string, error)
-	List(prefix string) ([]string, error)
-	Copy(srcBlob string, dstBlob string) error
-	Properties(dest string) error
-	EnsureStorageExists() error
+	Put(ctx context.Context, sourceFilePath string, dest string, options common.PutOptions) error
+	PutWithETag(ctx context.Context, sourceFilePath string, dest string, options common.PutOptions) (string, error)
+	PutWithManifest(ctx context.Context, sourceFilePath string, dest string, manifest common.UploadManifest, options common.PutOptions) error
+	Get(ctx context.Context, source string, dest string, options common.GetOptions) error
+	GetRange(ctx context.Context, source string, dest string, offset int64, options common.GetOptions) error
+	Delete(ctx context.Context, dest string) error
+	DeleteRecursive(ctx context.Context, prefix string, options common.DeleteRecursiveOptions) error
+	Exists(ctx context.Context, dest string) (bool, error)
+	Size(ctx context.Context, dest string) (int64, error)
+	Sign(ctx context.Context, dest string, action string, expiration time.Duration) (string, error)
+	SignWithOptions(ctx context.Context, dest string, action string, expiration time.Duration, options common.SignOptions) (string, error)
+	ValidateSign(ctx context.Context, dest string, action string, expiration time.Duration, options common.SignOptions) error
+	List(ctx context.Context, prefix string) ([]string, error)
+	ListWithLimit(ctx context.Context, prefix string, limit int) ([]string, error)
+	ListDetailed(ctx context.Context, prefix string) ([]common.ObjectInfo, error)
+	ListDetailedWithLimit(ctx context.Context, prefix string, limit int) ([]common.ObjectInfo, error)
+	Copy(ctx context.Context, srcBlob string, dstBlob string, options common.CopyOptions) error
+	CopyFromBucket(ctx context.Context, srcBucket string, srcRegion string, srcBlob string, dstBlob string, options common.CopyOptions) error
+	CopyToBucket(ctx context.Context, srcBlob string, dstBucket string, dstBlob string, options common.CopyOptions) error
+	Rename(ctx context.Context, srcBlob string, dstBlob string) error
+	Properties(ctx context.Context, dest string) (common.ObjectProperties, error)
+	PropertiesWithOptions(ctx context.Context, dest string, options common.PropertiesOptions) (common.ObjectProperties, error)
+	Head(ctx context.Context, dest string) (common.ObjectHead, error)
+	EnsureStorageExists(ctx context.Context) error
+	Identity(ctx context.Context) (common.Identity, error)
+	Capabilities() []string
 }
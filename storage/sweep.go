@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+// sweepConcurrency is the number of stale objects sweep deletes at the same time
+const sweepConcurrency = 10
+
+// sweepReport is printed once sweep is done
+type sweepReport struct {
+	Scanned int `json:"scanned"`
+	Stale   int `json:"stale"`
+	Deleted int `json:"deleted"`
+	Failed  int `json:"failed"`
+	// Skipped are the stale objects not deleted because an earlier one failed
+	Skipped int `json:"skipped"`
+}
+
+// olderThan matches the objects last modified before cutoff
+func olderThan(cutoff time.Time) func(common.ObjectInfo) bool {
+	return func(object common.ObjectInfo) bool {
+		return object.LastModified.Before(cutoff)
+	}
+}
+
+// sweep deletes the objects under prefix that were last modified more than maxAge ago.
+// With dryRun the stale objects are printed in planFormat the same way delete-recursive --dry-run prints its plan.
+// The deletes share limiter. Without continueOnError the sweep stops at the first object that fails to delete.
+func (sty *CommandExecuter) sweep(ctx context.Context, prefix string, maxAge time.Duration, dryRun bool, planFormat string, continueOnError bool, limiter *common.ConcurrencyLimiter) error {
+	objects, err := sty.str.ListDetailed(ctx, prefix)
+	if err != nil {
+		return fmt.Errorf("failed to list objects: %w", err)
+	}
+
+	isStale := olderThan(time.Now().Add(-maxAge))
+	var staleObjects []common.ObjectInfo
+	for _, object := range objects {
+		if isStale(object) {
+			staleObjects = append(staleObjects, object)
+		}
+	}
+
+	plan := newDeleteRecursivePlan(staleObjects)
+	if dryRun {
+		return plan.print(planFormat)
+	}
+	stale := plan.Keys
+
+	deleted, errs := sty.deleteConcurrently(ctx, stale, continueOnError, limiter)
+	err = printJSON(sweepReport{
+		Scanned: len(objects),
+		Stale:   len(stale),
+		Deleted: deleted,
+		Failed:  len(errs),
+		Skipped: len(stale) - deleted - len(errs),
+	})
+	return errors.Join(append(errs, err)...)
+}
+
+// deleteConcurrently deletes all the given objects, sweepConcurrency at a time and each delete in a
+// slot of limiter, and returns how many were deleted and the failures. Without continueOnError no
+// further deletes are started after the first failure and those still running are cancelled.
+func (sty *CommandExecuter) deleteConcurrently(ctx context.Context, keys []string, continueOnError bool, limiter *common.ConcurrencyLimiter) (int, []error) {
+	deleteCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu      sync.Mutex
+		errs    []error
+		deleted int
+		wg      sync.WaitGroup
+	)
+
+	semaphore := make(chan struct{}, sweepConcurrency)
+	for _, key := range keys {
+		// Acquiring before starting a delete keeps a failure from starting any more of them
+		semaphore <- struct{}{}
+		if deleteCtx.Err() != nil {
+			<-semaphore
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			err := limiter.Do(func() error { return sty.str.Delete(deleteCtx, key) })
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				deleted++
+			case deleteCtx.Err() != nil && ctx.Err() == nil && errors.Is(err, context.Canceled):
+				// Deletes cut short by an earlier failure have nothing to add to it
+			default:
+				errs = append(errs, fmt.Errorf("failed to delete %s: %w", key, err))
+				if !continueOnError {
+					cancel()
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	if err := ctx.Err(); err != nil && len(errs) == 0 {
+		errs = append(errs, err)
+	}
+	return deleted, errs
+}
+
+func printJSON(v any) error {
+	output, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	fmt.Println(string(output))
+	return nil
+}
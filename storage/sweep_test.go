@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("sweep", func() {
+	var (
+		commandExecuter *CommandExecuter
+		fakeStorager    *FakeStorager
+		deleted         []string
+	)
+
+	BeforeEach(func() {
+		fakeStorager = &FakeStorager{}
+		commandExecuter = NewCommandExecuter(fakeStorager)
+
+		now := time.Now()
+		fakeStorager.ListDetailedReturns([]common.ObjectInfo{
+			{Key: "cache/fresh", LastModified: now.Add(-time.Hour)},
+			{Key: "cache/stale", Size: 10, LastModified: now.Add(-48 * time.Hour)},
+			{Key: "cache/older", Size: 32, LastModified: now.Add(-30 * 24 * time.Hour)},
+			{Key: "cache/recent", LastModified: now.Add(-23 * time.Hour)},
+		}, nil)
+
+		deleted = nil
+		var mu sync.Mutex
+		fakeStorager.DeleteStub = func(_ context.Context, key string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			deleted = append(deleted, key)
+			return nil
+		}
+	})
+
+	It("deletes only the objects older than the given duration and reports the counts", func() {
+		var err error
+		output := captureStdout(func() {
+			err = commandExecuter.Execute(context.Background(), "sweep", []string{"--older-than", "24h", "cache/"})
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, prefix := fakeStorager.ListDetailedArgsForCall(0)
+
+		Expect(prefix).To(Equal("cache/"))
+		Expect(deleted).To(ConsistOf("cache/stale", "cache/older"))
+		Expect(output).To(MatchJSON(`{"scanned": 4, "stale": 2, "deleted": 2, "failed": 0, "skipped": 0}`))
+	})
+
+	It("accepts the flags after the prefix", func() {
+		captureStdout(func() {
+			Expect(commandExecuter.Execute(context.Background(), "sweep", []string{"cache/", "--older-than", "24h"})).To(Succeed())
+		})
+		Expect(deleted).To(ConsistOf("cache/stale", "cache/older"))
+	})
+
+	It("deletes no more objects at the same time than --total-concurrency allows", func() {
+		var inFlight, maxInFlight atomic.Int32
+		fakeStorager.DeleteStub = func(context.Context, string) error {
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				highest := maxInFlight.Load()
+				if current <= highest || maxInFlight.CompareAndSwap(highest, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		}
+
+		captureStdout(func() {
+			Expect(commandExecuter.Execute(context.Background(), "sweep", []string{"--older-than", "1m", "--total-concurrency", "1", "cache/"})).To(Succeed())
+		})
+		Expect(fakeStorager.DeleteCallCount()).To(Equal(4))
+		Expect(maxInFlight.Load()).To(Equal(int32(1)))
+	})
+
+	It("prints the stale objects without deleting them on a dry run", func() {
+		var err error
+		output := captureStdout(func() {
+			err = commandExecuter.Execute(context.Background(), "sweep", []string{"--older-than", "24h", "--dry-run", "cache/"})
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(fakeStorager.DeleteCallCount()).To(Equal(0))
+		Expect(output).To(MatchJSON(`{"keys": ["cache/stale", "cache/older"], "count": 2, "total_size": 42}`))
+	})
+
+	It("prints the stale objects one per line on a dry run with --format lines", func() {
+		var err error
+		output := captureStdout(func() {
+			err = commandExecuter.Execute(context.Background(), "sweep", []string{"--older-than", "24h", "--dry-run", "--format", "lines", "cache/"})
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(fakeStorager.DeleteCallCount()).To(Equal(0))
+		Expect(output).To(Equal("cache/stale\ncache/older\ntotal: 2 objects, 42 bytes\n"))
+	})
+
+	It("stops at the first object that fails to delete by default", func() {
+		fakeStorager.DeleteStub = func(ctx context.Context, key string) error {
+			if key == "cache/stale" {
+				return errors.New("boom")
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		var err error
+		output := captureStdout(func() {
+			err = commandExecuter.Execute(context.Background(), "sweep", []string{"--older-than", "24h", "cache/"})
+		})
+		Expect(err).To(MatchError("failed to delete cache/stale: boom"))
+		Expect(output).To(MatchJSON(`{"scanned": 4, "stale": 2, "deleted": 0, "failed": 1, "skipped": 1}`))
+	})
+
+	It("deletes the remaining objects when one fails with --continue-on-error and reports the failure", func() {
+		fakeStorager.DeleteStub = func(_ context.Context, key string) error {
+			if key == "cache/stale" {
+				return errors.New("boom")
+			}
+			return nil
+		}
+
+		var err error
+		output := captureStdout(func() {
+			err = commandExecuter.Execute(context.Background(), "sweep", []string{"--older-than", "24h", "--continue-on-error", "cache/"})
+		})
+		Expect(err).To(MatchError(ContainSubstring("failed to delete cache/stale: boom")))
+		Expect(fakeStorager.DeleteCallCount()).To(Equal(2))
+		Expect(output).To(MatchJSON(`{"scanned": 4, "stale": 2, "deleted": 1, "failed": 1, "skipped": 0}`))
+	})
+
+	It("returns the listing error", func() {
+		fakeStorager.ListDetailedReturns(nil, errors.New("boom"))
+
+		err := commandExecuter.Execute(context.Background(), "sweep", []string{"--older-than", "24h", "cache/"})
+		Expect(err).To(MatchError("failed to list objects: boom"))
+		Expect(fakeStorager.DeleteCallCount()).To(Equal(0))
+	})
+
+	It("requires a positive --older-than", func() {
+		err := commandExecuter.Execute(context.Background(), "sweep", []string{"cache/"})
+		Expect(err).To(MatchError("--older-than must be a positive duration"))
+		Expect(fakeStorager.ListDetailedCallCount()).To(Equal(0))
+	})
+
+	It("requires exactly one prefix", func() {
+		err := commandExecuter.Execute(context.Background(), "sweep", []string{"--older-than", "24h", "a", "b"})
+		Expect(err).To(MatchError("sweep method expected 1 argument (prefix) got 2"))
+	})
+})
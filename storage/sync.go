@@ -0,0 +1,262 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+// defaultSyncConcurrency is the number of files sync uploads at the same time unless told otherwise
+const defaultSyncConcurrency = 4
+
+// syncFile is a file of the local directory and the key it is uploaded to
+type syncFile struct {
+	path    string
+	key     string
+	size    int64
+	modTime time.Time
+}
+
+// syncPlan lists the keys of the local files by how they compare to the remote objects
+type syncPlan struct {
+	New       []string `json:"new"`
+	Changed   []string `json:"changed"`
+	Unchanged []string `json:"unchanged"`
+}
+
+// syncReport is printed once sync is done
+type syncReport struct {
+	New       int `json:"new"`
+	Changed   int `json:"changed"`
+	Unchanged int `json:"unchanged"`
+	Uploaded  int `json:"uploaded"`
+	Failed    int `json:"failed"`
+	// Skipped are the files not uploaded because an earlier one failed
+	Skipped int `json:"skipped"`
+}
+
+// syncDir uploads the files under localDir that are missing below prefix or differ from the object
+// there. With dryRun the files are only classified and the plan is printed. With checkCase keys of
+// the files and objects that differ only by case are warned about. With guessContentType each file
+// is uploaded with the content type detected from it, like put does. The requests of all uploads, each part on its own, share limiter.
+// Without continueOnError the sync stops at the first file that fails to upload.
+func (sty *CommandExecuter) syncDir(ctx context.Context, localDir string, prefix string, concurrency int, dryRun bool, checkCase bool, guessContentType bool, continueOnError bool, limiter *common.ConcurrencyLimiter) error {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	files, err := walkSyncDir(localDir, prefix)
+	if err != nil {
+		return err
+	}
+
+	objects, err := sty.str.ListDetailed(ctx, prefix)
+	if err != nil {
+		return fmt.Errorf("failed to list objects: %w", err)
+	}
+	remote := make(map[string]common.ObjectInfo, len(objects))
+	for _, object := range objects {
+		remote[object.Key] = object
+	}
+
+	if checkCase {
+		keys := make([]string, 0, len(files)+len(objects))
+		for _, file := range files {
+			keys = append(keys, file.key)
+		}
+		for _, object := range objects {
+			keys = append(keys, object.Key)
+		}
+		warnCaseCollisions(keys)
+	}
+
+	plan := syncPlan{New: []string{}, Changed: []string{}, Unchanged: []string{}}
+	var uploads []syncFile
+	canHead := slices.Contains(sty.str.Capabilities(), common.CapabilityHead)
+	for _, file := range files {
+		object, exists := remote[file.key]
+		if !exists {
+			plan.New = append(plan.New, file.key)
+			uploads = append(uploads, file)
+			continue
+		}
+
+		changed, err := sty.isChanged(ctx, file, object, canHead)
+		if err != nil {
+			return err
+		}
+		if changed {
+			plan.Changed = append(plan.Changed, file.key)
+			uploads = append(uploads, file)
+		} else {
+			plan.Unchanged = append(plan.Unchanged, file.key)
+		}
+	}
+
+	if dryRun {
+		return printJSON(plan)
+	}
+
+	uploaded, errs := sty.uploadConcurrently(ctx, uploads, concurrency, continueOnError, guessContentType, limiter)
+	err = printJSON(syncReport{
+		New:       len(plan.New),
+		Changed:   len(plan.Changed),
+		Unchanged: len(plan.Unchanged),
+		Uploaded:  uploaded,
+		Failed:    len(errs),
+		Skipped:   len(uploads) - uploaded - len(errs),
+	})
+	return errors.Join(append(errs, err)...)
+}
+
+// walkSyncDir returns the regular files below localDir, symlinks to files included, with their
+// path relative to localDir appended to prefix as key
+func walkSyncDir(localDir string, prefix string) ([]syncFile, error) {
+	var files []syncFile
+	err := filepath.WalkDir(localDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			slog.Warn("Skipping, not a regular file", "path", path)
+			return nil
+		}
+
+		relative, err := filepath.Rel(localDir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, syncFile{
+			path:    path,
+			key:     prefix + filepath.ToSlash(relative),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", localDir, err)
+	}
+	return files, nil
+}
+
+// isChanged reports whether file differs from object. Files of the same size are compared with the
+// checksum the object's head reports, like get --verify does, or else by their modification time.
+func (sty *CommandExecuter) isChanged(ctx context.Context, file syncFile, object common.ObjectInfo, canHead bool) (bool, error) {
+	if file.size != object.Size {
+		return true, nil
+	}
+
+	if canHead {
+		head, found, err := sty.fetchHead(ctx, file.key)
+		if err != nil {
+			return false, fmt.Errorf("failed to get checksum of %s: %w", file.key, err)
+		}
+		if !found {
+			return true, nil
+		}
+
+		algorithm, newHash, expected, err := expectedChecksum(head)
+		if err != nil {
+			return false, err
+		}
+		if algorithm != "" {
+			actual, err := fileChecksum(file.path, newHash())
+			if err != nil {
+				return false, fmt.Errorf("failed to compute checksum of %s: %w", file.path, err)
+			}
+			return !slices.Equal(actual, expected), nil
+		}
+	}
+
+	return file.modTime.After(object.LastModified), nil
+}
+
+// uploadConcurrently puts all the given files, concurrency at a time, and returns how many were
+// uploaded and the failures. Without continueOnError no further uploads are started after the first
+// failure and those still running are cancelled. The backends send every request of an upload in a
+// slot of limiter.
+func (sty *CommandExecuter) uploadConcurrently(ctx context.Context, files []syncFile, concurrency int, continueOnError bool, guessContentType bool, limiter *common.ConcurrencyLimiter) (int, []error) {
+	uploadCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		errs     []error
+		uploaded int
+		wg       sync.WaitGroup
+	)
+
+	semaphore := make(chan struct{}, concurrency)
+	for _, file := range files {
+		// Acquiring before starting an upload keeps a failure from starting any more of them
+		semaphore <- struct{}{}
+		if uploadCtx.Err() != nil {
+			<-semaphore
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			err := sty.uploadFile(uploadCtx, file, guessContentType, limiter)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				uploaded++
+			case uploadCtx.Err() != nil && ctx.Err() == nil && errors.Is(err, context.Canceled):
+				// Uploads cut short by an earlier failure have nothing to add to it
+			default:
+				errs = append(errs, err)
+				if !continueOnError {
+					cancel()
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	if err := ctx.Err(); err != nil && len(errs) == 0 {
+		errs = append(errs, err)
+	}
+	return uploaded, errs
+}
+
+// uploadFile puts file under its key, with the content type detected from it if guessContentType is set
+func (sty *CommandExecuter) uploadFile(ctx context.Context, file syncFile, guessContentType bool, limiter *common.ConcurrencyLimiter) error {
+	options := common.PutOptions{Concurrency: limiter}
+	if guessContentType {
+		contentType, err := fileContentType(file.path)
+		if err != nil {
+			return fmt.Errorf("failed to detect content type of %s: %w", file.path, err)
+		}
+		options.ContentType = contentType
+	}
+
+	slog.Info("Uploading", "file", file.path, "object", file.key, "content_type", options.ContentType)
+	if err := sty.str.Put(ctx, file.path, file.key, options); err != nil {
+		return fmt.Errorf("failed to upload %s: %w", file.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cloudfoundry/storage-cli/common"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("sync", func() {
+	var (
+		commandExecuter *CommandExecuter
+		fakeStorager    *FakeStorager
+		localDir        string
+		heads           map[string]common.ObjectHead
+		uploaded        map[string]string
+	)
+
+	writeFile := func(name string, content string, modTime time.Time) {
+		path := filepath.Join(localDir, name)
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+		Expect(os.Chtimes(path, modTime, modTime)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		fakeStorager = &FakeStorager{}
+		commandExecuter = NewCommandExecuter(fakeStorager)
+		localDir = GinkgoT().TempDir()
+
+		now := time.Now()
+		writeFile("new.txt", "new", now)
+		writeFile("nested/dir/new.txt", "nested", now)
+		writeFile("resized.txt", "longer than before", now)
+		writeFile("same-md5.txt", checkContent, now)
+		writeFile("other-md5.txt", checkContent, now)
+		writeFile("older.txt", checkContent, now.Add(-time.Hour))
+		writeFile("newer.txt", checkContent, now)
+
+		fakeStorager.CapabilitiesReturns([]string{common.CapabilityPut, common.CapabilityList, common.CapabilityHead})
+		fakeStorager.ListDetailedReturns([]common.ObjectInfo{
+			{Key: "release/resized.txt", Size: 5, LastModified: now},
+			{Key: "release/same-md5.txt", Size: 9, LastModified: now.Add(-time.Hour)},
+			{Key: "release/other-md5.txt", Size: 9, LastModified: now.Add(time.Hour)},
+			{Key: "release/older.txt", Size: 9, LastModified: now.Add(-time.Minute)},
+			{Key: "release/newer.txt", Size: 9, LastModified: now.Add(-time.Minute)},
+			{Key: "release/remote-only.txt", Size: 1, LastModified: now},
+		}, nil)
+
+		heads = map[string]common.ObjectHead{
+			"release/same-md5.txt":  {ContentMD5: base64Hex("25f9e794323b453885f5181f1b624d0b")},
+			"release/other-md5.txt": {ContentMD5: base64Hex("00000000000000000000000000000000")},
+			"release/older.txt":     {ETag: "d41d8cd98f00b204e9800998ecf8427e-2"},
+			"release/newer.txt":     {ETag: "d41d8cd98f00b204e9800998ecf8427e-2"},
+		}
+		fakeStorager.HeadStub = func(_ context.Context, key string) (common.ObjectHead, error) {
+			return heads[key], nil
+		}
+
+		uploaded = map[string]string{}
+		var mu sync.Mutex
+		fakeStorager.PutStub = func(_ context.Context, path string, key string, _ common.PutOptions) error {
+			mu.Lock()
+			defer mu.Unlock()
+			uploaded[key] = path
+			return nil
+		}
+	})
+
+	It("classifies the files as new, changed or unchanged on a dry run", func() {
+		var err error
+		output := captureStdout(func() {
+			err = commandExecuter.Execute(context.Background(), "sync", []string{"--dry-run", localDir, "release"})
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, prefix := fakeStorager.ListDetailedArgsForCall(0)
+
+		Expect(prefix).To(Equal("release/"))
+		Expect(fakeStorager.PutCallCount()).To(Equal(0))
+		Expect(output).To(MatchJSON(`{
+			"new": ["release/nested/dir/new.txt", "release/new.txt"],
+			"changed": ["release/newer.txt", "release/other-md5.txt", "release/resized.txt"],
+			"unchanged": ["release/older.txt", "release/same-md5.txt"]
+		}`))
+	})
+
+	It("uploads the new and changed files and reports the counts", func() {
+		var err error
+		output := captureStdout(func() {
+			err = commandExecuter.Execute(context.Background(), "sync", []string{localDir, "release/"})
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(uploaded).To(Equal(map[string]string{
+			"release/nested/dir/new.txt": filepath.Join(localDir, "nested", "dir", "new.txt"),
+			"release/new.txt":            filepath.Join(localDir, "new.txt"),
+			"release/newer.txt":          filepath.Join(localDir, "newer.txt"),
+			"release/other-md5.txt":      filepath.Join(localDir, "other-md5.txt"),
+			"release/resized.txt":        filepath.Join(localDir, "resized.txt"),
+		}))
+		Expect(output).To(MatchJSON(`{"new": 2, "changed": 3, "unchanged": 2, "uploaded": 5, "failed": 0, "skipped": 0}`))
+	})
+
+	It("compares by modification time when the backend can't report checksums", func() {
+		fakeStorager.CapabilitiesReturns([]string{common.CapabilityPut, common.CapabilityList})
+
+		var err error
+		output := captureStdout(func() {
+			err = commandExecuter.Execute(context.Background(), "sync", []string{"--dry-run", localDir, "release"})
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(fakeStorager.HeadCallCount()).To(Equal(0))
+		Expect(output).To(MatchJSON(`{
+			"new": ["release/nested/dir/new.txt", "release/new.txt"],
+			"changed": ["release/newer.txt", "release/resized.txt", "release/same-md5.txt"],
+			"unchanged": ["release/older.txt", "release/other-md5.txt"]
+		}`))
+	})
+
+	It("uploads each file with the content type guessed from it", func() {
+		writeFile("page.html", "<html></html>", time.Now())
+		writeFile("style.css", "body {}", time.Now())
+		writeFile("app.js", "let a = 1", time.Now())
+		writeFile("notes", "plain text", time.Now())
+
+		captureStdout(func() {
+			Expect(commandExecuter.Execute(context.Background(), "sync", []string{localDir, "release"})).To(Succeed())
+		})
+
+		contentTypes := map[string]string{}
+		for i := range fakeStorager.PutCallCount() {
+			_, _, key, options := fakeStorager.PutArgsForCall(i)
+			contentTypes[key] = options.ContentType
+		}
+		Expect(contentTypes).To(HaveKeyWithValue("release/page.html", "text/html; charset=utf-8"))
+		Expect(contentTypes).To(HaveKeyWithValue("release/style.css", "text/css; charset=utf-8"))
+		Expect(contentTypes).To(HaveKeyWithValue("release/app.js", "text/javascript; charset=utf-8"))
+		Expect(contentTypes).To(HaveKeyWithValue("release/notes", "text/plain; charset=utf-8"))
+		Expect(contentTypes).To(HaveKeyWithValue("release/new.txt", "text/plain; charset=utf-8"))
+	})
+
+	It("leaves the content type to the provider with --no-guess-content-type", func() {
+		captureStdout(func() {
+			Expect(commandExecuter.Execute(context.Background(), "sync", []string{"--no-guess-content-type", localDir, "release"})).To(Succeed())
+		})
+
+		Expect(fakeStorager.PutCallCount()).To(Equal(5))
+		for i := range fakeStorager.PutCallCount() {
+			_, _, _, options := fakeStorager.PutArgsForCall(i)
+			Expect(options.ContentType).To(BeEmpty())
+		}
+	})
+
+	It("uploads no more files at the same time than the concurrency allows", func() {
+		var inFlight, maxInFlight atomic.Int32
+		fakeStorager.PutStub = func(context.Context, string, string, common.PutOptions) error {
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				highest := maxInFlight.Load()
+				if current <= highest || maxInFlight.CompareAndSwap(highest, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		}
+
+		captureStdout(func() {
+			Expect(commandExecuter.Execute(context.Background(), "sync", []string{"--concurrency", "2", localDir, "release"})).To(Succeed())
+		})
+		Expect(fakeStorager.PutCallCount()).To(Equal(5))
+		Expect(maxInFlight.Load()).To(BeNumerically("<=", 2))
+	})
+
+	It("uploads no more parts of all files at the same time than --total-concurrency allows", func() {
+		// Like the backends, every file is uploaded in parallel parts that each take a slot
+		var inFlight, maxInFlight, parts atomic.Int32
+		fakeStorager.PutStub = func(_ context.Context, _ string, _ string, options common.PutOptions) error {
+			var wg sync.WaitGroup
+			for range 4 {
+				wg.Go(func() {
+					options.Concurrency.Do(func() error { //nolint:errcheck
+						current := inFlight.Add(1)
+						defer inFlight.Add(-1)
+						for {
+							highest := maxInFlight.Load()
+							if current <= highest || maxInFlight.CompareAndSwap(highest, current) {
+								break
+							}
+						}
+						parts.Add(1)
+						time.Sleep(10 * time.Millisecond)
+						return nil
+					})
+				})
+			}
+			wg.Wait()
+			return nil
+		}
+
+		captureStdout(func() {
+			Expect(commandExecuter.Execute(context.Background(), "sync", []string{"--concurrency", "5", "--total-concurrency", "3", localDir, "release"})).To(Succeed())
+		})
+		Expect(parts.Load()).To(Equal(int32(20)))
+		Expect(maxInFlight.Load()).To(BeNumerically("<=", 3))
+	})
+
+	It("stops at the first file that fails to upload by default", func() {
+		fakeStorager.PutStub = func(_ context.Context, path string, key string, _ common.PutOptions) error {
+			if key == "release/nested/dir/new.txt" {
+				return errors.New("boom")
+			}
+			return nil
+		}
+
+		var err error
+		output := captureStdout(func() {
+			err = commandExecuter.Execute(context.Background(), "sync", []string{"--concurrency", "1", localDir, "release"})
+		})
+		Expect(err).To(MatchError("failed to upload " + filepath.Join(localDir, "nested", "dir", "new.txt") + ": boom"))
+		Expect(fakeStorager.PutCallCount()).To(Equal(1))
+		Expect(output).To(MatchJSON(`{"new": 2, "changed": 3, "unchanged": 2, "uploaded": 0, "failed": 1, "skipped": 4}`))
+	})
+
+	It("cancels the uploads still running when one fails", func() {
+		fakeStorager.PutStub = func(ctx context.Context, path string, key string, _ common.PutOptions) error {
+			if key == "release/new.txt" {
+				return errors.New("boom")
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		var err error
+		output := captureStdout(func() {
+			err = commandExecuter.Execute(context.Background(), "sync", []string{"--fail-fast", "--concurrency", "5", localDir, "release"})
+		})
+		Expect(err).To(MatchError("failed to upload " + filepath.Join(localDir, "new.txt") + ": boom"))
+		Expect(output).To(MatchJSON(`{"new": 2, "changed": 3, "unchanged": 2, "uploaded": 0, "failed": 1, "skipped": 4}`))
+	})
+
+	It("uploads the remaining files when one fails with --continue-on-error and reports the failure", func() {
+		fakeStorager.PutStub = func(_ context.Context, path string, key string, _ common.PutOptions) error {
+			if key == "release/new.txt" {
+				return errors.New("boom")
+			}
+			return nil
+		}
+
+		var err error
+		output := captureStdout(func() {
+			err = commandExecuter.Execute(context.Background(), "sync", []string{"--continue-on-error", "--concurrency", "1", localDir, "release"})
+		})
+		Expect(err).To(MatchError(ContainSubstring("failed to upload " + filepath.Join(localDir, "new.txt") + ": boom")))
+		Expect(fakeStorager.PutCallCount()).To(Equal(5))
+		Expect(output).To(MatchJSON(`{"new": 2, "changed": 3, "unchanged": 2, "uploaded": 4, "failed": 1, "skipped": 0}`))
+	})
+
+	It("uses the paths relative to the directory as keys for an empty prefix", func() {
+		fakeStorager.ListDetailedReturns(nil, nil)
+
+		captureStdout(func() {
+			Expect(commandExecuter.Execute(context.Background(), "sync", []string{localDir, ""})).To(Succeed())
+		})
+		_, prefix := fakeStorager.ListDetailedArgsForCall(0)
+
+		Expect(prefix).To(Equal(""))
+		Expect(uploaded).To(HaveKey("nested/dir/new.txt"))
+		Expect(uploaded).To(HaveLen(7))
+	})
+
+	It("fails without listing if the source is not a directory", func() {
+		err := commandExecuter.Execute(context.Background(), "sync", []string{filepath.Join(localDir, "new.txt"), "release"})
+		Expect(err).To(MatchError(filepath.Join(localDir, "new.txt") + " is not a directory"))
+		Expect(fakeStorager.ListDetailedCallCount()).To(Equal(0))
+	})
+
+	It("fails if the listing fails", func() {
+		fakeStorager.ListDetailedReturns(nil, errors.New("not implemented"))
+
+		err := commandExecuter.Execute(context.Background(), "sync", []string{localDir, "release"})
+		Expect(err).To(MatchError("failed to list objects: not implemented"))
+		Expect(fakeStorager.PutCallCount()).To(Equal(0))
+	})
+
+	It("refuses a concurrency below 1", func() {
+		err := commandExecuter.Execute(context.Background(), "sync", []string{"--concurrency", "0", localDir, "release"})
+		Expect(err).To(MatchError("--concurrency must be at least 1, got 0"))
+	})
+
+	It("refuses a negative total concurrency", func() {
+		err := commandExecuter.Execute(context.Background(), "sync", []string{"--total-concurrency", "-1", localDir, "release"})
+		Expect(err).To(MatchError("--total-concurrency must not be negative, got -1"))
+	})
+})
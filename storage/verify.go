@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"hash/crc64"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/cloudfoundry/storage-cli/common"
+)
+
+// crc64NVME is the reflected polynomial of CRC-64/NVME, which S3 uses for its default checksums
+const crc64NVME = 0x9a6c9329ac4bc9b5
+
+// verifyAlgorithms are the checksum algorithms get --verify checks downloads with, in order of preference
+var verifyAlgorithms = []struct {
+	name    string
+	newHash func() hash.Hash
+}{
+	{common.ChecksumMD5, md5.New},
+	{common.ChecksumCRC64NVME, func() hash.Hash { return crc64.New(crc64.MakeTable(crc64NVME)) }},
+	{common.ChecksumCRC32C, func() hash.Hash { return crc32.New(crc32.MakeTable(crc32.Castagnoli)) }},
+	{common.ChecksumCRC32, func() hash.Hash { return crc32.NewIEEE() }},
+	{common.ChecksumCRC64ECMA, func() hash.Hash { return crc64.New(crc64.MakeTable(crc64.ECMA)) }},
+	{common.ChecksumSHA256, sha256.New},
+	{common.ChecksumSHA1, sha1.New},
+}
+
+// downloadVerification is the checksum of an object its download is verified against
+type downloadVerification struct {
+	algorithm string
+	newHash   func() hash.Hash
+	expected  []byte
+	inline    *common.DownloadChecksum
+}
+
+// prepareVerification fetches the checksum of src before it is downloaded, the backend hashes the
+// download while writing it into the checksum of the returned verification. Objects without a checksum to compare with are not verified,
+// for them and for missing objects nil is returned.
+func (sty *CommandExecuter) prepareVerification(ctx context.Context, src string) (*downloadVerification, error) {
+	head, found, err := sty.fetchHead(ctx, src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checksum: %w", err)
+	}
+	// Leave reporting a missing object to the backend's Get
+	if !found {
+		return nil, nil
+	}
+	return newVerification(src, head)
+}
+
+// newVerification returns the verification against the checksum of head, or nil if it has none
+func newVerification(src string, head common.ObjectHead) (*downloadVerification, error) {
+	algorithm, newHash, expected, err := expectedChecksum(head)
+	if err != nil {
+		return nil, err
+	}
+	if algorithm == "" {
+		slog.Warn("Not verifying the download, the object has no checksum of its whole content", "object", src)
+		return nil, nil
+	}
+
+	return &downloadVerification{algorithm: algorithm, newHash: newHash, expected: expected, inline: common.NewDownloadChecksum(newHash())}, nil
+}
+
+// verify compares the checksum of the downloaded file dst with the expected one and removes dst if
+// they differ. Downloads the backend didn't hash while writing them, e.g. resumed or cached ones,
+// are read once more to compute it.
+func (v *downloadVerification) verify(src string, dst string) error {
+	info, err := os.Stat(dst)
+	if err != nil {
+		return fmt.Errorf("failed to compute checksum: %w", err)
+	}
+	actual, err := v.inline.Sum(info.Size())
+	if err != nil {
+		slog.Debug("Download was not hashed while writing it, reading it again", "object", src, "reason", err)
+		if actual, err = fileChecksum(dst, v.newHash()); err != nil {
+			return fmt.Errorf("failed to compute checksum: %w", err)
+		}
+	}
+
+	if !bytes.Equal(actual, v.expected) {
+		if err := os.Remove(dst); err != nil {
+			slog.Warn("Failed to remove the corrupt download", "file", dst, "error", err)
+		}
+		return fmt.Errorf("%s checksum mismatch for %s: expected %s, downloaded %s",
+			v.algorithm, src, base64.StdEncoding.EncodeToString(v.expected), base64.StdEncoding.EncodeToString(actual))
+	}
+
+	slog.Debug("Download verified", "object", src, "algorithm", v.algorithm)
+	return nil
+}
+
+// expectedChecksum picks the checksum of head to verify against along with the hash computing it.
+// The algorithm is empty if there is none, the MD5 stored by put --store-md5 is used as a last resort.
+func expectedChecksum(head common.ObjectHead) (algorithm string, newHash func() hash.Hash, checksum []byte, err error) {
+	checksums := map[string]string{}
+	for algorithm, value := range head.Checksums {
+		checksums[algorithm] = value
+	}
+	if head.ContentMD5 != "" {
+		checksums[common.ChecksumMD5] = head.ContentMD5
+	}
+
+	for _, candidate := range verifyAlgorithms {
+		if value, ok := checksums[candidate.name]; ok {
+			checksum, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return "", nil, nil, fmt.Errorf("invalid %s checksum '%s': %w", candidate.name, value, err)
+			}
+			return candidate.name, candidate.newHash, checksum, nil
+		}
+	}
+
+	if value, ok := head.Metadata[common.MD5MetadataKey]; ok {
+		checksum, err := hex.DecodeString(value)
+		if err != nil {
+			return "", nil, nil, fmt.Errorf("invalid %s metadata '%s': %w", common.MD5MetadataKey, value, err)
+		}
+		return common.ChecksumMD5, md5.New, checksum, nil
+	}
+	return "", nil, nil, nil
+}
+
+// fileChecksum hashes the content of the file at path with h
+func fileChecksum(path string, h hash.Hash) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() //nolint:errcheck
+
+	if _, err := io.Copy(h, file); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// fetchHead returns the head of dest. For objects that don't exist found is false.
+func (sty *CommandExecuter) fetchHead(ctx context.Context, dest string) (head common.ObjectHead, found bool, err error) {
+	head, err = sty.str.Head(ctx, dest)
+	if errors.Is(err, common.ErrObjectNotFound) {
+		return common.ObjectHead{}, false, nil
+	}
+	if err != nil {
+		return common.ObjectHead{}, false, err
+	}
+	return head, true, nil
+}
@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudfoundry/storage-cli/common"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// checkContent is the input the check values of checksum algorithms are given for
+const checkContent = "123456789"
+
+// base64Hex re-encodes a hex encoded checksum the way ObjectHead carries it
+func base64Hex(checksum string) string {
+	decoded, err := hex.DecodeString(checksum)
+	Expect(err).ToNot(HaveOccurred())
+	return base64.StdEncoding.EncodeToString(decoded)
+}
+
+var _ = Describe("get --verify", func() {
+	var (
+		commandExecuter *CommandExecuter
+		fakeStorager    *FakeStorager
+		head            common.ObjectHead
+		headErr         error
+		dst             string
+	)
+
+	BeforeEach(func() {
+		fakeStorager = &FakeStorager{}
+		commandExecuter = NewCommandExecuter(fakeStorager)
+
+		fakeStorager.CapabilitiesReturns([]string{common.CapabilityGet, common.CapabilityHead})
+		fakeStorager.GetStub = func(_ context.Context, _ string, dst string, _ common.GetOptions) error {
+			return os.WriteFile(dst, []byte(checkContent), 0644)
+		}
+		head, headErr = common.ObjectHead{}, nil
+		fakeStorager.HeadStub = func(context.Context, string) (common.ObjectHead, error) {
+			return head, headErr
+		}
+
+		dst = filepath.Join(GinkgoT().TempDir(), "object")
+	})
+
+	DescribeTable("keeps a download matching the checksum of the object",
+		func(algorithm string, checksum string) {
+			head = common.ObjectHead{ContentLength: 9, Checksums: map[string]string{algorithm: base64Hex(checksum)}}
+
+			Expect(commandExecuter.Execute(context.Background(), "get", []string{"--verify", "object", dst})).To(Succeed())
+			Expect(os.ReadFile(dst)).To(BeEquivalentTo(checkContent))
+			_, key := fakeStorager.HeadArgsForCall(0)
+
+			Expect(key).To(Equal("object"))
+		},
+		Entry("md5", common.ChecksumMD5, "25f9e794323b453885f5181f1b624d0b"),
+		Entry("crc32", common.ChecksumCRC32, "cbf43926"),
+		Entry("crc32c", common.ChecksumCRC32C, "e3069283"),
+		Entry("crc64nvme", common.ChecksumCRC64NVME, "ae8b14860a799888"),
+		Entry("crc64ecma", common.ChecksumCRC64ECMA, "995dc9bbdf1939fa"),
+		Entry("sha1", common.ChecksumSHA1, "f7c3bc1d808e04732adf679965ccc34ca7ae3441"),
+		Entry("sha256", common.ChecksumSHA256, "15e2b0d3c33891ebb0f1ef609ec419420c20e320ce94c65fbc8c3312448eb225"),
+	)
+
+	It("verifies against the Content-MD5 of the object", func() {
+		head = common.ObjectHead{ContentMD5: base64Hex("25f9e794323b453885f5181f1b624d0b")}
+
+		Expect(commandExecuter.Execute(context.Background(), "get", []string{"--verify", "object", dst})).To(Succeed())
+		Expect(dst).To(BeAnExistingFile())
+	})
+
+	It("removes a download not matching the checksum of the object", func() {
+		head = common.ObjectHead{Checksums: map[string]string{common.ChecksumCRC32C: base64Hex("00000000")}}
+
+		err := commandExecuter.Execute(context.Background(), "get", []string{"--verify", "object", dst})
+		Expect(err).To(MatchError(ContainSubstring("crc32c checksum mismatch for object: expected AAAAAA==, downloaded 4waSgw==")))
+		Expect(dst).ToNot(BeAnExistingFile())
+	})
+
+	It("prefers the MD5 over other checksums", func() {
+		head = common.ObjectHead{
+			ContentMD5: base64Hex("00000000000000000000000000000000"),
+			Checksums:  map[string]string{common.ChecksumCRC32C: base64Hex("e3069283")},
+		}
+
+		err := commandExecuter.Execute(context.Background(), "get", []string{"--verify", "object", dst})
+		Expect(err).To(MatchError(ContainSubstring("md5 checksum mismatch")))
+	})
+
+	It("falls back to the MD5 stored by put --store-md5", func() {
+		head = common.ObjectHead{ETag: "d41d8cd98f00b204e9800998ecf8427e-2", Metadata: map[string]string{"md5": "25f9e794323b453885f5181f1b624d0b"}}
+
+		Expect(commandExecuter.Execute(context.Background(), "get", []string{"--verify", "object", dst})).To(Succeed())
+		Expect(dst).To(BeAnExistingFile())
+	})
+
+	It("keeps the download when the object has no checksum to verify against", func() {
+		head = common.ObjectHead{ETag: "d41d8cd98f00b204e9800998ecf8427e-2", ContentLength: 9}
+
+		Expect(commandExecuter.Execute(context.Background(), "get", []string{"--verify", "object", dst})).To(Succeed())
+		Expect(dst).To(BeAnExistingFile())
+	})
+
+	It("verifies a download resumed with --continue", func() {
+		Expect(os.WriteFile(dst+".part", []byte("1234"), 0644)).To(Succeed())
+		fakeStorager.SizeReturns(9, nil)
+		fakeStorager.GetRangeStub = func(_ context.Context, _ string, dst string, offset int64, _ common.GetOptions) error {
+			return os.WriteFile(dst, []byte(checkContent), 0644)
+		}
+		head = common.ObjectHead{Checksums: map[string]string{common.ChecksumCRC32C: base64Hex("00000000")}}
+
+		err := commandExecuter.Execute(context.Background(), "get", []string{"--continue", "--verify", "object", dst})
+		Expect(err).To(MatchError(ContainSubstring("checksum mismatch")))
+		Expect(dst).ToNot(BeAnExistingFile())
+	})
+
+	It("uses the checksum computed while the backend wrote the download", func() {
+		head = common.ObjectHead{ContentMD5: base64Hex("25f9e794323b453885f5181f1b624d0b")}
+		fakeStorager.GetStub = func(_ context.Context, _ string, dst string, options common.GetOptions) error {
+			file, err := os.Create(dst)
+			Expect(err).ToNot(HaveOccurred())
+			defer file.Close() //nolint:errcheck
+
+			_, err = common.NewChecksummingWriter(options.Checksum, file).Write([]byte(checkContent))
+			Expect(err).ToNot(HaveOccurred())
+			// Reading the file back would see this, the hash computed inline doesn't
+			_, err = file.WriteAt([]byte("X"), 0)
+			return err
+		}
+
+		Expect(commandExecuter.Execute(context.Background(), "get", []string{"--verify", "object", dst})).To(Succeed())
+	})
+
+	It("fetches the checksum before downloading", func() {
+		fakeStorager.GetStub = func(context.Context, string, string, common.GetOptions) error {
+			Expect(fakeStorager.HeadCallCount()).To(Equal(1))
+			return os.WriteFile(dst, []byte(checkContent), 0644)
+		}
+		head = common.ObjectHead{ContentMD5: base64Hex("25f9e794323b453885f5181f1b624d0b")}
+
+		Expect(commandExecuter.Execute(context.Background(), "get", []string{"--verify", "object", dst})).To(Succeed())
+	})
+
+	It("reports a failed download as is", func() {
+		head = common.ObjectHead{ContentMD5: base64Hex("25f9e794323b453885f5181f1b624d0b")}
+		fakeStorager.GetReturns(errors.New("download failed"))
+
+		Expect(commandExecuter.Execute(context.Background(), "get", []string{"--verify", "object", dst})).To(MatchError("download failed"))
+	})
+
+	It("leaves reporting a missing object to the download", func() {
+		headErr = common.ErrObjectNotFound
+		fakeStorager.GetReturns(errors.New("object not found"))
+
+		Expect(commandExecuter.Execute(context.Background(), "get", []string{"--verify", "object", dst})).To(MatchError("object not found"))
+	})
+
+	It("fails before downloading when the backend can't report checksums", func() {
+		fakeStorager.CapabilitiesReturns([]string{common.CapabilityGet})
+
+		err := commandExecuter.Execute(context.Background(), "get", []string{"--verify", "object", dst})
+		Expect(err).To(MatchError("--verify is not supported by this storage type"))
+		Expect(fakeStorager.GetCallCount()).To(Equal(0))
+	})
+
+	It("doesn't fetch the head without --verify", func() {
+		Expect(commandExecuter.Execute(context.Background(), "get", []string{"object", dst})).To(Succeed())
+		Expect(fakeStorager.HeadCallCount()).To(Equal(0))
+	})
+})